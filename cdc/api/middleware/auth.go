@@ -0,0 +1,401 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"database/sql"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/soheilhy/cmux"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// identityKey is the key of the client identity in the gin context.
+	identityKey = "cdc-client-identity"
+	// tidbTopologyKeyPrefix is the prefix of the keys which TiDB servers
+	// register their topology info to in the etcd of PD.
+	tidbTopologyKeyPrefix = "/topology/tidb/"
+	// tidbTLSConfigName is the name of the TLS config registered to the
+	// MySQL driver for connecting to the upstream TiDB.
+	tidbTLSConfigName = "cdc_auth_tls"
+	// tidbConnectTimeout is the timeout of connecting to the upstream TiDB.
+	tidbConnectTimeout = 5 * time.Second
+	// verifiedUserTTL is how long a verified user and password is cached,
+	// so that the upstream TiDB isn't connected on every request.
+	verifiedUserTTL = time.Minute
+	// forwardedClientHeader and forwardedRoleHeader carry the identity of
+	// the client when a request is forwarded to the owner, since the owner
+	// only sees the certificate of the forwarding TiCDC server.
+	forwardedClientHeader = "TiCDC-Forwarded-Client"
+	forwardedRoleHeader   = "TiCDC-Forwarded-Role"
+)
+
+// Identity is the identity of an authenticated HTTP API client.
+type Identity struct {
+	// Name is the user, the masked token or the certificate common name.
+	Name string
+	Role security.Role
+}
+
+// Authenticator authenticates the HTTP API clients by a kind of credential.
+type Authenticator interface {
+	// Authenticate returns the identity of the client. It returns nil
+	// without error if the request doesn't carry the credential handled
+	// by the authenticator.
+	Authenticate(c *gin.Context) (*Identity, error)
+}
+
+// GetIdentity returns the identity of the authenticated client,
+// it returns nil if the client auth is disabled.
+func GetIdentity(c *gin.Context) *Identity {
+	identity, ok := c.Get(identityKey)
+	if !ok {
+		return nil
+	}
+	return identity.(*Identity)
+}
+
+// AuthenticateMiddleware authenticates the HTTP API clients with the
+// authenticators configured in the server config. It does nothing if the
+// client auth is disabled.
+func AuthenticateMiddleware(capture capture.Capture) gin.HandlerFunc {
+	cfg := config.GetGlobalServerConfig().Security
+	if cfg == nil || cfg.ClientAuth == nil || !cfg.ClientAuth.Enable {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	return authenticateMiddleware(newAuthenticators(capture, cfg)...)
+}
+
+func newAuthenticators(
+	capture capture.Capture, credential *security.Credential,
+) []Authenticator {
+	var authenticators []Authenticator
+	authCfg := credential.ClientAuth
+	if len(authCfg.Tokens) != 0 {
+		authenticators = append(authenticators, &tokenAuthenticator{tokens: authCfg.Tokens})
+	}
+	if len(authCfg.Users) != 0 {
+		verifier := &tidbUserVerifier{capture: capture, credential: credential}
+		authenticators = append(authenticators, &userAuthenticator{
+			users:  authCfg.Users,
+			verify: verifier.verify,
+		})
+	}
+	if len(authCfg.CertCommonNames) != 0 {
+		authenticators = append(authenticators,
+			&certAuthenticator{commonNames: authCfg.CertCommonNames})
+	}
+	return authenticators
+}
+
+func authenticateMiddleware(authenticators ...Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, authenticator := range authenticators {
+			identity, err := authenticator.Authenticate(c)
+			if err != nil {
+				abortUnauthenticated(c, err)
+				return
+			}
+			if identity != nil {
+				c.Set(identityKey, identity)
+				c.Next()
+				return
+			}
+		}
+		abortUnauthenticated(c,
+			cerror.ErrUnauthenticated.GenWithStackByArgs("credential not found"))
+	}
+}
+
+func abortUnauthenticated(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Basic realm="TiCDC"`)
+	c.IndentedJSON(http.StatusUnauthorized, model.NewHTTPError(err))
+	c.Abort()
+}
+
+// setForwardedIdentity sets the identity of the authenticated client to the
+// headers of the request which is going to be forwarded to the owner.
+func setForwardedIdentity(c *gin.Context) {
+	c.Request.Header.Del(forwardedClientHeader)
+	c.Request.Header.Del(forwardedRoleHeader)
+	if identity := GetIdentity(c); identity != nil {
+		c.Request.Header.Set(forwardedClientHeader, identity.Name)
+		c.Request.Header.Set(forwardedRoleHeader, string(identity.Role))
+	}
+}
+
+// AuthorizeMiddleware checks whether the authenticated client has the
+// permission required by the API. Read-only requests require the `read`
+// permission, and the others require the `write` permission.
+func AuthorizeMiddleware(read, write security.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := GetIdentity(c)
+		// the client auth is disabled.
+		if identity == nil {
+			c.Next()
+			return
+		}
+		required := write
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			required = read
+		}
+		if !identity.Role.Allows(required) {
+			c.IndentedJSON(http.StatusForbidden, model.NewHTTPError(
+				cerror.ErrUnauthorized.GenWithStackByArgs(identity.Name,
+					identity.Role, c.Request.Method, c.Request.URL.Path)))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// tokenAuthenticator authenticates the clients by the static tokens
+// carried in the `Authorization: Bearer <token>` header.
+type tokenAuthenticator struct {
+	tokens map[string]security.Role
+}
+
+func (a *tokenAuthenticator) Authenticate(c *gin.Context) (*Identity, error) {
+	auth := c.GetHeader("Authorization")
+	const prefix = "Bearer "
+	if len(auth) < len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return nil, nil
+	}
+	token := auth[len(prefix):]
+	for t, role := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return &Identity{Name: security.MaskToken(t), Role: role}, nil
+		}
+	}
+	return nil, cerror.ErrUnauthenticated.GenWithStackByArgs("invalid token")
+}
+
+// userAuthenticator authenticates the clients by the upstream TiDB users
+// and passwords carried in the HTTP basic auth header.
+type userAuthenticator struct {
+	users  map[string]security.Role
+	verify func(ctx context.Context, user, password string) error
+
+	mu sync.Mutex
+	// verified maps the hashes of the verified users and passwords
+	// to the time when they expire.
+	verified map[[sha256.Size]byte]time.Time
+}
+
+func (a *userAuthenticator) Authenticate(c *gin.Context) (*Identity, error) {
+	user, password, ok := c.Request.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+	role, ok := a.users[user]
+	if !ok {
+		return nil, cerror.ErrUnauthenticated.GenWithStackByArgs(
+			"user " + user + " is not allowed")
+	}
+	if err := a.verifyCached(c.Request.Context(), user, password); err != nil {
+		return nil, err
+	}
+	return &Identity{Name: user, Role: role}, nil
+}
+
+// verifyCached verifies the user and password, the verified ones are cached
+// for verifiedUserTTL. Only the hashes of the passwords are kept in memory.
+func (a *userAuthenticator) verifyCached(ctx context.Context, user, password string) error {
+	key := sha256.Sum256([]byte(user + "\x00" + password))
+	now := time.Now()
+	a.mu.Lock()
+	expire, ok := a.verified[key]
+	a.mu.Unlock()
+	if ok && now.Before(expire) {
+		return nil
+	}
+
+	if err := a.verify(ctx, user, password); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.verified == nil {
+		a.verified = make(map[[sha256.Size]byte]time.Time)
+	}
+	for k, expire := range a.verified {
+		if !now.Before(expire) {
+			delete(a.verified, k)
+		}
+	}
+	a.verified[key] = now.Add(verifiedUserTTL)
+	return nil
+}
+
+// tidbUserVerifier verifies the users and passwords against the upstream
+// TiDB. The TiDB servers are discovered from the topology info registered in
+// the etcd of PD, which is also used by TiCDC to store the metadata.
+type tidbUserVerifier struct {
+	capture    capture.Capture
+	credential *security.Credential
+
+	// registerTLS registers the TLS config to the MySQL driver only once.
+	registerTLS    sync.Once
+	registerTLSErr error
+}
+
+func (v *tidbUserVerifier) verify(ctx context.Context, user, password string) error {
+	etcdClient, err := v.capture.GetEtcdClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := etcdClient.GetEtcdClient().Get(ctx, tidbTopologyKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var addrs []string
+	for _, kv := range resp.Kvs {
+		// the key is in the format of /topology/tidb/{ip:port}/info.
+		key := strings.TrimPrefix(string(kv.Key), tidbTopologyKeyPrefix)
+		if strings.HasSuffix(key, "/info") {
+			addrs = append(addrs, strings.TrimSuffix(key, "/info"))
+		}
+	}
+	if len(addrs) == 0 {
+		return cerror.ErrUnauthenticated.GenWithStackByArgs("no upstream TiDB found")
+	}
+
+	dsn := dmysql.NewConfig()
+	dsn.User = user
+	dsn.Passwd = password
+	dsn.Net = "tcp"
+	dsn.Timeout = tidbConnectTimeout
+	if v.credential.IsTLSEnabled() {
+		v.registerTLS.Do(func() {
+			tlsCfg, err := v.credential.ToTLSConfig()
+			if err != nil {
+				v.registerTLSErr = errors.Trace(err)
+				return
+			}
+			v.registerTLSErr = errors.Trace(
+				dmysql.RegisterTLSConfig(tidbTLSConfigName, tlsCfg))
+		})
+		if v.registerTLSErr != nil {
+			return v.registerTLSErr
+		}
+		dsn.TLSConfig = tidbTLSConfigName
+	}
+
+	for _, addr := range addrs {
+		dsn.Addr = addr
+		err = pingTiDB(ctx, dsn.FormatDSN())
+		if err == nil {
+			return nil
+		}
+		if mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError); ok &&
+			mysqlErr.Number == tmysql.ErrAccessDenied {
+			return cerror.ErrUnauthenticated.GenWithStackByArgs(
+				"invalid password of user " + user)
+		}
+		log.Warn("failed to connect to upstream TiDB for verifying user",
+			zap.String("addr", addr), zap.String("user", user), zap.Error(err))
+	}
+	return cerror.ErrUnauthenticated.Wrap(err).
+		GenWithStackByArgs("failed to connect to upstream TiDB")
+}
+
+func pingTiDB(ctx context.Context, dsn string) error {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer db.Close()
+	return db.PingContext(ctx)
+}
+
+// certAuthenticator authenticates the clients by the common names of the
+// certificates used in the mTLS connections.
+type certAuthenticator struct {
+	commonNames map[string]security.Role
+}
+
+func (a *certAuthenticator) Authenticate(c *gin.Context) (*Identity, error) {
+	state := tlsConnectionState(c.Request)
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil, nil
+	}
+	cn := state.PeerCertificates[0].Subject.CommonName
+	role, ok := a.commonNames[cn]
+	if !ok {
+		return nil, cerror.ErrUnauthenticated.GenWithStackByArgs(
+			"certificate common name " + cn + " is not allowed")
+	}
+	// the request is forwarded by a TiCDC server on behalf of the client,
+	// the forwarded identity is trusted if the server has all its permissions.
+	if name := c.GetHeader(forwardedClientHeader); name != "" {
+		forwarded := security.Role(c.GetHeader(forwardedRoleHeader))
+		if !role.Covers(forwarded) {
+			return nil, cerror.ErrUnauthenticated.GenWithStackByArgs(
+				"certificate common name " + cn +
+					" can not forward requests of role " + string(forwarded))
+		}
+		return &Identity{Name: name, Role: forwarded}, nil
+	}
+	return &Identity{Name: cn, Role: role}, nil
+}
+
+type tlsConnKey struct{}
+
+// ConnContext is used as the http.Server.ConnContext to keep the TLS
+// connection in the request context. The TLS connections are wrapped by
+// cmux, so the http.Request.TLS isn't set by the HTTP server.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if muxConn, ok := conn.(*cmux.MuxConn); ok {
+		conn = muxConn.Conn
+	}
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		return context.WithValue(ctx, tlsConnKey{}, tlsConn)
+	}
+	return ctx
+}
+
+func tlsConnectionState(req *http.Request) *tls.ConnectionState {
+	if req.TLS != nil {
+		return req.TLS
+	}
+	if tlsConn, ok := req.Context().Value(tlsConnKey{}).(*tls.Conn); ok {
+		state := tlsConn.ConnectionState()
+		return &state
+	}
+	return nil
+}
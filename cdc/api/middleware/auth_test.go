@@ -0,0 +1,253 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthTestRouter(authenticators ...Authenticator) *gin.Engine {
+	router := gin.New()
+	router.Use(authenticateMiddleware(authenticators...))
+	group := router.Group("/test")
+	group.Use(AuthorizeMiddleware(security.PermissionRead, security.PermissionWrite))
+	group.GET("", func(c *gin.Context) {
+		c.String(http.StatusOK, GetIdentity(c).Name)
+	})
+	group.POST("", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/unsafe", AuthorizeMiddleware(security.PermissionAdmin, security.PermissionAdmin),
+		func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+	return router
+}
+
+func doAuthTestRequest(
+	router *gin.Engine, method, url string, setup func(req *http.Request),
+) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), method, url, nil)
+	if setup != nil {
+		setup(req)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	router := newAuthTestRouter(&tokenAuthenticator{tokens: map[string]security.Role{
+		"viewer-token": security.RoleViewer,
+		"admin-token":  security.RoleAdmin,
+	}})
+	withToken := func(token string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	w := doAuthTestRequest(router, "GET", "/test", nil)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "credential not found")
+
+	w = doAuthTestRequest(router, "GET", "/test", withToken("invalid"))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "invalid token")
+
+	w = doAuthTestRequest(router, "GET", "/test", withToken("viewer-token"))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "token-view****", w.Body.String())
+
+	w = doAuthTestRequest(router, "POST", "/test", withToken("viewer-token"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "ErrUnauthorized")
+
+	w = doAuthTestRequest(router, "POST", "/unsafe", withToken("admin-token"))
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUserAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	router := newAuthTestRouter(&userAuthenticator{
+		users: map[string]security.Role{"root": security.RoleOperator},
+		verify: func(ctx context.Context, user, password string) error {
+			if password != "123" {
+				return cerror.ErrUnauthenticated.GenWithStackByArgs("invalid password")
+			}
+			return nil
+		},
+	})
+	withUser := func(user, password string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.SetBasicAuth(user, password)
+		}
+	}
+
+	w := doAuthTestRequest(router, "GET", "/test", withUser("test", "123"))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "user test is not allowed")
+
+	w = doAuthTestRequest(router, "GET", "/test", withUser("root", "456"))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "invalid password")
+
+	w = doAuthTestRequest(router, "POST", "/test", withUser("root", "123"))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = doAuthTestRequest(router, "POST", "/unsafe", withUser("root", "123"))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUserAuthenticatorCache(t *testing.T) {
+	t.Parallel()
+
+	verified := 0
+	router := newAuthTestRouter(&userAuthenticator{
+		users: map[string]security.Role{"root": security.RoleOperator},
+		verify: func(ctx context.Context, user, password string) error {
+			verified++
+			if password != "123" {
+				return cerror.ErrUnauthenticated.GenWithStackByArgs("invalid password")
+			}
+			return nil
+		},
+	})
+	withUser := func(user, password string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.SetBasicAuth(user, password)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		w := doAuthTestRequest(router, "GET", "/test", withUser("root", "123"))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	require.Equal(t, 1, verified)
+
+	// the failed verifications are not cached, and a wrong password
+	// doesn't hit the cache of the right one.
+	for i := 0; i < 2; i++ {
+		w := doAuthTestRequest(router, "GET", "/test", withUser("root", "456"))
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	require.Equal(t, 3, verified)
+}
+
+func TestCertAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	router := newAuthTestRouter(
+		&tokenAuthenticator{tokens: map[string]security.Role{"token": security.RoleViewer}},
+		&certAuthenticator{commonNames: map[string]security.Role{"client": security.RoleAdmin}},
+	)
+	withCert := func(cn string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+				{Subject: pkix.Name{CommonName: cn}},
+			}}
+		}
+	}
+
+	w := doAuthTestRequest(router, "GET", "/test", withCert("unknown"))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "common name unknown is not allowed")
+
+	w = doAuthTestRequest(router, "POST", "/unsafe", withCert("client"))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// the token takes precedence over the certificate.
+	w = doAuthTestRequest(router, "POST", "/unsafe", func(req *http.Request) {
+		withCert("client")(req)
+		req.Header.Set("Authorization", "Bearer token")
+	})
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCertAuthenticatorForwarded(t *testing.T) {
+	t.Parallel()
+
+	router := newAuthTestRouter(&certAuthenticator{commonNames: map[string]security.Role{
+		"server":   security.RoleAdmin,
+		"operator": security.RoleOperator,
+	}})
+	forwarded := func(cn, client string, role security.Role) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+				{Subject: pkix.Name{CommonName: cn}},
+			}}
+			req.Header.Set(forwardedClientHeader, client)
+			req.Header.Set(forwardedRoleHeader, string(role))
+		}
+	}
+
+	// the identity of the client is kept after forwarding.
+	w := doAuthTestRequest(router, "GET", "/test", forwarded("server", "client", security.RoleViewer))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "client", w.Body.String())
+	w = doAuthTestRequest(router, "POST", "/test", forwarded("server", "client", security.RoleViewer))
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// a forwarded role can't exceed the role of the forwarding certificate.
+	w = doAuthTestRequest(router, "POST", "/unsafe", forwarded("operator", "client", security.RoleAdmin))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "can not forward requests of role admin")
+}
+
+func TestSetForwardedIdentity(t *testing.T) {
+	t.Parallel()
+
+	router := gin.New()
+	router.Use(authenticateMiddleware(&tokenAuthenticator{
+		tokens: map[string]security.Role{"viewer-token": security.RoleViewer},
+	}))
+	router.GET("/test", func(c *gin.Context) {
+		setForwardedIdentity(c)
+		c.String(http.StatusOK, c.Request.Header.Get(forwardedClientHeader)+","+
+			c.Request.Header.Get(forwardedRoleHeader))
+	})
+	w := doAuthTestRequest(router, "GET", "/test", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer viewer-token")
+		// the headers set by the client are overwritten.
+		req.Header.Set(forwardedRoleHeader, string(security.RoleAdmin))
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "token-view****,viewer", w.Body.String())
+}
+
+func TestAuthorizeWithoutAuthentication(t *testing.T) {
+	t.Parallel()
+
+	router := gin.New()
+	router.POST("/unsafe", AuthorizeMiddleware(security.PermissionAdmin, security.PermissionAdmin),
+		func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+	w := doAuthTestRequest(router, "POST", "/unsafe", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+}
@@ -45,6 +45,10 @@ func LogMiddleware() gin.HandlerFunc {
 			stdErr = err.Err
 		}
 		version := c.Request.Header.Get(ClientVersionHeader)
+		var client string
+		if identity := GetIdentity(c); identity != nil {
+			client = identity.Name
+		}
 		log.Info(path,
 			zap.Int("status", c.Writer.Status()),
 			zap.String("method", c.Request.Method),
@@ -52,6 +56,7 @@ func LogMiddleware() gin.HandlerFunc {
 			zap.String("query", query),
 			zap.String("ip", c.ClientIP()),
 			zap.String("user-agent", c.Request.UserAgent()), zap.String("client-version", version),
+			zap.String("client", client),
 			zap.Error(stdErr),
 			zap.Duration("duration", cost),
 		)
@@ -84,6 +89,7 @@ func ErrorHandleMiddleware() gin.HandlerFunc {
 func ForwardToOwnerMiddleware(p capture.Capture) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		if !p.IsOwner() {
+			setForwardedIdentity(ctx)
 			api.ForwardToOwner(ctx, p)
 
 			// Without calling Abort(), Gin will continued to process the next handler,
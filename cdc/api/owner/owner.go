@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/logutil"
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.etcd.io/etcd/client/v3/concurrency"
 	"go.uber.org/zap"
@@ -85,6 +86,9 @@ func RegisterOwnerAPIRoutes(router *gin.Engine, capture capture.Capture) {
 
 	owner.Use(middleware.ErrorHandleMiddleware())
 	owner.Use(middleware.LogMiddleware())
+	owner.Use(middleware.AuthenticateMiddleware(capture))
+	owner.Use(middleware.AuthorizeMiddleware(
+		security.PermissionAdmin, security.PermissionAdmin))
 
 	owner.POST("/resign", gin.WrapF(ownerAPI.handleResignOwner))
 	owner.POST("/admin", gin.WrapF(ownerAPI.handleChangefeedAdmin))
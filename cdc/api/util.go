@@ -150,6 +150,46 @@ func HandleOwnerScheduleTable(
 	}
 }
 
+// HandleOwnerUpdateFilter updates the filter rules of a running changefeed
+func HandleOwnerUpdateFilter(
+	ctx context.Context, capture capture.Capture,
+	changefeedID model.ChangeFeedID, update *model.FilterUpdate,
+) error {
+	// Use buffered channel to prevent blocking owner.
+	done := make(chan error, 1)
+	o, err := capture.GetOwner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.UpdateFilter(changefeedID, update, done)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-done:
+		return errors.Trace(err)
+	}
+}
+
+// HandleOwnerResolveDDL resolves the pending DDL of a changefeed
+func HandleOwnerResolveDDL(
+	ctx context.Context, capture capture.Capture,
+	changefeedID model.ChangeFeedID, decision *model.DDLDecision,
+) error {
+	// Use buffered channel to prevent blocking owner.
+	done := make(chan error, 1)
+	o, err := capture.GetOwner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.ResolveDDL(changefeedID, decision, done)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-done:
+		return errors.Trace(err)
+	}
+}
+
 // ForwardToOwner forwards an request to the owner
 func ForwardToOwner(c *gin.Context, p capture.Capture) {
 	ctx := c.Request.Context()
@@ -30,6 +30,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/tikv/client-go/v2/oracle"
@@ -76,14 +77,23 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api OpenAPI) {
 	v1.Use(middleware.CheckServerReadyMiddleware(api.capture))
 	v1.Use(middleware.LogMiddleware())
 	v1.Use(middleware.ErrorHandleMiddleware())
+	v1.Use(middleware.AuthenticateMiddleware(api.capture))
+
+	readWrite := middleware.AuthorizeMiddleware(
+		security.PermissionRead, security.PermissionWrite)
+	readAdmin := middleware.AuthorizeMiddleware(
+		security.PermissionRead, security.PermissionAdmin)
+	admin := middleware.AuthorizeMiddleware(
+		security.PermissionAdmin, security.PermissionAdmin)
 
 	// common API
-	v1.GET("/status", api.ServerStatus)
-	v1.GET("/health", api.Health)
-	v1.POST("/log", SetLogLevel)
+	v1.GET("/status", readWrite, api.ServerStatus)
+	v1.GET("/health", readWrite, api.Health)
+	v1.POST("/log", admin, SetLogLevel)
 
 	// changefeed API
 	changefeedGroup := v1.Group("/changefeeds")
+	changefeedGroup.Use(readWrite)
 	changefeedGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	changefeedGroup.GET("", api.ListChangefeed)
 	changefeedGroup.GET("/:changefeed_id", api.GetChangefeed)
@@ -97,17 +107,20 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api OpenAPI) {
 
 	// owner API
 	ownerGroup := v1.Group("/owner")
+	ownerGroup.Use(admin)
 	ownerGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	ownerGroup.POST("/resign", api.ResignOwner)
 
 	// processor API
 	processorGroup := v1.Group("/processors")
+	processorGroup.Use(readWrite)
 	processorGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	processorGroup.GET("", api.ListProcessor)
 	processorGroup.GET("/:changefeed_id/:capture_id", api.GetProcessor)
 
 	// capture API
 	captureGroup := v1.Group("/captures")
+	captureGroup.Use(readAdmin)
 	captureGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	captureGroup.GET("", api.ListCapture)
 	captureGroup.PUT("/drain", api.DrainCapture)
@@ -24,9 +24,11 @@ import (
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/transform"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
@@ -159,6 +161,14 @@ func verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, err
 	}
+	err = transform.VerifyTables(replicaConfig, tableInfos)
+	if err != nil {
+		return nil, err
+	}
+	err = dispatcher.VerifyTables(changefeedConfig.SinkURI, replicaConfig, tableInfos)
+	if err != nil {
+		return nil, err
+	}
 	if !replicaConfig.ForceReplicate && !changefeedConfig.IgnoreIneligibleTable {
 		if len(ineligibleTables) != 0 {
 			return nil, cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables)
@@ -173,6 +183,7 @@ func verifyCreateChangefeedConfig(
 	if err := sink.Validate(ctx, info.SinkURI, info.Config); err != nil {
 		return nil, err
 	}
+	info.PrepareInitialSnapshot()
 
 	return info, nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/tiflow/cdc/api/middleware"
 	"github.com/pingcap/tiflow/cdc/capture"
+	"github.com/pingcap/tiflow/pkg/security"
 )
 
 // OpenAPIV2 provides CDC v2 APIs
@@ -42,26 +43,66 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	v2.Use(middleware.CheckServerReadyMiddleware(api.capture))
 	v2.Use(middleware.LogMiddleware())
 	v2.Use(middleware.ErrorHandleMiddleware())
+	v2.Use(middleware.AuthenticateMiddleware(api.capture))
+
+	readWrite := middleware.AuthorizeMiddleware(
+		security.PermissionRead, security.PermissionWrite)
+	readAdmin := middleware.AuthorizeMiddleware(
+		security.PermissionRead, security.PermissionAdmin)
+	admin := middleware.AuthorizeMiddleware(
+		security.PermissionAdmin, security.PermissionAdmin)
 
 	// changefeed apis
 	changefeedGroup := v2.Group("/changefeeds")
+	changefeedGroup.Use(readWrite)
 	changefeedGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	changefeedGroup.GET("", api.listChangeFeeds)
 	changefeedGroup.POST("", api.createChangefeed)
+	changefeedGroup.GET("/:changefeed_id", api.getChangeFeed)
 	changefeedGroup.PUT("/:changefeed_id", api.updateChangefeed)
+	changefeedGroup.DELETE("/:changefeed_id", api.deleteChangefeed)
 	changefeedGroup.GET("/:changefeed_id/meta_info", api.getChangeFeedMetaInfo)
+	changefeedGroup.GET("/:changefeed_id/status", api.getChangefeedStatus)
+	changefeedGroup.POST("/:changefeed_id/pause", api.pauseChangefeed)
 	changefeedGroup.POST("/:changefeed_id/resume", api.resumeChangefeed)
+	changefeedGroup.POST("/:changefeed_id/move_table", api.moveTable)
+	changefeedGroup.POST("/:changefeed_id/tables", api.updateTables)
+	changefeedGroup.POST("/:changefeed_id/ddl", api.resolveDDL)
 
 	verifyTableGroup := v2.Group("/verify_table")
+	verifyTableGroup.Use(readWrite)
 	verifyTableGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	verifyTableGroup.POST("", api.verifyTable)
 
+	// processor apis
+	processorGroup := v2.Group("/processors")
+	processorGroup.Use(readWrite)
+	processorGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	processorGroup.GET("", api.listProcessors)
+	processorGroup.GET("/:changefeed_id/:capture_id", api.getProcessor)
+
+	// capture apis
+	captureGroup := v2.Group("/captures")
+	captureGroup.Use(readAdmin)
+	captureGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	captureGroup.GET("", api.listCaptures)
+	captureGroup.POST("/:capture_id/drain", api.drainCapture)
+
+	// owner apis
+	ownerGroup := v2.Group("/owner")
+	ownerGroup.Use(admin)
+	ownerGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	ownerGroup.POST("/resign", api.resignOwner)
+
 	// unsafe apis
 	unsafeGroup := v2.Group("/unsafe")
+	unsafeGroup.Use(admin)
 	unsafeGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
 	unsafeGroup.GET("/metadata", api.CDCMetaData)
 	unsafeGroup.POST("/resolve_lock", api.ResolveLock)
 	unsafeGroup.DELETE("/service_gc_safepoint", api.DeleteServiceGcSafePoint)
 
 	// common APIs
-	v2.POST("/tso", api.QueryTso)
+	v2.POST("/tso", middleware.AuthorizeMiddleware(
+		security.PermissionRead, security.PermissionRead), api.QueryTso)
 }
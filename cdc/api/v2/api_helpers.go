@@ -28,10 +28,12 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/pingcap/tiflow/pkg/transform"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/version"
 	"github.com/r3labs/diff"
@@ -227,6 +229,14 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	if err != nil {
 		return nil, errors.Cause(err)
 	}
+	err = transform.VerifyTables(replicaCfg, tableInfos)
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
+	err = dispatcher.VerifyTables(cfg.SinkURI, replicaCfg, tableInfos)
+	if err != nil {
+		return nil, errors.Cause(err)
+	}
 	if !replicaCfg.ForceReplicate && !cfg.ReplicaConfig.IgnoreIneligibleTable {
 		if err != nil {
 			return nil, err
@@ -241,7 +251,7 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 		return nil, err
 	}
 
-	return &model.ChangeFeedInfo{
+	info := &model.ChangeFeedInfo{
 		UpstreamID:     pdClient.GetClusterID(ctx),
 		Namespace:      cfg.Namespace,
 		ID:             cfg.ID,
@@ -253,7 +263,9 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 		Config:         replicaCfg,
 		State:          model.StateNormal,
 		CreatorVersion: version.ReleaseVersion,
-	}, nil
+	}
+	info.PrepareInitialSnapshot()
+	return info, nil
 }
 
 // verifyUpstream verifies the upstream config before updating a changefeed
@@ -334,6 +346,11 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 		return nil, nil, cerror.ErrChangefeedUpdateRefused.
 			GenWithStackByArgs(errors.Cause(err).Error())
 	}
+	err = transform.VerifyTables(newInfo.Config, tableInfos)
+	if err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.
+			GenWithStackByArgs(errors.Cause(err).Error())
+	}
 
 	// verify SinkURI
 	if cfg.SinkURI != "" {
@@ -342,6 +359,10 @@ func (APIV2HelpersImpl) verifyUpdateChangefeedConfig(
 			return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
 		}
 	}
+	err = dispatcher.VerifyTables(newInfo.SinkURI, newInfo.Config, tableInfos)
+	if err != nil {
+		return nil, nil, cerror.ErrChangefeedUpdateRefused.GenWithStackByCause(err)
+	}
 	if cfg.Engine != "" {
 		newInfo.Engine = cfg.Engine
 	}
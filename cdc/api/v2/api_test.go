@@ -62,9 +62,14 @@ func (c *mockPDClient) Close() {}
 
 type mockStatusProvider struct {
 	owner.StatusProvider
-	changefeedStatus *model.ChangeFeedStatus
-	changefeedInfo   *model.ChangeFeedInfo
-	err              error
+	changefeedStatus   *model.ChangeFeedStatus
+	changefeedInfo     *model.ChangeFeedInfo
+	changefeedStatuses map[model.ChangeFeedID]*model.ChangeFeedStatus
+	changefeedInfos    map[model.ChangeFeedID]*model.ChangeFeedInfo
+	taskStatuses       map[model.CaptureID]*model.TaskStatus
+	processors         []*model.ProcInfoSnap
+	captures           []*model.CaptureInfo
+	err                error
 }
 
 // GetChangeFeedStatus returns a changefeeds' runtime status.
@@ -80,3 +85,34 @@ func (m *mockStatusProvider) GetChangeFeedInfo(ctx context.Context,
 ) (*model.ChangeFeedInfo, error) {
 	return m.changefeedInfo, m.err
 }
+
+// GetAllChangeFeedStatuses returns mock changefeeds' runtime status.
+func (m *mockStatusProvider) GetAllChangeFeedStatuses(ctx context.Context) (
+	map[model.ChangeFeedID]*model.ChangeFeedStatus, error,
+) {
+	return m.changefeedStatuses, m.err
+}
+
+// GetAllChangeFeedInfo returns mock changefeeds' info.
+func (m *mockStatusProvider) GetAllChangeFeedInfo(ctx context.Context) (
+	map[model.ChangeFeedID]*model.ChangeFeedInfo, error,
+) {
+	return m.changefeedInfos, m.err
+}
+
+// GetAllTaskStatuses returns the mock task status of a changefeed.
+func (m *mockStatusProvider) GetAllTaskStatuses(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) (map[model.CaptureID]*model.TaskStatus, error) {
+	return m.taskStatuses, m.err
+}
+
+// GetProcessors returns mock processors.
+func (m *mockStatusProvider) GetProcessors(ctx context.Context) ([]*model.ProcInfoSnap, error) {
+	return m.processors, m.err
+}
+
+// GetCaptures returns mock captures.
+func (m *mockStatusProvider) GetCaptures(ctx context.Context) ([]*model.CaptureInfo, error) {
+	return m.captures, m.err
+}
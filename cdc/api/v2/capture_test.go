@@ -0,0 +1,128 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	"github.com/pingcap/tiflow/cdc/scheduler"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCaptures(t *testing.T) {
+	t.Parallel()
+
+	list := testCase{url: "/api/v2/captures", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClient(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().Info().Return(model.CaptureInfo{ID: "capture-1"}, nil).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient, nil).AnyTimes()
+	etcdClient.EXPECT().GetClusterID().Return("default").AnyTimes()
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	statusProvider := &mockStatusProvider{
+		captures: []*model.CaptureInfo{
+			{ID: "capture-1", AdvertiseAddr: "127.0.0.1:8300"},
+			{ID: "capture-2", AdvertiseAddr: "127.0.0.1:8301"},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), list.method, list.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ListResponse[Capture]{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, 2, resp.Total)
+	require.Equal(t, []Capture{
+		{ID: "capture-1", IsOwner: true, AdvertiseAddr: "127.0.0.1:8300", ClusterID: "default"},
+		{ID: "capture-2", IsOwner: false, AdvertiseAddr: "127.0.0.1:8301", ClusterID: "default"},
+	}, resp.Items)
+}
+
+func TestDrainCapture(t *testing.T) {
+	t.Parallel()
+
+	drain := testCase{url: "/api/v2/captures/%s/drain", method: "POST"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	o := mock_owner.NewMockOwner(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().Info().Return(model.CaptureInfo{ID: "capture-1"}, nil).AnyTimes()
+	cp.EXPECT().GetOwner().Return(o, nil).AnyTimes()
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	statusProvider := &mockStatusProvider{
+		captures: []*model.CaptureInfo{{ID: "capture-1"}},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+
+	request := func(captureID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(),
+			drain.method, fmt.Sprintf(drain.url, captureID), nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	requireError := func(w *httptest.ResponseRecorder, code string) {
+		respErr := model.HTTPError{}
+		err := json.NewDecoder(w.Body).Decode(&respErr)
+		require.Nil(t, err)
+		require.Contains(t, respErr.Code, code)
+	}
+
+	// case 1: only one capture alive
+	w := request("capture-1")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	requireError(w, "ErrSchedulerRequestFailed")
+
+	// case 2: capture not exists
+	statusProvider.captures = append(statusProvider.captures, &model.CaptureInfo{ID: "capture-2"})
+	w = request("capture-3")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	requireError(w, "ErrCaptureNotExist")
+
+	// case 3: cannot drain the owner
+	w = request("capture-1")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	requireError(w, "ErrSchedulerRequestFailed")
+
+	// case 4: success
+	o.EXPECT().DrainCapture(gomock.Any(), gomock.Any()).
+		Do(func(query *scheduler.Query, done chan<- error) {
+			require.Equal(t, "capture-2", query.CaptureID)
+			query.Resp = &model.DrainCaptureResp{CurrentTableCount: 3}
+			close(done)
+		}).Times(1)
+	w = request("capture-2")
+	require.Equal(t, http.StatusAccepted, w.Code)
+	resp := DrainCaptureResp{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, 3, resp.CurrentTableCount)
+}
@@ -16,6 +16,7 @@ package v2
 import (
 	"context"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,15 +26,26 @@ import (
 	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
-const apiOpVarChangefeedID = "changefeed_id"
+const (
+	// apiOpVarChangefeedState is the key of changefeed state in HTTP API
+	apiOpVarChangefeedState = "state"
+	// apiOpVarChangefeedID is the key of changefeed ID in HTTP API
+	apiOpVarChangefeedID = "changefeed_id"
+	// apiOpVarCaptureID is the key of capture ID in HTTP API
+	apiOpVarCaptureID = "capture_id"
+)
 
 // createChangefeed handles create changefeed request,
 // it returns the changefeed's changefeedInfo that it just created
@@ -402,16 +414,382 @@ func (h *OpenAPIV2) resumeChangefeed(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
-func toAPIModel(info *model.ChangeFeedInfo, maskSinkURI bool) *ChangeFeedInfo {
-	var runningError *RunningError
-	if info.Error != nil {
-		runningError = &RunningError{
-			Addr:    info.Error.Addr,
-			Code:    info.Error.Code,
-			Message: info.Error.Message,
+// listChangeFeeds lists all changefeeds in cdc cluster,
+// the changefeeds can be filtered by the state query parameter.
+func (h *OpenAPIV2) listChangeFeeds(c *gin.Context) {
+	ctx := c.Request.Context()
+	state := c.Query(apiOpVarChangefeedState)
+	statuses, err := h.capture.StatusProvider().GetAllChangeFeedStatuses(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	infos, err := h.capture.StatusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	changefeeds := make([]model.ChangeFeedID, 0, len(statuses))
+	for cfID := range statuses {
+		changefeeds = append(changefeeds, cfID)
+	}
+	sort.Slice(changefeeds, func(i, j int) bool {
+		if changefeeds[i].Namespace == changefeeds[j].Namespace {
+			return changefeeds[i].ID < changefeeds[j].ID
+		}
+		return changefeeds[i].Namespace < changefeeds[j].Namespace
+	})
+
+	commonInfos := make([]ChangefeedCommonInfo, 0, len(changefeeds))
+	for _, cfID := range changefeeds {
+		cfInfo, exist := infos[cfID]
+		if !exist {
+			// If a changefeed info does not exists, skip it
+			continue
+		}
+		if !cfInfo.State.IsNeeded(state) {
+			continue
+		}
+		commonInfo := ChangefeedCommonInfo{
+			UpstreamID:   cfInfo.UpstreamID,
+			Namespace:    cfID.Namespace,
+			ID:           cfID.ID,
+			FeedState:    cfInfo.State,
+			RunningError: toAPIRunningError(cfInfo.Error),
+		}
+		if cfStatus := statuses[cfID]; cfStatus != nil {
+			commonInfo.CheckpointTSO = cfStatus.CheckpointTs
+			commonInfo.CheckpointTime = model.JSONTime(
+				oracle.GetTimeFromTS(cfStatus.CheckpointTs))
+		}
+		commonInfos = append(commonInfos, commonInfo)
+	}
+	c.JSON(http.StatusOK, &ListResponse[ChangefeedCommonInfo]{
+		Total: len(commonInfos),
+		Items: commonInfos,
+	})
+}
+
+// getChangeFeed returns the detail of a changefeed, including its config,
+// replication progress and the tables of each capture.
+func (h *OpenAPIV2) getChangeFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var taskStatus []CaptureTaskStatus
+	if info.State == model.StateNormal {
+		processorInfos, err := h.capture.StatusProvider().
+			GetAllTaskStatuses(ctx, changefeedID)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		for captureID, status := range processorInfos {
+			tables := make([]int64, 0, len(status.Tables))
+			for tableID := range status.Tables {
+				tables = append(tables, tableID)
+			}
+			sort.Slice(tables, func(i, j int) bool { return tables[i] < tables[j] })
+			taskStatus = append(taskStatus,
+				CaptureTaskStatus{CaptureID: captureID, Tables: tables})
+		}
+		sort.Slice(taskStatus, func(i, j int) bool {
+			return taskStatus[i].CaptureID < taskStatus[j].CaptureID
+		})
+	}
+
+	detail := toAPIModel(info, true)
+	detail.ResolvedTs = status.ResolvedTs
+	detail.CheckpointTs = status.CheckpointTs
+	detail.CheckpointTime = model.JSONTime(oracle.GetTimeFromTS(status.CheckpointTs))
+	detail.TaskStatus = taskStatus
+	totalTables := 0
+	for _, status := range taskStatus {
+		totalTables += len(status.Tables)
+	}
+	detail.InitialSnapshot = NewInitialSnapshotStatus(info.InitialSnapshotTs,
+		status.CheckpointTs, len(info.InitialSnapshotFinishedTables), totalTables)
+	c.JSON(http.StatusOK, detail)
+}
+
+// pauseChangefeed handles pause changefeed request.
+func (h *OpenAPIV2) pauseChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	// check if the changefeed exists
+	_, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	job := model.AdminJob{
+		CfID: changefeedID,
+		Type: model.AdminStop,
+	}
+	if err := api.HandleOwnerJob(ctx, h.capture, job); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// deleteChangefeed handles remove changefeed request, it returns after the
+// changefeed is removed. Removing a changefeed which doesn't exist succeeds.
+func (h *OpenAPIV2) deleteChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	_, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		if cerror.ErrChangeFeedNotExists.Equal(err) {
+			c.Status(http.StatusOK)
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	job := model.AdminJob{
+		CfID: changefeedID,
+		Type: model.AdminRemove,
+	}
+	if err := api.HandleOwnerJob(ctx, h.capture, job); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// Owner needs at least two ticks to remove a changefeed,
+	// we need to wait for it.
+	err = retry.Do(ctx, func() error {
+		_, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+		if err != nil {
+			if cerror.ErrChangeFeedNotExists.Equal(err) {
+				return nil
+			}
+			return err
+		}
+		return cerror.ErrChangeFeedDeletionUnfinished.GenWithStackByArgs(changefeedID)
+	},
+		retry.WithMaxTries(100),         // max retry duration is 1 minute
+		retry.WithBackoffBaseDelay(600), // default owner tick interval is 200ms
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// getChangefeedStatus returns the runtime status of a changefeed.
+func (h *OpenAPIV2) getChangefeedStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &ChangefeedStatus{
+		State:        string(info.State),
+		ResolvedTs:   status.ResolvedTs,
+		CheckpointTs: status.CheckpointTs,
+		LastError:    toAPIRunningError(info.Error),
+		PendingDDL:   ToAPIPendingDDL(info.PendingDDL),
+	})
+}
+
+// moveTable moves a table of a changefeed to the target capture.
+func (h *OpenAPIV2) moveTable(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	// check if the changefeed exists
+	_, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	req := &MoveTableReq{}
+	if err := c.BindJSON(req); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if err := model.ValidateChangefeedID(req.CaptureID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid capture_id: %s",
+			req.CaptureID))
+		return
+	}
+
+	err = api.HandleOwnerScheduleTable(
+		ctx, h.capture, changefeedID, req.CaptureID, req.TableID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// updateTables adds or removes tables of a running changefeed without pausing it.
+func (h *OpenAPIV2) updateTables(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfInfo, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	req := &UpdateTablesReq{}
+	if err := c.BindJSON(req); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if len(req.AddTables) == 0 && len(req.RemoveTables) == 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"no tables to add or remove"))
+		return
+	}
+	rules, err := updateFilterRules(cfInfo.Config.Filter.Rules, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	err = api.HandleOwnerUpdateFilter(ctx, h.capture, changefeedID,
+		&model.FilterUpdate{Rules: rules, ApplyTs: req.StartTs})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// resolveDDL approves, skips or replaces the pending DDL of a changefeed.
+func (h *OpenAPIV2) resolveDDL(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	// check if the changefeed exists
+	_, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	req := &ResolveDDLReq{}
+	if err := c.BindJSON(req); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	decision := &model.DDLDecision{
+		JobID:  req.JobID,
+		Action: model.DDLAction(req.Action),
+		Query:  req.Query,
+	}
+	switch decision.Action {
+	case model.DDLActionApprove, model.DDLActionSkip, model.DDLActionReplace:
+	default:
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid action %s, must be one of approve, skip and replace", req.Action))
+		return
+	}
+
+	err = api.HandleOwnerResolveDDL(ctx, h.capture, changefeedID, decision)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+// updateFilterRules returns the filter rules with the tables added or removed.
+// A table filter takes the last rule that matches a table, so the rules of
+// the added and removed tables are appended to the existing rules.
+func updateFilterRules(rules []string, req *UpdateTablesReq) ([]string, error) {
+	newRules := make([]string, 0, len(rules)+len(req.AddTables)+len(req.RemoveTables))
+	newRules = append(newRules, rules...)
+	for _, table := range req.AddTables {
+		if strings.HasPrefix(table, "!") {
+			return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid table to add: %s", table)
+		}
+		newRules = append(newRules, table)
+	}
+	for _, table := range req.RemoveTables {
+		if strings.HasPrefix(table, "!") {
+			return nil, cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid table to remove: %s", table)
 		}
+		newRules = append(newRules, "!"+table)
+	}
+	if _, err := filter.VerifyTableRules(&config.FilterConfig{Rules: newRules}); err != nil {
+		return nil, cerror.WrapError(cerror.ErrAPIInvalidParam, err)
+	}
+	return newRules, nil
+}
+
+func toAPIRunningError(err *model.RunningError) *RunningError {
+	if err == nil {
+		return nil
+	}
+	return &RunningError{
+		Addr:    err.Addr,
+		Code:    err.Code,
+		Message: err.Message,
 	}
+}
 
+func toAPIModel(info *model.ChangeFeedInfo, maskSinkURI bool) *ChangeFeedInfo {
 	sinkURI := info.SinkURI
 	var err error
 	if maskSinkURI {
@@ -433,9 +811,16 @@ func toAPIModel(info *model.ChangeFeedInfo, maskSinkURI bool) *ChangeFeedInfo {
 		Engine:         info.Engine,
 		Config:         ToAPIReplicaConfig(info.Config),
 		State:          info.State,
-		Error:          runningError,
+		Error:          toAPIRunningError(info.Error),
 		CreatorVersion: info.CreatorVersion,
 	}
+	if info.InitialSnapshotTs != 0 {
+		apiInfoModel.InitialSnapshot = &InitialSnapshotStatus{
+			SnapshotTs:     info.InitialSnapshotTs,
+			FinishedTables: len(info.InitialSnapshotFinishedTables),
+		}
+	}
+	apiInfoModel.PendingDDL = ToAPIPendingDDL(info.PendingDDL)
 	return apiInfoModel
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/label"
 	"github.com/pingcap/tiflow/pkg/security"
 )
 
@@ -89,19 +90,24 @@ type ChangefeedConfig struct {
 
 // ReplicaConfig is a duplicate of  config.ReplicaConfig
 type ReplicaConfig struct {
-	CaseSensitive         bool              `json:"case_sensitive"`
-	EnableOldValue        bool              `json:"enable_old_value"`
-	ForceReplicate        bool              `json:"force_replicate"`
-	IgnoreIneligibleTable bool              `json:"ignore_ineligible_table"`
-	CheckGCSafePoint      bool              `json:"check_gc_safe_point"`
-	EnableSyncPoint       bool              `json:"enable_sync_point"`
-	BDRMode               bool              `json:"bdr_mode"`
-	SyncPointInterval     time.Duration     `json:"sync_point_interval"`
-	SyncPointRetention    time.Duration     `json:"sync_point_retention"`
-	Filter                *FilterConfig     `json:"filter"`
-	Mounter               *MounterConfig    `json:"mounter"`
-	Sink                  *SinkConfig       `json:"sink"`
-	Consistent            *ConsistentConfig `json:"consistent"`
+	CaseSensitive         bool               `json:"case_sensitive"`
+	EnableOldValue        bool               `json:"enable_old_value"`
+	ForceReplicate        bool               `json:"force_replicate"`
+	IgnoreIneligibleTable bool               `json:"ignore_ineligible_table"`
+	CheckGCSafePoint      bool               `json:"check_gc_safe_point"`
+	EnableSyncPoint       bool               `json:"enable_sync_point"`
+	BDRMode               bool               `json:"bdr_mode"`
+	SyncPointInterval     time.Duration      `json:"sync_point_interval"`
+	SyncPointRetention    time.Duration      `json:"sync_point_retention"`
+	Filter                *FilterConfig      `json:"filter"`
+	Mounter               *MounterConfig     `json:"mounter"`
+	Sink                  *SinkConfig        `json:"sink"`
+	Consistent            *ConsistentConfig  `json:"consistent"`
+	Transform             *TransformConfig   `json:"transform,omitempty"`
+	InitialSnapshot       bool               `json:"initial_snapshot"`
+	Placement             *PlacementConfig   `json:"placement,omitempty"`
+	Quota                 *QuotaConfig       `json:"quota,omitempty"`
+	DDLApproval           *DDLApprovalConfig `json:"ddl_approval,omitempty"`
 }
 
 // ToInternalReplicaConfig coverts *v2.ReplicaConfig into *config.ReplicaConfig
@@ -115,6 +121,7 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 	res.SyncPointInterval = c.SyncPointInterval
 	res.SyncPointRetention = c.SyncPointRetention
 	res.BDRMode = c.BDRMode
+	res.InitialSnapshot = c.InitialSnapshot
 
 	if c.Filter != nil {
 		var mySQLReplicationRules *filter.MySQLReplicationRules
@@ -163,6 +170,50 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 			Storage:           c.Consistent.Storage,
 		}
 	}
+	if c.Transform != nil {
+		var rules []*config.TransformRule
+		for _, rule := range c.Transform.Rules {
+			var computedColumns []*config.ComputedColumn
+			for _, col := range rule.ComputedColumns {
+				computedColumns = append(computedColumns, &config.ComputedColumn{
+					Name:       col.Name,
+					Expression: col.Expression,
+				})
+			}
+			rules = append(rules, &config.TransformRule{
+				Matcher:         rule.Matcher,
+				RenameColumns:   rule.RenameColumns,
+				MaskColumns:     rule.MaskColumns,
+				HashColumns:     rule.HashColumns,
+				ComputedColumns: computedColumns,
+				ConstantColumns: rule.ConstantColumns,
+			})
+		}
+		res.Transform = &config.TransformConfig{Rules: rules}
+	}
+	if c.Placement != nil {
+		var selectors []*label.Selector
+		for _, selector := range c.Placement.Selectors {
+			selectors = append(selectors, &label.Selector{
+				Key:    label.Key(selector.Label),
+				Target: selector.Target,
+				Op:     label.Op(selector.Op),
+			})
+		}
+		res.Placement = &config.PlacementConfig{Selectors: selectors}
+	}
+	if c.Quota != nil {
+		res.Quota = &config.QuotaConfig{
+			Priority:      c.Quota.Priority,
+			SinkWorkerNum: c.Quota.SinkWorkerNum,
+			RedoWorkerNum: c.Quota.RedoWorkerNum,
+		}
+	}
+	if c.DDLApproval != nil {
+		res.DDLApproval = &config.DDLApprovalConfig{
+			DDLTypes: c.DDLApproval.DDLTypes,
+		}
+	}
 	if c.Sink != nil {
 		var dispatchRules []*config.DispatchRule
 		for _, rule := range c.Sink.DispatchRules {
@@ -170,7 +221,11 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 				Matcher:        rule.Matcher,
 				DispatcherRule: "",
 				PartitionRule:  rule.PartitionRule,
+				Columns:        rule.Columns,
 				TopicRule:      rule.TopicRule,
+				KeyFormat:      rule.KeyFormat,
+				KeyColumns:     rule.KeyColumns,
+				KeyTemplate:    rule.KeyTemplate,
 			})
 		}
 		var columnSelectors []*config.ColumnSelector
@@ -190,6 +245,21 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 			}
 		}
 
+		var deadLetterQueue *config.DeadLetterQueueConfig
+		if c.Sink.DeadLetterQueue != nil {
+			deadLetterQueue = &config.DeadLetterQueueConfig{
+				URI: c.Sink.DeadLetterQueue.URI,
+			}
+		}
+
+		var largeMessageHandle *config.LargeMessageHandleConfig
+		if c.Sink.LargeMessageHandle != nil {
+			largeMessageHandle = &config.LargeMessageHandleConfig{
+				LargeMessageHandleOption: c.Sink.LargeMessageHandle.LargeMessageHandleOption,
+				ClaimCheckStorageURI:     c.Sink.LargeMessageHandle.ClaimCheckStorageURI,
+			}
+		}
+
 		res.Sink = &config.SinkConfig{
 			DispatchRules:            dispatchRules,
 			Protocol:                 c.Sink.Protocol,
@@ -201,6 +271,9 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 			Terminator:               c.Sink.Terminator,
 			DateSeparator:            c.Sink.DateSeparator,
 			EnablePartitionSeparator: c.Sink.EnablePartitionSeparator,
+			DDLTopic:                 c.Sink.DDLTopic,
+			DeadLetterQueue:          deadLetterQueue,
+			LargeMessageHandle:       largeMessageHandle,
 		}
 	}
 	if c.Mounter != nil {
@@ -224,6 +297,7 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 		SyncPointInterval:     cloned.SyncPointInterval,
 		SyncPointRetention:    cloned.SyncPointRetention,
 		BDRMode:               cloned.BDRMode,
+		InitialSnapshot:       cloned.InitialSnapshot,
 	}
 
 	if cloned.Filter != nil {
@@ -273,7 +347,11 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			dispatchRules = append(dispatchRules, &DispatchRule{
 				Matcher:       rule.Matcher,
 				PartitionRule: rule.PartitionRule,
+				Columns:       rule.Columns,
 				TopicRule:     rule.TopicRule,
+				KeyFormat:     rule.KeyFormat,
+				KeyColumns:    rule.KeyColumns,
+				KeyTemplate:   rule.KeyTemplate,
 			})
 		}
 		var columnSelectors []*ColumnSelector
@@ -293,6 +371,21 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			}
 		}
 
+		var deadLetterQueue *DeadLetterQueueConfig
+		if cloned.Sink.DeadLetterQueue != nil {
+			deadLetterQueue = &DeadLetterQueueConfig{
+				URI: cloned.Sink.DeadLetterQueue.URI,
+			}
+		}
+
+		var largeMessageHandle *LargeMessageHandleConfig
+		if cloned.Sink.LargeMessageHandle != nil {
+			largeMessageHandle = &LargeMessageHandleConfig{
+				LargeMessageHandleOption: cloned.Sink.LargeMessageHandle.LargeMessageHandleOption,
+				ClaimCheckStorageURI:     cloned.Sink.LargeMessageHandle.ClaimCheckStorageURI,
+			}
+		}
+
 		res.Sink = &SinkConfig{
 			Protocol:                 cloned.Sink.Protocol,
 			SchemaRegistry:           cloned.Sink.SchemaRegistry,
@@ -304,6 +397,9 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			Terminator:               cloned.Sink.Terminator,
 			DateSeparator:            cloned.Sink.DateSeparator,
 			EnablePartitionSeparator: cloned.Sink.EnablePartitionSeparator,
+			DDLTopic:                 cloned.Sink.DDLTopic,
+			DeadLetterQueue:          deadLetterQueue,
+			LargeMessageHandle:       largeMessageHandle,
 		}
 	}
 	if cloned.Consistent != nil {
@@ -314,6 +410,50 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			Storage:           cloned.Consistent.Storage,
 		}
 	}
+	if cloned.Transform != nil {
+		var rules []*TransformRule
+		for _, rule := range cloned.Transform.Rules {
+			var computedColumns []*ComputedColumn
+			for _, col := range rule.ComputedColumns {
+				computedColumns = append(computedColumns, &ComputedColumn{
+					Name:       col.Name,
+					Expression: col.Expression,
+				})
+			}
+			rules = append(rules, &TransformRule{
+				Matcher:         rule.Matcher,
+				RenameColumns:   rule.RenameColumns,
+				MaskColumns:     rule.MaskColumns,
+				HashColumns:     rule.HashColumns,
+				ComputedColumns: computedColumns,
+				ConstantColumns: rule.ConstantColumns,
+			})
+		}
+		res.Transform = &TransformConfig{Rules: rules}
+	}
+	if cloned.Placement != nil {
+		var selectors []*LabelSelector
+		for _, selector := range cloned.Placement.Selectors {
+			selectors = append(selectors, &LabelSelector{
+				Label:  string(selector.Key),
+				Target: selector.Target,
+				Op:     string(selector.Op),
+			})
+		}
+		res.Placement = &PlacementConfig{Selectors: selectors}
+	}
+	if cloned.Quota != nil {
+		res.Quota = &QuotaConfig{
+			Priority:      cloned.Quota.Priority,
+			SinkWorkerNum: cloned.Quota.SinkWorkerNum,
+			RedoWorkerNum: cloned.Quota.RedoWorkerNum,
+		}
+	}
+	if cloned.DDLApproval != nil {
+		res.DDLApproval = &DDLApprovalConfig{
+			DDLTypes: cloned.DDLApproval.DDLTypes,
+		}
+	}
 	if cloned.Mounter != nil {
 		res.Mounter = &MounterConfig{
 			WorkerNum: cloned.Mounter.WorkerNum,
@@ -439,16 +579,32 @@ type Table struct {
 // SinkConfig represents sink config for a changefeed
 // This is a duplicate of config.SinkConfig
 type SinkConfig struct {
-	Protocol                 string            `json:"protocol"`
-	SchemaRegistry           string            `json:"schema_registry"`
-	CSVConfig                *CSVConfig        `json:"csv"`
-	DispatchRules            []*DispatchRule   `json:"dispatchers,omitempty"`
-	ColumnSelectors          []*ColumnSelector `json:"column_selectors"`
-	TxnAtomicity             string            `json:"transaction_atomicity"`
-	EncoderConcurrency       int               `json:"encoder_concurrency"`
-	Terminator               string            `json:"terminator"`
-	DateSeparator            string            `json:"date_separator"`
-	EnablePartitionSeparator bool              `json:"enable_partition_separator"`
+	Protocol                 string                    `json:"protocol"`
+	SchemaRegistry           string                    `json:"schema_registry"`
+	CSVConfig                *CSVConfig                `json:"csv"`
+	DispatchRules            []*DispatchRule           `json:"dispatchers,omitempty"`
+	ColumnSelectors          []*ColumnSelector         `json:"column_selectors"`
+	TxnAtomicity             string                    `json:"transaction_atomicity"`
+	EncoderConcurrency       int                       `json:"encoder_concurrency"`
+	Terminator               string                    `json:"terminator"`
+	DateSeparator            string                    `json:"date_separator"`
+	EnablePartitionSeparator bool                      `json:"enable_partition_separator"`
+	DDLTopic                 string                    `json:"ddl_topic"`
+	DeadLetterQueue          *DeadLetterQueueConfig    `json:"dead_letter_queue,omitempty"`
+	LargeMessageHandle       *LargeMessageHandleConfig `json:"large_message_handle,omitempty"`
+}
+
+// LargeMessageHandleConfig represents how the MQ sinks handle the large messages
+// This is the same as config.LargeMessageHandleConfig
+type LargeMessageHandleConfig struct {
+	LargeMessageHandleOption string `json:"large_message_handle_option"`
+	ClaimCheckStorageURI     string `json:"claim_check_storage_uri"`
+}
+
+// DeadLetterQueueConfig represents the dead letter queue config of a sink
+// This is the same as config.DeadLetterQueueConfig
+type DeadLetterQueueConfig struct {
+	URI string `json:"uri"`
 }
 
 // CSVConfig denotes the csv config
@@ -465,7 +621,11 @@ type CSVConfig struct {
 type DispatchRule struct {
 	Matcher       []string `json:"matcher,omitempty"`
 	PartitionRule string   `json:"partition"`
+	Columns       []string `json:"columns,omitempty"`
 	TopicRule     string   `json:"topic"`
+	KeyFormat     string   `json:"key_format,omitempty"`
+	KeyColumns    []string `json:"key_columns,omitempty"`
+	KeyTemplate   string   `json:"key_template,omitempty"`
 }
 
 // ColumnSelector represents a column selector for a table.
@@ -484,6 +644,59 @@ type ConsistentConfig struct {
 	Storage           string `json:"storage"`
 }
 
+// TransformConfig represents the row-level transformation config of a changefeed
+// This is a duplicate of config.TransformConfig
+type TransformConfig struct {
+	Rules []*TransformRule `json:"rules"`
+}
+
+// TransformRule is a set of transformations applied to the matched tables
+// This is a duplicate of config.TransformRule
+type TransformRule struct {
+	Matcher         []string          `json:"matcher"`
+	RenameColumns   map[string]string `json:"rename_columns,omitempty"`
+	MaskColumns     []string          `json:"mask_columns,omitempty"`
+	HashColumns     []string          `json:"hash_columns,omitempty"`
+	ComputedColumns []*ComputedColumn `json:"computed_columns,omitempty"`
+	ConstantColumns map[string]string `json:"constant_columns,omitempty"`
+}
+
+// ComputedColumn is a column calculated by a TiDB expression
+// This is a duplicate of config.ComputedColumn
+type ComputedColumn struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// PlacementConfig represents the placement rule of a changefeed
+// This is a duplicate of config.PlacementConfig
+type PlacementConfig struct {
+	Selectors []*LabelSelector `json:"selectors"`
+}
+
+// LabelSelector is a selector on the labels of captures
+// This is a duplicate of label.Selector
+type LabelSelector struct {
+	Label  string `json:"label"`
+	Target string `json:"target"`
+	Op     string `json:"op"`
+}
+
+// QuotaConfig represents the resource quotas of a changefeed
+// This is a duplicate of config.QuotaConfig
+type QuotaConfig struct {
+	Priority      string `json:"priority"`
+	SinkWorkerNum int    `json:"sink_worker_num"`
+	RedoWorkerNum int    `json:"redo_worker_num"`
+}
+
+// DDLApprovalConfig represents the DDLs that must be approved before they
+// are replicated
+// This is a duplicate of config.DDLApprovalConfig
+type DDLApprovalConfig struct {
+	DDLTypes []string `json:"ddl_types"`
+}
+
 // EtcdData contains key/value pair of etcd data
 type EtcdData struct {
 	Key   string `json:"key,omitempty"`
@@ -515,6 +728,80 @@ type ChangeFeedInfo struct {
 	State          model.FeedState    `json:"state,omitempty"`
 	Error          *RunningError      `json:"error,omitempty"`
 	CreatorVersion string             `json:"creator_version,omitempty"`
+
+	ResolvedTs     uint64              `json:"resolved_ts"`
+	CheckpointTs   uint64              `json:"checkpoint_ts"`
+	CheckpointTime model.JSONTime      `json:"checkpoint_time"`
+	TaskStatus     []CaptureTaskStatus `json:"task_status,omitempty"`
+
+	InitialSnapshot *InitialSnapshotStatus `json:"initial_snapshot,omitempty"`
+	PendingDDL      *PendingDDL            `json:"pending_ddl,omitempty"`
+}
+
+// InitialSnapshotStatus is the progress of the initial snapshot of a changefeed.
+type InitialSnapshotStatus struct {
+	SnapshotTs uint64 `json:"snapshot_ts"`
+	// FinishedTables is the number of the tables whose snapshot rows
+	// are replicated.
+	FinishedTables int `json:"finished_tables"`
+	// TotalTables is the number of the replicated tables, zero if unknown.
+	TotalTables int `json:"total_tables,omitempty"`
+	// Finished is true if the snapshot rows of all the tables are replicated.
+	Finished bool `json:"finished"`
+}
+
+// NewInitialSnapshotStatus returns the progress of the initial snapshot, or
+// nil if the initial snapshot is disabled.
+func NewInitialSnapshotStatus(
+	snapshotTs, checkpointTs uint64, finishedTables, totalTables int,
+) *InitialSnapshotStatus {
+	if snapshotTs == 0 {
+		return nil
+	}
+	status := &InitialSnapshotStatus{
+		SnapshotTs:     snapshotTs,
+		FinishedTables: finishedTables,
+		TotalTables:    totalTables,
+		Finished:       checkpointTs >= snapshotTs,
+	}
+	// the finished tables are not recorded after all the tables finish.
+	if status.Finished {
+		status.FinishedTables = totalTables
+	}
+	return status
+}
+
+// PendingDDL is a DDL that holds the changefeed until it is resolved
+// This is a duplicate of model.PendingDDL
+type PendingDDL struct {
+	JobID    int64  `json:"job_id"`
+	CommitTs uint64 `json:"commit_ts"`
+	Type     string `json:"type"`
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	Query    string `json:"query"`
+	// Action is empty until the DDL is resolved.
+	Action string `json:"action,omitempty"`
+}
+
+// ToAPIPendingDDL converts model.PendingDDL to PendingDDL, it returns nil
+// if there is no pending DDL.
+func ToAPIPendingDDL(pending *model.PendingDDL) *PendingDDL {
+	if pending == nil {
+		return nil
+	}
+	res := &PendingDDL{
+		JobID:    pending.JobID,
+		CommitTs: pending.CommitTs,
+		Type:     pending.Type,
+		Schema:   pending.Schema,
+		Table:    pending.Table,
+		Query:    pending.Query,
+	}
+	if pending.Decision != nil {
+		res.Action = string(pending.Decision.Action)
+	}
+	return res
 }
 
 // RunningError represents some running error from cdc components, such as processor.
@@ -568,3 +855,91 @@ type UpstreamConfig struct {
 	ID uint64 `json:"id"`
 	PDConfig
 }
+
+// ChangefeedCommonInfo holds some common usage information of a changefeed
+type ChangefeedCommonInfo struct {
+	UpstreamID     uint64          `json:"upstream_id"`
+	Namespace      string          `json:"namespace"`
+	ID             string          `json:"id"`
+	FeedState      model.FeedState `json:"state"`
+	CheckpointTSO  uint64          `json:"checkpoint_tso"`
+	CheckpointTime model.JSONTime  `json:"checkpoint_time"`
+	RunningError   *RunningError   `json:"error"`
+}
+
+// ChangefeedStatus holds the runtime status of a changefeed
+type ChangefeedStatus struct {
+	State        string        `json:"state,omitempty"`
+	ResolvedTs   uint64        `json:"resolved_ts"`
+	CheckpointTs uint64        `json:"checkpoint_ts"`
+	LastError    *RunningError `json:"last_error,omitempty"`
+	PendingDDL   *PendingDDL   `json:"pending_ddl,omitempty"`
+}
+
+// CaptureTaskStatus holds TaskStatus of a capture
+type CaptureTaskStatus struct {
+	CaptureID string `json:"capture_id"`
+	// Table list, containing tables that processor should process
+	Tables []int64 `json:"table_ids,omitempty"`
+}
+
+// MoveTableReq is the request to move a table to the target capture
+type MoveTableReq struct {
+	CaptureID string `json:"capture_id"`
+	TableID   int64  `json:"table_id"`
+}
+
+// UpdateTablesReq is the request to add or remove tables of a running
+// changefeed. The tables are given as filter rules, such as "db.tbl" or "db.*".
+type UpdateTablesReq struct {
+	AddTables    []string `json:"add_tables,omitempty"`
+	RemoveTables []string `json:"remove_tables,omitempty"`
+	// StartTs is the ts from which the added tables are replicated and the
+	// removed tables are no longer replicated. It must not be less than the
+	// checkpoint ts of the changefeed. The resolved ts of the changefeed is
+	// used if it is zero.
+	StartTs uint64 `json:"start_ts"`
+}
+
+// ResolveDDLReq is the request to resolve the pending DDL of a changefeed.
+type ResolveDDLReq struct {
+	// JobID is the ID of the pending DDL job.
+	JobID int64 `json:"job_id"`
+	// Action is one of approve, skip and replace.
+	Action string `json:"action"`
+	// Query is replicated instead of the DDL if the action is replace.
+	Query string `json:"query,omitempty"`
+}
+
+// ProcessorCommonInfo holds the common info of a processor
+type ProcessorCommonInfo struct {
+	Namespace    string `json:"namespace"`
+	ChangeFeedID string `json:"changefeed_id"`
+	CaptureID    string `json:"capture_id"`
+}
+
+// ProcessorDetail holds the detail info of a processor
+type ProcessorDetail struct {
+	// All table ids that this processor are replicating.
+	Tables []int64 `json:"table_ids"`
+}
+
+// Capture holds common information of a capture in cdc
+type Capture struct {
+	ID            string            `json:"id"`
+	IsOwner       bool              `json:"is_owner"`
+	AdvertiseAddr string            `json:"address"`
+	ClusterID     string            `json:"cluster_id"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+// DrainCaptureResp is response for manual `DrainCapture`
+type DrainCaptureResp struct {
+	CurrentTableCount int `json:"current_table_count"`
+}
+
+// ListResponse is the response for all List APIs
+type ListResponse[T any] struct {
+	Total int `json:"total"`
+	Items []T `json:"items"`
+}
@@ -21,6 +21,7 @@ import (
 	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	filter "github.com/pingcap/tidb/util/table-filter"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/label"
 	"github.com/stretchr/testify/require"
 )
 
@@ -81,6 +82,23 @@ func TestToAPIReplicaConfig(t *testing.T) {
 		}},
 	}
 	cfg.Mounter = &config.MounterConfig{WorkerNum: 11}
+	cfg.Transform = &config.TransformConfig{Rules: []*config.TransformRule{{
+		Matcher:         []string{"test.t1"},
+		RenameColumns:   map[string]string{"a": "b"},
+		MaskColumns:     []string{"email"},
+		HashColumns:     []string{"phone"},
+		ComputedColumns: []*config.ComputedColumn{{Name: "c", Expression: "a + 1"}},
+		ConstantColumns: map[string]string{"source": "tidb"},
+	}}}
+	cfg.Placement = &config.PlacementConfig{Selectors: []*label.Selector{{
+		Key: "zone", Target: "west", Op: label.OpEq,
+	}}}
+	cfg.Quota = &config.QuotaConfig{
+		Priority: config.ChangefeedPriorityHigh, SinkWorkerNum: 4, RedoWorkerNum: 2,
+	}
+	cfg.DDLApproval = &config.DDLApprovalConfig{
+		DDLTypes: []string{"drop table", "truncate table"},
+	}
 	cfg2 := ToAPIReplicaConfig(cfg).ToInternalReplicaConfig()
 	require.Equal(t, "", cfg2.Sink.DispatchRules[0].DispatcherRule)
 	cfg.Sink.DispatchRules[0].DispatcherRule = ""
@@ -159,3 +177,20 @@ func TestEventFilterRuleConvert(t *testing.T) {
 		require.Equal(t, c.inRule, c.apiRule.ToInternalEventFilterRule())
 	}
 }
+
+func TestNewInitialSnapshotStatus(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewInitialSnapshotStatus(0, 100, 0, 3))
+
+	status := NewInitialSnapshotStatus(100, 99, 2, 3)
+	require.Equal(t, &InitialSnapshotStatus{
+		SnapshotTs:     100,
+		FinishedTables: 2,
+		TotalTables:    3,
+	}, status)
+
+	status = NewInitialSnapshotStatus(100, 100, 0, 3)
+	require.True(t, status.Finished)
+	require.Equal(t, 3, status.FinishedTables)
+}
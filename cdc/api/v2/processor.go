@@ -0,0 +1,109 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// getProcessor gets the detailed info of a processor.
+func (h *OpenAPIV2) getProcessor(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	captureID := c.Param(apiOpVarCaptureID)
+	if err := model.ValidateChangefeedID(captureID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid capture_id: %s",
+			captureID))
+		return
+	}
+
+	info, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if info.State != model.StateNormal {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"changefeed in abnormal state: %s, "+
+				"can't get processors of an abnormal changefeed", string(info.State)))
+		return
+	}
+	// check if this captureID exist
+	procInfos, err := h.capture.StatusProvider().GetProcessors(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var found bool
+	for _, info := range procInfos {
+		if info.CaptureID == captureID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		_ = c.Error(cerror.ErrCaptureNotExist.GenWithStackByArgs(captureID))
+		return
+	}
+
+	statuses, err := h.capture.StatusProvider().GetAllTaskStatuses(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// Note: for the case that no tables are attached to a newly created changefeed,
+	//       we just do not report an error.
+	detail := &ProcessorDetail{Tables: make([]int64, 0)}
+	if status, ok := statuses[captureID]; ok {
+		for tableID := range status.Tables {
+			detail.Tables = append(detail.Tables, tableID)
+		}
+		sort.Slice(detail.Tables, func(i, j int) bool {
+			return detail.Tables[i] < detail.Tables[j]
+		})
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+// listProcessors lists all processors in the TiCDC cluster.
+func (h *OpenAPIV2) listProcessors(c *gin.Context) {
+	ctx := c.Request.Context()
+	infos, err := h.capture.StatusProvider().GetProcessors(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	processors := make([]ProcessorCommonInfo, 0, len(infos))
+	for _, info := range infos {
+		processors = append(processors, ProcessorCommonInfo{
+			Namespace:    info.CfID.Namespace,
+			ChangeFeedID: info.CfID.ID,
+			CaptureID:    info.CaptureID,
+		})
+	}
+	c.JSON(http.StatusOK, &ListResponse[ProcessorCommonInfo]{
+		Total: len(processors),
+		Items: processors,
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProcessor(t *testing.T) {
+	t.Parallel()
+
+	get := testCase{url: "/api/v2/processors/%s/%s", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	statusProvider := &mockStatusProvider{
+		changefeedInfo: &model.ChangeFeedInfo{State: model.StateStopped},
+		processors: []*model.ProcInfoSnap{
+			{CfID: changeFeedID, CaptureID: "capture-1"},
+			{CfID: changeFeedID, CaptureID: "capture-2"},
+		},
+		taskStatuses: map[model.CaptureID]*model.TaskStatus{
+			"capture-1": {Tables: map[model.TableID]*model.TableReplicaInfo{2: {}, 1: {}}},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+
+	request := func(changefeedID, captureID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(),
+			get.method, fmt.Sprintf(get.url, changefeedID, captureID), nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	requireError := func(w *httptest.ResponseRecorder, code string) {
+		respErr := model.HTTPError{}
+		err := json.NewDecoder(w.Body).Decode(&respErr)
+		require.Nil(t, err)
+		require.Contains(t, respErr.Code, code)
+	}
+
+	// case 1: invalid capture id
+	w := request(changeFeedID.ID, "@^Invalid")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	requireError(w, "ErrAPIInvalidParam")
+
+	// case 2: abnormal changefeed
+	w = request(changeFeedID.ID, "capture-1")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	requireError(w, "ErrAPIInvalidParam")
+
+	// case 3: capture not exists
+	statusProvider.changefeedInfo.State = model.StateNormal
+	w = request(changeFeedID.ID, "capture-3")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	requireError(w, "ErrCaptureNotExist")
+
+	// case 4: success
+	w = request(changeFeedID.ID, "capture-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	detail := ProcessorDetail{}
+	err := json.NewDecoder(w.Body).Decode(&detail)
+	require.Nil(t, err)
+	require.Equal(t, []int64{1, 2}, detail.Tables)
+
+	// case 5: no tables are attached to the processor
+	w = request(changeFeedID.ID, "capture-2")
+	require.Equal(t, http.StatusOK, w.Code)
+	detail = ProcessorDetail{}
+	err = json.NewDecoder(w.Body).Decode(&detail)
+	require.Nil(t, err)
+	require.Empty(t, detail.Tables)
+}
+
+func TestListProcessors(t *testing.T) {
+	t.Parallel()
+
+	list := testCase{url: "/api/v2/processors", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	apiV2 := NewOpenAPIV2ForTest(cp, APIV2HelpersImpl{})
+	router := newRouter(apiV2)
+	statusProvider := &mockStatusProvider{
+		processors: []*model.ProcInfoSnap{
+			{CfID: changeFeedID, CaptureID: "capture-1"},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), list.method, list.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ListResponse[ProcessorCommonInfo]{}
+	err := json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, 1, resp.Total)
+	require.Equal(t, ProcessorCommonInfo{
+		Namespace:    changeFeedID.Namespace,
+		ChangeFeedID: changeFeedID.ID,
+		CaptureID:    "capture-1",
+	}, resp.Items[0])
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/processor"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/factory"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/db/system"
@@ -104,6 +105,10 @@ type captureImpl struct {
 	sorterSystem      *ssystem.System
 	sortEngineFactory *factory.SortEngineFactory
 
+	// memQuota is the memory quota shared by all changefeeds on the capture,
+	// nil means it is not limited.
+	memQuota *memquota.CaptureMemQuota
+
 	// MessageServer is the receiver of the messages from the other nodes.
 	// It should be recreated each time the capture is restarted.
 	MessageServer *p2p.MessageServer
@@ -137,6 +142,7 @@ func NewCapture(pdEndpoints []string,
 	tableActorSystem *system.System,
 	sortEngineMangerFactory *factory.SortEngineFactory,
 	sorterSystem *ssystem.System,
+	memQuota *memquota.CaptureMemQuota,
 ) Capture {
 	return &captureImpl{
 		config:              config.GetGlobalServerConfig(),
@@ -154,6 +160,7 @@ func NewCapture(pdEndpoints []string,
 		useSortEngine:     sortEngineMangerFactory != nil,
 		sortEngineFactory: sortEngineMangerFactory,
 		sorterSystem:      sorterSystem,
+		memQuota:          memQuota,
 	}
 }
 
@@ -228,6 +235,7 @@ func (c *captureImpl) reset(ctx context.Context) error {
 		ID:            uuid.New().String(),
 		AdvertiseAddr: c.config.AdvertiseAddr,
 		Version:       version.ReleaseVersion,
+		Labels:        c.config.Labels,
 	}
 
 	if c.upstreamManager != nil {
@@ -334,6 +342,7 @@ func (c *captureImpl) run(stdCtx context.Context) error {
 		MessageRouter:     c.MessageRouter,
 		SorterSystem:      c.sorterSystem,
 		SortEngineFactory: c.sortEngineFactory,
+		CaptureMemQuota:   c.memQuota,
 	})
 
 	g.Go(func() error {
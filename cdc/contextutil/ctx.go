@@ -29,6 +29,7 @@ const (
 	ctxKeyTableID      = ctxKey("tableID")
 	ctxKeyCaptureAddr  = ctxKey("captureAddr")
 	ctxKeyChangefeedID = ctxKey("changefeedID")
+	ctxKeyUpstreamID   = ctxKey("upstreamID")
 	ctxKeyIsOwner      = ctxKey("isOwner")
 	ctxKeyTimezone     = ctxKey("timezone")
 	ctxKeyKVStorage    = ctxKey("kvStorage")
@@ -123,6 +124,21 @@ func PutChangefeedIDInCtx(ctx context.Context, changefeedID model.ChangeFeedID)
 	return context.WithValue(ctx, ctxKeyChangefeedID, changefeedID)
 }
 
+// UpstreamIDFromCtx returns the upstream ID stored in the specified context.
+// It returns 0 if there's no upstream ID found.
+func UpstreamIDFromCtx(ctx context.Context) uint64 {
+	upstreamID, ok := ctx.Value(ctxKeyUpstreamID).(uint64)
+	if !ok {
+		return 0
+	}
+	return upstreamID
+}
+
+// PutUpstreamIDInCtx returns a new child context with the specified upstream ID stored.
+func PutUpstreamIDInCtx(ctx context.Context, upstreamID uint64) context.Context {
+	return context.WithValue(ctx, ctxKeyUpstreamID, upstreamID)
+}
+
 // RoleFromCtx returns a role stored in the specified context.
 // It returns RoleUnknown if there's no valid role found
 func RoleFromCtx(ctx context.Context) util.Role {
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	pfilter "github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/transform"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
@@ -73,6 +74,7 @@ type mounter struct {
 	enableOldValue               bool
 	changefeedID                 model.ChangeFeedID
 	filter                       pfilter.Filter
+	transformer                  transform.Transformer
 	metricTotalRows              prometheus.Gauge
 	metricIgnoredDMLEventCounter prometheus.Counter
 }
@@ -82,6 +84,7 @@ func NewMounter(schemaStorage SchemaStorage,
 	changefeedID model.ChangeFeedID,
 	tz *time.Location,
 	filter pfilter.Filter,
+	transformer transform.Transformer,
 	enableOldValue bool,
 ) Mounter {
 	return &mounter{
@@ -89,6 +92,7 @@ func NewMounter(schemaStorage SchemaStorage,
 		changefeedID:   changefeedID,
 		enableOldValue: enableOldValue,
 		filter:         filter,
+		transformer:    transformer,
 		metricTotalRows: totalRowsCountGauge.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 		metricIgnoredDMLEventCounter: ignoredDMLEventCounter.
@@ -177,6 +181,11 @@ func (m *mounter) unmarshalAndMountRowChanged(ctx context.Context, raw *model.Ra
 				m.metricIgnoredDMLEventCounter.Inc()
 				return nil, nil
 			}
+			// The row is transformed after being filtered, so that the
+			// filter rules always work on the original columns.
+			if err := m.transformer.TransformDMLEvent(row, rawRow, tableInfo); err != nil {
+				return nil, err
+			}
 			return row, nil
 		}
 		return nil, nil
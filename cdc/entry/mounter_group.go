@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/transform"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -36,6 +37,7 @@ type mounterGroup struct {
 	inputCh        []chan *model.PolymorphicEvent
 	tz             *time.Location
 	filter         filter.Filter
+	transformer    transform.Transformer
 	enableOldValue bool
 
 	workerNum int
@@ -56,6 +58,7 @@ func NewMounterGroup(
 	workerNum int,
 	enableOldValue bool,
 	filter filter.Filter,
+	transformer transform.Transformer,
 	tz *time.Location,
 	changefeedID model.ChangeFeedID,
 ) *mounterGroup {
@@ -71,6 +74,7 @@ func NewMounterGroup(
 		inputCh:        inputCh,
 		enableOldValue: enableOldValue,
 		filter:         filter,
+		transformer:    transformer,
 		tz:             tz,
 
 		workerNum: workerNum,
@@ -94,7 +98,8 @@ func (m *mounterGroup) Run(ctx context.Context) error {
 }
 
 func (m *mounterGroup) runWorker(ctx context.Context, index int) error {
-	mounter := NewMounter(m.schemaStorage, m.changefeedID, m.tz, m.filter, m.enableOldValue)
+	mounter := NewMounter(m.schemaStorage, m.changefeedID, m.tz,
+		m.filter, m.transformer, m.enableOldValue)
 	rawCh := m.inputCh[index]
 	metrics := mounterGroupInputChanSizeGauge.
 		WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID, strconv.Itoa(index))
@@ -40,6 +40,7 @@ import (
 	pfilter "github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/regionspan"
 	"github.com/pingcap/tiflow/pkg/sqlmodel"
+	"github.com/pingcap/tiflow/pkg/transform"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
@@ -303,9 +304,11 @@ func testMounterDisableOldValue(t *testing.T, tc struct {
 	config := config.GetDefaultReplicaConfig()
 	filter, err := pfilter.NewFilter(config, "")
 	require.Nil(t, err)
+	transformer, err := transform.NewTransformer(config, "")
+	require.Nil(t, err)
 	mounter := NewMounter(scheamStorage,
 		model.DefaultChangeFeedID("c1"),
-		time.UTC, filter, false).(*mounter)
+		time.UTC, filter, transformer, false).(*mounter)
 	mounter.tz = time.Local
 	ctx := context.Background()
 
@@ -996,8 +999,16 @@ func TestDecodeEventIgnoreRow(t *testing.T) {
 
 	cfg := config.GetDefaultReplicaConfig()
 	cfg.Filter.Rules = []string{"test.student", "test.computer"}
+	cfg.Transform = &config.TransformConfig{Rules: []*config.TransformRule{{
+		Matcher:         []string{"test.computer"},
+		RenameColumns:   map[string]string{"brand": "maker"},
+		ComputedColumns: []*config.ComputedColumn{{Name: "discount_price", Expression: "price - 1"}},
+		ConstantColumns: map[string]string{"source": "tidb"},
+	}}}
 	filter, err := pfilter.NewFilter(cfg, "")
 	require.Nil(t, err)
+	transformer, err := transform.NewTransformer(cfg, "")
+	require.Nil(t, err)
 	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
 	require.Nil(t, err)
 	schemaStorage, err := NewSchemaStorage(helper.GetCurrentMeta(),
@@ -1012,7 +1023,7 @@ func TestDecodeEventIgnoreRow(t *testing.T) {
 
 	ts := schemaStorage.GetLastSnapshot().CurrentTs()
 	schemaStorage.AdvanceResolvedTs(ver.Ver)
-	mounter := NewMounter(schemaStorage, cfID, time.Local, filter, true).(*mounter)
+	mounter := NewMounter(schemaStorage, cfID, time.Local, filter, transformer, true).(*mounter)
 
 	type testCase struct {
 		schema  string
@@ -1075,6 +1086,15 @@ func TestDecodeEventIgnoreRow(t *testing.T) {
 			// Now we only allow filter dml event by table, so we only check row's table.
 			require.NotContains(t, ignoredTables, row.Table.Table)
 			require.Contains(t, tables, row.Table.Table)
+			if row.Table.Table == "computer" {
+				require.Len(t, row.Columns, 5)
+				require.Len(t, row.ColInfos, 5)
+				require.Equal(t, "maker", row.Columns[1].Name)
+				require.Equal(t, "discount_price", row.Columns[3].Name)
+				require.EqualValues(t, 19998, row.Columns[3].Value)
+				require.Equal(t, "source", row.Columns[4].Name)
+				require.Equal(t, []byte("tidb"), row.Columns[4].Value)
+			}
 		})
 		return rows
 	}
@@ -202,6 +202,9 @@ type CDCKVClient interface {
 
 	// RegionCount returns the number of captured regions.
 	RegionCount() uint64
+	// RegionWriteStats returns the bytes written to each captured region,
+	// sorted by region start key.
+	RegionWriteStats() []RegionWriteStat
 	// ResolvedTs returns the current ingress resolved ts.
 	ResolvedTs() model.Ts
 	// CommitTs returns the current ingress commit ts.
@@ -232,6 +235,7 @@ type CDCClient struct {
 		sync.Mutex
 		counts *list.List
 	}
+	regionWriteStats  regionWriteStats
 	ingressCommitTs   model.Ts
 	ingressResolvedTs model.Ts
 	// filterLoop is used in BDR mode, when it is true, tikv cdc component
@@ -349,6 +353,12 @@ func (c *CDCClient) RegionCount() uint64 {
 	return totalCount
 }
 
+// RegionWriteStats returns the bytes written to each captured region,
+// sorted by region start key.
+func (c *CDCClient) RegionWriteStats() []RegionWriteStat {
+	return c.regionWriteStats.snapshot()
+}
+
 // ResolvedTs returns the current ingress resolved ts.
 func (c *CDCClient) ResolvedTs() model.Ts {
 	return atomic.LoadUint64(&c.ingressResolvedTs)
@@ -204,6 +204,7 @@ func (w *regionWorker) handleSingleRegionError(err error, state *regionFeedState
 	// We need to ensure when the error is handled, `isStopped` must be set. So set it before sending the error.
 	state.markStopped()
 	w.delRegionState(regionID)
+	w.session.client.regionWriteStats.remove(regionID)
 	failpoint.Inject("kvClientSingleFeedProcessDelay", nil)
 
 	failpoint.Inject("kvClientErrUnreachable", func() {
@@ -608,6 +609,14 @@ func (w *regionWorker) handleEventEntry(
 	state *regionFeedState,
 ) error {
 	regionID, regionSpan, startTime, storeAddr := state.getRegionMeta()
+	// Bytes of the row events sent by this batch, they are accounted to
+	// the region once the batch is handled.
+	var writtenBytes uint64
+	defer func() {
+		if writtenBytes > 0 {
+			w.session.client.regionWriteStats.add(regionID, regionSpan, writtenBytes)
+		}
+	}()
 	for _, entry := range x.Entries.GetEntries() {
 		// if a region with kv range [a, z), and we only want the get [b, c) from this region,
 		// tikv will return all key events in the region, although specified [b, c) int the request.
@@ -642,6 +651,7 @@ func (w *regionWorker) handleEventEntry(
 				select {
 				case w.outputCh <- revent:
 					w.metrics.metricSendEventCommitCounter.Inc()
+					writtenBytes += uint64(revent.Val.ApproximateDataSize())
 				case <-ctx.Done():
 					return errors.Trace(ctx.Err())
 				}
@@ -666,6 +676,7 @@ func (w *regionWorker) handleEventEntry(
 			select {
 			case w.outputCh <- revent:
 				w.metrics.metricSendEventCommittedCounter.Inc()
+				writtenBytes += uint64(revent.Val.ApproximateDataSize())
 			case <-ctx.Done():
 				return errors.Trace(ctx.Err())
 			}
@@ -703,6 +714,7 @@ func (w *regionWorker) handleEventEntry(
 			select {
 			case w.outputCh <- revent:
 				w.metrics.metricSendEventCommitCounter.Inc()
+				writtenBytes += uint64(revent.Val.ApproximateDataSize())
 			case <-ctx.Done():
 				return errors.Trace(ctx.Err())
 			}
@@ -788,6 +800,7 @@ func (w *regionWorker) evictAllRegions() {
 			}
 			regionState.markStopped()
 			w.delRegionState(regionID)
+			w.session.client.regionWriteStats.remove(regionID)
 			regionState.setRegionInfoResolvedTs()
 			revokeToken := !regionState.isInitialized()
 			// since the context used in region worker will be cancelled after
@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pingcap/tiflow/pkg/regionspan"
+)
+
+// RegionWriteStat is the number of bytes written to a captured region since
+// the region is captured.
+type RegionWriteStat struct {
+	RegionID uint64
+	// Span is the captured part of the region, in comparable format.
+	Span         regionspan.ComparableSpan
+	WrittenBytes uint64
+}
+
+// regionWriteStats accumulates written bytes of captured regions, the zero
+// value is ready to use. A region is removed once it stops being captured, so the written bytes of
+// a region restart from zero if it is captured again.
+type regionWriteStats struct {
+	sync.Mutex
+	stats map[uint64]*RegionWriteStat
+}
+
+func (s *regionWriteStats) add(
+	regionID uint64, span regionspan.ComparableSpan, writtenBytes uint64,
+) {
+	s.Lock()
+	defer s.Unlock()
+	if s.stats == nil {
+		s.stats = make(map[uint64]*RegionWriteStat)
+	}
+	stat, ok := s.stats[regionID]
+	if !ok {
+		stat = &RegionWriteStat{RegionID: regionID}
+		s.stats[regionID] = stat
+	}
+	stat.Span = span
+	stat.WrittenBytes += writtenBytes
+}
+
+func (s *regionWriteStats) remove(regionID uint64) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.stats, regionID)
+}
+
+// snapshot returns the stats sorted by span start key.
+func (s *regionWriteStats) snapshot() []RegionWriteStat {
+	s.Lock()
+	stats := make([]RegionWriteStat, 0, len(s.stats))
+	for _, stat := range s.stats {
+		stats = append(stats, *stat)
+	}
+	s.Unlock()
+	sort.Slice(stats, func(i, j int) bool {
+		return regionspan.StartCompare(stats[i].Span.Start, stats[j].Span.Start) < 0
+	})
+	return stats
+}
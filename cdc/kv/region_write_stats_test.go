@@ -0,0 +1,44 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/regionspan"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionWriteStats(t *testing.T) {
+	t.Parallel()
+
+	stats := &regionWriteStats{}
+	span1 := regionspan.ComparableSpan{Start: []byte("a"), End: []byte("b")}
+	span2 := regionspan.ComparableSpan{Start: []byte("b"), End: []byte("c")}
+	stats.add(2, span2, 10)
+	stats.add(1, span1, 5)
+	stats.add(2, span2, 20)
+	require.Equal(t, []RegionWriteStat{
+		{RegionID: 1, Span: span1, WrittenBytes: 5},
+		{RegionID: 2, Span: span2, WrittenBytes: 30},
+	}, stats.snapshot())
+
+	// Written bytes restart from zero once the region is captured again.
+	stats.remove(2)
+	require.Equal(t, []RegionWriteStat{
+		{RegionID: 1, Span: span1, WrittenBytes: 5},
+	}, stats.snapshot())
+	stats.add(2, span2, 1)
+	require.Equal(t, uint64(1), stats.snapshot()[1].WrittenBytes)
+}
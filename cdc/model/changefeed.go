@@ -146,6 +146,67 @@ type ChangeFeedInfo struct {
 	Error  *RunningError         `json:"error"`
 
 	CreatorVersion string `json:"creator-version"`
+	// InitialSnapshotTs is the ts of the initial snapshot of the tables.
+	// It is zero if the initial snapshot is disabled.
+	InitialSnapshotTs uint64 `json:"initial-snapshot-ts,omitempty"`
+	// InitialSnapshotFinishedTables are the tables whose snapshot rows have
+	// been replicated before the checkpoint of the changefeed reaches the
+	// snapshot ts. They don't scan the snapshot again if they are restarted.
+	InitialSnapshotFinishedTables []TableID `json:"initial-snapshot-finished-tables,omitempty"`
+	// PendingFilterUpdate is the update of the filter rules that is waiting
+	// for the changefeed to reach its apply ts.
+	PendingFilterUpdate *FilterUpdate `json:"pending-filter-update,omitempty"`
+	// PendingDDL is the DDL that holds the changefeed until an operator
+	// decides how to replicate it.
+	PendingDDL *PendingDDL `json:"pending-ddl,omitempty"`
+}
+
+// FilterUpdate changes the filter rules of a running changefeed. The tables
+// added by the new rules are replicated from ApplyTs, and the tables removed
+// by the new rules are no longer replicated after ApplyTs.
+type FilterUpdate struct {
+	Rules   []string `json:"rules"`
+	ApplyTs uint64   `json:"apply-ts"`
+}
+
+// DDLAction is the action taken on a pending DDL.
+type DDLAction string
+
+const (
+	// DDLActionApprove replicates the DDL as it is.
+	DDLActionApprove DDLAction = "approve"
+	// DDLActionSkip does not replicate the DDL. The schema of the changefeed
+	// is still changed by the DDL, so the downstream must be compatible with
+	// the changes after it.
+	DDLActionSkip DDLAction = "skip"
+	// DDLActionReplace replicates another query instead of the DDL.
+	DDLActionReplace DDLAction = "replace"
+)
+
+// PendingDDL is a DDL job that needs approval. The changefeed is held at the
+// commit ts of the job until the job is resolved by a DDLDecision.
+type PendingDDL struct {
+	JobID    int64  `json:"job-id"`
+	CommitTs uint64 `json:"commit-ts"`
+	Type     string `json:"type"`
+	Schema   string `json:"schema"`
+	Table    string `json:"table"`
+	Query    string `json:"query"`
+	// MultiTable is true if the job changes several tables, e.g. renaming
+	// multiple tables, such a job can't be replaced by a single query.
+	MultiTable bool `json:"multi-table,omitempty"`
+	// Decision is nil until an operator resolves the DDL.
+	Decision *DDLDecision `json:"decision,omitempty"`
+}
+
+// DDLDecision resolves a pending DDL.
+type DDLDecision struct {
+	// JobID must be the ID of the pending DDL job, so that a decision is
+	// never applied to another DDL.
+	JobID  int64     `json:"job-id"`
+	Action DDLAction `json:"action"`
+	// Query is replicated instead of the DDL if the action is replace.
+	Query string `json:"query,omitempty"`
 }
 
 const changeFeedIDMaxLen = 128
@@ -228,6 +289,35 @@ func (info *ChangeFeedInfo) GetTargetTs() uint64 {
 	return uint64(math.MaxUint64)
 }
 
+// PrepareInitialSnapshot makes the changefeed scan the existing data of the
+// tables at StartTs before replicating the incremental changes. The snapshot
+// rows are emitted as the inserts committed at StartTs, so the changefeed
+// starts from StartTs-1 to make them replicated.
+func (info *ChangeFeedInfo) PrepareInitialSnapshot() {
+	if info.Config == nil || !info.Config.InitialSnapshot || info.StartTs == 0 {
+		return
+	}
+	info.InitialSnapshotTs = info.StartTs
+	info.StartTs--
+}
+
+// GetInitialSnapshotTs returns the ts of the initial snapshot of a table
+// starting from startTs, or zero if the table does not need to scan the
+// snapshot. Only the tables replicated since the creation of the changefeed
+// need the snapshot, and the snapshot of a table is finished once its
+// checkpoint reaches the snapshot ts.
+func (info *ChangeFeedInfo) GetInitialSnapshotTs(tableID TableID, startTs uint64) uint64 {
+	if info == nil || info.InitialSnapshotTs <= startTs {
+		return 0
+	}
+	for _, id := range info.InitialSnapshotFinishedTables {
+		if id == tableID {
+			return 0
+		}
+	}
+	return info.InitialSnapshotTs
+}
+
 // Marshal returns the json marshal format of a ChangeFeedInfo
 func (info *ChangeFeedInfo) Marshal() (string, error) {
 	data, err := json.Marshal(info)
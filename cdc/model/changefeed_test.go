@@ -963,3 +963,31 @@ func TestGetTs(t *testing.T) {
 	status := &ChangeFeedStatus{CheckpointTs: checkpointTs}
 	require.Equal(t, info.GetCheckpointTs(status), checkpointTs)
 }
+
+func TestInitialSnapshot(t *testing.T) {
+	t.Parallel()
+
+	info := &ChangeFeedInfo{
+		StartTs: 100,
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	info.PrepareInitialSnapshot()
+	require.Equal(t, uint64(100), info.StartTs)
+	require.Equal(t, uint64(0), info.GetInitialSnapshotTs(1, info.StartTs))
+
+	info.Config.InitialSnapshot = true
+	info.PrepareInitialSnapshot()
+	require.Equal(t, uint64(99), info.StartTs)
+	require.Equal(t, uint64(100), info.InitialSnapshotTs)
+	require.Equal(t, uint64(100), info.GetInitialSnapshotTs(1, 99))
+	// The tables added after the snapshot do not need to scan it.
+	require.Equal(t, uint64(0), info.GetInitialSnapshotTs(1, 100))
+	require.Equal(t, uint64(0), info.GetInitialSnapshotTs(1, 120))
+	// The tables whose snapshot is finished do not scan it again.
+	info.InitialSnapshotFinishedTables = []TableID{2}
+	require.Equal(t, uint64(0), info.GetInitialSnapshotTs(2, 99))
+	require.Equal(t, uint64(100), info.GetInitialSnapshotTs(1, 99))
+
+	var nilInfo *ChangeFeedInfo
+	require.Equal(t, uint64(0), nilInfo.GetInitialSnapshotTs(1, 99))
+}
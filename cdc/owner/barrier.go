@@ -29,6 +29,8 @@ const (
 	syncPointBarrier
 	// finishBarrier denotes a barrier for changefeed finished.
 	finishBarrier
+	// filterUpdateBarrier denotes a barrier for updating the filter rules.
+	filterUpdateBarrier
 )
 
 // barriers stores some barrierType and barrierTs, and can calculate the min barrierTs
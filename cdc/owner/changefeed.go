@@ -15,6 +15,9 @@ package owner
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -42,25 +45,27 @@ import (
 // newSchedulerFromCtx creates a new scheduler from context.
 // This function is factored out to facilitate unit testing.
 func newSchedulerFromCtx(
-	ctx cdcContext.Context, pdClock pdutil.Clock,
+	ctx cdcContext.Context, up *upstream.Upstream, cfg *config.ReplicaConfig,
 ) (ret scheduler.Scheduler, err error) {
 	changeFeedID := ctx.ChangefeedVars().ID
 	messageServer := ctx.GlobalVars().MessageServer
 	messageRouter := ctx.GlobalVars().MessageRouter
 	ownerRev := ctx.GlobalVars().OwnerRevision
 	captureID := ctx.GlobalVars().CaptureInfo.ID
-	cfg := config.GetGlobalServerConfig().Debug
+	debugCfg := config.GetGlobalServerConfig().Debug
 	ret, err = scheduler.NewScheduler(
 		ctx, captureID, changeFeedID,
-		messageServer, messageRouter, ownerRev, cfg.Scheduler, pdClock)
+		messageServer, messageRouter, ownerRev, up, debugCfg.Scheduler,
+		cfg.Placement)
 	return ret, errors.Trace(err)
 }
 
 func newScheduler(
 	ctx cdcContext.Context,
-	pdClock pdutil.Clock,
+	up *upstream.Upstream,
+	cfg *config.ReplicaConfig,
 ) (scheduler.Scheduler, error) {
-	return newSchedulerFromCtx(ctx, pdClock)
+	return newSchedulerFromCtx(ctx, up, cfg)
 }
 
 type changefeed struct {
@@ -87,6 +92,9 @@ type changefeed struct {
 	// but it will still be kept in the memory, and it will be check
 	// in every tick. Such as the changefeed that is stopped or encountered an error.
 	isReleased bool
+	// filterUpdated is true if the filter rules have been updated in this
+	// tick, and the changefeed must be reinitialized with the new rules.
+	filterUpdated bool
 
 	// only used for asyncExecDDL function
 	// ddlEventCache is not nil when the changefeed is executing
@@ -127,7 +135,9 @@ type changefeed struct {
 	) (puller.DDLPuller, error)
 
 	newSink      func(changefeedID model.ChangeFeedID, info *model.ChangeFeedInfo, reportErr func(error)) DDLSink
-	newScheduler func(ctx cdcContext.Context, pdClock pdutil.Clock) (scheduler.Scheduler, error)
+	newScheduler func(
+		ctx cdcContext.Context, up *upstream.Upstream, cfg *config.ReplicaConfig,
+	) (scheduler.Scheduler, error)
 
 	lastDDLTs uint64 // Timestamp of the last executed DDL. Only used for tests.
 }
@@ -165,7 +175,9 @@ func newChangefeed4Test(
 		changefeed model.ChangeFeedID,
 	) (puller.DDLPuller, error),
 	newSink func(changefeedID model.ChangeFeedID, info *model.ChangeFeedInfo, reportErr func(err error)) DDLSink,
-	newScheduler func(ctx cdcContext.Context, pdClock pdutil.Clock) (scheduler.Scheduler, error),
+	newScheduler func(
+		ctx cdcContext.Context, up *upstream.Upstream, cfg *config.ReplicaConfig,
+	) (scheduler.Scheduler, error),
 ) *changefeed {
 	c := newChangefeed(id, state, up)
 	c.newDDLPuller = newDDLPuller
@@ -288,10 +300,24 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 	}
 	c.sink.emitCheckpointTs(checkpointTs, c.currentTables)
 
+	if update := c.state.Info.PendingFilterUpdate; update != nil {
+		c.barriers.Update(filterUpdateBarrier, update.ApplyTs)
+	}
 	barrierTs, err := c.handleBarrier(ctx)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.filterUpdated {
+		// Only the owner side of the changefeed is reinitialized, the tables
+		// are kept replicating by processors, and the new scheduler adds or
+		// removes tables according to the new filter rules.
+		log.Info("changefeed filter rules updated, reinitialize the changefeed",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Uint64("checkpointTs", checkpointTs))
+		c.releaseResources(ctx)
+		return nil
+	}
 	log.Debug("owner handles barrier",
 		zap.String("namespace", c.id.Namespace),
 		zap.String("changefeed", c.id.ID),
@@ -396,6 +422,7 @@ func (c *changefeed) tick(ctx cdcContext.Context, captures map[model.CaptureID]*
 	})
 
 	c.updateStatus(newCheckpointTs, newResolvedTs)
+	c.updateInitialSnapshotProgress(newCheckpointTs)
 	c.updateMetrics(currentTs, newCheckpointTs, metricsResolvedTs)
 
 	return nil
@@ -410,6 +437,7 @@ func (c *changefeed) initialize(ctx cdcContext.Context) (err error) {
 		return nil
 	}
 	c.isReleased = false
+	c.filterUpdated = false
 	// clean the errCh
 	// When the changefeed is resumed after being stopped, the changefeed instance will be reused,
 	// So we should make sure that the errCh is empty when the changefeed is restarting
@@ -540,7 +568,7 @@ LOOP:
 		zap.String("changefeed", c.id.ID))
 
 	// create scheduler
-	c.scheduler, err = c.newScheduler(ctx, c.upstream.PDClock)
+	c.scheduler, err = c.newScheduler(ctx, c.upstream, c.state.Info.Config)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -796,6 +824,12 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 			return barrierTs, nil
 		}
 
+		// A DDL job that needs approval holds the changefeed at its commit ts
+		// until an operator resolves it.
+		if c.ddlEventCache == nil && !c.checkDDLApproval(ddlJob, ddlResolvedTs) {
+			return barrierTs, nil
+		}
+
 		done, err := c.asyncExecDDLJob(ctx, ddlJob)
 		if err != nil {
 			return 0, errors.Trace(err)
@@ -803,6 +837,7 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 		if !done {
 			return barrierTs, nil
 		}
+		c.clearPendingDDL(ddlJob.ID)
 
 		// If the last ddl was executed successfully, we can pop it
 		// from ddlPuller and update the ddl barrierTs.
@@ -824,12 +859,223 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 			c.feedStateManager.MarkFinished()
 		}
 		return barrierTs, nil
+	case filterUpdateBarrier:
+		if !fullyBlocked {
+			return barrierTs, nil
+		}
+		c.applyFilterUpdate()
+		return barrierTs, nil
 	default:
 		log.Panic("Unknown barrier type", zap.Int("barrierType", int(barrierTp)))
 	}
 	return barrierTs, nil
 }
 
+// applyFilterUpdate applies the pending filter update once the changefeed is
+// fully blocked at the apply ts of the update.
+func (c *changefeed) applyFilterUpdate() {
+	update := c.state.Info.PendingFilterUpdate
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.PendingFilterUpdate == nil {
+			return info, false, nil
+		}
+		info.Config.Filter.Rules = info.PendingFilterUpdate.Rules
+		info.PendingFilterUpdate = nil
+		return info, true, nil
+	})
+	c.barriers.Remove(filterUpdateBarrier)
+	c.filterUpdated = true
+	log.Info("apply changefeed filter update",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Strings("rules", update.Rules),
+		zap.Uint64("applyTs", update.ApplyTs))
+}
+
+// UpdateFilter queues an update of the filter rules of the changefeed, the
+// update is applied once the changefeed reaches the apply ts of it. If the
+// apply ts is zero, the update is applied at the current resolved ts.
+func (c *changefeed) UpdateFilter(update *model.FilterUpdate) error {
+	if c.state.Info == nil || c.state.Status == nil {
+		return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(c.id.ID)
+	}
+	if c.state.Info.State != model.StateNormal {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			"can only update tables of a running changefeed")
+	}
+	if c.state.Info.PendingFilterUpdate != nil {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			"another update of tables is in progress")
+	}
+	applyTs := update.ApplyTs
+	if applyTs == 0 {
+		applyTs = c.state.Status.ResolvedTs
+	}
+	if applyTs < c.state.Status.CheckpointTs {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(fmt.Sprintf(
+			"start-ts %d is less than the checkpoint-ts %d of the changefeed",
+			applyTs, c.state.Status.CheckpointTs))
+	}
+	if applyTs >= c.state.Info.GetTargetTs() {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(fmt.Sprintf(
+			"start-ts %d is not less than the target-ts %d of the changefeed",
+			applyTs, c.state.Info.GetTargetTs()))
+	}
+	pending := &model.FilterUpdate{Rules: update.Rules, ApplyTs: applyTs}
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil {
+			return nil, false, nil
+		}
+		info.PendingFilterUpdate = pending
+		return info, true, nil
+	})
+	log.Info("changefeed filter update is queued",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Strings("rules", pending.Rules),
+		zap.Uint64("applyTs", pending.ApplyTs))
+	return nil
+}
+
+// checkDDLApproval returns true if the DDL job can be executed. A job that
+// needs approval is recorded as the pending DDL of the changefeed, and it can
+// only be executed after an operator resolves it.
+func (c *changefeed) checkDDLApproval(job *timodel.Job, commitTs uint64) bool {
+	if job.BinlogInfo == nil || !c.state.Info.Config.DDLApproval.NeedApproval(job) {
+		return true
+	}
+	if pending := c.state.Info.PendingDDL; pending != nil && pending.JobID == job.ID {
+		return pending.Decision != nil
+	}
+	pending := &model.PendingDDL{
+		JobID:    job.ID,
+		CommitTs: commitTs,
+		Type:     job.Type.String(),
+		Schema:   job.SchemaName,
+		Table:    job.TableName,
+		Query:    job.Query,
+		// Only renaming multiple tables is built into several DDL events.
+		MultiTable: job.Type == timodel.ActionRenameTables,
+	}
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil {
+			return nil, false, nil
+		}
+		info.PendingDDL = pending
+		return info, true, nil
+	})
+	log.Info("changefeed is held by a DDL that needs approval",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Int64("jobID", job.ID),
+		zap.String("type", pending.Type),
+		zap.String("query", job.Query),
+		zap.Uint64("commitTs", commitTs))
+	return false
+}
+
+// applyDDLDecision returns the events of the DDL job that are replicated
+// according to the decision on the job, if the job is a resolved pending DDL.
+func (c *changefeed) applyDDLDecision(
+	job *timodel.Job, events []*model.DDLEvent,
+) []*model.DDLEvent {
+	pending := c.state.Info.PendingDDL
+	if pending == nil || pending.JobID != job.ID || pending.Decision == nil {
+		return events
+	}
+	switch pending.Decision.Action {
+	case model.DDLActionSkip:
+		// The events must not be nil, which means they are not built yet.
+		return []*model.DDLEvent{}
+	case model.DDLActionReplace:
+		if len(events) == 0 {
+			return events
+		}
+		replaced := *events[0]
+		replaced.Query = pending.Decision.Query
+		return []*model.DDLEvent{&replaced}
+	}
+	return events
+}
+
+// clearPendingDDL removes the pending DDL once the job is executed.
+func (c *changefeed) clearPendingDDL(jobID int64) {
+	pending := c.state.Info.PendingDDL
+	if pending == nil || pending.JobID != jobID {
+		return
+	}
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.PendingDDL == nil || info.PendingDDL.JobID != jobID {
+			return info, false, nil
+		}
+		info.PendingDDL = nil
+		return info, true, nil
+	})
+	fields := []zap.Field{
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Int64("jobID", jobID),
+	}
+	if pending.Decision != nil {
+		fields = append(fields, zap.String("action", string(pending.Decision.Action)))
+	}
+	log.Info("pending DDL is executed", fields...)
+}
+
+// ResolveDDL resolves the pending DDL of the changefeed by the decision of an
+// operator, the changefeed resumes once the decision is applied.
+func (c *changefeed) ResolveDDL(decision *model.DDLDecision) error {
+	if c.state.Info == nil {
+		return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(c.id.ID)
+	}
+	pending := c.state.Info.PendingDDL
+	if pending == nil {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			"the changefeed has no pending DDL")
+	}
+	if pending.JobID != decision.JobID {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(fmt.Sprintf(
+			"DDL job %d is not pending, the pending DDL job is %d",
+			decision.JobID, pending.JobID))
+	}
+	if pending.Decision != nil {
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(fmt.Sprintf(
+			"DDL job %d is already resolved", decision.JobID))
+	}
+	switch decision.Action {
+	case model.DDLActionApprove, model.DDLActionSkip:
+	case model.DDLActionReplace:
+		if strings.TrimSpace(decision.Query) == "" {
+			return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+				"a query is required to replace the DDL")
+		}
+		if pending.MultiTable {
+			return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(fmt.Sprintf(
+				"DDL job %d changes multiple tables and can't be replaced",
+				decision.JobID))
+		}
+	default:
+		return cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(fmt.Sprintf(
+			"invalid DDL action %s, must be one of approve, skip and replace",
+			decision.Action))
+	}
+	resolved := *decision
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil || info.PendingDDL == nil || info.PendingDDL.JobID != resolved.JobID {
+			return info, false, nil
+		}
+		info.PendingDDL.Decision = &resolved
+		return info, true, nil
+	})
+	log.Info("pending DDL is resolved",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Int64("jobID", resolved.JobID),
+		zap.String("action", string(resolved.Action)),
+		zap.String("query", resolved.Query))
+	return nil
+}
+
 // asyncExecDDLJob execute ddl job asynchronously, it returns true if the jod is done.
 // 0. Build ddl events from job.
 // 1. Apply ddl job to c.schema.
@@ -852,7 +1098,7 @@ func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 				zap.Any("job", job), zap.Error(err))
 			return false, errors.Trace(err)
 		}
-		c.ddlEventCache = ddlEvents
+		c.ddlEventCache = c.applyDDLDecision(job, ddlEvents)
 		// We can't use the latest schema directly,
 		// we need to make sure we receive the ddl before we start or stop broadcasting checkpoint ts.
 		// So let's remember the tables before processing and cache the DDL.
@@ -959,6 +1205,71 @@ func (c *changefeed) updateStatus(checkpointTs, resolvedTs model.Ts) {
 	})
 }
 
+// updateInitialSnapshotProgress records the tables whose snapshot rows have
+// been replicated, so that they don't scan the snapshot again if they are
+// restarted, e.g. moved to another capture or after the owner is changed,
+// before the checkpoint of the changefeed reaches the snapshot ts.
+func (c *changefeed) updateInitialSnapshotProgress(checkpointTs model.Ts) {
+	info := c.state.Info
+	if info == nil || info.InitialSnapshotTs == 0 {
+		return
+	}
+	if checkpointTs >= info.InitialSnapshotTs {
+		// the snapshot of all the tables is finished.
+		if len(info.InitialSnapshotFinishedTables) != 0 {
+			c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+				if info == nil {
+					return nil, false, nil
+				}
+				info.InitialSnapshotFinishedTables = nil
+				return info, true, nil
+			})
+		}
+		return
+	}
+
+	provider := c.GetInfoProvider()
+	if provider == nil {
+		return
+	}
+	// The checkpoint of a table is the minimum checkpoint of all its spans,
+	// which may be replicated by different captures.
+	tableCheckpoints, err := provider.GetTableCheckpoints()
+	if err != nil {
+		log.Warn("failed to get table checkpoints for the initial snapshot progress",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Error(err))
+		return
+	}
+	finished := make(map[model.TableID]struct{}, len(info.InitialSnapshotFinishedTables))
+	for _, tableID := range info.InitialSnapshotFinishedTables {
+		finished[tableID] = struct{}{}
+	}
+	var newTables []model.TableID
+	for tableID, checkpointTs := range tableCheckpoints {
+		if _, ok := finished[tableID]; ok {
+			continue
+		}
+		if checkpointTs >= info.InitialSnapshotTs {
+			finished[tableID] = struct{}{}
+			newTables = append(newTables, tableID)
+		}
+	}
+	if len(newTables) == 0 {
+		return
+	}
+	sort.Slice(newTables, func(i, j int) bool { return newTables[i] < newTables[j] })
+	c.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		if info == nil {
+			return nil, false, nil
+		}
+		info.InitialSnapshotFinishedTables = append(
+			info.InitialSnapshotFinishedTables, newTables...)
+		return info, true, nil
+	})
+}
+
 func (c *changefeed) Close(ctx cdcContext.Context) {
 	startTime := time.Now()
 	c.releaseResources(ctx)
@@ -33,9 +33,9 @@ import (
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
-	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/stretchr/testify/require"
@@ -178,6 +178,24 @@ func (m *mockScheduler) DrainCapture(target model.CaptureID) (int, error) {
 // Close closes the scheduler and releases resources.
 func (m *mockScheduler) Close(ctx context.Context) {}
 
+// mockInfoScheduler is a mockScheduler which provides the table checkpoints.
+type mockInfoScheduler struct {
+	mockScheduler
+	tableCheckpoints map[model.TableID]model.Ts
+}
+
+func (m *mockInfoScheduler) IsInitialized() bool {
+	return true
+}
+
+func (m *mockInfoScheduler) GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus, error) {
+	return nil, nil
+}
+
+func (m *mockInfoScheduler) GetTableCheckpoints() (map[model.TableID]model.Ts, error) {
+	return m.tableCheckpoints, nil
+}
+
 func createChangefeed4Test(ctx cdcContext.Context, t *testing.T,
 ) (
 	*changefeed, map[model.CaptureID]*model.CaptureInfo, *orchestrator.ReactorStateTester,
@@ -216,7 +234,7 @@ func createChangefeed4Test(ctx cdcContext.Context, t *testing.T,
 		},
 		// new scheduler
 		func(
-			ctx cdcContext.Context, pdClock pdutil.Clock,
+			ctx cdcContext.Context, up *upstream.Upstream, cfg *config.ReplicaConfig,
 		) (scheduler.Scheduler, error) {
 			return &mockScheduler{}, nil
 		})
@@ -363,6 +381,172 @@ func TestExecDDL(t *testing.T) {
 	require.Contains(t, cf.scheduler.(*mockScheduler).currentTables, job.TableID)
 }
 
+func TestDDLApproval(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.DDL2Job("create database test0")
+	helper.DDL2Job("create table test0.table0(id int primary key)")
+	helper.DDL2Job("create table test0.table1(id int primary key)")
+	job := helper.DDL2Job("create table test0.table2(id int primary key)")
+	startTs := job.BinlogInfo.FinishedTS + 1000
+
+	ctx := cdcContext.NewContext4Test(context.Background(), true)
+	ctx.ChangefeedVars().Info.StartTs = startTs
+	ctx.ChangefeedVars().Info.Config.DDLApproval = &config.DDLApprovalConfig{
+		DDLTypes: []string{"drop table", "truncate table", "rename tables"},
+	}
+
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	cf.upstream.KVStorage = helper.Storage()
+	defer cf.Close(ctx)
+	tickThreeTime := func() {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+	}
+	// pre check and initialize
+	tickThreeTime()
+	require.Len(t, cf.schema.AllPhysicalTables(), 3)
+
+	mockDDLPuller := cf.ddlPuller.(*mockDDLPuller)
+	mockDDLSink := cf.sink.(*mockDDLSink)
+	// holdDDL queues the DDL and checks that it holds the changefeed.
+	holdDDL := func(query string) *timodel.Job {
+		job := helper.DDL2Job(query)
+		mockDDLPuller.resolvedTs += 1000
+		job.BinlogInfo.FinishedTS = mockDDLPuller.resolvedTs
+		mockDDLPuller.ddlQueue = append(mockDDLPuller.ddlQueue, job)
+		tickThreeTime()
+		mockDDLPuller.resolvedTs += 1000
+		tickThreeTime()
+		require.Equal(t, job.BinlogInfo.FinishedTS, cf.state.Status.CheckpointTs)
+		pending := cf.state.Info.PendingDDL
+		require.NotNil(t, pending)
+		require.Equal(t, job.ID, pending.JobID)
+		require.Equal(t, job.BinlogInfo.FinishedTS, pending.CommitTs)
+		require.Equal(t, query, pending.Query)
+		require.Nil(t, pending.Decision)
+		return job
+	}
+
+	// case 1: approve the DDL
+	job = holdDDL("drop table test0.table0")
+	require.Nil(t, mockDDLSink.ddlExecuting)
+	require.Equal(t, "drop table", cf.state.Info.PendingDDL.Type)
+	require.Equal(t, "table0", cf.state.Info.PendingDDL.Table)
+	err := cf.ResolveDDL(&model.DDLDecision{JobID: job.ID + 1, Action: model.DDLActionApprove})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(errors.Cause(err)))
+	err = cf.ResolveDDL(&model.DDLDecision{JobID: job.ID, Action: model.DDLActionReplace})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(errors.Cause(err)))
+	err = cf.ResolveDDL(&model.DDLDecision{JobID: job.ID, Action: "ignore"})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(errors.Cause(err)))
+	require.Nil(t, cf.ResolveDDL(&model.DDLDecision{JobID: job.ID, Action: model.DDLActionApprove}))
+	tester.MustApplyPatches()
+	err = cf.ResolveDDL(&model.DDLDecision{JobID: job.ID, Action: model.DDLActionSkip})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(errors.Cause(err)))
+	mockDDLSink.ddlDone = true
+	tickThreeTime()
+	require.Equal(t, "DROP TABLE `test0`.`table0`", mockDDLSink.ddlExecuting.Query)
+	require.Nil(t, cf.state.Info.PendingDDL)
+	require.Greater(t, cf.state.Status.CheckpointTs, job.BinlogInfo.FinishedTS)
+	require.Len(t, cf.schema.AllPhysicalTables(), 2)
+
+	// case 2: skip the DDL, the schema is still changed by it
+	job = holdDDL("truncate table test0.table1")
+	require.Nil(t, cf.ResolveDDL(&model.DDLDecision{JobID: job.ID, Action: model.DDLActionSkip}))
+	tester.MustApplyPatches()
+	tickThreeTime()
+	require.Equal(t, "DROP TABLE `test0`.`table0`", mockDDLSink.ddlExecuting.Query)
+	require.Nil(t, cf.state.Info.PendingDDL)
+	require.Greater(t, cf.state.Status.CheckpointTs, job.BinlogInfo.FinishedTS)
+	require.Len(t, cf.schema.AllPhysicalTables(), 2)
+
+	// case 3: replace the DDL
+	job = holdDDL("drop table test0.table2")
+	query := "rename table test0.table2 to test0.table2_bak"
+	require.Nil(t, cf.ResolveDDL(&model.DDLDecision{
+		JobID: job.ID, Action: model.DDLActionReplace, Query: query,
+	}))
+	tester.MustApplyPatches()
+	mockDDLSink.ddlDone = true
+	tickThreeTime()
+	require.Equal(t, query, mockDDLSink.ddlExecuting.Query)
+	require.Nil(t, cf.state.Info.PendingDDL)
+	require.Greater(t, cf.state.Status.CheckpointTs, job.BinlogInfo.FinishedTS)
+
+	// case 4: a DDL that does not need approval is not held
+	job = helper.DDL2Job("create table test0.table3(id int primary key)")
+	mockDDLPuller.resolvedTs += 1000
+	job.BinlogInfo.FinishedTS = mockDDLPuller.resolvedTs
+	mockDDLPuller.ddlQueue = append(mockDDLPuller.ddlQueue, job)
+	tickThreeTime()
+	require.Equal(t, "create table test0.table3(id int primary key)", mockDDLSink.ddlExecuting.Query)
+	require.Nil(t, cf.state.Info.PendingDDL)
+	mockDDLSink.ddlDone = true
+	tickThreeTime()
+
+	// case 5: a DDL that changes multiple tables can't be replaced
+	job = holdDDL("rename table test0.table1 to test0.table10, test0.table3 to test0.table30")
+	require.True(t, cf.state.Info.PendingDDL.MultiTable)
+	err = cf.ResolveDDL(&model.DDLDecision{
+		JobID: job.ID, Action: model.DDLActionReplace, Query: query,
+	})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(errors.Cause(err)))
+	require.Nil(t, cf.ResolveDDL(&model.DDLDecision{JobID: job.ID, Action: model.DDLActionApprove}))
+	tester.MustApplyPatches()
+	// both DDL events of the job are executed
+	mockDDLSink.resetDDLDone = false
+	mockDDLSink.recordDDLHistory = true
+	mockDDLSink.ddlDone = true
+	tickThreeTime()
+	require.Len(t, mockDDLSink.ddlHistory, 2)
+	require.Nil(t, cf.state.Info.PendingDDL)
+	require.Greater(t, cf.state.Status.CheckpointTs, job.BinlogInfo.FinishedTS)
+}
+
+func TestInitialSnapshotProgress(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	const snapshotTs = 100
+	cf.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		info.InitialSnapshotTs = snapshotTs
+		return info, true, nil
+	})
+	tester.MustApplyPatches()
+	scheduler := &mockInfoScheduler{tableCheckpoints: map[model.TableID]model.Ts{
+		1: snapshotTs - 1,
+		2: snapshotTs,
+		3: snapshotTs + 10,
+	}}
+	cf.scheduler = scheduler
+
+	cf.updateInitialSnapshotProgress(snapshotTs - 1)
+	tester.MustApplyPatches()
+	require.Equal(t, []model.TableID{2, 3}, cf.state.Info.InitialSnapshotFinishedTables)
+	// the finished tables don't scan the snapshot again after restarted.
+	require.Equal(t, uint64(0), cf.state.Info.GetInitialSnapshotTs(2, snapshotTs-1))
+	require.Equal(t, uint64(snapshotTs), cf.state.Info.GetInitialSnapshotTs(1, snapshotTs-1))
+
+	// a moved table keeps being recorded as finished.
+	delete(scheduler.tableCheckpoints, 2)
+	scheduler.tableCheckpoints[1] = snapshotTs
+	cf.updateInitialSnapshotProgress(snapshotTs - 1)
+	tester.MustApplyPatches()
+	require.Equal(t, []model.TableID{2, 3, 1}, cf.state.Info.InitialSnapshotFinishedTables)
+
+	// the records are cleaned up once the checkpoint reaches the snapshot ts.
+	cf.updateInitialSnapshotProgress(snapshotTs)
+	tester.MustApplyPatches()
+	require.Empty(t, cf.state.Info.InitialSnapshotFinishedTables)
+}
+
 func TestEmitCheckpointTs(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
@@ -479,6 +663,57 @@ func TestFinished(t *testing.T) {
 	require.Equal(t, cf.state.Info.State, model.StateFinished)
 }
 
+func TestUpdateFilter(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+
+	// pre check
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	// initialize
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+
+	startTs := cf.state.Info.StartTs
+	err := cf.UpdateFilter(&model.FilterUpdate{Rules: []string{"test.*"}, ApplyTs: startTs - 1})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(errors.Cause(err)))
+
+	applyTs := startTs + 500
+	rules := []string{"*.*", "!test.t1"}
+	require.Nil(t, cf.UpdateFilter(&model.FilterUpdate{Rules: rules, ApplyTs: applyTs}))
+	tester.MustApplyPatches()
+	require.Equal(t, applyTs, cf.state.Info.PendingFilterUpdate.ApplyTs)
+	err = cf.UpdateFilter(&model.FilterUpdate{Rules: rules, ApplyTs: applyTs})
+	require.True(t, cerror.ErrChangefeedUpdateRefused.Equal(errors.Cause(err)))
+
+	ddlPuller := cf.ddlPuller.(*mockDDLPuller)
+	ddlPuller.resolvedTs += 2000
+	// The changefeed is blocked at the apply ts until the update is applied.
+	for i := 0; i <= 10 && cf.state.Info.PendingFilterUpdate != nil; i++ {
+		require.LessOrEqual(t, cf.state.Status.CheckpointTs, applyTs)
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+	}
+	require.Nil(t, cf.state.Info.PendingFilterUpdate)
+	require.Equal(t, rules, cf.state.Info.Config.Filter.Rules)
+	require.Equal(t, applyTs, cf.state.Status.CheckpointTs)
+	require.False(t, cf.initialized)
+
+	// The changefeed is reinitialized with the new rules.
+	cf.Tick(ctx, captures)
+	tester.MustApplyPatches()
+	require.True(t, cf.initialized)
+	require.True(t, cf.schema.filter.ShouldIgnoreTable("test", "t1"))
+	cf.ddlPuller.(*mockDDLPuller).resolvedTs = ddlPuller.resolvedTs
+	for i := 0; i <= 10; i++ {
+		cf.Tick(ctx, captures)
+		tester.MustApplyPatches()
+	}
+	require.Greater(t, cf.state.Status.CheckpointTs, applyTs)
+}
+
 func TestRemoveChangefeed(t *testing.T) {
 	baseCtx, cancel := context.WithCancel(context.Background())
 	ctx := cdcContext.NewContext4Test(baseCtx, true)
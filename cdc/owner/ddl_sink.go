@@ -118,6 +118,8 @@ type ddlSinkInitHandler func(ctx context.Context, a *ddlSinkImpl) error
 
 func ddlSinkInitializer(ctx context.Context, a *ddlSinkImpl) error {
 	ctx = contextutil.PutRoleInCtx(ctx, util.RoleOwner)
+	ctx = contextutil.PutChangefeedIDInCtx(ctx, a.changefeedID)
+	ctx = contextutil.PutUpstreamIDInCtx(ctx, a.info.UpstreamID)
 	conf := config.GetGlobalServerConfig()
 	if !conf.Debug.EnableNewSink {
 		log.Info("Try to create ddlSink based on sinkV1",
@@ -99,6 +99,18 @@ func (mr *MockOwnerMockRecorder) RebalanceTables(cfID, done interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebalanceTables", reflect.TypeOf((*MockOwner)(nil).RebalanceTables), cfID, done)
 }
 
+// ResolveDDL mocks base method.
+func (m *MockOwner) ResolveDDL(cfID model.ChangeFeedID, decision *model.DDLDecision, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResolveDDL", cfID, decision, done)
+}
+
+// ResolveDDL indicates an expected call of ResolveDDL.
+func (mr *MockOwnerMockRecorder) ResolveDDL(cfID, decision, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveDDL", reflect.TypeOf((*MockOwner)(nil).ResolveDDL), cfID, decision, done)
+}
+
 // ScheduleTable mocks base method.
 func (m *MockOwner) ScheduleTable(cfID model.ChangeFeedID, toCapture model.CaptureID, tableID model.TableID, done chan<- error) {
 	m.ctrl.T.Helper()
@@ -126,6 +138,18 @@ func (mr *MockOwnerMockRecorder) Tick(ctx, state interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tick", reflect.TypeOf((*MockOwner)(nil).Tick), ctx, state)
 }
 
+// UpdateFilter mocks base method.
+func (m *MockOwner) UpdateFilter(cfID model.ChangeFeedID, update *model.FilterUpdate, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateFilter", cfID, update, done)
+}
+
+// UpdateFilter indicates an expected call of UpdateFilter.
+func (mr *MockOwnerMockRecorder) UpdateFilter(cfID, update, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFilter", reflect.TypeOf((*MockOwner)(nil).UpdateFilter), cfID, update, done)
+}
+
 // ValidateChangefeed mocks base method.
 func (m *MockOwner) ValidateChangefeed(info *model.ChangeFeedInfo) error {
 	m.ctrl.T.Helper()
@@ -46,6 +46,8 @@ const (
 	ownerJobTypeAdminJob
 	ownerJobTypeDebugInfo
 	ownerJobTypeQuery
+	ownerJobTypeUpdateFilter
+	ownerJobTypeResolveDDL
 )
 
 // versionInconsistentLogRate represents the rate of log output when there are
@@ -84,6 +86,12 @@ type ownerJob struct {
 	// for scheduler related jobs
 	scheduleQuery *scheduler.Query
 
+	// for UpdateFilter only
+	filterUpdate *model.FilterUpdate
+
+	// for ResolveDDL only
+	ddlDecision *model.DDLDecision
+
 	done chan<- error
 }
 
@@ -99,6 +107,8 @@ type Owner interface {
 		tableID model.TableID, done chan<- error,
 	)
 	DrainCapture(query *scheduler.Query, done chan<- error)
+	UpdateFilter(cfID model.ChangeFeedID, update *model.FilterUpdate, done chan<- error)
+	ResolveDDL(cfID model.ChangeFeedID, decision *model.DDLDecision, done chan<- error)
 	WriteDebugInfo(w io.Writer, done chan<- error)
 	Query(query *Query, done chan<- error)
 	ValidateChangefeed(info *model.ChangeFeedInfo) error
@@ -288,6 +298,32 @@ func (o *ownerImpl) DrainCapture(query *scheduler.Query, done chan<- error) {
 	})
 }
 
+// UpdateFilter updates the filter rules of a running changefeed
+// `done` must be buffered to prevent blocking owner.
+func (o *ownerImpl) UpdateFilter(
+	cfID model.ChangeFeedID, update *model.FilterUpdate, done chan<- error,
+) {
+	o.pushOwnerJob(&ownerJob{
+		Tp:           ownerJobTypeUpdateFilter,
+		ChangefeedID: cfID,
+		filterUpdate: update,
+		done:         done,
+	})
+}
+
+// ResolveDDL resolves the pending DDL of a changefeed
+// `done` must be buffered to prevent blocking owner.
+func (o *ownerImpl) ResolveDDL(
+	cfID model.ChangeFeedID, decision *model.DDLDecision, done chan<- error,
+) {
+	o.pushOwnerJob(&ownerJob{
+		Tp:           ownerJobTypeResolveDDL,
+		ChangefeedID: cfID,
+		ddlDecision:  decision,
+		done:         done,
+	})
+}
+
 // WriteDebugInfo writes debug info into the specified http writer
 func (o *ownerImpl) WriteDebugInfo(w io.Writer, done chan<- error) {
 	o.pushOwnerJob(&ownerJob{
@@ -550,6 +586,14 @@ func (o *ownerImpl) handleJobs(ctx context.Context) {
 			}
 		case ownerJobTypeQuery:
 			job.done <- o.handleQueries(job.query)
+		case ownerJobTypeUpdateFilter:
+			if err := cfReactor.UpdateFilter(job.filterUpdate); err != nil {
+				job.done <- err
+			}
+		case ownerJobTypeResolveDDL:
+			if err := cfReactor.ResolveDDL(job.ddlDecision); err != nil {
+				job.done <- err
+			}
 		case ownerJobTypeDebugInfo:
 			// TODO: implement this function
 		}
@@ -653,6 +697,7 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 				ID:            captureInfo.ID,
 				AdvertiseAddr: captureInfo.AdvertiseAddr,
 				Version:       captureInfo.Version,
+				Labels:        captureInfo.Labels,
 			})
 		}
 		query.Data = ret
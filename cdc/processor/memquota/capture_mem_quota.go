@@ -0,0 +1,164 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memquota
+
+import (
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// normalPriorityQuotaRatio is the ratio of the capture memory quota
+	// that changefeeds of normal priority can use.
+	normalPriorityQuotaRatio = 0.8
+	// lowPriorityQuotaRatio is the ratio of the capture memory quota
+	// that changefeeds of low priority can use.
+	lowPriorityQuotaRatio = 0.5
+)
+
+// CaptureMemQuota is the memory quota shared by all changefeeds on a capture.
+// The memory of the sorter is reserved from the quota, and the rest is shared
+// by the events of the changefeeds after they are read from the sorter, i.e.,
+// the events being mounted and the events buffered in the sinks.
+//
+// A changefeed can use the quota up to a limit decided by its priority, so
+// that changefeeds of higher priorities can still make progress when the
+// quota is exhausted by changefeeds of lower priorities.
+type CaptureMemQuota struct {
+	// totalBytes is the quota shared by the changefeeds, the memory of the
+	// sorter is excluded.
+	totalBytes uint64
+
+	// blockAcquireCond is used to notify the blocked acquire.
+	blockAcquireCond *sync.Cond
+
+	// mu protects the following fields.
+	mu        sync.Mutex
+	usedBytes uint64
+
+	metricUsed prometheus.Gauge
+}
+
+// NewCaptureMemQuota creates a CaptureMemQuota of totalBytes, sorterBytes
+// of which are reserved for the sorter.
+func NewCaptureMemQuota(totalBytes, sorterBytes uint64) (*CaptureMemQuota, error) {
+	if sorterBytes >= totalBytes {
+		return nil, cerrors.ErrInvalidServerOption.GenWithStack(
+			"memory-quota (%d bytes) must be larger than the memory of the sorter (%d bytes), "+
+				"you can decrease `sorter.max-memory-percentage`", totalBytes, sorterBytes)
+	}
+	c := &CaptureMemQuota{
+		totalBytes: totalBytes - sorterBytes,
+		metricUsed: captureMemoryQuota.WithLabelValues("used"),
+	}
+	c.blockAcquireCond = sync.NewCond(&c.mu)
+	captureMemoryQuota.WithLabelValues("total").Set(float64(totalBytes))
+	captureMemoryQuota.WithLabelValues("sorter").Set(float64(sorterBytes))
+	c.metricUsed.Set(float64(0))
+	return c, nil
+}
+
+// limit returns the bytes that changefeeds of the priority can use.
+func (c *CaptureMemQuota) limit(priority string) uint64 {
+	switch priority {
+	case config.ChangefeedPriorityHigh:
+		return c.totalBytes
+	case config.ChangefeedPriorityLow:
+		return uint64(float64(c.totalBytes) * lowPriorityQuotaRatio)
+	default:
+		return uint64(float64(c.totalBytes) * normalPriorityQuotaRatio)
+	}
+}
+
+// available returns true if nBytes can be acquired. A request larger than
+// the limit is allowed if nothing is used, otherwise it can never succeed.
+// It must be called with mu held.
+func (c *CaptureMemQuota) available(nBytes uint64, priority string) bool {
+	return c.usedBytes == 0 || c.usedBytes+nBytes <= c.limit(priority)
+}
+
+// TryAcquire returns true if the memory quota is available, otherwise returns false.
+func (c *CaptureMemQuota) TryAcquire(nBytes uint64, priority string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.available(nBytes, priority) {
+		return false
+	}
+	c.usedBytes += nBytes
+	c.metricUsed.Set(float64(c.usedBytes))
+	return true
+}
+
+// ForceAcquire is used to force acquire the memory quota.
+func (c *CaptureMemQuota) ForceAcquire(nBytes uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usedBytes += nBytes
+	c.metricUsed.Set(float64(c.usedBytes))
+}
+
+// BlockAcquire blocks until the memory quota is available or isClosed returns
+// true. Callers must call Notify after closing to wake up the blocked acquires.
+func (c *CaptureMemQuota) BlockAcquire(
+	nBytes uint64, priority string, isClosed func() bool,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		if isClosed() {
+			return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
+		}
+		if c.available(nBytes, priority) {
+			c.usedBytes += nBytes
+			c.metricUsed.Set(float64(c.usedBytes))
+			return nil
+		}
+		c.blockAcquireCond.Wait()
+	}
+}
+
+// Release releases the memory quota.
+func (c *CaptureMemQuota) Release(nBytes uint64) {
+	if nBytes == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.usedBytes < nBytes {
+		log.Panic("CaptureMemQuota.Release fail",
+			zap.Uint64("used", c.usedBytes), zap.Uint64("release", nBytes))
+	}
+	c.usedBytes -= nBytes
+	c.metricUsed.Set(float64(c.usedBytes))
+	c.blockAcquireCond.Broadcast()
+}
+
+// Notify wakes up all blocked acquires, e.g., when a changefeed is closed.
+func (c *CaptureMemQuota) Notify() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blockAcquireCond.Broadcast()
+}
+
+// UsedBytes returns the used memory quota.
+func (c *CaptureMemQuota) UsedBytes() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usedBytes
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memquota
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestNewCaptureMemQuota(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCaptureMemQuota(100, 100)
+	require.True(t, cerrors.ErrInvalidServerOption.Equal(err))
+
+	// The memory of the sorter is reserved from the quota.
+	c, err := NewCaptureMemQuota(100, 20)
+	require.NoError(t, err)
+	require.True(t, c.TryAcquire(80, config.ChangefeedPriorityHigh))
+	require.False(t, c.TryAcquire(1, config.ChangefeedPriorityHigh))
+}
+
+func TestCaptureMemQuotaLimit(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCaptureMemQuota(100, 0)
+	require.NoError(t, err)
+	// A request larger than the limit is allowed if nothing is used.
+	require.True(t, c.TryAcquire(200, config.ChangefeedPriorityLow))
+	c.Release(200)
+
+	require.True(t, c.TryAcquire(50, config.ChangefeedPriorityLow))
+	require.False(t, c.TryAcquire(1, config.ChangefeedPriorityLow))
+	require.True(t, c.TryAcquire(30, config.ChangefeedPriorityNormal))
+	require.False(t, c.TryAcquire(1, config.ChangefeedPriorityNormal))
+	require.True(t, c.TryAcquire(20, config.ChangefeedPriorityHigh))
+	require.False(t, c.TryAcquire(1, config.ChangefeedPriorityHigh))
+
+	c.ForceAcquire(10)
+	require.Equal(t, uint64(110), c.UsedBytes())
+	c.Release(110)
+	require.Equal(t, uint64(0), c.UsedBytes())
+}
+
+func TestCaptureMemQuotaBlockAcquire(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCaptureMemQuota(100, 0)
+	require.NoError(t, err)
+	closed := atomic.NewBool(false)
+	require.NoError(t, c.BlockAcquire(100, config.ChangefeedPriorityHigh, closed.Load))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, c.BlockAcquire(50, config.ChangefeedPriorityHigh, closed.Load))
+	}()
+	c.Release(50)
+	wg.Wait()
+	require.Equal(t, uint64(100), c.UsedBytes())
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := c.BlockAcquire(50, config.ChangefeedPriorityHigh, closed.Load)
+		require.True(t, cerrors.ErrFlowControllerAborted.Equal(err))
+	}()
+	closed.Store(true)
+	c.Notify()
+	wg.Wait()
+	require.Equal(t, uint64(100), c.UsedBytes())
+}
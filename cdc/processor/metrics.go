@@ -14,6 +14,7 @@
 package processor
 
 import (
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline"
 	"github.com/pingcap/tiflow/cdc/processor/sinkmanager"
 	"github.com/prometheus/client_golang/prometheus"
@@ -144,4 +145,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(remainKVEventsGauge)
 	pipeline.InitMetrics(registry)
 	sinkmanager.InitMetrics(registry)
+	memquota.InitMetrics(registry)
 }
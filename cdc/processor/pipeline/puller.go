@@ -78,6 +78,7 @@ func (n *pullerNode) startWithSorterNode(ctx pipeline.NodeContext,
 		n.span.TableID,
 		n.tableName,
 		filterLoop,
+		ctx.ChangefeedVars().Info.GetInitialSnapshotTs(n.span.TableID, n.startTs),
 	)
 	n.wg.Go(func() error {
 		ctx.Throw(errors.Trace(n.plr.Run(ctxC)))
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/redo"
 	sinkv1 "github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/cdc/sink/flowcontrol"
@@ -296,7 +297,8 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 	splitTxn := t.replicaConfig.Sink.TxnAtomicity.ShouldSplitTxn()
 
 	flowController := flowcontrol.NewTableFlowController(t.memoryQuota,
-		t.redoManager.Enabled(), splitTxn)
+		t.redoManager.Enabled(), splitTxn).
+		WithCaptureQuota(t.globalVars.CaptureMemQuota, t.replicaConfig.Quota.GetPriority())
 	sorterNode := newSorterNode(t.tableName, t.span.TableID,
 		t.replicaInfo.StartTs, flowController,
 		t.mg, &t.state, t.changefeedID, t.redoManager.Enabled(),
@@ -468,8 +470,11 @@ func (t *tableActor) Stats() tablepb.Stats {
 
 	stats := tablepb.Stats{
 		RegionCount: pullerStats.RegionCount,
-		CurrentTs:   oracle.ComposeTS(oracle.GetPhysical(now), 0),
-		BarrierTs:   sinkStats.BarrierTs,
+		EventCount:  pullerStats.EventCount,
+		RegionWriteStats: puller.ToRegionWriteStats(
+			pullerStats.RegionWriteStats),
+		CurrentTs: oracle.ComposeTS(oracle.GetPhysical(now), 0),
+		BarrierTs: sinkStats.BarrierTs,
 		StageCheckpoints: map[string]tablepb.Checkpoint{
 			"puller-ingress": {
 				CheckpointTs: pullerStats.CheckpointTsIngress,
@@ -47,6 +47,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/transform"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -70,6 +71,11 @@ type processor struct {
 
 	filter filter.Filter
 	mg     entry.MounterGroup
+	// schemaReloader is nil before the processor is initialized.
+	schemaReloader *schemaReloader
+	// ctx is the context of the processor, it is canceled when the processor
+	// is closed.
+	ctx cdcContext.Context
 
 	pullBasedSinking bool
 
@@ -499,8 +505,11 @@ func (p *processor) getStatsFromSourceManagerAndSinkManager(tableID model.TableI
 
 	stats := tablepb.Stats{
 		RegionCount: pullerStats.RegionCount,
-		CurrentTs:   oracle.ComposeTS(oracle.GetPhysical(now), 0),
-		BarrierTs:   sinkStats.BarrierTs,
+		EventCount:  pullerStats.EventCount,
+		RegionWriteStats: puller.ToRegionWriteStats(
+			pullerStats.RegionWriteStats),
+		CurrentTs: oracle.ComposeTS(oracle.GetPhysical(now), 0),
+		BarrierTs: sinkStats.BarrierTs,
 		StageCheckpoints: map[string]tablepb.Checkpoint{
 			"puller-ingress": {
 				CheckpointTs: pullerStats.CheckpointTsIngress,
@@ -707,6 +716,9 @@ func (p *processor) tick(ctx cdcContext.Context) error {
 	if err := p.lazyInit(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	if err := p.reloadSchema(); err != nil {
+		return errors.Trace(err)
+	}
 	p.pushResolvedTs2Table()
 	// it is no need to check the error here, because we will use
 	// local time when an error return, which is acceptable
@@ -763,6 +775,7 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	}
 	ctx, cancel := cdcContext.WithCancel(ctx)
 	p.cancel = cancel
+	p.ctx = ctx
 	// We don't close this error channel, since it is only safe to close channel
 	// in sender, and this channel will be used in many modules including sink,
 	// redo log manager, etc. Let runtime GC to recycle it.
@@ -788,25 +801,37 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	}()
 
 	tz := contextutil.TimezoneFromCtx(ctx)
-	var err error
-	p.filter, err = filter.NewFilter(p.changefeed.Info.Config,
+	f, err := filter.NewFilter(p.changefeed.Info.Config,
+		util.GetTimeZoneName(tz))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	transformer, err := transform.NewTransformer(p.changefeed.Info.Config,
 		util.GetTimeZoneName(tz))
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	p.schemaStorage, err = p.createAndDriveSchemaStorage(ctx)
+	schemaCtx, schemaCancel := cdcContext.WithCancel(ctx)
+	schemaStorage, err := p.createAndDriveSchemaStorage(schemaCtx)
 	if err != nil {
+		schemaCancel()
 		return errors.Trace(err)
 	}
+	p.schemaReloader = newSchemaReloader(p.changefeed.Info.Config.Filter.Rules,
+		f, schemaStorage, schemaCancel)
+	p.filter = p.schemaReloader.filter
+	p.schemaStorage = p.schemaReloader.storage
 
 	stdCtx := contextutil.PutChangefeedIDInCtx(ctx, p.changefeedID)
+	stdCtx = contextutil.PutUpstreamIDInCtx(stdCtx, p.upstream.ID)
 	stdCtx = contextutil.PutRoleInCtx(stdCtx, util.RoleProcessor)
 
 	p.mg = entry.NewMounterGroup(p.schemaStorage,
 		p.changefeed.Info.Config.Mounter.WorkerNum,
 		p.changefeed.Info.Config.EnableOldValue,
-		p.filter, tz, p.changefeedID)
+		p.filter, transformer, tz, p.changefeedID)
 
 	p.wg.Add(1)
 	go func() {
@@ -846,7 +871,7 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 		}
 		p.sourceManager = sourcemanager.New(p.changefeedID, p.upstream, p.mg, sortEngine, p.errCh, p.changefeed.Info.Config.BDRMode)
 		p.sinkManager, err = sinkmanager.New(stdCtx, p.changefeedID, p.changefeed.Info, p.upstream, p.redoManager,
-			p.sourceManager, p.errCh, p.metricsTableSinkTotalRows)
+			p.sourceManager, ctx.GlobalVars().CaptureMemQuota, p.errCh, p.metricsTableSinkTotalRows)
 		if err != nil {
 			log.Info("Processor creates sink manager fail",
 				zap.String("namespace", p.changefeedID.Namespace),
@@ -998,7 +1023,11 @@ func (p *processor) createAndDriveSchemaStorage(ctx cdcContext.Context) (entry.S
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
-		p.sendError(ddlPuller.Run(stdCtx))
+		err := ddlPuller.Run(stdCtx)
+		// The DDL puller is canceled if the schema storage is reloaded.
+		if errors.Cause(err) != context.Canceled {
+			p.sendError(err)
+		}
 	}()
 	p.wg.Add(1)
 	go func() {
@@ -1031,6 +1060,38 @@ func (p *processor) createAndDriveSchemaStorage(ctx cdcContext.Context) (entry.S
 	return schemaStorage, nil
 }
 
+// reloadSchema reloads the filter and the schema storage once the filter
+// rules of the changefeed are updated. The new schema storage is built from
+// the checkpoint of the changefeed, which is the ts the update is applied at,
+// and the tables added by the new rules are mounted with it.
+func (p *processor) reloadSchema() error {
+	if p.schemaReloader == nil {
+		return nil
+	}
+	rules := p.changefeed.Info.Config.Filter.Rules
+	if !p.schemaReloader.rulesChanged(rules) {
+		return nil
+	}
+	tz := contextutil.TimezoneFromCtx(p.ctx)
+	f, err := filter.NewFilter(p.changefeed.Info.Config, util.GetTimeZoneName(tz))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	schemaCtx, schemaCancel := cdcContext.WithCancel(p.ctx)
+	schemaStorage, err := p.createAndDriveSchemaStorage(schemaCtx)
+	if err != nil {
+		schemaCancel()
+		return errors.Trace(err)
+	}
+	p.schemaReloader.reload(rules, f, schemaStorage, schemaCancel)
+	log.Info("processor reloads schema storage with new filter rules",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Strings("rules", rules),
+		zap.Uint64("checkpointTs", p.changefeed.Status.CheckpointTs))
+	return nil
+}
+
 func (p *processor) sendError(err error) {
 	if err == nil {
 		return
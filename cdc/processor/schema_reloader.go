@@ -0,0 +1,211 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"sync"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/entry/schema"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/filter"
+)
+
+// schemaReloader holds the filter and the schema storage of a processor, and
+// replaces them once the filter rules of the changefeed are updated, so that
+// the tables added by the new rules can be mounted without restarting the
+// processor.
+type schemaReloader struct {
+	rules   []string
+	filter  *reloadableFilter
+	storage *reloadableSchemaStorage
+	// cancel stops the DDL puller that drives the current schema storage.
+	cancel context.CancelFunc
+}
+
+func newSchemaReloader(
+	rules []string, f filter.Filter,
+	storage entry.SchemaStorage, cancel context.CancelFunc,
+) *schemaReloader {
+	return &schemaReloader{
+		rules:   rules,
+		filter:  &reloadableFilter{inner: f},
+		storage: &reloadableSchemaStorage{inner: storage, retired: make(chan struct{})},
+		cancel:  cancel,
+	}
+}
+
+// rulesChanged returns true if the given filter rules differ from the rules
+// of the current filter.
+func (r *schemaReloader) rulesChanged(rules []string) bool {
+	if len(rules) != len(r.rules) {
+		return true
+	}
+	for i := range rules {
+		if rules[i] != r.rules[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// reload replaces the filter and the schema storage, and stops the DDL
+// puller of the replaced schema storage.
+func (r *schemaReloader) reload(
+	rules []string, f filter.Filter,
+	storage entry.SchemaStorage, cancel context.CancelFunc,
+) {
+	r.filter.reload(f)
+	r.storage.reload(storage)
+	r.cancel()
+	r.rules = rules
+	r.cancel = cancel
+}
+
+// reloadableFilter is a filter.Filter that can be replaced at runtime.
+type reloadableFilter struct {
+	mu    sync.RWMutex
+	inner filter.Filter
+}
+
+var _ filter.Filter = (*reloadableFilter)(nil)
+
+func (f *reloadableFilter) load() filter.Filter {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.inner
+}
+
+func (f *reloadableFilter) reload(inner filter.Filter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inner = inner
+}
+
+// ShouldIgnoreDMLEvent implements filter.Filter.
+func (f *reloadableFilter) ShouldIgnoreDMLEvent(
+	dml *model.RowChangedEvent, rawRow model.RowChangedDatums, tableInfo *model.TableInfo,
+) (bool, error) {
+	return f.load().ShouldIgnoreDMLEvent(dml, rawRow, tableInfo)
+}
+
+// ShouldIgnoreDDLEvent implements filter.Filter.
+func (f *reloadableFilter) ShouldIgnoreDDLEvent(ddl *model.DDLEvent) (bool, error) {
+	return f.load().ShouldIgnoreDDLEvent(ddl)
+}
+
+// ShouldDiscardDDL implements filter.Filter.
+func (f *reloadableFilter) ShouldDiscardDDL(
+	ddlType timodel.ActionType, schema, table string,
+) bool {
+	return f.load().ShouldDiscardDDL(ddlType, schema, table)
+}
+
+// ShouldIgnoreTable implements filter.Filter.
+func (f *reloadableFilter) ShouldIgnoreTable(schema, table string) bool {
+	return f.load().ShouldIgnoreTable(schema, table)
+}
+
+// Verify implements filter.Filter.
+func (f *reloadableFilter) Verify(tableInfos []*model.TableInfo) error {
+	return f.load().Verify(tableInfos)
+}
+
+// reloadableSchemaStorage is an entry.SchemaStorage that can be replaced at
+// runtime.
+type reloadableSchemaStorage struct {
+	mu    sync.RWMutex
+	inner entry.SchemaStorage
+	// retired is closed once inner is replaced.
+	retired chan struct{}
+}
+
+var _ entry.SchemaStorage = (*reloadableSchemaStorage)(nil)
+
+func (s *reloadableSchemaStorage) load() (entry.SchemaStorage, chan struct{}) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.inner, s.retired
+}
+
+func (s *reloadableSchemaStorage) reload(inner entry.SchemaStorage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.retired)
+	s.inner = inner
+	s.retired = make(chan struct{})
+}
+
+// GetSnapshot implements entry.SchemaStorage. The replaced schema storage is
+// no longer driven, so a caller waiting on it retries with the new one.
+func (s *reloadableSchemaStorage) GetSnapshot(
+	ctx context.Context, ts uint64,
+) (*schema.Snapshot, error) {
+	for {
+		inner, retired := s.load()
+		if inner.ResolvedTs() >= ts {
+			return inner.GetSnapshot(ctx, ts)
+		}
+		snapCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-retired:
+				cancel()
+			case <-snapCtx.Done():
+			}
+		}()
+		snap, err := inner.GetSnapshot(snapCtx, ts)
+		cancel()
+		select {
+		case <-retired:
+			if ctx.Err() == nil {
+				continue
+			}
+		default:
+		}
+		return snap, err
+	}
+}
+
+// GetLastSnapshot implements entry.SchemaStorage.
+func (s *reloadableSchemaStorage) GetLastSnapshot() *schema.Snapshot {
+	inner, _ := s.load()
+	return inner.GetLastSnapshot()
+}
+
+// HandleDDLJob implements entry.SchemaStorage.
+func (s *reloadableSchemaStorage) HandleDDLJob(job *timodel.Job) error {
+	inner, _ := s.load()
+	return inner.HandleDDLJob(job)
+}
+
+// AdvanceResolvedTs implements entry.SchemaStorage.
+func (s *reloadableSchemaStorage) AdvanceResolvedTs(ts uint64) {
+	inner, _ := s.load()
+	inner.AdvanceResolvedTs(ts)
+}
+
+// ResolvedTs implements entry.SchemaStorage.
+func (s *reloadableSchemaStorage) ResolvedTs() uint64 {
+	inner, _ := s.load()
+	return inner.ResolvedTs()
+}
+
+// DoGC implements entry.SchemaStorage.
+func (s *reloadableSchemaStorage) DoGC(ts uint64) uint64 {
+	inner, _ := s.load()
+	return inner.DoGC(ts)
+}
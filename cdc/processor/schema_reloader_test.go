@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func newFilterForTest(t *testing.T, rules ...string) filter.Filter {
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = rules
+	f, err := filter.NewFilter(cfg, "")
+	require.Nil(t, err)
+	return f
+}
+
+func TestSchemaReloaderReloadFilter(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	storage, err := entry.NewSchemaStorage(nil, 0, false, changefeedID)
+	require.Nil(t, err)
+	canceled := false
+	r := newSchemaReloader([]string{"test.t1"}, newFilterForTest(t, "test.t1"),
+		storage, func() { canceled = true })
+	require.False(t, r.rulesChanged([]string{"test.t1"}))
+	require.True(t, r.rulesChanged([]string{"test.t1", "test.t2"}))
+	require.True(t, r.filter.ShouldIgnoreTable("test", "t2"))
+
+	newStorage, err := entry.NewSchemaStorage(nil, 0, false, changefeedID)
+	require.Nil(t, err)
+	newStorage.AdvanceResolvedTs(10)
+	rules := []string{"test.t1", "test.t2"}
+	r.reload(rules, newFilterForTest(t, rules...), newStorage, func() {})
+	require.True(t, canceled)
+	require.False(t, r.rulesChanged(rules))
+	require.False(t, r.filter.ShouldIgnoreTable("test", "t2"))
+	require.Equal(t, uint64(10), r.storage.ResolvedTs())
+}
+
+func TestReloadableSchemaStorageRetryAfterReload(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	storage, err := entry.NewSchemaStorage(nil, 0, false, changefeedID)
+	require.Nil(t, err)
+	r := newSchemaReloader(nil, newFilterForTest(t, "*.*"), storage, func() {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		// The replaced schema storage is never resolved to 10.
+		_, err := r.storage.GetSnapshot(ctx, 10)
+		done <- err
+	}()
+	select {
+	case <-done:
+		require.FailNow(t, "the snapshot should not be got before resolved")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	newStorage, err := entry.NewSchemaStorage(nil, 0, false, changefeedID)
+	require.Nil(t, err)
+	r.reload(nil, newFilterForTest(t, "*.*"), newStorage, func() {})
+	newStorage.AdvanceResolvedTs(10)
+	select {
+	case err := <-done:
+		require.Nil(t, err)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "the snapshot is not got from the new schema storage")
+	}
+}
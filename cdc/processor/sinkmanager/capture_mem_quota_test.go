@@ -0,0 +1,126 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sinkmanager
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureMemQuotaPriority(t *testing.T) {
+	t.Parallel()
+
+	c, err := memquota.NewCaptureMemQuota(100, 0)
+	require.NoError(t, err)
+	low := newMemQuota(model.DefaultChangeFeedID("low"), 100).
+		withCaptureQuota(c, config.ChangefeedPriorityLow)
+	defer low.close()
+	normal := newMemQuota(model.DefaultChangeFeedID("normal"), 100).
+		withCaptureQuota(c, config.ChangefeedPriorityNormal)
+	defer normal.close()
+	high := newMemQuota(model.DefaultChangeFeedID("high"), 100).
+		withCaptureQuota(c, config.ChangefeedPriorityHigh)
+	defer high.close()
+
+	// Changefeeds of low priority can use at most 50% of the quota.
+	require.True(t, low.tryAcquire(50))
+	require.False(t, low.tryAcquire(1))
+	// Changefeeds of normal priority can use at most 80% of the quota.
+	require.True(t, normal.tryAcquire(30))
+	require.False(t, normal.tryAcquire(1))
+	require.False(t, low.tryAcquire(1))
+	// Changefeeds of high priority can use all of the quota.
+	require.True(t, high.tryAcquire(20))
+	require.False(t, high.tryAcquire(1))
+	require.Equal(t, uint64(100), c.UsedBytes())
+	// Failed acquires do not consume the quota of the changefeed.
+	require.Equal(t, uint64(50), low.getUsedBytes())
+
+	// Forced acquires are always allowed.
+	low.forceAcquire(10)
+	require.Equal(t, uint64(110), c.UsedBytes())
+
+	low.refund(60)
+	require.True(t, normal.tryAcquire(20))
+	require.Equal(t, uint64(70), c.UsedBytes())
+}
+
+func TestCaptureMemQuotaRelease(t *testing.T) {
+	t.Parallel()
+
+	c, err := memquota.NewCaptureMemQuota(100, 0)
+	require.NoError(t, err)
+	m := newMemQuota(model.DefaultChangeFeedID("1"), 100).
+		withCaptureQuota(c, config.ChangefeedPriorityHigh)
+
+	m.addTable(1)
+	m.addTable(2)
+	require.True(t, m.tryAcquire(60))
+	m.record(1, model.NewResolvedTs(1), 10)
+	m.record(1, model.NewResolvedTs(2), 20)
+	m.record(2, model.NewResolvedTs(2), 30)
+	require.Equal(t, uint64(60), c.UsedBytes())
+
+	m.release(1, model.NewResolvedTs(1))
+	require.Equal(t, uint64(50), c.UsedBytes())
+	require.Equal(t, uint64(20), m.clean(1))
+	require.Equal(t, uint64(30), c.UsedBytes())
+
+	// Recorded memory is released to the capture once closed.
+	m.close()
+	require.Equal(t, uint64(0), c.UsedBytes())
+}
+
+func TestCaptureMemQuotaBlockAcquire(t *testing.T) {
+	t.Parallel()
+
+	c, err := memquota.NewCaptureMemQuota(100, 0)
+	require.NoError(t, err)
+	m1 := newMemQuota(model.DefaultChangeFeedID("1"), 100).
+		withCaptureQuota(c, config.ChangefeedPriorityHigh)
+	defer m1.close()
+	m2 := newMemQuota(model.DefaultChangeFeedID("2"), 100).
+		withCaptureQuota(c, config.ChangefeedPriorityHigh)
+
+	require.NoError(t, m1.blockAcquire(100))
+
+	// Blocked by the capture quota until m1 refunds.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, m2.blockAcquire(50))
+	}()
+	m1.refund(50)
+	wg.Wait()
+	require.Equal(t, uint64(100), c.UsedBytes())
+	require.Equal(t, uint64(50), m2.getUsedBytes())
+
+	// Blocked acquires are aborted once the changefeed is closed.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := m2.blockAcquire(50)
+		require.True(t, cerrors.ErrFlowControllerAborted.Equal(err))
+	}()
+	m2.close()
+	wg.Wait()
+	require.Equal(t, uint64(50), m2.getUsedBytes())
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
@@ -37,8 +38,8 @@ import (
 )
 
 const (
-	sinkWorkerNum               = 8
-	redoWorkerNum               = 4
+	defaultSinkWorkerNum        = 8
+	defaultRedoWorkerNum        = 4
 	defaultGenerateTaskInterval = 100 * time.Millisecond
 	// engine.CleanByTable can be expensive. So it's necessary to reduce useless calls.
 	cleanTableInterval  = 5 * time.Second
@@ -87,12 +88,16 @@ type SinkManager struct {
 	// lastBarrierTs is the last barrier ts.
 	lastBarrierTs atomic.Uint64
 
+	// sinkWorkerNum is the number of sinkWorkers.
+	sinkWorkerNum int
 	// sinkWorkers used to pull data from source manager.
 	sinkWorkers []*sinkWorker
 	// sinkTaskChan is used to send tasks to sinkWorkers.
 	sinkTaskChan        chan *sinkTask
 	sinkWorkerAvailable chan struct{}
 
+	// redoWorkerNum is the number of redoWorkers.
+	redoWorkerNum int
 	// redoWorkers used to pull data from source manager.
 	redoWorkers []*redoWorker
 	// redoTaskChan is used to send tasks to redoWorkers.
@@ -115,6 +120,7 @@ func New(
 	up *upstream.Upstream,
 	redoManager redo.LogManager,
 	sourceManager *sourcemanager.SourceManager,
+	captureMemQuota *memquota.CaptureMemQuota,
 	errChan chan error,
 	metricsTableSinkTotalRows prometheus.Counter,
 ) (*SinkManager, error) {
@@ -128,17 +134,21 @@ func New(
 		return nil, errors.Trace(err)
 	}
 
+	quota := changefeedInfo.Config.Quota
+	sinkWorkerNum := quota.GetSinkWorkerNum(defaultSinkWorkerNum)
 	ctx, cancel := context.WithCancel(ctx)
 	m := &SinkManager{
-		changefeedID:  changefeedID,
-		ctx:           ctx,
-		cancel:        cancel,
-		up:            up,
-		memQuota:      newMemQuota(changefeedID, changefeedInfo.Config.MemoryQuota),
+		changefeedID: changefeedID,
+		ctx:          ctx,
+		cancel:       cancel,
+		up:           up,
+		memQuota: newMemQuota(changefeedID, changefeedInfo.Config.MemoryQuota).
+			withCaptureQuota(captureMemQuota, quota.GetPriority()),
 		sinkFactory:   tableSinkFactory,
 		sourceManager: sourceManager,
 
 		sinkProgressHeap:    newTableProgresses(),
+		sinkWorkerNum:       sinkWorkerNum,
 		sinkWorkers:         make([]*sinkWorker, 0, sinkWorkerNum),
 		sinkTaskChan:        make(chan *sinkTask),
 		sinkWorkerAvailable: make(chan struct{}, 1),
@@ -149,7 +159,8 @@ func New(
 	if redoManager != nil && redoManager.Enabled() {
 		m.redoManager = redoManager
 		m.redoProgressHeap = newTableProgresses()
-		m.redoWorkers = make([]*redoWorker, 0, redoWorkerNum)
+		m.redoWorkerNum = quota.GetRedoWorkerNum(defaultRedoWorkerNum)
+		m.redoWorkers = make([]*redoWorker, 0, m.redoWorkerNum)
 		m.redoTaskChan = make(chan *redoTask)
 		m.redoWorkerAvailable = make(chan struct{}, 1)
 		// TODO: maybe should use at most 1/3 memory quota for redo event cache.
@@ -170,7 +181,7 @@ func New(
 // start all workers and report the error to the error channel.
 func (m *SinkManager) startWorkers(splitTxn bool, enableOldValue bool) {
 	redoEnabled := m.redoManager != nil && m.redoManager.Enabled()
-	for i := 0; i < sinkWorkerNum; i++ {
+	for i := 0; i < m.sinkWorkerNum; i++ {
 		w := newSinkWorker(m.changefeedID, m.sourceManager, m.memQuota,
 			m.eventCache, redoEnabled, splitTxn, enableOldValue)
 		m.sinkWorkers = append(m.sinkWorkers, w)
@@ -195,7 +206,7 @@ func (m *SinkManager) startWorkers(splitTxn bool, enableOldValue bool) {
 		return
 	}
 
-	for i := 0; i < redoWorkerNum; i++ {
+	for i := 0; i < m.redoWorkerNum; i++ {
 		w := newRedoWorker(m.changefeedID, m.sourceManager, m.memQuota,
 			m.redoManager, m.eventCache, splitTxn, enableOldValue)
 		m.redoWorkers = append(m.redoWorkers, w)
@@ -339,11 +350,11 @@ func (m *SinkManager) generateSinkTasks() error {
 	}
 
 	dispatchTasks := func() error {
-		tables := make([]*tableSinkWrapper, 0, sinkWorkerNum)
-		progs := make([]*progress, 0, sinkWorkerNum)
+		tables := make([]*tableSinkWrapper, 0, m.sinkWorkerNum)
+		progs := make([]*progress, 0, m.sinkWorkerNum)
 
 		// Collect some table progresses.
-		for len(tables) < sinkWorkerNum && m.sinkProgressHeap.len() > 0 {
+		for len(tables) < m.sinkWorkerNum && m.sinkProgressHeap.len() > 0 {
 			slowestTableProgress := m.sinkProgressHeap.pop()
 			tableID := slowestTableProgress.tableID
 
@@ -478,10 +489,10 @@ func (m *SinkManager) generateRedoTasks() error {
 	}
 
 	dispatchTasks := func() error {
-		tables := make([]*tableSinkWrapper, 0, redoWorkerNum)
-		progs := make([]*progress, 0, redoWorkerNum)
+		tables := make([]*tableSinkWrapper, 0, m.redoWorkerNum)
+		progs := make([]*progress, 0, m.redoWorkerNum)
 
-		for len(tables) < redoWorkerNum && m.redoProgressHeap.len() > 0 {
+		for len(tables) < m.redoWorkerNum && m.redoProgressHeap.len() > 0 {
 			slowestTableProgress := m.redoProgressHeap.pop()
 			tableID := slowestTableProgress.tableID
 
@@ -58,7 +58,7 @@ func createManagerWithMemEngine(
 	sm := sourcemanager.New(changefeedID, up, &entry.MockMountGroup{}, sortEngine, errChan, false)
 	manager, err := New(
 		ctx, changefeedID, changefeedInfo, up,
-		nil, sm,
+		nil, sm, nil,
 		errChan, prometheus.NewCounter(prometheus.CounterOpts{}))
 	require.NoError(t, err)
 	return manager, sortEngine
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -47,6 +48,12 @@ type memQuota struct {
 	// isClosed is used to indicate whether the mem quota is closed.
 	isClosed atomic.Bool
 
+	// capture is the memory quota shared by all changefeeds on the capture,
+	// nil means it is not limited.
+	capture *memquota.CaptureMemQuota
+	// priority is the priority of the changefeed when acquiring from capture.
+	priority string
+
 	metricTotal prometheus.Gauge
 	metricUsed  prometheus.Gauge
 }
@@ -67,6 +74,21 @@ func newMemQuota(changefeedID model.ChangeFeedID, totalBytes uint64) *memQuota {
 	return m
 }
 
+// withCaptureQuota makes the memory quota also acquire from the memory quota
+// of the capture with the given priority.
+func (m *memQuota) withCaptureQuota(capture *memquota.CaptureMemQuota, priority string) *memQuota {
+	m.capture = capture
+	m.priority = priority
+	return m
+}
+
+// releaseCapture releases nBytes to the memory quota of the capture.
+func (m *memQuota) releaseCapture(nBytes uint64) {
+	if m.capture != nil {
+		m.capture.Release(nBytes)
+	}
+}
+
 // tryAcquire returns true if the memory quota is available, otherwise returns false.
 func (m *memQuota) tryAcquire(nBytes uint64) bool {
 	m.mu.Lock()
@@ -74,6 +96,9 @@ func (m *memQuota) tryAcquire(nBytes uint64) bool {
 	if m.usedBytes+nBytes > m.totalBytes {
 		return false
 	}
+	if m.capture != nil && !m.capture.TryAcquire(nBytes, m.priority) {
+		return false
+	}
 	m.usedBytes += nBytes
 	m.metricUsed.Set(float64(m.usedBytes))
 	return true
@@ -83,6 +108,9 @@ func (m *memQuota) tryAcquire(nBytes uint64) bool {
 func (m *memQuota) forceAcquire(nBytes uint64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.capture != nil {
+		m.capture.ForceAcquire(nBytes)
+	}
 	m.usedBytes += nBytes
 	m.metricUsed.Set(float64(m.usedBytes))
 }
@@ -90,19 +118,35 @@ func (m *memQuota) forceAcquire(nBytes uint64) {
 // blockAcquire is used to block the request when the memory quota is not available.
 func (m *memQuota) blockAcquire(nBytes uint64) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	for {
 		if m.isClosed.Load() {
+			m.mu.Unlock()
 			return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
 		}
 
 		if m.usedBytes+nBytes <= m.totalBytes {
 			m.usedBytes += nBytes
 			m.metricUsed.Set(float64(m.usedBytes))
-			return nil
+			break
 		}
 		m.blockAcquireCond.Wait()
 	}
+	m.mu.Unlock()
+	if m.capture == nil {
+		return nil
+	}
+
+	// Wait for the capture quota without holding mu, so that the memory of
+	// the changefeed can still be released.
+	if err := m.capture.BlockAcquire(nBytes, m.priority, m.isClosed.Load); err != nil {
+		m.mu.Lock()
+		m.usedBytes -= nBytes
+		m.metricUsed.Set(float64(m.usedBytes))
+		m.blockAcquireCond.Broadcast()
+		m.mu.Unlock()
+		return err
+	}
+	return nil
 }
 
 // refund directly release the memory quota.
@@ -117,6 +161,7 @@ func (m *memQuota) refund(nBytes uint64) {
 			zap.Uint64("used", m.usedBytes), zap.Uint64("refund", nBytes))
 	}
 	m.usedBytes -= nBytes
+	m.releaseCapture(nBytes)
 	m.metricUsed.Set(float64(m.usedBytes))
 	if m.usedBytes < m.totalBytes {
 		m.blockAcquireCond.Broadcast()
@@ -143,6 +188,7 @@ func (m *memQuota) record(tableID model.TableID, resolved model.ResolvedTs, nByt
 				zap.Uint64("used", m.usedBytes), zap.Uint64("refund", nBytes))
 		}
 		m.usedBytes -= nBytes
+		m.releaseCapture(nBytes)
 		m.metricUsed.Set(float64(m.usedBytes))
 		if m.usedBytes < m.totalBytes {
 			m.blockAcquireCond.Broadcast()
@@ -184,6 +230,7 @@ func (m *memQuota) release(tableID model.TableID, resolved model.ResolvedTs) {
 			zap.Uint64("used", m.usedBytes), zap.Uint64("release", toRelease))
 	}
 	m.usedBytes -= toRelease
+	m.releaseCapture(toRelease)
 	m.metricUsed.Set(float64(m.usedBytes))
 	if m.usedBytes < m.totalBytes {
 		m.blockAcquireCond.Broadcast()
@@ -210,6 +257,7 @@ func (m *memQuota) clean(tableID model.TableID) uint64 {
 		cleaned += record.size
 	}
 	m.usedBytes -= cleaned
+	m.releaseCapture(cleaned)
 	m.metricUsed.Set(float64(m.usedBytes))
 	delete(m.tableMemory, tableID)
 	if m.usedBytes < m.totalBytes {
@@ -223,10 +271,24 @@ func (m *memQuota) close() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// NOTE: m.usedBytes is not reset, because refund can still be called after closed.
+	// But the recorded memory is dropped, release it to the capture.
+	if m.capture != nil {
+		recorded := uint64(0)
+		for _, records := range m.tableMemory {
+			for _, record := range records {
+				recorded += record.size
+			}
+		}
+		m.capture.Release(recorded)
+	}
 	m.tableMemory = make(map[model.TableID][]*memConsumeRecord)
 	m.metricUsed.Set(float64(0))
 	m.isClosed.Store(true)
 	m.blockAcquireCond.Broadcast()
+	if m.capture != nil {
+		// Wake up the acquires blocked by the capture quota.
+		m.capture.Notify()
+	}
 }
 
 // getUsedBytes returns the used memory quota.
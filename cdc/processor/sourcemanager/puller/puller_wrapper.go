@@ -82,6 +82,10 @@ func (n *Wrapper) Start(
 	ctxC = contextutil.PutCaptureAddrInCtx(ctxC, ctx.GlobalVars().CaptureInfo.AdvertiseAddr)
 	ctxC = contextutil.PutRoleInCtx(ctxC, util.RoleProcessor)
 	kvCfg := config.GetGlobalServerConfig().KVClient
+	var snapshotTs model.Ts
+	if ctx.ChangefeedVars() != nil {
+		snapshotTs = ctx.ChangefeedVars().Info.GetInitialSnapshotTs(n.tableID, n.startTs)
+	}
 	// NOTICE: always pull the old value internally
 	// See also: https://github.com/pingcap/tiflow/issues/2301.
 	n.p = puller.New(
@@ -98,6 +102,7 @@ func (n *Wrapper) Start(
 		n.tableID,
 		n.tableName,
 		n.bdrMode,
+		snapshotTs,
 	)
 	n.wg.Add(1)
 	go func() {
@@ -177,6 +177,10 @@ type Stats struct {
 	StageCheckpoints map[string]Checkpoint `protobuf:"bytes,3,rep,name=stage_checkpoints,json=stageCheckpoints,proto3" json:"stage_checkpoints" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	// The barrier timestamp of the table.
 	BarrierTs github_com_pingcap_tiflow_cdc_model.Ts `protobuf:"varint,4,opt,name=barrier_ts,json=barrierTs,proto3,casttype=github.com/pingcap/tiflow/cdc/model.Ts" json:"barrier_ts,omitempty"`
+	// Number of row changed events received by the table since it was added.
+	EventCount uint64 `protobuf:"varint,5,opt,name=event_count,json=eventCount,proto3" json:"event_count,omitempty"`
+	// Bytes written to each captured region since it was captured.
+	RegionWriteStats []RegionWriteStat `protobuf:"bytes,6,rep,name=region_write_stats,json=regionWriteStats,proto3" json:"region_write_stats"`
 }
 
 func (m *Stats) Reset()         { *m = Stats{} }
@@ -240,6 +244,20 @@ func (m *Stats) GetBarrierTs() github_com_pingcap_tiflow_cdc_model.Ts {
 	return 0
 }
 
+func (m *Stats) GetEventCount() uint64 {
+	if m != nil {
+		return m.EventCount
+	}
+	return 0
+}
+
+func (m *Stats) GetRegionWriteStats() []RegionWriteStat {
+	if m != nil {
+		return m.RegionWriteStats
+	}
+	return nil
+}
+
 // TableStatus is the running status of a table.
 // TODO rename to TableStatus.
 type TableStatus struct {
@@ -318,6 +336,75 @@ func (m *TableStatus) GetStats() Stats {
 	return Stats{}
 }
 
+// RegionWriteStat is the number of bytes written to a captured region.
+type RegionWriteStat struct {
+	RegionID     uint64 `protobuf:"varint,1,opt,name=region_id,json=regionId,proto3" json:"region_id,omitempty"`
+	StartKey     Key    `protobuf:"bytes,2,opt,name=start_key,json=startKey,proto3,casttype=Key" json:"start_key,omitempty"`
+	EndKey       Key    `protobuf:"bytes,3,opt,name=end_key,json=endKey,proto3,casttype=Key" json:"end_key,omitempty"`
+	WrittenBytes uint64 `protobuf:"varint,4,opt,name=written_bytes,json=writtenBytes,proto3" json:"written_bytes,omitempty"`
+}
+
+func (m *RegionWriteStat) Reset()         { *m = RegionWriteStat{} }
+func (m *RegionWriteStat) String() string { return proto.CompactTextString(m) }
+func (*RegionWriteStat) ProtoMessage()    {}
+func (*RegionWriteStat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ae83c9c6cf5ef75c, []int{4}
+}
+func (m *RegionWriteStat) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RegionWriteStat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RegionWriteStat.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RegionWriteStat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegionWriteStat.Merge(m, src)
+}
+func (m *RegionWriteStat) XXX_Size() int {
+	return m.Size()
+}
+func (m *RegionWriteStat) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegionWriteStat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegionWriteStat proto.InternalMessageInfo
+
+func (m *RegionWriteStat) GetRegionID() uint64 {
+	if m != nil {
+		return m.RegionID
+	}
+	return 0
+}
+
+func (m *RegionWriteStat) GetStartKey() Key {
+	if m != nil {
+		return m.StartKey
+	}
+	return nil
+}
+
+func (m *RegionWriteStat) GetEndKey() Key {
+	if m != nil {
+		return m.EndKey
+	}
+	return nil
+}
+
+func (m *RegionWriteStat) GetWrittenBytes() uint64 {
+	if m != nil {
+		return m.WrittenBytes
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("pingcap.tiflow.cdc.processor.tablepb.TableState", TableState_name, TableState_value)
 	proto.RegisterType((*Span)(nil), "pingcap.tiflow.cdc.processor.tablepb.Span")
@@ -325,57 +412,64 @@ func init() {
 	proto.RegisterType((*Stats)(nil), "pingcap.tiflow.cdc.processor.tablepb.Stats")
 	proto.RegisterMapType((map[string]Checkpoint)(nil), "pingcap.tiflow.cdc.processor.tablepb.Stats.StageCheckpointsEntry")
 	proto.RegisterType((*TableStatus)(nil), "pingcap.tiflow.cdc.processor.tablepb.TableStatus")
+	proto.RegisterType((*RegionWriteStat)(nil), "pingcap.tiflow.cdc.processor.tablepb.RegionWriteStat")
 }
 
 func init() { proto.RegisterFile("processor/tablepb/table.proto", fileDescriptor_ae83c9c6cf5ef75c) }
 
 var fileDescriptor_ae83c9c6cf5ef75c = []byte{
-	// 708 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xcf, 0x6b, 0x13, 0x41,
-	0x14, 0xde, 0xcd, 0xe6, 0x47, 0xf3, 0x12, 0x65, 0x3b, 0xb6, 0x1a, 0x03, 0x26, 0x6b, 0x28, 0xa5,
-	0xa4, 0xb0, 0x2b, 0xf5, 0x22, 0xbd, 0x35, 0xad, 0x4a, 0x09, 0xa2, 0x6c, 0x53, 0x0f, 0x5e, 0xc2,
-	0x66, 0x77, 0xdc, 0x2e, 0x4d, 0x67, 0x96, 0x9d, 0x49, 0x4b, 0x6e, 0x1e, 0x25, 0x27, 0x41, 0x10,
-	0x2f, 0x81, 0x9e, 0x05, 0x4f, 0xfe, 0x13, 0x3d, 0xf6, 0xe8, 0x41, 0x82, 0xa6, 0xff, 0x45, 0x4f,
-	0x32, 0xbb, 0xdb, 0x6c, 0x8d, 0x22, 0x89, 0xe0, 0x25, 0x99, 0x7d, 0xdf, 0xfb, 0xbe, 0xf7, 0xbe,
-	0xb7, 0x6f, 0x19, 0xb8, 0xe7, 0x07, 0xd4, 0xc6, 0x8c, 0xd1, 0xc0, 0xe0, 0x56, 0xa7, 0x8b, 0xfd,
-	0x4e, 0xf4, 0xaf, 0xfb, 0x01, 0xe5, 0x14, 0xad, 0xf8, 0x1e, 0x71, 0x6d, 0xcb, 0xd7, 0xb9, 0xf7,
-	0xba, 0x4b, 0x4f, 0x74, 0xdb, 0xb1, 0xf5, 0x09, 0x43, 0x8f, 0x19, 0xe5, 0x25, 0x97, 0xba, 0x34,
-	0x24, 0x18, 0xe2, 0x14, 0x71, 0x6b, 0x5f, 0x64, 0x48, 0xef, 0xf9, 0x16, 0x41, 0xfb, 0xb0, 0x10,
-	0x66, 0xb6, 0x3d, 0xa7, 0x24, 0x6b, 0xf2, 0x9a, 0xd2, 0xd8, 0x1c, 0x8f, 0xaa, 0xb9, 0x96, 0x88,
-	0xed, 0xee, 0x5c, 0x8e, 0xaa, 0xeb, 0xae, 0xc7, 0x0f, 0x7a, 0x1d, 0xdd, 0xa6, 0x47, 0x46, 0x5c,
-	0xd0, 0x88, 0x0a, 0x1a, 0xb6, 0x63, 0x1b, 0x47, 0xd4, 0xc1, 0x5d, 0x3d, 0x4e, 0x37, 0x73, 0xa1,
-	0xd6, 0xae, 0x83, 0x56, 0x20, 0xcf, 0xb8, 0x15, 0xf0, 0xf6, 0x21, 0xee, 0x97, 0x52, 0x9a, 0xbc,
-	0x56, 0x6c, 0xe4, 0x2e, 0x47, 0x55, 0xa5, 0x89, 0xfb, 0xe6, 0x42, 0x88, 0x34, 0x71, 0x1f, 0x69,
-	0x90, 0xc3, 0xc4, 0x09, 0x73, 0x94, 0x5f, 0x73, 0xb2, 0x98, 0x38, 0x4d, 0xdc, 0xdf, 0x2c, 0xbe,
-	0x3d, 0xad, 0x4a, 0x1f, 0x4f, 0xab, 0xd2, 0x9b, 0x6f, 0x9a, 0x54, 0xfb, 0x24, 0x03, 0x6c, 0x1f,
-	0x60, 0xfb, 0xd0, 0xa7, 0x1e, 0xe1, 0xe8, 0x39, 0xdc, 0xb0, 0x27, 0x4f, 0x6d, 0xce, 0x42, 0x03,
-	0xe9, 0x46, 0xfd, 0x72, 0x54, 0x5d, 0x9d, 0xa9, 0x6b, 0x66, 0x16, 0x13, 0x81, 0x16, 0x43, 0x4d,
-	0x28, 0x04, 0x98, 0xd1, 0xee, 0x31, 0x76, 0x84, 0x5c, 0x6a, 0x6e, 0x39, 0xb8, 0xa2, 0xb7, 0x58,
-	0xed, 0xb3, 0x02, 0x99, 0x3d, 0x6e, 0x71, 0x86, 0xee, 0x43, 0x31, 0xc0, 0xae, 0x47, 0x49, 0xdb,
-	0xa6, 0x3d, 0xc2, 0xa3, 0x36, 0xcd, 0x42, 0x14, 0xdb, 0x16, 0x21, 0xb4, 0x0b, 0x60, 0xf7, 0x82,
-	0x00, 0x47, 0x3e, 0xe6, 0x2f, 0x9c, 0x8f, 0xd9, 0x2d, 0x86, 0x38, 0x2c, 0x32, 0x6e, 0xb9, 0xb8,
-	0x9d, 0x58, 0x63, 0x25, 0x45, 0x53, 0xd6, 0x0a, 0x1b, 0x5b, 0xfa, 0x2c, 0x2b, 0xa3, 0x87, 0x5d,
-	0x8b, 0x5f, 0x17, 0x27, 0xd3, 0x66, 0x8f, 0x09, 0x0f, 0xfa, 0x8d, 0xf4, 0xd9, 0xa8, 0x2a, 0x99,
-	0x2a, 0x9b, 0x02, 0x85, 0x81, 0x8e, 0x15, 0x04, 0x1e, 0x0e, 0x84, 0x81, 0xf4, 0xfc, 0x06, 0x62,
-	0x76, 0x8b, 0x95, 0x7b, 0xb0, 0xfc, 0xc7, 0xda, 0x48, 0x05, 0x45, 0xac, 0x8a, 0x18, 0x5f, 0xde,
-	0x14, 0x47, 0xf4, 0x04, 0x32, 0xc7, 0x56, 0xb7, 0x87, 0xc3, 0x89, 0x15, 0x36, 0x1e, 0xcc, 0xe6,
-	0x2f, 0x11, 0x36, 0x23, 0xfa, 0x66, 0xea, 0x91, 0x5c, 0x7b, 0xaf, 0x40, 0x21, 0xdc, 0x63, 0x61,
-	0xbf, 0xc7, 0xfe, 0xd7, 0x97, 0xb1, 0x03, 0x69, 0xe6, 0x5b, 0xa4, 0x94, 0x09, 0x3b, 0xae, 0xcf,
-	0xf8, 0x46, 0x7c, 0x8b, 0xc4, 0xa3, 0x0f, 0xd9, 0xc2, 0x38, 0xe3, 0x16, 0x8f, 0x8c, 0xdf, 0x9c,
-	0xd5, 0xf8, 0xc4, 0x1e, 0x36, 0x23, 0x3a, 0x7a, 0x09, 0x90, 0xac, 0x49, 0xf8, 0x11, 0xfe, 0xc3,
-	0x14, 0xe3, 0xce, 0xae, 0x29, 0xa1, 0xa7, 0x51, 0x7f, 0xd1, 0x26, 0x14, 0x36, 0xd6, 0xe7, 0x58,
-	0xbc, 0x58, 0x2d, 0xe2, 0xd7, 0x3f, 0xa4, 0x00, 0x92, 0xb6, 0x51, 0x0d, 0x72, 0xfb, 0xe4, 0x90,
-	0xd0, 0x13, 0xa2, 0x4a, 0xe5, 0xe5, 0xc1, 0x50, 0x5b, 0x4c, 0xc0, 0x18, 0x40, 0x1a, 0x64, 0xb7,
-	0x3a, 0x0c, 0x13, 0xae, 0xca, 0xe5, 0xa5, 0xc1, 0x50, 0x53, 0x93, 0x94, 0x28, 0x8e, 0x56, 0x21,
-	0xff, 0x22, 0xc0, 0xbe, 0x15, 0x78, 0xc4, 0x55, 0x53, 0xe5, 0x3b, 0x83, 0xa1, 0x76, 0x2b, 0x49,
-	0x9a, 0x40, 0x68, 0x05, 0x16, 0xa2, 0x07, 0xec, 0xa8, 0x4a, 0xf9, 0xf6, 0x60, 0xa8, 0xa1, 0xe9,
-	0x34, 0xec, 0xa0, 0x3a, 0x14, 0x4c, 0xec, 0x77, 0x3d, 0xdb, 0xe2, 0x42, 0x2f, 0x5d, 0xbe, 0x3b,
-	0x18, 0x6a, 0xcb, 0xd7, 0x66, 0x9d, 0x80, 0x42, 0x71, 0x8f, 0x53, 0x5f, 0x4c, 0x43, 0xcd, 0x4c,
-	0x2b, 0x5e, 0x21, 0xc2, 0x65, 0x78, 0xc6, 0x8e, 0x9a, 0x9d, 0x76, 0x19, 0x03, 0x8d, 0x67, 0xe7,
-	0x3f, 0x2a, 0xd2, 0xd9, 0xb8, 0x22, 0x9f, 0x8f, 0x2b, 0xf2, 0xf7, 0x71, 0x45, 0x7e, 0x77, 0x51,
-	0x91, 0xce, 0x2f, 0x2a, 0xd2, 0xd7, 0x8b, 0x8a, 0xf4, 0xca, 0xf8, 0xfb, 0x6e, 0xfe, 0x76, 0xb1,
-	0x74, 0xb2, 0xe1, 0xbd, 0xf0, 0xf0, 0x67, 0x00, 0x00, 0x00, 0xff, 0xff, 0x68, 0xbd, 0xa0, 0x43,
-	0x74, 0x06, 0x00, 0x00,
+	// 812 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xcf, 0x6f, 0xe3, 0x44,
+	0x14, 0xb6, 0x63, 0x37, 0x3f, 0x9e, 0xb3, 0xe0, 0x1d, 0xb6, 0x50, 0x22, 0x11, 0x9b, 0x50, 0xad,
+	0x4a, 0x56, 0x72, 0x50, 0x10, 0x12, 0xea, 0x6d, 0xb3, 0x05, 0x14, 0x45, 0x08, 0xe4, 0x66, 0x41,
+	0xe2, 0x12, 0x39, 0xf6, 0xe0, 0xb5, 0x9a, 0x1d, 0x5b, 0x33, 0x93, 0x56, 0xb9, 0x71, 0x44, 0x39,
+	0x21, 0x21, 0x21, 0x2e, 0x91, 0xf6, 0x0a, 0x57, 0x4e, 0xfc, 0x07, 0x7b, 0xec, 0x91, 0x03, 0x8a,
+	0x20, 0xfd, 0x2f, 0x7a, 0x42, 0x33, 0xe3, 0xc6, 0xdb, 0x80, 0x50, 0x82, 0xd8, 0x4b, 0x32, 0x7e,
+	0xef, 0x7d, 0xdf, 0xbc, 0xef, 0x9b, 0x37, 0x36, 0xbc, 0x95, 0xd1, 0x34, 0xc4, 0x8c, 0xa5, 0xb4,
+	0xc3, 0x83, 0xf1, 0x04, 0x67, 0x63, 0xf5, 0xef, 0x65, 0x34, 0xe5, 0x29, 0x3a, 0xcc, 0x12, 0x12,
+	0x87, 0x41, 0xe6, 0xf1, 0xe4, 0xeb, 0x49, 0x7a, 0xe1, 0x85, 0x51, 0xe8, 0xad, 0x11, 0x5e, 0x8e,
+	0x68, 0xdc, 0x8b, 0xd3, 0x38, 0x95, 0x80, 0x8e, 0x58, 0x29, 0x6c, 0xeb, 0x17, 0x1d, 0xcc, 0xd3,
+	0x2c, 0x20, 0xe8, 0x31, 0x54, 0x65, 0xe5, 0x28, 0x89, 0x0e, 0x74, 0x57, 0x3f, 0x32, 0x7a, 0xc7,
+	0xab, 0xa5, 0x53, 0x19, 0x8a, 0x58, 0xff, 0xe4, 0x7a, 0xe9, 0x3c, 0x88, 0x13, 0xfe, 0x64, 0x3a,
+	0xf6, 0xc2, 0xf4, 0x69, 0x27, 0xdf, 0xb0, 0xa3, 0x36, 0xec, 0x84, 0x51, 0xd8, 0x79, 0x9a, 0x46,
+	0x78, 0xe2, 0xe5, 0xe5, 0x7e, 0x45, 0x72, 0xf5, 0x23, 0x74, 0x08, 0x35, 0xc6, 0x03, 0xca, 0x47,
+	0x67, 0x78, 0x76, 0x50, 0x72, 0xf5, 0xa3, 0x7a, 0xaf, 0x72, 0xbd, 0x74, 0x8c, 0x01, 0x9e, 0xf9,
+	0x55, 0x99, 0x19, 0xe0, 0x19, 0x72, 0xa1, 0x82, 0x49, 0x24, 0x6b, 0x8c, 0xdb, 0x35, 0x65, 0x4c,
+	0xa2, 0x01, 0x9e, 0x1d, 0xd7, 0xbf, 0x7d, 0xe6, 0x68, 0x3f, 0x3e, 0x73, 0xb4, 0x6f, 0x7e, 0x77,
+	0xb5, 0xd6, 0xcf, 0x3a, 0xc0, 0xa3, 0x27, 0x38, 0x3c, 0xcb, 0xd2, 0x84, 0x70, 0xf4, 0x19, 0xdc,
+	0x09, 0xd7, 0x4f, 0x23, 0xce, 0xa4, 0x00, 0xb3, 0xd7, 0xbe, 0x5e, 0x3a, 0xf7, 0xb7, 0xea, 0x9a,
+	0xf9, 0xf5, 0x82, 0x60, 0xc8, 0xd0, 0x00, 0x2c, 0x8a, 0x59, 0x3a, 0x39, 0xc7, 0x91, 0xa0, 0x2b,
+	0xed, 0x4c, 0x07, 0x37, 0xf0, 0x21, 0x6b, 0xfd, 0x6a, 0xc2, 0xde, 0x29, 0x0f, 0x38, 0x43, 0x6f,
+	0x43, 0x9d, 0xe2, 0x38, 0x49, 0xc9, 0x28, 0x4c, 0xa7, 0x84, 0xab, 0x36, 0x7d, 0x4b, 0xc5, 0x1e,
+	0x89, 0x10, 0xea, 0x03, 0x84, 0x53, 0x4a, 0xb1, 0xd2, 0xb1, 0xfb, 0xc6, 0xb5, 0x1c, 0x3d, 0x64,
+	0x88, 0xc3, 0x5d, 0xc6, 0x83, 0x18, 0x8f, 0x0a, 0x69, 0xec, 0xc0, 0x70, 0x8d, 0x23, 0xab, 0xfb,
+	0xd0, 0xdb, 0x66, 0x64, 0x3c, 0xd9, 0xb5, 0xf8, 0x8d, 0x71, 0xe1, 0x36, 0xfb, 0x88, 0x70, 0x3a,
+	0xeb, 0x99, 0xcf, 0x97, 0x8e, 0xe6, 0xdb, 0x6c, 0x23, 0x29, 0x04, 0x8c, 0x03, 0x4a, 0x13, 0x4c,
+	0x85, 0x00, 0x73, 0x77, 0x01, 0x39, 0x7a, 0xc8, 0x90, 0x03, 0x16, 0x3e, 0x17, 0x4e, 0x28, 0xb7,
+	0xf6, 0xa4, 0x5b, 0x20, 0x43, 0xca, 0xac, 0x04, 0x50, 0xee, 0xe7, 0x05, 0x4d, 0x38, 0x1e, 0x31,
+	0xd1, 0xef, 0x41, 0x59, 0x4a, 0xfc, 0x60, 0x3b, 0x89, 0xbe, 0xc4, 0x7f, 0x29, 0xe0, 0x42, 0xed,
+	0x8d, 0x2c, 0x7a, 0x3b, 0xcc, 0x1a, 0x53, 0xd8, 0xff, 0x47, 0x1f, 0x90, 0x0d, 0x86, 0x18, 0x5b,
+	0x71, 0x94, 0x35, 0x5f, 0x2c, 0xd1, 0xc7, 0xb0, 0x77, 0x1e, 0x4c, 0xa6, 0x58, 0x9e, 0x9e, 0xd5,
+	0x7d, 0x6f, 0xbb, 0x46, 0x0a, 0x62, 0x5f, 0xc1, 0x8f, 0x4b, 0x1f, 0xea, 0xad, 0xef, 0x0d, 0xb0,
+	0xe4, 0x9d, 0x12, 0x5d, 0x4c, 0xd9, 0xcb, 0xba, 0xa5, 0x27, 0x60, 0xb2, 0x2c, 0x20, 0xd2, 0x62,
+	0xab, 0xdb, 0xde, 0x72, 0x3a, 0xb2, 0x80, 0xe4, 0x7e, 0x49, 0xb4, 0x10, 0x2e, 0x4e, 0x40, 0x09,
+	0x7f, 0x65, 0x5b, 0xe1, 0x6b, 0x79, 0xd8, 0x57, 0x70, 0xf4, 0x05, 0x40, 0x31, 0xb2, 0xf2, 0x85,
+	0xf0, 0x1f, 0x5c, 0xcc, 0x3b, 0x7b, 0x81, 0x09, 0x7d, 0xa2, 0xfa, 0x53, 0x53, 0x69, 0x75, 0x1f,
+	0xec, 0x70, 0x09, 0x72, 0x36, 0x85, 0x6f, 0xfd, 0xa4, 0xc3, 0xab, 0x1b, 0x83, 0x83, 0xde, 0x85,
+	0x5a, 0x3e, 0x8b, 0xf9, 0xd1, 0x98, 0xbd, 0xfa, 0x6a, 0xe9, 0x54, 0x55, 0x5d, 0xff, 0xc4, 0xaf,
+	0xaa, 0xf4, 0xff, 0xf7, 0x4e, 0x44, 0xef, 0xc0, 0x1d, 0x31, 0xf7, 0x1c, 0x93, 0xd1, 0x78, 0xc6,
+	0x71, 0x7e, 0xdb, 0xfc, 0x7a, 0x1e, 0xec, 0x89, 0x58, 0xfb, 0x87, 0x12, 0x40, 0x61, 0x31, 0x6a,
+	0x41, 0xe5, 0x31, 0x39, 0x23, 0xe9, 0x05, 0xb1, 0xb5, 0xc6, 0xfe, 0x7c, 0xe1, 0xde, 0x2d, 0x92,
+	0x79, 0x02, 0xb9, 0x50, 0x7e, 0x38, 0x66, 0x98, 0x70, 0x5b, 0x6f, 0xdc, 0x9b, 0x2f, 0x5c, 0xbb,
+	0x28, 0x51, 0x71, 0x74, 0x1f, 0x6a, 0x9f, 0x53, 0x9c, 0x05, 0x34, 0x21, 0xb1, 0x5d, 0x6a, 0xbc,
+	0x31, 0x5f, 0xb8, 0xaf, 0x15, 0x45, 0xeb, 0x14, 0x3a, 0x84, 0xaa, 0x7a, 0xc0, 0x91, 0x6d, 0x34,
+	0x5e, 0x9f, 0x2f, 0x5c, 0xb4, 0x59, 0x86, 0x23, 0xd4, 0x06, 0xcb, 0xc7, 0xd9, 0x24, 0x09, 0x03,
+	0x2e, 0xf8, 0xcc, 0xc6, 0x9b, 0xf3, 0x85, 0xbb, 0xff, 0xc2, 0x5c, 0x14, 0x49, 0xc1, 0x78, 0xca,
+	0xd3, 0x4c, 0x9c, 0x9c, 0xbd, 0xb7, 0xc9, 0x78, 0x93, 0x11, 0x2a, 0xe5, 0x1a, 0x47, 0x76, 0x79,
+	0x53, 0x65, 0x9e, 0xe8, 0x7d, 0x7a, 0xf9, 0x67, 0x53, 0x7b, 0xbe, 0x6a, 0xea, 0x97, 0xab, 0xa6,
+	0xfe, 0xc7, 0xaa, 0xa9, 0x7f, 0x77, 0xd5, 0xd4, 0x2e, 0xaf, 0x9a, 0xda, 0x6f, 0x57, 0x4d, 0xed,
+	0xab, 0xce, 0xbf, 0xdf, 0xa3, 0xbf, 0x7d, 0x90, 0xc7, 0x65, 0xf9, 0x3d, 0x7d, 0xff, 0xaf, 0x00,
+	0x00, 0x00, 0xff, 0xff, 0x35, 0xf9, 0x16, 0x5d, 0xac, 0x07, 0x00, 0x00,
 }
 
 func (m *Span) Marshal() (dAtA []byte, err error) {
@@ -473,6 +567,25 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.RegionWriteStats) > 0 {
+		for iNdEx := len(m.RegionWriteStats) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RegionWriteStats[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTable(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if m.EventCount != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.EventCount))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.BarrierTs != 0 {
 		i = encodeVarintTable(dAtA, i, uint64(m.BarrierTs))
 		i--
@@ -578,6 +691,53 @@ func (m *TableStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *RegionWriteStat) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RegionWriteStat) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RegionWriteStat) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.WrittenBytes != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.WrittenBytes))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.EndKey) > 0 {
+		i -= len(m.EndKey)
+		copy(dAtA[i:], m.EndKey)
+		i = encodeVarintTable(dAtA, i, uint64(len(m.EndKey)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.StartKey) > 0 {
+		i -= len(m.StartKey)
+		copy(dAtA[i:], m.StartKey)
+		i = encodeVarintTable(dAtA, i, uint64(len(m.StartKey)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.RegionID != 0 {
+		i = encodeVarintTable(dAtA, i, uint64(m.RegionID))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTable(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTable(v)
 	base := offset
@@ -648,6 +808,15 @@ func (m *Stats) Size() (n int) {
 	if m.BarrierTs != 0 {
 		n += 1 + sovTable(uint64(m.BarrierTs))
 	}
+	if m.EventCount != 0 {
+		n += 1 + sovTable(uint64(m.EventCount))
+	}
+	if len(m.RegionWriteStats) > 0 {
+		for _, e := range m.RegionWriteStats {
+			l = e.Size()
+			n += 1 + l + sovTable(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -672,6 +841,29 @@ func (m *TableStatus) Size() (n int) {
 	return n
 }
 
+func (m *RegionWriteStat) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.RegionID != 0 {
+		n += 1 + sovTable(uint64(m.RegionID))
+	}
+	l = len(m.StartKey)
+	if l > 0 {
+		n += 1 + l + sovTable(uint64(l))
+	}
+	l = len(m.EndKey)
+	if l > 0 {
+		n += 1 + l + sovTable(uint64(l))
+	}
+	if m.WrittenBytes != 0 {
+		n += 1 + sovTable(uint64(m.WrittenBytes))
+	}
+	return n
+}
+
 func sovTable(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1118,6 +1310,59 @@ func (m *Stats) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventCount", wireType)
+			}
+			m.EventCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EventCount |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionWriteStats", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTable
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTable
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RegionWriteStats = append(m.RegionWriteStats, RegionWriteStat{})
+			if err := m.RegionWriteStats[len(m.RegionWriteStats)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTable(dAtA[iNdEx:])
@@ -1326,6 +1571,162 @@ func (m *TableStatus) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *RegionWriteStat) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTable
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegionWriteStat: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegionWriteStat: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegionID", wireType)
+			}
+			m.RegionID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RegionID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTable
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTable
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StartKey = append(m.StartKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.StartKey == nil {
+				m.StartKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTable
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTable
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EndKey = append(m.EndKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.EndKey == nil {
+				m.EndKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WrittenBytes", wireType)
+			}
+			m.WrittenBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTable
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WrittenBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTable(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTable
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTable(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
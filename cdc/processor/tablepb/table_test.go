@@ -106,3 +106,27 @@ func TestSpanEq(t *testing.T) {
 	require.False(t, a.Eq(d))
 	require.True(t, d.Eq(d))
 }
+
+func TestStatsRegionWriteStatsMarshal(t *testing.T) {
+	t.Parallel()
+
+	stats := Stats{
+		RegionCount: 2,
+		EventCount:  3,
+		RegionWriteStats: []RegionWriteStat{{
+			RegionID:     1,
+			StartKey:     decode("7480000000000009ff89000000f8"),
+			EndKey:       decode("748000000000000dffaa5f6980ff"),
+			WrittenBytes: 1024,
+		}, {
+			RegionID:     2,
+			StartKey:     decode("748000000000000dffaa5f6980ff"),
+			WrittenBytes: 1,
+		}},
+	}
+	b, err := stats.Marshal()
+	require.Nil(t, err)
+	var actual Stats
+	require.Nil(t, actual.Unmarshal(b))
+	require.Equal(t, stats, actual)
+}
@@ -69,6 +69,13 @@ var (
 			Name:      "discarded_ddl_count",
 			Help:      "The total count of ddl job that are discarded in ddl puller.",
 		}, []string{"namespace", "changefeed"})
+	initialSnapshotRowsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "puller",
+			Name:      "initial_snapshot_rows",
+			Help:      "The total count of rows scanned from the initial snapshot.",
+		}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file
@@ -80,4 +87,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(outputChanSizeHistogram)
 	registry.MustRegister(eventChanSizeHistogram)
 	registry.MustRegister(discardedDDLCounter)
+	registry.MustRegister(initialSnapshotRowsCounter)
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -40,6 +41,9 @@ import (
 const (
 	defaultPullerEventChanSize  = 128
 	defaultPullerOutputChanSize = 128
+	// defaultSnapshotBatchSize is the max number of the rows in a transaction
+	// of the initial snapshot.
+	defaultSnapshotBatchSize = 1024
 )
 
 // Stats of a puller.
@@ -49,6 +53,10 @@ type Stats struct {
 	ResolvedTsIngress   model.Ts
 	CheckpointTsEgress  model.Ts
 	ResolvedTsEgress    model.Ts
+	// EventCount is the number of row changed events the puller has sent.
+	EventCount uint64
+	// RegionWriteStats is the bytes written to each captured region.
+	RegionWriteStats []kv.RegionWriteStat
 }
 
 // Puller pull data from tikv and push changes into a buffer.
@@ -63,13 +71,21 @@ type Puller interface {
 type pullerImpl struct {
 	kvCli     kv.CDCKVClient
 	kvStorage tikv.Storage
-	spans     []regionspan.ComparableSpan
-	outputCh  chan *model.RawKVEntry
-	tsTracker frontier.Frontier
+	// snapStorage is used to scan the initial snapshot.
+	snapStorage tidbkv.Storage
+	spans       []regionspan.ComparableSpan
+	outputCh    chan *model.RawKVEntry
+	tsTracker   frontier.Frontier
 	// The commit ts of the latest raw kv event that puller has sent.
 	checkpointTs uint64
 	// The latest resolved ts that puller has sent.
 	resolvedTs uint64
+	// The ts of the initial snapshot, zero if the snapshot is not needed.
+	snapshotTs uint64
+	// The max number of the rows in a transaction of the initial snapshot.
+	snapshotBatchSize int
+	// The number of row changed events that puller has sent.
+	eventCount uint64
 
 	changefeed model.ChangeFeedID
 	tableID    model.TableID
@@ -77,6 +93,9 @@ type pullerImpl struct {
 }
 
 // New create a new Puller fetch event start from checkpointTs and put into buf.
+// If snapshotTs is greater than checkpointTs, the puller scans the snapshot of
+// the spans at snapshotTs and outputs the rows as the puts committed at
+// snapshotTs before fetching the events after snapshotTs.
 func New(ctx context.Context,
 	pdCli pd.Client,
 	grpcPool kv.GrpcPool,
@@ -90,6 +109,7 @@ func New(ctx context.Context,
 	tableID model.TableID,
 	tableName string,
 	filterLoop bool,
+	snapshotTs uint64,
 ) Puller {
 	tikvStorage, ok := kvStorage.(tikv.Storage)
 	if !ok {
@@ -115,16 +135,19 @@ func New(ctx context.Context,
 	kvCli := kv.NewCDCKVClient(
 		ctx, pdCli, grpcPool, regionCache, pdClock, cfg, changefeed, tableID, tableName, filterLoop)
 	p := &pullerImpl{
-		kvCli:        kvCli,
-		kvStorage:    tikvStorage,
-		checkpointTs: checkpointTs,
-		spans:        comparableSpans,
-		outputCh:     make(chan *model.RawKVEntry, defaultPullerOutputChanSize),
-		tsTracker:    tsTracker,
-		resolvedTs:   checkpointTs,
-		changefeed:   changefeed,
-		tableID:      tableID,
-		tableName:    tableName,
+		kvCli:             kvCli,
+		kvStorage:         tikvStorage,
+		snapStorage:       kvStorage,
+		checkpointTs:      checkpointTs,
+		snapshotTs:        snapshotTs,
+		spans:             comparableSpans,
+		snapshotBatchSize: defaultSnapshotBatchSize,
+		outputCh:          make(chan *model.RawKVEntry, defaultPullerOutputChanSize),
+		tsTracker:         tsTracker,
+		resolvedTs:        checkpointTs,
+		changefeed:        changefeed,
+		tableID:           tableID,
+		tableName:         tableName,
 	}
 	return p
 }
@@ -133,6 +156,12 @@ func New(ctx context.Context,
 func (p *pullerImpl) Run(ctx context.Context) error {
 	g, ctx := errgroup.WithContext(ctx)
 
+	if p.snapshotTs > p.checkpointTs {
+		if err := p.scanSnapshot(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	checkpointTs := p.checkpointTs
 	eventCh := make(chan model.RegionFeedEvent, defaultPullerEventChanSize)
 
@@ -163,9 +192,10 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 		pullerResolvedTsGauge.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID)
 		txnCollectCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "kv")
 		txnCollectCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID, "resolved")
+		initialSnapshotRowsCounter.DeleteLabelValues(p.changefeed.Namespace, p.changefeed.ID)
 	}()
 
-	lastResolvedTs := p.checkpointTs
+	lastResolvedTs := checkpointTs
 	g.Go(func() error {
 		metricsTicker := time.NewTicker(15 * time.Second)
 		defer metricsTicker.Stop()
@@ -191,6 +221,9 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 			case <-ctx.Done():
 				return errors.Trace(ctx.Err())
 			case p.outputCh <- raw:
+				if raw.OpType != model.OpTypeResolved {
+					atomic.AddUint64(&p.eventCount, 1)
+				}
 				if atomic.LoadUint64(&p.checkpointTs) < commitTs {
 					atomic.StoreUint64(&p.checkpointTs, commitTs)
 				}
@@ -269,6 +302,103 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 	return g.Wait()
 }
 
+// scanSnapshot outputs all the rows of the spans at snapshotTs as the puts
+// committed at snapshotTs, and then moves the checkpoint to snapshotTs.
+// The rows are scanned in batches of at most snapshotBatchSize rows, and each
+// batch is output as a separate transaction with a distinct start ts, so that
+// the whole table is never handled as one transaction by the sinks.
+func (p *pullerImpl) scanSnapshot(ctx context.Context) error {
+	start := time.Now()
+	metricSnapshotRows := initialSnapshotRowsCounter.
+		WithLabelValues(p.changefeed.Namespace, p.changefeed.ID)
+	snap := p.snapStorage.GetSnapshot(tidbkv.NewVersion(p.snapshotTs))
+	rows, batches := 0, 0
+	for _, span := range p.spans {
+		startKey, endKey, err := decodeSpan(span)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for {
+			n, nextKey, err := p.scanSnapshotBatch(
+				ctx, snap, startKey, endKey, p.snapshotTs-1-uint64(batches))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if n > 0 {
+				rows += n
+				batches++
+				metricSnapshotRows.Add(float64(n))
+			}
+			if nextKey == nil {
+				break
+			}
+			startKey = nextKey
+		}
+	}
+	atomic.StoreUint64(&p.checkpointTs, p.snapshotTs)
+	atomic.StoreUint64(&p.resolvedTs, p.snapshotTs)
+	log.Info("puller finishes scanning the initial snapshot",
+		zap.String("namespace", p.changefeed.Namespace),
+		zap.String("changefeed", p.changefeed.ID),
+		zap.Int64("tableID", p.tableID),
+		zap.String("tableName", p.tableName),
+		zap.Uint64("snapshotTs", p.snapshotTs),
+		zap.Int("rows", rows),
+		zap.Int("batches", batches),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// scanSnapshotBatch outputs at most snapshotBatchSize rows in [startKey, endKey)
+// with the start ts. It returns the key to scan the next batch from, or nil if
+// all the rows in the range have been scanned.
+func (p *pullerImpl) scanSnapshotBatch(
+	ctx context.Context, snap tidbkv.Snapshot, startKey, endKey []byte, startTs uint64,
+) (rows int, nextKey []byte, err error) {
+	iter, err := snap.Iter(startKey, endKey)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	defer iter.Close()
+	for iter.Valid() {
+		if rows >= p.snapshotBatchSize {
+			return rows, append([]byte{}, iter.Key()...), nil
+		}
+		raw := &model.RawKVEntry{
+			OpType:  model.OpTypePut,
+			Key:     append([]byte{}, iter.Key()...),
+			Value:   append([]byte{}, iter.Value()...),
+			StartTs: startTs,
+			CRTs:    p.snapshotTs,
+		}
+		select {
+		case <-ctx.Done():
+			return rows, nil, errors.Trace(ctx.Err())
+		case p.outputCh <- raw:
+		}
+		rows++
+		if err := iter.Next(); err != nil {
+			return rows, nil, errors.Trace(err)
+		}
+	}
+	return rows, nil, nil
+}
+
+// decodeSpan decodes the memcomparable keys of the span to the raw keys.
+func decodeSpan(span regionspan.ComparableSpan) (startKey, endKey []byte, err error) {
+	if len(span.Start) != 0 {
+		if _, startKey, err = codec.DecodeBytes(span.Start, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(span.End) != 0 {
+		if _, endKey, err = codec.DecodeBytes(span.End, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	return startKey, endKey, nil
+}
+
 func (p *pullerImpl) GetResolvedTs() uint64 {
 	return atomic.LoadUint64(&p.resolvedTs)
 }
@@ -284,5 +414,28 @@ func (p *pullerImpl) Stats() Stats {
 		CheckpointTsIngress: p.kvCli.CommitTs(),
 		ResolvedTsEgress:    atomic.LoadUint64(&p.resolvedTs),
 		CheckpointTsEgress:  atomic.LoadUint64(&p.checkpointTs),
+		EventCount:          atomic.LoadUint64(&p.eventCount),
+		RegionWriteStats:    p.kvCli.RegionWriteStats(),
+	}
+}
+
+// ToRegionWriteStats converts region write stats to the table stats format,
+// regions that have not been written are omitted.
+func ToRegionWriteStats(stats []kv.RegionWriteStat) []tablepb.RegionWriteStat {
+	if len(stats) == 0 {
+		return nil
+	}
+	res := make([]tablepb.RegionWriteStat, 0, len(stats))
+	for _, stat := range stats {
+		if stat.WrittenBytes == 0 {
+			continue
+		}
+		res = append(res, tablepb.RegionWriteStat{
+			RegionID:     stat.RegionID,
+			StartKey:     stat.Span.Start,
+			EndKey:       stat.Span.End,
+			WrittenBytes: stat.WrittenBytes,
+		})
 	}
+	return res
 }
@@ -115,10 +115,22 @@ func newPullerForTest(
 	spans []tablepb.Span,
 	checkpointTs uint64,
 ) (*mockInjectedPuller, context.CancelFunc, *sync.WaitGroup, tidbkv.Storage) {
-	var wg sync.WaitGroup
-	ctx, cancel := context.WithCancel(context.Background())
 	store, err := mockstore.NewMockStore()
 	require.Nil(t, err)
+	plr, cancel, wg := newPullerWithSnapshotForTest(t, store, spans, checkpointTs, 0, defaultSnapshotBatchSize)
+	return plr, cancel, wg, store
+}
+
+func newPullerWithSnapshotForTest(
+	t *testing.T,
+	store tidbkv.Storage,
+	spans []tablepb.Span,
+	checkpointTs uint64,
+	snapshotTs uint64,
+	snapshotBatchSize int,
+) (*mockInjectedPuller, context.CancelFunc, *sync.WaitGroup) {
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(context.Background())
 	backupNewCDCKVClient := kv.NewCDCKVClient
 	kv.NewCDCKVClient = newMockCDCKVClient
 	defer func() {
@@ -132,7 +144,8 @@ func newPullerForTest(
 	plr := New(
 		ctx, pdCli, grpcPool, regionCache, store, pdutil.NewClock4Test(),
 		checkpointTs, spans, config.GetDefaultServerConfig().KVClient,
-		model.DefaultChangeFeedID("changefeed-id-test"), 0, "table-test", false)
+		model.DefaultChangeFeedID("changefeed-id-test"), 0, "table-test", false, snapshotTs)
+	plr.(*pullerImpl).snapshotBatchSize = snapshotBatchSize
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -141,12 +154,11 @@ func newPullerForTest(
 			require.Equal(t, context.Canceled, errors.Cause(err))
 		}
 	}()
-	require.Nil(t, err)
 	mockPlr := &mockInjectedPuller{
 		Puller: plr,
 		cli:    plr.(*pullerImpl).kvCli.(*mockCDCKVClient),
 	}
-	return mockPlr, cancel, &wg, store
+	return mockPlr, cancel, &wg
 }
 
 func TestPullerResolvedForward(t *testing.T) {
@@ -236,3 +248,51 @@ func TestPullerRawKV(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestPullerInitialSnapshot(t *testing.T) {
+	store, err := mockstore.NewMockStore()
+	require.Nil(t, err)
+	txn, err := store.Begin()
+	require.Nil(t, err)
+	for _, key := range []string{"a", "c", "d", "d1", "d2", "e"} {
+		require.Nil(t, txn.Set([]byte(key), []byte("value-"+key)))
+	}
+	require.Nil(t, txn.Commit(context.Background()))
+	snapshotTs, err := store.CurrentVersion(tidbkv.GlobalTxnScope)
+	require.Nil(t, err)
+
+	spans := []tablepb.Span{
+		{
+			StartKey: regionspan.ToComparableKey([]byte("c")),
+			EndKey:   regionspan.ToComparableKey([]byte("e")),
+		},
+	}
+	plr, cancel, wg := newPullerWithSnapshotForTest(
+		t, store, spans, snapshotTs.Ver-1, snapshotTs.Ver, 2)
+
+	plr.cli.Returns(model.RegionFeedEvent{
+		Val: &model.RawKVEntry{
+			OpType: model.OpTypePut,
+			Key:    []byte("d"),
+			Value:  []byte("new-value"),
+			CRTs:   snapshotTs.Ver + 1,
+		},
+	})
+	var ev *model.RawKVEntry
+	// the snapshot is output in the transactions of at most 2 rows.
+	for i, key := range []string{"c", "d", "d1", "d2"} {
+		ev = <-plr.Output()
+		require.Equal(t, model.OpTypePut, ev.OpType)
+		require.Equal(t, []byte(key), ev.Key)
+		require.Equal(t, []byte("value-"+key), ev.Value)
+		require.Equal(t, snapshotTs.Ver, ev.CRTs)
+		require.Equal(t, snapshotTs.Ver-1-uint64(i/2), ev.StartTs)
+	}
+	ev = <-plr.Output()
+	require.Equal(t, []byte("new-value"), ev.Value)
+	require.Equal(t, snapshotTs.Ver+1, ev.CRTs)
+
+	store.Close()
+	cancel()
+	wg.Wait()
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/pingcap/errors"
-	backuppb "github.com/pingcap/kvproto/pkg/brpb"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -41,27 +40,21 @@ const (
 	RedoLogFileFormatV2 = "%s_%s_%s_%s_%d_%s%s"
 )
 
-// InitS3storage init a storage used for s3,
-// s3URI should be like s3URI="s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/"
-var InitS3storage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
+// InitExternalStorage init an external storage used for redo logs,
+// extStorageURI should be like extStorageURI="s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/",
+// or "gcs://logbucket/test-changefeed", "azblob://logbucket/test-changefeed".
+var InitExternalStorage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
 	if len(uri.Host) == 0 {
-		return nil, cerror.WrapChangefeedUnretryableErr(cerror.ErrS3StorageInitialize, errors.Errorf("please specify the bucket for s3 in %v", uri))
+		return nil, cerror.WrapChangefeedUnretryableErr(cerror.ErrS3StorageInitialize,
+			errors.Errorf("please specify the bucket for %s in %v", uri.Scheme, uri))
 	}
 
-	prefix := strings.Trim(uri.Path, "/")
-	s3 := &backuppb.S3{Bucket: uri.Host, Prefix: prefix}
-	options := &storage.BackendOptions{}
-	storage.ExtractQueryParameters(&uri, &options.S3)
-	if err := options.S3.Apply(s3); err != nil {
+	// br sets ForcePathStyle to true for s3 by default in ParseBackend.
+	backend, err := storage.ParseBackend(uri.String(), nil)
+	if err != nil {
 		return nil, cerror.WrapChangefeedUnretryableErr(cerror.ErrS3StorageInitialize, err)
 	}
-
-	// we should set this to true, since br set it by default in parseBackend
-	s3.ForcePathStyle = true
-	backend := &backuppb.StorageBackend{
-		Backend: &backuppb.StorageBackend_S3{S3: s3},
-	}
-	s3storage, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{
+	extStorage, err := storage.New(ctx, backend, &storage.ExternalStorageOptions{
 		SendCredentials: false,
 		HTTPClient:      nil,
 		S3Retryer:       DefaultS3Retryer(),
@@ -70,7 +63,7 @@ var InitS3storage = func(ctx context.Context, uri url.URL) (storage.ExternalStor
 		return nil, cerror.WrapChangefeedUnretryableErr(cerror.ErrS3StorageInitialize, err)
 	}
 
-	return s3storage, nil
+	return extStorage, nil
 }
 
 // logFormat2ParseFormat converts redo log file name format to the space separated
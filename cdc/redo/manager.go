@@ -60,6 +60,10 @@ const (
 	consistentStorageLocal     consistentStorage = "local"
 	consistentStorageNFS       consistentStorage = "nfs"
 	consistentStorageS3        consistentStorage = "s3"
+	consistentStorageGCS       consistentStorage = "gcs"
+	consistentStorageGS        consistentStorage = "gs"
+	consistentStorageAzblob    consistentStorage = "azblob"
+	consistentStorageAzure     consistentStorage = "azure"
 	consistentStorageBlackhole consistentStorage = "blackhole"
 )
 
@@ -76,11 +80,10 @@ func IsValidConsistentLevel(level string) bool {
 // IsValidConsistentStorage checks whether a give consistent storage is valid
 func IsValidConsistentStorage(storage string) bool {
 	switch consistentStorage(storage) {
-	case consistentStorageLocal, consistentStorageNFS,
-		consistentStorageS3, consistentStorageBlackhole:
+	case consistentStorageLocal, consistentStorageNFS, consistentStorageBlackhole:
 		return true
 	default:
-		return false
+		return IsExternalStorage(storage)
 	}
 }
 
@@ -89,9 +92,16 @@ func IsConsistentEnabled(level string) bool {
 	return IsValidConsistentLevel(level) && ConsistentLevelType(level) != ConsistentLevelNone
 }
 
-// IsS3StorageEnabled returns whether s3 storage is enabled
-func IsS3StorageEnabled(storage string) bool {
-	return consistentStorage(storage) == consistentStorageS3
+// IsExternalStorage returns whether the consistent storage is an external
+// storage, such as s3, gcs and azure blob storage.
+func IsExternalStorage(storage string) bool {
+	switch consistentStorage(storage) {
+	case consistentStorageS3, consistentStorageGCS, consistentStorageGS,
+		consistentStorageAzblob, consistentStorageAzure:
+		return true
+	default:
+		return false
+	}
 }
 
 // LogManager defines an interface that is used to manage redo log
@@ -229,13 +239,14 @@ func NewManager(ctx context.Context, cfg *config.ConsistentConfig, opts *Manager
 			WithLabelValues(changeFeedID.Namespace, changeFeedID.ID),
 	}
 
-	switch m.storageType {
-	case consistentStorageBlackhole:
+	switch {
+	case m.storageType == consistentStorageBlackhole:
 		m.writer = writer.NewBlackHoleWriter()
-	case consistentStorageLocal, consistentStorageNFS, consistentStorageS3:
+	case m.storageType == consistentStorageLocal, m.storageType == consistentStorageNFS,
+		IsExternalStorage(string(m.storageType)):
 		globalConf := config.GetGlobalServerConfig()
 		// When an external storage such S3 is used, we use redoDir as a temporary dir to store redo logs
-		// before we flush them to S3.
+		// before we flush them to the external storage.
 		var redoDir string
 		if changeFeedID.Namespace == model.DefaultNamespace {
 			redoDir = filepath.Join(globalConf.DataDir,
@@ -252,20 +263,20 @@ func NewManager(ctx context.Context, cfg *config.ConsistentConfig, opts *Manager
 		}
 
 		writerCfg := &writer.LogWriterConfig{
-			Dir:               redoDir,
-			CaptureID:         contextutil.CaptureAddrFromCtx(ctx),
-			ChangeFeedID:      changeFeedID,
-			CreateTime:        time.Now(),
-			MaxLogSize:        cfg.MaxLogSize,
-			FlushIntervalInMs: cfg.FlushIntervalInMs,
-			S3Storage:         m.storageType == consistentStorageS3,
+			Dir:                redoDir,
+			CaptureID:          contextutil.CaptureAddrFromCtx(ctx),
+			ChangeFeedID:       changeFeedID,
+			CreateTime:         time.Now(),
+			MaxLogSize:         cfg.MaxLogSize,
+			FlushIntervalInMs:  cfg.FlushIntervalInMs,
+			UseExternalStorage: IsExternalStorage(string(m.storageType)),
 
 			EmitMeta:      m.opts.EmitMeta,
 			EmitRowEvents: m.opts.EmitRowEvents,
 			EmitDDLEvents: m.opts.EmitDDLEvents,
 		}
-		if writerCfg.S3Storage {
-			writerCfg.S3URI = *uri
+		if writerCfg.UseExternalStorage {
+			writerCfg.ExtStorageURI = *uri
 		}
 		writer, err := writer.NewLogWriter(ctx, writerCfg)
 		if err != nil {
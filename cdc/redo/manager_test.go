@@ -17,13 +17,17 @@ import (
 	"context"
 	"math"
 	"math/rand"
+	"net/url"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
 	"github.com/pingcap/tiflow/pkg/chann"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -65,6 +69,10 @@ func TestConsistentConfig(t *testing.T) {
 		{"local", true},
 		{"nfs", true},
 		{"s3", true},
+		{"gcs", true},
+		{"gs", true},
+		{"azblob", true},
+		{"azure", true},
 		{"blackhole", true},
 		{"Local", false},
 		{"", false},
@@ -73,17 +81,21 @@ func TestConsistentConfig(t *testing.T) {
 		require.Equal(t, sc.valid, IsValidConsistentStorage(sc.storage))
 	}
 
-	s3StorageCases := []struct {
-		storage   string
-		s3Enabled bool
+	externalStorageCases := []struct {
+		storage  string
+		external bool
 	}{
 		{"local", false},
 		{"nfs", false},
 		{"s3", true},
+		{"gcs", true},
+		{"gs", true},
+		{"azblob", true},
+		{"azure", true},
 		{"blackhole", false},
 	}
-	for _, sc := range s3StorageCases {
-		require.Equal(t, sc.s3Enabled, IsS3StorageEnabled(sc.storage))
+	for _, sc := range externalStorageCases {
+		require.Equal(t, sc.external, IsExternalStorage(sc.storage))
 	}
 }
 
@@ -420,3 +432,89 @@ func TestReuseWritter(t *testing.T) {
 	err := mgrs[1].UpdateResolvedTs(context.Background(), 1, 1)
 	require.Error(t, err)
 }
+
+// TestManagerWithExternalStorage tests the redo logs can be written to and
+// read from gcs and azure blob storage, which are faked by a local storage.
+func TestManagerWithExternalStorage(t *testing.T) {
+	for _, storageURI := range []string{
+		"gcs://logbucket/test-changefeed",
+		"azblob://logbucket/test-changefeed",
+	} {
+		testManagerWithExternalStorage(t, storageURI)
+	}
+}
+
+func testManagerWithExternalStorage(t *testing.T, storageURI string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = contextutil.PutChangefeedIDInCtx(ctx,
+		model.DefaultChangeFeedID("test-external-storage"))
+	ctx = contextutil.PutCaptureAddrInCtx(ctx, "127.0.0.1:8300")
+
+	extDir := t.TempDir()
+	origin := common.InitExternalStorage
+	defer func() {
+		common.InitExternalStorage = origin
+	}()
+	common.InitExternalStorage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
+		require.Equal(t, "logbucket", uri.Host)
+		return storage.NewLocalStorage(extDir)
+	}
+	// redo logs are written to the data dir before they are flushed to the
+	// external storage.
+	originConf := config.GetGlobalServerConfig()
+	defer config.StoreGlobalServerConfig(originConf)
+	conf := originConf.Clone()
+	conf.DataDir = t.TempDir()
+	config.StoreGlobalServerConfig(conf)
+
+	cfg := &config.ConsistentConfig{
+		Level:             string(ConsistentLevelEventual),
+		MaxLogSize:        64,
+		FlushIntervalInMs: 2000,
+		Storage:           storageURI,
+	}
+	errCh := make(chan error, 1)
+	logMgr, err := NewManager(ctx, cfg, newMockManagerOptions(errCh))
+	require.Nil(t, err)
+
+	tableID := model.TableID(53)
+	logMgr.AddTable(tableID, 100)
+	rows := []*model.RowChangedEvent{
+		{CommitTs: 120, Table: &model.TableName{TableID: tableID}},
+		{CommitTs: 125, Table: &model.TableName{TableID: tableID}},
+	}
+	err = logMgr.EmitRowChangedEvents(ctx, tableID, nil, rows...)
+	require.Nil(t, err)
+	err = logMgr.UpdateResolvedTs(ctx, tableID, 150)
+	require.Nil(t, err)
+	logMgr.UpdateMeta(100, 150)
+	require.Eventually(t, func() bool {
+		var checkpointTs, resolvedTs model.Ts
+		logMgr.GetFlushedMeta(&checkpointTs, &resolvedTs)
+		return logMgr.GetMinResolvedTs() == 150 && resolvedTs == 150
+	}, 10*time.Second, 100*time.Millisecond)
+	// close the writer to upload the ongoing log files.
+	require.Nil(t, logMgr.writer.Close())
+
+	uri, err := storage.ParseRawURL(storageURI)
+	require.Nil(t, err)
+	rd, err := NewRedoReader(ctx, uri.Scheme, &reader.LogReaderConfig{
+		Dir:                t.TempDir(),
+		UseExternalStorage: true,
+		ExtStorageURI:      *uri,
+	})
+	require.Nil(t, err)
+	checkpointTs, resolvedTs, err := rd.ReadMeta(ctx)
+	require.Nil(t, err)
+	require.Equal(t, uint64(100), checkpointTs)
+	require.Equal(t, uint64(150), resolvedTs)
+	err = rd.ResetReader(ctx, checkpointTs, resolvedTs)
+	require.Nil(t, err)
+	redoRows, err := rd.ReadNextLog(ctx, 10)
+	require.Nil(t, err)
+	require.Len(t, redoRows, len(rows))
+	for i, row := range redoRows {
+		require.Equal(t, rows[i].CommitTs, row.Row.CommitTs)
+	}
+}
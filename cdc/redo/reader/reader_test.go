@@ -45,26 +45,26 @@ func TestNewLogReader(t *testing.T) {
 
 	dir := t.TempDir()
 
-	s3URI, err := url.Parse("s3://logbucket/test-changefeed?endpoint=http://111/")
+	extStorageURI, err := url.Parse("s3://logbucket/test-changefeed?endpoint=http://111/")
 	require.Nil(t, err)
 
-	origin := common.InitS3storage
+	origin := common.InitExternalStorage
 	defer func() {
-		common.InitS3storage = origin
+		common.InitExternalStorage = origin
 	}()
 	controller := gomock.NewController(t)
 	mockStorage := mockstorage.NewMockExternalStorage(controller)
 	// no file to download
 	mockStorage.EXPECT().WalkDir(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-	common.InitS3storage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
+	common.InitExternalStorage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
 		return mockStorage, nil
 	}
 
 	// after init should rm the dir
 	_, err = NewLogReader(context.Background(), &LogReaderConfig{
-		S3Storage: true,
-		Dir:       dir,
-		S3URI:     *s3URI,
+		UseExternalStorage: true,
+		Dir:                dir,
+		ExtStorageURI:      *extStorageURI,
 	})
 	require.Nil(t, err)
 	_, err = os.Stat(dir)
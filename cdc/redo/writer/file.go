@@ -44,8 +44,8 @@ const (
 	// pageBytes is the alignment for flushing records to the backing Writer.
 	// It should be a multiple of the minimum sector size so that log can safely
 	// distinguish between torn writes and ordinary data corruption.
-	pageBytes        = 8 * common.MinSectorSize
-	defaultS3Timeout = 15 * time.Second
+	pageBytes                = 8 * common.MinSectorSize
+	defaultExtStorageTimeout = 15 * time.Second
 )
 
 var (
@@ -79,9 +79,9 @@ type FileWriterConfig struct {
 	FileType     string
 	CreateTime   time.Time
 	// MaxLogSize is the maximum size of log in megabyte, defaults to defaultMaxLogSize.
-	MaxLogSize int64
-	S3Storage  bool
-	S3URI      url.URL
+	MaxLogSize         int64
+	UseExternalStorage bool
+	ExtStorageURI      url.URL
 }
 
 // Option define the writerOptions
@@ -148,10 +148,10 @@ func NewWriter(ctx context.Context, cfg *FileWriterConfig, opts ...Option) (*Wri
 	if cfg.MaxLogSize == 0 {
 		cfg.MaxLogSize = defaultMaxLogSize
 	}
-	var s3storage storage.ExternalStorage
-	if cfg.S3Storage {
+	var extStorage storage.ExternalStorage
+	if cfg.UseExternalStorage {
 		var err error
-		s3storage, err = common.InitS3storage(ctx, cfg.S3URI)
+		extStorage, err = common.InitExternalStorage(ctx, cfg.ExtStorageURI)
 		if err != nil {
 			return nil, err
 		}
@@ -166,7 +166,7 @@ func NewWriter(ctx context.Context, cfg *FileWriterConfig, opts ...Option) (*Wri
 		cfg:       cfg,
 		op:        op,
 		uint64buf: make([]byte, 8),
-		storage:   s3storage,
+		storage:   extStorage,
 
 		metricFsyncDuration: common.RedoFsyncDurationHistogram.
 			WithLabelValues(cfg.ChangeFeedID.Namespace, cfg.ChangeFeedID.ID),
@@ -191,10 +191,10 @@ func NewWriter(ctx context.Context, cfg *FileWriterConfig, opts ...Option) (*Wri
 			errors.Annotatef(err, "can't make dir: %s for redo writing", cfg.Dir))
 	}
 
-	// if we use S3 as the remote storage, a file allocator can be leveraged to
+	// if we use an external storage as the remote storage, a file allocator can be leveraged to
 	// pre-allocate files for us.
 	// TODO: test whether this improvement can also be applied to NFS.
-	if cfg.S3Storage {
+	if cfg.UseExternalStorage {
 		w.allocator = fsutil.NewFileAllocator(cfg.Dir, cfg.FileType, defaultMaxLogSize)
 	}
 
@@ -317,7 +317,7 @@ func (w *Writer) close() error {
 		return err
 	}
 
-	if w.cfg.S3Storage {
+	if w.cfg.UseExternalStorage {
 		off, err := w.file.Seek(0, io.SeekCurrent)
 		if err != nil {
 			return err
@@ -353,13 +353,13 @@ func (w *Writer) close() error {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
 	}
 
-	// We only write content to S3 before closing the local file.
-	// By this way, we no longer need renaming object in S3.
-	if w.cfg.S3Storage {
-		ctx, cancel := context.WithTimeout(context.Background(), defaultS3Timeout)
+	// We only write content to the external storage before closing the local file.
+	// By this way, we no longer need renaming object in the external storage.
+	if w.cfg.UseExternalStorage {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultExtStorageTimeout)
 		defer cancel()
 
-		err = w.writeToS3(ctx, w.ongoingFilePath)
+		err = w.writeToExtStorage(ctx, w.ongoingFilePath)
 		if err != nil {
 			w.file.Close()
 			w.file = nil
@@ -478,8 +478,8 @@ func (w *Writer) GC(checkPointTs uint64) error {
 		return cerror.WrapError(cerror.ErrRedoFileOp, errs)
 	}
 
-	if w.cfg.S3Storage {
-		// since if fail delete in s3, do not block any path, so just log the error if any
+	if w.cfg.UseExternalStorage {
+		// since if fail delete in the external storage, do not block any path, so just log the error if any
 		go func() {
 			var errs error
 			for _, f := range remove {
@@ -488,7 +488,7 @@ func (w *Writer) GC(checkPointTs uint64) error {
 			}
 			if errs != nil {
 				errs = cerror.WrapError(cerror.ErrS3StorageAPI, errs)
-				log.Warn("delete redo log in s3 fail", zap.Error(errs))
+				log.Warn("delete redo log in external storage fail", zap.Error(errs))
 			}
 		}()
 	}
@@ -546,7 +546,7 @@ func (w *Writer) getShouldRemovedFiles(checkPointTs uint64) ([]os.FileInfo, erro
 	return logFiles, nil
 }
 
-// flushAndRotateFile flushes the file to disk and rotate it if S3 storage is used.
+// flushAndRotateFile flushes the file to disk and rotate it if an external storage is used.
 func (w *Writer) flushAndRotateFile() error {
 	if w.file == nil {
 		return nil
@@ -558,7 +558,7 @@ func (w *Writer) flushAndRotateFile() error {
 		return err
 	}
 
-	if !w.cfg.S3Storage {
+	if !w.cfg.UseExternalStorage {
 		return nil
 	}
 
@@ -566,8 +566,8 @@ func (w *Writer) flushAndRotateFile() error {
 		return nil
 	}
 
-	// for s3 storage, when the file is flushed to disk, we need an immediate
-	// file rotate. Otherwise, the existing file content would be repeatedly written to S3,
+	// for external storage, when the file is flushed to disk, we need an immediate
+	// file rotate. Otherwise, the existing file content would be repeatedly written to the external storage,
 	// which could cause considerable network bandwidth waste.
 	err = w.rotate()
 	if err != nil {
@@ -605,7 +605,7 @@ func (w *Writer) flush() error {
 	return cerror.WrapError(cerror.ErrRedoFileOp, err)
 }
 
-func (w *Writer) writeToS3(ctx context.Context, name string) error {
+func (w *Writer) writeToExtStorage(ctx context.Context, name string) error {
 	fileData, err := os.ReadFile(name)
 	if err != nil {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
@@ -619,7 +619,7 @@ func (w *Writer) writeToS3(ctx context.Context, name string) error {
 
 	// in case the page cache piling up triggered the OS memory reclaming which may cause
 	// I/O latency spike, we mandatorily drop the page cache of the file when it is successfully
-	// written to S3.
+	// written to the external storage.
 	err = fsutil.DropPageCache(name)
 	if err != nil {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
@@ -214,13 +214,13 @@ func TestWriterGC(t *testing.T) {
 
 	megabyte = 1
 	cfg := &FileWriterConfig{
-		Dir:          dir,
-		ChangeFeedID: model.DefaultChangeFeedID("test"),
-		CaptureID:    "cp",
-		MaxLogSize:   10,
-		FileType:     common.DefaultRowLogFileType,
-		CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-		S3Storage:    true,
+		Dir:                dir,
+		ChangeFeedID:       model.DefaultChangeFeedID("test"),
+		CaptureID:          "cp",
+		MaxLogSize:         10,
+		FileType:           common.DefaultRowLogFileType,
+		CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+		UseExternalStorage: true,
 	}
 	w := &Writer{
 		cfg:       cfg,
@@ -291,8 +291,8 @@ func TestNewWriter(t *testing.T) {
 
 	uuidGen := uuid.NewConstGenerator("const-uuid")
 	w, err := NewWriter(context.Background(), &FileWriterConfig{
-		Dir:       "sdfsf",
-		S3Storage: false,
+		Dir:                "sdfsf",
+		UseExternalStorage: false,
 	},
 		WithUUIDGenerator(func() uuid.Generator { return uuidGen }),
 	)
@@ -321,13 +321,13 @@ func TestNewWriter(t *testing.T) {
 	}
 	w = &Writer{
 		cfg: &FileWriterConfig{
-			Dir:          dir,
-			CaptureID:    "cp",
-			ChangeFeedID: changefeed,
-			FileType:     common.DefaultDDLLogFileType,
-			CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			S3Storage:    true,
-			MaxLogSize:   defaultMaxLogSize,
+			Dir:                dir,
+			CaptureID:          "cp",
+			ChangeFeedID:       changefeed,
+			FileType:           common.DefaultDDLLogFileType,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			UseExternalStorage: true,
+			MaxLogSize:         defaultMaxLogSize,
 		},
 		uint64buf: make([]byte, 8),
 		storage:   mockStorage,
@@ -377,13 +377,13 @@ func TestRotateFileWithFileAllocator(t *testing.T) {
 	}
 	w := &Writer{
 		cfg: &FileWriterConfig{
-			Dir:          dir,
-			CaptureID:    "cp",
-			ChangeFeedID: changefeed,
-			FileType:     common.DefaultRowLogFileType,
-			CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			S3Storage:    true,
-			MaxLogSize:   defaultMaxLogSize,
+			Dir:                dir,
+			CaptureID:          "cp",
+			ChangeFeedID:       changefeed,
+			FileType:           common.DefaultRowLogFileType,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			UseExternalStorage: true,
+			MaxLogSize:         defaultMaxLogSize,
 		},
 		uint64buf: make([]byte, 8),
 		metricWriteBytes: common.RedoWriteBytesGauge.
@@ -442,13 +442,13 @@ func TestRotateFileWithoutFileAllocator(t *testing.T) {
 	}
 	w := &Writer{
 		cfg: &FileWriterConfig{
-			Dir:          dir,
-			CaptureID:    "cp",
-			ChangeFeedID: changefeed,
-			FileType:     common.DefaultDDLLogFileType,
-			CreateTime:   time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			S3Storage:    true,
-			MaxLogSize:   defaultMaxLogSize,
+			Dir:                dir,
+			CaptureID:          "cp",
+			ChangeFeedID:       changefeed,
+			FileType:           common.DefaultDDLLogFileType,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			UseExternalStorage: true,
+			MaxLogSize:         defaultMaxLogSize,
 		},
 		uint64buf: make([]byte, 8),
 		metricWriteBytes: common.RedoWriteBytesGauge.
@@ -15,12 +15,15 @@ package writer
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"time"
 
+	gcsstorage "cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/pingcap/errors"
@@ -70,11 +73,12 @@ type LogWriterConfig struct {
 	CaptureID    string
 	CreateTime   time.Time
 	// MaxLogSize is the maximum size of log in megabyte, defaults to defaultMaxLogSize.
-	MaxLogSize        int64
-	FlushIntervalInMs int64
-	S3Storage         bool
-	// S3URI should be like S3URI="s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/"
-	S3URI url.URL
+	MaxLogSize         int64
+	FlushIntervalInMs  int64
+	UseExternalStorage bool
+	// ExtStorageURI should be like ExtStorageURI="s3://logbucket/test-changefeed?endpoint=http://$S3_ENDPOINT/",
+	// gcs and azblob URIs are supported as well.
+	ExtStorageURI url.URL
 
 	EmitMeta      bool
 	EmitRowEvents bool
@@ -107,14 +111,14 @@ func NewLogWriter(
 
 	if logWriter.cfg.EmitRowEvents {
 		writerCfg := &FileWriterConfig{
-			Dir:          cfg.Dir,
-			ChangeFeedID: cfg.ChangeFeedID,
-			CaptureID:    cfg.CaptureID,
-			FileType:     common.DefaultRowLogFileType,
-			CreateTime:   cfg.CreateTime,
-			MaxLogSize:   cfg.MaxLogSize,
-			S3Storage:    cfg.S3Storage,
-			S3URI:        cfg.S3URI,
+			Dir:                cfg.Dir,
+			ChangeFeedID:       cfg.ChangeFeedID,
+			CaptureID:          cfg.CaptureID,
+			FileType:           common.DefaultRowLogFileType,
+			CreateTime:         cfg.CreateTime,
+			MaxLogSize:         cfg.MaxLogSize,
+			UseExternalStorage: cfg.UseExternalStorage,
+			ExtStorageURI:      cfg.ExtStorageURI,
 		}
 		if logWriter.rowWriter, err = NewWriter(ctx, writerCfg, opts...); err != nil {
 			return
@@ -123,14 +127,14 @@ func NewLogWriter(
 
 	if logWriter.cfg.EmitDDLEvents {
 		writerCfg := &FileWriterConfig{
-			Dir:          cfg.Dir,
-			ChangeFeedID: cfg.ChangeFeedID,
-			CaptureID:    cfg.CaptureID,
-			FileType:     common.DefaultDDLLogFileType,
-			CreateTime:   cfg.CreateTime,
-			MaxLogSize:   cfg.MaxLogSize,
-			S3Storage:    cfg.S3Storage,
-			S3URI:        cfg.S3URI,
+			Dir:                cfg.Dir,
+			ChangeFeedID:       cfg.ChangeFeedID,
+			CaptureID:          cfg.CaptureID,
+			FileType:           common.DefaultDDLLogFileType,
+			CreateTime:         cfg.CreateTime,
+			MaxLogSize:         cfg.MaxLogSize,
+			UseExternalStorage: cfg.UseExternalStorage,
+			ExtStorageURI:      cfg.ExtStorageURI,
 		}
 		if logWriter.ddlWriter, err = NewWriter(ctx, writerCfg, opts...); err != nil {
 			return
@@ -147,13 +151,13 @@ func NewLogWriter(
 		}
 	}
 
-	if cfg.S3Storage {
-		logWriter.storage, err = common.InitS3storage(ctx, cfg.S3URI)
+	if cfg.UseExternalStorage {
+		logWriter.storage, err = common.InitExternalStorage(ctx, cfg.ExtStorageURI)
 		if err != nil {
 			return nil, err
 		}
 		// since other process get the remove changefeed job async, may still write some logs after owner delete the log
-		err = logWriter.preCleanUpS3(ctx)
+		err = logWriter.preCleanUpExtStorage(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -164,7 +168,7 @@ func NewLogWriter(
 	return
 }
 
-func (l *LogWriter) preCleanUpS3(ctx context.Context) error {
+func (l *LogWriter) preCleanUpExtStorage(ctx context.Context) error {
 	ret, err := l.storage.FileExists(ctx, l.getDeletedChangefeedMarker())
 	if err != nil {
 		return cerror.WrapError(cerror.ErrS3StorageAPI, err)
@@ -173,7 +177,7 @@ func (l *LogWriter) preCleanUpS3(ctx context.Context) error {
 		return nil
 	}
 
-	files, err := getAllFilesInS3(ctx, l)
+	files, err := getAllFilesInExtStorage(ctx, l)
 	if err != nil {
 		return err
 	}
@@ -184,12 +188,12 @@ func (l *LogWriter) preCleanUpS3(ctx context.Context) error {
 			ff = append(ff, file)
 		}
 	}
-	err = l.deleteFilesInS3(ctx, ff)
+	err = l.deleteFilesInExtStorage(ctx, ff)
 	if err != nil {
 		return err
 	}
 	err = l.storage.DeleteFile(ctx, l.getDeletedChangefeedMarker())
-	if !isNotExistInS3(err) {
+	if !isNotExistInExtStorage(err) {
 		return cerror.WrapError(cerror.ErrS3StorageAPI, err)
 	}
 
@@ -361,23 +365,23 @@ func (l *LogWriter) DeleteAllLogs(ctx context.Context) (err error) {
 		}
 	}
 
-	if !l.cfg.S3Storage {
+	if !l.cfg.UseExternalStorage {
 		return
 	}
 
 	var remoteFiles []string
-	remoteFiles, err = getAllFilesInS3(ctx, l)
+	remoteFiles, err = getAllFilesInExtStorage(ctx, l)
 	if err != nil {
 		return err
 	}
 	filteredFiles = common.FilterChangefeedFiles(remoteFiles, l.cfg.ChangeFeedID)
-	err = l.deleteFilesInS3(ctx, filteredFiles)
+	err = l.deleteFilesInExtStorage(ctx, filteredFiles)
 	if err != nil {
 		return
 	}
 
 	// Write deleted mark before clean any files.
-	err = l.writeDeletedMarkerToS3(ctx)
+	err = l.writeDeletedMarkerToExtStorage(ctx)
 	log.Info("redo manager write deleted mark",
 		zap.String("namespace", l.cfg.ChangeFeedID.Namespace),
 		zap.String("changefeed", l.cfg.ChangeFeedID.ID),
@@ -392,11 +396,11 @@ func (l *LogWriter) getDeletedChangefeedMarker() string {
 	return fmt.Sprintf("delete_%s_%s", l.cfg.ChangeFeedID.Namespace, l.cfg.ChangeFeedID.ID)
 }
 
-func (l *LogWriter) writeDeletedMarkerToS3(ctx context.Context) error {
+func (l *LogWriter) writeDeletedMarkerToExtStorage(ctx context.Context) error {
 	return cerror.WrapError(cerror.ErrS3StorageAPI, l.storage.WriteFile(ctx, l.getDeletedChangefeedMarker(), []byte("D")))
 }
 
-func (l *LogWriter) deleteFilesInS3(ctx context.Context, files []string) error {
+func (l *LogWriter) deleteFilesInExtStorage(ctx context.Context, files []string) error {
 	eg, eCtx := errgroup.WithContext(ctx)
 	for _, f := range files {
 		name := f
@@ -404,7 +408,7 @@ func (l *LogWriter) deleteFilesInS3(ctx context.Context, files []string) error {
 			err := l.storage.DeleteFile(eCtx, name)
 			if err != nil {
 				// if fail then retry, may end up with notExit err, ignore the error
-				if !isNotExistInS3(err) {
+				if !isNotExistInExtStorage(err) {
 					return cerror.WrapError(cerror.ErrS3StorageAPI, err)
 				}
 			}
@@ -414,19 +418,22 @@ func (l *LogWriter) deleteFilesInS3(ctx context.Context, files []string) error {
 	return eg.Wait()
 }
 
-func isNotExistInS3(err error) bool {
-	if err != nil {
-		if aerr, ok := errors.Cause(err).(awserr.Error); ok { // nolint:errorlint
-			switch aerr.Code() {
-			case s3.ErrCodeNoSuchKey:
-				return true
-			}
-		}
+func isNotExistInExtStorage(err error) bool {
+	if err == nil {
+		return false
+	}
+	cause := errors.Cause(err)
+	if aerr, ok := cause.(awserr.Error); ok { // nolint:errorlint
+		return aerr.Code() == s3.ErrCodeNoSuchKey
+	}
+	var azErr *azblob.StorageError
+	if goerrors.As(cause, &azErr) {
+		return azErr.ErrorCode == azblob.StorageErrorCodeBlobNotFound
 	}
-	return false
+	return goerrors.Is(cause, gcsstorage.ErrObjectNotExist) || os.IsNotExist(cause)
 }
 
-var getAllFilesInS3 = func(ctx context.Context, l *LogWriter) ([]string, error) {
+var getAllFilesInExtStorage = func(ctx context.Context, l *LogWriter) ([]string, error) {
 	files := []string{}
 	err := l.storage.WalkDir(ctx, &storage.WalkOption{}, func(path string, _ int64) error {
 		files = append(files, path)
@@ -570,16 +577,16 @@ func (l *LogWriter) flushLogMeta(checkpointTs, resolvedTs uint64) error {
 		return cerror.WrapError(cerror.ErrRedoFileOp, err)
 	}
 
-	if !l.cfg.S3Storage {
+	if !l.cfg.UseExternalStorage {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultS3Timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultExtStorageTimeout)
 	defer cancel()
-	return l.writeMetaToS3(ctx)
+	return l.writeMetaToExtStorage(ctx)
 }
 
-func (l *LogWriter) writeMetaToS3(ctx context.Context) error {
+func (l *LogWriter) writeMetaToExtStorage(ctx context.Context) error {
 	name := l.filePath()
 	fileData, err := os.ReadFile(name)
 	if err != nil {
@@ -597,5 +604,5 @@ func (cfg LogWriterConfig) String() string {
 	return fmt.Sprintf("%s:%s:%s:%s:%d:%d:%s:%t",
 		cfg.ChangeFeedID.Namespace, cfg.ChangeFeedID.ID,
 		cfg.CaptureID, cfg.Dir, cfg.MaxLogSize,
-		cfg.FlushIntervalInMs, cfg.S3URI.String(), cfg.S3Storage)
+		cfg.FlushIntervalInMs, cfg.ExtStorageURI.String(), cfg.UseExternalStorage)
 }
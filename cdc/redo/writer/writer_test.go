@@ -22,6 +22,8 @@ import (
 	"testing"
 	"time"
 
+	gcsstorage "cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/golang/mock/gomock"
@@ -321,13 +323,13 @@ func TestLogWriterFlushLog(t *testing.T) {
 		mockWriter.On("Flush", mock.Anything).Return(tt.flushErr)
 		mockWriter.On("IsRunning").Return(tt.isRunning)
 		cfg := &LogWriterConfig{
-			Dir:               dir,
-			ChangeFeedID:      model.DefaultChangeFeedID("test-cf"),
-			CaptureID:         "cp",
-			MaxLogSize:        10,
-			CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			FlushIntervalInMs: 5,
-			S3Storage:         true,
+			Dir:                dir,
+			ChangeFeedID:       model.DefaultChangeFeedID("test-cf"),
+			CaptureID:          "cp",
+			MaxLogSize:         10,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			FlushIntervalInMs:  5,
+			UseExternalStorage: true,
 
 			EmitMeta:      true,
 			EmitRowEvents: true,
@@ -359,10 +361,10 @@ func TestLogWriterFlushLog(t *testing.T) {
 func TestLogWriterRegress(t *testing.T) {
 	dir := t.TempDir()
 	writer, err := NewLogWriter(context.Background(), &LogWriterConfig{
-		Dir:          dir,
-		ChangeFeedID: model.DefaultChangeFeedID("test-log-writer-regress"),
-		CaptureID:    "cp",
-		S3Storage:    false,
+		Dir:                dir,
+		ChangeFeedID:       model.DefaultChangeFeedID("test-log-writer-regress"),
+		CaptureID:          "cp",
+		UseExternalStorage: false,
 
 		EmitMeta:      true,
 		EmitRowEvents: true,
@@ -448,25 +450,25 @@ func TestNewLogWriter(t *testing.T) {
 	require.Equal(t, meta.CheckpointTs, l.meta.CheckpointTs)
 	require.Equal(t, meta.ResolvedTs, l.meta.ResolvedTs)
 
-	origin := common.InitS3storage
+	origin := common.InitExternalStorage
 	defer func() {
-		common.InitS3storage = origin
+		common.InitExternalStorage = origin
 	}()
 	controller := gomock.NewController(t)
 	mockStorage := mockstorage.NewMockExternalStorage(controller)
 	// skip pre cleanup
 	mockStorage.EXPECT().FileExists(gomock.Any(), gomock.Any()).Return(false, nil)
-	common.InitS3storage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
+	common.InitExternalStorage = func(ctx context.Context, uri url.URL) (storage.ExternalStorage, error) {
 		return mockStorage, nil
 	}
 	cfg3 := &LogWriterConfig{
-		Dir:               dir,
-		ChangeFeedID:      model.DefaultChangeFeedID("test-cf112232"),
-		CaptureID:         "cp",
-		MaxLogSize:        10,
-		CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-		FlushIntervalInMs: 5,
-		S3Storage:         true,
+		Dir:                dir,
+		ChangeFeedID:       model.DefaultChangeFeedID("test-cf112232"),
+		CaptureID:          "cp",
+		MaxLogSize:         10,
+		CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+		FlushIntervalInMs:  5,
+		UseExternalStorage: true,
 	}
 	l3, err := NewLogWriter(ctx, cfg3)
 	require.Nil(t, err)
@@ -514,7 +516,7 @@ func TestDeleteAllLogs(t *testing.T) {
 		},
 		{
 			changefeed:         changefeedID1,
-			name:               "getAllFilesInS3 err",
+			name:               "getAllFilesInExtStorage err",
 			args:               args{enableS3: true},
 			getAllFilesInS3Err: errors.New("xx"),
 			wantErr:            ".*xx*.",
@@ -560,8 +562,8 @@ func TestDeleteAllLogs(t *testing.T) {
 		_, err = os.Create(path)
 		require.Nil(t, err)
 
-		origin := getAllFilesInS3
-		getAllFilesInS3 = func(ctx context.Context, l *LogWriter) ([]string, error) {
+		origin := getAllFilesInExtStorage
+		getAllFilesInExtStorage = func(ctx context.Context, l *LogWriter) ([]string, error) {
 			return []string{fileName, fileName1}, tt.getAllFilesInS3Err
 		}
 		controller := gomock.NewController(t)
@@ -573,13 +575,13 @@ func TestDeleteAllLogs(t *testing.T) {
 		mockWriter := &mockFileWriter{}
 		mockWriter.On("Close").Return(tt.closeErr)
 		cfg := &LogWriterConfig{
-			Dir:               dir,
-			ChangeFeedID:      tt.changefeed,
-			CaptureID:         "cp",
-			MaxLogSize:        10,
-			CreateTime:        time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
-			FlushIntervalInMs: 5,
-			S3Storage:         tt.args.enableS3,
+			Dir:                dir,
+			ChangeFeedID:       tt.changefeed,
+			CaptureID:          "cp",
+			MaxLogSize:         10,
+			CreateTime:         time.Date(2000, 1, 1, 1, 1, 1, 1, &time.Location{}),
+			FlushIntervalInMs:  5,
+			UseExternalStorage: tt.args.enableS3,
 
 			EmitMeta:      true,
 			EmitRowEvents: true,
@@ -609,7 +611,7 @@ func TestDeleteAllLogs(t *testing.T) {
 				}
 			}
 		}
-		getAllFilesInS3 = origin
+		getAllFilesInExtStorage = origin
 	}
 }
 
@@ -632,7 +634,7 @@ func TestPreCleanUpS3(t *testing.T) {
 			wantErr:       ".*xx*.",
 		},
 		{
-			name:               "getAllFilesInS3 err",
+			name:               "getAllFilesInExtStorage err",
 			fileExists:         true,
 			getAllFilesInS3Err: errors.New("xx"),
 			wantErr:            ".*xx*.",
@@ -659,8 +661,8 @@ func TestPreCleanUpS3(t *testing.T) {
 			model.DefaultChangeFeedID("test-cf"),
 		}
 		for _, cf := range cfs {
-			origin := getAllFilesInS3
-			getAllFilesInS3 = func(ctx context.Context, l *LogWriter) ([]string, error) {
+			origin := getAllFilesInExtStorage
+			getAllFilesInExtStorage = func(ctx context.Context, l *LogWriter) ([]string, error) {
 				if cf.Namespace == model.DefaultNamespace {
 					return []string{"1", "11", "delete_test-cf"}, tc.getAllFilesInS3Err
 				}
@@ -687,13 +689,35 @@ func TestPreCleanUpS3(t *testing.T) {
 				cfg:     cfg,
 				storage: mockStorage,
 			}
-			ret := writer.preCleanUpS3(context.Background())
+			ret := writer.preCleanUpExtStorage(context.Background())
 			if tc.wantErr != "" {
 				require.Regexp(t, tc.wantErr, ret.Error(), tc.name)
 			} else {
 				require.Nil(t, ret, tc.name)
 			}
-			getAllFilesInS3 = origin
+			getAllFilesInExtStorage = origin
 		}
 	}
 }
+
+func TestIsNotExistInExtStorage(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, isNotExistInExtStorage(nil))
+	require.False(t, isNotExistInExtStorage(errors.New("unknown error")))
+	require.True(t, isNotExistInExtStorage(
+		errors.Trace(awserr.New(s3.ErrCodeNoSuchKey, "no such key", nil))))
+	require.False(t, isNotExistInExtStorage(
+		awserr.New(s3.ErrCodeNoSuchBucket, "no such bucket", nil)))
+	require.True(t, isNotExistInExtStorage(errors.Trace(gcsstorage.ErrObjectNotExist)))
+	require.True(t, isNotExistInExtStorage(
+		errors.Annotate(&azblob.StorageError{ErrorCode: azblob.StorageErrorCodeBlobNotFound}, "delete")))
+	require.False(t, isNotExistInExtStorage(
+		&azblob.StorageError{ErrorCode: azblob.StorageErrorCodeContainerNotFound}))
+
+	// the local storage is used to fake the external storage in tests.
+	localStorage, err := storage.NewLocalStorage(t.TempDir())
+	require.Nil(t, err)
+	err = localStorage.DeleteFile(context.Background(), "not-exist")
+	require.True(t, isNotExistInExtStorage(err))
+}
@@ -26,4 +26,9 @@ type InfoProvider interface {
 
 	// GetTaskStatuses returns the task statuses.
 	GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus, error)
+
+	// GetTableCheckpoints returns the checkpoint ts of the tables, which is
+	// the minimum checkpoint ts of all the spans of a table across captures.
+	// A table is omitted if its spans don't cover the whole table.
+	GetTableCheckpoints() (map[model.TableID]model.Ts, error)
 }
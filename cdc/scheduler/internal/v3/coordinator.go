@@ -15,13 +15,17 @@ package v3
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/compat"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/keyspan"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/scheduler"
@@ -31,6 +35,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/p2p"
 	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/version"
 	"go.uber.org/zap"
 )
@@ -54,12 +59,29 @@ type coordinator struct {
 	replicationM *replication.Manager
 	captureM     *member.CaptureManager
 	schedulerM   *scheduler.Manager
+	reconciler   *keyspan.Reconciler
+	compat       *compat.Compat
 	pdClock      pdutil.Clock
 
+	// spanChanges are the span changes by write traffic that are being
+	// applied to replications.
+	spanChanges          []*spanChange
+	checkTrafficInterval time.Duration
+	lastCheckTrafficTime time.Time
+
 	lastCollectTime time.Time
 	changefeedID    model.ChangeFeedID
 }
 
+// spanChange is a span change by write traffic that is being applied.
+type spanChange struct {
+	keyspan.SpanChange
+	// captureID is the capture that new spans are added to.
+	captureID model.CaptureID
+	// removing is true once old spans are being removed.
+	removing bool
+}
+
 // NewCoordinator returns a two phase scheduler.
 func NewCoordinator(
 	ctx context.Context,
@@ -68,7 +90,9 @@ func NewCoordinator(
 	messageServer *p2p.MessageServer,
 	messageRouter p2p.MessageRouter,
 	ownerRevision int64,
+	regionCache keyspan.RegionCache,
 	cfg *config.SchedulerConfig,
+	placement *config.PlacementConfig,
 	pdClock pdutil.Clock,
 ) (internal.Scheduler, error) {
 	trans, err := transport.NewTransport(
@@ -76,9 +100,10 @@ func NewCoordinator(
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	coord := newCoordinator(captureID, changefeedID, ownerRevision, cfg)
+	coord := newCoordinator(captureID, changefeedID, ownerRevision, regionCache, cfg)
 	coord.trans = trans
 	coord.pdClock = pdClock
+	coord.schedulerM.SetPlacement(placement)
 	return coord, nil
 }
 
@@ -86,6 +111,7 @@ func newCoordinator(
 	captureID model.CaptureID,
 	changefeedID model.ChangeFeedID,
 	ownerRevision int64,
+	regionCache keyspan.RegionCache,
 	cfg *config.SchedulerConfig,
 ) *coordinator {
 	revision := schedulepb.OwnerRevision{Revision: ownerRevision}
@@ -99,7 +125,11 @@ func newCoordinator(
 		captureM: member.NewCaptureManager(
 			captureID, changefeedID, revision, cfg.HeartbeatTick),
 		schedulerM:   scheduler.NewSchedulerManager(changefeedID, cfg),
+		reconciler:   keyspan.NewReconciler(changefeedID, regionCache, cfg),
+		compat:       compat.New(cfg, map[model.CaptureID]*model.CaptureInfo{}),
 		changefeedID: changefeedID,
+
+		checkTrafficInterval: time.Duration(cfg.CheckBalanceInterval),
 	}
 }
 
@@ -171,11 +201,13 @@ func (c *coordinator) DrainCapture(target model.CaptureID) (int, error) {
 	}
 
 	var count int
-	for _, rep := range c.replicationM.ReplicationSets() {
-		if rep.Primary == target {
-			count++
-		}
-	}
+	c.replicationM.ReplicationSets().Ascend(
+		func(_ tablepb.Span, rep *replication.ReplicationSet) bool {
+			if rep.Primary == target {
+				count++
+			}
+			return true
+		})
 
 	if count == 0 {
 		log.Info("schedulerv3: drain capture request ignored, "+
@@ -245,6 +277,14 @@ func (c *coordinator) poll(
 	aliveCaptures map[model.CaptureID]*model.CaptureInfo,
 ) (newCheckpointTs, newResolvedTs model.Ts, err error) {
 	c.maybeCollectMetrics()
+	if c.compat.UpdateCaptureInfo(aliveCaptures) {
+		spanReplicationEnabled := c.compat.CheckSpanReplicationEnabled()
+		log.Info("schedulerv3: compat update capture info",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.Any("captures", aliveCaptures),
+			zap.Bool("spanReplicationEnabled", spanReplicationEnabled))
+	}
 
 	recvMsgs, err := c.recvMsgs(ctx)
 	if err != nil {
@@ -294,8 +334,14 @@ func (c *coordinator) poll(
 	// Generate schedule tasks based on the current status.
 	replications := c.replicationM.ReplicationSets()
 	runningTasks := c.replicationM.RunningTasks()
+	currentSpans := c.reconciler.Reconcile(ctx, currentTables, replications, c.compat)
+	c.maybeReconcileTraffic(replications)
+	// Tables whose spans are being changed are only scheduled by span changes.
+	spanChangeTasks, currentSpans, replications := c.scheduleSpanChanges(
+		currentTables, currentSpans, replications, runningTasks)
 	allTasks := c.schedulerM.Schedule(
-		checkpointTs, currentTables, c.captureM.Captures, replications, runningTasks)
+		checkpointTs, currentSpans, c.captureM.Captures, replications, runningTasks)
+	allTasks = append(spanChangeTasks, allTasks...)
 
 	// Handle generated schedule tasks.
 	msgs, err = c.replicationM.HandleTasks(allTasks)
@@ -329,6 +375,7 @@ func (c *coordinator) recvMsgs(ctx context.Context) ([]*schedulepb.Message, erro
 			n++
 		}
 	}
+	c.compat.AfterTransportReceive(recvMsgs[:n])
 	return recvMsgs[:n], nil
 }
 
@@ -355,9 +402,195 @@ func (c *coordinator) sendMsgs(ctx context.Context, msgs []*schedulepb.Message)
 		m.From = c.captureID
 
 	}
+	c.compat.BeforeTransportSend(msgs)
 	return c.trans.Send(ctx, msgs)
 }
 
+// maybeReconcileTraffic periodically splits and merges spans by write traffic.
+func (c *coordinator) maybeReconcileTraffic(
+	replications *spanz.Map[*replication.ReplicationSet],
+) {
+	if !c.compat.CheckSpanReplicationEnabled() {
+		return
+	}
+	now := time.Now()
+	if now.Sub(c.lastCheckTrafficTime) < c.checkTrafficInterval {
+		return
+	}
+	c.lastCheckTrafficTime = now
+
+	for _, change := range c.reconciler.ReconcileTraffic(replications) {
+		c.spanChanges = append(c.spanChanges, &spanChange{
+			SpanChange: change,
+			captureID:  replications.GetV(change.OldSpans[0]).Primary,
+		})
+	}
+}
+
+// scheduleSpanChanges generates tasks to apply span changes. Old spans are
+// removed first, and new spans are added from the checkpoint of the change
+// once all old spans are removed, so that a key is never replicated by two
+// spans at the same time.
+//
+// It returns the current spans and replications without the tables whose
+// spans are being changed, they must not be scheduled by other schedulers.
+func (c *coordinator) scheduleSpanChanges(
+	currentTables []model.TableID,
+	currentSpans []tablepb.Span,
+	replications *spanz.Map[*replication.ReplicationSet],
+	runningTasks *spanz.Map[*replication.ScheduleTask],
+) ([]*replication.ScheduleTask, []tablepb.Span, *spanz.Map[*replication.ReplicationSet]) {
+	if len(c.spanChanges) == 0 {
+		return nil, currentSpans, replications
+	}
+
+	tables := model.NewTableSet()
+	for _, tableID := range currentTables {
+		tables.Add(tableID)
+	}
+	changingTables := make(map[model.TableID]struct{})
+	pendingChanges := make([]*spanChange, 0, len(c.spanChanges))
+	var finishedTables []model.TableID
+	var removeTables []replication.RemoveTable
+	var addTables []replication.AddTable
+	for _, change := range c.spanChanges {
+		if !tables.Contain(change.TableID) {
+			// The table is dropped, its spans are removed by the basic
+			// scheduler.
+			log.Info("schedulerv3: span change ignored, since the table is dropped",
+				zap.String("namespace", c.changefeedID.Namespace),
+				zap.String("changefeed", c.changefeedID.ID),
+				zap.Int64("tableID", change.TableID))
+			continue
+		}
+		changingTables[change.TableID] = struct{}{}
+
+		if !change.removing {
+			change.removing = true
+			c.refreshSpanChangeCheckpoint(change, replications)
+		}
+		removed := true
+		for _, span := range change.OldSpans {
+			rep, ok := replications.Get(span)
+			if !ok {
+				continue
+			}
+			removed = false
+			if runningTasks.Has(span) ||
+				rep.State == replication.ReplicationSetStatePrepare ||
+				rep.State == replication.ReplicationSetStateCommit {
+				// Wait for the running task, e.g., moving the span.
+				continue
+			}
+			removeTables = append(removeTables, replication.RemoveTable{
+				Span:      span,
+				CaptureID: rep.Primary,
+			})
+		}
+		target := c.spanChangeCapture(change.captureID)
+		if !removed || target == "" {
+			pendingChanges = append(pendingChanges, change)
+			continue
+		}
+		for _, span := range change.NewSpans {
+			addTables = append(addTables, replication.AddTable{
+				Span:         span,
+				CaptureID:    target,
+				CheckpointTs: change.CheckpointTs,
+			})
+		}
+		finishedTables = append(finishedTables, change.TableID)
+		log.Info("schedulerv3: old spans are removed, add new spans",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.Int64("tableID", change.TableID),
+			zap.Int("newSpans", len(change.NewSpans)),
+			zap.String("captureID", target),
+			zap.Uint64("checkpointTs", change.CheckpointTs))
+	}
+	c.spanChanges = pendingChanges
+	for _, tableID := range finishedTables {
+		finished := true
+		for _, change := range c.spanChanges {
+			if change.TableID == tableID {
+				finished = false
+				break
+			}
+		}
+		if finished {
+			c.reconciler.FinishSpanChange(tableID)
+		}
+	}
+
+	var tasks []*replication.ScheduleTask
+	if len(removeTables) != 0 || len(addTables) != 0 {
+		tasks = append(tasks, &replication.ScheduleTask{
+			BurstBalance: &replication.BurstBalance{
+				AddTables:    addTables,
+				RemoveTables: removeTables,
+			},
+		})
+	}
+	if len(changingTables) == 0 {
+		return tasks, currentSpans, replications
+	}
+	spans := make([]tablepb.Span, 0, len(currentSpans))
+	for _, span := range currentSpans {
+		if _, ok := changingTables[span.TableID]; !ok {
+			spans = append(spans, span)
+		}
+	}
+	reps := spanz.NewMap[*replication.ReplicationSet]()
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if _, ok := changingTables[span.TableID]; !ok {
+			reps.ReplaceOrInsert(span, rep)
+		}
+		return true
+	})
+	return tasks, spans, reps
+}
+
+// refreshSpanChangeCheckpoint advances the checkpoint of the change to the
+// latest checkpoint of old spans before they are removed, so that new spans
+// replicate as few duplicated changes as possible.
+func (c *coordinator) refreshSpanChangeCheckpoint(
+	change *spanChange, replications *spanz.Map[*replication.ReplicationSet],
+) {
+	var checkpointTs model.Ts
+	for i, span := range change.OldSpans {
+		rep, ok := replications.Get(span)
+		if !ok {
+			return
+		}
+		if i == 0 || rep.Checkpoint.CheckpointTs < checkpointTs {
+			checkpointTs = rep.Checkpoint.CheckpointTs
+		}
+	}
+	if checkpointTs > change.CheckpointTs {
+		change.CheckpointTs = checkpointTs
+	}
+}
+
+// spanChangeCapture returns the capture that new spans are added to, it
+// prefers the capture that replicated old spans.
+func (c *coordinator) spanChangeCapture(captureID model.CaptureID) model.CaptureID {
+	if capture, ok := c.captureM.Captures[captureID]; ok &&
+		capture.State == member.CaptureStateInitialized {
+		return captureID
+	}
+	captureIDs := make([]model.CaptureID, 0, len(c.captureM.Captures))
+	for id, capture := range c.captureM.Captures {
+		if capture.State == member.CaptureStateInitialized {
+			captureIDs = append(captureIDs, id)
+		}
+	}
+	if len(captureIDs) == 0 {
+		return ""
+	}
+	sort.Strings(captureIDs)
+	return captureIDs[0]
+}
+
 func (c *coordinator) maybeCollectMetrics() {
 	now := time.Now()
 	if now.Sub(c.lastCollectTime) < metricsInterval {
@@ -22,10 +22,14 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/compat"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/keyspan"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/transport"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -70,6 +74,10 @@ func BenchmarkCoordinatorInit(b *testing.B) {
 			currentTables = append(currentTables, int64(10000+i))
 		}
 		coord = &coordinator{
+			reconciler: keyspan.NewReconciler(
+				model.ChangeFeedID{}, keyspan.NewMockRegionCache(), &config.SchedulerConfig{}),
+			compat: compat.New(
+				&config.SchedulerConfig{}, map[model.CaptureID]*model.CaptureInfo{}),
 			trans:        transport.NewMockTrans(),
 			replicationM: replication.NewReplicationManager(10, model.ChangeFeedID{}),
 			// Disable heartbeat.
@@ -105,6 +113,10 @@ func BenchmarkCoordinatorHeartbeat(b *testing.B) {
 			currentTables = append(currentTables, int64(10000+i))
 		}
 		coord = &coordinator{
+			reconciler: keyspan.NewReconciler(
+				model.ChangeFeedID{}, keyspan.NewMockRegionCache(), &config.SchedulerConfig{}),
+			compat: compat.New(
+				&config.SchedulerConfig{}, map[model.CaptureID]*model.CaptureInfo{}),
 			trans:        transport.NewMockTrans(),
 			replicationM: replication.NewReplicationManager(10, model.ChangeFeedID{}),
 			captureM:     captureM,
@@ -140,13 +152,14 @@ func BenchmarkCoordinatorHeartbeatResponse(b *testing.B) {
 			tableID := int64(10000 + i)
 			currentTables = append(currentTables, tableID)
 			captureID := fmt.Sprint(i % captureCount)
-			rep, err := replication.NewReplicationSet(
-				tableID, 0, map[string]*tablepb.TableStatus{
-					captureID: {
-						TableID: tableID,
-						State:   tablepb.TableStateReplicating,
-					},
-				}, model.ChangeFeedID{})
+			rep, err := replication.NewReplicationSet(spanz.TableIDToComparableSpan(
+				tableID), 0, map[string]*tablepb.TableStatus{
+				captureID: {
+					TableID: tableID,
+					Span:    spanz.TableIDToComparableSpan(tableID),
+					State:   tablepb.TableStateReplicating,
+				},
+			}, model.ChangeFeedID{})
 			if err != nil {
 				b.Fatal(err)
 			}
@@ -164,6 +177,7 @@ func BenchmarkCoordinatorHeartbeatResponse(b *testing.B) {
 				heartbeatResp[captureID].HeartbeatResponse.Tables,
 				tablepb.TableStatus{
 					TableID: tableID,
+					Span:    spanz.TableIDToComparableSpan(tableID),
 					State:   tablepb.TableStateReplicating,
 				})
 		}
@@ -176,6 +190,10 @@ func BenchmarkCoordinatorHeartbeatResponse(b *testing.B) {
 			KeepRecvBuffer: true,
 		}
 		coord = &coordinator{
+			reconciler: keyspan.NewReconciler(
+				model.ChangeFeedID{}, keyspan.NewMockRegionCache(), &config.SchedulerConfig{}),
+			compat: compat.New(
+				&config.SchedulerConfig{}, map[model.CaptureID]*model.CaptureInfo{}),
 			trans:        trans,
 			replicationM: replicationM,
 			captureM:     captureM,
@@ -20,6 +20,8 @@ import (
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/compat"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/keyspan"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/scheduler"
@@ -28,7 +30,9 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/leakutil"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 func TestMain(m *testing.M) {
@@ -44,16 +48,31 @@ func TestCoordinatorSendMsgs(t *testing.T) {
 		revision:  schedulepb.OwnerRevision{Revision: 3},
 		captureID: "0",
 		trans:     trans,
+		compat: compat.New(
+			&config.SchedulerConfig{}, map[model.CaptureID]*model.CaptureInfo{}),
 	}
 	coord.captureM = member.NewCaptureManager("", model.ChangeFeedID{}, coord.revision, 0)
-	coord.sendMsgs(
-		ctx, []*schedulepb.Message{{To: "1", MsgType: schedulepb.MsgDispatchTableRequest}})
+	newRequest := func() *schedulepb.DispatchTableRequest {
+		return &schedulepb.DispatchTableRequest{
+			Request: &schedulepb.DispatchTableRequest_AddTable{
+				AddTable: &schedulepb.AddTableRequest{
+					TableID: 1, Span: spanz.TableIDToComparableSpan(1),
+				},
+			},
+		}
+	}
+	coord.sendMsgs(ctx, []*schedulepb.Message{{
+		To: "1", MsgType: schedulepb.MsgDispatchTableRequest,
+		DispatchTableRequest: newRequest(),
+	}})
 
 	coord.captureM.Captures["1"] = &member.CaptureStatus{
 		Epoch: schedulepb.ProcessorEpoch{Epoch: "epoch"},
 	}
-	coord.sendMsgs(
-		ctx, []*schedulepb.Message{{To: "1", MsgType: schedulepb.MsgDispatchTableRequest}})
+	coord.sendMsgs(ctx, []*schedulepb.Message{{
+		To: "1", MsgType: schedulepb.MsgDispatchTableRequest,
+		DispatchTableRequest: newRequest(),
+	}})
 
 	require.EqualValues(t, []*schedulepb.Message{{
 		Header: &schedulepb.Message_Header{
@@ -61,6 +80,7 @@ func TestCoordinatorSendMsgs(t *testing.T) {
 			OwnerRevision: coord.revision,
 		},
 		From: "0", To: "1", MsgType: schedulepb.MsgDispatchTableRequest,
+		DispatchTableRequest: newRequest(),
 	}, {
 		Header: &schedulepb.Message_Header{
 			Version:        coord.version,
@@ -68,6 +88,7 @@ func TestCoordinatorSendMsgs(t *testing.T) {
 			ProcessorEpoch: schedulepb.ProcessorEpoch{Epoch: "epoch"},
 		},
 		From: "0", To: "1", MsgType: schedulepb.MsgDispatchTableRequest,
+		DispatchTableRequest: newRequest(),
 	}}, trans.SendBuffer)
 }
 
@@ -81,6 +102,19 @@ func TestCoordinatorRecvMsgs(t *testing.T) {
 		revision:  schedulepb.OwnerRevision{Revision: 3},
 		captureID: "0",
 		trans:     trans,
+		compat: compat.New(
+			&config.SchedulerConfig{}, map[model.CaptureID]*model.CaptureInfo{}),
+	}
+	newResponse := func() *schedulepb.DispatchTableResponse {
+		return &schedulepb.DispatchTableResponse{
+			Response: &schedulepb.DispatchTableResponse_AddTable{
+				AddTable: &schedulepb.AddTableResponse{
+					Status: &tablepb.TableStatus{
+						TableID: 1, Span: spanz.TableIDToComparableSpan(1),
+					},
+				},
+			},
+		}
 	}
 
 	trans.RecvBuffer = append(trans.RecvBuffer,
@@ -89,6 +123,7 @@ func TestCoordinatorRecvMsgs(t *testing.T) {
 				OwnerRevision: coord.revision,
 			},
 			From: "1", To: coord.captureID, MsgType: schedulepb.MsgDispatchTableResponse,
+			DispatchTableResponse: newResponse(),
 		})
 	trans.RecvBuffer = append(trans.RecvBuffer,
 		&schedulepb.Message{
@@ -96,6 +131,7 @@ func TestCoordinatorRecvMsgs(t *testing.T) {
 				OwnerRevision: schedulepb.OwnerRevision{Revision: 4},
 			},
 			From: "2", To: coord.captureID, MsgType: schedulepb.MsgDispatchTableResponse,
+			DispatchTableResponse: newResponse(),
 		})
 	trans.RecvBuffer = append(trans.RecvBuffer,
 		&schedulepb.Message{
@@ -103,6 +139,7 @@ func TestCoordinatorRecvMsgs(t *testing.T) {
 				OwnerRevision: coord.revision,
 			},
 			From: "3", To: "lost", MsgType: schedulepb.MsgDispatchTableResponse,
+			DispatchTableResponse: newResponse(),
 		})
 
 	msgs, err := coord.recvMsgs(ctx)
@@ -112,13 +149,14 @@ func TestCoordinatorRecvMsgs(t *testing.T) {
 			OwnerRevision: coord.revision,
 		},
 		From: "1", To: "0", MsgType: schedulepb.MsgDispatchTableResponse,
+		DispatchTableResponse: newResponse(),
 	}}, msgs)
 }
 
 func TestCoordinatorHeartbeat(t *testing.T) {
 	t.Parallel()
 
-	coord := newCoordinator("a", model.ChangeFeedID{}, 1, &config.SchedulerConfig{
+	coord := newCoordinator("a", model.ChangeFeedID{}, 1, keyspan.NewMockRegionCache(), &config.SchedulerConfig{
 		HeartbeatTick:      math.MaxInt,
 		MaxTaskConcurrency: 1,
 		AddTableBatchSize:  50,
@@ -158,8 +196,8 @@ func TestCoordinatorHeartbeat(t *testing.T) {
 		MsgType: schedulepb.MsgHeartbeatResponse,
 		HeartbeatResponse: &schedulepb.HeartbeatResponse{
 			Tables: []tablepb.TableStatus{
-				{TableID: 1, State: tablepb.TableStateReplicating},
-				{TableID: 2, State: tablepb.TableStateReplicating},
+				{TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating},
+				{TableID: 2, Span: spanz.TableIDToComparableSpan(2), State: tablepb.TableStateReplicating},
 			},
 		},
 	})
@@ -171,12 +209,12 @@ func TestCoordinatorHeartbeat(t *testing.T) {
 	require.Len(t, msgs, 1)
 	// Basic scheduler, make sure all tables get replicated.
 	require.EqualValues(t, 3, msgs[0].DispatchTableRequest.GetAddTable().TableID)
-	require.Len(t, coord.replicationM.GetReplicationSetForTests(), 3)
+	require.Equal(t, 3, coord.replicationM.GetReplicationSetForTests().Len())
 }
 
 func TestCoordinatorAddCapture(t *testing.T) {
 	t.Parallel()
-	coord := newCoordinator("a", model.ChangeFeedID{}, 1, &config.SchedulerConfig{
+	coord := newCoordinator("a", model.ChangeFeedID{}, 1, keyspan.NewMockRegionCache(), &config.SchedulerConfig{
 		HeartbeatTick:      math.MaxInt,
 		MaxTaskConcurrency: 1,
 	})
@@ -191,15 +229,15 @@ func TestCoordinatorAddCapture(t *testing.T) {
 	require.True(t, coord.captureM.CheckAllCaptureInitialized())
 	init := map[string][]tablepb.TableStatus{
 		"a": {
-			{TableID: 1, State: tablepb.TableStateReplicating},
-			{TableID: 2, State: tablepb.TableStateReplicating},
-			{TableID: 3, State: tablepb.TableStateReplicating},
+			{TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating},
+			{TableID: 2, Span: spanz.TableIDToComparableSpan(2), State: tablepb.TableStateReplicating},
+			{TableID: 3, Span: spanz.TableIDToComparableSpan(3), State: tablepb.TableStateReplicating},
 		},
 	}
 	msgs, err := coord.replicationM.HandleCaptureChanges(init, nil, 0)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Len(t, coord.replicationM.GetReplicationSetForTests(), 3)
+	require.Equal(t, 3, coord.replicationM.GetReplicationSetForTests().Len())
 
 	// Capture "b" is online, heartbeat, and then move one table to capture "b".
 	ctx := context.Background()
@@ -232,7 +270,7 @@ func TestCoordinatorAddCapture(t *testing.T) {
 func TestCoordinatorRemoveCapture(t *testing.T) {
 	t.Parallel()
 
-	coord := newCoordinator("a", model.ChangeFeedID{}, 1, &config.SchedulerConfig{
+	coord := newCoordinator("a", model.ChangeFeedID{}, 1, keyspan.NewMockRegionCache(), &config.SchedulerConfig{
 		HeartbeatTick:      math.MaxInt,
 		MaxTaskConcurrency: 1,
 		AddTableBatchSize:  50,
@@ -249,14 +287,14 @@ func TestCoordinatorRemoveCapture(t *testing.T) {
 	coord.captureM.SetInitializedForTests(true)
 	require.True(t, coord.captureM.CheckAllCaptureInitialized())
 	init := map[string][]tablepb.TableStatus{
-		"a": {{TableID: 1, State: tablepb.TableStateReplicating}},
-		"b": {{TableID: 2, State: tablepb.TableStateReplicating}},
-		"c": {{TableID: 3, State: tablepb.TableStateReplicating}},
+		"a": {{TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating}},
+		"b": {{TableID: 2, Span: spanz.TableIDToComparableSpan(2), State: tablepb.TableStateReplicating}},
+		"c": {{TableID: 3, Span: spanz.TableIDToComparableSpan(3), State: tablepb.TableStateReplicating}},
 	}
 	msgs, err := coord.replicationM.HandleCaptureChanges(init, nil, 0)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Len(t, coord.replicationM.GetReplicationSetForTests(), 3)
+	require.Equal(t, 3, coord.replicationM.GetReplicationSetForTests().Len())
 
 	// Capture "c" is removed, add table 3 to another capture.
 	ctx := context.Background()
@@ -293,7 +331,7 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 	require.Equal(t, 0, count)
 
 	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
-		TableID: 1,
+		Span:    spanz.TableIDToComparableSpan(1),
 		State:   replication.ReplicationSetStateReplicating,
 		Primary: "a",
 	})
@@ -304,7 +342,7 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 
 	coord.captureM.Captures["b"] = &member.CaptureStatus{State: member.CaptureStateInitialized}
 	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
-		TableID: 2,
+		Span:    spanz.TableIDToComparableSpan(2),
 		State:   replication.ReplicationSetStateReplicating,
 		Primary: "b",
 	})
@@ -323,7 +361,7 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 func TestCoordinatorAdvanceCheckpoint(t *testing.T) {
 	t.Parallel()
 
-	coord := newCoordinator("a", model.ChangeFeedID{}, 1, &config.SchedulerConfig{
+	coord := newCoordinator("a", model.ChangeFeedID{}, 1, keyspan.NewMockRegionCache(), &config.SchedulerConfig{
 		HeartbeatTick:      math.MaxInt,
 		MaxTaskConcurrency: 1,
 	})
@@ -359,13 +397,15 @@ func TestCoordinatorAdvanceCheckpoint(t *testing.T) {
 		HeartbeatResponse: &schedulepb.HeartbeatResponse{
 			Tables: []tablepb.TableStatus{
 				{
-					TableID: 1, State: tablepb.TableStateReplicating,
+					TableID: 1, Span: spanz.TableIDToComparableSpan(1),
+					State: tablepb.TableStateReplicating,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 2, ResolvedTs: 4,
 					},
 				},
 				{
-					TableID: 2, State: tablepb.TableStateReplicating,
+					TableID: 2, Span: spanz.TableIDToComparableSpan(2),
+					State: tablepb.TableStateReplicating,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 2, ResolvedTs: 4,
 					},
@@ -392,13 +432,15 @@ func TestCoordinatorAdvanceCheckpoint(t *testing.T) {
 		HeartbeatResponse: &schedulepb.HeartbeatResponse{
 			Tables: []tablepb.TableStatus{
 				{
-					TableID: 1, State: tablepb.TableStateReplicating,
+					TableID: 1, Span: spanz.TableIDToComparableSpan(1),
+					State: tablepb.TableStateReplicating,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 3, ResolvedTs: 5,
 					},
 				},
 				{
-					TableID: 2, State: tablepb.TableStateReplicating,
+					TableID: 2, Span: spanz.TableIDToComparableSpan(2),
+					State: tablepb.TableStateReplicating,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 4, ResolvedTs: 5,
 					},
@@ -412,3 +454,115 @@ func TestCoordinatorAdvanceCheckpoint(t *testing.T) {
 	require.EqualValues(t, 3, cts)
 	require.EqualValues(t, 5, rts)
 }
+
+func TestCoordinatorSpanChangeByTraffic(t *testing.T) {
+	t.Parallel()
+
+	coord := newCoordinator("a", model.ChangeFeedID{}, 1, keyspan.NewMockRegionCache(), &config.SchedulerConfig{
+		HeartbeatTick:      math.MaxInt,
+		MaxTaskConcurrency: 10,
+		AddTableBatchSize:  50,
+		RegionPerSpan:      1000,
+		WriteBytesPerSpan:  100,
+	})
+	trans := transport.NewMockTrans()
+	coord.trans = trans
+	coord.captureM.Captures["a"] = &member.CaptureStatus{State: member.CaptureStateInitialized}
+	coord.captureM.SetInitializedForTests(true)
+
+	// Table 1 is replicated by one span with two hot regions.
+	span := spanz.TableIDToComparableSpan(1)
+	midKey := append(append([]byte{}, span.StartKey...), 'm')
+	coord.replicationM.SetReplicationSetForTests(&replication.ReplicationSet{
+		Span:     span,
+		State:    replication.ReplicationSetStateReplicating,
+		Primary:  "a",
+		Captures: map[model.CaptureID]replication.Role{"a": replication.RolePrimary},
+	})
+	respond := func(state tablepb.TableState, round int) {
+		trans.RecvBuffer = append(trans.RecvBuffer, &schedulepb.Message{
+			Header: &schedulepb.Message_Header{
+				OwnerRevision: schedulepb.OwnerRevision{Revision: 1},
+			},
+			To:      "a",
+			From:    "a",
+			MsgType: schedulepb.MsgHeartbeatResponse,
+			HeartbeatResponse: &schedulepb.HeartbeatResponse{
+				Tables: []tablepb.TableStatus{{
+					TableID: 1,
+					Span:    span,
+					State:   state,
+					Checkpoint: tablepb.Checkpoint{
+						CheckpointTs: uint64(10 + round), ResolvedTs: uint64(10 + round),
+					},
+					Stats: tablepb.Stats{
+						RegionCount: 2,
+						CurrentTs:   oracle.ComposeTS(int64(round*1000), 0),
+						RegionWriteStats: []tablepb.RegionWriteStat{{
+							RegionID: 1, StartKey: span.StartKey, EndKey: midKey,
+							WrittenBytes: uint64(round * 1000),
+						}, {
+							RegionID: 2, StartKey: midKey, EndKey: span.EndKey,
+							WrittenBytes: uint64(round * 1000),
+						}},
+					},
+				}},
+			},
+		})
+	}
+
+	ctx := context.Background()
+	currentTables := []model.TableID{1}
+	aliveCaptures := map[model.CaptureID]*model.CaptureInfo{"a": {Version: "6.6.0"}}
+	// The span is split once it has been replicating for a while.
+	for round := 1; round < 3; round++ {
+		respond(tablepb.TableStateReplicating, round)
+		trans.SendBuffer = []*schedulepb.Message{}
+		_, _, err := coord.poll(ctx, 0, currentTables, aliveCaptures)
+		require.Nil(t, err)
+		require.Empty(t, trans.SendBuffer)
+	}
+
+	// The old span is removed first.
+	respond(tablepb.TableStateReplicating, 3)
+	trans.SendBuffer = []*schedulepb.Message{}
+	_, _, err := coord.poll(ctx, 0, currentTables, aliveCaptures)
+	require.Nil(t, err)
+	require.Len(t, trans.SendBuffer, 1)
+	require.Equal(t, span, trans.SendBuffer[0].DispatchTableRequest.GetRemoveTable().Span)
+	require.Len(t, coord.spanChanges, 1)
+
+	// New spans are not added until the old span is removed.
+	respond(tablepb.TableStateStopping, 4)
+	trans.SendBuffer = []*schedulepb.Message{}
+	_, _, err = coord.poll(ctx, 0, currentTables, aliveCaptures)
+	require.Nil(t, err)
+	require.Empty(t, trans.SendBuffer)
+
+	// New spans are added from the checkpoint of the old span.
+	respond(tablepb.TableStateStopped, 4)
+	trans.SendBuffer = []*schedulepb.Message{}
+	_, _, err = coord.poll(ctx, 0, currentTables, aliveCaptures)
+	require.Nil(t, err)
+	require.Len(t, trans.SendBuffer, 2)
+	addSpans := make([]tablepb.Span, 0, 2)
+	for _, msg := range trans.SendBuffer {
+		req := msg.DispatchTableRequest.GetAddTable()
+		require.NotNil(t, req)
+		require.Equal(t, "a", msg.To)
+		require.EqualValues(t, 13, req.Checkpoint.CheckpointTs)
+		addSpans = append(addSpans, req.Span)
+	}
+	require.ElementsMatch(t, []tablepb.Span{
+		{TableID: 1, StartKey: span.StartKey, EndKey: midKey},
+		{TableID: 1, StartKey: midKey, EndKey: span.EndKey},
+	}, addSpans)
+	require.Empty(t, coord.spanChanges)
+
+	// New spans are not added again.
+	trans.SendBuffer = []*schedulepb.Message{}
+	_, _, err = coord.poll(ctx, 0, currentTables, aliveCaptures)
+	require.Nil(t, err)
+	require.Empty(t, trans.SendBuffer)
+	require.Equal(t, 2, coord.replicationM.GetReplicationSetForTests().Len())
+}
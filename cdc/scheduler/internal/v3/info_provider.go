@@ -40,6 +40,12 @@ func (c *coordinator) GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus,
 			Tables: make(map[model.TableID]*model.TableReplicaInfo),
 		}
 		for _, s := range status.Tables {
+			// A table may have multiple spans on a capture, use the minimum
+			// checkpoint of its spans.
+			if info, ok := taskStatus.Tables[s.TableID]; ok &&
+				info.StartTs <= s.Checkpoint.CheckpointTs {
+				continue
+			}
 			taskStatus.Tables[s.TableID] = &model.TableReplicaInfo{
 				StartTs: s.Checkpoint.CheckpointTs,
 			}
@@ -48,3 +54,11 @@ func (c *coordinator) GetTaskStatuses() (map[model.CaptureID]*model.TaskStatus,
 	}
 	return tasks, nil
 }
+
+// GetTableCheckpoints returns the checkpoint ts of the tables.
+func (c *coordinator) GetTableCheckpoints() (map[model.TableID]model.Ts, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.replicationM.TableCheckpoints(), nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/compat"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
@@ -36,18 +37,31 @@ type Reconciler struct {
 	regionCache      RegionCache
 	changefeedID     model.ChangeFeedID
 	maxRegionPerSpan int
+
+	// Fields used by ReconcileTraffic.
+	writeBytesPerSpan uint64
+	regionSamples     map[uint64]regionWriteSample
+	spanRounds        *spanz.Map[int]
+	// changingTables are tables whose spans are being changed by write
+	// traffic, their spans are not reconciled from replications until
+	// FinishSpanChange is called.
+	changingTables map[model.TableID]struct{}
 }
 
 // NewReconciler returns a Reconciler.
 func NewReconciler(
 	changefeedID model.ChangeFeedID, regionCache RegionCache,
-	maxRegionPerSpan int,
+	cfg *config.SchedulerConfig,
 ) *Reconciler {
 	return &Reconciler{
-		tableSpans:       make(map[int64][]tablepb.Span),
-		regionCache:      regionCache,
-		changefeedID:     changefeedID,
-		maxRegionPerSpan: maxRegionPerSpan,
+		tableSpans:        make(map[int64][]tablepb.Span),
+		regionCache:       regionCache,
+		changefeedID:      changefeedID,
+		maxRegionPerSpan:  cfg.RegionPerSpan,
+		writeBytesPerSpan: cfg.WriteBytesPerSpan,
+		regionSamples:     make(map[uint64]regionWriteSample),
+		spanRounds:        spanz.NewMap[int](),
+		changingTables:    make(map[model.TableID]struct{}),
 	}
 }
 
@@ -74,6 +88,11 @@ func (m *Reconciler) Reconcile(
 			allTablesFound = false
 			updateCache = true
 		}
+		if _, ok := m.changingTables[tableID]; ok {
+			// Replications of the table do not match its spans until
+			// the span change is finished.
+			continue
+		}
 
 		// Reconcile spans from current replications.
 		tableStart, tableEnd := spanz.TableIDToComparableRange(tableID)
@@ -144,20 +163,25 @@ func (m *Reconciler) Reconcile(
 			if !ok {
 				// Found dropped table.
 				delete(m.tableSpans, tableID)
+				delete(m.changingTables, tableID)
 				updateCache = true
 			}
 		}
 	}
 
 	if updateCache {
-		m.spanCache = make([]tablepb.Span, 0)
-		for _, spans := range m.tableSpans {
-			m.spanCache = append(m.spanCache, spans...)
-		}
+		m.updateSpanCache()
 	}
 	return m.spanCache
 }
 
+func (m *Reconciler) updateSpanCache() {
+	m.spanCache = make([]tablepb.Span, 0)
+	for _, spans := range m.tableSpans {
+		m.spanCache = append(m.spanCache, spans...)
+	}
+}
+
 func (m *Reconciler) splitSpan(ctx context.Context, span tablepb.Span) []tablepb.Span {
 	bo := tikv.NewBackoffer(ctx, 500)
 	regions, err := m.regionCache.ListRegionIDsInKeyRange(bo, span.StartKey, span.EndKey)
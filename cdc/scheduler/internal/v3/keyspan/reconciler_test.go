@@ -88,7 +88,7 @@ func TestSplitSpan(t *testing.T) {
 
 	for i, cs := range cases {
 		cfg := &config.SchedulerConfig{RegionPerSpan: cs.regionPerSpan}
-		reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg.RegionPerSpan)
+		reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg)
 		spans := reconciler.splitSpan(context.Background(), cs.span)
 		require.Equalf(t, cs.expectSpans, spans, "%d %s", i, &cs.span)
 	}
@@ -103,7 +103,7 @@ func TestSplitSpanRegionOutOfOrder(t *testing.T) {
 	cache.regions.ReplaceOrInsert(tablepb.Span{StartKey: []byte("t1_2"), EndKey: []byte("t1_3")}, 3)
 
 	cfg := &config.SchedulerConfig{RegionPerSpan: 1}
-	reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg.RegionPerSpan)
+	reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg)
 	span := tablepb.Span{TableID: 1, StartKey: []byte("t1"), EndKey: []byte("t2")}
 	spans := reconciler.splitSpan(context.Background(), span)
 	require.Equal(
@@ -154,7 +154,7 @@ func TestReconcile(t *testing.T) {
 
 	// Test 1. changefeed initialization.
 	reps := spanz.NewMap[*replication.ReplicationSet]()
-	reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg.RegionPerSpan)
+	reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg)
 	spans := reconciler.Reconcile(ctx, []model.TableID{1}, reps, compat)
 	require.Equal(t, allSpan[:4], spans)
 	require.Equal(t, allSpan[:4], reconciler.tableSpans[1])
@@ -164,7 +164,7 @@ func TestReconcile(t *testing.T) {
 	for _, span := range reconciler.tableSpans[1] {
 		reps.ReplaceOrInsert(span, nil)
 	}
-	reconciler = NewReconciler(model.ChangeFeedID{}, cache, cfg.RegionPerSpan)
+	reconciler = NewReconciler(model.ChangeFeedID{}, cache, cfg)
 	spans = reconciler.Reconcile(ctx, []model.TableID{1}, reps, compat)
 	require.Equal(t, allSpan[:4], spans)
 	require.Equal(t, allSpan[:4], reconciler.tableSpans[1])
@@ -246,7 +246,7 @@ func TestCompatDisable(t *testing.T) {
 	require.False(t, cm.CheckSpanReplicationEnabled())
 	ctx := context.Background()
 	reps := spanz.NewMap[*replication.ReplicationSet]()
-	reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg.RegionPerSpan)
+	reconciler := NewReconciler(model.ChangeFeedID{}, cache, cfg)
 	spans := reconciler.Reconcile(ctx, []model.TableID{1}, reps, cm)
 	require.Equal(t, []tablepb.Span{spanz.TableIDToComparableSpan(1)}, spans)
 	require.Equal(t, 1, len(reconciler.tableSpans))
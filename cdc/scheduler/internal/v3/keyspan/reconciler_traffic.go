@@ -0,0 +1,320 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspan
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// trafficStableRounds is the number of rounds a span must be observed in
+// the replicating state before it can be split or merged. It makes sure the
+// write rate of the span is measured, and prevents a span from being split
+// and merged back and forth.
+const trafficStableRounds = 3
+
+// SpanChange describes a re-arrangement of spans of a table by write traffic.
+//
+// To preserve the order of changes of each key at the sink, OldSpans must be
+// removed before NewSpans are added, so that a key is replicated by exactly
+// one span at any time. NewSpans must start from CheckpointTs, the minimum
+// checkpoint ts of OldSpans, so that no change is lost. Changes that are
+// already replicated by OldSpans may be replicated again by NewSpans, which
+// is the same guarantee as moving a table between captures.
+type SpanChange struct {
+	TableID      model.TableID
+	OldSpans     []tablepb.Span
+	NewSpans     []tablepb.Span
+	CheckpointTs model.Ts
+}
+
+type regionWriteSample struct {
+	writtenBytes uint64
+	// physical time of the sample in milliseconds.
+	physicalTs int64
+	// bytes per second.
+	rate float64
+}
+
+type regionRate struct {
+	startKey tablepb.Key
+	rate     float64
+}
+
+type spanLoad struct {
+	span         tablepb.Span
+	checkpointTs model.Ts
+	regionCount  uint64
+	// regions are sorted by start key.
+	regions []regionRate
+	rate    float64
+}
+
+// ReconcileTraffic splits spans that are written faster than
+// `write-bytes-per-span` by region boundaries, and merges adjacent spans of
+// a table that are written slower than half of it. Only spans that have been
+// replicating for a while are considered. The write rate of regions is
+// computed from the region write stats reported by captures.
+//
+// The returned changes are already applied to the spans of the Reconciler.
+// Spans of the changed tables are not reconciled from replications, and the
+// tables are not reconciled by traffic again, until callers apply the changes
+// to replications and call FinishSpanChange.
+func (m *Reconciler) ReconcileTraffic(
+	replications *spanz.Map[*replication.ReplicationSet],
+) []SpanChange {
+	if m.writeBytesPerSpan == 0 {
+		return nil
+	}
+	rates := m.updateRegionRates(replications)
+
+	tableIDs := make([]model.TableID, 0, len(m.tableSpans))
+	for tableID := range m.tableSpans {
+		tableIDs = append(tableIDs, tableID)
+	}
+	sort.Slice(tableIDs, func(i, j int) bool { return tableIDs[i] < tableIDs[j] })
+
+	spanRounds := spanz.NewMap[int]()
+	var changes []SpanChange
+	for _, tableID := range tableIDs {
+		if _, ok := m.changingTables[tableID]; ok {
+			continue
+		}
+		spans := make([]tablepb.Span, len(m.tableSpans[tableID]))
+		copy(spans, m.tableSpans[tableID])
+		sort.Slice(spans, func(i, j int) bool {
+			return bytes.Compare(spans[i].StartKey, spans[j].StartKey) < 0
+		})
+
+		stable := true
+		loads := make([]spanLoad, 0, len(spans))
+		for _, span := range spans {
+			rounds := m.spanRounds.GetV(span)
+			rs, ok := replications.Get(span)
+			if !ok || rs.State != replication.ReplicationSetStateReplicating {
+				rounds = 0
+			} else {
+				rounds++
+			}
+			spanRounds.ReplaceOrInsert(span, rounds)
+			if rounds < trafficStableRounds {
+				stable = false
+				continue
+			}
+			loads = append(loads, newSpanLoad(span, rs, rates))
+		}
+		if !stable {
+			continue
+		}
+		tableSpans, tableChanges := m.planTraffic(loads)
+		if len(tableChanges) == 0 {
+			continue
+		}
+		for _, change := range tableChanges {
+			log.Info("schedulerv3: reconcile spans by write traffic",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Int64("tableID", tableID),
+				zap.Int("oldSpans", len(change.OldSpans)),
+				zap.Int("newSpans", len(change.NewSpans)),
+				zap.Uint64("checkpointTs", change.CheckpointTs))
+		}
+		m.tableSpans[tableID] = tableSpans
+		m.changingTables[tableID] = struct{}{}
+		changes = append(changes, tableChanges...)
+	}
+	m.spanRounds = spanRounds
+	if len(changes) != 0 {
+		m.updateSpanCache()
+	}
+	return changes
+}
+
+// FinishSpanChange marks the span change of the table as finished, so that
+// spans of the table are reconciled from replications again.
+func (m *Reconciler) FinishSpanChange(tableID model.TableID) {
+	delete(m.changingTables, tableID)
+}
+
+// updateRegionRates updates write samples of regions and returns the write
+// rate of each region in bytes per second.
+func (m *Reconciler) updateRegionRates(
+	replications *spanz.Map[*replication.ReplicationSet],
+) map[uint64]float64 {
+	samples := make(map[uint64]regionWriteSample)
+	replications.Ascend(func(_ tablepb.Span, rs *replication.ReplicationSet) bool {
+		physicalTs := oracle.ExtractPhysical(rs.Stats.CurrentTs)
+		for _, stat := range rs.Stats.RegionWriteStats {
+			sample := regionWriteSample{
+				writtenBytes: stat.WrittenBytes,
+				physicalTs:   physicalTs,
+			}
+			prev, ok := m.regionSamples[stat.RegionID]
+			if ok {
+				if physicalTs <= prev.physicalTs {
+					// Stats are not updated since the last sample.
+					sample = prev
+				} else if stat.WrittenBytes >= prev.writtenBytes {
+					sample.rate = float64(stat.WrittenBytes-prev.writtenBytes) /
+						(float64(physicalTs-prev.physicalTs) / 1000)
+				}
+				// Otherwise, the region is captured again and its written
+				// bytes restart from zero, wait for the next sample.
+			}
+			samples[stat.RegionID] = sample
+		}
+		return true
+	})
+	m.regionSamples = samples
+
+	rates := make(map[uint64]float64, len(samples))
+	for regionID, sample := range samples {
+		rates[regionID] = sample.rate
+	}
+	return rates
+}
+
+func newSpanLoad(
+	span tablepb.Span, rs *replication.ReplicationSet, rates map[uint64]float64,
+) spanLoad {
+	load := spanLoad{
+		span:         span,
+		checkpointTs: rs.Checkpoint.CheckpointTs,
+		regionCount:  rs.Stats.RegionCount,
+	}
+	for _, stat := range rs.Stats.RegionWriteStats {
+		// Ignore regions out of the span.
+		if bytes.Compare(stat.StartKey, span.EndKey) >= 0 ||
+			bytes.Compare(stat.EndKey, span.StartKey) <= 0 {
+			continue
+		}
+		rate := rates[stat.RegionID]
+		load.regions = append(load.regions, regionRate{
+			startKey: stat.StartKey,
+			rate:     rate,
+		})
+		load.rate += rate
+	}
+	sort.Slice(load.regions, func(i, j int) bool {
+		return bytes.Compare(load.regions[i].startKey, load.regions[j].startKey) < 0
+	})
+	return load
+}
+
+// planTraffic returns the new spans of a table and the changes to get there.
+// loads must be sorted by span start key.
+func (m *Reconciler) planTraffic(loads []spanLoad) ([]tablepb.Span, []SpanChange) {
+	threshold := float64(m.writeBytesPerSpan)
+	spans := make([]tablepb.Span, 0, len(loads))
+	var changes []SpanChange
+	for i := 0; i < len(loads); {
+		load := loads[i]
+		if load.rate > threshold {
+			pieces := splitSpanByTraffic(load, threshold)
+			if len(pieces) > 1 {
+				changes = append(changes, SpanChange{
+					TableID:      load.span.TableID,
+					OldSpans:     []tablepb.Span{load.span},
+					NewSpans:     pieces,
+					CheckpointTs: load.checkpointTs,
+				})
+				spans = append(spans, pieces...)
+				i++
+				continue
+			}
+			// A single hot region can not be split any further.
+		}
+
+		// Merge adjacent cold spans. Spans are merged only if they are
+		// written slower than half of the threshold in total, so that
+		// the merged span is not split again soon.
+		j := i + 1
+		rate, regionCount := load.rate, load.regionCount
+		for ; j < len(loads); j++ {
+			next := loads[j]
+			if !bytes.Equal(loads[j-1].span.EndKey, next.span.StartKey) ||
+				rate+next.rate >= threshold/2 {
+				break
+			}
+			if m.maxRegionPerSpan != 0 &&
+				regionCount+next.regionCount > uint64(m.maxRegionPerSpan) {
+				break
+			}
+			rate += next.rate
+			regionCount += next.regionCount
+		}
+		if j-i == 1 {
+			spans = append(spans, load.span)
+			i++
+			continue
+		}
+		change := SpanChange{
+			TableID:      load.span.TableID,
+			CheckpointTs: load.checkpointTs,
+		}
+		for _, l := range loads[i:j] {
+			change.OldSpans = append(change.OldSpans, l.span)
+			if l.checkpointTs < change.CheckpointTs {
+				change.CheckpointTs = l.checkpointTs
+			}
+		}
+		merged := tablepb.Span{
+			TableID:  load.span.TableID,
+			StartKey: load.span.StartKey,
+			EndKey:   loads[j-1].span.EndKey,
+		}
+		change.NewSpans = []tablepb.Span{merged}
+		changes = append(changes, change)
+		spans = append(spans, merged)
+		i = j
+	}
+	return spans, changes
+}
+
+// splitSpanByTraffic splits a span at region start keys, so that each piece
+// is written no faster than the threshold unless it has only one region.
+func splitSpanByTraffic(load spanLoad, threshold float64) []tablepb.Span {
+	span := load.span
+	pieces := make([]tablepb.Span, 0, 2)
+	start := span.StartKey
+	rate := float64(0)
+	for _, region := range load.regions {
+		if rate > 0 && rate+region.rate > threshold &&
+			bytes.Compare(region.startKey, start) > 0 &&
+			bytes.Compare(region.startKey, span.EndKey) < 0 {
+			end := append(tablepb.Key(nil), region.startKey...)
+			pieces = append(pieces, tablepb.Span{
+				TableID:  span.TableID,
+				StartKey: start,
+				EndKey:   end,
+			})
+			start, rate = end, 0
+		}
+		rate += region.rate
+	}
+	pieces = append(pieces, tablepb.Span{
+		TableID:  span.TableID,
+		StartKey: start,
+		EndKey:   span.EndKey,
+	})
+	return pieces
+}
@@ -0,0 +1,158 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspan
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func newTrafficReplicationSet(
+	checkpointTs model.Ts, physicalTs int64, regionCount uint64,
+	stats ...tablepb.RegionWriteStat,
+) *replication.ReplicationSet {
+	return &replication.ReplicationSet{
+		State:      replication.ReplicationSetStateReplicating,
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: checkpointTs},
+		Stats: tablepb.Stats{
+			CurrentTs:        oracle.ComposeTS(physicalTs, 0),
+			RegionCount:      regionCount,
+			RegionWriteStats: stats,
+		},
+	}
+}
+
+func regionStat(id uint64, start, end string, writtenBytes uint64) tablepb.RegionWriteStat {
+	return tablepb.RegionWriteStat{
+		RegionID:     id,
+		StartKey:     []byte(start),
+		EndKey:       []byte(end),
+		WrittenBytes: writtenBytes,
+	}
+}
+
+func TestReconcileTrafficSplit(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.SchedulerConfig{RegionPerSpan: 1000, WriteBytesPerSpan: 100}
+	reconciler := NewReconciler(model.ChangeFeedID{}, NewMockRegionCache(), cfg)
+	span := tablepb.Span{TableID: 1, StartKey: []byte("t1"), EndKey: []byte("t2")}
+	reconciler.tableSpans[1] = []tablepb.Span{span}
+
+	reps := spanz.NewMap[*replication.ReplicationSet]()
+	report := func(physicalTs int64, written uint64) {
+		reps.ReplaceOrInsert(span, newTrafficReplicationSet(10, physicalTs, 3,
+			regionStat(1, "t1", "t1_1", written),
+			regionStat(2, "t1_1", "t1_2", written*3/2),
+			regionStat(3, "t1_2", "t2", written*2)))
+	}
+
+	// Spans are not changed until they are observed for a while.
+	for i := 1; i < trafficStableRounds; i++ {
+		report(int64(i*1000), uint64(i*100))
+		require.Empty(t, reconciler.ReconcileTraffic(reps))
+	}
+	// Write rates of regions are 100, 150 and 200 bytes per second.
+	report(trafficStableRounds*1000, trafficStableRounds*100)
+	changes := reconciler.ReconcileTraffic(reps)
+	expected := []tablepb.Span{
+		{TableID: 1, StartKey: []byte("t1"), EndKey: []byte("t1_1")},
+		{TableID: 1, StartKey: []byte("t1_1"), EndKey: []byte("t1_2")},
+		{TableID: 1, StartKey: []byte("t1_2"), EndKey: []byte("t2")},
+	}
+	require.Equal(t, []SpanChange{{
+		TableID:      1,
+		OldSpans:     []tablepb.Span{span},
+		NewSpans:     expected,
+		CheckpointTs: 10,
+	}}, changes)
+	require.Equal(t, expected, reconciler.tableSpans[1])
+	require.ElementsMatch(t, expected, reconciler.spanCache)
+
+	// New spans are not replicating yet.
+	require.Empty(t, reconciler.ReconcileTraffic(reps))
+}
+
+func TestReconcileTrafficHotRegion(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.SchedulerConfig{RegionPerSpan: 1000, WriteBytesPerSpan: 100}
+	reconciler := NewReconciler(model.ChangeFeedID{}, NewMockRegionCache(), cfg)
+	span := tablepb.Span{TableID: 1, StartKey: []byte("t1"), EndKey: []byte("t2")}
+	reconciler.tableSpans[1] = []tablepb.Span{span}
+
+	reps := spanz.NewMap[*replication.ReplicationSet]()
+	for i := 1; i <= trafficStableRounds+1; i++ {
+		// A single hot region can not be split.
+		reps.ReplaceOrInsert(span, newTrafficReplicationSet(10, int64(i*1000), 1,
+			regionStat(1, "t1", "t2", uint64(i*1000))))
+		require.Empty(t, reconciler.ReconcileTraffic(reps))
+	}
+	require.Equal(t, []tablepb.Span{span}, reconciler.tableSpans[1])
+}
+
+func TestReconcileTrafficMerge(t *testing.T) {
+	t.Parallel()
+
+	spans := []tablepb.Span{
+		{TableID: 1, StartKey: []byte("t1"), EndKey: []byte("t1_1")},
+		{TableID: 1, StartKey: []byte("t1_1"), EndKey: []byte("t1_2")},
+		{TableID: 1, StartKey: []byte("t1_2"), EndKey: []byte("t1_3")},
+		{TableID: 1, StartKey: []byte("t1_3"), EndKey: []byte("t2")},
+	}
+	reconcile := func(secondRegionCount uint64) []SpanChange {
+		cfg := &config.SchedulerConfig{RegionPerSpan: 1000, WriteBytesPerSpan: 100}
+		reconciler := NewReconciler(model.ChangeFeedID{}, NewMockRegionCache(), cfg)
+		reconciler.tableSpans[1] = spans
+		reps := spanz.NewMap[*replication.ReplicationSet]()
+		var changes []SpanChange
+		for i := 1; i <= trafficStableRounds; i++ {
+			ms := int64(i * 1000)
+			// Write rates are 10, 20, 100 and 10 bytes per second.
+			reps.ReplaceOrInsert(spans[0], newTrafficReplicationSet(30, ms, 600,
+				regionStat(1, "t1", "t1_1", uint64(i*10))))
+			reps.ReplaceOrInsert(spans[1], newTrafficReplicationSet(20, ms, secondRegionCount,
+				regionStat(2, "t1_1", "t1_2", uint64(i*20))))
+			reps.ReplaceOrInsert(spans[2], newTrafficReplicationSet(40, ms, 1,
+				regionStat(3, "t1_2", "t1_3", uint64(i*100))))
+			reps.ReplaceOrInsert(spans[3], newTrafficReplicationSet(50, ms, 1,
+				regionStat(4, "t1_3", "t2", uint64(i*10))))
+			changes = reconciler.ReconcileTraffic(reps)
+			if i < trafficStableRounds {
+				require.Empty(t, changes)
+			}
+		}
+		return changes
+	}
+
+	// The first two spans are merged and start from the minimum checkpoint
+	// ts, the third span is too hot to be merged with the last span.
+	merged := tablepb.Span{TableID: 1, StartKey: []byte("t1"), EndKey: []byte("t1_2")}
+	require.Equal(t, []SpanChange{{
+		TableID:      1,
+		OldSpans:     spans[:2],
+		NewSpans:     []tablepb.Span{merged},
+		CheckpointTs: 20,
+	}}, reconcile(400))
+
+	// Spans are not merged if the merged span has too many regions.
+	require.Empty(t, reconcile(401))
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/label"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
 
@@ -62,17 +64,28 @@ type CaptureStatus struct {
 	ID       model.CaptureID
 	Addr     string
 	IsOwner  bool
+	Labels   label.Set
 }
 
 func newCaptureStatus(
-	rev schedulepb.OwnerRevision, id model.CaptureID, addr string, isOwner bool,
+	rev schedulepb.OwnerRevision, id model.CaptureID, addr string,
+	labels map[string]string, isOwner bool,
 ) *CaptureStatus {
+	labelSet, err := label.NewSetFromMap(labels)
+	if err != nil {
+		log.Warn("schedulerv3: ignore invalid capture labels",
+			zap.String("capture", id),
+			zap.Any("labels", labels),
+			zap.Error(err))
+		labelSet = label.NewSet()
+	}
 	return &CaptureStatus{
 		OwnerRev: rev,
 		State:    CaptureStateUninitialized,
 		ID:       id,
 		Addr:     addr,
 		IsOwner:  isOwner,
+		Labels:   labelSet,
 	}
 }
 
@@ -163,26 +176,27 @@ func (c *CaptureManager) checkAllCaptureInitialized() bool {
 // Tick advances the logical lock of capture manager and produce heartbeat when
 // necessary.
 func (c *CaptureManager) Tick(
-	reps map[model.TableID]*replication.ReplicationSet, drainingCapture model.CaptureID,
+	reps *spanz.Map[*replication.ReplicationSet], drainingCapture model.CaptureID,
 ) []*schedulepb.Message {
 	c.tickCounter++
 	if c.tickCounter < c.heartbeatTick {
 		return nil
 	}
 	c.tickCounter = 0
-	tables := make(map[model.CaptureID][]model.TableID)
-	for tableID, rep := range reps {
+	spans := make(map[model.CaptureID][]tablepb.Span)
+	reps.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
 		for captureID := range rep.Captures {
-			tables[captureID] = append(tables[captureID], tableID)
+			spans[captureID] = append(spans[captureID], span)
 		}
-	}
+		return true
+	})
 	msgs := make([]*schedulepb.Message, 0, len(c.Captures))
 	for to := range c.Captures {
 		msgs = append(msgs, &schedulepb.Message{
 			To:      to,
 			MsgType: schedulepb.MsgHeartbeat,
 			Heartbeat: &schedulepb.Heartbeat{
-				TableIDs: tables[to],
+				Spans: spans[to],
 				// IsStopping let the receiver capture know that it should be stopping now.
 				// At the moment, this is triggered by `DrainCapture` scheduler.
 				IsStopping: drainingCapture == to,
@@ -219,7 +233,7 @@ func (c *CaptureManager) HandleAliveCaptureUpdate(
 		if _, ok := c.Captures[id]; !ok {
 			// A new capture.
 			c.Captures[id] = newCaptureStatus(
-				c.OwnerRev, id, info.AdvertiseAddr, c.ownerID == id)
+				c.OwnerRev, id, info.AdvertiseAddr, info.Labels, c.ownerID == id)
 			log.Info("schedulerv3: find a new capture",
 				zap.String("captureAddr", info.AdvertiseAddr),
 				zap.String("capture", id))
@@ -20,6 +20,8 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/label"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,7 +34,7 @@ func TestCaptureStatusHandleHeartbeatResponse(t *testing.T) {
 
 	rev := schedulepb.OwnerRevision{Revision: 1}
 	epoch := schedulepb.ProcessorEpoch{Epoch: "test"}
-	c := newCaptureStatus(rev, "", "", true)
+	c := newCaptureStatus(rev, "", "", nil, true)
 	require.Equal(t, CaptureStateUninitialized, c.State)
 	require.True(t, c.IsOwner)
 
@@ -54,6 +56,20 @@ func TestCaptureStatusHandleHeartbeatResponse(t *testing.T) {
 	require.Equal(t, epoch, c.Epoch)
 }
 
+func TestCaptureStatusLabels(t *testing.T) {
+	t.Parallel()
+
+	rev := schedulepb.OwnerRevision{Revision: 1}
+	cm := NewCaptureManager("1", model.ChangeFeedID{}, rev, 2)
+	cm.HandleAliveCaptureUpdate(map[model.CaptureID]*model.CaptureInfo{
+		"1": {Labels: map[string]string{"zone": "us-west-1a"}},
+		"2": {Labels: map[string]string{"zone": "us west"}},
+	})
+	require.Equal(t, label.Set{"zone": "us-west-1a"}, cm.Captures["1"].Labels)
+	// Invalid labels are ignored.
+	require.Empty(t, cm.Captures["2"].Labels)
+}
+
 func TestCaptureManagerHandleAliveCaptureUpdate(t *testing.T) {
 	t.Parallel()
 
@@ -181,9 +197,9 @@ func TestCaptureManagerTick(t *testing.T) {
 	cm := NewCaptureManager("", model.ChangeFeedID{}, rev, 2)
 
 	// No heartbeat if there is no capture.
-	msgs := cm.Tick(nil, captureIDNotDraining)
+	msgs := cm.Tick(spanz.NewMap[*replication.ReplicationSet](), captureIDNotDraining)
 	require.Empty(t, msgs)
-	msgs = cm.Tick(nil, captureIDNotDraining)
+	msgs = cm.Tick(spanz.NewMap[*replication.ReplicationSet](), captureIDNotDraining)
 	require.Empty(t, msgs)
 
 	ms := map[model.CaptureID]*model.CaptureInfo{
@@ -193,9 +209,9 @@ func TestCaptureManagerTick(t *testing.T) {
 	cm.HandleAliveCaptureUpdate(ms)
 
 	// Heartbeat even if capture is uninitialized.
-	msgs = cm.Tick(nil, captureIDNotDraining)
+	msgs = cm.Tick(spanz.NewMap[*replication.ReplicationSet](), captureIDNotDraining)
 	require.Empty(t, msgs)
-	msgs = cm.Tick(nil, captureIDNotDraining)
+	msgs = cm.Tick(spanz.NewMap[*replication.ReplicationSet](), captureIDNotDraining)
 	require.ElementsMatch(t, []*schedulepb.Message{
 		{To: "1", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
 		{To: "2", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
@@ -205,37 +221,48 @@ func TestCaptureManagerTick(t *testing.T) {
 	for _, s := range []CaptureState{CaptureStateInitialized, CaptureStateStopping} {
 		cm.Captures["1"].State = s
 		cm.Captures["2"].State = s
-		msgs = cm.Tick(nil, captureIDNotDraining)
+		msgs = cm.Tick(spanz.NewMap[*replication.ReplicationSet](), captureIDNotDraining)
 		require.Empty(t, msgs)
-		msgs = cm.Tick(nil, captureIDNotDraining)
+		msgs = cm.Tick(spanz.NewMap[*replication.ReplicationSet](), captureIDNotDraining)
 		require.ElementsMatch(t, []*schedulepb.Message{
 			{To: "1", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
 			{To: "2", MsgType: schedulepb.MsgHeartbeat, Heartbeat: &schedulepb.Heartbeat{}},
 		}, msgs)
 	}
 
-	// TableID in heartbeat.
-	msgs = cm.Tick(nil, captureIDNotDraining)
+	// Spans in heartbeat.
+	msgs = cm.Tick(spanz.NewMap[*replication.ReplicationSet](), captureIDNotDraining)
 	require.Empty(t, msgs)
-	tables := map[model.TableID]*replication.ReplicationSet{
-		1: {Captures: map[model.CaptureID]replication.Role{
+	reps := spanz.NewMap[*replication.ReplicationSet]()
+	reps.ReplaceOrInsert(spanz.TableIDToComparableSpan(1), &replication.ReplicationSet{
+		Captures: map[model.CaptureID]replication.Role{
 			"1": replication.RolePrimary,
-		}},
-		2: {Captures: map[model.CaptureID]replication.Role{
+		},
+	})
+	reps.ReplaceOrInsert(spanz.TableIDToComparableSpan(2), &replication.ReplicationSet{
+		Captures: map[model.CaptureID]replication.Role{
 			"1": replication.RolePrimary, "2": replication.RoleSecondary,
-		}},
-		3: {Captures: map[model.CaptureID]replication.Role{
+		},
+	})
+	reps.ReplaceOrInsert(spanz.TableIDToComparableSpan(3), &replication.ReplicationSet{
+		Captures: map[model.CaptureID]replication.Role{
 			"2": replication.RoleSecondary,
-		}},
-		4: {},
-	}
-	msgs = cm.Tick(tables, captureIDNotDraining)
+		},
+	})
+	reps.ReplaceOrInsert(spanz.TableIDToComparableSpan(4), &replication.ReplicationSet{})
+	msgs = cm.Tick(reps, captureIDNotDraining)
 	require.Len(t, msgs, 2)
+	spans12 := []tablepb.Span{
+		spanz.TableIDToComparableSpan(1), spanz.TableIDToComparableSpan(2),
+	}
+	spans23 := []tablepb.Span{
+		spanz.TableIDToComparableSpan(2), spanz.TableIDToComparableSpan(3),
+	}
 	if msgs[0].To == "1" {
-		require.ElementsMatch(t, []model.TableID{1, 2}, msgs[0].Heartbeat.TableIDs)
-		require.ElementsMatch(t, []model.TableID{2, 3}, msgs[1].Heartbeat.TableIDs)
+		require.ElementsMatch(t, spans12, msgs[0].Heartbeat.Spans)
+		require.ElementsMatch(t, spans23, msgs[1].Heartbeat.Spans)
 	} else {
-		require.ElementsMatch(t, []model.TableID{2, 3}, msgs[0].Heartbeat.TableIDs)
-		require.ElementsMatch(t, []model.TableID{1, 2}, msgs[1].Heartbeat.TableIDs)
+		require.ElementsMatch(t, spans23, msgs[0].Heartbeat.Spans)
+		require.ElementsMatch(t, spans12, msgs[1].Heartbeat.Spans)
 	}
 }
@@ -14,6 +14,7 @@
 package replication
 
 import (
+	"bytes"
 	"container/heap"
 	"math"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
@@ -47,22 +49,22 @@ type BurstBalance struct {
 	MoveTables   []MoveTable
 }
 
-// MoveTable is a schedule task for moving a table.
+// MoveTable is a schedule task for moving a table span.
 type MoveTable struct {
-	TableID     model.TableID
+	Span        tablepb.Span
 	DestCapture model.CaptureID
 }
 
-// AddTable is a schedule task for adding a table.
+// AddTable is a schedule task for adding a table span.
 type AddTable struct {
-	TableID      model.TableID
+	Span         tablepb.Span
 	CaptureID    model.CaptureID
 	CheckpointTs model.Ts
 }
 
-// RemoveTable is a schedule task for removing a table.
+// RemoveTable is a schedule task for removing a table span.
 type RemoveTable struct {
-	TableID   model.TableID
+	Span      tablepb.Span
 	CaptureID model.CaptureID
 }
 
@@ -92,13 +94,13 @@ func (s *ScheduleTask) Name() string {
 
 // Manager manages replications and running scheduling tasks.
 type Manager struct { //nolint:revive
-	tables map[model.TableID]*ReplicationSet
+	spans *spanz.Map[*ReplicationSet]
 
-	runningTasks       map[model.TableID]*ScheduleTask
+	runningTasks       *spanz.Map[*ScheduleTask]
 	maxTaskConcurrency int
 
 	changefeedID           model.ChangeFeedID
-	slowestTableID         model.TableID
+	slowestSpan            tablepb.Span
 	slowTableHeap          SetHeap
 	acceptAddTableTask     int
 	acceptRemoveTableTask  int
@@ -116,8 +118,8 @@ func NewReplicationManager(
 	heap.Init(&slowTableHeap)
 
 	return &Manager{
-		tables:                make(map[int64]*ReplicationSet),
-		runningTasks:          make(map[int64]*ScheduleTask),
+		spans:                 spanz.NewMap[*ReplicationSet](),
+		runningTasks:          spanz.NewMap[*ScheduleTask](),
 		maxTaskConcurrency:    maxTaskConcurrency,
 		changefeedID:          changefeedID,
 		slowTableHeap:         slowTableHeap,
@@ -132,45 +134,61 @@ func (r *Manager) HandleCaptureChanges(
 	checkpointTs model.Ts,
 ) ([]*schedulepb.Message, error) {
 	if init != nil {
-		if len(r.tables) != 0 {
+		if r.spans.Len() != 0 {
 			log.Panic("schedulerv3: init again",
 				zap.String("namespace", r.changefeedID.Namespace),
 				zap.String("changefeed", r.changefeedID.ID),
-				zap.Any("init", init), zap.Any("tables", r.tables))
+				zap.Any("init", init), zap.Int("spans", r.spans.Len()))
 		}
-		tableStatus := map[model.TableID]map[model.CaptureID]*tablepb.TableStatus{}
+		spanStatus := spanz.NewMap[map[model.CaptureID]*tablepb.TableStatus]()
 		for captureID, tables := range init {
 			for i := range tables {
 				table := tables[i]
-				if _, ok := tableStatus[table.TableID]; !ok {
-					tableStatus[table.TableID] = map[model.CaptureID]*tablepb.TableStatus{}
+				status, ok := spanStatus.Get(table.Span)
+				if !ok {
+					status = map[model.CaptureID]*tablepb.TableStatus{}
+					spanStatus.ReplaceOrInsert(table.Span, status)
 				}
-				tableStatus[table.TableID][captureID] = &table
+				status[captureID] = &table
 			}
 		}
-		for tableID, status := range tableStatus {
-			table, err := NewReplicationSet(
-				tableID, checkpointTs, status, r.changefeedID)
+		var err error
+		spanStatus.Ascend(func(
+			span tablepb.Span, status map[model.CaptureID]*tablepb.TableStatus,
+		) bool {
+			var table *ReplicationSet
+			table, err = NewReplicationSet(span, checkpointTs, status, r.changefeedID)
 			if err != nil {
-				return nil, errors.Trace(err)
+				return false
 			}
-			r.tables[tableID] = table
+			r.spans.ReplaceOrInsert(span, table)
+			return true
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
 	}
 	sentMsgs := make([]*schedulepb.Message, 0)
 	if removed != nil {
-		for _, table := range r.tables {
+		var err error
+		r.spans.Ascend(func(span tablepb.Span, table *ReplicationSet) bool {
 			for captureID := range removed {
-				msgs, affected, err := table.handleCaptureShutdown(captureID)
+				var msgs []*schedulepb.Message
+				var affected bool
+				msgs, affected, err = table.handleCaptureShutdown(captureID)
 				if err != nil {
-					return nil, errors.Trace(err)
+					return false
 				}
 				sentMsgs = append(sentMsgs, msgs...)
 				if affected {
 					// Cleanup its running task.
-					delete(r.runningTasks, table.TableID)
+					r.runningTasks.Delete(span)
 				}
 			}
+			return true
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
 	}
 	return sentMsgs, nil
@@ -211,7 +229,7 @@ func (r *Manager) handleMessageHeartbeatResponse(
 ) ([]*schedulepb.Message, error) {
 	sentMsgs := make([]*schedulepb.Message, 0)
 	for _, status := range msg.Tables {
-		table, ok := r.tables[status.TableID]
+		table, ok := r.spans.Get(status.Span)
 		if !ok {
 			log.Info("schedulerv3: ignore table status no table found",
 				zap.String("namespace", r.changefeedID.Namespace),
@@ -227,8 +245,8 @@ func (r *Manager) handleMessageHeartbeatResponse(
 			log.Info("schedulerv3: table has removed",
 				zap.String("namespace", r.changefeedID.Namespace),
 				zap.String("changefeed", r.changefeedID.ID),
-				zap.Int64("tableID", status.TableID))
-			delete(r.tables, status.TableID)
+				zap.Stringer("span", &status.Span))
+			r.spans.Delete(status.Span)
 		}
 		sentMsgs = append(sentMsgs, msgs...)
 	}
@@ -252,7 +270,7 @@ func (r *Manager) handleMessageDispatchTableResponse(
 		return nil, nil
 	}
 
-	table, ok := r.tables[status.TableID]
+	table, ok := r.spans.Get(status.Span)
 	if !ok {
 		log.Info("schedulerv3: ignore table status no table found",
 			zap.String("namespace", r.changefeedID.Namespace),
@@ -268,8 +286,8 @@ func (r *Manager) handleMessageDispatchTableResponse(
 		log.Info("schedulerv3: table has removed",
 			zap.String("namespace", r.changefeedID.Namespace),
 			zap.String("changefeed", r.changefeedID.ID),
-			zap.Int64("tableID", status.TableID))
-		delete(r.tables, status.TableID)
+			zap.Stringer("span", &status.Span))
+		r.spans.Delete(status.Span)
 	}
 	return msgs, nil
 }
@@ -279,17 +297,22 @@ func (r *Manager) HandleTasks(
 	tasks []*ScheduleTask,
 ) ([]*schedulepb.Message, error) {
 	// Check if a running task is finished.
-	for tableID := range r.runningTasks {
-		if table, ok := r.tables[tableID]; ok {
+	var finishedTasks []tablepb.Span
+	r.runningTasks.Ascend(func(span tablepb.Span, _ *ScheduleTask) bool {
+		if table, ok := r.spans.Get(span); ok {
 			// If table is back to Replicating or Removed,
 			// the running task is finished.
 			if table.State == ReplicationSetStateReplicating || table.hasRemoved() {
-				delete(r.runningTasks, tableID)
+				finishedTasks = append(finishedTasks, span)
 			}
 		} else {
 			// No table found, remove the task
-			delete(r.runningTasks, tableID)
+			finishedTasks = append(finishedTasks, span)
 		}
+		return true
+	})
+	for _, span := range finishedTasks {
+		r.runningTasks.Delete(span)
 	}
 
 	sentMsgs := make([]*schedulepb.Message, 0)
@@ -308,7 +331,7 @@ func (r *Manager) HandleTasks(
 		}
 
 		// Check if accepting one more task exceeds maxTaskConcurrency.
-		if len(r.runningTasks) == r.maxTaskConcurrency {
+		if r.runningTasks.Len() == r.maxTaskConcurrency {
 			log.Debug("schedulerv3: too many running task",
 				zap.String("namespace", r.changefeedID.Namespace),
 				zap.String("changefeed", r.changefeedID.ID))
@@ -317,25 +340,25 @@ func (r *Manager) HandleTasks(
 			continue
 		}
 
-		var tableID model.TableID
+		var span tablepb.Span
 		if task.AddTable != nil {
-			tableID = task.AddTable.TableID
+			span = task.AddTable.Span
 		} else if task.RemoveTable != nil {
-			tableID = task.RemoveTable.TableID
+			span = task.RemoveTable.Span
 		} else if task.MoveTable != nil {
-			tableID = task.MoveTable.TableID
+			span = task.MoveTable.Span
 		}
 
 		// Skip task if the table is already running a task,
 		// or the table has removed.
-		if _, ok := r.runningTasks[tableID]; ok {
+		if r.runningTasks.Has(span) {
 			log.Info("schedulerv3: ignore task, already exists",
 				zap.String("namespace", r.changefeedID.Namespace),
 				zap.String("changefeed", r.changefeedID.ID),
 				zap.Any("task", task))
 			continue
 		}
-		if _, ok := r.tables[tableID]; !ok && task.AddTable == nil {
+		if !r.spans.Has(span) && task.AddTable == nil {
 			log.Info("schedulerv3: ignore task, table not found",
 				zap.String("namespace", r.changefeedID.Namespace),
 				zap.String("changefeed", r.changefeedID.ID),
@@ -356,7 +379,7 @@ func (r *Manager) HandleTasks(
 			return nil, errors.Trace(err)
 		}
 		sentMsgs = append(sentMsgs, msgs...)
-		r.runningTasks[tableID] = task
+		r.runningTasks.ReplaceOrInsert(span, task)
 		if task.Accept != nil {
 			task.Accept()
 		}
@@ -369,14 +392,14 @@ func (r *Manager) handleAddTableTask(
 ) ([]*schedulepb.Message, error) {
 	r.acceptAddTableTask++
 	var err error
-	table := r.tables[task.TableID]
-	if table == nil {
+	table, ok := r.spans.Get(task.Span)
+	if !ok {
 		table, err = NewReplicationSet(
-			task.TableID, task.CheckpointTs, nil, r.changefeedID)
+			task.Span, task.CheckpointTs, nil, r.changefeedID)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		r.tables[task.TableID] = table
+		r.spans.ReplaceOrInsert(task.Span, table)
 	}
 	return table.handleAddTable(task.CaptureID)
 }
@@ -385,13 +408,15 @@ func (r *Manager) handleRemoveTableTask(
 	task *RemoveTable,
 ) ([]*schedulepb.Message, error) {
 	r.acceptRemoveTableTask++
-	table := r.tables[task.TableID]
-	if table.hasRemoved() {
+	table := r.spans.GetV(task.Span)
+	// An absent table is not replicated by any capture, remove it directly.
+	if table.hasRemoved() ||
+		(table.State == ReplicationSetStateAbsent && len(table.Captures) == 0) {
 		log.Info("schedulerv3: table has removed",
 			zap.String("namespace", r.changefeedID.Namespace),
 			zap.String("changefeed", r.changefeedID.ID),
-			zap.Int64("tableID", task.TableID))
-		delete(r.tables, task.TableID)
+			zap.Stringer("span", &task.Span))
+		r.spans.Delete(task.Span)
 		return nil, nil
 	}
 	return table.handleRemoveTable()
@@ -401,7 +426,7 @@ func (r *Manager) handleMoveTableTask(
 	task *MoveTable,
 ) ([]*schedulepb.Message, error) {
 	r.acceptMoveTableTask++
-	table := r.tables[task.TableID]
+	table := r.spans.GetV(task.Span)
 	return table.handleMoveTable(task.DestCapture)
 }
 
@@ -430,7 +455,7 @@ func (r *Manager) handleBurstBalanceTasks(
 	sentMsgs := make([]*schedulepb.Message, 0, len(task.AddTables))
 	for i := range task.AddTables {
 		addTable := task.AddTables[i]
-		if r.runningTasks[addTable.TableID] != nil {
+		if r.runningTasks.Has(addTable.Span) {
 			// Skip add table if the table is already running a task.
 			continue
 		}
@@ -440,11 +465,11 @@ func (r *Manager) handleBurstBalanceTasks(
 		}
 		sentMsgs = append(sentMsgs, msgs...)
 		// Just for place holding.
-		r.runningTasks[addTable.TableID] = &ScheduleTask{}
+		r.runningTasks.ReplaceOrInsert(addTable.Span, &ScheduleTask{})
 	}
 	for i := range task.RemoveTables {
 		removeTable := task.RemoveTables[i]
-		if r.runningTasks[removeTable.TableID] != nil {
+		if r.runningTasks.Has(removeTable.Span) {
 			// Skip add table if the table is already running a task.
 			continue
 		}
@@ -454,11 +479,11 @@ func (r *Manager) handleBurstBalanceTasks(
 		}
 		sentMsgs = append(sentMsgs, msgs...)
 		// Just for place holding.
-		r.runningTasks[removeTable.TableID] = &ScheduleTask{}
+		r.runningTasks.ReplaceOrInsert(removeTable.Span, &ScheduleTask{})
 	}
 	for i := range task.MoveTables {
 		moveTable := task.MoveTables[i]
-		if r.runningTasks[moveTable.TableID] != nil {
+		if r.runningTasks.Has(moveTable.Span) {
 			// Skip add table if the table is already running a task.
 			continue
 		}
@@ -468,20 +493,20 @@ func (r *Manager) handleBurstBalanceTasks(
 		}
 		sentMsgs = append(sentMsgs, msgs...)
 		// Just for place holding.
-		r.runningTasks[moveTable.TableID] = &ScheduleTask{}
+		r.runningTasks.ReplaceOrInsert(moveTable.Span, &ScheduleTask{})
 	}
 	return sentMsgs, nil
 }
 
 // ReplicationSets return all tracking replication set
 // Caller must not modify the returned map.
-func (r *Manager) ReplicationSets() map[model.TableID]*ReplicationSet {
-	return r.tables
+func (r *Manager) ReplicationSets() *spanz.Map[*ReplicationSet] {
+	return r.spans
 }
 
 // RunningTasks return running tasks.
 // Caller must not modify the returned map.
-func (r *Manager) RunningTasks() map[model.TableID]*ScheduleTask {
+func (r *Manager) RunningTasks() *spanz.Map[*ScheduleTask] {
 	return r.runningTasks
 }
 
@@ -491,10 +516,30 @@ func (r *Manager) AdvanceCheckpoint(
 	currentTime time.Time,
 ) (newCheckpointTs, newResolvedTs model.Ts) {
 	newCheckpointTs, newResolvedTs = math.MaxUint64, math.MaxUint64
-	slowestTableID := int64(0)
+	var slowestSpan tablepb.Span
 	for _, tableID := range currentTables {
-		table, ok := r.tables[tableID]
-		if !ok {
+		tableStart, tableEnd := spanz.TableIDToComparableRange(tableID)
+		// Spans of a table must cover the whole table, otherwise some
+		// changes of the table are not replicated.
+		covered, lastEndKey := true, tableStart.StartKey
+		r.spans.AscendRange(tableStart, tableEnd,
+			func(span tablepb.Span, table *ReplicationSet) bool {
+				if !bytes.Equal(lastEndKey, span.StartKey) {
+					covered = false
+					return false
+				}
+				lastEndKey = span.EndKey
+				// Find the minimum checkpoint ts and resolved ts.
+				if newCheckpointTs > table.Checkpoint.CheckpointTs {
+					newCheckpointTs = table.Checkpoint.CheckpointTs
+					slowestSpan = span
+				}
+				if newResolvedTs > table.Checkpoint.ResolvedTs {
+					newResolvedTs = table.Checkpoint.ResolvedTs
+				}
+				return true
+			})
+		if !covered || !bytes.Equal(lastEndKey, tableEnd.StartKey) {
 			// Can not advance checkpoint there is a table missing.
 			log.Warn("schedulerv3: cannot advance checkpoint since missing table",
 				zap.String("namespace", r.changefeedID.Namespace),
@@ -502,17 +547,9 @@ func (r *Manager) AdvanceCheckpoint(
 				zap.Int64("tableID", tableID))
 			return checkpointCannotProceed, checkpointCannotProceed
 		}
-		// Find the minimum checkpoint ts and resolved ts.
-		if newCheckpointTs > table.Checkpoint.CheckpointTs {
-			newCheckpointTs = table.Checkpoint.CheckpointTs
-			slowestTableID = tableID
-		}
-		if newResolvedTs > table.Checkpoint.ResolvedTs {
-			newResolvedTs = table.Checkpoint.ResolvedTs
-		}
 	}
-	if slowestTableID != 0 {
-		r.slowestTableID = slowestTableID
+	if slowestSpan.TableID != 0 {
+		r.slowestSpan = slowestSpan
 	}
 
 	// If changefeed's checkpoint lag is larger than 30s,
@@ -528,21 +565,55 @@ func (r *Manager) AdvanceCheckpoint(
 	return newCheckpointTs, newResolvedTs
 }
 
+// TableCheckpoints returns the checkpoint ts of the tables whose spans cover
+// the whole table, it's the minimum checkpoint ts of the spans of a table.
+func (r *Manager) TableCheckpoints() map[model.TableID]model.Ts {
+	checkpoints := make(map[model.TableID]model.Ts)
+	r.spans.Ascend(func(span tablepb.Span, _ *ReplicationSet) bool {
+		checkpoints[span.TableID] = math.MaxUint64
+		return true
+	})
+	for tableID := range checkpoints {
+		tableStart, tableEnd := spanz.TableIDToComparableRange(tableID)
+		checkpointTs := uint64(math.MaxUint64)
+		covered, lastEndKey := true, tableStart.StartKey
+		r.spans.AscendRange(tableStart, tableEnd,
+			func(span tablepb.Span, table *ReplicationSet) bool {
+				if !bytes.Equal(lastEndKey, span.StartKey) {
+					covered = false
+					return false
+				}
+				lastEndKey = span.EndKey
+				if checkpointTs > table.Checkpoint.CheckpointTs {
+					checkpointTs = table.Checkpoint.CheckpointTs
+				}
+				return true
+			})
+		if !covered || !bytes.Equal(lastEndKey, tableEnd.StartKey) {
+			delete(checkpoints, tableID)
+			continue
+		}
+		checkpoints[tableID] = checkpointTs
+	}
+	return checkpoints
+}
+
 func (r *Manager) logSlowTableInfo(currentTables []model.TableID, currentTime time.Time) {
 	// find the slow tables
 	for _, tableID := range currentTables {
-		table, ok := r.tables[tableID]
-		if !ok {
-			continue
-		}
-		lag := currentTime.Sub(oracle.GetTimeFromTS(table.Checkpoint.CheckpointTs))
-		if lag < logSlowTablesLagThreshold {
-			continue
-		}
-		heap.Push(&r.slowTableHeap, table)
-		if r.slowTableHeap.Len() > defaultSlowTableHeapSize {
-			heap.Pop(&r.slowTableHeap)
-		}
+		tableStart, tableEnd := spanz.TableIDToComparableRange(tableID)
+		r.spans.AscendRange(tableStart, tableEnd,
+			func(_ tablepb.Span, table *ReplicationSet) bool {
+				lag := currentTime.Sub(oracle.GetTimeFromTS(table.Checkpoint.CheckpointTs))
+				if lag < logSlowTablesLagThreshold {
+					return true
+				}
+				heap.Push(&r.slowTableHeap, table)
+				if r.slowTableHeap.Len() > defaultSlowTableHeapSize {
+					heap.Pop(&r.slowTableHeap)
+				}
+				return true
+			})
 	}
 
 	num := r.slowTableHeap.Len()
@@ -551,7 +622,8 @@ func (r *Manager) logSlowTableInfo(currentTables []model.TableID, currentTime ti
 		log.Info("schedulerv3: slow table",
 			zap.String("namespace", r.changefeedID.Namespace),
 			zap.String("changefeed", r.changefeedID.ID),
-			zap.Int64("tableID", table.TableID),
+			zap.Int64("tableID", table.Span.TableID),
+			zap.Stringer("span", &table.Span),
 			zap.String("tableStatus", table.Stats.String()),
 			zap.Uint64("checkpointTs", table.Checkpoint.CheckpointTs),
 			zap.Uint64("resolvedTs", table.Checkpoint.ResolvedTs),
@@ -564,10 +636,10 @@ func (r *Manager) logSlowTableInfo(currentTables []model.TableID, currentTime ti
 func (r *Manager) CollectMetrics() {
 	cf := r.changefeedID
 	tableGauge.
-		WithLabelValues(cf.Namespace, cf.ID).Set(float64(len(r.tables)))
-	if table, ok := r.tables[r.slowestTableID]; ok {
+		WithLabelValues(cf.Namespace, cf.ID).Set(float64(r.spans.Len()))
+	if table, ok := r.spans.Get(r.slowestSpan); ok {
 		slowestTableIDGauge.
-			WithLabelValues(cf.Namespace, cf.ID).Set(float64(r.slowestTableID))
+			WithLabelValues(cf.Namespace, cf.ID).Set(float64(r.slowestSpan.TableID))
 		slowestTableStateGauge.
 			WithLabelValues(cf.Namespace, cf.ID).Set(float64(table.State))
 		phyCkpTs := oracle.ExtractPhysical(table.Checkpoint.CheckpointTs)
@@ -625,9 +697,9 @@ func (r *Manager) CollectMetrics() {
 	metricAcceptScheduleTask.WithLabelValues("burstBalance").Add(float64(r.acceptBurstBalanceTask))
 	r.acceptBurstBalanceTask = 0
 	runningScheduleTaskGauge.
-		WithLabelValues(cf.Namespace, cf.ID).Set(float64(len(r.runningTasks)))
+		WithLabelValues(cf.Namespace, cf.ID).Set(float64(r.runningTasks.Len()))
 	var stateCounters [6]int
-	for _, table := range r.tables {
+	r.spans.Ascend(func(_ tablepb.Span, table *ReplicationSet) bool {
 		switch table.State {
 		case ReplicationSetStateUnknown:
 			stateCounters[ReplicationSetStateUnknown]++
@@ -642,7 +714,8 @@ func (r *Manager) CollectMetrics() {
 		case ReplicationSetStateRemoving:
 			stateCounters[ReplicationSetStateRemoving]++
 		}
-	}
+		return true
+	})
 	for s, counter := range stateCounters {
 		tableStateGauge.
 			WithLabelValues(cf.Namespace, cf.ID, ReplicationSetState(s).String()).
@@ -667,7 +740,7 @@ func (r *Manager) CleanMetrics() {
 	metricAcceptScheduleTask.DeleteLabelValues("moveTable")
 	metricAcceptScheduleTask.DeleteLabelValues("burstBalance")
 	var stateCounters [6]int
-	for _, table := range r.tables {
+	r.spans.Ascend(func(_ tablepb.Span, table *ReplicationSet) bool {
 		switch table.State {
 		case ReplicationSetStateUnknown:
 			stateCounters[ReplicationSetStateUnknown]++
@@ -682,7 +755,8 @@ func (r *Manager) CleanMetrics() {
 		case ReplicationSetStateRemoving:
 			stateCounters[ReplicationSetStateRemoving]++
 		}
-	}
+		return true
+	})
 	for s := range stateCounters {
 		tableStateGauge.
 			DeleteLabelValues(cf.Namespace, cf.ID, ReplicationSetState(s).String())
@@ -698,10 +772,10 @@ func (r *Manager) CleanMetrics() {
 
 // SetReplicationSetForTests is only used in tests.
 func (r *Manager) SetReplicationSetForTests(rs *ReplicationSet) {
-	r.tables[rs.TableID] = rs
+	r.spans.ReplaceOrInsert(rs.Span, rs)
 }
 
 // GetReplicationSetForTests is only used in tests.
-func (r *Manager) GetReplicationSetForTests() map[model.TableID]*ReplicationSet {
-	return r.tables
+func (r *Manager) GetReplicationSetForTests() *spanz.Map[*ReplicationSet] {
+	return r.spans
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,7 +31,7 @@ func TestReplicationManagerHandleAddTableTask(t *testing.T) {
 	addTableCh := make(chan int, 1)
 	// Absent -> Prepare
 	msgs, err := r.HandleTasks([]*ScheduleTask{{
-		AddTable: &AddTable{TableID: 1, CaptureID: "1", CheckpointTs: 1},
+		AddTable: &AddTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1", CheckpointTs: 1},
 		Accept: func() {
 			addTableCh <- 1
 			close(addTableCh)
@@ -45,6 +46,7 @@ func TestReplicationManagerHandleAddTableTask(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
 					TableID:     1,
+					Span:        spanz.TableIDToComparableSpan(1),
 					IsSecondary: true,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 1,
@@ -54,12 +56,12 @@ func TestReplicationManagerHandleAddTableTask(t *testing.T) {
 			},
 		},
 	}, msgs[0])
-	require.NotNil(t, r.runningTasks[1])
+	require.NotNil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 	require.Equal(t, 1, <-addTableCh)
 
 	// Ignore if add the table again.
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
-		AddTable: &AddTable{TableID: 1, CaptureID: "1"},
+		AddTable: &AddTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1"},
 		Accept:   func() { t.Fatalf("must not accept") },
 	}})
 	require.Nil(t, err)
@@ -74,6 +76,7 @@ func TestReplicationManagerHandleAddTableTask(t *testing.T) {
 				AddTable: &schedulepb.AddTableResponse{
 					Status: &tablepb.TableStatus{
 						TableID: 1,
+						Span:    spanz.TableIDToComparableSpan(1),
 						State:   tablepb.TableStatePrepared,
 					},
 				},
@@ -89,6 +92,7 @@ func TestReplicationManagerHandleAddTableTask(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
 					TableID:     1,
+					Span:        spanz.TableIDToComparableSpan(1),
 					IsSecondary: false,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 1,
@@ -98,9 +102,9 @@ func TestReplicationManagerHandleAddTableTask(t *testing.T) {
 			},
 		},
 	}, msgs[0])
-	require.Equal(t, ReplicationSetStateCommit, r.tables[1].State)
-	require.Equal(t, "1", r.tables[1].Primary)
-	require.False(t, r.tables[1].hasRole(RoleSecondary))
+	require.Equal(t, ReplicationSetStateCommit, r.spans.GetV(spanz.TableIDToComparableSpan(1)).State)
+	require.Equal(t, "1", r.spans.GetV(spanz.TableIDToComparableSpan(1)).Primary)
+	require.False(t, r.spans.GetV(spanz.TableIDToComparableSpan(1)).hasRole(RoleSecondary))
 
 	// Commit -> Replicating through heartbeat response.
 	msgs, err = r.HandleMessage([]*schedulepb.Message{{
@@ -109,21 +113,22 @@ func TestReplicationManagerHandleAddTableTask(t *testing.T) {
 		HeartbeatResponse: &schedulepb.HeartbeatResponse{
 			Tables: []tablepb.TableStatus{{
 				TableID: 1,
+				Span:    spanz.TableIDToComparableSpan(1),
 				State:   tablepb.TableStateReplicating,
 			}},
 		},
 	}})
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Equal(t, ReplicationSetStateReplicating, r.tables[1].State)
-	require.Equal(t, "1", r.tables[1].Primary)
-	require.False(t, r.tables[1].hasRole(RoleSecondary))
+	require.Equal(t, ReplicationSetStateReplicating, r.spans.GetV(spanz.TableIDToComparableSpan(1)).State)
+	require.Equal(t, "1", r.spans.GetV(spanz.TableIDToComparableSpan(1)).Primary)
+	require.False(t, r.spans.GetV(spanz.TableIDToComparableSpan(1)).hasRole(RoleSecondary))
 
 	// Handle task again to clear runningTasks
 	msgs, err = r.HandleTasks(nil)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Nil(t, r.runningTasks[1])
+	require.Nil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 }
 
 func TestReplicationManagerRemoveTable(t *testing.T) {
@@ -134,23 +139,23 @@ func TestReplicationManagerRemoveTable(t *testing.T) {
 
 	// Ignore remove table if there is no such table.
 	msgs, err := r.HandleTasks([]*ScheduleTask{{
-		RemoveTable: &RemoveTable{TableID: 1, CaptureID: "1"},
+		RemoveTable: &RemoveTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1"},
 		Accept:      func() { t.Fatal("must not accept") },
 	}})
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
 
 	// Add the table.
-	tbl, err := NewReplicationSet(1, 0, map[string]*tablepb.TableStatus{
-		"1": {TableID: 1, State: tablepb.TableStateReplicating},
+	tbl, err := NewReplicationSet(spanz.TableIDToComparableSpan(1), 0, map[string]*tablepb.TableStatus{
+		"1": {TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating},
 	}, model.ChangeFeedID{})
 	require.Nil(t, err)
 	require.Equal(t, ReplicationSetStateReplicating, tbl.State)
-	r.tables[1] = tbl
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(1), tbl)
 
 	// Remove the table.
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
-		RemoveTable: &RemoveTable{TableID: 1, CaptureID: "1"},
+		RemoveTable: &RemoveTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1"},
 		Accept: func() {
 			removeTableCh <- 1
 			close(removeTableCh)
@@ -163,16 +168,16 @@ func TestReplicationManagerRemoveTable(t *testing.T) {
 		MsgType: schedulepb.MsgDispatchTableRequest,
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_RemoveTable{
-				RemoveTable: &schedulepb.RemoveTableRequest{TableID: 1},
+				RemoveTable: &schedulepb.RemoveTableRequest{TableID: 1, Span: spanz.TableIDToComparableSpan(1)},
 			},
 		},
 	}, msgs[0])
-	require.NotNil(t, r.runningTasks[1])
+	require.NotNil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 	require.Equal(t, 1, <-removeTableCh)
 
 	// Ignore if remove table again.
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
-		RemoveTable: &RemoveTable{TableID: 1, CaptureID: "1"},
+		RemoveTable: &RemoveTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1"},
 		Accept:      func() { t.Fatalf("must not accept") },
 	}})
 	require.Nil(t, err)
@@ -187,6 +192,7 @@ func TestReplicationManagerRemoveTable(t *testing.T) {
 				RemoveTable: &schedulepb.RemoveTableResponse{
 					Status: &tablepb.TableStatus{
 						TableID: 1,
+						Span:    spanz.TableIDToComparableSpan(1),
 						State:   tablepb.TableStateStopping,
 					},
 				},
@@ -203,19 +209,20 @@ func TestReplicationManagerRemoveTable(t *testing.T) {
 		HeartbeatResponse: &schedulepb.HeartbeatResponse{
 			Tables: []tablepb.TableStatus{{
 				TableID: 1,
+				Span:    spanz.TableIDToComparableSpan(1),
 				State:   tablepb.TableStateStopped,
 			}},
 		},
 	}})
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Nil(t, r.tables[1])
+	require.Nil(t, r.spans.GetV(spanz.TableIDToComparableSpan(1)))
 
 	// Handle task again to clear runningTasks
 	msgs, err = r.HandleTasks(nil)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Nil(t, r.runningTasks[1])
+	require.Nil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 }
 
 func TestReplicationManagerMoveTable(t *testing.T) {
@@ -229,23 +236,23 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 
 	// Ignore move table if it's not exist.
 	msgs, err := r.HandleTasks([]*ScheduleTask{{
-		MoveTable: &MoveTable{TableID: 1, DestCapture: dest},
+		MoveTable: &MoveTable{Span: spanz.TableIDToComparableSpan(1), DestCapture: dest},
 		Accept:    func() { t.Fatal("must not accept") },
 	}})
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
 
 	// Add the table.
-	tbl, err := NewReplicationSet(1, 0, map[string]*tablepb.TableStatus{
-		source: {TableID: 1, State: tablepb.TableStateReplicating},
+	tbl, err := NewReplicationSet(spanz.TableIDToComparableSpan(1), 0, map[string]*tablepb.TableStatus{
+		source: {TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating},
 	}, model.ChangeFeedID{})
 	require.Nil(t, err)
 	require.Equal(t, ReplicationSetStateReplicating, tbl.State)
-	r.tables[1] = tbl
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(1), tbl)
 
 	// Replicating -> Prepare
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
-		MoveTable: &MoveTable{TableID: 1, DestCapture: dest},
+		MoveTable: &MoveTable{Span: spanz.TableIDToComparableSpan(1), DestCapture: dest},
 		Accept: func() {
 			moveTableCh <- 1
 			close(moveTableCh)
@@ -260,17 +267,18 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
 					TableID:     1,
+					Span:        spanz.TableIDToComparableSpan(1),
 					IsSecondary: true,
 				},
 			},
 		},
 	}, msgs[0])
-	require.NotNil(t, r.runningTasks[1])
+	require.NotNil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 	require.Equal(t, 1, <-moveTableCh)
 
 	// Ignore if move table again.
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
-		MoveTable: &MoveTable{TableID: 1, DestCapture: dest},
+		MoveTable: &MoveTable{Span: spanz.TableIDToComparableSpan(1), DestCapture: dest},
 		Accept: func() {
 			moveTableCh <- 1
 			close(moveTableCh)
@@ -288,6 +296,7 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 				AddTable: &schedulepb.AddTableResponse{
 					Status: &tablepb.TableStatus{
 						TableID: 1,
+						Span:    spanz.TableIDToComparableSpan(1),
 						State:   tablepb.TableStatePrepared,
 					},
 				},
@@ -301,7 +310,7 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 		MsgType: schedulepb.MsgDispatchTableRequest,
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_RemoveTable{
-				RemoveTable: &schedulepb.RemoveTableRequest{TableID: 1},
+				RemoveTable: &schedulepb.RemoveTableRequest{TableID: 1, Span: spanz.TableIDToComparableSpan(1)},
 			},
 		},
 	}, msgs[0])
@@ -314,6 +323,7 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 		HeartbeatResponse: &schedulepb.HeartbeatResponse{
 			Tables: []tablepb.TableStatus{{
 				TableID: 1,
+				Span:    spanz.TableIDToComparableSpan(1),
 				State:   tablepb.TableStateStopped,
 			}},
 		},
@@ -327,6 +337,7 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
 					TableID:     1,
+					Span:        spanz.TableIDToComparableSpan(1),
 					IsSecondary: false,
 				},
 			},
@@ -342,6 +353,7 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 				AddTable: &schedulepb.AddTableResponse{
 					Status: &tablepb.TableStatus{
 						TableID: 1,
+						Span:    spanz.TableIDToComparableSpan(1),
 						State:   tablepb.TableStateReplicating,
 					},
 				},
@@ -350,14 +362,14 @@ func TestReplicationManagerMoveTable(t *testing.T) {
 	}})
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Equal(t, ReplicationSetStateReplicating, r.tables[1].State)
-	require.Equal(t, dest, r.tables[1].Primary)
+	require.Equal(t, ReplicationSetStateReplicating, r.spans.GetV(spanz.TableIDToComparableSpan(1)).State)
+	require.Equal(t, dest, r.spans.GetV(spanz.TableIDToComparableSpan(1)).Primary)
 
 	// Handle task again to clear runningTasks
 	msgs, err = r.HandleTasks(nil)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Nil(t, r.runningTasks[1])
+	require.Nil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 }
 
 func TestReplicationManagerBurstBalance(t *testing.T) {
@@ -368,15 +380,15 @@ func TestReplicationManagerBurstBalance(t *testing.T) {
 
 	// Burst balance is not limited by maxTaskConcurrency.
 	msgs, err := r.HandleTasks([]*ScheduleTask{{
-		AddTable: &AddTable{TableID: 1, CaptureID: "0", CheckpointTs: 1},
+		AddTable: &AddTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "0", CheckpointTs: 1},
 	}, {
 		BurstBalance: &BurstBalance{
 			AddTables: []AddTable{{
-				TableID: 1, CaptureID: "1", CheckpointTs: 1,
+				Span: spanz.TableIDToComparableSpan(1), CaptureID: "1", CheckpointTs: 1,
 			}, {
-				TableID: 2, CaptureID: "2", CheckpointTs: 1,
+				Span: spanz.TableIDToComparableSpan(2), CaptureID: "2", CheckpointTs: 1,
 			}, {
-				TableID: 3, CaptureID: "3", CheckpointTs: 1,
+				Span: spanz.TableIDToComparableSpan(3), CaptureID: "3", CheckpointTs: 1,
 			}},
 		},
 		Accept: func() {
@@ -396,6 +408,7 @@ func TestReplicationManagerBurstBalance(t *testing.T) {
 				Request: &schedulepb.DispatchTableRequest_AddTable{
 					AddTable: &schedulepb.AddTableRequest{
 						TableID:     tableID,
+						Span:        spanz.TableIDToComparableSpan(tableID),
 						IsSecondary: true,
 						Checkpoint: tablepb.Checkpoint{
 							CheckpointTs: 1,
@@ -405,28 +418,29 @@ func TestReplicationManagerBurstBalance(t *testing.T) {
 				},
 			},
 		}, msgs)
-		require.Contains(t, r.tables, tableID)
-		require.Contains(t, r.runningTasks, tableID)
+		require.True(t, r.spans.Has(spanz.TableIDToComparableSpan(tableID)))
+		require.True(t, r.runningTasks.Has(spanz.TableIDToComparableSpan(tableID)))
 	}
 
 	// Add a new table.
-	r.tables[5], err = NewReplicationSet(5, 0, map[string]*tablepb.TableStatus{
-		"5": {TableID: 5, State: tablepb.TableStateReplicating},
+	rs, err := NewReplicationSet(spanz.TableIDToComparableSpan(5), 0, map[string]*tablepb.TableStatus{
+		"5": {TableID: 5, Span: spanz.TableIDToComparableSpan(5), State: tablepb.TableStateReplicating},
 	}, model.ChangeFeedID{})
 	require.Nil(t, err)
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(5), rs)
 
 	// More burst balance is still allowed.
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
 		BurstBalance: &BurstBalance{
 			AddTables: []AddTable{{
-				TableID: 4, CaptureID: "4", CheckpointTs: 2,
+				Span: spanz.TableIDToComparableSpan(4), CaptureID: "4", CheckpointTs: 2,
 			}, {
-				TableID: 1, CaptureID: "0", CheckpointTs: 2,
+				Span: spanz.TableIDToComparableSpan(1), CaptureID: "0", CheckpointTs: 2,
 			}},
 			RemoveTables: []RemoveTable{{
-				TableID: 5, CaptureID: "5",
+				Span: spanz.TableIDToComparableSpan(5), CaptureID: "5",
 			}, {
-				TableID: 1, CaptureID: "0",
+				Span: spanz.TableIDToComparableSpan(1), CaptureID: "0",
 			}},
 		},
 		Accept: func() {
@@ -443,6 +457,7 @@ func TestReplicationManagerBurstBalance(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
 					TableID:     4,
+					Span:        spanz.TableIDToComparableSpan(4),
 					IsSecondary: true,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 2,
@@ -459,6 +474,7 @@ func TestReplicationManagerBurstBalance(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_RemoveTable{
 				RemoveTable: &schedulepb.RemoveTableRequest{
 					TableID: 5,
+					Span:    spanz.TableIDToComparableSpan(5),
 				},
 			},
 		},
@@ -471,24 +487,26 @@ func TestReplicationManagerBurstBalanceMoveTables(t *testing.T) {
 	r := NewReplicationManager(1, model.ChangeFeedID{})
 	balanceTableCh := make(chan int, 1)
 
-	var err error
 	// Two tables in "1".
-	r.tables[1], err = NewReplicationSet(1, 0, map[string]*tablepb.TableStatus{
-		"1": {TableID: 1, State: tablepb.TableStateReplicating},
+	rs, err := NewReplicationSet(spanz.TableIDToComparableSpan(1), 0, map[string]*tablepb.TableStatus{
+		"1": {TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating},
 	}, model.ChangeFeedID{})
 	require.Nil(t, err)
-	r.tables[2], err = NewReplicationSet(2, 0, map[string]*tablepb.TableStatus{
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(1), rs)
+	rs, err = NewReplicationSet(spanz.TableIDToComparableSpan(2), 0, map[string]*tablepb.TableStatus{
 		"1": {
-			TableID: 2, State: tablepb.TableStateReplicating,
+			TableID: 2, Span: spanz.TableIDToComparableSpan(2),
+			State:      tablepb.TableStateReplicating,
 			Checkpoint: tablepb.Checkpoint{CheckpointTs: 1},
 		},
 	}, model.ChangeFeedID{})
 	require.Nil(t, err)
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(2), rs)
 
 	msgs, err := r.HandleTasks([]*ScheduleTask{{
 		BurstBalance: &BurstBalance{
 			MoveTables: []MoveTable{{
-				TableID: 2, DestCapture: "2",
+				Span: spanz.TableIDToComparableSpan(2), DestCapture: "2",
 			}},
 		},
 		Accept: func() {
@@ -505,14 +523,15 @@ func TestReplicationManagerBurstBalanceMoveTables(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
 					TableID:     2,
+					Span:        spanz.TableIDToComparableSpan(2),
 					IsSecondary: true,
 					Checkpoint:  tablepb.Checkpoint{CheckpointTs: 1},
 				},
 			},
 		},
 	}, msgs)
-	require.Contains(t, r.tables, model.TableID(2))
-	require.Contains(t, r.runningTasks, model.TableID(2))
+	require.True(t, r.spans.Has(spanz.TableIDToComparableSpan(model.TableID(2))))
+	require.True(t, r.runningTasks.Has(spanz.TableIDToComparableSpan(model.TableID(2))))
 }
 
 func TestReplicationManagerMaxTaskConcurrency(t *testing.T) {
@@ -522,7 +541,7 @@ func TestReplicationManagerMaxTaskConcurrency(t *testing.T) {
 	addTableCh := make(chan int, 1)
 
 	msgs, err := r.HandleTasks([]*ScheduleTask{{
-		AddTable: &AddTable{TableID: 1, CaptureID: "1"},
+		AddTable: &AddTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1"},
 		Accept: func() {
 			addTableCh <- 1
 			close(addTableCh)
@@ -537,17 +556,18 @@ func TestReplicationManagerMaxTaskConcurrency(t *testing.T) {
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
 					TableID:     1,
+					Span:        spanz.TableIDToComparableSpan(1),
 					IsSecondary: true,
 				},
 			},
 		},
 	}, msgs[0])
-	require.NotNil(t, r.runningTasks[1])
+	require.NotNil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 	require.Equal(t, 1, <-addTableCh)
 
 	// No more tasks allowed.
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
-		AddTable: &AddTable{TableID: 2, CaptureID: "1"},
+		AddTable: &AddTable{Span: spanz.TableIDToComparableSpan(2), CaptureID: "1"},
 		Accept: func() {
 			t.Fatal("must not accept")
 		},
@@ -560,10 +580,11 @@ func TestReplicationManagerAdvanceCheckpoint(t *testing.T) {
 	t.Parallel()
 
 	r := NewReplicationManager(1, model.ChangeFeedID{})
-	rs, err := NewReplicationSet(model.TableID(1), model.Ts(10),
+	rs, err := NewReplicationSet(spanz.TableIDToComparableSpan(model.TableID(1)), model.Ts(10),
 		map[model.CaptureID]*tablepb.TableStatus{
 			"1": {
 				TableID: model.TableID(1),
+				Span:    spanz.TableIDToComparableSpan(model.TableID(1)),
 				State:   tablepb.TableStateReplicating,
 				Checkpoint: tablepb.Checkpoint{
 					CheckpointTs: model.Ts(10),
@@ -572,12 +593,13 @@ func TestReplicationManagerAdvanceCheckpoint(t *testing.T) {
 			},
 		}, model.ChangeFeedID{})
 	require.NoError(t, err)
-	r.tables[model.TableID(1)] = rs
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(model.TableID(1)), rs)
 
-	rs, err = NewReplicationSet(model.TableID(2), model.Ts(15),
+	rs, err = NewReplicationSet(spanz.TableIDToComparableSpan(model.TableID(2)), model.Ts(15),
 		map[model.CaptureID]*tablepb.TableStatus{
 			"2": {
 				TableID: model.TableID(2),
+				Span:    spanz.TableIDToComparableSpan(model.TableID(2)),
 				State:   tablepb.TableStateReplicating,
 				Checkpoint: tablepb.Checkpoint{
 					CheckpointTs: model.Ts(15),
@@ -586,7 +608,7 @@ func TestReplicationManagerAdvanceCheckpoint(t *testing.T) {
 			},
 		}, model.ChangeFeedID{})
 	require.NoError(t, err)
-	r.tables[model.TableID(2)] = rs
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(model.TableID(2)), rs)
 
 	// all table is replicating
 	currentTables := []model.TableID{1, 2}
@@ -600,10 +622,11 @@ func TestReplicationManagerAdvanceCheckpoint(t *testing.T) {
 	require.Equal(t, checkpointCannotProceed, checkpoint)
 	require.Equal(t, checkpointCannotProceed, resolved)
 
-	rs, err = NewReplicationSet(model.TableID(3), model.Ts(5),
+	rs, err = NewReplicationSet(spanz.TableIDToComparableSpan(model.TableID(3)), model.Ts(5),
 		map[model.CaptureID]*tablepb.TableStatus{
 			"1": {
 				TableID: model.TableID(3),
+				Span:    spanz.TableIDToComparableSpan(model.TableID(3)),
 				State:   tablepb.TableStateReplicating,
 				Checkpoint: tablepb.Checkpoint{
 					CheckpointTs: model.Ts(5),
@@ -612,6 +635,7 @@ func TestReplicationManagerAdvanceCheckpoint(t *testing.T) {
 			},
 			"2": {
 				TableID: model.TableID(3),
+				Span:    spanz.TableIDToComparableSpan(model.TableID(3)),
 				State:   tablepb.TableStatePreparing,
 				Checkpoint: tablepb.Checkpoint{
 					CheckpointTs: model.Ts(5),
@@ -620,16 +644,17 @@ func TestReplicationManagerAdvanceCheckpoint(t *testing.T) {
 			},
 		}, model.ChangeFeedID{})
 	require.NoError(t, err)
-	r.tables[model.TableID(3)] = rs
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(model.TableID(3)), rs)
 	checkpoint, resolved = r.AdvanceCheckpoint(currentTables, time.Now())
 	require.Equal(t, model.Ts(5), checkpoint)
 	require.Equal(t, model.Ts(20), resolved)
 
 	currentTables = append(currentTables, 4)
-	rs, err = NewReplicationSet(model.TableID(4), model.Ts(3),
+	rs, err = NewReplicationSet(spanz.TableIDToComparableSpan(model.TableID(4)), model.Ts(3),
 		map[model.CaptureID]*tablepb.TableStatus{
 			"1": {
 				TableID: model.TableID(4),
+				Span:    spanz.TableIDToComparableSpan(model.TableID(4)),
 				State:   tablepb.TableStatePrepared,
 				Checkpoint: tablepb.Checkpoint{
 					CheckpointTs: model.Ts(3),
@@ -638,48 +663,85 @@ func TestReplicationManagerAdvanceCheckpoint(t *testing.T) {
 			},
 		}, model.ChangeFeedID{})
 	require.NoError(t, err)
-	r.tables[model.TableID(4)] = rs
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(model.TableID(4)), rs)
 	checkpoint, resolved = r.AdvanceCheckpoint(currentTables, time.Now())
 	require.Equal(t, model.Ts(3), checkpoint)
 	require.Equal(t, model.Ts(10), resolved)
 }
 
+func TestReplicationManagerTableCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	r := NewReplicationManager(1, model.ChangeFeedID{})
+	addSpan := func(span tablepb.Span, captureID model.CaptureID, checkpointTs model.Ts) {
+		rs, err := NewReplicationSet(span, checkpointTs,
+			map[model.CaptureID]*tablepb.TableStatus{
+				captureID: {
+					TableID:    span.TableID,
+					Span:       span,
+					State:      tablepb.TableStateReplicating,
+					Checkpoint: tablepb.Checkpoint{CheckpointTs: checkpointTs},
+				},
+			}, model.ChangeFeedID{})
+		require.NoError(t, err)
+		r.spans.ReplaceOrInsert(span, rs)
+	}
+	split := func(tableID model.TableID) (tablepb.Span, tablepb.Span) {
+		left := spanz.TableIDToComparableSpan(tableID)
+		right := left
+		left.EndKey = append(append([]byte{}, left.StartKey...), 1)
+		right.StartKey = left.EndKey
+		return left, right
+	}
+
+	// The spans of table 1 are replicated by different captures.
+	left, right := split(1)
+	addSpan(left, "1", 20)
+	addSpan(right, "2", 10)
+	// Table 2 is not covered.
+	left, _ = split(2)
+	addSpan(left, "1", 20)
+	addSpan(spanz.TableIDToComparableSpan(3), "2", 30)
+
+	require.Equal(t, map[model.TableID]model.Ts{1: 10, 3: 30}, r.TableCheckpoints())
+}
+
 func TestReplicationManagerHandleCaptureChanges(t *testing.T) {
 	t.Parallel()
 
 	r := NewReplicationManager(1, model.ChangeFeedID{})
 	init := map[model.CaptureID][]tablepb.TableStatus{
-		"1": {{TableID: 1, State: tablepb.TableStateReplicating}},
-		"2": {{TableID: 2, State: tablepb.TableStateReplicating}},
+		"1": {{TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating}},
+		"2": {{TableID: 2, Span: spanz.TableIDToComparableSpan(2), State: tablepb.TableStateReplicating}},
 		"3": {
-			{TableID: 3, State: tablepb.TableStateReplicating},
-			{TableID: 2, State: tablepb.TableStatePreparing},
+			{TableID: 3, Span: spanz.TableIDToComparableSpan(3), State: tablepb.TableStateReplicating},
+			{TableID: 2, Span: spanz.TableIDToComparableSpan(2), State: tablepb.TableStatePreparing},
 		},
-		"4": {{TableID: 4, State: tablepb.TableStateStopping}},
-		"5": {{TableID: 5, State: tablepb.TableStateStopped}},
+		"4": {{TableID: 4, Span: spanz.TableIDToComparableSpan(4), State: tablepb.TableStateStopping}},
+		"5": {{TableID: 5, Span: spanz.TableIDToComparableSpan(5), State: tablepb.TableStateStopped}},
 	}
 	msgs, err := r.HandleCaptureChanges(init, nil, 0)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Len(t, r.tables, 5)
-	require.Equal(t, ReplicationSetStateReplicating, r.tables[1].State)
-	require.Equal(t, ReplicationSetStatePrepare, r.tables[2].State)
-	require.Equal(t, ReplicationSetStateReplicating, r.tables[3].State)
-	require.Equal(t, ReplicationSetStateRemoving, r.tables[4].State)
-	require.Equal(t, ReplicationSetStateAbsent, r.tables[5].State)
+	require.Equal(t, 5, r.spans.Len())
+	require.Equal(t, ReplicationSetStateReplicating, r.spans.GetV(spanz.TableIDToComparableSpan(1)).State)
+	require.Equal(t, ReplicationSetStatePrepare, r.spans.GetV(spanz.TableIDToComparableSpan(2)).State)
+	require.Equal(t, ReplicationSetStateReplicating, r.spans.GetV(spanz.TableIDToComparableSpan(3)).State)
+	require.Equal(t, ReplicationSetStateRemoving, r.spans.GetV(spanz.TableIDToComparableSpan(4)).State)
+	require.Equal(t, ReplicationSetStateAbsent, r.spans.GetV(spanz.TableIDToComparableSpan(5)).State)
 
 	removed := map[string][]tablepb.TableStatus{
-		"1": {{TableID: 1, State: tablepb.TableStateReplicating}},
+		"1": {{TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStateReplicating}},
 	}
 	msgs, err = r.HandleCaptureChanges(nil, removed, 0)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Len(t, r.tables, 5)
-	require.Equal(t, ReplicationSetStateAbsent, r.tables[1].State)
-	require.Equal(t, ReplicationSetStatePrepare, r.tables[2].State)
-	require.Equal(t, ReplicationSetStateReplicating, r.tables[3].State)
-	require.Equal(t, ReplicationSetStateRemoving, r.tables[4].State)
-	require.Equal(t, ReplicationSetStateAbsent, r.tables[5].State)
+	require.Equal(t, 5, r.spans.Len())
+	require.Equal(t, ReplicationSetStateAbsent, r.spans.GetV(spanz.TableIDToComparableSpan(1)).State)
+	require.Equal(t, ReplicationSetStatePrepare, r.spans.GetV(spanz.TableIDToComparableSpan(2)).State)
+	require.Equal(t, ReplicationSetStateReplicating, r.spans.GetV(spanz.TableIDToComparableSpan(3)).State)
+	require.Equal(t, ReplicationSetStateRemoving, r.spans.GetV(spanz.TableIDToComparableSpan(4)).State)
+	require.Equal(t, ReplicationSetStateAbsent, r.spans.GetV(spanz.TableIDToComparableSpan(5)).State)
 }
 
 func TestReplicationManagerHandleCaptureChangesDuringAddTable(t *testing.T) {
@@ -689,96 +751,96 @@ func TestReplicationManagerHandleCaptureChangesDuringAddTable(t *testing.T) {
 	addTableCh := make(chan int, 1)
 
 	msgs, err := r.HandleTasks([]*ScheduleTask{{
-		AddTable: &AddTable{TableID: 1, CaptureID: "1"},
+		AddTable: &AddTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1"},
 		Accept: func() {
 			addTableCh <- 1
 		},
 	}})
 	require.Nil(t, err)
 	require.Len(t, msgs, 1)
-	require.NotNil(t, r.runningTasks[1])
+	require.NotNil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 	require.Equal(t, 1, <-addTableCh)
 
 	removed := map[string][]tablepb.TableStatus{
-		"1": {{TableID: 1, State: tablepb.TableStatePreparing}},
+		"1": {{TableID: 1, Span: spanz.TableIDToComparableSpan(1), State: tablepb.TableStatePreparing}},
 	}
 	msgs, err = r.HandleCaptureChanges(nil, removed, 0)
 	require.Nil(t, err)
 	require.Len(t, msgs, 0)
-	require.Len(t, r.tables, 1)
-	require.Equal(t, ReplicationSetStateAbsent, r.tables[1].State)
-	require.Nil(t, r.runningTasks[1])
+	require.Equal(t, 1, r.spans.Len())
+	require.Equal(t, ReplicationSetStateAbsent, r.spans.GetV(spanz.TableIDToComparableSpan(1)).State)
+	require.Nil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 
 	// New task must be accepted.
 	msgs, err = r.HandleTasks([]*ScheduleTask{{
-		AddTable: &AddTable{TableID: 1, CaptureID: "1"},
+		AddTable: &AddTable{Span: spanz.TableIDToComparableSpan(1), CaptureID: "1"},
 		Accept: func() {
 			addTableCh <- 1
 		},
 	}})
 	require.Nil(t, err)
 	require.Len(t, msgs, 1)
-	require.NotNil(t, r.runningTasks[1])
+	require.NotNil(t, r.runningTasks.GetV(spanz.TableIDToComparableSpan(1)))
 	require.Equal(t, 1, <-addTableCh)
 }
 
 func TestLogSlowTableInfo(t *testing.T) {
 	t.Parallel()
 	r := NewReplicationManager(1, model.ChangeFeedID{})
-	r.tables[1] = &ReplicationSet{
-		TableID:    1,
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(1), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(1),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 1},
 		State:      ReplicationSetStateReplicating,
-	}
-	r.tables[2] = &ReplicationSet{
-		TableID:    2,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(2), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(2),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 2},
 		State:      ReplicationSetStatePrepare,
-	}
-	r.tables[3] = &ReplicationSet{
-		TableID:    3,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(3), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(3),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 3},
 		State:      ReplicationSetStatePrepare,
-	}
+	})
 	currentTables := []model.TableID{1, 2, 3}
 	r.logSlowTableInfo(currentTables, time.Now())
 	// make sure all tables are will be pop out from heal after logged
 	require.Equal(t, r.slowTableHeap.Len(), 0)
-	r.tables[4] = &ReplicationSet{
-		TableID:    4,
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(4), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(4),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 4},
 		State:      ReplicationSetStatePrepare,
-	}
-	r.tables[5] = &ReplicationSet{
-		TableID:    5,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(5), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(5),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 5},
 		State:      ReplicationSetStatePrepare,
-	}
-	r.tables[6] = &ReplicationSet{
-		TableID:    6,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(6), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(6),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 6},
 		State:      ReplicationSetStatePrepare,
-	}
-	r.tables[7] = &ReplicationSet{
-		TableID:    7,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(7), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(7),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 7},
 		State:      ReplicationSetStatePrepare,
-	}
-	r.tables[8] = &ReplicationSet{
-		TableID:    8,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(8), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(8),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 8},
 		State:      ReplicationSetStatePrepare,
-	}
-	r.tables[9] = &ReplicationSet{
-		TableID:    9,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(9), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(9),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 9},
 		State:      ReplicationSetStatePrepare,
-	}
-	r.tables[10] = &ReplicationSet{
-		TableID:    10,
+	})
+	r.spans.ReplaceOrInsert(spanz.TableIDToComparableSpan(10), &ReplicationSet{
+		Span:       spanz.TableIDToComparableSpan(10),
 		Checkpoint: tablepb.Checkpoint{CheckpointTs: 10},
 		State:      ReplicationSetStatePrepare,
-	}
+	})
 	currentTables = []model.TableID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 	r.logSlowTableInfo(currentTables, time.Now())
 	// make sure the slowTableHeap's capacity will not extend
@@ -127,7 +127,7 @@ func (r Role) MarshalJSON() ([]byte, error) {
 // ReplicationSet is a state machine that manages replication states.
 type ReplicationSet struct { //nolint:revive
 	Changefeed model.ChangeFeedID
-	TableID    model.TableID
+	Span       tablepb.Span
 	State      ReplicationSetState
 	// Primary is the capture ID that is currently replicating the table.
 	Primary model.CaptureID
@@ -141,14 +141,14 @@ type ReplicationSet struct { //nolint:revive
 
 // NewReplicationSet returns a new replication set.
 func NewReplicationSet(
-	tableID model.TableID,
+	span tablepb.Span,
 	checkpoint model.Ts,
 	tableStatus map[model.CaptureID]*tablepb.TableStatus,
 	changefeed model.ChangeFeedID,
 ) (*ReplicationSet, error) {
 	r := &ReplicationSet{
 		Changefeed: changefeed,
-		TableID:    tableID,
+		Span:       span,
 		Captures:   make(map[string]Role),
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: checkpoint,
@@ -159,9 +159,9 @@ func NewReplicationSet(
 	stoppingCount := 0
 	committed := false
 	for captureID, table := range tableStatus {
-		if r.TableID != table.TableID {
+		if !r.Span.Eq(&table.Span) {
 			return nil, r.inconsistentError(table, captureID,
-				"schedulerv3: table id inconsistent")
+				"schedulerv3: span inconsistent")
 		}
 		r.updateCheckpointAndStats(table.Checkpoint, table.Stats)
 
@@ -202,7 +202,7 @@ func NewReplicationSet(
 			// proceeding further scheduling.
 			log.Warn("schedulerv3: found a stopping capture during initializing",
 				zap.Any("replicationSet", r),
-				zap.Int64("tableID", table.TableID),
+				zap.Stringer("span", &table.Span),
 				zap.Any("status", tableStatus))
 			err := r.setCapture(captureID, RoleUndetermined)
 			if err != nil {
@@ -215,7 +215,7 @@ func NewReplicationSet(
 		default:
 			log.Warn("schedulerv3: unknown table state",
 				zap.Any("replicationSet", r),
-				zap.Int64("tableID", table.TableID),
+				zap.Stringer("span", &table.Span),
 				zap.Any("status", tableStatus))
 		}
 	}
@@ -324,7 +324,7 @@ func (r *ReplicationSet) inconsistentError(
 	}...)
 	log.L().WithOptions(zap.AddCallerSkip(1)).Error(msg, fields...)
 	return cerror.ErrReplicationSetInconsistent.GenWithStackByArgs(
-		fmt.Sprintf("tableID %d, %s", r.TableID, msg))
+		fmt.Sprintf("span %s, %s", r.Span.String(), msg))
 }
 
 func (r *ReplicationSet) multiplePrimaryError(
@@ -337,16 +337,16 @@ func (r *ReplicationSet) multiplePrimaryError(
 	}...)
 	log.L().WithOptions(zap.AddCallerSkip(1)).Error(msg, fields...)
 	return cerror.ErrReplicationSetMultiplePrimaryError.GenWithStackByArgs(
-		fmt.Sprintf("tableID %d, %s", r.TableID, msg))
+		fmt.Sprintf("span %s, %s", r.Span.String(), msg))
 }
 
 // checkInvariant ensures ReplicationSet invariant is hold.
 func (r *ReplicationSet) checkInvariant(
 	input *tablepb.TableStatus, captureID model.CaptureID,
 ) error {
-	if r.TableID != input.TableID {
+	if !r.Span.Eq(&input.Span) {
 		return r.inconsistentError(input, captureID,
-			"schedulerv3: tableID must be the same")
+			"schedulerv3: span must be the same")
 	}
 	if len(r.Captures) == 0 {
 		if r.State == ReplicationSetStatePrepare ||
@@ -463,7 +463,8 @@ func (r *ReplicationSet) pollOnPrepare(
 				DispatchTableRequest: &schedulepb.DispatchTableRequest{
 					Request: &schedulepb.DispatchTableRequest_AddTable{
 						AddTable: &schedulepb.AddTableRequest{
-							TableID:     r.TableID,
+							TableID:     r.Span.TableID,
+							Span:        r.Span,
 							IsSecondary: true,
 							Checkpoint:  r.Checkpoint,
 						},
@@ -540,7 +541,10 @@ func (r *ReplicationSet) pollOnCommit(
 					MsgType: schedulepb.MsgDispatchTableRequest,
 					DispatchTableRequest: &schedulepb.DispatchTableRequest{
 						Request: &schedulepb.DispatchTableRequest_RemoveTable{
-							RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.TableID},
+							RemoveTable: &schedulepb.RemoveTableRequest{
+								TableID: r.Span.TableID,
+								Span:    r.Span,
+							},
 						},
 					},
 				}, false, nil
@@ -575,7 +579,8 @@ func (r *ReplicationSet) pollOnCommit(
 				DispatchTableRequest: &schedulepb.DispatchTableRequest{
 					Request: &schedulepb.DispatchTableRequest_AddTable{
 						AddTable: &schedulepb.AddTableRequest{
-							TableID:     r.TableID,
+							TableID:     r.Span.TableID,
+							Span:        r.Span,
 							IsSecondary: false,
 							Checkpoint:  r.Checkpoint,
 						},
@@ -615,7 +620,8 @@ func (r *ReplicationSet) pollOnCommit(
 				DispatchTableRequest: &schedulepb.DispatchTableRequest{
 					Request: &schedulepb.DispatchTableRequest_AddTable{
 						AddTable: &schedulepb.AddTableRequest{
-							TableID:     r.TableID,
+							TableID:     r.Span.TableID,
+							Span:        r.Span,
 							IsSecondary: false,
 							Checkpoint:  r.Checkpoint,
 						},
@@ -658,7 +664,10 @@ func (r *ReplicationSet) pollOnCommit(
 					MsgType: schedulepb.MsgDispatchTableRequest,
 					DispatchTableRequest: &schedulepb.DispatchTableRequest{
 						Request: &schedulepb.DispatchTableRequest_RemoveTable{
-							RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.TableID},
+							RemoveTable: &schedulepb.RemoveTableRequest{
+								TableID: r.Span.TableID,
+								Span:    r.Span,
+							},
 						},
 					},
 				}, false, nil
@@ -754,7 +763,10 @@ func (r *ReplicationSet) pollOnRemoving(
 			MsgType: schedulepb.MsgDispatchTableRequest,
 			DispatchTableRequest: &schedulepb.DispatchTableRequest{
 				Request: &schedulepb.DispatchTableRequest_RemoveTable{
-					RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.TableID},
+					RemoveTable: &schedulepb.RemoveTableRequest{
+						TableID: r.Span.TableID,
+						Span:    r.Span,
+					},
 				},
 			},
 		}, false, nil
@@ -797,7 +809,7 @@ func (r *ReplicationSet) handleAddTable(
 	// Ignore add table if it's not in Absent state.
 	if r.State != ReplicationSetStateAbsent {
 		log.Warn("schedulerv3: add table is ignored",
-			zap.Any("replicationSet", r), zap.Int64("tableID", r.TableID))
+			zap.Any("replicationSet", r), zap.Stringer("span", &r.Span))
 		return nil, nil
 	}
 	oldState := r.State
@@ -810,7 +822,8 @@ func (r *ReplicationSet) handleAddTable(
 		zap.Any("replicationSet", r),
 		zap.Stringer("old", oldState), zap.Stringer("new", r.State))
 	status := tablepb.TableStatus{
-		TableID:    r.TableID,
+		TableID:    r.Span.TableID,
+		Span:       r.Span,
 		State:      tablepb.TableStateAbsent,
 		Checkpoint: tablepb.Checkpoint{},
 	}
@@ -823,7 +836,7 @@ func (r *ReplicationSet) handleMoveTable(
 	// Ignore move table if it has been removed already.
 	if r.hasRemoved() {
 		log.Warn("schedulerv3: move table is ignored",
-			zap.Any("replicationSet", r), zap.Int64("tableID", r.TableID))
+			zap.Any("replicationSet", r), zap.Stringer("span", &r.Span))
 		return nil, nil
 	}
 	// Ignore move table if
@@ -831,7 +844,7 @@ func (r *ReplicationSet) handleMoveTable(
 	// 2) the dest capture is the primary.
 	if r.State != ReplicationSetStateReplicating || r.Primary == dest {
 		log.Warn("schedulerv3: move table is ignored",
-			zap.Any("replicationSet", r), zap.Int64("tableID", r.TableID))
+			zap.Any("replicationSet", r), zap.Stringer("span", &r.Span))
 		return nil, nil
 	}
 	oldState := r.State
@@ -844,7 +857,8 @@ func (r *ReplicationSet) handleMoveTable(
 		zap.Any("replicationSet", r),
 		zap.Stringer("old", oldState), zap.Stringer("new", r.State))
 	status := tablepb.TableStatus{
-		TableID:    r.TableID,
+		TableID:    r.Span.TableID,
+		Span:       r.Span,
 		State:      tablepb.TableStateAbsent,
 		Checkpoint: tablepb.Checkpoint{},
 	}
@@ -855,13 +869,13 @@ func (r *ReplicationSet) handleRemoveTable() ([]*schedulepb.Message, error) {
 	// Ignore remove table if it has been removed already.
 	if r.hasRemoved() {
 		log.Warn("schedulerv3: remove table is ignored",
-			zap.Any("replicationSet", r), zap.Int64("tableID", r.TableID))
+			zap.Any("replicationSet", r), zap.Stringer("span", &r.Span))
 		return nil, nil
 	}
 	// Ignore remove table if it's not in Replicating state.
 	if r.State != ReplicationSetStateReplicating {
 		log.Warn("schedulerv3: remove table is ignored",
-			zap.Any("replicationSet", r), zap.Int64("tableID", r.TableID))
+			zap.Any("replicationSet", r), zap.Stringer("span", &r.Span))
 		return nil, nil
 	}
 	oldState := r.State
@@ -870,7 +884,8 @@ func (r *ReplicationSet) handleRemoveTable() ([]*schedulepb.Message, error) {
 		zap.Any("replicationSet", r),
 		zap.Stringer("old", oldState), zap.Stringer("new", r.State))
 	status := tablepb.TableStatus{
-		TableID: r.TableID,
+		TableID: r.Span.TableID,
+		Span:    r.Span,
 		State:   tablepb.TableStateReplicating,
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: r.Checkpoint.CheckpointTs,
@@ -899,7 +914,8 @@ func (r *ReplicationSet) handleCaptureShutdown(
 	}
 	// The capture has shutdown, the table has stopped.
 	status := tablepb.TableStatus{
-		TableID: r.TableID,
+		TableID: r.Span.TableID,
+		Span:    r.Span,
 		State:   tablepb.TableStateStopped,
 	}
 	msgs, err := r.poll(&status, captureID)
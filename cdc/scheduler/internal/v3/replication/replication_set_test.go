@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/schedulepb"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -213,7 +214,7 @@ func TestNewReplicationSet(t *testing.T) {
 		status := tc.tableStatus
 		checkpoint := tc.checkpoint
 
-		output, err := NewReplicationSet(0, checkpoint, status, model.ChangeFeedID{})
+		output, err := NewReplicationSet(tablepb.Span{}, checkpoint, status, model.ChangeFeedID{})
 		if set == nil {
 			require.Errorf(t, err, "%d", id)
 		} else {
@@ -253,16 +254,17 @@ func TestReplicationSetPoll(t *testing.T) {
 		for id, state := range states {
 			status[id] = &tablepb.TableStatus{
 				TableID:    1,
+				Span:       spanz.TableIDToComparableSpan(1),
 				State:      state,
 				Checkpoint: tablepb.Checkpoint{},
 			}
 		}
-		r, _ := NewReplicationSet(1, 0, status, model.ChangeFeedID{})
+		r, _ := NewReplicationSet(spanz.TableIDToComparableSpan(1), 0, status, model.ChangeFeedID{})
 		var tableStates []int
 		for state := range tablepb.TableState_name {
 			tableStates = append(tableStates, int(state))
 		}
-		input := &tablepb.TableStatus{TableID: model.TableID(1)}
+		input := &tablepb.TableStatus{TableID: model.TableID(1), Span: spanz.TableIDToComparableSpan(model.TableID(1))}
 		iterPermutation(tableStates, func(tableStateSequence []int) {
 			t.Logf("test %d, %v, %v", seed, status, tableStateSequence)
 			for _, state := range tableStateSequence {
@@ -288,9 +290,10 @@ func TestReplicationSetPollUnknownCapture(t *testing.T) {
 	t.Parallel()
 
 	tableID := model.TableID(1)
-	r, err := NewReplicationSet(tableID, 0, map[model.CaptureID]*tablepb.TableStatus{
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, map[model.CaptureID]*tablepb.TableStatus{
 		"1": {
 			TableID:    tableID,
+			Span:       spanz.TableIDToComparableSpan(tableID),
 			State:      tablepb.TableStateReplicating,
 			Checkpoint: tablepb.Checkpoint{},
 		},
@@ -299,6 +302,7 @@ func TestReplicationSetPollUnknownCapture(t *testing.T) {
 
 	msgs, err := r.poll(&tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 	}, "unknown")
 	require.Nil(t, msgs)
@@ -306,6 +310,7 @@ func TestReplicationSetPollUnknownCapture(t *testing.T) {
 
 	msgs, err = r.poll(&tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateAbsent,
 	}, "unknown")
 	require.Len(t, msgs, 0)
@@ -313,6 +318,7 @@ func TestReplicationSetPollUnknownCapture(t *testing.T) {
 
 	msgs, err = r.poll(&tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 	}, "unknown")
 	require.Len(t, msgs, 0)
@@ -324,7 +330,7 @@ func TestReplicationSetAddTable(t *testing.T) {
 
 	from := "1"
 	tableID := model.TableID(1)
-	r, err := NewReplicationSet(tableID, 0, nil, model.ChangeFeedID{})
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, nil, model.ChangeFeedID{})
 	require.Nil(t, err)
 
 	// Absent -> Prepare
@@ -337,7 +343,8 @@ func TestReplicationSetAddTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: true,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -355,6 +362,7 @@ func TestReplicationSetAddTable(t *testing.T) {
 	// AddTableRequest is lost somehow, send AddTableRequest again.
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateAbsent,
 	})
 	require.Nil(t, err)
@@ -365,7 +373,8 @@ func TestReplicationSetAddTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: true,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -378,6 +387,7 @@ func TestReplicationSetAddTable(t *testing.T) {
 	// Prepare is in-progress.
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStatePreparing,
 	})
 	require.Nil(t, err)
@@ -388,6 +398,7 @@ func TestReplicationSetAddTable(t *testing.T) {
 	// Prepare -> Commit.
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStatePrepared,
 	})
 	require.Nil(t, err)
@@ -398,7 +409,8 @@ func TestReplicationSetAddTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: false,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -411,6 +423,7 @@ func TestReplicationSetAddTable(t *testing.T) {
 	// The secondary AddTable request may be lost.
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStatePrepared,
 	})
 	require.Nil(t, err)
@@ -421,7 +434,8 @@ func TestReplicationSetAddTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: false,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -435,6 +449,7 @@ func TestReplicationSetAddTable(t *testing.T) {
 	// Commit -> Replicating
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 	})
 	require.Nil(t, err)
@@ -446,6 +461,7 @@ func TestReplicationSetAddTable(t *testing.T) {
 	// Replicating -> Replicating
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: 3,
@@ -468,7 +484,7 @@ func TestReplicationSetRemoveTable(t *testing.T) {
 
 	from := "1"
 	tableID := model.TableID(1)
-	r, err := NewReplicationSet(tableID, 0, nil, model.ChangeFeedID{})
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, nil, model.ChangeFeedID{})
 	require.Nil(t, err)
 
 	// Ignore removing table if it's not in replicating.
@@ -489,7 +505,7 @@ func TestReplicationSetRemoveTable(t *testing.T) {
 		MsgType: schedulepb.MsgDispatchTableRequest,
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_RemoveTable{
-				RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.TableID},
+				RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.Span.TableID, Span: r.Span},
 			},
 		},
 	}, msgs[0])
@@ -504,6 +520,7 @@ func TestReplicationSetRemoveTable(t *testing.T) {
 	// Removing is in-progress.
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateStopping,
 	})
 	require.Nil(t, err)
@@ -515,6 +532,7 @@ func TestReplicationSetRemoveTable(t *testing.T) {
 	rClone := clone(r)
 	msgs, err = rClone.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateStopped,
 	})
 	require.Nil(t, err)
@@ -525,6 +543,7 @@ func TestReplicationSetRemoveTable(t *testing.T) {
 	// Removed if the table is stopped.
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateStopped,
 	})
 	require.Nil(t, err)
@@ -546,7 +565,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	t.Parallel()
 
 	tableID := model.TableID(1)
-	r, err := NewReplicationSet(tableID, 0, nil, model.ChangeFeedID{})
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, nil, model.ChangeFeedID{})
 	require.Nil(t, err)
 
 	source := "1"
@@ -572,7 +591,8 @@ func TestReplicationSetMoveTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: true,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -591,6 +611,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	// Source primary sends heartbeat response
 	msgs, err = r.handleTableStatus(source, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: 1,
@@ -607,6 +628,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	// AddTableRequest is lost somehow, send AddTableRequest again.
 	msgs, err = r.handleTableStatus(dest, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateAbsent,
 	})
 	require.Nil(t, err)
@@ -617,7 +639,8 @@ func TestReplicationSetMoveTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: true,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -630,6 +653,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	// Prepare -> Commit.
 	msgs, err = r.handleTableStatus(dest, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStatePrepared,
 	})
 	require.Nil(t, err)
@@ -639,7 +663,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 		MsgType: schedulepb.MsgDispatchTableRequest,
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_RemoveTable{
-				RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.TableID},
+				RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.Span.TableID, Span: r.Span},
 			},
 		},
 	}, msgs[0])
@@ -650,6 +674,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	// Source updates it's table status
 	msgs, err = r.handleTableStatus(source, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: 2,
@@ -663,7 +688,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 		MsgType: schedulepb.MsgDispatchTableRequest,
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_RemoveTable{
-				RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.TableID},
+				RemoveTable: &schedulepb.RemoveTableRequest{TableID: r.Span.TableID, Span: r.Span},
 			},
 		},
 	}, msgs[0])
@@ -678,6 +703,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	// Removing source is in-progress.
 	msgs, err = r.handleTableStatus(source, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateStopping,
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: 3,
@@ -698,6 +724,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	rClone := clone(r)
 	msgs, err = r.handleTableStatus(source, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateStopped,
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: 3,
@@ -712,7 +739,8 @@ func TestReplicationSetMoveTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: false,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -731,6 +759,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	// rClone has checkpoint ts 3, resolved ts 3
 	msgs, err = rClone.handleTableStatus(source, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateAbsent,
 	})
 	require.Nil(t, err)
@@ -741,7 +770,8 @@ func TestReplicationSetMoveTable(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: false,
 					Checkpoint: tablepb.Checkpoint{
 						CheckpointTs: 3,
@@ -762,6 +792,7 @@ func TestReplicationSetMoveTable(t *testing.T) {
 	// Commit -> Replicating
 	msgs, err = r.handleTableStatus(dest, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 	})
 	require.Nil(t, err)
@@ -777,7 +808,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 
 	from := "1"
 	tableID := model.TableID(1)
-	r, err := NewReplicationSet(tableID, 0, nil, model.ChangeFeedID{})
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, nil, model.ChangeFeedID{})
 	require.Nil(t, err)
 
 	// Add table, Absent -> Prepare
@@ -790,7 +821,8 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 		DispatchTableRequest: &schedulepb.DispatchTableRequest{
 			Request: &schedulepb.DispatchTableRequest_AddTable{
 				AddTable: &schedulepb.AddTableRequest{
-					TableID:     r.TableID,
+					TableID:     r.Span.TableID,
+					Span:        r.Span,
 					IsSecondary: true,
 					Checkpoint:  r.Checkpoint,
 				},
@@ -817,6 +849,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 	// Add table, Prepare -> Commit
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStatePrepared,
 	})
 	require.Nil(t, err)
@@ -841,6 +874,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 	// Add table, Commit -> Replicating
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 	})
 	require.Nil(t, err)
@@ -911,6 +945,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 	// Move table, Prepare -> Commit
 	msgs, err = r.handleTableStatus(dest, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStatePrepared,
 	})
 	require.Nil(t, err)
@@ -932,7 +967,8 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 			DispatchTableRequest: &schedulepb.DispatchTableRequest{
 				Request: &schedulepb.DispatchTableRequest_AddTable{
 					AddTable: &schedulepb.AddTableRequest{
-						TableID:     r.TableID,
+						TableID:     r.Span.TableID,
+						Span:        r.Span,
 						IsSecondary: false,
 						Checkpoint:  r.Checkpoint,
 					},
@@ -973,6 +1009,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 			rClone1 := clone(rClone)
 			msgs, err = rClone1.handleTableStatus(rClone1.Primary, &tablepb.TableStatus{
 				TableID: 1,
+				Span:    spanz.TableIDToComparableSpan(1),
 				State:   tablepb.TableStateReplicating,
 			})
 			require.Nil(t, err)
@@ -989,6 +1026,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 			rClone1 := clone(rClone)
 			msgs, err = rClone1.handleTableStatus(rClone1.Primary, &tablepb.TableStatus{
 				TableID: 1,
+				Span:    spanz.TableIDToComparableSpan(1),
 				State:   tablepb.TableStateStopped,
 			})
 			require.Nil(t, err)
@@ -1005,6 +1043,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 			rClone1 := clone(rClone)
 			msgs, err = rClone1.handleTableStatus(rClone1.Primary, &tablepb.TableStatus{
 				TableID: 1,
+				Span:    spanz.TableIDToComparableSpan(1),
 				State:   tablepb.TableStateAbsent,
 			})
 			require.Nil(t, err)
@@ -1015,7 +1054,8 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 				DispatchTableRequest: &schedulepb.DispatchTableRequest{
 					Request: &schedulepb.DispatchTableRequest_AddTable{
 						AddTable: &schedulepb.AddTableRequest{
-							TableID:     r.TableID,
+							TableID:     r.Span.TableID,
+							Span:        r.Span,
 							IsSecondary: true,
 							Checkpoint:  r.Checkpoint,
 						},
@@ -1032,6 +1072,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 	// Move table, original primary is stopped.
 	msgs, err = r.handleTableStatus(from, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateStopped,
 	})
 	require.Nil(t, err)
@@ -1054,6 +1095,7 @@ func TestReplicationSetCaptureShutdown(t *testing.T) {
 	// Commit -> Replicating
 	msgs, err = r.handleTableStatus(dest, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 	})
 	require.Nil(t, err)
@@ -1080,9 +1122,9 @@ func TestReplicationSetCaptureShutdownAfterReconstructCommitState(t *testing.T)
 	from := "1"
 	tableID := model.TableID(1)
 	tableStatus := map[model.CaptureID]*tablepb.TableStatus{
-		from: {TableID: tableID, State: tablepb.TableStatePrepared},
+		from: {TableID: tableID, Span: spanz.TableIDToComparableSpan(tableID), State: tablepb.TableStatePrepared},
 	}
-	r, err := NewReplicationSet(tableID, 0, tableStatus, model.ChangeFeedID{})
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, tableStatus, model.ChangeFeedID{})
 	require.Nil(t, err)
 	require.Equal(t, ReplicationSetStateCommit, r.State)
 	require.Equal(t, "", r.Primary)
@@ -1102,7 +1144,7 @@ func TestReplicationSetMoveTableWithHeartbeatResponse(t *testing.T) {
 	t.Parallel()
 
 	tableID := model.TableID(1)
-	r, err := NewReplicationSet(tableID, 0, nil, model.ChangeFeedID{})
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, nil, model.ChangeFeedID{})
 	require.Nil(t, err)
 
 	source := "1"
@@ -1122,6 +1164,7 @@ func TestReplicationSetMoveTableWithHeartbeatResponse(t *testing.T) {
 	// Prepare -> Commit.
 	msgs, err = r.handleTableStatus(dest, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStatePrepared,
 	})
 	require.Nil(t, err)
@@ -1134,6 +1177,7 @@ func TestReplicationSetMoveTableWithHeartbeatResponse(t *testing.T) {
 	// Source is removed.
 	msgs, err = r.handleTableStatus(source, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateStopped,
 		Checkpoint: tablepb.Checkpoint{
 			CheckpointTs: 3,
@@ -1153,6 +1197,7 @@ func TestReplicationSetMoveTableWithHeartbeatResponse(t *testing.T) {
 	// Source sends a heartbeat response.
 	msgs, err = r.handleTableStatus(source, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateAbsent,
 	})
 	require.Nil(t, err)
@@ -1168,6 +1213,7 @@ func TestReplicationSetMoveTableWithHeartbeatResponse(t *testing.T) {
 	// Commit -> Replicating
 	msgs, err = r.handleTableStatus(dest, &tablepb.TableStatus{
 		TableID: tableID,
+		Span:    spanz.TableIDToComparableSpan(tableID),
 		State:   tablepb.TableStateReplicating,
 	})
 	require.Nil(t, err)
@@ -1189,7 +1235,7 @@ func TestReplicationSetMoveTableSameDestCapture(t *testing.T) {
 	t.Parallel()
 
 	tableID := model.TableID(1)
-	r, err := NewReplicationSet(tableID, 0, nil, model.ChangeFeedID{})
+	r, err := NewReplicationSet(spanz.TableIDToComparableSpan(tableID), 0, nil, model.ChangeFeedID{})
 	require.Nil(t, err)
 
 	source := "1"
@@ -1221,7 +1267,7 @@ func TestReplicationSetCommitRestart(t *testing.T) {
 			Checkpoint: tablepb.Checkpoint{},
 		},
 	}
-	r, err := NewReplicationSet(0, 0, tableStatus, model.ChangeFeedID{})
+	r, err := NewReplicationSet(tablepb.Span{}, 0, tableStatus, model.ChangeFeedID{})
 	require.Nil(t, err)
 	require.Equal(t, ReplicationSetStateCommit, r.State)
 	require.EqualValues(t, RoleSecondary, r.Captures["1"])
@@ -1303,7 +1349,7 @@ func TestReplicationSetRemoveRestart(t *testing.T) {
 			Checkpoint: tablepb.Checkpoint{},
 		},
 	}
-	r, err := NewReplicationSet(0, 0, tableStatus, model.ChangeFeedID{})
+	r, err := NewReplicationSet(tablepb.Span{}, 0, tableStatus, model.ChangeFeedID{})
 	require.Nil(t, err)
 	require.Equal(t, ReplicationSetStateRemoving, r.State)
 	require.False(t, r.hasRole(RoleSecondary))
@@ -1346,10 +1392,10 @@ func TestReplicationSetHeap_Len(t *testing.T) {
 	h := NewReplicationSetHeap(defaultSlowTableHeapSize)
 	require.Equal(t, 0, h.Len())
 
-	h = append(h, &ReplicationSet{TableID: 0})
+	h = append(h, &ReplicationSet{Span: spanz.TableIDToComparableSpan(0)})
 	require.Equal(t, 1, h.Len())
 
-	h = append(h, &ReplicationSet{TableID: 1})
+	h = append(h, &ReplicationSet{Span: spanz.TableIDToComparableSpan(1)})
 	require.Equal(t, 2, h.Len())
 }
 
@@ -1357,9 +1403,9 @@ func TestReplicationSetHeap_Less(t *testing.T) {
 	t.Parallel()
 
 	h := NewReplicationSetHeap(defaultSlowTableHeapSize)
-	h = append(h, &ReplicationSet{TableID: 0, Checkpoint: tablepb.Checkpoint{CheckpointTs: 1}})
-	h = append(h, &ReplicationSet{TableID: 1, Checkpoint: tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 3}})
-	h = append(h, &ReplicationSet{TableID: 2, Checkpoint: tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 4}})
+	h = append(h, &ReplicationSet{Span: spanz.TableIDToComparableSpan(0), Checkpoint: tablepb.Checkpoint{CheckpointTs: 1}})
+	h = append(h, &ReplicationSet{Span: spanz.TableIDToComparableSpan(1), Checkpoint: tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 3}})
+	h = append(h, &ReplicationSet{Span: spanz.TableIDToComparableSpan(2), Checkpoint: tablepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 4}})
 	require.True(t, h.Less(1, 0))
 	require.True(t, h.Less(2, 1))
 }
@@ -1369,14 +1415,14 @@ func TestReplicationSetHeap_Basic(t *testing.T) {
 
 	h := NewReplicationSetHeap(defaultSlowTableHeapSize)
 	heap.Init(&h)
-	heap.Push(&h, &ReplicationSet{TableID: 0, Checkpoint: tablepb.Checkpoint{CheckpointTs: 1}})
-	heap.Push(&h, &ReplicationSet{TableID: 1, Checkpoint: tablepb.Checkpoint{CheckpointTs: 2}})
+	heap.Push(&h, &ReplicationSet{Span: spanz.TableIDToComparableSpan(0), Checkpoint: tablepb.Checkpoint{CheckpointTs: 1}})
+	heap.Push(&h, &ReplicationSet{Span: spanz.TableIDToComparableSpan(1), Checkpoint: tablepb.Checkpoint{CheckpointTs: 2}})
 	require.Equal(t, 2, h.Len())
 
-	require.Equal(t, int64(1), heap.Pop(&h).(*ReplicationSet).TableID)
+	require.Equal(t, int64(1), heap.Pop(&h).(*ReplicationSet).Span.TableID)
 	require.Equal(t, 1, h.Len())
 
-	require.Equal(t, int64(0), heap.Pop(&h).(*ReplicationSet).TableID)
+	require.Equal(t, int64(0), heap.Pop(&h).(*ReplicationSet).Span.TableID)
 	require.Equal(t, 0, h.Len())
 }
 
@@ -1391,7 +1437,7 @@ func TestReplicationSetHeap_MinK(t *testing.T) {
 
 	for i := 2 * defaultSlowTableHeapSize; i > 0; i-- {
 		replicationSet := &ReplicationSet{
-			TableID:    int64(i),
+			Span:       spanz.TableIDToComparableSpan(int64(i)),
 			Checkpoint: tablepb.Checkpoint{CheckpointTs: uint64(i)},
 		}
 		heap.Push(&h, replicationSet)
@@ -1411,8 +1457,8 @@ func TestReplicationSetHeap_MinK(t *testing.T) {
 	tableCounts := h.Len()
 	for i := 0; i < tableCounts; i++ {
 		element := heap.Pop(&h).(*ReplicationSet)
-		fmt.Println(element.TableID)
-		tables = append(tables, element.TableID)
+		fmt.Println(element.Span.TableID)
+		tables = append(tables, element.Span.TableID)
 	}
 	require.Equal(t, expectedTables, tables)
 	require.Equal(t, 0, h.Len())
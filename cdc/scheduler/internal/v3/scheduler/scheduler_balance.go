@@ -19,8 +19,10 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
 )
 
 var _ scheduler = &balanceScheduler{}
@@ -55,9 +57,9 @@ func (b *balanceScheduler) Name() string {
 
 func (b *balanceScheduler) Schedule(
 	_ model.Ts,
-	currentTables []model.TableID,
+	_ []tablepb.Span,
 	captures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
+	replications *spanz.Map[*replication.ReplicationSet],
 ) []*replication.ScheduleTask {
 	if !b.forceBalance {
 		now := time.Now()
@@ -76,29 +78,17 @@ func (b *balanceScheduler) Schedule(
 	}
 
 	tasks := buildBalanceMoveTables(
-		b.random, currentTables, captures, replications, b.maxTaskConcurrency)
+		b.random, captures, replications, b.maxTaskConcurrency)
 	b.forceBalance = len(tasks) != 0
 	return tasks
 }
 
 func buildBalanceMoveTables(
 	random *rand.Rand,
-	currentTables []model.TableID,
 	captures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
+	replications *spanz.Map[*replication.ReplicationSet],
 	maxTaskConcurrency int,
 ) []*replication.ScheduleTask {
-	captureTables := make(map[model.CaptureID][]model.TableID)
-	for _, tableID := range currentTables {
-		rep, ok := replications[tableID]
-		if !ok {
-			continue
-		}
-		for captureID := range rep.Captures {
-			captureTables[captureID] = append(captureTables[captureID], tableID)
-		}
-	}
-
 	moves := newBalanceMoveTables(
 		random, captures, replications, maxTaskConcurrency, model.ChangeFeedID{})
 	tasks := make([]*replication.ScheduleTask, 0, len(moves))
@@ -0,0 +1,317 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// trafficBalanceCooldownRounds is the number of balance rounds that a moved
+// table must wait before it can be moved again.
+const trafficBalanceCooldownRounds = 3
+
+// trafficBalanceEpsilon absorbs floating point errors when comparing loads.
+const trafficBalanceEpsilon = 1e-9
+
+var _ scheduler = &trafficBalanceScheduler{}
+
+// trafficSample is the last observed event count of a table, it is used to
+// compute the throughput of the table.
+type trafficSample struct {
+	eventCount uint64
+	// The physical time (in milliseconds) when the event count is observed.
+	physicalTs int64
+	// Events per second.
+	rate float64
+}
+
+// The scheduler for balancing tables among all captures by the load of tables.
+//
+// The load of a table is the sum of its share of the table count, its share
+// of the throughput, its share of the region count and its share of the sink
+// lag in the changefeed. Tables are moved from the most loaded capture to the
+// least loaded capture only when their loads deviate from the average load by
+// more than the threshold, and a moved table is not moved again within
+// a few balance rounds.
+type trafficBalanceScheduler struct {
+	changefeedID         model.ChangeFeedID
+	lastRebalanceTime    time.Time
+	checkBalanceInterval time.Duration
+	maxTaskConcurrency   int
+	threshold            float64
+
+	round      uint64
+	samples    *spanz.Map[*trafficSample]
+	movedRound *spanz.Map[uint64]
+}
+
+func newTrafficBalanceScheduler(
+	interval time.Duration, concurrency int, threshold float64,
+	changefeedID model.ChangeFeedID,
+) *trafficBalanceScheduler {
+	return &trafficBalanceScheduler{
+		changefeedID:         changefeedID,
+		checkBalanceInterval: interval,
+		maxTaskConcurrency:   concurrency,
+		threshold:            threshold,
+		samples:              spanz.NewMap[*trafficSample](),
+		movedRound:           spanz.NewMap[uint64](),
+	}
+}
+
+func (b *trafficBalanceScheduler) Name() string {
+	return "traffic-balance-scheduler"
+}
+
+func (b *trafficBalanceScheduler) Schedule(
+	_ model.Ts,
+	currentSpans []tablepb.Span,
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.Map[*replication.ReplicationSet],
+) []*replication.ScheduleTask {
+	now := time.Now()
+	if now.Sub(b.lastRebalanceTime) < b.checkBalanceInterval {
+		// skip balance.
+		return nil
+	}
+	b.lastRebalanceTime = now
+
+	for _, capture := range captures {
+		if capture.State == member.CaptureStateStopping {
+			log.Debug("schedulerv3: capture is stopping, premature to balance table")
+			return nil
+		}
+	}
+
+	b.round++
+	loads := b.collectTableLoads(currentSpans, replications)
+	moves := b.buildMoveTables(captures, replications, loads)
+	tasks := make([]*replication.ScheduleTask, 0, len(moves))
+	for i := 0; i < len(moves); i++ {
+		// No need for accept callback here.
+		tasks = append(tasks, &replication.ScheduleTask{MoveTable: &moves[i]})
+	}
+	return tasks
+}
+
+// collectTableLoads updates the traffic samples and returns the load of
+// each replicating table span.
+func (b *trafficBalanceScheduler) collectTableLoads(
+	currentSpans []tablepb.Span,
+	replications *spanz.Map[*replication.ReplicationSet],
+) *spanz.Map[float64] {
+	type tableTraffic struct {
+		rate    float64
+		regions float64
+		lag     float64
+	}
+	traffics := spanz.NewMap[tableTraffic]()
+	var totalRate, totalRegions, totalLag float64
+	for _, span := range currentSpans {
+		rep, ok := replications.Get(span)
+		if !ok || rep.State != replication.ReplicationSetStateReplicating {
+			continue
+		}
+		tt := tableTraffic{
+			rate:    b.updateSample(span, rep),
+			regions: float64(rep.Stats.RegionCount),
+		}
+		if rep.Stats.CurrentTs != 0 && rep.Checkpoint.CheckpointTs != 0 {
+			lag := oracle.ExtractPhysical(rep.Stats.CurrentTs) -
+				oracle.ExtractPhysical(rep.Checkpoint.CheckpointTs)
+			if lag > 0 {
+				tt.lag = float64(lag)
+			}
+		}
+		traffics.ReplaceOrInsert(span, tt)
+		totalRate += tt.rate
+		totalRegions += tt.regions
+		totalLag += tt.lag
+	}
+	// Forget spans that have been removed.
+	var removed []tablepb.Span
+	b.samples.Ascend(func(span tablepb.Span, _ *trafficSample) bool {
+		if !replications.Has(span) {
+			removed = append(removed, span)
+		}
+		return true
+	})
+	for _, span := range removed {
+		b.samples.Delete(span)
+		b.movedRound.Delete(span)
+	}
+
+	share := func(v, total float64) float64 {
+		if total == 0 {
+			return 0
+		}
+		return v / total
+	}
+	loads := spanz.NewMap[float64]()
+	traffics.Ascend(func(span tablepb.Span, tt tableTraffic) bool {
+		loads.ReplaceOrInsert(span, share(1, float64(traffics.Len()))+
+			share(tt.rate, totalRate)+
+			share(tt.regions, totalRegions)+
+			share(tt.lag, totalLag))
+		return true
+	})
+	return loads
+}
+
+// updateSample records the event count of the table span and returns the
+// throughput of the table span.
+func (b *trafficBalanceScheduler) updateSample(
+	span tablepb.Span, rep *replication.ReplicationSet,
+) float64 {
+	if rep.Stats.CurrentTs == 0 {
+		// The capture has not reported stats yet.
+		if sample, ok := b.samples.Get(span); ok {
+			return sample.rate
+		}
+		return 0
+	}
+	physicalTs := oracle.ExtractPhysical(rep.Stats.CurrentTs)
+	sample, ok := b.samples.Get(span)
+	if !ok {
+		b.samples.ReplaceOrInsert(span, &trafficSample{
+			eventCount: rep.Stats.EventCount,
+			physicalTs: physicalTs,
+		})
+		return 0
+	}
+	// The event count is reset after the table is moved to another capture,
+	// keep the last rate in this case.
+	if rep.Stats.EventCount >= sample.eventCount && physicalTs > sample.physicalTs {
+		sample.rate = float64(rep.Stats.EventCount-sample.eventCount) * 1000 /
+			float64(physicalTs-sample.physicalTs)
+	}
+	sample.eventCount = rep.Stats.EventCount
+	sample.physicalTs = physicalTs
+	return sample.rate
+}
+
+func (b *trafficBalanceScheduler) buildMoveTables(
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.Map[*replication.ReplicationSet],
+	loads *spanz.Map[float64],
+) []replication.MoveTable {
+	if len(captures) == 0 {
+		return nil
+	}
+	captureLoads := make(map[model.CaptureID]float64, len(captures))
+	captureTables := make(map[model.CaptureID][]tablepb.Span, len(captures))
+	for captureID := range captures {
+		captureLoads[captureID] = 0
+	}
+	totalLoad := 0.0
+	// Spans are iterated in order, so that the result is deterministic.
+	loads.Ascend(func(span tablepb.Span, load float64) bool {
+		primary := replications.GetV(span).Primary
+		if _, ok := captureLoads[primary]; !ok {
+			return true
+		}
+		captureLoads[primary] += load
+		captureTables[primary] = append(captureTables[primary], span)
+		totalLoad += load
+		return true
+	})
+	avgLoad := totalLoad / float64(len(captures))
+	upperLimit := avgLoad * (1 + b.threshold)
+	lowerLimit := avgLoad * (1 - b.threshold)
+
+	moveTables := make([]replication.MoveTable, 0)
+	for len(moveTables) < b.maxTaskConcurrency {
+		source, target := maxMinLoadCaptures(captureLoads)
+		sourceLoad, targetLoad := captureLoads[source], captureLoads[target]
+		if sourceLoad <= upperLimit && targetLoad >= lowerLimit {
+			// All captures are within the threshold.
+			break
+		}
+		// Pick the table that makes the loads of the two captures closest,
+		// a table is worth moving only if it reduces the gap.
+		gap := sourceLoad - targetLoad
+		victimIdx := -1
+		minDiff := math.MaxFloat64
+		for i, span := range captureTables[source] {
+			load := loads.GetV(span)
+			if load >= gap-trafficBalanceEpsilon {
+				continue
+			}
+			if movedAt, ok := b.movedRound.Get(span); ok &&
+				b.round-movedAt < trafficBalanceCooldownRounds {
+				continue
+			}
+			if diff := math.Abs(load - gap/2); diff < minDiff {
+				minDiff = diff
+				victimIdx = i
+			}
+		}
+		if victimIdx < 0 {
+			break
+		}
+		span := captureTables[source][victimIdx]
+		captureTables[source] = append(
+			captureTables[source][:victimIdx], captureTables[source][victimIdx+1:]...)
+		load := loads.GetV(span)
+		captureLoads[source] -= load
+		captureLoads[target] += load
+		b.movedRound.ReplaceOrInsert(span, b.round)
+		moveTables = append(moveTables, replication.MoveTable{
+			Span:        span,
+			DestCapture: target,
+		})
+		log.Info("schedulerv3: traffic balance moves table",
+			zap.String("namespace", b.changefeedID.Namespace),
+			zap.String("changefeed", b.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.String("source", source),
+			zap.String("target", target),
+			zap.Float64("tableLoad", load),
+			zap.Float64("sourceLoad", sourceLoad),
+			zap.Float64("targetLoad", targetLoad))
+	}
+	return moveTables
+}
+
+// maxMinLoadCaptures returns the captures with the max load and the min load.
+func maxMinLoadCaptures(
+	captureLoads map[model.CaptureID]float64,
+) (maxCapture, minCapture model.CaptureID) {
+	captureIDs := make([]model.CaptureID, 0, len(captureLoads))
+	for captureID := range captureLoads {
+		captureIDs = append(captureIDs, captureID)
+	}
+	// Sort captures so that the result is deterministic.
+	sort.Strings(captureIDs)
+	for _, captureID := range captureIDs {
+		if maxCapture == "" || captureLoads[captureID] > captureLoads[maxCapture] {
+			maxCapture = captureID
+		}
+		if minCapture == "" || captureLoads[captureID] < captureLoads[minCapture] {
+			minCapture = captureID
+		}
+	}
+	return
+}
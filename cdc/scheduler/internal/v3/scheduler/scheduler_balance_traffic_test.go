@@ -0,0 +1,182 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func newTrafficReplicationSet(
+	primary model.CaptureID, physicalTs int64, eventCount uint64,
+) *replication.ReplicationSet {
+	ts := oracle.ComposeTS(physicalTs, 0)
+	return &replication.ReplicationSet{
+		State:      replication.ReplicationSetStateReplicating,
+		Primary:    primary,
+		Checkpoint: tablepb.Checkpoint{CheckpointTs: ts, ResolvedTs: ts},
+		Stats: tablepb.Stats{
+			RegionCount: 1,
+			CurrentTs:   ts,
+			EventCount:  eventCount,
+		},
+	}
+}
+
+func TestSchedulerTrafficBalanceHotTables(t *testing.T) {
+	t.Parallel()
+
+	sched := newTrafficBalanceScheduler(
+		time.Duration(0), 10, 0.2, model.ChangeFeedID{})
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}, "c": {}}
+	currentTables := []model.TableID{1, 2, 3, 4, 5, 6}
+	primaries := map[model.TableID]model.CaptureID{
+		1: "a", 2: "a", 3: "b", 4: "b", 5: "c", 6: "c",
+	}
+
+	// Tables are balanced by count, and there is no traffic yet.
+	replications := make(map[model.TableID]*replication.ReplicationSet)
+	for tableID, primary := range primaries {
+		replications[tableID] = newTrafficReplicationSet(primary, 1000, 0)
+	}
+	tasks := sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 0)
+
+	// Table 1 and 2 become hot, capture "a" is overloaded.
+	for tableID, primary := range primaries {
+		eventCount := uint64(0)
+		if tableID == 1 || tableID == 2 {
+			eventCount = 10000
+		}
+		replications[tableID] = newTrafficReplicationSet(primary, 11000, eventCount)
+	}
+	tasks = sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 2)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spanz.TableIDToComparableSpan(1), DestCapture: "b",
+	}, tasks[0].MoveTable)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spanz.TableIDToComparableSpan(3), DestCapture: "c",
+	}, tasks[1].MoveTable)
+}
+
+func TestSchedulerTrafficBalanceThreshold(t *testing.T) {
+	t.Parallel()
+
+	sched := newTrafficBalanceScheduler(
+		time.Duration(0), 10, 0.2, model.ChangeFeedID{})
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentTables := []model.TableID{1, 2}
+	replications := map[model.TableID]*replication.ReplicationSet{
+		1: newTrafficReplicationSet("a", 1000, 0),
+		2: newTrafficReplicationSet("b", 1000, 0),
+	}
+	tasks := sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 0)
+
+	// The throughput differs slightly, it is within the threshold.
+	replications = map[model.TableID]*replication.ReplicationSet{
+		1: newTrafficReplicationSet("a", 11000, 11000),
+		2: newTrafficReplicationSet("b", 11000, 9000),
+	}
+	tasks = sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 0)
+
+	// Moving the only table does not reduce the gap.
+	captures["c"] = &member.CaptureStatus{}
+	replications = map[model.TableID]*replication.ReplicationSet{
+		1: newTrafficReplicationSet("a", 21000, 50000),
+		2: newTrafficReplicationSet("b", 21000, 9000),
+	}
+	tasks = sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 0)
+
+	// Skip balance within the check interval.
+	sched.checkBalanceInterval = time.Hour
+	captures = map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	replications = map[model.TableID]*replication.ReplicationSet{
+		1: newTrafficReplicationSet("a", 31000, 50000),
+		2: newTrafficReplicationSet("a", 31000, 9000),
+	}
+	tasks = sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 0)
+}
+
+func TestSchedulerTrafficBalanceCooldown(t *testing.T) {
+	t.Parallel()
+
+	sched := newTrafficBalanceScheduler(
+		time.Duration(0), 10, 0.2, model.ChangeFeedID{})
+
+	// New capture "b" online, tables have not reported stats yet.
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {}, "b": {}}
+	currentTables := []model.TableID{1, 2, 3}
+	replications := map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+	}
+	tasks := sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 1)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spanz.TableIDToComparableSpan(1), DestCapture: "b",
+	}, tasks[0].MoveTable)
+
+	// Capture "b" becomes overloaded, but the moved table 1 must not be
+	// moved back immediately.
+	currentTables = []model.TableID{1, 2, 3, 4, 5, 6}
+	replications = map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		4: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		5: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		6: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	}
+	tasks = sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 1)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spanz.TableIDToComparableSpan(4), DestCapture: "a",
+	}, tasks[0].MoveTable)
+
+	// Stopping captures pause balancing.
+	captures["a"].State = member.CaptureStateStopping
+	tasks = sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 0)
+}
+
+func TestSchedulerTrafficBalanceSample(t *testing.T) {
+	t.Parallel()
+
+	sched := newTrafficBalanceScheduler(
+		time.Duration(0), 10, 0.2, model.ChangeFeedID{})
+	span := spanz.TableIDToComparableSpan(1)
+	require.Equal(t, 0.0, sched.updateSample(span, newTrafficReplicationSet("a", 1000, 0)))
+	require.Equal(t, 100.0, sched.updateSample(span, newTrafficReplicationSet("a", 2000, 100)))
+	// The event count is reset after the table is moved, keep the last rate.
+	require.Equal(t, 100.0, sched.updateSample(span, newTrafficReplicationSet("b", 3000, 10)))
+	require.Equal(t, 200.0, sched.updateSample(span, newTrafficReplicationSet("b", 4000, 210)))
+
+	// Samples of removed tables are dropped.
+	sched.collectTableLoads(nil, spanz.NewMap[*replication.ReplicationSet]())
+	require.Equal(t, 0, sched.samples.Len())
+}
@@ -19,8 +19,10 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
 
@@ -53,33 +55,33 @@ func (b *basicScheduler) Name() string {
 
 func (b *basicScheduler) Schedule(
 	checkpointTs model.Ts,
-	currentTables []model.TableID,
+	currentSpans []tablepb.Span,
 	captures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
+	replications *spanz.Map[*replication.ReplicationSet],
 ) []*replication.ScheduleTask {
 	tasks := make([]*replication.ScheduleTask, 0)
-	tablesLenEqual := len(currentTables) == len(replications)
+	tablesLenEqual := len(currentSpans) == replications.Len()
 	tablesAllFind := true
-	newTables := make([]model.TableID, 0)
-	for _, tableID := range currentTables {
-		if len(newTables) >= b.batchSize {
+	newSpans := make([]tablepb.Span, 0)
+	for _, span := range currentSpans {
+		if len(newSpans) >= b.batchSize {
 			break
 		}
-		rep, ok := replications[tableID]
+		rep, ok := replications.Get(span)
 		if !ok {
-			newTables = append(newTables, tableID)
-			// The table ID is not in the replication means the two sets are
+			newSpans = append(newSpans, span)
+			// The span is not in the replication means the two sets are
 			// not identical.
 			tablesAllFind = false
 			continue
 		}
 		if rep.State == replication.ReplicationSetStateAbsent {
-			newTables = append(newTables, tableID)
+			newSpans = append(newSpans, span)
 		}
 	}
 
 	// Build add table tasks.
-	if len(newTables) > 0 {
+	if len(newSpans) > 0 {
 		captureIDs := make([]model.CaptureID, 0, len(captures))
 		for captureID, status := range captures {
 			if status.State == member.CaptureStateStopping {
@@ -108,51 +110,47 @@ func (b *basicScheduler) Schedule(
 			zap.String("namespace", b.changefeedID.Namespace),
 			zap.String("changefeed", b.changefeedID.ID),
 			zap.Strings("captureIDs", captureIDs),
-			zap.Int64s("tableIDs", newTables))
+			zap.Any("spans", newSpans))
 		tasks = append(
-			tasks, newBurstAddTables(checkpointTs, newTables, captureIDs))
+			tasks, newBurstAddTables(checkpointTs, newSpans, captureIDs))
 	}
 
 	// Build remove table tasks.
 	// For most of the time, remove tables are unlikely to happen.
 	//
 	// Fast path for check whether two sets are identical:
-	// If the length of currentTables and replications are equal,
-	// and for all tables in currentTables have a record in replications.
+	// If the length of currentSpans and replications are equal,
+	// and for all spans in currentSpans have a record in replications.
 	if !tablesLenEqual || !tablesAllFind {
-		// The two sets are not identical. We need to find removed tables.
-		intersectionTable := make(map[model.TableID]struct{}, len(currentTables))
-		for _, tableID := range currentTables {
-			_, ok := replications[tableID]
-			if !ok {
-				continue
-			}
-			intersectionTable[tableID] = struct{}{}
+		// The two sets are not identical. We need to find removed spans.
+		currentSpanSet := spanz.NewSet()
+		for _, span := range currentSpans {
+			currentSpanSet.Add(span)
 		}
-		rmTables := make([]model.TableID, 0)
-		for tableID := range replications {
-			_, ok := intersectionTable[tableID]
-			if !ok {
-				rmTables = append(rmTables, tableID)
+		rmSpans := make([]tablepb.Span, 0)
+		replications.Ascend(func(span tablepb.Span, _ *replication.ReplicationSet) bool {
+			if !currentSpanSet.Contain(span) {
+				rmSpans = append(rmSpans, span)
 			}
-		}
-		if len(rmTables) > 0 {
+			return true
+		})
+		if len(rmSpans) > 0 {
 			tasks = append(tasks,
-				newBurstRemoveTables(rmTables, replications, b.changefeedID))
+				newBurstRemoveTables(rmSpans, replications, b.changefeedID))
 		}
 	}
 	return tasks
 }
 
-// newBurstAddTables add each new table to captures in a round-robin way.
+// newBurstAddTables add each new span to captures in a round-robin way.
 func newBurstAddTables(
-	checkpointTs model.Ts, newTables []model.TableID, captureIDs []model.CaptureID,
+	checkpointTs model.Ts, newSpans []tablepb.Span, captureIDs []model.CaptureID,
 ) *replication.ScheduleTask {
 	idx := 0
-	tables := make([]replication.AddTable, 0, len(newTables))
-	for _, tableID := range newTables {
+	tables := make([]replication.AddTable, 0, len(newSpans))
+	for _, span := range newSpans {
 		tables = append(tables, replication.AddTable{
-			TableID:      tableID,
+			Span:         span,
 			CaptureID:    captureIDs[idx],
 			CheckpointTs: checkpointTs,
 		})
@@ -167,12 +165,12 @@ func newBurstAddTables(
 }
 
 func newBurstRemoveTables(
-	rmTables []model.TableID, replications map[model.TableID]*replication.ReplicationSet,
+	rmSpans []tablepb.Span, replications *spanz.Map[*replication.ReplicationSet],
 	changefeedID model.ChangeFeedID,
 ) *replication.ScheduleTask {
-	tables := make([]replication.RemoveTable, 0, len(rmTables))
-	for _, tableID := range rmTables {
-		rep := replications[tableID]
+	tables := make([]replication.RemoveTable, 0, len(rmSpans))
+	for _, span := range rmSpans {
+		rep := replications.GetV(span)
 		var captureID model.CaptureID
 		for id := range rep.Captures {
 			captureID = id
@@ -186,7 +184,7 @@ func newBurstRemoveTables(
 			continue
 		}
 		tables = append(tables, replication.RemoveTable{
-			TableID:   tableID,
+			Span:      span,
 			CaptureID: captureID,
 		})
 	}
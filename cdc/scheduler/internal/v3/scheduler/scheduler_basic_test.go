@@ -36,7 +36,7 @@ func TestSchedulerBasic(t *testing.T) {
 
 	// one capture stopping, another one is initialized
 	captures["a"].State = member.CaptureStateStopping
-	tasks := b.Schedule(0, currentTables, captures, replications)
+	tasks := b.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 1)
 	require.Len(t, tasks[0].BurstBalance.AddTables, 2)
 	require.Equal(t, tasks[0].BurstBalance.AddTables[0].CaptureID, "b")
@@ -44,16 +44,16 @@ func TestSchedulerBasic(t *testing.T) {
 
 	// all capture's stopping, cannot add table
 	captures["b"].State = member.CaptureStateStopping
-	tasks = b.Schedule(0, currentTables, captures, replications)
+	tasks = b.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 0)
 
 	captures["a"].State = member.CaptureStateInitialized
 	captures["b"].State = member.CaptureStateInitialized
-	tasks = b.Schedule(0, currentTables, captures, replications)
+	tasks = b.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 1)
 	require.Len(t, tasks[0].BurstBalance.AddTables, 2)
-	require.Equal(t, tasks[0].BurstBalance.AddTables[0].TableID, model.TableID(1))
-	require.Equal(t, tasks[0].BurstBalance.AddTables[1].TableID, model.TableID(2))
+	require.Equal(t, tasks[0].BurstBalance.AddTables[0].Span.TableID, model.TableID(1))
+	require.Equal(t, tasks[0].BurstBalance.AddTables[1].Span.TableID, model.TableID(2))
 
 	// Capture offline, causes replication.ReplicationSetStateAbsent.
 	// AddTable only.
@@ -78,9 +78,9 @@ func TestSchedulerBasic(t *testing.T) {
 		},
 		4: {State: replication.ReplicationSetStateAbsent},
 	}
-	tasks = b.Schedule(1, currentTables, captures, replications)
+	tasks = b.Schedule(1, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 1)
-	require.Equal(t, tasks[0].BurstBalance.AddTables[0].TableID, model.TableID(4))
+	require.Equal(t, tasks[0].BurstBalance.AddTables[0].Span.TableID, model.TableID(4))
 	require.Equal(t, tasks[0].BurstBalance.AddTables[0].CheckpointTs, model.Ts(1))
 
 	// DDL CREATE/DROP/TRUNCATE TABLE.
@@ -111,16 +111,16 @@ func TestSchedulerBasic(t *testing.T) {
 			},
 		},
 	}
-	tasks = b.Schedule(2, currentTables, captures, replications)
+	tasks = b.Schedule(2, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 2)
 	if tasks[0].BurstBalance.AddTables != nil {
-		require.Equal(t, tasks[0].BurstBalance.AddTables[0].TableID, model.TableID(4))
+		require.Equal(t, tasks[0].BurstBalance.AddTables[0].Span.TableID, model.TableID(4))
 		require.Equal(t, tasks[0].BurstBalance.AddTables[0].CheckpointTs, model.Ts(2))
-		require.Equal(t, tasks[1].BurstBalance.RemoveTables[0].TableID, model.TableID(5))
+		require.Equal(t, tasks[1].BurstBalance.RemoveTables[0].Span.TableID, model.TableID(5))
 	} else {
-		require.Equal(t, tasks[1].BurstBalance.AddTables[0].TableID, model.TableID(4))
+		require.Equal(t, tasks[1].BurstBalance.AddTables[0].Span.TableID, model.TableID(4))
 		require.Equal(t, tasks[0].BurstBalance.AddTables[0].CheckpointTs, model.Ts(2))
-		require.Equal(t, tasks[0].BurstBalance.RemoveTables[0].TableID, model.TableID(5))
+		require.Equal(t, tasks[0].BurstBalance.RemoveTables[0].Span.TableID, model.TableID(5))
 	}
 
 	// RemoveTable only.
@@ -156,9 +156,9 @@ func TestSchedulerBasic(t *testing.T) {
 			},
 		},
 	}
-	tasks = b.Schedule(3, currentTables, captures, replications)
+	tasks = b.Schedule(3, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 1)
-	require.Equal(t, tasks[0].BurstBalance.RemoveTables[0].TableID, model.TableID(5))
+	require.Equal(t, tasks[0].BurstBalance.RemoveTables[0].Span.TableID, model.TableID(5))
 }
 
 func TestSchedulerPriority(t *testing.T) {
@@ -188,7 +188,7 @@ func benchmarkSchedulerBalance(
 		b.ResetTimer()
 		b.Run(name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				sched.Schedule(0, currentTables, captures, replications)
+				sched.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 			}
 		})
 		b.StopTimer()
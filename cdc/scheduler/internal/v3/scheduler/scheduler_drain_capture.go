@@ -19,8 +19,10 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
 
@@ -70,9 +72,9 @@ func (d *drainCaptureScheduler) setTarget(target model.CaptureID) bool {
 
 func (d *drainCaptureScheduler) Schedule(
 	_ model.Ts,
-	_ []model.TableID,
+	_ []tablepb.Span,
 	captures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
+	replications *spanz.Map[*replication.ReplicationSet],
 ) []*replication.ScheduleTask {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -123,9 +125,10 @@ func (d *drainCaptureScheduler) Schedule(
 	}
 
 	maxTaskConcurrency := d.maxTaskConcurrency
-	// victimTables record tables should be moved out from the target capture
-	victimTables := make([]model.TableID, 0, maxTaskConcurrency)
-	for tableID, rep := range replications {
+	// victimSpans record spans should be moved out from the target capture
+	victimSpans := make([]tablepb.Span, 0, maxTaskConcurrency)
+	allReplicating := true
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
 		if rep.State != replication.ReplicationSetStateReplicating {
 			// only drain the target capture if all tables is replicating,
 			log.Debug("schedulerv3: drain capture scheduler skip this tick,"+
@@ -134,26 +137,32 @@ func (d *drainCaptureScheduler) Schedule(
 				zap.String("changefeed", d.changefeedID.ID),
 				zap.String("target", d.target),
 				zap.Any("replication", rep))
-			return nil
+			allReplicating = false
+			return false
 		}
 
 		if rep.Primary == d.target {
-			if len(victimTables) < maxTaskConcurrency {
-				victimTables = append(victimTables, tableID)
+			if len(victimSpans) < maxTaskConcurrency {
+				victimSpans = append(victimSpans, span)
 			}
 		}
 
 		// only calculate workload of other captures not the drain target.
-		if rep.Primary != d.target {
+		// Captures that do not match the placement rule are not destinations.
+		if _, ok := captureWorkload[rep.Primary]; ok {
 			captureWorkload[rep.Primary]++
 		}
+		return true
+	})
+	if !allReplicating {
+		return nil
 	}
 
 	// this always indicate that the whole draining process finished, and can be triggered by:
 	// 1. the target capture has no table at the beginning
 	// 2. all tables moved from the target capture
 	// 3. the target capture cannot be found in the latest captures
-	if len(victimTables) == 0 {
+	if len(victimSpans) == 0 {
 		log.Info("schedulerv3: drain capture scheduler finished, since no table",
 			zap.String("namespace", d.changefeedID.Namespace),
 			zap.String("changefeed", d.changefeedID.ID),
@@ -164,7 +173,7 @@ func (d *drainCaptureScheduler) Schedule(
 
 	// For each victim table, find the target for it
 	result := make([]*replication.ScheduleTask, 0, maxTaskConcurrency)
-	for _, tableID := range victimTables {
+	for _, span := range victimSpans {
 		target := ""
 		minWorkload := math.MaxInt64
 		for captureID, workload := range captureWorkload {
@@ -183,7 +192,7 @@ func (d *drainCaptureScheduler) Schedule(
 
 		result = append(result, &replication.ScheduleTask{
 			MoveTable: &replication.MoveTable{
-				TableID:     tableID,
+				Span:        span,
 				DestCapture: target,
 			},
 			Accept: (replication.Callback)(nil), // No need for accept callback here.
@@ -19,9 +19,11 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
 
@@ -32,6 +34,11 @@ type Manager struct { //nolint:revive
 	schedulers         []scheduler
 	tasksCounter       map[struct{ scheduler, task string }]int
 	maxTaskConcurrency int
+
+	// placement restricts the captures that tables can be scheduled to.
+	placement *config.PlacementConfig
+	// placementUnsatisfied is true if no alive capture matches the placement.
+	placementUnsatisfied bool
 }
 
 // NewSchedulerManager returns a new scheduler manager.
@@ -52,8 +59,16 @@ func NewSchedulerManager(
 		cfg.AddTableBatchSize, changefeedID)
 	sm.schedulers[schedulerPriorityDrainCapture] = newDrainCaptureScheduler(
 		cfg.MaxTaskConcurrency, changefeedID)
-	sm.schedulers[schedulerPriorityBalance] = newBalanceScheduler(
-		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency)
+	sm.schedulers[schedulerPriorityPlacement] = newPlacementScheduler(
+		cfg.MaxTaskConcurrency, changefeedID)
+	if cfg.BalanceStrategy == config.BalanceStrategyTraffic {
+		sm.schedulers[schedulerPriorityBalance] = newTrafficBalanceScheduler(
+			time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency,
+			cfg.BalanceThreshold, changefeedID)
+	} else {
+		sm.schedulers[schedulerPriorityBalance] = newBalanceScheduler(
+			time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency)
+	}
 	sm.schedulers[schedulerPriorityMoveTable] = newMoveTableScheduler(changefeedID)
 	sm.schedulers[schedulerPriorityRebalance] = newRebalanceScheduler(changefeedID)
 
@@ -63,22 +78,23 @@ func NewSchedulerManager(
 // Schedule generates schedule tasks based on the inputs.
 func (sm *Manager) Schedule(
 	checkpointTs model.Ts,
-	currentTables []model.TableID,
+	currentSpans []tablepb.Span,
 	aliveCaptures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
-	runTasking map[model.TableID]*replication.ScheduleTask,
+	replications *spanz.Map[*replication.ReplicationSet],
+	runTasking *spanz.Map[*replication.ScheduleTask],
 ) []*replication.ScheduleTask {
+	aliveCaptures = sm.placeableCaptures(aliveCaptures)
 	for sid, scheduler := range sm.schedulers {
 		// Basic scheduler bypasses max task check, because it handles the most
 		// critical scheduling, e.g. add table via CREATE TABLE DDL.
 		if sid != int(schedulerPriorityBasic) {
-			if len(runTasking) >= sm.maxTaskConcurrency {
+			if runTasking.Len() >= sm.maxTaskConcurrency {
 				// Do not generate more scheduling tasks if there are too many
 				// running tasks.
 				return nil
 			}
 		}
-		tasks := scheduler.Schedule(checkpointTs, currentTables, aliveCaptures, replications)
+		tasks := scheduler.Schedule(checkpointTs, currentSpans, aliveCaptures, replications)
 		for _, t := range tasks {
 			name := struct {
 				scheduler, task string
@@ -98,7 +114,43 @@ func (sm *Manager) Schedule(
 	return nil
 }
 
-// MoveTable moves a table to the target capture.
+// SetPlacement sets the placement rule of the changefeed. Tables are only
+// scheduled to the captures whose labels match the rule.
+func (sm *Manager) SetPlacement(placement *config.PlacementConfig) {
+	sm.placement = placement
+}
+
+// placeableCaptures returns the captures that match the placement rule.
+// All captures are returned if none of them matches, so that tables are
+// still replicated.
+func (sm *Manager) placeableCaptures(
+	aliveCaptures map[model.CaptureID]*member.CaptureStatus,
+) map[model.CaptureID]*member.CaptureStatus {
+	if sm.placement == nil || len(sm.placement.Selectors) == 0 {
+		return aliveCaptures
+	}
+	captures := make(map[model.CaptureID]*member.CaptureStatus, len(aliveCaptures))
+	for id, capture := range aliveCaptures {
+		if sm.placement.Matches(capture.Labels) {
+			captures[id] = capture
+		}
+	}
+	if len(captures) == 0 && len(aliveCaptures) != 0 {
+		if !sm.placementUnsatisfied {
+			log.Warn("schedulerv3: no capture matches the placement rule, "+
+				"schedule tables to all captures",
+				zap.String("namespace", sm.changefeedID.Namespace),
+				zap.String("changefeed", sm.changefeedID.ID),
+				zap.Any("placement", sm.placement))
+		}
+		sm.placementUnsatisfied = true
+		return aliveCaptures
+	}
+	sm.placementUnsatisfied = false
+	return captures
+}
+
+// MoveTable moves all spans of a table to the target capture.
 func (sm *Manager) MoveTable(tableID model.TableID, target model.CaptureID) {
 	scheduler := sm.schedulers[schedulerPriorityMoveTable]
 	moveTableScheduler, ok := scheduler.(*moveTableScheduler)
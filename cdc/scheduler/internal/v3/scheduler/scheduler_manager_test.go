@@ -17,12 +17,34 @@ import (
 	"testing"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/label"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
 )
 
+// tableIDsToSpans converts table IDs to comparable spans.
+func tableIDsToSpans(tables []model.TableID) []tablepb.Span {
+	spans := make([]tablepb.Span, 0, len(tables))
+	for _, tableID := range tables {
+		spans = append(spans, spanz.TableIDToComparableSpan(tableID))
+	}
+	return spans
+}
+
+// mapToSpanMap converts a map keyed by table ID to a map keyed by
+// comparable span.
+func mapToSpanMap[T any](in map[model.TableID]T) *spanz.Map[T] {
+	out := spanz.NewMap[T]()
+	for tableID, v := range in {
+		out.ReplaceOrInsert(spanz.TableIDToComparableSpan(tableID), v)
+	}
+	return out
+}
+
 func TestNewSchedulerManager(t *testing.T) {
 	t.Parallel()
 
@@ -34,6 +56,13 @@ func TestNewSchedulerManager(t *testing.T) {
 	require.NotNil(t, m.schedulers[schedulerPriorityMoveTable])
 	require.NotNil(t, m.schedulers[schedulerPriorityRebalance])
 	require.NotNil(t, m.schedulers[schedulerPriorityDrainCapture])
+	require.IsType(t, &balanceScheduler{}, m.schedulers[schedulerPriorityBalance])
+
+	cfg := config.NewDefaultSchedulerConfig()
+	cfg.BalanceStrategy = config.BalanceStrategyTraffic
+	m = NewSchedulerManager(model.DefaultChangeFeedID("test-changefeed"), cfg)
+	require.IsType(t, &trafficBalanceScheduler{},
+		m.schedulers[schedulerPriorityBalance])
 }
 
 func TestSchedulerManagerScheduler(t *testing.T) {
@@ -52,14 +81,14 @@ func TestSchedulerManagerScheduler(t *testing.T) {
 	// schedulerPriorityBasic bypasses task check.
 	replications := map[model.TableID]*replication.ReplicationSet{}
 	runningTasks := map[model.TableID]*replication.ScheduleTask{1: {}}
-	tasks := m.Schedule(0, currentTables, captures, replications, runningTasks)
+	tasks := m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
 	require.Len(t, tasks, 1)
 
 	// No more task.
 	replications = map[model.TableID]*replication.ReplicationSet{
 		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
 	}
-	tasks = m.Schedule(0, currentTables, captures, replications, runningTasks)
+	tasks = m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
 	require.Len(t, tasks, 0)
 
 	// Move table is drop because of running tasks.
@@ -67,7 +96,7 @@ func TestSchedulerManagerScheduler(t *testing.T) {
 	replications = map[model.TableID]*replication.ReplicationSet{
 		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
 	}
-	tasks = m.Schedule(0, currentTables, captures, replications, runningTasks)
+	tasks = m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
 	require.Len(t, tasks, 0)
 
 	// Move table can proceed after clean up tasks.
@@ -76,6 +105,62 @@ func TestSchedulerManagerScheduler(t *testing.T) {
 		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
 	}
 	runningTasks = map[model.TableID]*replication.ScheduleTask{}
-	tasks = m.Schedule(0, currentTables, captures, replications, runningTasks)
+	tasks = m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
 	require.Len(t, tasks, 1)
 }
+
+func TestSchedulerManagerPlacement(t *testing.T) {
+	t.Parallel()
+
+	m := NewSchedulerManager(model.DefaultChangeFeedID("test-changefeed"),
+		config.NewDefaultSchedulerConfig())
+	m.SetPlacement(&config.PlacementConfig{
+		Selectors: []*label.Selector{{Key: "zone", Target: "west", Op: label.OpEq}},
+	})
+
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateInitialized, Labels: label.Set{"zone": "east"}},
+		"b": {State: member.CaptureStateInitialized, Labels: label.Set{"zone": "west"}},
+	}
+	currentTables := []model.TableID{1, 2}
+
+	// New tables are only added to the capture that matches the placement.
+	replications := map[model.TableID]*replication.ReplicationSet{}
+	runningTasks := map[model.TableID]*replication.ScheduleTask{}
+	tasks := m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].BurstBalance.AddTables, 2)
+	for _, task := range tasks[0].BurstBalance.AddTables {
+		require.Equal(t, "b", task.CaptureID)
+	}
+
+	// Misplaced tables are moved to the capture that matches the placement.
+	replications = map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	}
+	tasks = m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
+	require.Len(t, tasks, 1)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spanz.TableIDToComparableSpan(1), DestCapture: "b",
+	}, tasks[0].MoveTable)
+
+	// Manual move to the capture that does not match the placement is ignored.
+	replications = map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	}
+	m.MoveTable(1, "a")
+	tasks = m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
+	require.Len(t, tasks, 0)
+
+	// Schedule tables to all captures if none of them matches the placement.
+	delete(captures, "b")
+	replications = map[model.TableID]*replication.ReplicationSet{}
+	tasks = m.Schedule(0, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications), mapToSpanMap(runningTasks))
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].BurstBalance.AddTables, 2)
+	for _, task := range tasks[0].BurstBalance.AddTables {
+		require.Equal(t, "a", task.CaptureID)
+	}
+}
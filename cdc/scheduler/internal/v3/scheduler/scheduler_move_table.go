@@ -18,23 +18,35 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
 
 var _ scheduler = &moveTableScheduler{}
 
+// moveTableTask moves all spans of a table to the target capture.
+type moveTableTask struct {
+	target model.CaptureID
+	// spanCount is the number of spans of the table.
+	spanCount int
+	// accepted is the spans that have been accepted by the replication
+	// manager. The task is finished once all spans are accepted.
+	accepted *spanz.Set
+}
+
 type moveTableScheduler struct {
 	mu    sync.Mutex
-	tasks map[model.TableID]*replication.ScheduleTask
+	tasks map[model.TableID]*moveTableTask
 
 	changefeedID model.ChangeFeedID
 }
 
 func newMoveTableScheduler(changefeed model.ChangeFeedID) *moveTableScheduler {
 	return &moveTableScheduler{
-		tasks:        make(map[model.TableID]*replication.ScheduleTask),
+		tasks:        make(map[model.TableID]*moveTableTask),
 		changefeedID: changefeed,
 	}
 }
@@ -50,25 +62,15 @@ func (m *moveTableScheduler) addTask(tableID model.TableID, target model.Capture
 	if _, ok := m.tasks[tableID]; ok {
 		return false
 	}
-	m.tasks[tableID] = &replication.ScheduleTask{
-		MoveTable: &replication.MoveTable{
-			TableID:     tableID,
-			DestCapture: target,
-		},
-		Accept: func() {
-			m.mu.Lock()
-			defer m.mu.Unlock()
-			delete(m.tasks, tableID)
-		},
-	}
+	m.tasks[tableID] = &moveTableTask{target: target, accepted: spanz.NewSet()}
 	return true
 }
 
 func (m *moveTableScheduler) Schedule(
 	_ model.Ts,
-	currentTables []model.TableID,
+	currentSpans []tablepb.Span,
 	captures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
+	replications *spanz.Map[*replication.ReplicationSet],
 ) []*replication.ScheduleTask {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -84,8 +86,8 @@ func (m *moveTableScheduler) Schedule(
 	}
 
 	allTables := model.NewTableSet()
-	for _, tableID := range currentTables {
-		allTables.Add(tableID)
+	for _, span := range currentSpans {
+		allTables.Add(span.TableID)
 	}
 
 	for tableID, task := range m.tasks {
@@ -96,19 +98,19 @@ func (m *moveTableScheduler) Schedule(
 				zap.String("namespace", m.changefeedID.Namespace),
 				zap.String("changefeed", m.changefeedID.ID),
 				zap.Int64("tableID", tableID),
-				zap.String("captureID", task.MoveTable.DestCapture))
+				zap.String("captureID", task.target))
 			delete(m.tasks, tableID)
 			continue
 		}
 
 		// the target capture may offline after manual move table triggered.
-		status, ok := captures[task.MoveTable.DestCapture]
+		status, ok := captures[task.target]
 		if !ok {
 			log.Info("schedulerv3: move table ignored, since the target capture cannot found",
 				zap.String("namespace", m.changefeedID.Namespace),
 				zap.String("changefeed", m.changefeedID.ID),
 				zap.Int64("tableID", tableID),
-				zap.String("captureID", task.MoveTable.DestCapture))
+				zap.String("captureID", task.target))
 			delete(m.tasks, tableID)
 			continue
 		}
@@ -117,37 +119,76 @@ func (m *moveTableScheduler) Schedule(
 				zap.String("namespace", m.changefeedID.Namespace),
 				zap.String("changefeed", m.changefeedID.ID),
 				zap.Int64("tableID", tableID),
-				zap.String("captureID", task.MoveTable.DestCapture),
+				zap.String("captureID", task.target),
 				zap.Any("state", status.State))
 			delete(m.tasks, tableID)
 			continue
 		}
 
-		rep, ok := replications[tableID]
-		if !ok {
-			log.Warn("schedulerv3: move table ignored, table not found in the replication set",
-				zap.String("namespace", m.changefeedID.Namespace),
-				zap.String("changefeed", m.changefeedID.ID),
-				zap.Int64("tableID", tableID),
-				zap.String("captureID", task.MoveTable.DestCapture))
+		// Find the spans of the table that have not been moved yet.
+		var pending []tablepb.Span
+		replicating := true
+		tableStart, tableEnd := spanz.TableIDToComparableRange(tableID)
+		replications.AscendRange(tableStart, tableEnd,
+			func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+				if task.accepted.Contain(span) {
+					return true
+				}
+				// only move replicating table.
+				if rep.State != replication.ReplicationSetStateReplicating {
+					log.Info("schedulerv3: move table ignored, "+
+						"since the table is not replicating now",
+						zap.String("namespace", m.changefeedID.Namespace),
+						zap.String("changefeed", m.changefeedID.ID),
+						zap.Int64("tableID", tableID),
+						zap.String("captureID", task.target),
+						zap.Any("replicationState", rep.State))
+					replicating = false
+					return false
+				}
+				pending = append(pending, span)
+				return true
+			})
+		if !replicating {
 			delete(m.tasks, tableID)
 			continue
 		}
-		// only move replicating table.
-		if rep.State != replication.ReplicationSetStateReplicating {
-			log.Info("schedulerv3: move table ignored, since the table is not replicating now",
-				zap.String("namespace", m.changefeedID.Namespace),
-				zap.String("changefeed", m.changefeedID.ID),
-				zap.Int64("tableID", tableID),
-				zap.String("captureID", task.MoveTable.DestCapture),
-				zap.Any("replicationState", rep.State))
+		if len(pending) == 0 {
+			if task.accepted.Size() == 0 {
+				log.Warn("schedulerv3: move table ignored, "+
+					"table not found in the replication set",
+					zap.String("namespace", m.changefeedID.Namespace),
+					zap.String("changefeed", m.changefeedID.ID),
+					zap.Int64("tableID", tableID),
+					zap.String("captureID", task.target))
+			}
 			delete(m.tasks, tableID)
+			continue
+		}
+		task.spanCount = task.accepted.Size() + len(pending)
+		for _, span := range pending {
+			result = append(result, m.newMoveTableTask(tableID, span, task))
 		}
-	}
-
-	for _, task := range m.tasks {
-		result = append(result, task)
 	}
 
 	return result
 }
+
+func (m *moveTableScheduler) newMoveTableTask(
+	tableID model.TableID, span tablepb.Span, task *moveTableTask,
+) *replication.ScheduleTask {
+	return &replication.ScheduleTask{
+		MoveTable: &replication.MoveTable{
+			Span:        span,
+			DestCapture: task.target,
+		},
+		Accept: func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			task.accepted.Add(span)
+			if task.accepted.Size() == task.spanCount && m.tasks[tableID] == task {
+				delete(m.tasks, tableID)
+			}
+		},
+	}
+}
@@ -17,8 +17,10 @@ import (
 	"testing"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,47 +43,88 @@ func TestSchedulerMoveTable(t *testing.T) {
 	require.Equal(t, "move-table-scheduler", scheduler.Name())
 
 	tasks := scheduler.Schedule(
-		checkpointTs, currentTables, map[model.CaptureID]*member.CaptureStatus{}, replications)
+		checkpointTs, tableIDsToSpans(currentTables),
+		map[model.CaptureID]*member.CaptureStatus{}, mapToSpanMap(replications))
 	require.Len(t, tasks, 0)
 
 	scheduler.addTask(model.TableID(0), "a")
 	tasks = scheduler.Schedule(
-		checkpointTs, currentTables, map[model.CaptureID]*member.CaptureStatus{}, replications)
+		checkpointTs, tableIDsToSpans(currentTables),
+		map[model.CaptureID]*member.CaptureStatus{}, mapToSpanMap(replications))
 	require.Len(t, tasks, 0)
 
 	// move a not exist table
 	scheduler.addTask(model.TableID(0), "a")
-	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	tasks = scheduler.Schedule(checkpointTs, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 0)
 
 	// move table to a not exist capture
 	scheduler.addTask(model.TableID(1), "c")
-	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	tasks = scheduler.Schedule(checkpointTs, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 0)
 
 	// move table not replicating
 	scheduler.addTask(model.TableID(1), "b")
 	tasks = scheduler.Schedule(
-		checkpointTs, currentTables, captures, map[model.TableID]*replication.ReplicationSet{})
+		checkpointTs, tableIDsToSpans(currentTables), captures,
+		spanz.NewMap[*replication.ReplicationSet]())
 	require.Len(t, tasks, 0)
 
 	scheduler.addTask(model.TableID(1), "b")
 	replications[model.TableID(1)].State = replication.ReplicationSetStatePrepare
-	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	tasks = scheduler.Schedule(checkpointTs, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 0)
 
 	scheduler.addTask(model.TableID(1), "b")
 	replications[model.TableID(1)].State = replication.ReplicationSetStateReplicating
-	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	tasks = scheduler.Schedule(checkpointTs, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 1)
-	require.Equal(t, model.TableID(1), tasks[0].MoveTable.TableID)
+	require.Equal(t, model.TableID(1), tasks[0].MoveTable.Span.TableID)
 	require.Equal(t, "b", tasks[0].MoveTable.DestCapture)
-	require.Equal(t, scheduler.tasks[model.TableID(1)], tasks[0])
+	require.Equal(t, 1, scheduler.tasks[model.TableID(1)].spanCount)
 
 	// the target capture is stopping
 	scheduler.addTask(model.TableID(1), "b")
 	captures["b"].State = member.CaptureStateStopping
-	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	tasks = scheduler.Schedule(checkpointTs, tableIDsToSpans(currentTables), captures, mapToSpanMap(replications))
 	require.Len(t, tasks, 0)
 	require.NotContains(t, scheduler.tasks, model.TableID(1))
 }
+
+func TestSchedulerMoveTableSpans(t *testing.T) {
+	t.Parallel()
+
+	captures := map[model.CaptureID]*member.CaptureStatus{"a": {
+		State: member.CaptureStateInitialized,
+	}, "b": {
+		State: member.CaptureStateInitialized,
+	}}
+	span := spanz.TableIDToComparableSpan(1)
+	midKey := append(append([]byte{}, span.StartKey...), 'm')
+	span1 := tablepb.Span{TableID: 1, StartKey: span.StartKey, EndKey: midKey}
+	span2 := tablepb.Span{TableID: 1, StartKey: midKey, EndKey: span.EndKey}
+	replications := spanz.NewMap[*replication.ReplicationSet]()
+	replications.ReplaceOrInsert(span1, &replication.ReplicationSet{
+		State: replication.ReplicationSetStateReplicating, Primary: "a",
+	})
+	replications.ReplaceOrInsert(span2, &replication.ReplicationSet{
+		State: replication.ReplicationSetStateReplicating, Primary: "a",
+	})
+	currentSpans := []tablepb.Span{span1, span2}
+
+	// All spans of the table are moved.
+	scheduler := newMoveTableScheduler(model.ChangeFeedID{})
+	require.True(t, scheduler.addTask(model.TableID(1), "b"))
+	tasks := scheduler.Schedule(0, currentSpans, captures, replications)
+	require.Len(t, tasks, 2)
+	require.Equal(t, span1, tasks[0].MoveTable.Span)
+	require.Equal(t, span2, tasks[1].MoveTable.Span)
+	require.Equal(t, "b", tasks[1].MoveTable.DestCapture)
+
+	// The table task is done only after all spans are accepted.
+	tasks[0].Accept()
+	require.Contains(t, scheduler.tasks, model.TableID(1))
+	require.False(t, scheduler.addTask(model.TableID(1), "a"))
+	tasks[1].Accept()
+	require.NotContains(t, scheduler.tasks, model.TableID(1))
+}
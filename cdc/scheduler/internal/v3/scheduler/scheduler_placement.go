@@ -0,0 +1,124 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"go.uber.org/zap"
+)
+
+var _ scheduler = &placementScheduler{}
+
+// placementScheduler moves tables out of the captures that do not match the
+// placement rule of the changefeed.
+//
+// The scheduler manager only passes the captures that match the placement
+// rule, so a replicating table whose primary is not one of the captures is
+// misplaced, e.g., the placement rule was added after the table had been
+// scheduled, or the table was scheduled when no capture matched the rule.
+type placementScheduler struct {
+	changefeedID       model.ChangeFeedID
+	maxTaskConcurrency int
+}
+
+func newPlacementScheduler(
+	concurrency int, changefeedID model.ChangeFeedID,
+) *placementScheduler {
+	return &placementScheduler{
+		changefeedID:       changefeedID,
+		maxTaskConcurrency: concurrency,
+	}
+}
+
+func (p *placementScheduler) Name() string {
+	return "placement-scheduler"
+}
+
+func (p *placementScheduler) Schedule(
+	_ model.Ts,
+	_ []tablepb.Span,
+	captures map[model.CaptureID]*member.CaptureStatus,
+	replications *spanz.Map[*replication.ReplicationSet],
+) []*replication.ScheduleTask {
+	// Currently, the workload is the number of tables in a capture.
+	captureWorkload := make(map[model.CaptureID]int)
+	for id, capture := range captures {
+		if capture.State == member.CaptureStateInitialized {
+			captureWorkload[id] = 0
+		}
+	}
+	if len(captureWorkload) == 0 {
+		return nil
+	}
+
+	// Spans are iterated in order, so that the result is deterministic.
+	victims := make([]tablepb.Span, 0)
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
+		if rep.State != replication.ReplicationSetStateReplicating {
+			return true
+		}
+		if _, ok := captures[rep.Primary]; !ok {
+			victims = append(victims, span)
+			return true
+		}
+		if _, ok := captureWorkload[rep.Primary]; ok {
+			captureWorkload[rep.Primary]++
+		}
+		return true
+	})
+	if len(victims) == 0 {
+		return nil
+	}
+	if len(victims) > p.maxTaskConcurrency {
+		victims = victims[:p.maxTaskConcurrency]
+	}
+
+	captureIDs := make([]model.CaptureID, 0, len(captureWorkload))
+	for id := range captureWorkload {
+		captureIDs = append(captureIDs, id)
+	}
+	sort.Strings(captureIDs)
+	tasks := make([]*replication.ScheduleTask, 0, len(victims))
+	for _, span := range victims {
+		target := captureIDs[0]
+		for _, id := range captureIDs {
+			if captureWorkload[id] < captureWorkload[target] {
+				target = id
+			}
+		}
+		log.Info("schedulerv3: move table to a capture that matches the placement",
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.String("source", replications.GetV(span).Primary),
+			zap.String("target", target))
+		tasks = append(tasks, &replication.ScheduleTask{
+			MoveTable: &replication.MoveTable{
+				Span:        span,
+				DestCapture: target,
+			},
+			Accept: (replication.Callback)(nil), // No need for accept callback here.
+		})
+		// Increase target workload to make sure tables are evenly distributed.
+		captureWorkload[target]++
+	}
+	return tasks
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
+	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerPlacement(t *testing.T) {
+	t.Parallel()
+
+	sched := newPlacementScheduler(2, model.ChangeFeedID{})
+
+	// Only capture "a" and "b" match the placement, tables on capture "c"
+	// are misplaced.
+	captures := map[model.CaptureID]*member.CaptureStatus{
+		"a": {State: member.CaptureStateInitialized},
+		"b": {State: member.CaptureStateInitialized},
+	}
+	replications := map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "c"},
+		3: {State: replication.ReplicationSetStateReplicating, Primary: "c"},
+		4: {State: replication.ReplicationSetStateReplicating, Primary: "c"},
+		5: {State: replication.ReplicationSetStatePrepare, Primary: "c"},
+	}
+	tasks := sched.Schedule(0, nil, captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 2)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spanz.TableIDToComparableSpan(2), DestCapture: "b",
+	}, tasks[0].MoveTable)
+	require.EqualValues(t, &replication.MoveTable{
+		Span: spanz.TableIDToComparableSpan(3), DestCapture: "a",
+	}, tasks[1].MoveTable)
+
+	// Uninitialized captures are not destinations.
+	captures["b"].State = member.CaptureStateUninitialized
+	tasks = sched.Schedule(0, nil, captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 2)
+	require.Equal(t, "a", tasks[0].MoveTable.DestCapture)
+	require.Equal(t, "a", tasks[1].MoveTable.DestCapture)
+
+	// All tables are placed.
+	captures["b"].State = member.CaptureStateInitialized
+	replications = map[model.TableID]*replication.ReplicationSet{
+		1: {State: replication.ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: replication.ReplicationSetStateReplicating, Primary: "b"},
+	}
+	tasks = sched.Schedule(0, nil, captures, mapToSpanMap(replications))
+	require.Len(t, tasks, 0)
+}
@@ -16,14 +16,15 @@ package scheduler
 import (
 	"math"
 	"math/rand"
-	"sort"
 	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/member"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/replication"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"go.uber.org/zap"
 )
 
@@ -50,9 +51,9 @@ func (r *rebalanceScheduler) Name() string {
 
 func (r *rebalanceScheduler) Schedule(
 	_ model.Ts,
-	currentTables []model.TableID,
+	currentSpans []tablepb.Span,
 	captures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
+	replications *spanz.Map[*replication.ReplicationSet],
 ) []*replication.ScheduleTask {
 	// rebalance is not triggered, or there is still some pending task,
 	// do not generate new tasks.
@@ -75,8 +76,8 @@ func (r *rebalanceScheduler) Schedule(
 	}
 
 	// only rebalance when all tables are replicating
-	for _, tableID := range currentTables {
-		rep, ok := replications[tableID]
+	for _, span := range currentSpans {
+		rep, ok := replications.Get(span)
 		if !ok {
 			return nil
 		}
@@ -108,26 +109,33 @@ func (r *rebalanceScheduler) Schedule(
 func newBalanceMoveTables(
 	random *rand.Rand,
 	captures map[model.CaptureID]*member.CaptureStatus,
-	replications map[model.TableID]*replication.ReplicationSet,
+	replications *spanz.Map[*replication.ReplicationSet],
 	maxTaskLimit int,
 	changefeedID model.ChangeFeedID,
 ) []replication.MoveTable {
-	tablesPerCapture := make(map[model.CaptureID]*model.TableSet)
+	tablesPerCapture := make(map[model.CaptureID]*spanz.Set)
 	for captureID := range captures {
-		tablesPerCapture[captureID] = model.NewTableSet()
+		tablesPerCapture[captureID] = spanz.NewSet()
 	}
 
-	for tableID, rep := range replications {
+	replications.Ascend(func(span tablepb.Span, rep *replication.ReplicationSet) bool {
 		if rep.State != replication.ReplicationSetStateReplicating {
-			continue
+			return true
 		}
-		tablesPerCapture[rep.Primary].Add(tableID)
-	}
+		tables, ok := tablesPerCapture[rep.Primary]
+		if !ok {
+			// The primary does not match the placement rule, the table is
+			// moved by the placement scheduler.
+			return true
+		}
+		tables.Add(span)
+		return true
+	})
 
 	// findVictim return tables which need to be moved
-	upperLimitPerCapture := int(math.Ceil(float64(len(replications)) / float64(len(captures))))
+	upperLimitPerCapture := int(math.Ceil(float64(replications.Len()) / float64(len(captures))))
 
-	victims := make([]model.TableID, 0)
+	victims := make([]tablepb.Span, 0)
 	for _, ts := range tablesPerCapture {
 		tables := ts.Keys()
 		if random != nil {
@@ -141,11 +149,9 @@ func newBalanceMoveTables(
 				tables[i], tables[j] = tables[j], tables[i]
 			})
 		} else {
-			// sort the tableIDs here so that the result is deterministic,
+			// sort the spans here so that the result is deterministic,
 			// which would aid testing and debugging.
-			sort.Slice(tables, func(i, j int) bool {
-				return tables[i] < tables[j]
-			})
+			spanz.Sort(tables)
 		}
 
 		tableNum2Remove := len(tables) - upperLimitPerCapture
@@ -172,7 +178,7 @@ func newBalanceMoveTables(
 	}
 	// for each victim table, find the target for it
 	moveTables := make([]replication.MoveTable, 0, len(victims))
-	for idx, tableID := range victims {
+	for idx, span := range victims {
 		target := ""
 		minWorkload := math.MaxInt64
 
@@ -195,10 +201,10 @@ func newBalanceMoveTables(
 		}
 
 		moveTables = append(moveTables, replication.MoveTable{
-			TableID:     tableID,
+			Span:        span,
 			DestCapture: target,
 		})
-		tablesPerCapture[target].Add(tableID)
+		tablesPerCapture[target].Add(span)
 		captureWorkload[target] = randomizeWorkload(random, tablesPerCapture[target].Size())
 	}
 
@@ -23,7 +23,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/p2p"
-	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -84,12 +84,13 @@ func NewScheduler(
 	messageServer *p2p.MessageServer,
 	messageRouter p2p.MessageRouter,
 	ownerRevision int64,
+	up *upstream.Upstream,
 	cfg *config.SchedulerConfig,
-	pdClock pdutil.Clock,
+	placement *config.PlacementConfig,
 ) (Scheduler, error) {
 	return v3.NewCoordinator(
-		ctx, captureID, changeFeedID,
-		messageServer, messageRouter, ownerRevision, cfg, pdClock)
+		ctx, captureID, changeFeedID, messageServer, messageRouter,
+		ownerRevision, up.RegionCache, cfg, placement, up.PDClock)
 }
 
 // InitMetrics registers all metrics used in scheduler
@@ -28,8 +28,10 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tiflow/cdc"
+	"github.com/pingcap/tiflow/cdc/api/middleware"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/kv"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/factory"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/db/system"
@@ -196,13 +198,33 @@ func (s *server) prepare(ctx context.Context) error {
 		return errors.Trace(err)
 	}
 
+	captureMemQuota, err := newCaptureMemQuota(conf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	s.capture = capture.NewCapture(
 		s.pdEndpoints, createEtcdClient, s.grpcService,
-		s.tableActorSystem, s.sortEngineFactory, s.sorterSystem)
+		s.tableActorSystem, s.sortEngineFactory, s.sorterSystem, captureMemQuota)
 
 	return nil
 }
 
+// newCaptureMemQuota creates the memory quota shared by all changefeeds on the
+// capture, the memory that the sorter can use is reserved from it.
+func newCaptureMemQuota(conf *config.ServerConfig) (*memquota.CaptureMemQuota, error) {
+	if conf.MemoryQuota == 0 {
+		return nil, nil
+	}
+	totalMemory, err := memory.MemTotal()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	memPercentage := float64(conf.Sorter.MaxMemoryPercentage) / 100
+	sorterBytes := uint64(float64(totalMemory) * memPercentage)
+	return memquota.NewCaptureMemQuota(conf.MemoryQuota, sorterBytes)
+}
+
 func (s *server) startActorSystems(ctx context.Context) error {
 	if s.tableActorSystem != nil {
 		s.tableActorSystem.Stop()
@@ -292,6 +314,7 @@ func (s *server) startStatusHTTP(lis net.Listener) error {
 	// Add ReadTimeout and WriteTimeout to avoid some abnormal connections never close.
 	s.statusServer = &http.Server{
 		Handler:      router,
+		ConnContext:  middleware.ConnContext,
 		ReadTimeout:  httpConnectionTimeout,
 		WriteTimeout: httpConnectionTimeout,
 	}
@@ -41,11 +41,12 @@ import (
 // BatchEncoder converts the events to binary Avro data
 type BatchEncoder struct {
 	namespace          string
-	keySchemaManager   *schemaManager
-	valueSchemaManager *schemaManager
+	keySchemaManager   *SchemaManager
+	valueSchemaManager *SchemaManager
 	result             []*common.Message
 
 	enableTiDBExtension        bool
+	enableWatermark            bool
 	decimalHandlingMode        string
 	bigintUnsignedHandlingMode string
 }
@@ -114,9 +115,21 @@ func (a *BatchEncoder) AppendRowChangedEvent(
 	return nil
 }
 
-// EncodeCheckpointEvent is no-op for now
+// EncodeCheckpointEvent only encodes the watermark event if both the TiDB
+// extension and the watermark are enabled, it's no-op otherwise.
 func (a *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
-	return nil, nil
+	if !a.enableTiDBExtension || !a.enableWatermark {
+		return nil, nil
+	}
+	buf := new(bytes.Buffer)
+	data := []interface{}{watermarkByte, ts}
+	for _, v := range data {
+		err := binary.Write(buf, binary.BigEndian, v)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrAvroToEnvelopeError, err)
+		}
+	}
+	return common.NewResolvedMsg(config.ProtocolAvro, nil, buf.Bytes(), ts), nil
 }
 
 // EncodeDDLEvent is no-op now
@@ -146,7 +159,7 @@ func (a *BatchEncoder) avroEncode(
 		cols                []*model.Column
 		colInfos            []rowcodec.ColInfo
 		enableTiDBExtension bool
-		schemaManager       *schemaManager
+		schemaManager       *SchemaManager
 		operation           string
 	)
 	if isKey {
@@ -762,7 +775,13 @@ func columnToAvroData(
 	}
 }
 
-const magicByte = uint8(0)
+const (
+	magicByte = uint8(0)
+	// watermarkByte is the first byte of the watermark event, which is not
+	// a part of the confluent avro wire format. It's only used when the TiDB
+	// extension and the watermark are enabled.
+	watermarkByte = uint8(3)
+)
 
 // confluent avro wire format, confluent avro is not same as apache avro
 // https://rmoff.net/2020/07/03/why-json-isnt-the-same-as-json-schema-in-kafka-connect-converters \
@@ -782,8 +801,8 @@ func (r *avroEncodeResult) toEnvelope() ([]byte, error) {
 type batchEncoderBuilder struct {
 	namespace          string
 	config             *common.Config
-	keySchemaManager   *schemaManager
-	valueSchemaManager *schemaManager
+	keySchemaManager   *SchemaManager
+	valueSchemaManager *SchemaManager
 }
 
 const (
@@ -829,6 +848,7 @@ func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
 	encoder.valueSchemaManager = b.valueSchemaManager
 	encoder.result = make([]*common.Message, 0, 1024)
 	encoder.enableTiDBExtension = b.config.EnableTiDBExtension
+	encoder.enableWatermark = b.config.AvroEnableWatermark
 	encoder.decimalHandlingMode = b.config.AvroDecimalHandlingMode
 	encoder.bigintUnsignedHandlingMode = b.config.AvroBigintUnsignedHandlingMode
 
@@ -0,0 +1,355 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// decoder decodes the avro messages produced by the BatchEncoder. One message
+// contains at most one event, the writer schemas are fetched from the schema
+// registry by the registry IDs carried in the messages.
+type decoder struct {
+	ctx                context.Context
+	keySchemaManager   *SchemaManager
+	valueSchemaManager *SchemaManager
+
+	key   []byte
+	value []byte
+}
+
+// NewDecoder returns a decoder for the avro message with the given key and value.
+func NewDecoder(
+	ctx context.Context,
+	key, value []byte,
+	keySchemaManager, valueSchemaManager *SchemaManager,
+) codec.EventBatchDecoder {
+	return &decoder{
+		ctx:                ctx,
+		keySchemaManager:   keySchemaManager,
+		valueSchemaManager: valueSchemaManager,
+		key:                key,
+		value:              value,
+	}
+}
+
+// HasNext implements the EventBatchDecoder interface
+func (d *decoder) HasNext() (model.MessageType, bool, error) {
+	if len(d.key) == 0 && len(d.value) == 0 {
+		return model.MessageTypeUnknown, false, nil
+	}
+	// the tombstone message of the delete event only has the key.
+	if len(d.value) == 0 {
+		return model.MessageTypeRow, true, nil
+	}
+	switch d.value[0] {
+	case magicByte:
+		return model.MessageTypeRow, true, nil
+	case watermarkByte:
+		return model.MessageTypeResolved, true, nil
+	default:
+		return model.MessageTypeUnknown, false, cerror.ErrAvroDecodeFailed.GenWithStack(
+			"unknown magic byte %d", d.value[0])
+	}
+}
+
+// NextResolvedEvent implements the EventBatchDecoder interface
+func (d *decoder) NextResolvedEvent() (uint64, error) {
+	if len(d.value) != 9 || d.value[0] != watermarkByte {
+		return 0, cerror.ErrAvroDecodeFailed.GenWithStack("not found resolved event message")
+	}
+	ts := binary.BigEndian.Uint64(d.value[1:])
+	d.key, d.value = nil, nil
+	return ts, nil
+}
+
+// NextDDLEvent implements the EventBatchDecoder interface,
+// DDL events are not supported by the avro protocol.
+func (d *decoder) NextDDLEvent() (*model.DDLEvent, error) {
+	return nil, cerror.ErrAvroDecodeFailed.GenWithStack("avro protocol does not support DDL events")
+}
+
+// NextRowChangedEvent implements the EventBatchDecoder interface
+func (d *decoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if len(d.key) == 0 && len(d.value) == 0 {
+		return nil, cerror.ErrAvroDecodeFailed.GenWithStack("not found row changed event message")
+	}
+
+	handleKeys := make(map[string]struct{})
+	var keyCols []*model.Column
+	var keySchema *avroSchemaTop
+	if len(d.key) != 0 {
+		native, schema, err := d.decode(d.key, d.keySchemaManager)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keyCols, err = assembleColumns(native, schema, handleKeys)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, col := range keyCols {
+			col.Flag.SetIsHandleKey()
+			handleKeys[col.Name] = struct{}{}
+		}
+		keySchema = schema
+	}
+
+	event := new(model.RowChangedEvent)
+	if len(d.value) == 0 {
+		// The tombstone message of the delete event, it only contains the handle
+		// key columns and the commit ts is unknown.
+		event.Table = schemaToTableName(keySchema)
+		event.PreColumns = keyCols
+		d.key, d.value = nil, nil
+		return event, nil
+	}
+
+	native, schema, err := d.decode(d.value, d.valueSchemaManager)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cols, err := assembleColumns(native, schema, handleKeys)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	event.Table = schemaToTableName(schema)
+	if commitTs, ok := native[tidbCommitTs].(int64); ok {
+		event.CommitTs = uint64(commitTs)
+	}
+	event.Columns = cols
+	d.key, d.value = nil, nil
+	return event, nil
+}
+
+// decode unwraps the confluent avro envelope and decodes the data with the
+// writer schema registered with the registry ID in the envelope.
+func (d *decoder) decode(
+	data []byte, schemaManager *SchemaManager,
+) (map[string]interface{}, *avroSchemaTop, error) {
+	if len(data) < 5 || data[0] != magicByte {
+		return nil, nil, cerror.ErrAvroDecodeFailed.GenWithStack("invalid avro envelope")
+	}
+	registryID := int(binary.BigEndian.Uint32(data[1:5]))
+	avroCodec, err := schemaManager.LookupByID(d.ctx, registryID)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	native, _, err := avroCodec.NativeFromBinary(data[5:])
+	if err != nil {
+		return nil, nil, cerror.WrapError(cerror.ErrAvroDecodeFailed, err)
+	}
+	result, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, nil, cerror.ErrAvroDecodeFailed.GenWithStack("native data is not a record")
+	}
+
+	schema := new(avroSchemaTop)
+	if err := json.Unmarshal([]byte(avroCodec.Schema()), schema); err != nil {
+		return nil, nil, cerror.WrapError(cerror.ErrAvroDecodeFailed, err)
+	}
+	return result, schema, nil
+}
+
+// schemaToTableName extracts the table name from the record schema, see
+// getAvroNamespace for how the namespace is constructed.
+func schemaToTableName(schema *avroSchemaTop) *model.TableName {
+	tableName := &model.TableName{Table: schema.Name}
+	if idx := strings.Index(schema.Namespace, "."); idx >= 0 {
+		tableName.Schema = schema.Namespace[idx+1:]
+	} else {
+		tableName.Schema = schema.Namespace
+	}
+	return tableName
+}
+
+var tidbType2Type = map[string]byte{
+	"INT":             mysql.TypeLong,
+	"INT UNSIGNED":    mysql.TypeLong,
+	"BIGINT":          mysql.TypeLonglong,
+	"BIGINT UNSIGNED": mysql.TypeLonglong,
+	"FLOAT":           mysql.TypeFloat,
+	"DOUBLE":          mysql.TypeDouble,
+	"BIT":             mysql.TypeBit,
+	"DECIMAL":         mysql.TypeNewDecimal,
+	"TEXT":            mysql.TypeVarchar,
+	"BLOB":            mysql.TypeBlob,
+	"ENUM":            mysql.TypeEnum,
+	"SET":             mysql.TypeSet,
+	"JSON":            mysql.TypeJSON,
+	"DATE":            mysql.TypeDate,
+	"DATETIME":        mysql.TypeDatetime,
+	"TIMESTAMP":       mysql.TypeTimestamp,
+	"TIME":            mysql.TypeDuration,
+	"YEAR":            mysql.TypeYear,
+}
+
+// assembleColumns converts the native data of a record to columns,
+// the TiDB extension fields are skipped.
+func assembleColumns(
+	native map[string]interface{},
+	schema *avroSchemaTop,
+	handleKeys map[string]struct{},
+) ([]*model.Column, error) {
+	cols := make([]*model.Column, 0, len(schema.Fields))
+	for _, field := range schema.Fields {
+		name, _ := field["name"].(string)
+		if name == tidbOp || name == tidbCommitTs || name == tidbPhysicalTime {
+			continue
+		}
+
+		fieldType, nullable := unwrapNullableType(field["type"])
+		if fieldType == nil {
+			return nil, cerror.ErrAvroDecodeFailed.GenWithStack(
+				"unexpected type of field %s", name)
+		}
+		parameters, _ := fieldType["connect.parameters"].(map[string]interface{})
+		tt, _ := parameters[tidbType].(string)
+		mysqlType, ok := tidbType2Type[tt]
+		if !ok {
+			return nil, cerror.ErrAvroDecodeFailed.GenWithStack(
+				"unknown tidb type %s of field %s", tt, name)
+		}
+
+		col := &model.Column{Name: name, Type: mysqlType}
+		if nullable {
+			col.Flag.SetIsNullable()
+		}
+		if _, ok := handleKeys[name]; ok {
+			col.Flag.SetIsHandleKey()
+		}
+		if strings.HasSuffix(tt, " UNSIGNED") {
+			col.Flag.SetIsUnsigned()
+		}
+		if tt == "BLOB" {
+			col.Flag.SetIsBinary()
+		}
+
+		value := native[name]
+		// https://pkg.go.dev/github.com/linkedin/goavro/v2#Union
+		if union, ok := value.(map[string]interface{}); ok {
+			for _, v := range union {
+				value = v
+			}
+		}
+		if value != nil {
+			v, err := avroDataToColumnValue(value, tt, fieldType)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			col.Value = v
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// unwrapNullableType returns the non-null type of the field and whether the
+// field is nullable.
+func unwrapNullableType(tp interface{}) (map[string]interface{}, bool) {
+	switch t := tp.(type) {
+	case map[string]interface{}:
+		return t, false
+	case []interface{}:
+		for _, v := range t {
+			if m, ok := v.(map[string]interface{}); ok {
+				return m, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// avroDataToColumnValue is the reverse of columnToAvroData.
+func avroDataToColumnValue(
+	data interface{}, tt string, fieldType map[string]interface{},
+) (interface{}, error) {
+	switch tt {
+	case "INT", "YEAR":
+		if v, ok := data.(int32); ok {
+			return int64(v), nil
+		}
+	case "INT UNSIGNED":
+		switch v := data.(type) {
+		case int32:
+			return uint64(v), nil
+		case int64:
+			return uint64(v), nil
+		}
+	case "BIGINT":
+		if v, ok := data.(int64); ok {
+			return v, nil
+		}
+	case "BIGINT UNSIGNED":
+		switch v := data.(type) {
+		case int64:
+			return uint64(v), nil
+		case string:
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrAvroDecodeFailed, err)
+			}
+			return n, nil
+		}
+	case "FLOAT", "DOUBLE":
+		if v, ok := data.(float64); ok {
+			return v, nil
+		}
+	case "BIT":
+		if v, ok := data.([]byte); ok {
+			n, err := types.BinaryLiteral(v).ToInt(nil)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrAvroDecodeFailed, err)
+			}
+			return n, nil
+		}
+	case "DECIMAL":
+		switch v := data.(type) {
+		case *big.Rat:
+			scale, _ := fieldType["scale"].(float64)
+			return v.FloatString(int(scale)), nil
+		case string:
+			return v, nil
+		}
+	case "TEXT":
+		if v, ok := data.(string); ok {
+			return []byte(v), nil
+		}
+	case "BLOB":
+		if v, ok := data.([]byte); ok {
+			return v, nil
+		}
+	case "ENUM", "SET", "JSON", "DATE", "DATETIME", "TIMESTAMP", "TIME":
+		if v, ok := data.(string); ok {
+			return v, nil
+		}
+	}
+	log.Error("unexpected avro data", zap.String("tidbType", tt), zap.Any("data", data))
+	return nil, cerror.ErrAvroDecodeFailed.GenWithStack(
+		"unexpected avro data %v for tidb type %s", data, tt)
+}
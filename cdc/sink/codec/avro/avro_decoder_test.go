@@ -0,0 +1,165 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newDecoderTestRow() *model.RowChangedEvent {
+	decimalFt := types.NewFieldType(mysql.TypeNewDecimal)
+	decimalFt.SetFlen(10)
+	decimalFt.SetDecimal(2)
+	return &model.RowChangedEvent{
+		CommitTs: 417318403368288260,
+		Table:    &model.TableName{Schema: "testdb", Table: "avrodecode"},
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "testdb", Table: "avrodecode"},
+		},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: int64(1)},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte("alice")},
+			{Name: "data", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: []byte{1, 2}},
+			{Name: "price", Type: mysql.TypeNewDecimal, Value: "12.30"},
+			{
+				Name: "counter", Type: mysql.TypeLonglong,
+				Flag: model.UnsignedFlag, Value: uint64(1) << 63,
+			},
+			{Name: "score", Type: mysql.TypeDouble, Value: float64(3.5)},
+			{Name: "created", Type: mysql.TypeDatetime, Value: "2022-12-01 12:00:00"},
+			{Name: "remark", Type: mysql.TypeLong, Flag: model.NullableFlag, Value: nil},
+		},
+		ColInfos: []rowcodec.ColInfo{
+			{ID: 1, IsPKHandle: true, Ft: types.NewFieldType(mysql.TypeLong)},
+			{ID: 2, Ft: types.NewFieldType(mysql.TypeVarchar)},
+			{ID: 3, Ft: setBinChsClnFlag(types.NewFieldType(mysql.TypeBlob))},
+			{ID: 4, Ft: decimalFt},
+			{ID: 5, Ft: setFlag(types.NewFieldType(mysql.TypeLonglong), mysql.UnsignedFlag)},
+			{ID: 6, Ft: types.NewFieldType(mysql.TypeDouble)},
+			{ID: 7, Ft: types.NewFieldType(mysql.TypeDatetime)},
+			{ID: 8, Ft: types.NewFieldType(mysql.TypeLong)},
+		},
+	}
+}
+
+func newTestDecoder(encoder *BatchEncoder, msg *common.Message) *decoder {
+	d := NewDecoder(
+		context.Background(), msg.Key, msg.Value,
+		encoder.keySchemaManager, encoder.valueSchemaManager,
+	)
+	return d.(*decoder)
+}
+
+func TestDecodeRowChangedEvent(t *testing.T) {
+	encoder, err := setupEncoderAndSchemaRegistry(true, "precise", "string")
+	require.NoError(t, err)
+	defer teardownEncoderAndSchemaRegistry()
+
+	ctx := context.Background()
+	insert := newDecoderTestRow()
+	err = encoder.AppendRowChangedEvent(ctx, "test", insert, nil)
+	require.NoError(t, err)
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+
+	d := newTestDecoder(encoder, msgs[0])
+	tp, hasNext, err := d.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	event, err := d.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, insert.CommitTs, event.CommitTs)
+	require.Equal(t, insert.Table, event.Table)
+	require.True(t, event.IsInsert())
+	require.Len(t, event.Columns, len(insert.Columns))
+	for i, col := range event.Columns {
+		expected := insert.Columns[i]
+		require.Equal(t, expected.Name, col.Name)
+		require.Equal(t, expected.Value, col.Value, col.Name)
+		require.Equal(t, expected.Flag.IsHandleKey(), col.Flag.IsHandleKey(), col.Name)
+		require.Equal(t, expected.Flag.IsNullable(), col.Flag.IsNullable(), col.Name)
+		require.Equal(t, expected.Flag.IsUnsigned(), col.Flag.IsUnsigned(), col.Name)
+		require.Equal(t, expected.Flag.IsBinary(), col.Flag.IsBinary(), col.Name)
+	}
+	_, hasNext, err = d.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+
+	// the delete event is a tombstone message, even if the TiDB extension
+	// is enabled.
+	del := newDecoderTestRow()
+	del.PreColumns, del.Columns = del.Columns, nil
+	err = encoder.AppendRowChangedEvent(ctx, "test", del, nil)
+	require.NoError(t, err)
+	msgs = encoder.Build()
+	require.Len(t, msgs, 1)
+	require.Nil(t, msgs[0].Value)
+
+	d = newTestDecoder(encoder, msgs[0])
+	_, hasNext, err = d.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	event, err = d.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.True(t, event.IsDelete())
+	require.Equal(t, uint64(0), event.CommitTs)
+	require.Equal(t, del.Table, event.Table)
+	require.Len(t, event.PreColumns, 1)
+	require.Equal(t, "id", event.PreColumns[0].Name)
+	require.Equal(t, int64(1), event.PreColumns[0].Value)
+	require.True(t, event.PreColumns[0].Flag.IsHandleKey())
+}
+
+func TestDecodeResolvedEvent(t *testing.T) {
+	encoder, err := setupEncoderAndSchemaRegistry(true, "precise", "long")
+	require.NoError(t, err)
+	defer teardownEncoderAndSchemaRegistry()
+
+	msg, err := encoder.EncodeCheckpointEvent(417318403368288260)
+	require.NoError(t, err)
+	require.Nil(t, msg)
+
+	encoder.enableWatermark = true
+	msg, err = encoder.EncodeCheckpointEvent(417318403368288260)
+	require.NoError(t, err)
+	require.NotNil(t, msg)
+
+	d := newTestDecoder(encoder, msg)
+	tp, hasNext, err := d.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeResolved, tp)
+	ts, err := d.NextResolvedEvent()
+	require.NoError(t, err)
+	require.Equal(t, uint64(417318403368288260), ts)
+	_, hasNext, err = d.HasNext()
+	require.NoError(t, err)
+	require.False(t, hasNext)
+
+	_, err = d.NextDDLEvent()
+	require.Error(t, err)
+
+	d = newTestDecoder(encoder, &common.Message{Value: []byte{1, 2, 3}})
+	_, _, err = d.HasNext()
+	require.ErrorContains(t, err, "unknown magic byte")
+}
@@ -31,6 +31,7 @@ import (
 type mockRegistry struct {
 	mu       sync.Mutex
 	subjects map[string]*mockRegistrySchema
+	schemas  map[int]string
 	newID    int
 }
 
@@ -45,6 +46,7 @@ func startHTTPInterceptForTestingRegistry() {
 
 	registry := mockRegistry{
 		subjects: make(map[string]*mockRegistrySchema),
+		schemas:  make(map[int]string),
 		newID:    1,
 	}
 
@@ -91,6 +93,7 @@ func startHTTPInterceptForTestingRegistry() {
 					respData.ID = registry.newID
 				}
 			}
+			registry.schemas[respData.ID] = reqData.Schema
 			registry.newID++
 			registry.mu.Unlock()
 			return httpmock.NewJsonResponse(200, &respData)
@@ -118,6 +121,23 @@ func startHTTPInterceptForTestingRegistry() {
 			return httpmock.NewJsonResponse(200, &respData)
 		})
 
+	httpmock.RegisterResponder("GET", `=~^http://127.0.0.1:8081/schemas/ids/(\d+)`,
+		func(req *http.Request) (*http.Response, error) {
+			id, err := httpmock.GetSubmatchAsInt(req, 1)
+			if err != nil {
+				return httpmock.NewStringResponse(500, "Internal Server Error"), err
+			}
+
+			registry.mu.Lock()
+			schema, exists := registry.schemas[int(id)]
+			registry.mu.Unlock()
+			if !exists {
+				return httpmock.NewStringResponse(404, ""), nil
+			}
+
+			return httpmock.NewJsonResponse(200, &lookupResponse{Schema: schema})
+		})
+
 	httpmock.RegisterResponder("DELETE", `=~^http://127.0.0.1:8081/subjects/(.+)`,
 		func(req *http.Request) (*http.Response, error) {
 			subject, err := httpmock.GetSubmatch(req, 1)
@@ -231,6 +251,47 @@ func TestSchemaRegistry(t *testing.T) {
 	require.Equal(t, codec.CanonicalSchema(), codec2.CanonicalSchema())
 }
 
+func TestSchemaRegistryLookupByID(t *testing.T) {
+	startHTTPInterceptForTestingRegistry()
+	defer stopHTTPInterceptForTestingRegistry()
+
+	manager, err := NewAvroSchemaManager(
+		getTestingContext(),
+		nil,
+		"http://127.0.0.1:8081",
+		"-value",
+	)
+	require.NoError(t, err)
+
+	_, err = manager.LookupByID(getTestingContext(), 1)
+	require.Regexp(t, `.*not\sfound.*`, err)
+
+	codec, err := goavro.NewCodec(`{
+       "type": "record",
+       "name": "test",
+       "fields":
+         [
+           {
+             "type": "string",
+             "name": "field1"
+           }
+          ]
+     }`)
+	require.NoError(t, err)
+
+	id, err := manager.Register(getTestingContext(), "cdctest", codec)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		codec2, err := manager.LookupByID(getTestingContext(), id)
+		require.NoError(t, err)
+		require.Equal(t, codec.CanonicalSchema(), codec2.CanonicalSchema())
+	}
+	// one request for the missing schema and one for the registered schema,
+	// the second lookup of the registered schema hits the local cache.
+	require.Equal(t, 2, httpmock.GetCallCountInfo()["GET =~^http://127.0.0.1:8081/schemas/ids/(\\d+)"])
+}
+
 func TestSchemaRegistryBad(t *testing.T) {
 	startHTTPInterceptForTestingRegistry()
 	defer stopHTTPInterceptForTestingRegistry()
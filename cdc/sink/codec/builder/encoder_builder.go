@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/codec/csv"
 	"github.com/pingcap/tiflow/cdc/sink/codec/maxwell"
 	"github.com/pingcap/tiflow/cdc/sink/codec/open"
+	"github.com/pingcap/tiflow/cdc/sink/codec/parquet"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -32,19 +33,21 @@ import (
 func NewEventBatchEncoderBuilder(ctx context.Context, c *common.Config) (codec.EncoderBuilder, error) {
 	switch c.Protocol {
 	case config.ProtocolDefault, config.ProtocolOpen:
-		return open.NewBatchEncoderBuilder(c), nil
+		return open.NewBatchEncoderBuilder(ctx, c)
 	case config.ProtocolCanal:
-		return canal.NewBatchEncoderBuilder(), nil
+		return canal.NewBatchEncoderBuilder(c), nil
 	case config.ProtocolAvro:
 		return avro.NewBatchEncoderBuilder(ctx, c)
 	case config.ProtocolMaxwell:
-		return maxwell.NewBatchEncoderBuilder(), nil
+		return maxwell.NewBatchEncoderBuilder(c), nil
 	case config.ProtocolCanalJSON:
-		return canal.NewJSONBatchEncoderBuilder(c), nil
+		return canal.NewJSONBatchEncoderBuilder(ctx, c)
 	case config.ProtocolCraft:
 		return craft.NewBatchEncoderBuilder(c), nil
 	case config.ProtocolCsv:
 		return csv.NewBatchEncoderBuilder(c), nil
+	case config.ProtocolParquet:
+		return parquet.NewBatchEncoderBuilder(), nil
 	default:
 		return nil, cerror.ErrSinkUnknownProtocol.GenWithStackByArgs(c.Protocol)
 	}
@@ -0,0 +1,225 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canal
+
+import (
+	"strconv"
+
+	"github.com/golang/protobuf/proto" // nolint:staticcheck
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	canal "github.com/pingcap/tiflow/proto/canal"
+)
+
+// protobufBatchDecoder decodes the canal protobuf packet into the original events.
+type protobufBatchDecoder struct {
+	entries [][]byte
+
+	// ddl, rows or resolvedTs are decoded from the next entry by HasNext.
+	ddl        *model.DDLEvent
+	rows       []*model.RowChangedEvent
+	resolvedTs uint64
+}
+
+// NewProtobufBatchDecoder returns a decoder for the canal protobuf packet.
+func NewProtobufBatchDecoder(data []byte) (codec.EventBatchDecoder, error) {
+	packet := new(canal.Packet)
+	if err := proto.Unmarshal(data, packet); err != nil {
+		return nil, cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+	}
+	if packet.GetType() != canal.PacketType_MESSAGES {
+		return nil, cerror.ErrCanalDecodeFailed.GenWithStack(
+			"unexpected packet type %s", packet.GetType())
+	}
+	messages := new(canal.Messages)
+	if err := proto.Unmarshal(packet.GetBody(), messages); err != nil {
+		return nil, cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+	}
+	return &protobufBatchDecoder{entries: messages.GetMessages()}, nil
+}
+
+// HasNext implements the EventBatchDecoder interface
+func (b *protobufBatchDecoder) HasNext() (model.MessageType, bool, error) {
+	for b.ddl == nil && len(b.rows) == 0 && b.resolvedTs == 0 {
+		if len(b.entries) == 0 {
+			return model.MessageTypeUnknown, false, nil
+		}
+		if err := b.decodeNextEntry(); err != nil {
+			return model.MessageTypeUnknown, false, err
+		}
+	}
+	if b.ddl != nil {
+		return model.MessageTypeDDL, true, nil
+	}
+	if b.resolvedTs != 0 {
+		return model.MessageTypeResolved, true, nil
+	}
+	return model.MessageTypeRow, true, nil
+}
+
+// NextRowChangedEvent implements the EventBatchDecoder interface
+// `HasNext` should be called before this.
+func (b *protobufBatchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if len(b.rows) == 0 {
+		return nil, cerror.ErrCanalDecodeFailed.
+			GenWithStack("not found row changed event message")
+	}
+	row := b.rows[0]
+	b.rows = b.rows[1:]
+	return row, nil
+}
+
+// NextDDLEvent implements the EventBatchDecoder interface
+// `HasNext` should be called before this.
+func (b *protobufBatchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
+	if b.ddl == nil {
+		return nil, cerror.ErrCanalDecodeFailed.
+			GenWithStack("not found ddl event message")
+	}
+	ddl := b.ddl
+	b.ddl = nil
+	return ddl, nil
+}
+
+// NextResolvedEvent implements the EventBatchDecoder interface,
+// the resolved events are the heartbeat entries encoded with the TiDB extension.
+func (b *protobufBatchDecoder) NextResolvedEvent() (uint64, error) {
+	if b.resolvedTs == 0 {
+		return 0, cerror.ErrCanalDecodeFailed.
+			GenWithStack("not found resolved event message")
+	}
+	ts := b.resolvedTs
+	b.resolvedTs = 0
+	return ts, nil
+}
+
+func (b *protobufBatchDecoder) decodeNextEntry() error {
+	entry := new(canal.Entry)
+	if err := proto.Unmarshal(b.entries[0], entry); err != nil {
+		return cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+	}
+	b.entries = b.entries[1:]
+
+	header := entry.GetHeader()
+	if entry.GetEntryType() == canal.EntryType_ENTRYHEARTBEAT {
+		ts, ok, err := getTiDBTsProp(header, tidbWatermarkTsKey)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			return cerror.ErrCanalDecodeFailed.GenWithStack(
+				"the heartbeat entry does not carry the %s property", tidbWatermarkTsKey)
+		}
+		b.resolvedTs = ts
+		return nil
+	}
+
+	rc := new(canal.RowChange)
+	if err := proto.Unmarshal(entry.GetStoreValue(), rc); err != nil {
+		return cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+	}
+
+	commitTs, ok, err := getTiDBTsProp(header, tidbCommitTsKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		// the canal entry only keeps the physical part of the commit ts
+		// if the TiDB extension is disabled.
+		commitTs = convertFromCanalTs(header.GetExecuteTime())
+	}
+	if rc.GetIsDdl() {
+		ddl := new(model.DDLEvent)
+		ddl.CommitTs = commitTs
+		ddl.TableInfo = new(model.TableInfo)
+		ddl.TableInfo.TableName = model.TableName{
+			Schema: header.GetSchemaName(),
+			Table:  header.GetTableName(),
+		}
+		ddl.Query = rc.GetSql()
+		// hack the DDL Type to be compatible with MySQL sink's logic,
+		// the same as the canal-json decoder.
+		ddl.Type = getDDLActionType(ddl.Query)
+		b.ddl = ddl
+		return nil
+	}
+
+	for _, rowData := range rc.GetRowDatas() {
+		row := new(model.RowChangedEvent)
+		row.CommitTs = commitTs
+		row.Table = &model.TableName{
+			Schema: header.GetSchemaName(),
+			Table:  header.GetTableName(),
+		}
+		keys := make(map[string]struct{})
+		row.PreColumns = canalColumns2RowChangeColumns(rowData.GetBeforeColumns(), keys)
+		if rc.GetEventType() != canal.EventType_DELETE {
+			row.Columns = canalColumns2RowChangeColumns(rowData.GetAfterColumns(), keys)
+		}
+		// the canal protobuf encoder marks the primary key columns only.
+		row.WithHandlePrimaryFlag(keys)
+		b.rows = append(b.rows, row)
+	}
+	return nil
+}
+
+// getTiDBTsProp returns the ts kept in the header property of the TiDB extension.
+func getTiDBTsProp(header *canal.Header, key string) (uint64, bool, error) {
+	for _, p := range header.GetProps() {
+		if p.GetKey() != key {
+			continue
+		}
+		ts, err := strconv.ParseUint(p.GetValue(), 10, 64)
+		if err != nil {
+			return 0, false, cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+		}
+		return ts, true, nil
+	}
+	return 0, false, nil
+}
+
+// canalColumns2RowChangeColumns converts canal columns to row changed columns,
+// the names of the key columns are collected into keys.
+func canalColumns2RowChangeColumns(
+	columns []*canal.Column, keys map[string]struct{},
+) []*model.Column {
+	if len(columns) == 0 {
+		return nil
+	}
+	result := make([]*model.Column, 0, len(columns))
+	for _, c := range columns {
+		var value interface{}
+		if !c.GetIsNull() {
+			value = c.GetValue()
+		}
+		mysqlType := types.StrToType(trimUnsignedFromMySQLType(c.GetMysqlType()))
+		col := internal.NewColumn(value, mysqlType).
+			ToCanalJSONFormatColumn(c.GetName(), internal.JavaSQLType(c.GetSqlType()))
+		if c.GetIsKey() {
+			keys[c.GetName()] = struct{}{}
+		}
+		result = append(result, col)
+	}
+	return result
+}
+
+// convert timestamp(in ms) in canal to ts in tidb, the logical part is lost,
+// so different transactions committed in the same millisecond share the ts.
+func convertFromCanalTs(ts int64) uint64 {
+	return uint64(ts) << 18
+}
@@ -0,0 +1,166 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanalBatchDecoder4RowMessage(t *testing.T) {
+	t.Parallel()
+	expectedDecodedValue := collectExpectedDecodedValue(testColumnsTable)
+
+	encoder := newBatchEncoder(common.NewConfig(config.ProtocolCanal))
+	for _, row := range []*model.RowChangedEvent{testCaseInsert, testCaseUpdate, testCaseDelete} {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+		require.Nil(t, err)
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+
+	decoder, err := NewProtobufBatchDecoder(messages[0].Value)
+	require.Nil(t, err)
+
+	checkColumns := func(cols []*model.Column) {
+		require.Len(t, cols, len(testColumns))
+		for _, col := range cols {
+			expected, ok := expectedDecodedValue[col.Name]
+			require.True(t, ok)
+			require.Equal(t, expected, col.Value, col.Name)
+		}
+	}
+
+	for _, expected := range []*model.RowChangedEvent{testCaseInsert, testCaseUpdate, testCaseDelete} {
+		ty, hasNext, err := decoder.HasNext()
+		require.Nil(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, ty)
+
+		consumed, err := decoder.NextRowChangedEvent()
+		require.Nil(t, err)
+		require.Equal(t, expected.Table, consumed.Table)
+		// only the physical part of the commit ts is kept.
+		require.Equal(t, expected.CommitTs>>18, consumed.CommitTs>>18)
+		require.Equal(t, expected.IsInsert(), consumed.IsInsert())
+		require.Equal(t, expected.IsUpdate(), consumed.IsUpdate())
+		require.Equal(t, expected.IsDelete(), consumed.IsDelete())
+		if !expected.IsDelete() {
+			checkColumns(consumed.Columns)
+		}
+		if !expected.IsInsert() {
+			checkColumns(consumed.PreColumns)
+		}
+	}
+
+	_, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+
+	consumed, err := decoder.NextRowChangedEvent()
+	require.NotNil(t, err)
+	require.Nil(t, consumed)
+}
+
+func TestCanalBatchDecoder4DDLMessage(t *testing.T) {
+	t.Parallel()
+	encoder := newBatchEncoder(common.NewConfig(config.ProtocolCanal))
+	msg, err := encoder.EncodeDDLEvent(testCaseDDL)
+	require.Nil(t, err)
+	require.NotNil(t, msg)
+
+	decoder, err := NewProtobufBatchDecoder(msg.Value)
+	require.Nil(t, err)
+
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, ty)
+
+	consumed, err := decoder.NextDDLEvent()
+	require.Nil(t, err)
+	require.Equal(t, testCaseDDL.CommitTs>>18, consumed.CommitTs>>18)
+	require.Equal(t, testCaseDDL.TableInfo, consumed.TableInfo)
+	require.Equal(t, testCaseDDL.Query, consumed.Query)
+
+	ty, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+	require.Equal(t, model.MessageTypeUnknown, ty)
+
+	consumed, err = decoder.NextDDLEvent()
+	require.NotNil(t, err)
+	require.Nil(t, consumed)
+
+	_, err = NewProtobufBatchDecoder([]byte("invalid"))
+	require.NotNil(t, err)
+}
+
+func TestCanalBatchDecoderWithTiDBExtension(t *testing.T) {
+	t.Parallel()
+	codecConfig := common.NewConfig(config.ProtocolCanal)
+	codecConfig.EnableTiDBExtension = true
+	encoder := newBatchEncoder(codecConfig)
+
+	err := encoder.AppendRowChangedEvent(context.Background(), "", testCaseInsert, nil)
+	require.Nil(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	decoder, err := NewProtobufBatchDecoder(messages[0].Value)
+	require.Nil(t, err)
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, ty)
+	row, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	// the full commit ts is kept by the TiDB extension.
+	require.Equal(t, testCaseInsert.CommitTs, row.CommitTs)
+
+	msg, err := encoder.EncodeDDLEvent(testCaseDDL)
+	require.Nil(t, err)
+	decoder, err = NewProtobufBatchDecoder(msg.Value)
+	require.Nil(t, err)
+	ty, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, ty)
+	ddl, err := decoder.NextDDLEvent()
+	require.Nil(t, err)
+	require.Equal(t, testCaseDDL.CommitTs, ddl.CommitTs)
+
+	msg, err = encoder.EncodeCheckpointEvent(417318403368288260)
+	require.Nil(t, err)
+	require.Equal(t, model.MessageTypeResolved, msg.Type)
+	decoder, err = NewProtobufBatchDecoder(msg.Value)
+	require.Nil(t, err)
+	ty, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeResolved, ty)
+	ts, err := decoder.NextResolvedEvent()
+	require.Nil(t, err)
+	require.Equal(t, uint64(417318403368288260), ts)
+
+	_, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+	_, err = decoder.NextResolvedEvent()
+	require.NotNil(t, err)
+}
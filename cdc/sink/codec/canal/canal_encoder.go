@@ -15,6 +15,7 @@ package canal
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pingcap/errors"
@@ -34,13 +35,25 @@ type BatchEncoder struct {
 	callbackBuf  []func()
 	packet       *canal.Packet
 	entryBuilder *canalEntryBuilder
+
+	// When it is true, the entries carry the full commit ts in the header
+	// properties, and the checkpoint events are encoded as heartbeat entries.
+	enableTiDBExtension bool
 }
 
 // EncodeCheckpointEvent implements the EventBatchEncoder interface
 func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
 	// For canal now, there is no such a corresponding type to ResolvedEvent so far.
-	// Therefore, the event is ignored.
-	return nil, nil
+	// Therefore, the event is ignored unless the TiDB extension is enabled.
+	if !d.enableTiDBExtension {
+		return nil, nil
+	}
+
+	value, err := newPacketValue(d.entryBuilder.fromCheckpointEvent(ts))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewResolvedMsg(config.ProtocolCanal, nil, value, ts), nil
 }
 
 // AppendRowChangedEvent implements the EventBatchEncoder interface
@@ -54,6 +67,7 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	d.appendTiDBCommitTs(entry, e.CommitTs)
 	b, err := proto.Marshal(entry)
 	if err != nil {
 		return cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
@@ -71,6 +85,29 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	d.appendTiDBCommitTs(entry, e.CommitTs)
+
+	b, err := newPacketValue(entry)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewDDLMsg(config.ProtocolCanal, nil, b, e), nil
+}
+
+// appendTiDBCommitTs keeps the full commit ts in the entry header if the TiDB
+// extension is enabled, since the execute time only keeps the physical part.
+func (d *BatchEncoder) appendTiDBCommitTs(entry *canal.Entry, commitTs uint64) {
+	if !d.enableTiDBExtension {
+		return
+	}
+	entry.Header.Props = append(entry.Header.Props, &canal.Pair{
+		Key:   tidbCommitTsKey,
+		Value: strconv.FormatUint(commitTs, 10),
+	})
+}
+
+// newPacketValue marshals a packet containing only the given entry.
+func newPacketValue(entry *canal.Entry) ([]byte, error) {
 	b, err := proto.Marshal(entry)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
@@ -94,8 +131,7 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
-
-	return common.NewDDLMsg(config.ProtocolCanal, nil, b, e), nil
+	return b, nil
 }
 
 // Build implements the EventBatchEncoder interface
@@ -156,25 +192,28 @@ func (d *BatchEncoder) resetPacket() {
 }
 
 // newBatchEncoder creates a new canalBatchEncoder.
-func newBatchEncoder() codec.EventBatchEncoder {
+func newBatchEncoder(config *common.Config) codec.EventBatchEncoder {
 	encoder := &BatchEncoder{
-		messages:     &canal.Messages{},
-		callbackBuf:  make([]func(), 0),
-		entryBuilder: newCanalEntryBuilder(),
+		messages:            &canal.Messages{},
+		callbackBuf:         make([]func(), 0),
+		entryBuilder:        newCanalEntryBuilder(),
+		enableTiDBExtension: config.EnableTiDBExtension,
 	}
 
 	encoder.resetPacket()
 	return encoder
 }
 
-type batchEncoderBuilder struct{}
+type batchEncoderBuilder struct {
+	config *common.Config
+}
 
 // Build a `canalBatchEncoder`
 func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
-	return newBatchEncoder()
+	return newBatchEncoder(b.config)
 }
 
 // NewBatchEncoderBuilder creates a canal batchEncoderBuilder.
-func NewBatchEncoderBuilder() codec.EncoderBuilder {
-	return &batchEncoderBuilder{}
+func NewBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
+	return &batchEncoderBuilder{config: config}
 }
@@ -20,6 +20,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
 	canal "github.com/pingcap/tiflow/proto/canal"
 	"github.com/stretchr/testify/require"
 )
@@ -28,7 +30,7 @@ func TestCanalBatchEncoder(t *testing.T) {
 	t.Parallel()
 	s := defaultCanalBatchTester
 	for _, cs := range s.rowCases {
-		encoder := newBatchEncoder()
+		encoder := newBatchEncoder(common.NewConfig(config.ProtocolCanal))
 		for _, row := range cs {
 			err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
 			require.Nil(t, err)
@@ -55,7 +57,7 @@ func TestCanalBatchEncoder(t *testing.T) {
 	}
 
 	for _, cs := range s.ddlCases {
-		encoder := newBatchEncoder()
+		encoder := newBatchEncoder(common.NewConfig(config.ProtocolCanal))
 		for _, ddl := range cs {
 			msg, err := encoder.EncodeDDLEvent(ddl)
 			require.Nil(t, err)
@@ -76,7 +78,7 @@ func TestCanalBatchEncoder(t *testing.T) {
 }
 
 func TestCanalAppendRowChangedEventWithCallback(t *testing.T) {
-	encoder := newBatchEncoder()
+	encoder := newBatchEncoder(common.NewConfig(config.ProtocolCanal))
 	require.NotNil(t, encoder)
 
 	count := 0
@@ -143,3 +145,20 @@ func TestCanalAppendRowChangedEventWithCallback(t *testing.T) {
 	msgs[0].Callback()
 	require.Equal(t, 15, count, "expected all callbacks to be called")
 }
+
+func TestCanalBatchEncoderCheckpointEvent(t *testing.T) {
+	t.Parallel()
+	encoder := newBatchEncoder(common.NewConfig(config.ProtocolCanal))
+	msg, err := encoder.EncodeCheckpointEvent(1)
+	require.Nil(t, err)
+	require.Nil(t, msg)
+
+	codecConfig := common.NewConfig(config.ProtocolCanal)
+	codecConfig.EnableTiDBExtension = true
+	encoder = newBatchEncoder(codecConfig)
+	msg, err = encoder.EncodeCheckpointEvent(1)
+	require.Nil(t, err)
+	require.NotNil(t, msg)
+	require.Equal(t, model.MessageTypeResolved, msg.Type)
+	require.Equal(t, uint64(1), msg.Ts)
+}
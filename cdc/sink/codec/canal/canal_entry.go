@@ -41,6 +41,15 @@ const (
 	CanalServerEncode    string = "UTF-8"
 )
 
+// The header properties of the TiDB extension, they are only set when
+// `enable-tidb-extension` is true, so that the full TiDB ts can be recovered.
+const (
+	// tidbCommitTsKey carries the commit ts of the row changed and DDL entries.
+	tidbCommitTsKey = "tidbCommitTs"
+	// tidbWatermarkTsKey carries the checkpoint ts of the heartbeat entries.
+	tidbWatermarkTsKey = "tidbWatermarkTs"
+)
+
 type canalEntryBuilder struct {
 	bytesDecoder *encoding.Decoder // default charset is ISO-8859-1
 }
@@ -228,6 +237,25 @@ func (b *canalEntryBuilder) fromDDLEvent(e *model.DDLEvent) (*canal.Entry, error
 	return entry, nil
 }
 
+// fromCheckpointEvent builds a heartbeat canal entry carrying the checkpoint ts,
+// the entry is ignored by the canal clients.
+func (b *canalEntryBuilder) fromCheckpointEvent(ts uint64) *canal.Entry {
+	header := &canal.Header{
+		VersionPresent:    &canal.Header_Version{Version: CanalProtocolVersion},
+		ServerenCode:      CanalServerEncode,
+		ExecuteTime:       convertToCanalTs(ts),
+		SourceTypePresent: &canal.Header_SourceType{SourceType: canal.Type_MYSQL},
+		Props: []*canal.Pair{{
+			Key:   tidbWatermarkTsKey,
+			Value: strconv.FormatUint(ts, 10),
+		}},
+	}
+	return &canal.Entry{
+		Header:           header,
+		EntryTypePresent: &canal.Entry_EntryType{EntryType: canal.EntryType_ENTRYHEARTBEAT},
+	}
+}
+
 // convert ts in tidb to timestamp(in ms) in canal
 func convertToCanalTs(commitTs uint64) int64 {
 	return int64(commitTs >> 18)
@@ -15,11 +15,14 @@ package canal
 
 import (
 	"bytes"
+	"context"
 
 	"github.com/goccy/go-json"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/claimcheck"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -30,18 +33,36 @@ type batchDecoder struct {
 	msg                 canalJSONMessageInterface
 	enableTiDBExtension bool
 	terminator          string
+
+	ctx        context.Context
+	claimCheck *claimcheck.ClaimCheck
 }
 
 // NewBatchDecoder return a decoder for canal-json
 func NewBatchDecoder(data []byte,
 	enableTiDBExtension bool,
 	terminator string,
+) codec.EventBatchDecoder {
+	return NewBatchDecoderWithClaimCheck(context.Background(),
+		data, enableTiDBExtension, terminator, nil)
+}
+
+// NewBatchDecoderWithClaimCheck return a decoder for canal-json, which reads
+// the rows of the large messages from the claim-check storage.
+func NewBatchDecoderWithClaimCheck(
+	ctx context.Context,
+	data []byte,
+	enableTiDBExtension bool,
+	terminator string,
+	claimCheck *claimcheck.ClaimCheck,
 ) codec.EventBatchDecoder {
 	return &batchDecoder{
 		data:                data,
 		msg:                 nil,
 		enableTiDBExtension: enableTiDBExtension,
 		terminator:          terminator,
+		ctx:                 ctx,
+		claimCheck:          claimCheck,
 	}
 }
 
@@ -93,7 +114,11 @@ func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 		return nil, cerror.ErrCanalDecodeFailed.
 			GenWithStack("not found row changed event message")
 	}
-	result, err := canalJSONMessage2RowChange(b.msg)
+	msg, err := b.resolveClaimCheck(b.msg)
+	if err != nil {
+		return nil, err
+	}
+	result, err := canalJSONMessage2RowChange(msg)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +126,34 @@ func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 	return result, nil
 }
 
+// resolveClaimCheck returns the full message of the row if the message refers
+// to the row in the claim-check storage, otherwise the message itself.
+func (b *batchDecoder) resolveClaimCheck(
+	msg canalJSONMessageInterface,
+) (canalJSONMessageInterface, error) {
+	withExtension, ok := msg.(*canalJSONMessageWithTiDBExtension)
+	if !ok || withExtension.Extensions.ClaimCheckLocation == "" {
+		return msg, nil
+	}
+	location := withExtension.Extensions.ClaimCheckLocation
+	if b.claimCheck == nil {
+		return nil, cerror.ErrCanalDecodeFailed.GenWithStack(
+			"claim-check storage is not configured to read %s", location)
+	}
+	claimCheckMsg, err := b.claimCheck.ReadMessage(b.ctx, location)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fullMsg := &canalJSONMessageWithTiDBExtension{
+		JSONMessage: &JSONMessage{},
+		Extensions:  &tidbExtension{},
+	}
+	if err := json.Unmarshal(claimCheckMsg.Value, fullMsg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrCanalDecodeFailed, err)
+	}
+	return fullMsg, nil
+}
+
 // NextDDLEvent implements the EventBatchDecoder interface
 // `HasNext` should be called before this.
 func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
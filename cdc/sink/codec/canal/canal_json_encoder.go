@@ -21,8 +21,10 @@ import (
 	"github.com/mailru/easyjson/jwriter"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/claimcheck"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -37,8 +39,14 @@ type JSONBatchEncoder struct {
 	// which, at the moment, only includes `tidbWaterMarkType` and `_tidb` fields.
 	enableTiDBExtension bool
 	// the symbol separating two lines
-	terminator []byte
-	messages   []*common.Message
+	terminator   []byte
+	keyGenerator *common.KeyGenerator
+	messages     []*common.Message
+
+	maxMessageBytes int
+	// claimCheck stores the rows whose messages are larger than
+	// maxMessageBytes, it's nil if the claim-check mode is disabled.
+	claimCheck *claimcheck.ClaimCheck
 }
 
 // newJSONBatchEncoder creates a new JSONBatchEncoder
@@ -48,11 +56,18 @@ func newJSONBatchEncoder(config *common.Config) codec.EventBatchEncoder {
 		enableTiDBExtension: config.EnableTiDBExtension,
 		messages:            make([]*common.Message, 0, 1),
 		terminator:          []byte(config.Terminator),
+		keyGenerator:        config.KeyGenerator,
+		maxMessageBytes:     config.MaxMessageBytes,
 	}
 	return encoder
 }
 
-func (c *JSONBatchEncoder) newJSONMessageForDML(e *model.RowChangedEvent) ([]byte, error) {
+// newJSONMessageForDML encodes the row changed event, claimCheckLocation is
+// the file of the row in the claim-check storage if the message only refers
+// to it, and it's only encoded with the TiDB extension.
+func (c *JSONBatchEncoder) newJSONMessageForDML(
+	e *model.RowChangedEvent, claimCheckLocation string,
+) ([]byte, error) {
 	isDelete := e.IsDelete()
 	mysqlTypeMap := make(map[string]string, len(e.Columns))
 
@@ -239,6 +254,10 @@ func (c *JSONBatchEncoder) newJSONMessageForDML(e *model.RowChangedEvent) ([]byt
 		out.RawByte('{')
 		out.RawString("\"commitTs\":")
 		out.Uint64(e.CommitTs)
+		if claimCheckLocation != "" {
+			out.RawString(",\"claimCheckLocation\":")
+			out.String(claimCheckLocation)
+		}
 		out.RawByte('}')
 	}
 	out.RawByte('}')
@@ -307,20 +326,39 @@ func (c *JSONBatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, er
 
 // AppendRowChangedEvent implements the interface EventJSONBatchEncoder
 func (c *JSONBatchEncoder) AppendRowChangedEvent(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	value, err := c.newJSONMessageForDML(e)
+	value, err := c.newJSONMessageForDML(e, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key, _, err := c.keyGenerator.Generate(e)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	length := len(key) + len(value) + len(c.terminator) + common.MaxRecordOverhead
+	if length > c.maxMessageBytes && c.claimCheck != nil {
+		value, err = c.newClaimCheckMessage(ctx, e, key, value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		length = len(key) + len(value) + len(c.terminator) + common.MaxRecordOverhead
+		if length > c.maxMessageBytes {
+			log.Warn("Single message too large",
+				zap.Int("max-message-size", c.maxMessageBytes),
+				zap.Int("length", length), zap.Any("table", e.Table))
+			return cerror.ErrCanalEncodeFailed.GenWithStack(
+				"the message of the handle key columns is larger than max-message-bytes")
+		}
+	}
 	if len(c.terminator) > 0 {
 		value = append(value, c.terminator...)
 	}
 	m := &common.Message{
-		Key:      nil,
+		Key:      key,
 		Value:    value,
 		Ts:       e.CommitTs,
 		Schema:   &e.Table.Schema,
@@ -335,6 +373,45 @@ func (c *JSONBatchEncoder) AppendRowChangedEvent(
 	return nil
 }
 
+// newClaimCheckMessage writes the encoded row into the claim-check storage,
+// and returns the message which only carries the handle key columns of the
+// row and the file of it.
+func (c *JSONBatchEncoder) newClaimCheckMessage(
+	ctx context.Context, e *model.RowChangedEvent, key, value []byte,
+) ([]byte, error) {
+	fileName, err := c.claimCheck.WriteMessage(ctx, key, value, e.CommitTs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	log.Info("large message is written to the claim-check storage",
+		zap.Int("length", len(key)+len(value)), zap.Any("table", e.Table),
+		zap.String("file", fileName))
+
+	handleKeyOnly := *e
+	handleKeyOnly.Columns = handleKeyColumns(e.Columns)
+	handleKeyOnly.PreColumns = handleKeyColumns(e.PreColumns)
+	// the consumer can't tell the type of the row without the columns.
+	if len(handleKeyOnly.Columns) != len(e.Columns) && len(handleKeyOnly.Columns) == 0 ||
+		len(handleKeyOnly.PreColumns) != len(e.PreColumns) && len(handleKeyOnly.PreColumns) == 0 {
+		return nil, cerror.ErrCanalEncodeFailed.GenWithStack(
+			"claim-check requires the handle key columns of table %s", e.Table)
+	}
+	return c.newJSONMessageForDML(&handleKeyOnly, fileName)
+}
+
+func handleKeyColumns(cols []*model.Column) []*model.Column {
+	if len(cols) == 0 {
+		return nil
+	}
+	result := make([]*model.Column, 0, len(cols))
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
 // Build implements the EventBatchEncoder interface
 func (c *JSONBatchEncoder) Build() []*common.Message {
 	if len(c.messages) == 0 {
@@ -357,15 +434,29 @@ func (c *JSONBatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, e
 }
 
 type jsonBatchEncoderBuilder struct {
-	config *common.Config
+	config     *common.Config
+	claimCheck *claimcheck.ClaimCheck
 }
 
 // NewJSONBatchEncoderBuilder creates a canal-json batchEncoderBuilder.
-func NewJSONBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
-	return &jsonBatchEncoderBuilder{config: config}
+func NewJSONBatchEncoderBuilder(
+	ctx context.Context, config *common.Config,
+) (codec.EncoderBuilder, error) {
+	builder := &jsonBatchEncoderBuilder{config: config}
+	if config.LargeMessageHandle.EnableClaimCheck() {
+		claimCheck, err := claimcheck.New(ctx,
+			config.LargeMessageHandle.ClaimCheckStorageURI, contextutil.ChangefeedIDFromCtx(ctx))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		builder.claimCheck = claimCheck
+	}
+	return builder, nil
 }
 
 // Build a `JSONBatchEncoder`
 func (b *jsonBatchEncoderBuilder) Build() codec.EventBatchEncoder {
-	return newJSONBatchEncoder(b.config)
+	encoder := newJSONBatchEncoder(b.config)
+	encoder.(*JSONBatchEncoder).claimCheck = b.claimCheck
+	return encoder
 }
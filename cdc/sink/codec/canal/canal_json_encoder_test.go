@@ -16,12 +16,15 @@ package canal
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/claimcheck"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/encoding/charmap"
 )
@@ -53,7 +56,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 	encoder, ok := e.(*JSONBatchEncoder)
 	require.True(t, ok)
 
-	data, err := encoder.newJSONMessageForDML(testCaseInsert)
+	data, err := encoder.newJSONMessageForDML(testCaseInsert, "")
 	require.Nil(t, err)
 	var msg canalJSONMessageInterface = &JSONMessage{}
 	err = json.Unmarshal(data, msg)
@@ -96,7 +99,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 		require.Equal(t, item.expectedEncodedValue, obtainedValue)
 	}
 
-	data, err = encoder.newJSONMessageForDML(testCaseUpdate)
+	data, err = encoder.newJSONMessageForDML(testCaseUpdate, "")
 	require.Nil(t, err)
 	jsonMsg = &JSONMessage{}
 	err = json.Unmarshal(data, jsonMsg)
@@ -105,7 +108,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 	require.NotNil(t, jsonMsg.Old)
 	require.Equal(t, "UPDATE", jsonMsg.EventType)
 
-	data, err = encoder.newJSONMessageForDML(testCaseDelete)
+	data, err = encoder.newJSONMessageForDML(testCaseDelete, "")
 	require.Nil(t, err)
 	jsonMsg = &JSONMessage{}
 	err = json.Unmarshal(data, jsonMsg)
@@ -122,7 +125,7 @@ func TestNewCanalJSONMessage4DML(t *testing.T) {
 
 	encoder, ok = e.(*JSONBatchEncoder)
 	require.True(t, ok)
-	data, err = encoder.newJSONMessageForDML(testCaseUpdate)
+	data, err = encoder.newJSONMessageForDML(testCaseUpdate, "")
 	require.Nil(t, err)
 
 	withExtension := &canalJSONMessageWithTiDBExtension{}
@@ -400,3 +403,63 @@ func TestCanalJSONAppendRowChangedEventWithCallback(t *testing.T) {
 	msgs[4].Callback()
 	require.Equal(t, 15, count, "expected one callback be called")
 }
+
+func TestCanalJSONClaimCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storageURI := "file://" + t.TempDir()
+	cfg := common.NewConfig(config.ProtocolCanalJSON).WithMaxMessageBytes(1024)
+	cfg.EnableTiDBExtension = true
+	cfg.LargeMessageHandle = &config.LargeMessageHandleConfig{
+		LargeMessageHandleOption: config.LargeMessageHandleOptionClaimCheck,
+		ClaimCheckStorageURI:     storageURI,
+	}
+	builder, err := NewJSONBatchEncoderBuilder(ctx, cfg)
+	require.Nil(t, err)
+	encoder := builder.Build()
+
+	largeRow := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: int64(1)},
+			{Name: "data", Type: mysql.TypeVarchar, Value: []byte(strings.Repeat("x", 1024))},
+		},
+	}
+	err = encoder.AppendRowChangedEvent(ctx, "", largeRow, nil)
+	require.Nil(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.LessOrEqual(t, messages[0].Length(), 1024)
+
+	decoder := NewBatchDecoder(messages[0].Value, true, "")
+	tp, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	_, err = decoder.NextRowChangedEvent()
+	require.Regexp(t, ".*claim-check storage is not configured.*", err)
+
+	claimCheck, err := claimcheck.New(ctx, storageURI, model.ChangeFeedID{})
+	require.Nil(t, err)
+	decoder = NewBatchDecoderWithClaimCheck(ctx, messages[0].Value, true, "", claimCheck)
+	_, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	row, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Equal(t, largeRow.CommitTs, row.CommitTs)
+	require.Equal(t, largeRow.Table, row.Table)
+	require.Len(t, row.Columns, 2)
+	for _, col := range row.Columns {
+		if col.Name == "data" {
+			require.Equal(t, strings.Repeat("x", 1024), col.Value)
+		}
+	}
+
+	// the row is still too large if its handle key columns are too large.
+	largeRow.Columns[0].Value = strings.Repeat("1", 1024)
+	err = encoder.AppendRowChangedEvent(ctx, "", largeRow, nil)
+	require.True(t, cerror.ErrCanalEncodeFailed.Equal(err))
+}
@@ -133,6 +133,9 @@ func (c *JSONMessage) pkNameSet() map[string]struct{} {
 type tidbExtension struct {
 	CommitTs    uint64 `json:"commitTs,omitempty"`
 	WatermarkTs uint64 `json:"watermarkTs,omitempty"`
+	// ClaimCheckLocation is the file of the row in the claim-check storage,
+	// the message only carries the handle key columns of the row if it's set.
+	ClaimCheckLocation string `json:"claimCheckLocation,omitempty"`
 }
 
 type canalJSONMessageWithTiDBExtension struct {
@@ -0,0 +1,96 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package claimcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/uuid"
+	"go.uber.org/zap"
+)
+
+// ClaimCheck stores the messages which are larger than max-message-bytes in
+// an external storage, so that the messages sent to the MQ system only carry
+// the references to them. It's used by both the encoders and the decoders.
+type ClaimCheck struct {
+	changefeedID model.ChangeFeedID
+	storage      storage.ExternalStorage
+	uuid         uuid.Generator
+}
+
+// Message is a large message stored in the claim-check storage.
+type Message struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// New creates a ClaimCheck instance on the storage.
+func New(ctx context.Context, storageURI string, changefeedID model.ChangeFeedID) (*ClaimCheck, error) {
+	bs, err := storage.ParseBackend(storageURI, nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrClaimCheckInitFailed, err, storageURI)
+	}
+	extStorage, err := storage.New(ctx, bs, &storage.ExternalStorageOptions{
+		SendCredentials: false,
+		S3Retryer:       common.DefaultS3Retryer(),
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrClaimCheckInitFailed, err, storageURI)
+	}
+
+	log.Info("claim-check storage created",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("storageURI", extStorage.URI()))
+	return &ClaimCheck{
+		changefeedID: changefeedID,
+		storage:      extStorage,
+		uuid:         uuid.NewGenerator(),
+	}, nil
+}
+
+// WriteMessage writes the message into the storage, and returns the file
+// name of it, which is relative to the storage URI.
+func (c *ClaimCheck) WriteMessage(ctx context.Context, key, value []byte, commitTs uint64) (string, error) {
+	data, err := json.Marshal(&Message{Key: key, Value: value})
+	if err != nil {
+		return "", cerror.WrapError(cerror.ErrClaimCheckWriteFailed, err)
+	}
+	fileName := fmt.Sprintf("%s/%s/%d_%s.json",
+		c.changefeedID.Namespace, c.changefeedID.ID, commitTs, c.uuid.NewString())
+	if err := c.storage.WriteFile(ctx, fileName, data); err != nil {
+		return "", cerror.WrapError(cerror.ErrClaimCheckWriteFailed, err)
+	}
+	return fileName, nil
+}
+
+// ReadMessage reads the message of the file name from the storage.
+func (c *ClaimCheck) ReadMessage(ctx context.Context, fileName string) (*Message, error) {
+	data, err := c.storage.ReadFile(ctx, fileName)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrClaimCheckReadFailed, err, fileName)
+	}
+	msg := new(Message)
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrClaimCheckReadFailed, err, fileName)
+	}
+	return msg, nil
+}
@@ -33,13 +33,26 @@ type Config struct {
 	MaxMessageBytes int
 	MaxBatchSize    int
 
-	// canal-json only
+	// canal-json, canal, maxwell and avro only
 	EnableTiDBExtension bool
 
 	// avro only
 	AvroSchemaRegistry             string
 	AvroDecimalHandlingMode        string
 	AvroBigintUnsignedHandlingMode string
+	// AvroEnableWatermark makes the avro encoder emit watermark events,
+	// it only takes effect when EnableTiDBExtension is true.
+	AvroEnableWatermark bool
+
+	// KeyGenerator generates the message keys in the key formats configured
+	// by the dispatch rules, it's nil if all of them use the default format.
+	// It's only used by the open, canal-json and maxwell protocols.
+	KeyGenerator *KeyGenerator
+
+	// LargeMessageHandle is how to handle the messages larger than
+	// MaxMessageBytes, the claim-check mode is only supported by
+	// the open and canal-json protocols.
+	LargeMessageHandle *config.LargeMessageHandleConfig
 
 	// for sinking to cloud storage
 	Delimiter       string
@@ -61,6 +74,7 @@ func NewConfig(protocol config.Protocol) *Config {
 		AvroSchemaRegistry:             "",
 		AvroDecimalHandlingMode:        "precise",
 		AvroBigintUnsignedHandlingMode: "long",
+		AvroEnableWatermark:            false,
 	}
 }
 
@@ -71,6 +85,7 @@ const (
 	codecOPTAvroDecimalHandlingMode        = "avro-decimal-handling-mode"
 	codecOPTAvroBigintUnsignedHandlingMode = "avro-bigint-unsigned-handling-mode"
 	codecOPTAvroSchemaRegistry             = "schema-registry"
+	codecOPTAvroEnableWatermark            = "avro-enable-watermark"
 )
 
 const (
@@ -119,11 +134,26 @@ func (c *Config) Apply(sinkURI *url.URL, config *config.ReplicaConfig) error {
 		c.AvroBigintUnsignedHandlingMode = s
 	}
 
+	if s := params.Get(codecOPTAvroEnableWatermark); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		c.AvroEnableWatermark = b
+	}
+
 	if config.Sink != nil && config.Sink.SchemaRegistry != "" {
 		c.AvroSchemaRegistry = config.Sink.SchemaRegistry
 	}
 
 	if config.Sink != nil {
+		keyGenerator, err := NewKeyGenerator(config.Sink.DispatchRules, config.CaseSensitive)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.KeyGenerator = keyGenerator
+		c.LargeMessageHandle = config.Sink.LargeMessageHandle
+
 		c.Terminator = config.Sink.Terminator
 		if config.Sink.CSVConfig != nil {
 			c.Delimiter = config.Sink.CSVConfig.Delimiter
@@ -145,9 +175,10 @@ func (c *Config) WithMaxMessageBytes(bytes int) *Config {
 // Validate the Config
 func (c *Config) Validate() error {
 	if c.EnableTiDBExtension &&
-		!(c.Protocol == config.ProtocolCanalJSON || c.Protocol == config.ProtocolAvro) {
+		!(c.Protocol == config.ProtocolCanalJSON || c.Protocol == config.ProtocolAvro ||
+			c.Protocol == config.ProtocolCanal || c.Protocol == config.ProtocolMaxwell) {
 		return cerror.ErrCodecInvalidConfig.GenWithStack(
-			`enable-tidb-extension only supports canal-json/avro protocol`,
+			`enable-tidb-extension only supports canal-json/canal/maxwell/avro protocol`,
 		)
 	}
 
@@ -178,6 +209,32 @@ func (c *Config) Validate() error {
 				BigintUnsignedHandlingModeString,
 			)
 		}
+
+		if c.AvroEnableWatermark && !c.EnableTiDBExtension {
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`%s requires parameter "%s" to be true`,
+				codecOPTAvroEnableWatermark,
+				codecOPTEnableTiDBExtension,
+			)
+		}
+	}
+
+	if c.LargeMessageHandle.EnableClaimCheck() {
+		switch c.Protocol {
+		case config.ProtocolOpen, config.ProtocolDefault:
+		case config.ProtocolCanalJSON:
+			// the claim-check location is carried by the TiDB extension.
+			if !c.EnableTiDBExtension {
+				return cerror.ErrCodecInvalidConfig.GenWithStack(
+					`claim-check requires parameter "%s" to be true for canal-json protocol`,
+					codecOPTEnableTiDBExtension,
+				)
+			}
+		default:
+			return cerror.ErrCodecInvalidConfig.GenWithStack(
+				`claim-check only supports open and canal-json protocols`,
+			)
+		}
 	}
 
 	if c.MaxMessageBytes <= 0 {
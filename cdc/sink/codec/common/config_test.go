@@ -80,7 +80,7 @@ func TestConfigApplyValidate(t *testing.T) {
 	require.True(t, c.EnableTiDBExtension)
 
 	err = c.Validate()
-	require.ErrorContains(t, err, "enable-tidb-extension only supports canal-json/avro protocol")
+	require.ErrorContains(t, err, "enable-tidb-extension only supports canal-json/canal/maxwell/avro protocol")
 
 	// avro
 	uri = "kafka://127.0.0.1:9092/abc?protocol=avro"
@@ -168,6 +168,34 @@ func TestConfigApplyValidate(t *testing.T) {
 		`bigint-unsigned-handling-mode value could only be "long" or "string"`,
 	)
 
+	// avro-enable-watermark
+	c = NewConfig(config.ProtocolAvro)
+	require.False(t, c.AvroEnableWatermark)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&avro-enable-watermark=true"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	require.True(t, c.AvroEnableWatermark)
+
+	err = c.Validate()
+	require.ErrorContains(
+		t,
+		err,
+		`avro-enable-watermark requires parameter "enable-tidb-extension" to be true`,
+	)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=avro&avro-enable-watermark=true&enable-tidb-extension=true"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+
+	err = c.Apply(sinkURI, replicaConfig)
+	require.NoError(t, err)
+	err = c.Validate()
+	require.NoError(t, err)
+
 	// Illegal max-message-bytes.
 	uri = "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&max-message-bytes=a"
 	sinkURI, err = url.Parse(uri)
@@ -204,3 +232,25 @@ func TestConfigApplyValidate(t *testing.T) {
 	err = c.Validate()
 	require.ErrorContains(t, err, "invalid max-batch-size -1")
 }
+
+func TestValidateClaimCheck(t *testing.T) {
+	t.Parallel()
+
+	largeMessageHandle := &config.LargeMessageHandleConfig{
+		LargeMessageHandleOption: config.LargeMessageHandleOptionClaimCheck,
+		ClaimCheckStorageURI:     "file:///tmp/claim-check",
+	}
+	c := NewConfig(config.ProtocolOpen)
+	c.LargeMessageHandle = largeMessageHandle
+	require.NoError(t, c.Validate())
+
+	c = NewConfig(config.ProtocolCanalJSON)
+	c.LargeMessageHandle = largeMessageHandle
+	require.ErrorContains(t, c.Validate(), `claim-check requires parameter "enable-tidb-extension"`)
+	c.EnableTiDBExtension = true
+	require.NoError(t, c.Validate())
+
+	c = NewConfig(config.ProtocolMaxwell)
+	c.LargeMessageHandle = largeMessageHandle
+	require.ErrorContains(t, c.Validate(), "claim-check only supports open and canal-json protocols")
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"strings"
+
+	filter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+type keyRule struct {
+	filter.Filter
+	format   string
+	columns  []string
+	template string
+}
+
+// KeyGenerator generates the message keys of the row changed events in the
+// key formats configured by the dispatch rules. Like the topic and partition
+// dispatchers, the first dispatch rule matching the table takes effect.
+type KeyGenerator struct {
+	rules []keyRule
+}
+
+// NewKeyGenerator creates a KeyGenerator, it returns nil if all the dispatch
+// rules use the default key format.
+func NewKeyGenerator(rules []*config.DispatchRule, caseSensitive bool) (*KeyGenerator, error) {
+	customized := false
+	keyRules := make([]keyRule, 0, len(rules))
+	for _, rule := range rules {
+		f, err := filter.Parse(rule.Matcher)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, rule.Matcher)
+		}
+		if !caseSensitive {
+			f = filter.CaseInsensitive(f)
+		}
+		format := strings.ToLower(rule.KeyFormat)
+		if format == "" {
+			format = config.KeyFormatDefault
+		}
+		if format != config.KeyFormatDefault {
+			customized = true
+		}
+		keyRules = append(keyRules, keyRule{
+			Filter:   f,
+			format:   format,
+			columns:  rule.KeyColumns,
+			template: rule.KeyTemplate,
+		})
+	}
+	if !customized {
+		return nil, nil
+	}
+	return &KeyGenerator{rules: keyRules}, nil
+}
+
+// Generate returns the message key of the row changed event. The returned
+// bool is false if the default key format of the protocol should be used.
+func (g *KeyGenerator) Generate(row *model.RowChangedEvent) ([]byte, bool, error) {
+	if g == nil {
+		return nil, false, nil
+	}
+	var rule *keyRule
+	for i := range g.rules {
+		if g.rules[i].MatchTable(row.Table.Schema, row.Table.Table) {
+			rule = &g.rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		return nil, false, nil
+	}
+
+	switch rule.format {
+	case config.KeyFormatPrimaryKey:
+		return encodeKeyValues(row.HandleKeyColumns())
+	case config.KeyFormatColumns:
+		cols, err := findKeyColumns(row, rule.columns)
+		if err != nil {
+			return nil, false, err
+		}
+		return encodeKeyValues(cols)
+	case config.KeyFormatTemplate:
+		handleKeys := row.HandleKeyColumns()
+		values := make([]string, 0, len(handleKeys))
+		for _, col := range handleKeys {
+			values = append(values, model.ColumnValueString(col.Value))
+		}
+		key := strings.NewReplacer(
+			config.KeyTemplateSchema, row.Table.Schema,
+			config.KeyTemplateTable, row.Table.Table,
+			config.KeyTemplatePrimaryKey, strings.Join(values, ","),
+		).Replace(rule.template)
+		return []byte(key), true, nil
+	case config.KeyFormatNone:
+		return nil, true, nil
+	}
+	return nil, false, nil
+}
+
+func findKeyColumns(row *model.RowChangedEvent, names []string) ([]*model.Column, error) {
+	cols := row.Columns
+	if row.IsDelete() {
+		cols = row.PreColumns
+	}
+	result := make([]*model.Column, 0, len(names))
+	for _, name := range names {
+		var found *model.Column
+		for _, col := range cols {
+			if col != nil && strings.EqualFold(col.Name, name) {
+				found = col
+				break
+			}
+		}
+		if found == nil {
+			return nil, cerror.ErrEncodeFailed.GenWithStackByArgs(
+				"key column " + name + " is not found in table " + row.Table.String())
+		}
+		result = append(result, found)
+	}
+	return result, nil
+}
+
+// encodeKeyValues encodes the column values as a JSON array.
+func encodeKeyValues(cols []*model.Column) ([]byte, bool, error) {
+	values := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		// the string values are stored as bytes, which are encoded
+		// as base64 strings by the json package.
+		if b, ok := col.Value.([]byte); ok {
+			values = append(values, string(b))
+		} else {
+			values = append(values, col.Value)
+		}
+	}
+	key, err := json.Marshal(values)
+	if err != nil {
+		return nil, false, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return key, true, nil
+}
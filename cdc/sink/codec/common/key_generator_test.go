@@ -0,0 +1,123 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyGenerator(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewKeyGenerator(nil, false)
+	require.NoError(t, err)
+	require.Nil(t, g)
+
+	g, err = NewKeyGenerator([]*config.DispatchRule{
+		{Matcher: []string{"test.*"}, PartitionRule: "ts"},
+		{Matcher: []string{"test1.*"}, KeyFormat: "default"},
+	}, false)
+	require.NoError(t, err)
+	require.Nil(t, g)
+
+	_, err = NewKeyGenerator([]*config.DispatchRule{
+		{Matcher: []string{"test.*", "!"}, KeyFormat: "none"},
+	}, false)
+	require.Error(t, err)
+
+	// the nil generator always uses the default key format.
+	key, customized, err := g.Generate(&model.RowChangedEvent{
+		Table: &model.TableName{Schema: "test", Table: "t"},
+	})
+	require.NoError(t, err)
+	require.False(t, customized)
+	require.Nil(t, key)
+}
+
+func TestKeyGeneratorGenerate(t *testing.T) {
+	t.Parallel()
+
+	g, err := NewKeyGenerator([]*config.DispatchRule{
+		{Matcher: []string{"pk.*"}, KeyFormat: "primary-key"},
+		{Matcher: []string{"columns.*"}, KeyFormat: "Columns", KeyColumns: []string{"B", "a"}},
+		{Matcher: []string{"template.*"}, KeyFormat: "template", KeyTemplate: "{schema}.{table}:{pk}"},
+		{Matcher: []string{"none.*"}, KeyFormat: "none"},
+		{Matcher: []string{"default.*"}, KeyFormat: "default"},
+	}, false)
+	require.NoError(t, err)
+
+	cols := []*model.Column{
+		{Name: "a", Value: int64(1), Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "b", Value: []byte("x"), Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+		{Name: "c", Value: nil},
+	}
+	testCases := []struct {
+		row        *model.RowChangedEvent
+		key        []byte
+		customized bool
+	}{
+		{
+			row:        &model.RowChangedEvent{Table: &model.TableName{Schema: "pk", Table: "t"}, Columns: cols},
+			key:        []byte(`[1,"x"]`),
+			customized: true,
+		},
+		{
+			// the old values are used for the delete events.
+			row:        &model.RowChangedEvent{Table: &model.TableName{Schema: "PK", Table: "t"}, PreColumns: cols},
+			key:        []byte(`[1,"x"]`),
+			customized: true,
+		},
+		{
+			row:        &model.RowChangedEvent{Table: &model.TableName{Schema: "columns", Table: "t"}, Columns: cols},
+			key:        []byte(`["x",1]`),
+			customized: true,
+		},
+		{
+			row:        &model.RowChangedEvent{Table: &model.TableName{Schema: "template", Table: "t"}, Columns: cols},
+			key:        []byte(`template.t:1,x`),
+			customized: true,
+		},
+		{
+			row:        &model.RowChangedEvent{Table: &model.TableName{Schema: "none", Table: "t"}, Columns: cols},
+			key:        nil,
+			customized: true,
+		},
+		{
+			row:        &model.RowChangedEvent{Table: &model.TableName{Schema: "default", Table: "t"}, Columns: cols},
+			key:        nil,
+			customized: false,
+		},
+		{
+			row:        &model.RowChangedEvent{Table: &model.TableName{Schema: "unmatched", Table: "t"}, Columns: cols},
+			key:        nil,
+			customized: false,
+		},
+	}
+	for _, tc := range testCases {
+		key, customized, err := g.Generate(tc.row)
+		require.NoError(t, err)
+		require.Equal(t, tc.customized, customized)
+		require.Equal(t, tc.key, key)
+	}
+
+	_, _, err = g.Generate(&model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "columns", Table: "t"},
+		Columns: cols[:1],
+	})
+	require.Regexp(t, ".*key column B is not found in table.*", err)
+}
@@ -16,8 +16,10 @@ package codec
 import (
 	"context"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
 const (
@@ -40,7 +42,45 @@ type EventBatchEncoder interface {
 	Build() []*common.Message
 }
 
+// FileEventBatchEncoder is an EventBatchEncoder which encodes all the events
+// appended between two builds into one file, e.g. parquet. Unlike the other
+// protocols, building the file may fail, so `BuildFile` should be used instead
+// of `Build`.
+type FileEventBatchEncoder interface {
+	EventBatchEncoder
+	// BuildFile builds the file of the appended events, and returns the error
+	// if the file can't be written.
+	BuildFile() ([]*common.Message, error)
+}
+
 // EncoderBuilder builds encoder with context.
 type EncoderBuilder interface {
 	Build() EventBatchEncoder
 }
+
+// deadLetterErrors are the errors caused by the data of the rows.
+var deadLetterErrors = []*errors.Error{
+	cerror.ErrOpenProtocolCodecRowTooLarge,
+	cerror.ErrAvroEncodeFailed,
+	cerror.ErrCanalEncodeFailed,
+	cerror.ErrMaxwellEncodeFailed,
+	cerror.ErrCSVEncodeFailed,
+	cerror.ErrParquetEncodeFailed,
+}
+
+// IsDeadLetterError returns true if the error is caused by the data of the
+// row, and encoding the row again can never succeed. Only these rows can be
+// written to the dead letter queue, the other errors, such as the failures
+// of the schema registry or the claim check storage, must be retried.
+func IsDeadLetterError(err error) bool {
+	if err == nil {
+		return false
+	}
+	code, hasCode := cerror.RFCCode(err)
+	for _, e := range deadLetterErrors {
+		if e.Equal(err) || (hasCode && code == e.RFCCode()) {
+			return true
+		}
+	}
+	return false
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -54,10 +55,16 @@ type encoderGroup struct {
 	index   uint64
 
 	outputCh chan *future
+	// deadLetter receives the events which can not be encoded,
+	// it is nil if the dead letter queue is not configured.
+	deadLetter dlq.Writer
 }
 
-// NewEncoderGroup creates a new EncoderGroup instance
-func NewEncoderGroup(builder EncoderBuilder, count int, changefeedID model.ChangeFeedID) *encoderGroup {
+// NewEncoderGroup creates a new EncoderGroup instance. The events which
+// can not be encoded are written to the deadLetter if it is not nil.
+func NewEncoderGroup(
+	builder EncoderBuilder, count int, changefeedID model.ChangeFeedID, deadLetter dlq.Writer,
+) *encoderGroup {
 	if count <= 0 {
 		count = defaultEncoderGroupSize
 	}
@@ -75,6 +82,8 @@ func NewEncoderGroup(builder EncoderBuilder, count int, changefeedID model.Chang
 		inputCh:  inputCh,
 		index:    0,
 		outputCh: make(chan *future, defaultInputChanSize*count),
+
+		deadLetter: deadLetter,
 	}
 }
 
@@ -113,7 +122,13 @@ func (g *encoderGroup) runEncoder(ctx context.Context, idx int) error {
 			for _, event := range future.events {
 				err := encoder.AppendRowChangedEvent(ctx, future.Topic, event.Event, event.Callback)
 				if err != nil {
-					return errors.Trace(err)
+					if g.deadLetter == nil || !IsDeadLetterError(err) {
+						return errors.Trace(err)
+					}
+					if err := g.deadLetter.WriteRowChangedEvents(ctx, err, event.Event); err != nil {
+						return errors.Trace(err)
+					}
+					event.Callback()
 				}
 			}
 			future.Messages = encoder.Build()
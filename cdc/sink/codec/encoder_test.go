@@ -0,0 +1,39 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDeadLetterError(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, IsDeadLetterError(nil))
+	require.True(t, IsDeadLetterError(cerror.ErrOpenProtocolCodecRowTooLarge.GenWithStackByArgs()))
+	require.True(t, IsDeadLetterError(errors.Trace(
+		cerror.WrapError(cerror.ErrAvroEncodeFailed, errors.New("invalid decimal")))))
+
+	// the transient errors must be retried.
+	require.False(t, IsDeadLetterError(context.Canceled))
+	require.False(t, IsDeadLetterError(errors.Trace(
+		cerror.WrapError(cerror.ErrAvroSchemaAPIError, errors.New("connection refused")))))
+	require.False(t, IsDeadLetterError(
+		cerror.WrapError(cerror.ErrClaimCheckWriteFailed, errors.New("timeout"))))
+}
@@ -28,6 +28,10 @@ type MessageKey struct {
 	RowID     int64             `json:"rid,omitempty"`
 	Partition *int64            `json:"ptn,omitempty"`
 	Type      model.MessageType `json:"t"`
+	// ClaimCheckFile is the file name of the row in the claim-check
+	// storage. It's only set if the message of the row is too large,
+	// and the message only carries the handle key columns of the row.
+	ClaimCheckFile string `json:"ccf,omitempty"`
 }
 
 // Encode encodes the message key to a byte slice.
@@ -0,0 +1,245 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maxwell
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/errors"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// batchDecoder decodes the maxwell messages produced by the BatchEncoder.
+// A row message contains a batch of row events concatenated one by one,
+// while a DDL or resolved message contains only one event.
+type batchDecoder struct {
+	rows       *json.Decoder
+	ddl        *model.DDLEvent
+	resolvedTs uint64
+}
+
+// NewBatchDecoder creates a new maxwell batchDecoder.
+func NewBatchDecoder(key, value []byte) (codec.EventBatchDecoder, error) {
+	if len(key) == 8 && binary.BigEndian.Uint64(key) == codec.BatchVersion1 {
+		rows := json.NewDecoder(bytes.NewReader(value))
+		rows.UseNumber()
+		return &batchDecoder{rows: rows}, nil
+	}
+
+	// The row messages with the customized keys contain only one row event,
+	// they are told from the DDL and resolved messages by the value instead
+	// of the key, which is replaced by the customized key.
+	if len(value) != 0 {
+		valueMsg := new(ddlMaxwellMessage)
+		if err := json.Unmarshal(value, valueMsg); err != nil {
+			return nil, cerror.WrapError(cerror.ErrMaxwellDecodeFailed, err)
+		}
+		if isRowEventType(valueMsg.Type) {
+			rows := json.NewDecoder(bytes.NewReader(value))
+			rows.UseNumber()
+			return &batchDecoder{rows: rows}, nil
+		}
+	}
+
+	keyMsg := new(internal.MessageKey)
+	if err := keyMsg.Decode(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// the resolved messages are only sent with the TiDB extension.
+	if keyMsg.Type == model.MessageTypeResolved {
+		return &batchDecoder{resolvedTs: keyMsg.Ts}, nil
+	}
+	if keyMsg.Type != model.MessageTypeDDL {
+		return nil, cerror.ErrMaxwellDecodeFailed.GenWithStack(
+			"unexpected message type %d", keyMsg.Type)
+	}
+	valueMsg := new(ddlMaxwellMessage)
+	if err := json.Unmarshal(value, valueMsg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMaxwellDecodeFailed, err)
+	}
+	return &batchDecoder{ddl: maxwellMsgToDDLEvent(keyMsg, valueMsg)}, nil
+}
+
+// isRowEventType returns whether the maxwell type is of a row event.
+func isRowEventType(tp string) bool {
+	return tp == "insert" || tp == "update" || tp == "delete"
+}
+
+// HasNext implements the EventBatchDecoder interface
+func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
+	if b.ddl != nil {
+		return model.MessageTypeDDL, true, nil
+	}
+	if b.resolvedTs != 0 {
+		return model.MessageTypeResolved, true, nil
+	}
+	if b.rows != nil && b.rows.More() {
+		return model.MessageTypeRow, true, nil
+	}
+	return model.MessageTypeUnknown, false, nil
+}
+
+// NextResolvedEvent implements the EventBatchDecoder interface,
+// the resolved events are only sent with the TiDB extension.
+func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
+	if b.resolvedTs == 0 {
+		return 0, cerror.ErrMaxwellDecodeFailed.GenWithStack("not found resolved event message")
+	}
+	ts := b.resolvedTs
+	b.resolvedTs = 0
+	return ts, nil
+}
+
+// NextRowChangedEvent implements the EventBatchDecoder interface
+func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if b.rows == nil || !b.rows.More() {
+		return nil, cerror.ErrMaxwellDecodeFailed.GenWithStack("not found row event message")
+	}
+	msg := new(maxwellMessage)
+	if err := b.rows.Decode(msg); err != nil {
+		return nil, cerror.WrapError(cerror.ErrMaxwellDecodeFailed, err)
+	}
+	return maxwellMsgToRowChange(msg)
+}
+
+// NextDDLEvent implements the EventBatchDecoder interface
+func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
+	if b.ddl == nil {
+		return nil, cerror.ErrMaxwellDecodeFailed.GenWithStack("not found ddl event message")
+	}
+	ddl := b.ddl
+	b.ddl = nil
+	return ddl, nil
+}
+
+// maxwellMsgToRowChange is the reverse of rowChangeToMaxwellMsg. The maxwell
+// message only keeps the commit time in seconds and does not carry the column
+// types and flags, so the decoded event is an approximation of the original one.
+// In particular, without the TiDB extension the CommitTs is rounded down to the
+// second, so different transactions committed in the same second share it.
+func maxwellMsgToRowChange(msg *maxwellMessage) (*model.RowChangedEvent, error) {
+	e := new(model.RowChangedEvent)
+	e.CommitTs = oracle.ComposeTS(msg.Ts*1000, 0)
+	if msg.TiDB != nil {
+		e.CommitTs = msg.TiDB.CommitTs
+	}
+	e.Table = &model.TableName{Schema: msg.Database, Table: msg.Table}
+
+	var err error
+	switch msg.Type {
+	case "insert":
+		e.Columns, err = maxwellDataToColumns(msg.Data)
+	case "update":
+		e.Columns, err = maxwellDataToColumns(msg.Data)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// only the changed columns are kept in `old`.
+		old := make(map[string]interface{}, len(msg.Data))
+		for name, value := range msg.Data {
+			old[name] = value
+		}
+		for name, value := range msg.Old {
+			old[name] = value
+		}
+		e.PreColumns, err = maxwellDataToColumns(old)
+	case "delete":
+		e.PreColumns, err = maxwellDataToColumns(msg.Old)
+	default:
+		return nil, cerror.ErrMaxwellDecodeFailed.GenWithStack(
+			"unknown row event type %s", msg.Type)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return e, nil
+}
+
+func maxwellDataToColumns(data map[string]interface{}) ([]*model.Column, error) {
+	cols := make([]*model.Column, 0, len(data))
+	for name, value := range data {
+		col := &model.Column{Name: name}
+		switch v := value.(type) {
+		case nil:
+			col.Type = mysql.TypeNull
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				col.Type = mysql.TypeLonglong
+				col.Value = n
+			} else if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+				col.Type = mysql.TypeLonglong
+				col.Flag.SetIsUnsigned()
+				col.Value = n
+			} else {
+				f, err := v.Float64()
+				if err != nil {
+					return nil, cerror.WrapError(cerror.ErrMaxwellDecodeFailed, err)
+				}
+				col.Type = mysql.TypeDouble
+				col.Value = f
+			}
+		case string:
+			col.Type = mysql.TypeVarchar
+			col.Value = []byte(v)
+		default:
+			return nil, cerror.ErrMaxwellDecodeFailed.GenWithStack(
+				"unexpected value %v of column %s", value, name)
+		}
+		cols = append(cols, col)
+	}
+	sort.Slice(cols, func(i, j int) bool {
+		return cols[i].Name < cols[j].Name
+	})
+	return cols, nil
+}
+
+// maxwellMsgToDDLEvent is the reverse of ddlEventToMaxwellMsg.
+func maxwellMsgToDDLEvent(key *internal.MessageKey, msg *ddlMaxwellMessage) *model.DDLEvent {
+	e := new(model.DDLEvent)
+	e.CommitTs = key.Ts
+	e.TableInfo = new(model.TableInfo)
+	e.TableInfo.TableName = model.TableName{Schema: key.Schema, Table: key.Table}
+	e.Query = msg.SQL
+	e.Type = maxwellTypeToDDL(msg.Type)
+	return e
+}
+
+// maxwellTypeToDDL is the reverse of ddlToMaxwellType, the exact DDL type is
+// lost for most DDLs, it only needs to be compatible with the MySQL sink.
+func maxwellTypeToDDL(tp string) timodel.ActionType {
+	switch tp {
+	case "table-create":
+		return timodel.ActionCreateTable
+	case "table-drop":
+		return timodel.ActionDropTable
+	case "database-create":
+		return timodel.ActionCreateSchema
+	case "database-drop":
+		return timodel.ActionDropSchema
+	case "database-alter":
+		return timodel.ActionModifySchemaCharsetAndCollate
+	default:
+		return timodel.ActionNone
+	}
+}
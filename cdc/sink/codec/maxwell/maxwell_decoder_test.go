@@ -0,0 +1,240 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maxwell
+
+import (
+	"context"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxwellBatchDecoder4RowMessage(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "a", Table: "b"}
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLonglong, Value: int64(1)},
+		{Name: "name", Type: mysql.TypeVarchar, Value: []byte("alice")},
+		{Name: "score", Type: mysql.TypeDouble, Value: float64(3.5)},
+		{Name: "remark", Type: mysql.TypeVarchar, Value: nil},
+	}
+	preColumns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLonglong, Value: int64(1)},
+		{Name: "name", Type: mysql.TypeVarchar, Value: []byte("bob")},
+		{Name: "score", Type: mysql.TypeDouble, Value: float64(3.5)},
+		{Name: "remark", Type: mysql.TypeVarchar, Value: nil},
+	}
+	// 2022-12-01 00:00:00 UTC
+	commitTs := uint64(1669852800000) << 18
+	rows := []*model.RowChangedEvent{
+		{CommitTs: commitTs, Table: table, Columns: columns},
+		{CommitTs: commitTs, Table: table, Columns: columns, PreColumns: preColumns},
+		{CommitTs: commitTs, Table: table, PreColumns: preColumns},
+	}
+
+	encoder := newBatchEncoder(common.NewConfig(config.ProtocolMaxwell))
+	for _, row := range rows {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+		require.Nil(t, err)
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+
+	decoder, err := NewBatchDecoder(messages[0].Key, messages[0].Value)
+	require.Nil(t, err)
+
+	checkColumns := func(expected, actual []*model.Column) {
+		values := make(map[string]interface{}, len(expected))
+		for _, col := range expected {
+			values[col.Name] = col.Value
+		}
+		require.Len(t, actual, len(expected))
+		for _, col := range actual {
+			require.Equal(t, values[col.Name], col.Value, col.Name)
+		}
+	}
+	for _, expected := range rows {
+		ty, hasNext, err := decoder.HasNext()
+		require.Nil(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, ty)
+
+		consumed, err := decoder.NextRowChangedEvent()
+		require.Nil(t, err)
+		require.Equal(t, expected.Table, consumed.Table)
+		require.Equal(t, expected.CommitTs, consumed.CommitTs)
+		require.Equal(t, expected.IsInsert(), consumed.IsInsert())
+		require.Equal(t, expected.IsUpdate(), consumed.IsUpdate())
+		require.Equal(t, expected.IsDelete(), consumed.IsDelete())
+		checkColumns(expected.Columns, consumed.Columns)
+		checkColumns(expected.PreColumns, consumed.PreColumns)
+	}
+
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+	require.Equal(t, model.MessageTypeUnknown, ty)
+
+	_, err = decoder.NextRowChangedEvent()
+	require.NotNil(t, err)
+	_, err = decoder.NextResolvedEvent()
+	require.NotNil(t, err)
+}
+
+func TestMaxwellBatchDecoder4DDLMessage(t *testing.T) {
+	t.Parallel()
+
+	ddl := &model.DDLEvent{
+		CommitTs: 417318403368288260,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "a", Table: "b"},
+			TableInfo: &timodel.TableInfo{},
+		},
+		Query: "create table a.b(id int primary key)",
+		Type:  timodel.ActionCreateTable,
+	}
+	encoder := newBatchEncoder(common.NewConfig(config.ProtocolMaxwell))
+	msg, err := encoder.EncodeDDLEvent(ddl)
+	require.Nil(t, err)
+
+	decoder, err := NewBatchDecoder(msg.Key, msg.Value)
+	require.Nil(t, err)
+
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeDDL, ty)
+
+	consumed, err := decoder.NextDDLEvent()
+	require.Nil(t, err)
+	require.Equal(t, ddl.CommitTs, consumed.CommitTs)
+	require.Equal(t, ddl.TableInfo.TableName, consumed.TableInfo.TableName)
+	require.Equal(t, ddl.Query, consumed.Query)
+	require.Equal(t, ddl.Type, consumed.Type)
+
+	_, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+	_, err = decoder.NextDDLEvent()
+	require.NotNil(t, err)
+
+	_, err = NewBatchDecoder([]byte("invalid"), msg.Value)
+	require.NotNil(t, err)
+}
+
+func TestMaxwellBatchDecoderWithTiDBExtension(t *testing.T) {
+	t.Parallel()
+
+	codecConfig := common.NewConfig(config.ProtocolMaxwell)
+	codecConfig.EnableTiDBExtension = true
+	encoder := newBatchEncoder(codecConfig)
+
+	row := &model.RowChangedEvent{
+		CommitTs: 417318403368288260,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Value: int64(1)},
+		},
+	}
+	err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+	require.Nil(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+
+	decoder, err := NewBatchDecoder(messages[0].Key, messages[0].Value)
+	require.Nil(t, err)
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, ty)
+	consumed, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	// the full commit ts is kept by the TiDB extension.
+	require.Equal(t, row.CommitTs, consumed.CommitTs)
+
+	msg, err := encoder.EncodeCheckpointEvent(row.CommitTs)
+	require.Nil(t, err)
+	require.Equal(t, model.MessageTypeResolved, msg.Type)
+
+	decoder, err = NewBatchDecoder(msg.Key, msg.Value)
+	require.Nil(t, err)
+	ty, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeResolved, ty)
+	ts, err := decoder.NextResolvedEvent()
+	require.Nil(t, err)
+	require.Equal(t, row.CommitTs, ts)
+
+	_, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+
+	// the checkpoint events are ignored without the TiDB extension.
+	encoder = newBatchEncoder(common.NewConfig(config.ProtocolMaxwell))
+	msg, err = encoder.EncodeCheckpointEvent(row.CommitTs)
+	require.Nil(t, err)
+	require.Nil(t, msg)
+}
+
+func TestMaxwellBatchDecoderWithCustomizedKey(t *testing.T) {
+	t.Parallel()
+
+	keyGenerator, err := common.NewKeyGenerator([]*config.DispatchRule{
+		{Matcher: []string{"a.b"}, KeyFormat: config.KeyFormatPrimaryKey},
+	}, false)
+	require.Nil(t, err)
+	cfg := common.NewConfig(config.ProtocolMaxwell)
+	cfg.KeyGenerator = keyGenerator
+	encoder := newBatchEncoder(cfg)
+
+	row := &model.RowChangedEvent{
+		CommitTs: uint64(1669852800000) << 18,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		PreColumns: []*model.Column{{
+			Name:  "t",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag,
+			Value: int64(2),
+		}},
+	}
+	err = encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+	require.Nil(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.Equal(t, []byte("[2]"), messages[0].Key)
+
+	// the row is decoded regardless of the customized key.
+	decoder, err := NewBatchDecoder(messages[0].Key, messages[0].Value)
+	require.Nil(t, err)
+	ty, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, ty)
+	consumed, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.True(t, consumed.IsDelete())
+	require.Equal(t, row.CommitTs, consumed.CommitTs)
+	require.Equal(t, row.Table, consumed.Table)
+
+	_, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
 	"github.com/pingcap/tiflow/pkg/config"
 )
 
@@ -31,13 +32,35 @@ type BatchEncoder struct {
 	valueBuf    *bytes.Buffer
 	callbackBuf []func()
 	batchSize   int
+
+	// keyGenerator generates the customized message keys. The row changed
+	// events with customized keys are sent in their own messages instead of
+	// being batched, so that each message carries the key of its row.
+	keyGenerator *common.KeyGenerator
+	messages     []*common.Message
+
+	// When it is true, the row messages carry the full commit ts in the `_tidb`
+	// field, and the checkpoint events are encoded as resolved messages.
+	enableTiDBExtension bool
 }
 
 // EncodeCheckpointEvent implements the EventBatchEncoder interface
 func (d *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
 	// For maxwell now, there is no such a corresponding type to ResolvedEvent so far.
-	// Therefore the event is ignored.
-	return nil, nil
+	// Therefore the event is ignored unless the TiDB extension is enabled.
+	if !d.enableTiDBExtension {
+		return nil, nil
+	}
+
+	keyMsg := &internal.MessageKey{
+		Ts:   ts,
+		Type: model.MessageTypeResolved,
+	}
+	key, err := keyMsg.Encode()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.NewResolvedMsg(config.ProtocolMaxwell, key, nil, ts), nil
 }
 
 // AppendRowChangedEvent implements the EventBatchEncoder interface
@@ -48,10 +71,25 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	callback func(),
 ) error {
 	_, valueMsg := rowChangeToMaxwellMsg(e)
+	if d.enableTiDBExtension {
+		valueMsg.TiDB = &tidbExtension{CommitTs: e.CommitTs}
+	}
 	value, err := valueMsg.encode()
 	if err != nil {
 		return errors.Trace(err)
 	}
+	key, customized, err := d.keyGenerator.Generate(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if customized {
+		msg := common.NewMsg(config.ProtocolMaxwell, key, value,
+			e.CommitTs, model.MessageTypeRow, &e.Table.Schema, &e.Table.Table)
+		msg.SetRowsCount(1)
+		msg.Callback = callback
+		d.messages = append(d.messages, msg)
+		return nil
+	}
 	d.valueBuf.Write(value)
 	d.batchSize++
 	if callback != nil {
@@ -78,8 +116,10 @@ func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error
 
 // Build implements the EventBatchEncoder interface
 func (d *BatchEncoder) Build() []*common.Message {
+	messages := d.messages
+	d.messages = nil
 	if d.batchSize == 0 {
-		return nil
+		return messages
 	}
 
 	ret := common.NewMsg(config.ProtocolMaxwell,
@@ -95,7 +135,7 @@ func (d *BatchEncoder) Build() []*common.Message {
 		d.callbackBuf = make([]func(), 0)
 	}
 	d.reset()
-	return []*common.Message{ret}
+	return append([]*common.Message{ret}, messages...)
 }
 
 // reset implements the EventBatchEncoder interface
@@ -109,24 +149,28 @@ func (d *BatchEncoder) reset() {
 }
 
 // newBatchEncoder creates a new maxwell BatchEncoder.
-func newBatchEncoder() codec.EventBatchEncoder {
+func newBatchEncoder(config *common.Config) codec.EventBatchEncoder {
 	batch := &BatchEncoder{
-		keyBuf:      &bytes.Buffer{},
-		valueBuf:    &bytes.Buffer{},
-		callbackBuf: make([]func(), 0),
+		keyBuf:              &bytes.Buffer{},
+		valueBuf:            &bytes.Buffer{},
+		callbackBuf:         make([]func(), 0),
+		keyGenerator:        config.KeyGenerator,
+		enableTiDBExtension: config.EnableTiDBExtension,
 	}
 	batch.reset()
 	return batch
 }
 
-type batchEncoderBuilder struct{}
+type batchEncoderBuilder struct {
+	config *common.Config
+}
 
 // NewBatchEncoderBuilder creates a maxwell batchEncoderBuilder.
-func NewBatchEncoderBuilder() codec.EncoderBuilder {
-	return &batchEncoderBuilder{}
+func NewBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
+	return &batchEncoderBuilder{config: config}
 }
 
 // Build a `maxwellBatchEncoder`
 func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
-	return newBatchEncoder()
+	return newBatchEncoder(b.config)
 }
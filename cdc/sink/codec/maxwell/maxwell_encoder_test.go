@@ -20,6 +20,8 @@ import (
 	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,7 +35,7 @@ func TestMaxwellBatchCodec(t *testing.T) {
 		Columns:  []*model.Column{{Name: "col1", Type: 3, Value: 10}},
 	}}, {}}
 	for _, cs := range rowCases {
-		encoder := newEncoder()
+		encoder := newEncoder(common.NewConfig(config.ProtocolMaxwell))
 		for _, row := range cs {
 			err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
 			require.Nil(t, err)
@@ -59,7 +61,7 @@ func TestMaxwellBatchCodec(t *testing.T) {
 		Type:  1,
 	}}}
 	for _, cs := range ddlCases {
-		encoder := newEncoder()
+		encoder := newEncoder(common.NewConfig(config.ProtocolMaxwell))
 		for _, ddl := range cs {
 			msg, err := encoder.EncodeDDLEvent(ddl)
 			require.Nil(t, err)
@@ -69,7 +71,7 @@ func TestMaxwellBatchCodec(t *testing.T) {
 }
 
 func TestMaxwellAppendRowChangedEventWithCallback(t *testing.T) {
-	encoder := newBatchEncoder()
+	encoder := newBatchEncoder(common.NewConfig(config.ProtocolMaxwell))
 	require.NotNil(t, encoder)
 
 	count := 0
@@ -136,3 +138,40 @@ func TestMaxwellAppendRowChangedEventWithCallback(t *testing.T) {
 	msgs[0].Callback()
 	require.Equal(t, 15, count, "expected all callbacks to be called")
 }
+
+func TestMaxwellCustomizedKey(t *testing.T) {
+	t.Parallel()
+	keyGenerator, err := common.NewKeyGenerator([]*config.DispatchRule{
+		{Matcher: []string{"a.b"}, KeyFormat: config.KeyFormatTemplate, KeyTemplate: "{table}-{pk}"},
+	}, false)
+	require.Nil(t, err)
+	cfg := common.NewConfig(config.ProtocolMaxwell)
+	cfg.KeyGenerator = keyGenerator
+	encoder := newBatchEncoder(cfg)
+
+	for _, table := range []string{"b", "c", "b"} {
+		row := &model.RowChangedEvent{
+			CommitTs: 1,
+			Table:    &model.TableName{Schema: "a", Table: table},
+			Columns: []*model.Column{{
+				Name:  "col1",
+				Type:  mysql.TypeLong,
+				Flag:  model.HandleKeyFlag,
+				Value: int64(10),
+			}},
+		}
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+		require.Nil(t, err)
+	}
+
+	messages := encoder.Build()
+	require.Len(t, messages, 3)
+	// the batched message comes first.
+	require.Equal(t, 1, messages[0].GetRowsCount())
+	require.Len(t, messages[0].Key, 8)
+	for _, msg := range messages[1:] {
+		require.Equal(t, []byte("b-10"), msg.Key)
+		require.Equal(t, 1, msg.GetRowsCount())
+	}
+	require.Nil(t, encoder.Build())
+}
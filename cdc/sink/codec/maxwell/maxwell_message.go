@@ -35,6 +35,13 @@ type maxwellMessage struct {
 	Gtid     string                 `json:"gtid,omitempty"`
 	Data     map[string]interface{} `json:"data,omitempty"`
 	Old      map[string]interface{} `json:"old,omitempty"`
+	// TiDB is only set when `enable-tidb-extension` is true.
+	TiDB *tidbExtension `json:"_tidb,omitempty"`
+}
+
+// tidbExtension keeps the full commit ts, since `ts` is in seconds.
+type tidbExtension struct {
+	CommitTs uint64 `json:"commitTs"`
 }
 
 // Encode encodes the message to bytes
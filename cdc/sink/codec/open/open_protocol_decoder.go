@@ -14,11 +14,13 @@
 package open
 
 import (
+	"context"
 	"encoding/binary"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/claimcheck"
 	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -28,6 +30,9 @@ type BatchMixedDecoder struct {
 	mixedBytes []byte
 	nextKey    *internal.MessageKey
 	nextKeyLen uint64
+
+	ctx        context.Context
+	claimCheck *claimcheck.ClaimCheck
 }
 
 // HasNext implements the EventBatchDecoder interface
@@ -77,7 +82,10 @@ func (b *BatchMixedDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error
 	if err := rowMsg.decode(value); err != nil {
 		return nil, errors.Trace(err)
 	}
-	rowEvent := msgToRowChange(b.nextKey, rowMsg)
+	rowEvent, err := resolveRowChange(b.ctx, b.claimCheck, b.nextKey, rowMsg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	b.nextKey = nil
 	return rowEvent, nil
 }
@@ -129,6 +137,9 @@ type BatchDecoder struct {
 	valueBytes []byte
 	nextKey    *internal.MessageKey
 	nextKeyLen uint64
+
+	ctx        context.Context
+	claimCheck *claimcheck.ClaimCheck
 }
 
 // HasNext implements the EventBatchDecoder interface
@@ -178,7 +189,10 @@ func (b *BatchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 	if err := rowMsg.decode(value); err != nil {
 		return nil, errors.Trace(err)
 	}
-	rowEvent := msgToRowChange(b.nextKey, rowMsg)
+	rowEvent, err := resolveRowChange(b.ctx, b.claimCheck, b.nextKey, rowMsg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	b.nextKey = nil
 	return rowEvent, nil
 }
@@ -223,8 +237,45 @@ func (b *BatchDecoder) decodeNextKey() error {
 	return nil
 }
 
+// resolveRowChange converts the message to the row changed event. If the
+// message refers to a row in the claim-check storage, the row is read from
+// the storage instead.
+func resolveRowChange(
+	ctx context.Context, claimCheck *claimcheck.ClaimCheck,
+	key *internal.MessageKey, value *messageRow,
+) (*model.RowChangedEvent, error) {
+	if key.ClaimCheckFile == "" {
+		return msgToRowChange(key, value), nil
+	}
+	if claimCheck == nil {
+		return nil, cerror.ErrOpenProtocolCodecInvalidData.GenWithStack(
+			"claim-check storage is not configured to read %s", key.ClaimCheckFile)
+	}
+	msg, err := claimCheck.ReadMessage(ctx, key.ClaimCheckFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fullKey := new(internal.MessageKey)
+	if err := fullKey.Decode(msg.Key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	fullValue := new(messageRow)
+	if err := fullValue.decode(msg.Value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return msgToRowChange(fullKey, fullValue), nil
+}
+
 // NewBatchDecoder creates a new BatchDecoder.
 func NewBatchDecoder(key []byte, value []byte) (codec.EventBatchDecoder, error) {
+	return NewBatchDecoderWithClaimCheck(context.Background(), key, value, nil)
+}
+
+// NewBatchDecoderWithClaimCheck creates a new BatchDecoder, which reads the
+// rows of the large messages from the claim-check storage.
+func NewBatchDecoderWithClaimCheck(
+	ctx context.Context, key []byte, value []byte, claimCheck *claimcheck.ClaimCheck,
+) (codec.EventBatchDecoder, error) {
 	version := binary.BigEndian.Uint64(key[:8])
 	key = key[8:]
 	if version != codec.BatchVersion1 {
@@ -234,10 +285,14 @@ func NewBatchDecoder(key []byte, value []byte) (codec.EventBatchDecoder, error)
 	if len(key) > 0 && len(value) == 0 {
 		return &BatchMixedDecoder{
 			mixedBytes: key,
+			ctx:        ctx,
+			claimCheck: claimCheck,
 		}, nil
 	}
 	return &BatchDecoder{
 		keyBytes:   key,
 		valueBytes: value,
+		ctx:        ctx,
+		claimCheck: claimCheck,
 	}, nil
 }
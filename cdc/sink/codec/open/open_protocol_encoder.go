@@ -20,9 +20,12 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/claimcheck"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
@@ -37,39 +40,70 @@ type BatchEncoder struct {
 	// configs
 	MaxMessageBytes int
 	MaxBatchSize    int
+
+	// keyGenerator generates the customized message keys. The row changed
+	// events with customized keys are sent in their own messages, whose keys
+	// are the customized keys instead of the batched open protocol keys.
+	keyGenerator *common.KeyGenerator
+
+	// claimCheck stores the rows whose messages are larger than
+	// MaxMessageBytes, it's nil if the claim-check mode is disabled.
+	claimCheck *claimcheck.ClaimCheck
 }
 
 // AppendRowChangedEvent implements the EventBatchEncoder interface
 func (d *BatchEncoder) AppendRowChangedEvent(
-	_ context.Context,
+	ctx context.Context,
 	_ string,
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	keyMsg, valueMsg := rowChangeToMsg(e)
-	key, err := keyMsg.Encode()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	value, err := valueMsg.encode()
+	key, value, err := encodeRowChangedEvent(rowChangeToMsg(e))
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	var keyLenByte [8]byte
-	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
-	var valueLenByte [8]byte
-	binary.BigEndian.PutUint64(valueLenByte[:], uint64(len(value)))
-
 	// for single message that longer than max-message-size, do not send it.
 	// 16 is the length of `keyLenByte` and `valueLenByte`, 8 is the length of `versionHead`
 	length := len(key) + len(value) + common.MaxRecordOverhead + 16 + 8
+	if length > d.MaxMessageBytes && d.claimCheck != nil {
+		key, value, err = d.newClaimCheckMessage(ctx, e, key, value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		length = len(key) + len(value) + common.MaxRecordOverhead + 16 + 8
+	}
 	if length > d.MaxMessageBytes {
 		log.Warn("Single message too large",
 			zap.Int("max-message-size", d.MaxMessageBytes), zap.Int("length", length), zap.Any("table", e.Table))
 		return cerror.ErrOpenProtocolCodecRowTooLarge.GenWithStackByArgs()
 	}
 
+	var keyLenByte [8]byte
+	binary.BigEndian.PutUint64(keyLenByte[:], uint64(len(key)))
+	var valueLenByte [8]byte
+	binary.BigEndian.PutUint64(valueLenByte[:], uint64(len(value)))
+
+	customKey, customized, err := d.keyGenerator.Generate(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if customized {
+		d.tryBuildCallback()
+		valueBuf := new(bytes.Buffer)
+		valueBuf.Write(valueLenByte[:])
+		valueBuf.Write(value)
+		msg := common.NewMsg(config.ProtocolOpen, customKey, valueBuf.Bytes(),
+			e.CommitTs, model.MessageTypeRow, &e.Table.Schema, &e.Table.Table)
+		msg.IncRowsCount()
+		msg.Callback = callback
+		d.messageBuf = append(d.messageBuf, msg)
+		// mark the batch as full, so that the following events are not
+		// appended to the message with the customized key.
+		d.curBatchSize = d.MaxBatchSize
+		return nil
+	}
+
 	if len(d.messageBuf) == 0 ||
 		d.curBatchSize >= d.MaxBatchSize ||
 		d.messageBuf[len(d.messageBuf)-1].Length()+len(key)+len(value)+16 > d.MaxMessageBytes {
@@ -100,6 +134,39 @@ func (d *BatchEncoder) AppendRowChangedEvent(
 	return nil
 }
 
+// newClaimCheckMessage writes the encoded row into the claim-check storage,
+// and returns the message which only carries the file name of the row and
+// its handle key columns.
+func (d *BatchEncoder) newClaimCheckMessage(
+	ctx context.Context, e *model.RowChangedEvent, key, value []byte,
+) ([]byte, []byte, error) {
+	fileName, err := d.claimCheck.WriteMessage(ctx, key, value, e.CommitTs)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	log.Info("large message is written to the claim-check storage",
+		zap.Int("length", len(key)+len(value)), zap.Any("table", e.Table),
+		zap.String("file", fileName))
+
+	keyMsg, valueMsg := rowChangeToHandleKeyOnlyMsg(e)
+	keyMsg.ClaimCheckFile = fileName
+	return encodeRowChangedEvent(keyMsg, valueMsg)
+}
+
+func encodeRowChangedEvent(
+	keyMsg *internal.MessageKey, valueMsg *messageRow,
+) ([]byte, []byte, error) {
+	key, err := keyMsg.Encode()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	value, err := valueMsg.encode()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return key, value, nil
+}
+
 // EncodeDDLEvent implements the EventBatchEncoder interface
 func (d *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error) {
 	keyMsg, valueMsg := ddlEventToMsg(e)
@@ -182,7 +249,8 @@ func (d *BatchEncoder) tryBuildCallback() {
 }
 
 type batchEncoderBuilder struct {
-	config *common.Config
+	config     *common.Config
+	claimCheck *claimcheck.ClaimCheck
 }
 
 // Build a BatchEncoder
@@ -190,13 +258,24 @@ func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
 	encoder := NewBatchEncoder()
 	encoder.(*BatchEncoder).MaxMessageBytes = b.config.MaxMessageBytes
 	encoder.(*BatchEncoder).MaxBatchSize = b.config.MaxBatchSize
+	encoder.(*BatchEncoder).keyGenerator = b.config.KeyGenerator
+	encoder.(*BatchEncoder).claimCheck = b.claimCheck
 
 	return encoder
 }
 
 // NewBatchEncoderBuilder creates an open-protocol batchEncoderBuilder.
-func NewBatchEncoderBuilder(config *common.Config) codec.EncoderBuilder {
-	return &batchEncoderBuilder{config: config}
+func NewBatchEncoderBuilder(ctx context.Context, config *common.Config) (codec.EncoderBuilder, error) {
+	builder := &batchEncoderBuilder{config: config}
+	if config.LargeMessageHandle.EnableClaimCheck() {
+		claimCheck, err := claimcheck.New(ctx,
+			config.LargeMessageHandle.ClaimCheckStorageURI, contextutil.ChangefeedIDFromCtx(ctx))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		builder.claimCheck = claimCheck
+	}
+	return builder, nil
 }
 
 // NewBatchEncoder creates a new BatchEncoder.
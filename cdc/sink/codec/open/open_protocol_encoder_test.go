@@ -15,13 +15,16 @@ package open
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/claimcheck"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sink/codec/internal"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,19 +56,19 @@ func TestMaxMessageBytes(t *testing.T) {
 	// for a single message, the overhead is 36(maxRecordOverhead) + 8(versionHea) = 44, just can hold it.
 	a := 88 + 44
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(a)
-	encoder := NewBatchEncoderBuilder(config).Build()
+	encoder := (&batchEncoderBuilder{config: config}).Build()
 	err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.Nil(t, err)
 
 	// cannot hold a single message
 	config = config.WithMaxMessageBytes(a - 1)
-	encoder = NewBatchEncoderBuilder(config).Build()
+	encoder = (&batchEncoderBuilder{config: config}).Build()
 	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.NotNil(t, err)
 
 	// make sure each batch's `Length` not greater than `max-message-bytes`
 	config = config.WithMaxMessageBytes(256)
-	encoder = NewBatchEncoderBuilder(config).Build()
+	encoder = (&batchEncoderBuilder{config: config}).Build()
 	for i := 0; i < 10000; i++ {
 		err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 		require.Nil(t, err)
@@ -81,7 +84,7 @@ func TestMaxBatchSize(t *testing.T) {
 	t.Parallel()
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(1048576)
 	config.MaxBatchSize = 64
-	encoder := NewBatchEncoderBuilder(config).Build()
+	encoder := (&batchEncoderBuilder{config: config}).Build()
 
 	testEvent := &model.RowChangedEvent{
 		CommitTs: 1,
@@ -206,5 +209,113 @@ func TestOpenProtocolBatchCodec(t *testing.T) {
 	config := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(8192)
 	config.MaxBatchSize = 64
 	tester := internal.NewDefaultBatchTester()
-	tester.TestBatchCodec(t, NewBatchEncoderBuilder(config), NewBatchDecoder)
+	builder, err := NewBatchEncoderBuilder(context.Background(), config)
+	require.Nil(t, err)
+	tester.TestBatchCodec(t, builder, NewBatchDecoder)
+}
+
+func TestOpenProtocolCustomizedKey(t *testing.T) {
+	t.Parallel()
+	keyGenerator, err := common.NewKeyGenerator([]*config.DispatchRule{
+		{Matcher: []string{"a.b"}, KeyFormat: config.KeyFormatPrimaryKey},
+	}, false)
+	require.Nil(t, err)
+	cfg := common.NewConfig(config.ProtocolOpen)
+	cfg.KeyGenerator = keyGenerator
+	encoder := (&batchEncoderBuilder{config: cfg}).Build()
+
+	newRow := func(table string) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			CommitTs: 1,
+			Table:    &model.TableName{Schema: "a", Table: table},
+			Columns: []*model.Column{{
+				Name:  "col1",
+				Type:  mysql.TypeLong,
+				Flag:  model.HandleKeyFlag,
+				Value: int64(1),
+			}},
+		}
+	}
+	count := 0
+	callback := func() { count++ }
+	for _, table := range []string{"c", "b", "c", "c"} {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", newRow(table), callback)
+		require.Nil(t, err)
+	}
+
+	messages := encoder.Build()
+	require.Len(t, messages, 3)
+	require.Equal(t, 1, messages[0].GetRowsCount())
+	require.Equal(t, []byte(`[1]`), messages[1].Key)
+	require.Equal(t, 1, messages[1].GetRowsCount())
+	require.Equal(t, 2, messages[2].GetRowsCount())
+	for _, msg := range messages {
+		msg.Callback()
+	}
+	require.Equal(t, 4, count)
+
+	// the messages with the default keys can still be decoded.
+	decoder, err := NewBatchDecoder(messages[2].Key, messages[2].Value)
+	require.Nil(t, err)
+	tp, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+}
+
+func TestOpenProtocolClaimCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storageURI := "file://" + t.TempDir()
+	cfg := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(300)
+	cfg.LargeMessageHandle = &config.LargeMessageHandleConfig{
+		LargeMessageHandleOption: config.LargeMessageHandleOptionClaimCheck,
+		ClaimCheckStorageURI:     storageURI,
+	}
+	builder, err := NewBatchEncoderBuilder(ctx, cfg)
+	require.Nil(t, err)
+	encoder := builder.Build()
+
+	largeRow := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: int64(1)},
+			{Name: "data", Type: mysql.TypeVarchar, Value: []byte(strings.Repeat("x", 300))},
+		},
+	}
+	err = encoder.AppendRowChangedEvent(ctx, "", largeRow, nil)
+	require.Nil(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.LessOrEqual(t, messages[0].Length(), 300)
+
+	decoder, err := NewBatchDecoder(messages[0].Key, messages[0].Value)
+	require.Nil(t, err)
+	_, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	_, err = decoder.NextRowChangedEvent()
+	require.Regexp(t, ".*claim-check storage is not configured.*", err)
+
+	claimCheck, err := claimcheck.New(ctx, storageURI, model.ChangeFeedID{})
+	require.Nil(t, err)
+	decoder, err = NewBatchDecoderWithClaimCheck(ctx, messages[0].Key, messages[0].Value, claimCheck)
+	require.Nil(t, err)
+	_, hasNext, err = decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	row, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Equal(t, largeRow.Table, row.Table)
+	require.Len(t, row.Columns, 2)
+	require.Equal(t, "id", row.Columns[0].Name)
+	require.Equal(t, "data", row.Columns[1].Name)
+	require.Equal(t, []byte(strings.Repeat("x", 300)), row.Columns[1].Value)
+
+	// the row is still too large if its handle key columns are too large.
+	largeRow.Columns[0].Value = strings.Repeat("1", 300)
+	err = encoder.AppendRowChangedEvent(ctx, "", largeRow, nil)
+	require.True(t, cerror.ErrOpenProtocolCodecRowTooLarge.Equal(err))
 }
@@ -77,11 +77,37 @@ func newResolvedMessage(ts uint64) *internal.MessageKey {
 }
 
 func rowChangeToMsg(e *model.RowChangedEvent) (*internal.MessageKey, *messageRow) {
+	key := rowChangeToMsgKey(e)
+	value := &messageRow{}
+	if e.IsDelete() {
+		value.Delete = rowChangeColumns2CodecColumns(e.PreColumns)
+	} else {
+		value.Update = rowChangeColumns2CodecColumns(e.Columns)
+		value.PreColumns = rowChangeColumns2CodecColumns(e.PreColumns)
+	}
+	return key, value
+}
+
+// rowChangeToHandleKeyOnlyMsg is like rowChangeToMsg, but the message only
+// carries the handle key columns of the row.
+func rowChangeToHandleKeyOnlyMsg(e *model.RowChangedEvent) (*internal.MessageKey, *messageRow) {
+	key := rowChangeToMsgKey(e)
+	value := &messageRow{}
+	if e.IsDelete() {
+		value.Delete = rowChangeColumns2CodecColumns(handleKeyColumns(e.PreColumns))
+	} else {
+		value.Update = rowChangeColumns2CodecColumns(handleKeyColumns(e.Columns))
+		value.PreColumns = rowChangeColumns2CodecColumns(handleKeyColumns(e.PreColumns))
+	}
+	return key, value
+}
+
+func rowChangeToMsgKey(e *model.RowChangedEvent) *internal.MessageKey {
 	var partition *int64
 	if e.Table.IsPartition {
 		partition = &e.Table.TableID
 	}
-	key := &internal.MessageKey{
+	return &internal.MessageKey{
 		Ts:        e.CommitTs,
 		Schema:    e.Table.Schema,
 		Table:     e.Table.Table,
@@ -89,14 +115,16 @@ func rowChangeToMsg(e *model.RowChangedEvent) (*internal.MessageKey, *messageRow
 		Partition: partition,
 		Type:      model.MessageTypeRow,
 	}
-	value := &messageRow{}
-	if e.IsDelete() {
-		value.Delete = rowChangeColumns2CodecColumns(e.PreColumns)
-	} else {
-		value.Update = rowChangeColumns2CodecColumns(e.Columns)
-		value.PreColumns = rowChangeColumns2CodecColumns(e.PreColumns)
+}
+
+func handleKeyColumns(cols []*model.Column) []*model.Column {
+	result := make([]*model.Column, 0, len(cols))
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			result = append(result, col)
+		}
 	}
-	return key, value
+	return result
 }
 
 func msgToRowChange(key *internal.MessageKey, value *messageRow) *model.RowChangedEvent {
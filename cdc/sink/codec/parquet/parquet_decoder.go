@@ -0,0 +1,107 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+type batchDecoder struct {
+	tableInfo *model.TableInfo
+	// columns holds the values of the parquet file column by column.
+	columns [][]interface{}
+	numRows int
+	nextRow int
+}
+
+// NewBatchDecoder creates a new parquet BatchDecoder, the tableInfo
+// should be built from the TableDefinition of the encoded table.
+func NewBatchDecoder(tableInfo *model.TableInfo, value []byte) (codec.EventBatchDecoder, error) {
+	pf, err := buffer.NewBufferFile(value)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetDecodeFailed, err)
+	}
+	pr, err := reader.NewParquetColumnReader(pf, 1)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetDecodeFailed, err)
+	}
+	defer pr.ReadStop()
+
+	numColumns := len(tableInfo.Columns) + extraColumnsCnt
+	if len(pr.SchemaHandler.ValueColumns) != numColumns {
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+			"the column length of parquet file %d doesn't match that of tableInfo %d",
+			len(pr.SchemaHandler.ValueColumns), len(tableInfo.Columns))
+	}
+	numRows := pr.GetNumRows()
+	columns := make([][]interface{}, 0, numColumns)
+	for i := 0; i < numColumns; i++ {
+		values, _, _, err := pr.ReadColumnByIndex(int64(i), numRows)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrParquetDecodeFailed, err)
+		}
+		if int64(len(values)) != numRows {
+			return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+				"column %d has %d values, expected %d", i, len(values), numRows)
+		}
+		columns = append(columns, values)
+	}
+
+	return &batchDecoder{
+		tableInfo: tableInfo,
+		columns:   columns,
+		numRows:   int(numRows),
+	}, nil
+}
+
+// HasNext implements the EventBatchDecoder interface.
+func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
+	if b.nextRow >= b.numRows {
+		return model.MessageTypeUnknown, false, nil
+	}
+	return model.MessageTypeRow, true, nil
+}
+
+// NextResolvedEvent implements the EventBatchDecoder interface.
+func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
+	return 0, nil
+}
+
+// NextRowChangedEvent implements the EventBatchDecoder interface.
+func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
+	if b.nextRow >= b.numRows {
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack("no parquet row can be found")
+	}
+	record := make([]interface{}, 0, len(b.columns))
+	for _, values := range b.columns {
+		record = append(record, values[b.nextRow])
+	}
+	b.nextRow++
+
+	e, err := record2RowChangedEvent(record, b.tableInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return e, nil
+}
+
+// NextDDLEvent implements the EventBatchDecoder interface.
+func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
+	return nil, nil
+}
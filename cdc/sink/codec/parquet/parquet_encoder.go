@@ -0,0 +1,190 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/xitongsys/parquet-go/writer"
+	"go.uber.org/zap"
+)
+
+// BatchEncoder encodes the row changed events of a table into a parquet file.
+// Unlike the text protocols, parquet files can't be concatenated, so all the
+// events appended between two `Build` calls are written into one file.
+//
+// The zero dates (e.g. 0000-00-00) and other dates which can't be parsed as
+// a valid time can't be represented by the parquet DATE and TIMESTAMP types,
+// so they are written as NULL.
+type BatchEncoder struct {
+	// table is the table of the buffered events, all events appended
+	// between two `Build` calls must belong to the same table version.
+	table       *model.TableInfo
+	schema      *fileSchema
+	records     []interface{}
+	callbackBuf []func()
+}
+
+// AppendRowChangedEvent implements the EventBatchEncoder interface
+func (b *BatchEncoder) AppendRowChangedEvent(
+	_ context.Context,
+	_ string,
+	e *model.RowChangedEvent,
+	callback func(),
+) error {
+	if e.TableInfo == nil {
+		return cerror.ErrParquetEncodeFailed.GenWithStack(
+			"table info of %s is not found", e.Table)
+	}
+	if b.table == nil {
+		if err := b.resetSchema(e.TableInfo); err != nil {
+			return errors.Trace(err)
+		}
+	} else if b.table.TableName.Schema != e.TableInfo.TableName.Schema ||
+		b.table.TableName.Table != e.TableInfo.TableName.Table ||
+		b.table.Version != e.TableInfo.Version {
+		return cerror.ErrParquetEncodeFailed.GenWithStack(
+			"events of different tables can't be encoded into one parquet file, "+
+				"expected %s version %d, got %s version %d",
+			b.table.TableName, b.table.Version, e.TableInfo.TableName, e.TableInfo.Version)
+	}
+
+	record, err := rowChangedEvent2Record(b.schema, e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.records = append(b.records, record)
+	if callback != nil {
+		b.callbackBuf = append(b.callbackBuf, callback)
+	}
+	return nil
+}
+
+func (b *BatchEncoder) resetSchema(tableInfo *model.TableInfo) error {
+	var def cloudstorage.TableDefinition
+	def.FromTableInfo(tableInfo)
+	schema, err := newFileSchema(&def)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, col := range tableInfo.Columns {
+		if col.GetType() == mysql.TypeEnum || col.GetType() == mysql.TypeSet {
+			schema.elems[col.Name.O] = col.GetElems()
+		}
+	}
+	b.table = tableInfo
+	b.schema = schema
+	return nil
+}
+
+// EncodeDDLEvent implements the EventBatchEncoder interface
+func (b *BatchEncoder) EncodeDDLEvent(e *model.DDLEvent) (*common.Message, error) {
+	return nil, nil
+}
+
+// EncodeCheckpointEvent implements the EventBatchEncoder interface
+func (b *BatchEncoder) EncodeCheckpointEvent(ts uint64) (*common.Message, error) {
+	return nil, nil
+}
+
+// Build implements the EventBatchEncoder interface. The events are dropped if
+// the file can't be written, use BuildFile to get the error instead.
+func (b *BatchEncoder) Build() (messages []*common.Message) {
+	messages, err := b.BuildFile()
+	if err != nil {
+		log.Error("parquet encoding failed", zap.Error(err))
+		return nil
+	}
+	return messages
+}
+
+// BuildFile implements the FileEventBatchEncoder interface
+func (b *BatchEncoder) BuildFile() ([]*common.Message, error) {
+	if len(b.records) == 0 {
+		return nil, nil
+	}
+
+	value, err := b.writeFile()
+	if err != nil {
+		table := b.table.TableName
+		b.reset()
+		return nil, errors.Annotatef(err, "write parquet file of %s", table)
+	}
+	ret := common.NewMsg(config.ProtocolParquet, nil, value, 0, model.MessageTypeRow, nil, nil)
+	ret.SetRowsCount(len(b.records))
+	if len(b.callbackBuf) != 0 {
+		callbacks := b.callbackBuf
+		ret.Callback = func() {
+			for _, cb := range callbacks {
+				cb()
+			}
+		}
+	}
+
+	b.reset()
+	return []*common.Message{ret}, nil
+}
+
+func (b *BatchEncoder) reset() {
+	b.table = nil
+	b.schema = nil
+	b.records = nil
+	b.callbackBuf = make([]func(), 0)
+}
+
+func (b *BatchEncoder) writeFile() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w, err := writer.NewCSVWriterFromWriter(b.schema.metadata, buf, 1)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	for _, record := range b.records {
+		if err := w.Write(record); err != nil {
+			return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+		}
+	}
+	if err := w.WriteStop(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// newBatchEncoder creates a new parquet BatchEncoder.
+func newBatchEncoder() codec.FileEventBatchEncoder {
+	return &BatchEncoder{
+		callbackBuf: make([]func(), 0),
+	}
+}
+
+type batchEncoderBuilder struct{}
+
+// NewBatchEncoderBuilder creates a parquet batchEncoderBuilder.
+func NewBatchEncoderBuilder() codec.EncoderBuilder {
+	return &batchEncoderBuilder{}
+}
+
+// Build a parquet BatchEncoder
+func (b *batchEncoderBuilder) Build() codec.EventBatchEncoder {
+	return newBatchEncoder()
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/charset"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+	"github.com/stretchr/testify/require"
+)
+
+func newColumnInfo(
+	id int64, name string, tp byte, flen, decimal int, flag uint, chs string,
+) *timodel.ColumnInfo {
+	ft := types.NewFieldType(tp)
+	ft.SetFlen(flen)
+	ft.SetDecimal(decimal)
+	ft.SetFlag(flag)
+	ft.SetCharset(chs)
+	return &timodel.ColumnInfo{
+		ID:        id,
+		Name:      timodel.NewCIStr(name),
+		FieldType: *ft,
+	}
+}
+
+func newTestTableInfo() *model.TableInfo {
+	enumCol := newColumnInfo(11, "e", mysql.TypeEnum, 1, 0, 0, charset.CharsetUTF8MB4)
+	enumCol.SetElems([]string{"a", "b"})
+	return &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t"},
+		Version:   100,
+		TableInfo: &timodel.TableInfo{
+			Name: timodel.NewCIStr("t"),
+			Columns: []*timodel.ColumnInfo{
+				newColumnInfo(1, "id", mysql.TypeLonglong, 20, 0,
+					mysql.PriKeyFlag|mysql.NotNullFlag, charset.CharsetBin),
+				newColumnInfo(2, "name", mysql.TypeVarchar, 255, 0, 0, charset.CharsetUTF8MB4),
+				newColumnInfo(3, "data", mysql.TypeBlob, 65535, 0, 0, charset.CharsetBin),
+				newColumnInfo(4, "price", mysql.TypeNewDecimal, 10, 2, 0, charset.CharsetBin),
+				newColumnInfo(5, "big", mysql.TypeNewDecimal, 65, 30, 0, charset.CharsetBin),
+				newColumnInfo(6, "counter", mysql.TypeLonglong, 20, 0,
+					mysql.UnsignedFlag, charset.CharsetBin),
+				newColumnInfo(7, "score", mysql.TypeDouble, 22, -1, 0, charset.CharsetBin),
+				newColumnInfo(8, "born", mysql.TypeDate, 10, 0, 0, charset.CharsetBin),
+				newColumnInfo(9, "created", mysql.TypeDatetime, 23, 3, 0, charset.CharsetBin),
+				newColumnInfo(10, "dur", mysql.TypeDuration, 10, 0, 0, charset.CharsetBin),
+				enumCol,
+				newColumnInfo(12, "j", mysql.TypeJSON, 0, 0, 0, charset.CharsetBin),
+				newColumnInfo(13, "b", mysql.TypeBit, 8, 0, mysql.UnsignedFlag, charset.CharsetBin),
+				newColumnInfo(14, "remark", mysql.TypeLong, 11, 0, 0, charset.CharsetBin),
+			},
+		},
+	}
+}
+
+func newTestRow(tableInfo *model.TableInfo, commitTs uint64, id int64) *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		CommitTs:  commitTs,
+		Table:     &model.TableName{Schema: "test", Table: "t"},
+		TableInfo: tableInfo,
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Value: id},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte("测试")},
+			{Name: "data", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: []byte{0, 0xff, 1}},
+			{Name: "price", Type: mysql.TypeNewDecimal, Value: "-12.30"},
+			{
+				Name: "big", Type: mysql.TypeNewDecimal,
+				Value: "12345678901234567890123456789012345.123456789012345678901234567890",
+			},
+			{Name: "counter", Type: mysql.TypeLonglong, Flag: model.UnsignedFlag, Value: uint64(1) << 63},
+			{Name: "score", Type: mysql.TypeDouble, Value: float64(3.5)},
+			{Name: "born", Type: mysql.TypeDate, Value: "1000-01-01"},
+			{Name: "created", Type: mysql.TypeDatetime, Value: "2022-12-01 12:00:00.123"},
+			{Name: "dur", Type: mysql.TypeDuration, Value: "-838:59:59"},
+			{Name: "e", Type: mysql.TypeEnum, Value: uint64(2)},
+			{Name: "j", Type: mysql.TypeJSON, Value: `{"key": "value"}`},
+			{Name: "b", Type: mysql.TypeBit, Value: uint64(5)},
+			{Name: "remark", Type: mysql.TypeLong, Value: nil},
+		},
+	}
+}
+
+func TestParquetBatchCodec(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := newTestTableInfo()
+	insert := newTestRow(tableInfo, 433305438660591626, 1)
+	update := newTestRow(tableInfo, 433305438660591627, 2)
+	update.PreColumns = newTestRow(tableInfo, 0, 2).Columns
+	del := newTestRow(tableInfo, 433305438660591628, 3)
+	del.PreColumns, del.Columns = del.Columns, nil
+	rows := []*model.RowChangedEvent{insert, update, del}
+
+	encoder := newBatchEncoder()
+	count := 0
+	for _, row := range rows {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, func() { count++ })
+		require.Nil(t, err)
+	}
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+	require.Equal(t, len(rows), messages[0].GetRowsCount())
+	messages[0].Callback()
+	require.Equal(t, len(rows), count)
+	require.Nil(t, encoder.Build())
+
+	// the decoder rebuilds the table info from the table definition,
+	// the same as the storage consumer does.
+	var def cloudstorage.TableDefinition
+	def.FromTableInfo(tableInfo)
+	decodeTableInfo, err := def.ToTableInfo()
+	require.Nil(t, err)
+	decoder, err := NewBatchDecoder(decodeTableInfo, messages[0].Value)
+	require.Nil(t, err)
+
+	expectedValues := map[string]interface{}{
+		"name":    []byte("测试"),
+		"data":    []byte{0, 0xff, 1},
+		"price":   "-12.30",
+		"big":     "12345678901234567890123456789012345.123456789012345678901234567890",
+		"counter": uint64(1) << 63,
+		"score":   float64(3.5),
+		"born":    "1000-01-01",
+		"created": "2022-12-01 12:00:00.123",
+		"dur":     "-838:59:59",
+		"e":       "b",
+		"j":       `{"key": "value"}`,
+		"b":       uint64(5),
+		"remark":  nil,
+	}
+	for _, expected := range rows {
+		tp, hasNext, err := decoder.HasNext()
+		require.Nil(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, tp)
+
+		event, err := decoder.NextRowChangedEvent()
+		require.Nil(t, err)
+		require.Equal(t, expected.CommitTs, event.CommitTs)
+		require.Equal(t, expected.Table, event.Table)
+		require.Equal(t, expected.IsDelete(), event.IsDelete())
+		cols := event.Columns
+		expectedCols := expected.Columns
+		if expected.IsDelete() {
+			cols = event.PreColumns
+			expectedCols = expected.PreColumns
+		}
+		require.Len(t, cols, len(expectedCols))
+		for _, col := range cols {
+			if col.Name == "id" {
+				require.Equal(t, expectedCols[0].Value, col.Value)
+				require.True(t, col.Flag.IsPrimaryKey())
+				continue
+			}
+			require.Equal(t, expectedValues[col.Name], col.Value, col.Name)
+		}
+	}
+	_, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.False(t, hasNext)
+	_, err = decoder.NextRowChangedEvent()
+	require.NotNil(t, err)
+
+	_, err = NewBatchDecoder(decodeTableInfo, []byte("invalid"))
+	require.NotNil(t, err)
+}
+
+func TestParquetEncodeDifferentTables(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := newTestTableInfo()
+	encoder := newBatchEncoder()
+	err := encoder.AppendRowChangedEvent(
+		context.Background(), "", newTestRow(tableInfo, 1, 1), nil)
+	require.Nil(t, err)
+
+	newTableInfo := newTestTableInfo()
+	newTableInfo.Version = 101
+	err = encoder.AppendRowChangedEvent(
+		context.Background(), "", newTestRow(newTableInfo, 2, 2), nil)
+	require.ErrorContains(t, err, "events of different tables")
+
+	row := newTestRow(tableInfo, 3, 3)
+	row.TableInfo = nil
+	err = encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+	require.ErrorContains(t, err, "table info")
+
+	// the encoder can be reused for another table after building.
+	require.Len(t, encoder.Build(), 1)
+	err = encoder.AppendRowChangedEvent(
+		context.Background(), "", newTestRow(newTableInfo, 2, 2), nil)
+	require.Nil(t, err)
+	require.Len(t, encoder.Build(), 1)
+}
@@ -0,0 +1,414 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/charset"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
+)
+
+// Every parquet file starts with two extra columns before the table columns.
+const (
+	opColumnName       = "_tidb_op"
+	commitTsColumnName = "_tidb_commit_ts"
+	extraColumnsCnt    = 2
+)
+
+// operation specifies the operation type, which is the same as the csv protocol.
+const (
+	operationInsert = "I"
+	operationDelete = "D"
+	operationUpdate = "U"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	datetimeLayout = "2006-01-02 15:04:05"
+)
+
+// unixEpoch is used to convert the days since the unix epoch to the date.
+var unixEpoch = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const secondsPerDay = 24 * 60 * 60
+
+// fileSchema is the schema of a parquet file, which is derived from the
+// TableDefinition of the table.
+type fileSchema struct {
+	// metadata is the schema of the parquet file in the format of parquet-go tags.
+	metadata []string
+	columns  []*timodel.ColumnInfo
+	// elems holds the elements of the enum and set columns, which are
+	// not kept in the table definition. It's only used by the encoder.
+	elems map[string][]string
+}
+
+// newFileSchema derives the parquet schema from the table definition.
+// All columns are optional, since parquet-go only writes flat optional
+// columns for the records without struct tags.
+func newFileSchema(def *cloudstorage.TableDefinition) (*fileSchema, error) {
+	tableInfo, err := def.ToTableInfo()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+
+	s := &fileSchema{
+		metadata: make([]string, 0, len(tableInfo.Columns)+extraColumnsCnt),
+		columns:  tableInfo.Columns,
+		elems:    make(map[string][]string),
+	}
+	s.metadata = append(s.metadata,
+		columnMetadata(opColumnName, "type=BYTE_ARRAY, convertedtype=UTF8"),
+		columnMetadata(commitTsColumnName, "type=INT64, convertedtype=UINT_64"))
+	for _, col := range tableInfo.Columns {
+		if strings.ContainsAny(col.Name.O, ",=") {
+			return nil, cerror.ErrParquetEncodeFailed.GenWithStack(
+				"column name %s is not supported by the parquet protocol", col.Name.O)
+		}
+		s.metadata = append(s.metadata, columnMetadata(col.Name.O, columnType(&col.FieldType)))
+	}
+	return s, nil
+}
+
+func columnMetadata(name, tp string) string {
+	return fmt.Sprintf("name=%s, %s, repetitiontype=OPTIONAL", name, tp)
+}
+
+// columnType returns the parquet physical and logical types of a column.
+//
+// MySQL TIME is a duration which could be negative or longer than a day,
+// so it is stored as a string instead of the parquet TIME type.
+func columnType(ft *types.FieldType) string {
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong,
+		mysql.TypeLonglong, mysql.TypeYear:
+		if mysql.HasUnsignedFlag(ft.GetFlag()) {
+			return "type=INT64, convertedtype=UINT_64"
+		}
+		return "type=INT64, convertedtype=INT_64"
+	case mysql.TypeBit:
+		return "type=INT64, convertedtype=UINT_64"
+	case mysql.TypeFloat:
+		return "type=FLOAT"
+	case mysql.TypeDouble:
+		return "type=DOUBLE"
+	case mysql.TypeNewDecimal:
+		return fmt.Sprintf("type=BYTE_ARRAY, convertedtype=DECIMAL, precision=%d, scale=%d",
+			ft.GetFlen(), decimalScale(ft))
+	case mysql.TypeDate:
+		return "type=INT32, convertedtype=DATE"
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		return "type=INT64, convertedtype=TIMESTAMP_MICROS"
+	case mysql.TypeJSON:
+		return "type=BYTE_ARRAY, convertedtype=JSON"
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
+		mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if ft.GetCharset() == charset.CharsetBin {
+			return "type=BYTE_ARRAY"
+		}
+		return "type=BYTE_ARRAY, convertedtype=UTF8"
+	case mysql.TypeDuration, mysql.TypeEnum, mysql.TypeSet:
+		return "type=BYTE_ARRAY, convertedtype=UTF8"
+	default:
+		return "type=BYTE_ARRAY"
+	}
+}
+
+func decimalScale(ft *types.FieldType) int {
+	if ft.GetDecimal() == types.UnspecifiedLength {
+		return 0
+	}
+	return ft.GetDecimal()
+}
+
+// rowChangedEvent2Record converts a RowChangedEvent to a parquet record,
+// like the csv protocol, only the after columns are recorded for the update.
+func rowChangedEvent2Record(s *fileSchema, e *model.RowChangedEvent) ([]interface{}, error) {
+	op := operationInsert
+	cols := e.Columns
+	if e.IsDelete() {
+		op = operationDelete
+		cols = e.PreColumns
+	} else if e.IsUpdate() {
+		op = operationUpdate
+	}
+
+	values := make(map[string]*model.Column, len(cols))
+	for _, col := range cols {
+		// column could be nil in a condition described in
+		// https://github.com/pingcap/tiflow/issues/6198#issuecomment-1191132951
+		if col != nil {
+			values[col.Name] = col
+		}
+	}
+
+	record := make([]interface{}, 0, len(s.columns)+extraColumnsCnt)
+	record = append(record, op, int64(e.CommitTs))
+	for _, ticol := range s.columns {
+		col, ok := values[ticol.Name.O]
+		if !ok || col.Value == nil {
+			record = append(record, nil)
+			continue
+		}
+		value, err := columnValue2Parquet(col, &ticol.FieldType, s.elems[ticol.Name.O])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		record = append(record, value)
+	}
+	return record, nil
+}
+
+// columnValue2Parquet converts the value of a column to the parquet native value.
+func columnValue2Parquet(
+	col *model.Column, ft *types.FieldType, elems []string,
+) (interface{}, error) {
+	switch v := col.Value.(type) {
+	case int64:
+		return v, nil
+	case uint64:
+		switch col.Type {
+		case mysql.TypeEnum:
+			enum, err := types.ParseEnumValue(elems, v)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+			}
+			return enum.Name, nil
+		case mysql.TypeSet:
+			set, err := types.ParseSetValue(elems, v)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+			}
+			return set.Name, nil
+		default:
+			return int64(v), nil
+		}
+	case float32:
+		if col.Type == mysql.TypeDouble {
+			return float64(v), nil
+		}
+		return v, nil
+	case float64:
+		if col.Type == mysql.TypeFloat {
+			return float32(v), nil
+		}
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case string:
+		switch col.Type {
+		case mysql.TypeNewDecimal:
+			return decimal2Bytes(v, decimalScale(ft))
+		case mysql.TypeDate:
+			t, ok := parseTime(dateLayout, v)
+			if !ok {
+				return nil, nil
+			}
+			return int32(t.Unix() / secondsPerDay), nil
+		case mysql.TypeDatetime, mysql.TypeTimestamp:
+			t, ok := parseTime(datetimeLayout, v)
+			if !ok {
+				return nil, nil
+			}
+			return t.UnixMicro(), nil
+		default:
+			return v, nil
+		}
+	default:
+		return nil, cerror.ErrParquetEncodeFailed.GenWithStack(
+			"unexpected value %v of column %s", col.Value, col.Name)
+	}
+}
+
+// parseTime parses the time in UTC. It returns false for the zero dates, which
+// can't be represented in parquet and are written as NULL by the caller.
+func parseTime(layout, value string) (time.Time, bool) {
+	t, err := time.ParseInLocation(layout, value, time.UTC)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// decimal2Bytes encodes the decimal into the big-endian two's complement
+// representation of the unscaled value.
+func decimal2Bytes(value string, scale int) (string, error) {
+	dec := new(types.MyDecimal)
+	if err := dec.FromString([]byte(value)); err != nil {
+		return "", cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	if err := dec.Round(dec, scale, types.ModeHalfUp); err != nil {
+		return "", cerror.WrapError(cerror.ErrParquetEncodeFailed, err)
+	}
+	str := string(dec.ToString())
+	if scale > 0 {
+		// pad the fraction part to the scale, e.g. `1.2` with scale 3 is `1.200`.
+		idx := strings.IndexByte(str, '.')
+		if idx < 0 {
+			str += "." + strings.Repeat("0", scale)
+		} else if frac := len(str) - idx - 1; frac < scale {
+			str += strings.Repeat("0", scale-frac)
+		}
+	}
+	unscaled, ok := new(big.Int).SetString(strings.Replace(str, ".", "", 1), 10)
+	if !ok {
+		return "", cerror.ErrParquetEncodeFailed.GenWithStack("invalid decimal %s", value)
+	}
+
+	if unscaled.Sign() >= 0 {
+		b := unscaled.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return string(b), nil
+	}
+	// -n = ^(n-1) in two's complement.
+	b := new(big.Int).Sub(new(big.Int).Neg(unscaled), big.NewInt(1)).Bytes()
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	return string(b), nil
+}
+
+// bytes2Decimal is the reverse of decimal2Bytes.
+func bytes2Decimal(b []byte, scale int) string {
+	unscaled := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+
+	str := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if len(str) <= scale {
+			str = strings.Repeat("0", scale-len(str)+1) + str
+		}
+		str = str[:len(str)-scale] + "." + str[len(str)-scale:]
+	}
+	if unscaled.Sign() < 0 {
+		str = "-" + str
+	}
+	return str
+}
+
+// parquetValue2ColumnValue is the reverse of columnValue2Parquet.
+func parquetValue2ColumnValue(value interface{}, ft *types.FieldType) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case int32:
+		if ft.GetType() == mysql.TypeDate {
+			return unixEpoch.AddDate(0, 0, int(v)).Format(dateLayout), nil
+		}
+	case int64:
+		switch ft.GetType() {
+		case mysql.TypeDatetime, mysql.TypeTimestamp:
+			layout := datetimeLayout
+			if fsp := ft.GetDecimal(); fsp > 0 && fsp != types.UnspecifiedLength {
+				layout += "." + strings.Repeat("0", fsp)
+			}
+			return time.UnixMicro(v).UTC().Format(layout), nil
+		case mysql.TypeBit:
+			return uint64(v), nil
+		}
+		if mysql.HasUnsignedFlag(ft.GetFlag()) {
+			return uint64(v), nil
+		}
+		return v, nil
+	case float32:
+		return strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+	case float64:
+		return v, nil
+	case string:
+		switch ft.GetType() {
+		case mysql.TypeNewDecimal:
+			return bytes2Decimal([]byte(v), decimalScale(ft)), nil
+		case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeTinyBlob,
+			mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+			return []byte(v), nil
+		default:
+			return v, nil
+		}
+	}
+	return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+		"unexpected value %v for column type %d", value, ft.GetType())
+}
+
+// record2RowChangedEvent converts a parquet record to a RowChangedEvent,
+// the record must contain the extra columns and all the table columns.
+func record2RowChangedEvent(
+	record []interface{}, tableInfo *model.TableInfo,
+) (*model.RowChangedEvent, error) {
+	if len(record) != len(tableInfo.Columns)+extraColumnsCnt {
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack(
+			"the column length of parquet record %d doesn't match that of tableInfo %d",
+			len(record), len(tableInfo.Columns))
+	}
+	op, _ := record[0].(string)
+	commitTs, _ := record[1].(int64)
+
+	cols := make([]*model.Column, 0, len(tableInfo.Columns))
+	for idx, ticol := range tableInfo.Columns {
+		col := new(model.Column)
+		col.Type = ticol.GetType()
+		col.Charset = ticol.GetCharset()
+		col.Name = ticol.Name.O
+		if mysql.HasPriKeyFlag(ticol.GetFlag()) {
+			col.Flag.SetIsHandleKey()
+			col.Flag.SetIsPrimaryKey()
+		}
+		if mysql.HasUnsignedFlag(ticol.GetFlag()) {
+			col.Flag.SetIsUnsigned()
+		}
+		if ticol.GetCharset() == charset.CharsetBin {
+			col.Flag.SetIsBinary()
+		}
+		value, err := parquetValue2ColumnValue(record[idx+extraColumnsCnt], &ticol.FieldType)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		col.Value = value
+		cols = append(cols, col)
+	}
+
+	e := &model.RowChangedEvent{
+		CommitTs: uint64(commitTs),
+		Table: &model.TableName{
+			Schema: tableInfo.TableName.Schema,
+			Table:  tableInfo.TableName.Table,
+		},
+	}
+	switch op {
+	case operationDelete:
+		e.PreColumns = cols
+	case operationInsert, operationUpdate:
+		e.Columns = cols
+	default:
+		return nil, cerror.ErrParquetDecodeFailed.GenWithStack("invalid operation type %s", op)
+	}
+	return e, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalConversion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value    string
+		scale    int
+		expected []byte
+		decoded  string
+	}{
+		{value: "0", scale: 0, expected: []byte{0}, decoded: "0"},
+		{value: "1.5", scale: 2, expected: []byte{0, 150}, decoded: "1.50"},
+		{value: "-1", scale: 0, expected: []byte{0xff}, decoded: "-1"},
+		{value: "-1.28", scale: 2, expected: []byte{0x80}, decoded: "-1.28"},
+		{value: "-1.29", scale: 2, expected: []byte{0xff, 0x7f}, decoded: "-1.29"},
+		{value: "1.28", scale: 2, expected: []byte{0, 0x80}, decoded: "1.28"},
+		{value: "0.01", scale: 3, expected: []byte{10}, decoded: "0.010"},
+		{value: "-0.001", scale: 3, expected: []byte{0xff}, decoded: "-0.001"},
+	}
+	for _, tc := range testCases {
+		b, err := decimal2Bytes(tc.value, tc.scale)
+		require.Nil(t, err)
+		require.Equal(t, tc.expected, []byte(b), tc.value)
+		require.Equal(t, tc.decoded, bytes2Decimal([]byte(b), tc.scale), tc.value)
+	}
+
+	_, err := decimal2Bytes("abc", 0)
+	require.NotNil(t, err)
+}
+
+func TestZeroDateAsNull(t *testing.T) {
+	t.Parallel()
+
+	_, ok := parseTime(dateLayout, "0000-00-00")
+	require.False(t, ok)
+	_, ok = parseTime(datetimeLayout, "0000-00-00 00:00:00")
+	require.False(t, ok)
+
+	tm, ok := parseTime(dateLayout, "2022-01-02")
+	require.True(t, ok)
+	require.Equal(t, 2022, tm.Year())
+}
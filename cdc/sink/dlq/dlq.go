@@ -0,0 +1,228 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+)
+
+// Writer writes the row changed events which can not be written to the
+// downstream into the dead letter queue, so that the changefeed can keep
+// going, and the operators can inspect and replay them later.
+// It is safe for concurrent use.
+type Writer interface {
+	// WriteRowChangedEvents records the events with the error which
+	// prevents them from being written to the downstream.
+	WriteRowChangedEvents(ctx context.Context, cause error, rows ...*model.RowChangedEvent) error
+	// WriteMessages records the encoded messages which are rejected by
+	// the downstream MQ system for their content, e.g. they are larger
+	// than the limit of the broker, so that sending them again can never
+	// succeed. topic is the topic which the messages are sent to.
+	WriteMessages(ctx context.Context, cause error, topic string, msgs ...*common.Message) error
+	// Close closes the writer.
+	Close() error
+}
+
+// NewWriter creates a Writer according to the dead letter queue config.
+// It returns nil if the dead letter queue is not configured.
+func NewWriter(
+	ctx context.Context, cfg *config.DeadLetterQueueConfig, changefeedID model.ChangeFeedID,
+) (Writer, error) {
+	if cfg == nil || cfg.URI == "" {
+		return nil, nil
+	}
+	uri, err := url.Parse(cfg.URI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDeadLetterQueueInitFailed, err, "")
+	}
+	scheme := strings.ToLower(uri.Scheme)
+	switch {
+	case sink.IsKafkaScheme(scheme):
+		w, err := newKafkaWriter(ctx, uri, changefeedID)
+		if err != nil {
+			return nil, err
+		}
+		return w, nil
+	case sink.IsStorageScheme(scheme):
+		w, err := newStorageWriter(ctx, uri, changefeedID)
+		if err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	return nil, cerror.ErrDeadLetterQueueInitFailed.GenWithStackByArgs(uri.Redacted())
+}
+
+// Column is a column of the row changed event in the dead letter queue.
+type Column struct {
+	Name  string               `json:"name"`
+	Type  byte                 `json:"type"`
+	Flag  model.ColumnFlagType `json:"flag"`
+	Value interface{}          `json:"value"`
+}
+
+// Record is a row changed event recorded in the dead letter queue, which
+// is encoded in JSON.
+type Record struct {
+	Namespace  string   `json:"namespace"`
+	Changefeed string   `json:"changefeed"`
+	Schema     string   `json:"schema"`
+	Table      string   `json:"table"`
+	StartTs    uint64   `json:"start-ts"`
+	CommitTs   uint64   `json:"commit-ts"`
+	Columns    []Column `json:"columns,omitempty"`
+	PreColumns []Column `json:"pre-columns,omitempty"`
+	// Error is the error which prevents the event from being written
+	// to the downstream.
+	Error string `json:"error"`
+	// Time is the time when the event is recorded.
+	Time time.Time `json:"time"`
+}
+
+func newRecord(
+	changefeedID model.ChangeFeedID, row *model.RowChangedEvent, cause error, now time.Time,
+) *Record {
+	r := &Record{
+		Namespace:  changefeedID.Namespace,
+		Changefeed: changefeedID.ID,
+		StartTs:    row.StartTs,
+		CommitTs:   row.CommitTs,
+		Columns:    newColumns(row.Columns),
+		PreColumns: newColumns(row.PreColumns),
+		Time:       now,
+	}
+	if row.Table != nil {
+		r.Schema = row.Table.Schema
+		r.Table = row.Table.Table
+	}
+	if cause != nil {
+		r.Error = cause.Error()
+	}
+	return r
+}
+
+func newColumns(cols []*model.Column) []Column {
+	if len(cols) == 0 {
+		return nil
+	}
+	result := make([]Column, 0, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		value := col.Value
+		// the string values are stored as bytes, which are encoded
+		// as base64 strings by the json package.
+		if b, ok := value.([]byte); ok && !col.Flag.IsBinary() {
+			value = string(b)
+		}
+		result = append(result, Column{
+			Name:  col.Name,
+			Type:  col.Type,
+			Flag:  col.Flag,
+			Value: value,
+		})
+	}
+	return result
+}
+
+// MessageRecord is an encoded message recorded in the dead letter queue,
+// which is encoded in JSON. The key and value are kept as they are
+// encoded by the protocol of the changefeed.
+type MessageRecord struct {
+	Namespace  string `json:"namespace"`
+	Changefeed string `json:"changefeed"`
+	Topic      string `json:"topic"`
+	Protocol   string `json:"protocol"`
+	Schema     string `json:"schema,omitempty"`
+	Table      string `json:"table,omitempty"`
+	CommitTs   uint64 `json:"commit-ts"`
+	// RowsCount is the number of the row changed events in the message.
+	RowsCount int    `json:"rows-count"`
+	Key       []byte `json:"key,omitempty"`
+	Value     []byte `json:"value"`
+	// Error is the error which prevents the message from being sent
+	// to the downstream.
+	Error string `json:"error"`
+	// Time is the time when the message is recorded.
+	Time time.Time `json:"time"`
+}
+
+func newMessageRecord(
+	changefeedID model.ChangeFeedID, topic string, msg *common.Message, cause error, now time.Time,
+) *MessageRecord {
+	r := &MessageRecord{
+		Namespace:  changefeedID.Namespace,
+		Changefeed: changefeedID.ID,
+		Topic:      topic,
+		Protocol:   msg.Protocol.String(),
+		CommitTs:   msg.Ts,
+		RowsCount:  msg.GetRowsCount(),
+		Key:        msg.Key,
+		Value:      msg.Value,
+		Time:       now,
+	}
+	if msg.Schema != nil {
+		r.Schema = *msg.Schema
+	}
+	if msg.Table != nil {
+		r.Table = *msg.Table
+	}
+	if cause != nil {
+		r.Error = cause.Error()
+	}
+	return r
+}
+
+// encodeRecords encodes the events as JSON records.
+func encodeRecords(
+	changefeedID model.ChangeFeedID, cause error, rows []*model.RowChangedEvent,
+) ([][]byte, error) {
+	now := time.Now()
+	records := make([][]byte, 0, len(rows))
+	for _, row := range rows {
+		data, err := json.Marshal(newRecord(changefeedID, row, cause, now))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDeadLetterQueueWriteFailed, err)
+		}
+		records = append(records, data)
+	}
+	return records, nil
+}
+
+// encodeMessageRecords encodes the messages as JSON records.
+func encodeMessageRecords(
+	changefeedID model.ChangeFeedID, cause error, topic string, msgs []*common.Message,
+) ([][]byte, error) {
+	now := time.Now()
+	records := make([][]byte, 0, len(msgs))
+	for _, msg := range msgs {
+		data, err := json.Marshal(newMessageRecord(changefeedID, topic, msg, cause, now))
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDeadLetterQueueWriteFailed, err)
+		}
+		records = append(records, data)
+	}
+	return records, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	changefeedID := model.DefaultChangeFeedID("test")
+
+	w, err := NewWriter(ctx, nil, changefeedID)
+	require.NoError(t, err)
+	require.Nil(t, w)
+	w, err = NewWriter(ctx, &config.DeadLetterQueueConfig{}, changefeedID)
+	require.NoError(t, err)
+	require.Nil(t, w)
+
+	_, err = NewWriter(ctx, &config.DeadLetterQueueConfig{URI: "mysql://127.0.0.1:3306"}, changefeedID)
+	require.Regexp(t, ".*failed to initialize the dead letter queue.*", err)
+
+	w, err = NewWriter(ctx, &config.DeadLetterQueueConfig{URI: "file://" + t.TempDir()}, changefeedID)
+	require.NoError(t, err)
+	require.IsType(t, &storageWriter{}, w)
+	require.NoError(t, w.Close())
+}
+
+func TestStorageWriter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	changefeedID := model.DefaultChangeFeedID("test")
+	w, err := NewWriter(ctx, &config.DeadLetterQueueConfig{URI: "file://" + dir}, changefeedID)
+	require.NoError(t, err)
+	defer w.Close()
+
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:  1,
+			CommitTs: 2,
+			Table:    &model.TableName{Schema: "test", Table: "t"},
+			Columns: []*model.Column{
+				{Name: "a", Type: mysql.TypeLong, Value: int64(1)},
+				{Name: "b", Type: mysql.TypeVarchar, Value: []byte("x")},
+			},
+		},
+		{
+			StartTs:  1,
+			CommitTs: 3,
+			Table:    &model.TableName{Schema: "test", Table: "t"},
+			PreColumns: []*model.Column{
+				{Name: "a", Type: mysql.TypeLong, Value: int64(2)},
+				{Name: "b", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: []byte("y")},
+			},
+		},
+	}
+	require.NoError(t, w.WriteRowChangedEvents(ctx, errors.New("row too large"), rows...))
+
+	files, err := filepath.Glob(filepath.Join(dir, "default", "test", "3_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	f, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, records, 2)
+
+	require.Equal(t, "default", records[0].Namespace)
+	require.Equal(t, "test", records[0].Changefeed)
+	require.Equal(t, "test", records[0].Schema)
+	require.Equal(t, "t", records[0].Table)
+	require.Equal(t, uint64(2), records[0].CommitTs)
+	require.Equal(t, "row too large", records[0].Error)
+	require.Equal(t, "x", records[0].Columns[1].Value)
+	require.Nil(t, records[0].PreColumns)
+	// the binary values are encoded as base64 strings.
+	require.Equal(t, "eQ==", records[1].PreColumns[1].Value)
+	require.Equal(t, float64(2), records[1].PreColumns[0].Value)
+}
+
+func TestStorageWriterWriteMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	changefeedID := model.DefaultChangeFeedID("test")
+	w, err := NewWriter(ctx, &config.DeadLetterQueueConfig{URI: "file://" + dir}, changefeedID)
+	require.NoError(t, err)
+	defer w.Close()
+
+	schema, table := "test", "t"
+	msg := common.NewMsg(config.ProtocolCanalJSON, []byte("key"), []byte("value"),
+		5, model.MessageTypeRow, &schema, &table)
+	msg.SetRowsCount(2)
+	require.NoError(t, w.WriteMessages(ctx, errors.New("message too large"), "topic", msg))
+
+	files, err := filepath.Glob(filepath.Join(dir, "default", "test", "5_*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	var record MessageRecord
+	require.NoError(t, json.Unmarshal(data, &record))
+	require.Equal(t, "default", record.Namespace)
+	require.Equal(t, "test", record.Changefeed)
+	require.Equal(t, "topic", record.Topic)
+	require.Equal(t, "canal-json", record.Protocol)
+	require.Equal(t, "test", record.Schema)
+	require.Equal(t, "t", record.Table)
+	require.Equal(t, uint64(5), record.CommitTs)
+	require.Equal(t, 2, record.RowsCount)
+	require.Equal(t, []byte("key"), record.Key)
+	require.Equal(t, []byte("value"), record.Value)
+	require.Equal(t, "message too large", record.Error)
+}
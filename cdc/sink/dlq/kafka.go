@@ -0,0 +1,145 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// kafkaWriter writes the dead letter records to a kafka topic,
+// each record is a message keyed by the table name.
+type kafkaWriter struct {
+	changefeedID model.ChangeFeedID
+	topic        string
+	producer     sarama.SyncProducer
+}
+
+func newKafkaWriter(
+	ctx context.Context, uri *url.URL, changefeedID model.ChangeFeedID,
+) (*kafkaWriter, error) {
+	topic := strings.TrimFunc(uri.Path, func(r rune) bool {
+		return r == '/'
+	})
+	if topic == "" {
+		return nil, cerror.ErrDeadLetterQueueInitFailed.GenWithStackByArgs(uri.Redacted())
+	}
+
+	cfg := kafka.NewConfig()
+	if err := cfg.Apply(uri); err != nil {
+		return nil, cerror.WrapError(cerror.ErrDeadLetterQueueInitFailed, err, uri.Redacted())
+	}
+	saramaConfig, err := kafka.NewSaramaConfig(ctx, cfg)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDeadLetterQueueInitFailed, err, uri.Redacted())
+	}
+	// the sync producer requires both of them to be true.
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+	producer, err := sarama.NewSyncProducer(cfg.BrokerEndpoints, saramaConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDeadLetterQueueInitFailed, err, uri.Redacted())
+	}
+
+	log.Info("dead letter queue kafka writer created",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("topic", topic))
+	return &kafkaWriter{
+		changefeedID: changefeedID,
+		topic:        topic,
+		producer:     producer,
+	}, nil
+}
+
+// WriteRowChangedEvents implements Writer.
+func (w *kafkaWriter) WriteRowChangedEvents(
+	_ context.Context, cause error, rows ...*model.RowChangedEvent,
+) error {
+	records, err := encodeRecords(w.changefeedID, cause, rows)
+	if err != nil {
+		return err
+	}
+	msgs := make([]*sarama.ProducerMessage, 0, len(records))
+	for i, record := range records {
+		msg := &sarama.ProducerMessage{
+			Topic: w.topic,
+			Value: sarama.ByteEncoder(record),
+		}
+		if rows[i].Table != nil {
+			msg.Key = sarama.StringEncoder(rows[i].Table.String())
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := w.producer.SendMessages(msgs); err != nil {
+		return cerror.WrapError(cerror.ErrDeadLetterQueueWriteFailed, err)
+	}
+	log.Warn("row changed events are written to the dead letter queue",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("topic", w.topic),
+		zap.Int("count", len(rows)),
+		zap.Error(cause))
+	return nil
+}
+
+// WriteMessages implements Writer.
+// Each record carries the whole rejected message, so the records of the
+// messages larger than the limit of the dead letter topic are rejected too,
+// and the storage dead letter queue is preferred for such messages.
+func (w *kafkaWriter) WriteMessages(
+	_ context.Context, cause error, topic string, msgs ...*common.Message,
+) error {
+	records, err := encodeMessageRecords(w.changefeedID, cause, topic, msgs)
+	if err != nil {
+		return err
+	}
+	producerMsgs := make([]*sarama.ProducerMessage, 0, len(records))
+	for i, record := range records {
+		msg := &sarama.ProducerMessage{
+			Topic: w.topic,
+			Value: sarama.ByteEncoder(record),
+		}
+		if msgs[i].Schema != nil && msgs[i].Table != nil {
+			tableName := model.TableName{Schema: *msgs[i].Schema, Table: *msgs[i].Table}
+			msg.Key = sarama.StringEncoder(tableName.String())
+		}
+		producerMsgs = append(producerMsgs, msg)
+	}
+	if err := w.producer.SendMessages(producerMsgs); err != nil {
+		return cerror.WrapError(cerror.ErrDeadLetterQueueWriteFailed, err)
+	}
+	log.Warn("messages are written to the dead letter queue",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("topic", w.topic),
+		zap.String("sourceTopic", topic),
+		zap.Int("count", len(msgs)),
+		zap.Error(cause))
+	return nil
+}
+
+// Close implements Writer.
+func (w *kafkaWriter) Close() error {
+	return w.producer.Close()
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+)
+
+// MockWriter is a mocked dlq.Writer which records the events in memory.
+// Only used in test.
+type MockWriter struct {
+	mu     sync.Mutex
+	rows   []*model.RowChangedEvent
+	causes []error
+	msgs   []*common.Message
+	// msgCauses are the errors of msgs.
+	msgCauses []error
+	closed    bool
+}
+
+// NewMockWriter creates a new MockWriter instance.
+func NewMockWriter() *MockWriter {
+	return &MockWriter{}
+}
+
+// WriteRowChangedEvents implements dlq.Writer.WriteRowChangedEvents.
+func (w *MockWriter) WriteRowChangedEvents(
+	_ context.Context, cause error, rows ...*model.RowChangedEvent,
+) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, row := range rows {
+		w.rows = append(w.rows, row)
+		w.causes = append(w.causes, cause)
+	}
+	return nil
+}
+
+// WriteMessages implements dlq.Writer.WriteMessages.
+func (w *MockWriter) WriteMessages(
+	_ context.Context, cause error, _ string, msgs ...*common.Message,
+) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, msg := range msgs {
+		w.msgs = append(w.msgs, msg)
+		w.msgCauses = append(w.msgCauses, cause)
+	}
+	return nil
+}
+
+// Close implements dlq.Writer.Close.
+func (w *MockWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+// Rows returns the events written to the MockWriter and their errors.
+func (w *MockWriter) Rows() ([]*model.RowChangedEvent, []error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rows, w.causes
+}
+
+// Messages returns the messages written to the MockWriter and their errors.
+func (w *MockWriter) Messages() ([]*common.Message, []error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.msgs, w.msgCauses
+}
+
+// IsClosed returns true if the MockWriter is closed.
+func (w *MockWriter) IsClosed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tiflow/cdc/model"
+	rcommon "github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/uuid"
+	"go.uber.org/zap"
+)
+
+// storageWriter writes the dead letter records to an external storage.
+// The records of each write are stored in a new file, whose path is
+// {namespace}/{changefeed}/{max-commit-ts}_{uuid}.json, and the records
+// are separated by line breaks in the file.
+type storageWriter struct {
+	changefeedID model.ChangeFeedID
+	storage      storage.ExternalStorage
+	uuid         uuid.Generator
+}
+
+func newStorageWriter(
+	ctx context.Context, uri *url.URL, changefeedID model.ChangeFeedID,
+) (*storageWriter, error) {
+	bs, err := storage.ParseBackend(uri.String(), nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDeadLetterQueueInitFailed, err, uri.Redacted())
+	}
+	extStorage, err := storage.New(ctx, bs, &storage.ExternalStorageOptions{
+		SendCredentials: false,
+		S3Retryer:       rcommon.DefaultS3Retryer(),
+	})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrDeadLetterQueueInitFailed, err, uri.Redacted())
+	}
+
+	log.Info("dead letter queue storage writer created",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.String("uri", uri.Redacted()))
+	return &storageWriter{
+		changefeedID: changefeedID,
+		storage:      extStorage,
+		uuid:         uuid.NewGenerator(),
+	}, nil
+}
+
+// WriteRowChangedEvents implements Writer.
+func (w *storageWriter) WriteRowChangedEvents(
+	ctx context.Context, cause error, rows ...*model.RowChangedEvent,
+) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	records, err := encodeRecords(w.changefeedID, cause, rows)
+	if err != nil {
+		return err
+	}
+	var maxCommitTs uint64
+	for _, row := range rows {
+		if row.CommitTs > maxCommitTs {
+			maxCommitTs = row.CommitTs
+		}
+	}
+	path, err := w.writeRecords(ctx, maxCommitTs, records)
+	if err != nil {
+		return err
+	}
+	log.Warn("row changed events are written to the dead letter queue",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("path", path),
+		zap.Int("count", len(rows)),
+		zap.Error(cause))
+	return nil
+}
+
+// WriteMessages implements Writer.
+func (w *storageWriter) WriteMessages(
+	ctx context.Context, cause error, topic string, msgs ...*common.Message,
+) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	records, err := encodeMessageRecords(w.changefeedID, cause, topic, msgs)
+	if err != nil {
+		return err
+	}
+	var maxCommitTs uint64
+	for _, msg := range msgs {
+		if msg.Ts > maxCommitTs {
+			maxCommitTs = msg.Ts
+		}
+	}
+	path, err := w.writeRecords(ctx, maxCommitTs, records)
+	if err != nil {
+		return err
+	}
+	log.Warn("messages are written to the dead letter queue",
+		zap.String("namespace", w.changefeedID.Namespace),
+		zap.String("changefeed", w.changefeedID.ID),
+		zap.String("topic", topic),
+		zap.String("path", path),
+		zap.Int("count", len(msgs)),
+		zap.Error(cause))
+	return nil
+}
+
+// writeRecords writes the records into a new file and returns its path.
+func (w *storageWriter) writeRecords(
+	ctx context.Context, maxCommitTs uint64, records [][]byte,
+) (string, error) {
+	path := fmt.Sprintf("%s/%s/%d_%s.json",
+		w.changefeedID.Namespace, w.changefeedID.ID, maxCommitTs, w.uuid.NewString())
+	data := append(bytes.Join(records, []byte("\n")), '\n')
+	if err := w.storage.WriteFile(ctx, path, data); err != nil {
+		return "", cerror.WrapError(cerror.ErrDeadLetterQueueWriteFailed, err)
+	}
+	return path, nil
+}
+
+// Close implements Writer.
+func (w *storageWriter) Close() error {
+	return nil
+}
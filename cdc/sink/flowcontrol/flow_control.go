@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/pkg/container/queue"
 	"go.uber.org/zap"
 )
@@ -80,6 +81,16 @@ func NewTableFlowController(quota uint64, redoLogEnabled bool, splitTxn bool) *T
 	}
 }
 
+// WithCaptureQuota makes the flow controller also acquire from the memory quota
+// of the capture with the given priority. It must be called before Consume.
+func (c *TableFlowController) WithCaptureQuota(
+	capture *memquota.CaptureMemQuota, priority string,
+) *TableFlowController {
+	c.memoryQuota.capture = capture
+	c.memoryQuota.priority = priority
+	return c
+}
+
 // Consume is called when an event has arrived for being processed by the sink.
 // It will handle transaction boundaries automatically, and will not block intra-transaction.
 func (c *TableFlowController) Consume(
@@ -23,6 +23,8 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -848,3 +850,43 @@ func BenchmarkTableFlowController(B *testing.B) {
 		return nil
 	})
 }
+
+func TestFlowControlWithCaptureQuota(t *testing.T) {
+	t.Parallel()
+
+	capture, err := memquota.NewCaptureMemQuota(1024, 0)
+	require.NoError(t, err)
+	controller1 := NewTableFlowController(1024, false, false).
+		WithCaptureQuota(capture, config.ChangefeedPriorityHigh)
+	controller2 := NewTableFlowController(1024, false, false).
+		WithCaptureQuota(capture, config.ChangefeedPriorityHigh)
+
+	err = controller1.Consume(model.NewEmptyPolymorphicEvent(1), 800, dummyCallBackWithBatch)
+	require.NoError(t, err)
+	require.Equal(t, uint64(800), capture.UsedBytes())
+
+	// controller2 is blocked by the capture quota until controller1 releases.
+	callBacker := &mockCallBacker{}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := controller2.Consume(model.NewEmptyPolymorphicEvent(1), 300, callBacker.cb)
+		require.NoError(t, err)
+		require.Equal(t, 1, callBacker.timesCalled)
+		err = controller2.Consume(model.NewEmptyPolymorphicEvent(2), 1000, callBacker.cb)
+		require.Regexp(t, ".*ErrFlowControllerAborted.*", err)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	controller1.Release(model.NewResolvedTs(1))
+	require.Eventually(t, func() bool {
+		return capture.UsedBytes() == 300
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The memory of controller2 is returned to the capture once aborted.
+	time.Sleep(100 * time.Millisecond)
+	controller2.Abort()
+	wg.Wait()
+	require.Equal(t, uint64(0), capture.UsedBytes())
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
@@ -31,9 +32,18 @@ type tableMemoryQuota struct {
 
 	isAborted atomic.Bool
 
+	// capture is the memory quota shared by all changefeeds on the capture,
+	// nil means it is not limited.
+	capture *memquota.CaptureMemQuota
+	// priority is the priority of the changefeed when acquiring from capture.
+	priority string
+
 	consumed struct {
 		sync.Mutex
 		bytes uint64
+		// captureBytes is the memory acquired from the capture, it's returned
+		// to the capture once aborted.
+		captureBytes uint64
 	}
 
 	consumedCond *sync.Cond
@@ -60,13 +70,16 @@ func (c *tableMemoryQuota) consumeWithBlocking(nBytes uint64, blockCallBack func
 	}
 
 	c.consumed.Lock()
-	if c.consumed.bytes+nBytes >= c.quota {
+	// captured indicates whether nBytes has been acquired from the capture.
+	captured := false
+	if c.consumed.bytes+nBytes >= c.quota || !c.tryAcquireCapture(nBytes) {
 		c.consumed.Unlock()
 		err := blockCallBack()
 		if err != nil {
 			return errors.Trace(err)
 		}
 	} else {
+		captured = c.capture != nil
 		c.consumed.Unlock()
 	}
 
@@ -75,6 +88,7 @@ func (c *tableMemoryQuota) consumeWithBlocking(nBytes uint64, blockCallBack func
 
 	for {
 		if c.isAborted.Load() {
+			// The memory acquired from the capture has been returned by abort.
 			return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
 		}
 
@@ -85,9 +99,43 @@ func (c *tableMemoryQuota) consumeWithBlocking(nBytes uint64, blockCallBack func
 	}
 
 	c.consumed.bytes += nBytes
+	if c.capture == nil || captured {
+		return nil
+	}
+
+	// Wait for the capture quota without holding the lock, so that the memory
+	// of the table can still be released.
+	c.consumed.Unlock()
+	err := c.capture.BlockAcquire(nBytes, c.priority, c.isAborted.Load)
+	c.consumed.Lock()
+	if err == nil && c.isAborted.Load() {
+		// Aborted after the capture quota is acquired, return it here since
+		// abort has returned the acquired memory already.
+		c.capture.Release(nBytes)
+		err = cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
+	}
+	if err != nil {
+		c.consumed.bytes -= nBytes
+		c.consumedCond.Signal()
+		return err
+	}
+	c.consumed.captureBytes += nBytes
 	return nil
 }
 
+// tryAcquireCapture tries to acquire nBytes from the capture, it returns true
+// if the capture quota is not limited. It must be called with the lock held.
+func (c *tableMemoryQuota) tryAcquireCapture(nBytes uint64) bool {
+	if c.capture == nil {
+		return true
+	}
+	if !c.capture.TryAcquire(nBytes, c.priority) {
+		return false
+	}
+	c.consumed.captureBytes += nBytes
+	return true
+}
+
 // forceConsume is called when blocking is not acceptable and the limit can be violated
 // for the sake of avoid deadlock. It merely records the increased memory consumption.
 func (c *tableMemoryQuota) forceConsume(nBytes uint64) error {
@@ -99,6 +147,10 @@ func (c *tableMemoryQuota) forceConsume(nBytes uint64) error {
 	}
 
 	c.consumed.bytes += nBytes
+	if c.capture != nil {
+		c.capture.ForceAcquire(nBytes)
+		c.consumed.captureBytes += nBytes
+	}
 	return nil
 }
 
@@ -114,6 +166,10 @@ func (c *tableMemoryQuota) release(nBytes uint64) {
 	}
 
 	c.consumed.bytes -= nBytes
+	if c.capture != nil && !c.isAborted.Load() {
+		c.capture.Release(nBytes)
+		c.consumed.captureBytes -= nBytes
+	}
 	if c.consumed.bytes < c.quota {
 		c.consumed.Unlock()
 		c.consumedCond.Signal()
@@ -125,7 +181,16 @@ func (c *tableMemoryQuota) release(nBytes uint64) {
 
 // abort interrupts any ongoing consumeWithBlocking call
 func (c *tableMemoryQuota) abort() {
+	c.consumed.Lock()
 	c.isAborted.Store(true)
+	if c.capture != nil {
+		// The events of the table are dropped, return their memory to the
+		// capture and wake up the acquires blocked by the capture quota.
+		c.capture.Release(c.consumed.captureBytes)
+		c.consumed.captureBytes = 0
+		c.capture.Notify()
+	}
+	c.consumed.Unlock()
 	c.consumedCond.Signal()
 }
 
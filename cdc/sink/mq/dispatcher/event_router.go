@@ -14,6 +14,8 @@
 package dispatcher
 
 import (
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/log"
@@ -23,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/topic"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
 	"go.uber.org/zap"
 )
 
@@ -44,6 +47,7 @@ const (
 	partitionDispatchRuleTS
 	partitionDispatchRuleTable
 	partitionDispatchRuleIndexValue
+	partitionDispatchRuleColumns
 )
 
 func (r *partitionDispatchRule) fromString(rule string) {
@@ -59,9 +63,11 @@ func (r *partitionDispatchRule) fromString(rule string) {
 		log.Warn("rowid is deprecated, please use index-value instead.")
 	case "index-value":
 		*r = partitionDispatchRuleIndexValue
+	case config.PartitionRuleColumns:
+		*r = partitionDispatchRuleColumns
 	default:
 		*r = partitionDispatchRuleDefault
-		log.Warn("the partition dispatch rule is not default/ts/table/index-value," +
+		log.Warn("the partition dispatch rule is not default/ts/table/index-value/columns," +
 			" use the default rule instead.")
 	}
 }
@@ -70,7 +76,14 @@ func (r *partitionDispatchRule) fromString(rule string) {
 // an event should be dispatched to.
 type EventRouter struct {
 	defaultTopic string
-	rules        []struct {
+	// ddlTopicDispatcher is the dispatcher of the dedicated DDL topic,
+	// it is nil if the DDL topic is not configured.
+	ddlTopicDispatcher topic.Dispatcher
+	// changefeed and clusterID are the values of the {changefeed}
+	// and {cluster-id} placeholders in the topic expressions.
+	changefeed string
+	clusterID  string
+	rules      []struct {
 		partitionDispatcher partition.Dispatcher
 		topicDispatcher     topic.Dispatcher
 		filter.Filter
@@ -78,7 +91,10 @@ type EventRouter struct {
 }
 
 // NewEventRouter creates a new EventRouter.
-func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRouter, error) {
+func NewEventRouter(
+	cfg *config.ReplicaConfig, defaultTopic string,
+	changefeedID model.ChangeFeedID, upstreamID uint64,
+) (*EventRouter, error) {
 	// If an event does not match any dispatching rules in the config file,
 	// it will be dispatched by the default partition dispatcher and
 	// static topic dispatcher because it matches *.* rule.
@@ -102,7 +118,10 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 			f = filter.CaseInsensitive(f)
 		}
 
-		d := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		d, err := getPartitionDispatcher(ruleConfig, cfg.EnableOldValue)
+		if err != nil {
+			return nil, err
+		}
 		t, err := getTopicDispatcher(ruleConfig, defaultTopic, cfg.Sink.Protocol)
 		if err != nil {
 			return nil, err
@@ -114,44 +133,83 @@ func NewEventRouter(cfg *config.ReplicaConfig, defaultTopic string) (*EventRoute
 		}{partitionDispatcher: d, topicDispatcher: t, Filter: f})
 	}
 
+	var ddlTopicDispatcher topic.Dispatcher
+	if cfg.Sink.DDLTopic != "" {
+		topicExpr := topic.Expression(cfg.Sink.DDLTopic)
+		if err := topicExpr.ValidateForDDL(); err != nil {
+			return nil, err
+		}
+		if topicExpr.SplitsByEventType() {
+			log.Warn("the DDL events are sent to different topics by their types, "+
+				"the order of the DDL events can not be kept by the consumers",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID),
+				zap.String("ddlTopic", cfg.Sink.DDLTopic))
+		}
+		ddlTopicDispatcher = topic.NewDynamicTopicDispatcher(topicExpr)
+	}
+
 	return &EventRouter{
-		defaultTopic: defaultTopic,
-		rules:        rules,
+		defaultTopic:       defaultTopic,
+		ddlTopicDispatcher: ddlTopicDispatcher,
+		changefeed:         changefeedID.ID,
+		clusterID:          strconv.FormatUint(upstreamID, 10),
+		rules:              rules,
 	}, nil
 }
 
 // GetTopicForRowChange returns the target topic for row changes.
 func (s *EventRouter) GetTopicForRowChange(row *model.RowChangedEvent) string {
 	topicDispatcher, _ := s.matchDispatcher(row.Table.Schema, row.Table.Table)
-	return topicDispatcher.Substitute(row.Table.Schema, row.Table.Table)
+	return topicDispatcher.SubstituteValues(topic.Values{
+		Schema:     row.Table.Schema,
+		Table:      row.Table.Table,
+		Partition:  partitionName(row),
+		Changefeed: s.changefeed,
+		EventType:  rowEventType(row),
+		ClusterID:  s.clusterID,
+	})
 }
 
 // GetTopicForDDL returns the target topic for DDL.
+// If the DDL topic is configured, all the DDL events are sent to it.
 func (s *EventRouter) GetTopicForDDL(ddl *model.DDLEvent) string {
 	var schema, table string
 	if ddl.PreTableInfo != nil {
-		if ddl.PreTableInfo.TableName.Table == "" {
-			return s.defaultTopic
-		}
 		schema = ddl.PreTableInfo.TableName.Schema
 		table = ddl.PreTableInfo.TableName.Table
 	} else {
-		if ddl.TableInfo.TableName.Table == "" {
-			return s.defaultTopic
-		}
 		schema = ddl.TableInfo.TableName.Schema
 		table = ddl.TableInfo.TableName.Table
 	}
 
+	if s.ddlTopicDispatcher != nil {
+		return s.ddlTopicDispatcher.SubstituteValues(topic.Values{
+			Schema:     schema,
+			Table:      table,
+			Changefeed: s.changefeed,
+			EventType:  ddl.Type.String(),
+			ClusterID:  s.clusterID,
+		})
+	}
+	if table == "" {
+		return s.defaultTopic
+	}
 	topicDispatcher, _ := s.matchDispatcher(schema, table)
-	return topicDispatcher.Substitute(schema, table)
+	return topicDispatcher.SubstituteValues(topic.Values{
+		Schema:     schema,
+		Table:      table,
+		Changefeed: s.changefeed,
+		EventType:  ddl.Type.String(),
+		ClusterID:  s.clusterID,
+	})
 }
 
 // GetPartitionForRowChange returns the target partition for row changes.
 func (s *EventRouter) GetPartitionForRowChange(
 	row *model.RowChangedEvent,
 	partitionNum int32,
-) int32 {
+) (int32, error) {
 	_, partitionDispatcher := s.matchDispatcher(
 		row.Table.Schema, row.Table.Table,
 	)
@@ -161,6 +219,72 @@ func (s *EventRouter) GetPartitionForRowChange(
 	)
 }
 
+// RowPartition is a row changed event and the partition it's dispatched to.
+type RowPartition struct {
+	Row       *model.RowChangedEvent
+	Partition int32
+}
+
+// GetPartitionsForRowChange returns the target partitions for row changes.
+// An update event which changes the values that the partition depends on,
+// such as the handle key or the columns of the columns partition rule, is
+// split into a delete event and an insert event, which are dispatched to the
+// partitions of the old values and the new values respectively. So all the
+// events of the same key are always dispatched to the same partition.
+func (s *EventRouter) GetPartitionsForRowChange(
+	row *model.RowChangedEvent,
+	partitionNum int32,
+) ([]RowPartition, error) {
+	_, partitionDispatcher := s.matchDispatcher(
+		row.Table.Schema, row.Table.Table,
+	)
+	partition, err := partitionDispatcher.DispatchRowChangedEvent(row, partitionNum)
+	if err != nil {
+		return nil, err
+	}
+	if !row.IsUpdate() {
+		return []RowPartition{{Row: row, Partition: partition}}, nil
+	}
+
+	deleteRow, insertRow := splitUpdateEvent(row)
+	prePartition, err := partitionDispatcher.DispatchRowChangedEvent(deleteRow, partitionNum)
+	if err != nil {
+		return nil, err
+	}
+	if prePartition == partition {
+		return []RowPartition{{Row: row, Partition: partition}}, nil
+	}
+	return []RowPartition{
+		{Row: deleteRow, Partition: prePartition},
+		{Row: insertRow, Partition: partition},
+	}, nil
+}
+
+// splitUpdateEvent splits an update event into a delete event with the old
+// values and an insert event with the new values.
+func splitUpdateEvent(row *model.RowChangedEvent) (*model.RowChangedEvent, *model.RowChangedEvent) {
+	deleteRow := *row
+	deleteRow.Columns = nil
+	insertRow := *row
+	insertRow.PreColumns = nil
+	return &deleteRow, &insertRow
+}
+
+// VerifyTables checks whether the dispatch rules are valid for the tables.
+func (s *EventRouter) VerifyTables(tableInfos []*model.TableInfo) error {
+	for _, tableInfo := range tableInfos {
+		_, partitionDispatcher := s.matchDispatcher(
+			tableInfo.TableName.Schema, tableInfo.TableName.Table,
+		)
+		if d, ok := partitionDispatcher.(*partition.ColumnsDispatcher); ok {
+			if err := d.VerifyTable(tableInfo); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // GetDLLDispatchRuleByProtocol returns the DDL
 // distribution rule according to the protocol.
 func (s *EventRouter) GetDLLDispatchRuleByProtocol(
@@ -174,22 +298,24 @@ func (s *EventRouter) GetDLLDispatchRuleByProtocol(
 
 // GetActiveTopics returns a list of the corresponding topics
 // for the tables that are actively synchronized.
-func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
+func (s *EventRouter) GetActiveTopics(activeTables []*model.TableInfo) []string {
 	topics := make([]string, 0)
 	topicsMap := make(map[string]bool, len(activeTables))
 	for _, table := range activeTables {
-		topicDispatcher, _ := s.matchDispatcher(table.Schema, table.Table)
-		topicName := topicDispatcher.Substitute(table.Schema, table.Table)
-		if topicName == s.defaultTopic {
-			log.Debug("topic name corresponding to the table is the same as the default topic name",
-				zap.String("table", table.String()),
-				zap.String("defaultTopic", s.defaultTopic),
-				zap.String("topicDispatcherExpression", topicDispatcher.String()),
-			)
-		}
-		if !topicsMap[topicName] {
-			topicsMap[topicName] = true
-			topics = append(topics, topicName)
+		topicDispatcher, _ := s.matchDispatcher(table.TableName.Schema, table.TableName.Table)
+		for _, values := range s.activeTopicValues(table) {
+			topicName := topicDispatcher.SubstituteValues(values)
+			if topicName == s.defaultTopic {
+				log.Debug("topic name corresponding to the table is the same as the default topic name",
+					zap.String("table", table.TableName.String()),
+					zap.String("defaultTopic", s.defaultTopic),
+					zap.String("topicDispatcherExpression", topicDispatcher.String()),
+				)
+			}
+			if !topicsMap[topicName] {
+				topicsMap[topicName] = true
+				topics = append(topics, topicName)
+			}
 		}
 	}
 
@@ -201,6 +327,33 @@ func (s *EventRouter) GetActiveTopics(activeTables []model.TableName) []string {
 	return topics
 }
 
+// activeTopicValues returns the placeholder values of all the topics which
+// the row changed events of the table can be dispatched to.
+func (s *EventRouter) activeTopicValues(table *model.TableInfo) []topic.Values {
+	partitions := []string{""}
+	// The partitions are unknown if the table info is missing.
+	if table.TableInfo != nil && table.GetPartitionInfo() != nil {
+		partitions = partitions[:0]
+		for _, def := range table.GetPartitionInfo().Definitions {
+			partitions = append(partitions, def.Name.O)
+		}
+	}
+	values := make([]topic.Values, 0, len(partitions)*len(rowEventTypes))
+	for _, partition := range partitions {
+		for _, eventType := range rowEventTypes {
+			values = append(values, topic.Values{
+				Schema:     table.TableName.Schema,
+				Table:      table.TableName.Table,
+				Partition:  partition,
+				Changefeed: s.changefeed,
+				EventType:  eventType,
+				ClusterID:  s.clusterID,
+			})
+		}
+	}
+	return values
+}
+
 // GetDefaultTopic returns the default topic name.
 func (s *EventRouter) GetDefaultTopic() string {
 	return s.defaultTopic
@@ -221,10 +374,67 @@ func (s *EventRouter) matchDispatcher(
 	return nil, nil
 }
 
+// VerifyTables checks whether the dispatch rules of the MQ sink are valid
+// for the tables. It does nothing if the sink isn't a MQ sink.
+func VerifyTables(
+	sinkURI string, cfg *config.ReplicaConfig, tableInfos []*model.TableInfo,
+) error {
+	uri, err := url.Parse(sinkURI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if !sink.IsMQScheme(strings.ToLower(uri.Scheme)) {
+		return nil
+	}
+	router, err := NewEventRouter(cfg, "", model.ChangeFeedID{}, 0)
+	if err != nil {
+		return err
+	}
+	return router.VerifyTables(tableInfos)
+}
+
+// The values of the {event-type} placeholder of the row changed events.
+const (
+	rowEventTypeInsert = "insert"
+	rowEventTypeUpdate = "update"
+	rowEventTypeDelete = "delete"
+)
+
+var rowEventTypes = []string{rowEventTypeInsert, rowEventTypeUpdate, rowEventTypeDelete}
+
+func rowEventType(row *model.RowChangedEvent) string {
+	if row.IsDelete() {
+		return rowEventTypeDelete
+	}
+	if row.IsUpdate() {
+		return rowEventTypeUpdate
+	}
+	return rowEventTypeInsert
+}
+
+// partitionName returns the name of the partition the row belongs to,
+// or an empty string if the table is not a partitioned table.
+func partitionName(row *model.RowChangedEvent) string {
+	if !row.Table.IsPartition {
+		return ""
+	}
+	if row.TableInfo != nil && row.TableInfo.TableInfo != nil {
+		if pi := row.TableInfo.GetPartitionInfo(); pi != nil {
+			for _, def := range pi.Definitions {
+				if def.ID == row.Table.TableID {
+					return def.Name.O
+				}
+			}
+		}
+	}
+	// It should not happen, use the physical table ID as the partition name.
+	return strconv.FormatInt(row.Table.TableID, 10)
+}
+
 // getPartitionDispatcher returns the partition dispatcher for a specific partition rule.
 func getPartitionDispatcher(
 	ruleConfig *config.DispatchRule, enableOldValue bool,
-) partition.Dispatcher {
+) (partition.Dispatcher, error) {
 	var (
 		d    partition.Dispatcher
 		rule partitionDispatchRule
@@ -232,12 +442,16 @@ func getPartitionDispatcher(
 	rule.fromString(ruleConfig.PartitionRule)
 	switch rule {
 	case partitionDispatchRuleIndexValue:
-		if enableOldValue {
-			log.Warn("This index-value distribution mode " +
-				"does not guarantee row-level orderliness when " +
-				"switching on the old value, so please use caution!")
-		}
 		d = partition.NewIndexValueDispatcher()
+	case partitionDispatchRuleColumns:
+		// The delete events only carry the handle key columns
+		// if the old value is disabled.
+		if !enableOldValue {
+			return nil, cerror.ErrDispatcherFailed.GenWithStack(
+				"the columns partition rule requires the old value to be enabled: %v",
+				ruleConfig.Matcher)
+		}
+		d = partition.NewColumnsDispatcher(ruleConfig.Columns)
 	case partitionDispatchRuleTS:
 		d = partition.NewTsDispatcher()
 	case partitionDispatchRuleTable:
@@ -246,7 +460,7 @@ func getPartitionDispatcher(
 		d = partition.NewDefaultDispatcher(enableOldValue)
 	}
 
-	return d
+	return d, nil
 }
 
 // getTopicDispatcher returns the topic dispatcher for a specific topic rule (aka topic expression).
@@ -278,5 +492,11 @@ func getTopicDispatcher(
 			}
 		}
 	}
+	if topicExpr.SplitsByEventType() {
+		log.Warn("the row changed events are sent to different topics by their types, "+
+			"the order of the events of the same key can not be kept by the consumers",
+			zap.Strings("matcher", ruleConfig.Matcher),
+			zap.String("topic", ruleConfig.TopicRule))
+	}
 	return topic.NewDynamicTopicDispatcher(topicExpr), nil
 }
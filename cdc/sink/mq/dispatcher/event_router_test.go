@@ -27,7 +27,7 @@ import (
 func TestEventRouter(t *testing.T) {
 	t.Parallel()
 
-	d, err := NewEventRouter(config.GetDefaultReplicaConfig(), "test")
+	d, err := NewEventRouter(config.GetDefaultReplicaConfig(), "test", model.ChangeFeedID{}, 0)
 	require.Nil(t, err)
 	require.Equal(t, "test", d.GetDefaultTopic())
 	topicDispatcher, partitionDispatcher := d.matchDispatcher("test", "test")
@@ -67,7 +67,7 @@ func TestEventRouter(t *testing.T) {
 				},
 			},
 		},
-	}, "")
+	}, "", model.ChangeFeedID{}, 0)
 	require.Nil(t, err)
 	topicDispatcher, partitionDispatcher = d.matchDispatcher("test", "table1")
 	require.IsType(t, &topic.DynamicTopicDispatcher{}, topicDispatcher)
@@ -134,17 +134,17 @@ func TestGetActiveTopics(t *testing.T) {
 				},
 			},
 		},
-	}, "test")
+	}, "test", model.ChangeFeedID{}, 0)
 	require.Nil(t, err)
-	names := []model.TableName{
-		{Schema: "test_default1", Table: "table"},
-		{Schema: "test_default2", Table: "table"},
-		{Schema: "test_table", Table: "table"},
-		{Schema: "test_index_value", Table: "table"},
-		{Schema: "test", Table: "table"},
-		{Schema: "sbs", Table: "table"},
+	tables := []*model.TableInfo{
+		{TableName: model.TableName{Schema: "test_default1", Table: "table"}},
+		{TableName: model.TableName{Schema: "test_default2", Table: "table"}},
+		{TableName: model.TableName{Schema: "test_table", Table: "table"}},
+		{TableName: model.TableName{Schema: "test_index_value", Table: "table"}},
+		{TableName: model.TableName{Schema: "test", Table: "table"}},
+		{TableName: model.TableName{Schema: "sbs", Table: "table"}},
 	}
-	topics := d.GetActiveTopics(names)
+	topics := d.GetActiveTopics(tables)
 	require.Equal(t, []string{"test", "hello_test_table_world", "test_index_value_world", "hello_test", "sbs_table"}, topics)
 }
 
@@ -184,7 +184,7 @@ func TestGetTopicForRowChange(t *testing.T) {
 				},
 			},
 		},
-	}, "test")
+	}, "test", model.ChangeFeedID{}, 0)
 	require.Nil(t, err)
 
 	topicName := d.GetTopicForRowChange(&model.RowChangedEvent{
@@ -245,10 +245,10 @@ func TestGetPartitionForRowChange(t *testing.T) {
 				},
 			},
 		},
-	}, "test")
+	}, "test", model.ChangeFeedID{}, 0)
 	require.Nil(t, err)
 
-	p := d.GetPartitionForRowChange(&model.RowChangedEvent{
+	p, err := d.GetPartitionForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "test_default1", Table: "table"},
 		Columns: []*model.Column{
 			{
@@ -259,8 +259,9 @@ func TestGetPartitionForRowChange(t *testing.T) {
 		},
 		IndexColumns: [][]int{{0}},
 	}, 16)
+	require.Nil(t, err)
 	require.Equal(t, int32(10), p)
-	p = d.GetPartitionForRowChange(&model.RowChangedEvent{
+	p, err = d.GetPartitionForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "test_default2", Table: "table"},
 		Columns: []*model.Column{
 			{
@@ -271,14 +272,16 @@ func TestGetPartitionForRowChange(t *testing.T) {
 		},
 		IndexColumns: [][]int{{0}},
 	}, 16)
+	require.Nil(t, err)
 	require.Equal(t, int32(4), p)
 
-	p = d.GetPartitionForRowChange(&model.RowChangedEvent{
+	p, err = d.GetPartitionForRowChange(&model.RowChangedEvent{
 		Table:    &model.TableName{Schema: "test_table", Table: "table"},
 		CommitTs: 1,
 	}, 16)
+	require.Nil(t, err)
 	require.Equal(t, int32(15), p)
-	p = d.GetPartitionForRowChange(&model.RowChangedEvent{
+	p, err = d.GetPartitionForRowChange(&model.RowChangedEvent{
 		Table: &model.TableName{Schema: "test_index_value", Table: "table"},
 		Columns: []*model.Column{
 			{
@@ -292,14 +295,126 @@ func TestGetPartitionForRowChange(t *testing.T) {
 			},
 		},
 	}, 10)
+	require.Nil(t, err)
 	require.Equal(t, int32(1), p)
-	p = d.GetPartitionForRowChange(&model.RowChangedEvent{
+	p, err = d.GetPartitionForRowChange(&model.RowChangedEvent{
 		Table:    &model.TableName{Schema: "a", Table: "table"},
 		CommitTs: 1,
 	}, 2)
+	require.Nil(t, err)
 	require.Equal(t, int32(1), p)
 }
 
+func TestGetPartitionsForRowChange(t *testing.T) {
+	t.Parallel()
+
+	d, err := NewEventRouter(&config.ReplicaConfig{
+		EnableOldValue: true,
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"test.*"},
+					PartitionRule: "columns",
+					Columns:       []string{"tenant_id"},
+				},
+			},
+		},
+	}, "test", model.ChangeFeedID{}, 0)
+	require.Nil(t, err)
+
+	newColumns := func(id, tenantID int) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Value: id, Flag: model.HandleKeyFlag},
+			{Name: "tenant_id", Value: tenantID},
+		}
+	}
+	table := &model.TableName{Schema: "test", Table: "t"}
+	insert := &model.RowChangedEvent{Table: table, Columns: newColumns(1, 100)}
+	partitions, err := d.GetPartitionsForRowChange(insert, 16)
+	require.Nil(t, err)
+	require.Len(t, partitions, 1)
+	require.Equal(t, insert, partitions[0].Row)
+	oldPartition := partitions[0].Partition
+
+	// the update event is not split if the tenant_id isn't changed.
+	update := &model.RowChangedEvent{
+		Table: table, PreColumns: newColumns(1, 100), Columns: newColumns(2, 100),
+	}
+	partitions, err = d.GetPartitionsForRowChange(update, 16)
+	require.Nil(t, err)
+	require.Len(t, partitions, 1)
+	require.Equal(t, update, partitions[0].Row)
+
+	// the update event is split if the tenant_id is dispatched to another partition.
+	var newTenantID int
+	for newTenantID = 101; ; newTenantID++ {
+		p, err := d.GetPartitionForRowChange(&model.RowChangedEvent{
+			Table: table, Columns: newColumns(1, newTenantID),
+		}, 16)
+		require.Nil(t, err)
+		if p != oldPartition {
+			break
+		}
+	}
+	update = &model.RowChangedEvent{
+		Table: table, PreColumns: newColumns(1, 100), Columns: newColumns(1, newTenantID),
+	}
+	partitions, err = d.GetPartitionsForRowChange(update, 16)
+	require.Nil(t, err)
+	require.Len(t, partitions, 2)
+	require.True(t, partitions[0].Row.IsDelete())
+	require.Equal(t, update.PreColumns, partitions[0].Row.PreColumns)
+	require.Equal(t, oldPartition, partitions[0].Partition)
+	require.True(t, partitions[1].Row.IsInsert())
+	require.Equal(t, update.Columns, partitions[1].Row.Columns)
+	require.NotEqual(t, oldPartition, partitions[1].Partition)
+
+	// the column is dropped.
+	_, err = d.GetPartitionsForRowChange(&model.RowChangedEvent{
+		Table: table, Columns: newColumns(1, 100)[:1],
+	}, 16)
+	require.ErrorContains(t, err, "column tenant_id is not found in table test.t")
+}
+
+func TestColumnsPartitionRule(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.ReplicaConfig{
+		Sink: &config.SinkConfig{
+			DispatchRules: []*config.DispatchRule{
+				{
+					Matcher:       []string{"test.*"},
+					PartitionRule: "columns",
+					Columns:       []string{"tenant_id"},
+				},
+			},
+		},
+	}
+	_, err := NewEventRouter(cfg, "test", model.ChangeFeedID{}, 0)
+	require.ErrorContains(t, err, "requires the old value to be enabled")
+
+	cfg.EnableOldValue = true
+	d, err := NewEventRouter(cfg, "test", model.ChangeFeedID{}, 0)
+	require.Nil(t, err)
+	newTableInfo := func(schema, table string, columns ...string) *model.TableInfo {
+		info := &model.TableInfo{
+			TableName: model.TableName{Schema: schema, Table: table},
+			TableInfo: &timodel.TableInfo{},
+		}
+		for _, col := range columns {
+			info.Columns = append(info.Columns, &timodel.ColumnInfo{Name: timodel.NewCIStr(col)})
+		}
+		return info
+	}
+	require.Nil(t, d.VerifyTables([]*model.TableInfo{
+		newTableInfo("test", "t1", "id", "tenant_id"),
+		newTableInfo("other", "t1", "id"),
+	}))
+	require.ErrorContains(t, d.VerifyTables([]*model.TableInfo{
+		newTableInfo("test", "t2", "id"),
+	}), "column tenant_id is not found in table test.t2")
+}
+
 func TestGetDLLDispatchRuleByProtocol(t *testing.T) {
 	t.Parallel()
 
@@ -313,7 +428,7 @@ func TestGetDLLDispatchRuleByProtocol(t *testing.T) {
 				},
 			},
 		},
-	}, "test")
+	}, "test", model.ChangeFeedID{}, 0)
 	require.Nil(t, err)
 
 	tests := []struct {
@@ -374,7 +489,7 @@ func TestGetTopicForDDL(t *testing.T) {
 				},
 			},
 		},
-	}, "test")
+	}, "test", model.ChangeFeedID{}, 0)
 	require.Nil(t, err)
 
 	tests := []struct {
@@ -487,3 +602,90 @@ func TestGetTopicForDDL(t *testing.T) {
 		require.Equal(t, test.expectedTopic, d.GetTopicForDDL(test.ddl))
 	}
 }
+
+func TestTopicPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.DispatchRules = []*config.DispatchRule{
+		{
+			Matcher:   []string{"test.*"},
+			TopicRule: "{cluster-id}.{changefeed}.{schema}.{table}.{partition}.{event-type}",
+		},
+	}
+	d, err := NewEventRouter(cfg, "default",
+		model.ChangeFeedID{Namespace: "default", ID: "cf"}, 7)
+	require.Nil(t, err)
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t", TableID: 100},
+		TableInfo: &timodel.TableInfo{
+			ID: 100,
+			Partition: &timodel.PartitionInfo{
+				Enable: true,
+				Definitions: []timodel.PartitionDefinition{
+					{ID: 101, Name: timodel.NewCIStr("p0")},
+					{ID: 102, Name: timodel.NewCIStr("p1")},
+				},
+			},
+		},
+	}
+	cols := []*model.Column{{Name: "id", Value: 1}}
+	row := &model.RowChangedEvent{
+		Table:     &model.TableName{Schema: "test", Table: "t", TableID: 102, IsPartition: true},
+		TableInfo: tableInfo,
+		Columns:   cols,
+	}
+	require.Equal(t, "7.cf.test.t.p1.insert", d.GetTopicForRowChange(row))
+	row.PreColumns = cols
+	require.Equal(t, "7.cf.test.t.p1.update", d.GetTopicForRowChange(row))
+	row.Columns = nil
+	require.Equal(t, "7.cf.test.t.p1.delete", d.GetTopicForRowChange(row))
+
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "test", Table: "t1", TableID: 103},
+		Columns: cols,
+	}
+	require.Equal(t, "7.cf.test.t1..insert", d.GetTopicForRowChange(row))
+
+	topics := d.GetActiveTopics([]*model.TableInfo{tableInfo})
+	require.Equal(t, []string{
+		"7.cf.test.t.p0.insert", "7.cf.test.t.p0.update", "7.cf.test.t.p0.delete",
+		"7.cf.test.t.p1.insert", "7.cf.test.t.p1.update", "7.cf.test.t.p1.delete",
+		"default",
+	}, topics)
+
+	cfg.Sink.Protocol = config.ProtocolOpen.String()
+	cfg.Sink.DispatchRules[0].TopicRule = "{schema}_{unknown}"
+	_, err = NewEventRouter(cfg, "default", model.ChangeFeedID{}, 0)
+	require.ErrorContains(t, err, "invalid topic expression")
+}
+
+func TestDDLTopic(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.*"}, TopicRule: "{schema}_{table}"},
+	}
+	cfg.Sink.DDLTopic = "{changefeed}_ddl_{event-type}"
+	d, err := NewEventRouter(cfg, "default", model.ChangeFeedID{ID: "cf"}, 0)
+	require.Nil(t, err)
+
+	require.Equal(t, "cf_ddl_create_table", d.GetTopicForDDL(&model.DDLEvent{
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test", Table: "t"}},
+		Type:      timodel.ActionCreateTable,
+	}))
+	require.Equal(t, "cf_ddl_drop_schema", d.GetTopicForDDL(&model.DDLEvent{
+		TableInfo: &model.TableInfo{TableName: model.TableName{Schema: "test"}},
+		Type:      timodel.ActionDropSchema,
+	}))
+	// the row changed events are not affected by the DDL topic.
+	require.Equal(t, "test_t", d.GetTopicForRowChange(&model.RowChangedEvent{
+		Table: &model.TableName{Schema: "test", Table: "t"},
+	}))
+
+	cfg.Sink.DDLTopic = "ddl_{partition}"
+	_, err = NewEventRouter(cfg, "default", model.ChangeFeedID{}, 0)
+	require.ErrorContains(t, err, "invalid topic expression")
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/hash"
+)
+
+// ColumnsDispatcher is a partition dispatcher which dispatches events
+// based on the values of the given columns.
+type ColumnsDispatcher struct {
+	hasher *hash.PositionInertia
+	lock   sync.Mutex
+
+	Columns []string
+}
+
+// NewColumnsDispatcher creates a ColumnsDispatcher.
+func NewColumnsDispatcher(columns []string) *ColumnsDispatcher {
+	return &ColumnsDispatcher{
+		hasher:  hash.NewPositionInertia(),
+		Columns: columns,
+	}
+}
+
+// DispatchRowChangedEvent returns the target partition to which
+// a row changed event should be dispatched.
+func (r *ColumnsDispatcher) DispatchRowChangedEvent(
+	row *model.RowChangedEvent, partitionNum int32,
+) (int32, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.hasher.Reset()
+	r.hasher.Write([]byte(row.Table.Schema), []byte(row.Table.Table))
+
+	dispatchCols := row.Columns
+	if len(dispatchCols) == 0 {
+		dispatchCols = row.PreColumns
+	}
+	for _, name := range r.Columns {
+		col := findColumn(dispatchCols, name)
+		if col == nil {
+			return -1, cerror.ErrDispatcherFailed.GenWithStack(
+				"column %s is not found in table %s, it may have been dropped",
+				name, row.Table)
+		}
+		r.hasher.Write([]byte(name), []byte(model.ColumnValueString(col.Value)))
+	}
+	return int32(r.hasher.Sum32() % uint32(partitionNum)), nil
+}
+
+// VerifyTable checks whether all the columns exist in the table.
+func (r *ColumnsDispatcher) VerifyTable(tableInfo *model.TableInfo) error {
+	for _, name := range r.Columns {
+		found := false
+		for _, col := range tableInfo.Columns {
+			if strings.EqualFold(col.Name.O, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return cerror.ErrDispatcherFailed.GenWithStack(
+				"column %s is not found in table %s", name, tableInfo.TableName)
+		}
+	}
+	return nil
+}
+
+func findColumn(cols []*model.Column, name string) *model.Column {
+	for _, col := range cols {
+		if col != nil && strings.EqualFold(col.Name, name) {
+			return col
+		}
+	}
+	return nil
+}
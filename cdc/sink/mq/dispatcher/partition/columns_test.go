@@ -0,0 +1,79 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnsDispatcher(t *testing.T) {
+	t.Parallel()
+
+	newRow := func(id, tenantID int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{Schema: "test", Table: "t1"},
+			Columns: []*model.Column{
+				{Name: "id", Value: id, Flag: model.HandleKeyFlag},
+				{Name: "tenant_id", Value: tenantID},
+			},
+		}
+	}
+
+	p := NewColumnsDispatcher([]string{"TENANT_ID"})
+	p1, err := p.DispatchRowChangedEvent(newRow(1, 100), 16)
+	require.Nil(t, err)
+	p2, err := p.DispatchRowChangedEvent(newRow(2, 100), 16)
+	require.Nil(t, err)
+	require.Equal(t, p1, p2)
+
+	// delete events are dispatched by the pre columns.
+	row := newRow(3, 100)
+	row.PreColumns, row.Columns = row.Columns, nil
+	p3, err := p.DispatchRowChangedEvent(row, 16)
+	require.Nil(t, err)
+	require.Equal(t, p1, p3)
+
+	p = NewColumnsDispatcher([]string{"id", "tenant_id"})
+	p4, err := p.DispatchRowChangedEvent(newRow(1, 100), 16)
+	require.Nil(t, err)
+	p5, err := p.DispatchRowChangedEvent(newRow(1, 100), 16)
+	require.Nil(t, err)
+	require.Equal(t, p4, p5)
+
+	p = NewColumnsDispatcher([]string{"tenant_id", "region"})
+	_, err = p.DispatchRowChangedEvent(newRow(1, 100), 16)
+	require.ErrorContains(t, err, "column region is not found in table test.t1")
+}
+
+func TestColumnsDispatcherVerifyTable(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t1"},
+		TableInfo: &timodel.TableInfo{
+			Columns: []*timodel.ColumnInfo{
+				{Name: timodel.NewCIStr("id")},
+				{Name: timodel.NewCIStr("Tenant_ID")},
+			},
+		},
+	}
+	require.Nil(t, NewColumnsDispatcher([]string{"tenant_id"}).VerifyTable(tableInfo))
+	require.ErrorContains(t,
+		NewColumnsDispatcher([]string{"tenant_id", "region"}).VerifyTable(tableInfo),
+		"column region is not found in table test.t1")
+}
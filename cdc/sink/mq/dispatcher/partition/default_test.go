@@ -195,7 +195,9 @@ func TestDefaultDispatcher(t *testing.T) {
 	}
 	p := NewDefaultDispatcher(false)
 	for _, tc := range testCases {
-		require.Equal(t, tc.expectPartition, p.DispatchRowChangedEvent(tc.row, 16))
+		partition, err := p.DispatchRowChangedEvent(tc.row, 16)
+		require.Nil(t, err)
+		require.Equal(t, tc.expectPartition, partition)
 	}
 }
 
@@ -222,5 +224,7 @@ func TestDefaultDispatcherWithOldValue(t *testing.T) {
 	}
 
 	p := NewDefaultDispatcher(true)
-	require.Equal(t, int32(3), p.DispatchRowChangedEvent(row, 16))
+	partition, err := p.DispatchRowChangedEvent(row, 16)
+	require.Nil(t, err)
+	require.Equal(t, int32(3), partition)
 }
@@ -35,13 +35,16 @@ func NewIndexValueDispatcher() *IndexValueDispatcher {
 
 // DispatchRowChangedEvent returns the target partition to which
 // a row changed event should be dispatched.
-func (r *IndexValueDispatcher) DispatchRowChangedEvent(row *model.RowChangedEvent, partitionNum int32) int32 {
+func (r *IndexValueDispatcher) DispatchRowChangedEvent(
+	row *model.RowChangedEvent, partitionNum int32,
+) (int32, error) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	r.hasher.Reset()
 	r.hasher.Write([]byte(row.Table.Schema), []byte(row.Table.Table))
-	// FIXME(leoppro): if the row events includes both pre-cols and cols
-	// the dispatch logic here is wrong
+	// The update events which change the index value are split into
+	// delete and insert events by the EventRouter, so only the columns
+	// are used if the row events include both pre-cols and cols.
 
 	// distribute partition by rowid or unique column value
 	dispatchCols := row.Columns
@@ -56,5 +59,5 @@ func (r *IndexValueDispatcher) DispatchRowChangedEvent(row *model.RowChangedEven
 			r.hasher.Write([]byte(col.Name), []byte(model.ColumnValueString(col.Value)))
 		}
 	}
-	return int32(r.hasher.Sum32() % uint32(partitionNum))
+	return int32(r.hasher.Sum32() % uint32(partitionNum)), nil
 }
@@ -149,6 +149,8 @@ func TestIndexValueDispatcher(t *testing.T) {
 	}
 	p := NewIndexValueDispatcher()
 	for _, tc := range testCases {
-		require.Equal(t, tc.expectPartition, p.DispatchRowChangedEvent(tc.row, 16))
+		partition, err := p.DispatchRowChangedEvent(tc.row, 16)
+		require.Nil(t, err)
+		require.Equal(t, tc.expectPartition, partition)
 	}
 }
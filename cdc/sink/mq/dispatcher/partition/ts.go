@@ -25,6 +25,8 @@ func NewTsDispatcher() *TsDispatcher {
 
 // DispatchRowChangedEvent returns the target partition to which
 // a row changed event should be dispatched.
-func (t *TsDispatcher) DispatchRowChangedEvent(row *model.RowChangedEvent, partitionNum int32) int32 {
-	return int32(row.CommitTs % uint64(partitionNum))
+func (t *TsDispatcher) DispatchRowChangedEvent(
+	row *model.RowChangedEvent, partitionNum int32,
+) (int32, error) {
+	return int32(row.CommitTs % uint64(partitionNum)), nil
 }
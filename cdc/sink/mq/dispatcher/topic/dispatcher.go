@@ -21,6 +21,7 @@ import (
 type Dispatcher interface {
 	fmt.Stringer
 	Substitute(schema, table string) string
+	SubstituteValues(v Values) string
 }
 
 // StaticTopicDispatcher is a topic dispatcher which dispatches rows and ddls to the default topic.
@@ -40,6 +41,11 @@ func (s *StaticTopicDispatcher) Substitute(schema, table string) string {
 	return s.defaultTopic
 }
 
+// SubstituteValues converts the placeholders in a topic expression to kafka topic name.
+func (s *StaticTopicDispatcher) SubstituteValues(_ Values) string {
+	return s.defaultTopic
+}
+
 func (s *StaticTopicDispatcher) String() string {
 	return s.defaultTopic
 }
@@ -62,6 +68,11 @@ func (d *DynamicTopicDispatcher) Substitute(schema, table string) string {
 	return d.expression.Substitute(schema, table)
 }
 
+// SubstituteValues converts the placeholders in a topic expression to kafka topic name.
+func (d *DynamicTopicDispatcher) SubstituteValues(v Values) string {
+	return d.expression.SubstituteValues(v)
+}
+
 func (d *DynamicTopicDispatcher) String() string {
 	return string(d.expression)
 }
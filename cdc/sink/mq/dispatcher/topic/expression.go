@@ -15,36 +15,62 @@ package topic
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/pingcap/tiflow/pkg/errors"
 )
 
+// The placeholders which can be used in a topic expression.
+const (
+	// PlaceholderSchema is substituted with the schema name.
+	PlaceholderSchema = "{schema}"
+	// PlaceholderTable is substituted with the table name.
+	PlaceholderTable = "{table}"
+	// PlaceholderPartition is substituted with the partition name of a
+	// partitioned table, and with an empty string for the other tables.
+	PlaceholderPartition = "{partition}"
+	// PlaceholderChangefeed is substituted with the changefeed ID.
+	PlaceholderChangefeed = "{changefeed}"
+	// PlaceholderEventType is substituted with the type of the event, which is
+	// insert, update or delete for the row changed events, and the DDL type,
+	// such as create_table, for the DDL events.
+	// Note that the events of the same key are sent to different topics by
+	// their types, so the consumers can not keep the order of them, e.g. an
+	// update may be consumed before the insert of the same row.
+	PlaceholderEventType = "{event-type}"
+	// PlaceholderClusterID is substituted with the upstream cluster ID.
+	PlaceholderClusterID = "{cluster-id}"
+)
+
 var (
-	// topicNameRE is used to match a valid topic expression
+	// topicNameRE is used to match a valid topic expression, which consists of
+	// the placeholders and the characters allowed in the kafka topic name.
 	topicNameRE = regexp.MustCompile(
-		`^[A-Za-z0-9\._\-]*\{schema\}([A-Za-z0-9\._\-]*\{table\})?[A-Za-z0-9\._\-]*$`,
+		`^([A-Za-z0-9\._\-]|\{(schema|table|partition|changefeed|event-type|cluster-id)\})*$`,
 	)
 	// kafkaForbidRE is used to reject the characters which are forbidden in kafka topic name
 	kafkaForbidRE = regexp.MustCompile(`[^a-zA-Z0-9\._\-]`)
-	// schemaRE is used to match substring '{schema}' in topic expression
-	schemaRE = regexp.MustCompile(`\{schema\}`)
-	// tableRE is used to match substring '{table}' in topic expression
-	tableRE = regexp.MustCompile(`\{table\}`)
-	// avro has different topic name pattern requirements, '{schema}' and '{table}' placeholders
-	// are necessary
-	avroTopicNameRE = regexp.MustCompile(
-		`^[A-Za-z0-9\._\-]*\{schema\}[A-Za-z0-9\._\-]*\{table\}[A-Za-z0-9\._\-]*$`,
-	)
 )
 
 // The max length of kafka topic name is 249.
 // See https://github.com/apache/kafka/blob/trunk/clients/src/main/java/org/apache/kafka/common/internals/Topic.java#L35
 const kafkaTopicNameMaxLength = 249
 
+// Values are the values of the placeholders in a topic expression.
+type Values struct {
+	Schema     string
+	Table      string
+	Partition  string
+	Changefeed string
+	EventType  string
+	ClusterID  string
+}
+
 // Expression represent a kafka topic expression.
-// The expression should be in form of: [prefix]{schema}[middle][{table}][suffix]
-// prefix/suffix/middle are optional and should match the regex of [A-Za-z0-9\._\-]*
-// {table} can also be optional.
+// The expression consists of the placeholders {schema}, {table}, {partition},
+// {changefeed}, {event-type}, {cluster-id} and the strings matching the regex
+// of [A-Za-z0-9\._\-]*. The expression of the row changed events must contain
+// the {schema} placeholder, e.g. [prefix]{schema}[middle][{table}][suffix].
 type Expression string
 
 // Validate checks whether a kafka topic name is valid or not.
@@ -53,13 +79,20 @@ func (e Expression) Validate() error {
 	if ok := topicNameRE.MatchString(string(e)); !ok {
 		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
 	}
+	if !e.contains(PlaceholderSchema) {
+		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
+	}
 
 	return nil
 }
 
-// ValidateForAvro checks whether topic pattern is {schema}_{table}, the only allowed
+// ValidateForAvro checks whether topic pattern contains {schema} and {table},
+// which are necessary for the avro protocol.
 func (e Expression) ValidateForAvro() error {
-	if ok := avroTopicNameRE.MatchString(string(e)); !ok {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if !e.contains(PlaceholderTable) {
 		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs(
 			"topic rule for Avro must contain {schema} and {table}",
 		)
@@ -68,18 +101,44 @@ func (e Expression) ValidateForAvro() error {
 	return nil
 }
 
+// ValidateForDDL checks whether the expression of the DDL topic is valid.
+// Unlike the expressions of the row changed events, a DDL topic expression
+// can be a static topic name, and it can not contain {partition}.
+func (e Expression) ValidateForDDL() error {
+	if ok := topicNameRE.MatchString(string(e)); !ok || e == "" {
+		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
+	}
+	if e.contains(PlaceholderPartition) {
+		return errors.ErrKafkaInvalidTopicExpression.GenWithStackByArgs()
+	}
+
+	return nil
+}
+
 // Substitute converts schema/table name in a topic expression to kafka topic name.
 // When doing conversion, the special characters other than [A-Za-z0-9\._\-] in schema/table
 // will be substituted for underscore '_'.
 func (e Expression) Substitute(schema, table string) string {
-	// some of the special characters will be replaced with '_'
-	replacedSchema := kafkaForbidRE.ReplaceAllString(schema, "_")
-	replacedTable := kafkaForbidRE.ReplaceAllString(table, "_")
+	return e.SubstituteValues(Values{Schema: schema, Table: table})
+}
 
-	topicExpr := string(e)
+// SubstituteValues converts all the placeholders in a topic expression to
+// kafka topic name. Like Substitute, the special characters other than
+// [A-Za-z0-9\._\-] in the values will be substituted for underscore '_'.
+func (e Expression) SubstituteValues(v Values) string {
+	// some of the special characters will be replaced with '_'
+	replace := func(s string) string {
+		return kafkaForbidRE.ReplaceAllString(s, "_")
+	}
 	// doing the real conversion things
-	topicName := schemaRE.ReplaceAllString(topicExpr, replacedSchema)
-	topicName = tableRE.ReplaceAllString(topicName, replacedTable)
+	topicName := strings.NewReplacer(
+		PlaceholderSchema, replace(v.Schema),
+		PlaceholderTable, replace(v.Table),
+		PlaceholderPartition, replace(v.Partition),
+		PlaceholderChangefeed, replace(v.Changefeed),
+		PlaceholderEventType, replace(v.EventType),
+		PlaceholderClusterID, replace(v.ClusterID),
+	).Replace(string(e))
 
 	// topicName will be truncated if it exceed the limit.
 	// And topicName '.' and '..' are also invalid, replace them with '_'.
@@ -94,3 +153,15 @@ func (e Expression) Substitute(schema, table string) string {
 		return topicName
 	}
 }
+
+// contains returns true if the expression contains the placeholder.
+// SplitsByEventType returns true if the expression contains the {event-type}
+// placeholder, with which the events of the same key are sent to different
+// topics, and the order of them is lost.
+func (e Expression) SplitsByEventType() bool {
+	return e.contains(PlaceholderEventType)
+}
+
+func (e Expression) contains(placeholder string) bool {
+	return strings.Contains(string(e), placeholder)
+}
@@ -227,6 +227,38 @@ func TestSubstituteTopicExpression(t *testing.T) {
 	}
 }
 
+func TestSubstituteValues(t *testing.T) {
+	t.Parallel()
+
+	topicExpr := Expression("{cluster-id}_{changefeed}_{table}_{schema}_{partition}_{event-type}")
+	require.Nil(t, topicExpr.Validate())
+	require.Nil(t, topicExpr.ValidateForAvro())
+	require.Equal(t, "1_cf_tbl_db_p0_insert", topicExpr.SubstituteValues(Values{
+		Schema:     "db",
+		Table:      "tbl",
+		Partition:  "p0",
+		Changefeed: "cf",
+		EventType:  "insert",
+		ClusterID:  "1",
+	}))
+
+	// the row changed events can not be dispatched without {schema}.
+	require.Error(t, Expression("{changefeed}_{table}").Validate())
+	require.Error(t, Expression("{schema}_{changefeed}").ValidateForAvro())
+
+	// the DDL topic can be a static topic name.
+	require.Nil(t, Expression("ddl").ValidateForDDL())
+	require.Nil(t, Expression("ddl_{changefeed}_{event-type}").ValidateForDDL())
+	require.Error(t, Expression("ddl_{partition}").ValidateForDDL())
+	require.Error(t, Expression("").ValidateForDDL())
+	require.Equal(t, "ddl_add_column", Expression("ddl_{event-type}").SubstituteValues(Values{
+		EventType: "add column",
+	}))
+
+	require.True(t, topicExpr.SplitsByEventType())
+	require.False(t, Expression("{schema}_{table}").SplitsByEventType())
+}
+
 // cmd: go test -run='^$' -bench '^(BenchmarkSubstitute)$' github.com/pingcap/tiflow/cdc/sink/dispatcher/topic
 // goos: linux
 // goarch: amd64
@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// pulsarPartitionsRefreshInterval is the interval to refresh
+// the partition number of a topic.
+const pulsarPartitionsRefreshInterval = time.Minute
+
+type pulsarTopic struct {
+	partitionNum int32
+	lastRefresh  time.Time
+}
+
+// pulsarTopicManager is a manager for pulsar topics.
+// Pulsar does not provide a way to create topics from the client,
+// topics are created by the broker automatically on the first lookup
+// if `allowAutoTopicCreation` is enabled.
+type pulsarTopicManager struct {
+	client pulsar.Client
+
+	mu     sync.Mutex
+	topics map[string]*pulsarTopic
+}
+
+// NewPulsarTopicManager creates a new topic manager.
+func NewPulsarTopicManager(client pulsar.Client) *pulsarTopicManager {
+	return &pulsarTopicManager{
+		client: client,
+		topics: make(map[string]*pulsarTopic),
+	}
+}
+
+// GetPartitionNum returns the number of partitions of the topic.
+// The partition number is refreshed periodically,
+// since partitions can be added to a pulsar topic at any time.
+func (m *pulsarTopicManager) GetPartitionNum(topic string) (int32, error) {
+	m.mu.Lock()
+	t, ok := m.topics[topic]
+	m.mu.Unlock()
+	if ok && time.Since(t.lastRefresh) < pulsarPartitionsRefreshInterval {
+		return t.partitionNum, nil
+	}
+	return m.CreateTopicAndWaitUntilVisible(topic)
+}
+
+// CreateTopicAndWaitUntilVisible looks up the topic, which triggers
+// the creation of the topic if the broker allows it.
+func (m *pulsarTopicManager) CreateTopicAndWaitUntilVisible(topic string) (int32, error) {
+	partitions, err := m.client.TopicPartitions(topic)
+	if err != nil {
+		return 0, cerror.WrapError(cerror.ErrPulsarTopicPartitions, err)
+	}
+	partitionNum := int32(len(partitions))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old, ok := m.topics[topic]
+	if !ok || old.partitionNum != partitionNum {
+		log.Info("store pulsar topic partition number",
+			zap.String("topic", topic),
+			zap.Int32("partitionNumber", partitionNum))
+	}
+	m.topics[topic] = &pulsarTopic{
+		partitionNum: partitionNum,
+		lastRefresh:  time.Now(),
+	}
+	return partitionNum, nil
+}
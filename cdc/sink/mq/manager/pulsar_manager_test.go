@@ -0,0 +1,51 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPulsarPartitions(t *testing.T) {
+	t.Parallel()
+
+	client := pulsar.NewMockClient()
+	manager := NewPulsarTopicManager(client)
+	partitionNum, err := manager.CreateTopicAndWaitUntilVisible(pulsar.DefaultMockTopicName)
+	require.Nil(t, err)
+	require.Equal(t, int32(pulsar.DefaultMockPartitionNum), partitionNum)
+
+	client.SetPartitionNum("test", 4)
+	partitionNum, err = manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+
+	// The cached partition number is used before the next refresh.
+	client.SetPartitionNum("test", 6)
+	partitionNum, err = manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+
+	manager.topics["test"].lastRefresh = time.Now().Add(-2 * pulsarPartitionsRefreshInterval)
+	partitionNum, err = manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(6), partitionNum)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/mq/manager"
@@ -76,7 +77,8 @@ func newMqSink(
 		return nil, cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
 	}
 
-	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, defaultTopic)
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, defaultTopic,
+		changefeedID, contextutil.UpstreamIDFromCtx(ctx))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -84,9 +86,14 @@ func newMqSink(
 	captureAddr := contextutil.CaptureAddrFromCtx(ctx)
 	role := contextutil.RoleFromCtx(ctx)
 
+	deadLetter, err := dlq.NewWriter(ctx, replicaConfig.Sink.DeadLetterQueue, changefeedID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	encoder := encoderBuilder.Build()
 	statistics := metrics.NewStatistics(ctx, captureAddr, metrics.SinkTypeMQ)
-	flushWorker := newFlushWorker(encoder, mqProducer, statistics)
+	flushWorker := newFlushWorker(encoder, mqProducer, statistics, deadLetter)
 
 	s := &mqSink{
 		mqProducer:     mqProducer,
@@ -142,15 +149,20 @@ func (k *mqSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowCha
 		if err != nil {
 			return errors.Trace(err)
 		}
-		partition := k.eventRouter.GetPartitionForRowChange(row, partitionNum)
-		err = k.flushWorker.addEvent(ctx, mqEvent{
-			row: row,
-			key: TopicPartitionKey{
-				Topic: topic, Partition: partition,
-			},
-		})
+		partitions, err := k.eventRouter.GetPartitionsForRowChange(row, partitionNum)
 		if err != nil {
-			return err
+			return errors.Trace(err)
+		}
+		for _, p := range partitions {
+			err = k.flushWorker.addEvent(ctx, mqEvent{
+				row: p.Row,
+				key: TopicPartitionKey{
+					Topic: topic, Partition: p.Partition,
+				},
+			})
+			if err != nil {
+				return err
+			}
 		}
 		rowsCount++
 	}
@@ -257,11 +269,7 @@ func (k *mqSink) EmitCheckpointTs(ctx context.Context, ts uint64, tables []*mode
 		err = k.mqProducer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
 		return errors.Trace(err)
 	}
-	var tableNames []model.TableName
-	for _, table := range tables {
-		tableNames = append(tableNames, table.TableName)
-	}
-	topics := k.eventRouter.GetActiveTopics(tableNames)
+	topics := k.eventRouter.GetActiveTopics(tables)
 	log.Debug("MQ sink current active topics", zap.Any("topics", topics))
 	for _, topic := range topics {
 		partitionNum, err := k.topicManager.GetPartitionNum(topic)
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/cdc/sink/mq/producer"
 	"github.com/pingcap/tiflow/pkg/chann"
@@ -65,6 +66,9 @@ type flushWorker struct {
 	encoder    codec.EventBatchEncoder
 	producer   producer.Producer
 	statistics *metrics.Statistics
+	// deadLetter records the events which can not be encoded, such as the
+	// too large events. It is nil if the dead letter queue is not configured.
+	deadLetter dlq.Writer
 }
 
 // newFlushWorker creates a new flush worker.
@@ -72,6 +76,7 @@ func newFlushWorker(
 	encoder codec.EventBatchEncoder,
 	producer producer.Producer,
 	statistics *metrics.Statistics,
+	deadLetter dlq.Writer,
 ) *flushWorker {
 	w := &flushWorker{
 		msgChan:    chann.New[mqEvent](),
@@ -79,6 +84,7 @@ func newFlushWorker(
 		encoder:    encoder,
 		producer:   producer,
 		statistics: statistics,
+		deadLetter: deadLetter,
 	}
 	return w
 }
@@ -165,7 +171,14 @@ func (w *flushWorker) asyncSend(
 		for _, event := range events {
 			err := w.encoder.AppendRowChangedEvent(ctx, key.Topic, event, nil)
 			if err != nil {
-				return err
+				if w.deadLetter == nil || !codec.IsDeadLetterError(err) {
+					return err
+				}
+				// The event can not be sent, record it in the dead letter queue
+				// instead of failing the changefeed.
+				if err := w.deadLetter.WriteRowChangedEvents(ctx, err, event); err != nil {
+					return errors.Trace(err)
+				}
 			}
 		}
 
@@ -268,4 +281,9 @@ func (w *flushWorker) close() {
 	for range w.msgChan.Out() {
 		// Do nothing. We do not care about the data.
 	}
+	if w.deadLetter != nil {
+		if err := w.deadLetter.Close(); err != nil {
+			log.Warn("failed to close the dead letter queue writer", zap.Error(err))
+		}
+	}
 }
@@ -16,6 +16,7 @@ package mq
 import (
 	"context"
 	"math"
+	"strings"
 	"sync"
 	"testing"
 
@@ -24,8 +25,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	mockdlq "github.com/pingcap/tiflow/cdc/sink/dlq/mock"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 )
@@ -96,7 +99,7 @@ func newTestWorker(ctx context.Context) (*flushWorker, *mockProducer) {
 	}
 	producer := NewMockProducer()
 	return newFlushWorker(encoder, producer,
-		metrics.NewStatistics(ctx, "", metrics.SinkTypeMQ)), producer
+		metrics.NewStatistics(ctx, "", metrics.SinkTypeMQ), nil), producer
 }
 
 //nolint:tparallel
@@ -391,6 +394,56 @@ func TestAsyncSend(t *testing.T) {
 	require.Len(t, producer.mqEvent[key3], 2)
 }
 
+func TestAsyncSendWithDeadLetterQueue(t *testing.T) {
+	t.Parallel()
+
+	key := TopicPartitionKey{
+		Topic:     "test",
+		Partition: 1,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	largeRow := &model.RowChangedEvent{
+		CommitTs: 2,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte(strings.Repeat("a", 200)),
+		}},
+	}
+	events := []mqEvent{
+		{
+			row: &model.RowChangedEvent{
+				CommitTs: 1,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+				Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+			},
+			key: key,
+		},
+		{row: largeRow, key: key},
+	}
+
+	// the changefeed fails without the dead letter queue.
+	worker, _ := newTestWorker(ctx)
+	err := worker.asyncSend(ctx, worker.group(events))
+	require.True(t, cerror.ErrOpenProtocolCodecRowTooLarge.Equal(err))
+	worker.close()
+
+	worker, producer := newTestWorker(ctx)
+	deadLetter := mockdlq.NewMockWriter()
+	worker.deadLetter = deadLetter
+	err = worker.asyncSend(ctx, worker.group(events))
+	require.NoError(t, err)
+	require.Len(t, producer.mqEvent[key], 1)
+	rows, causes := deadLetter.Rows()
+	require.Equal(t, []*model.RowChangedEvent{largeRow}, rows)
+	require.True(t, cerror.ErrOpenProtocolCodecRowTooLarge.Equal(causes[0]))
+	worker.close()
+	require.True(t, deadLetter.IsClosed())
+}
+
 func TestFlush(t *testing.T) {
 	t.Parallel()
 
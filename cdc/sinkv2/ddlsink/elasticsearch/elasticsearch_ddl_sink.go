@@ -0,0 +1,88 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"go.uber.org/zap"
+)
+
+// Assert DDLEventSink implementation
+var _ ddlsink.DDLEventSink = (*ddlSink)(nil)
+
+// ddlSink maps the DDL events to the index mapping updates.
+type ddlSink struct {
+	// id indicates which changefeed this sink belongs to.
+	id     model.ChangeFeedID
+	cfg    *elasticsearch.Config
+	client *elasticsearch.Client
+}
+
+// NewElasticsearchDDLSink creates a ddl sink for elasticsearch.
+func NewElasticsearchDDLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*ddlSink, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	cfg := elasticsearch.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	client, err := elasticsearch.NewClient(cfg, changefeedID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ddlSink{
+		id:     changefeedID,
+		cfg:    cfg,
+		client: client,
+	}, nil
+}
+
+// WriteDDLEvent puts the mapping of the table created or altered by the DDL.
+func (d *ddlSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	if !elasticsearch.ShouldUpdateMapping(ddl) {
+		log.Info("Skip ddl event", zap.Uint64("commitTs", ddl.CommitTs),
+			zap.String("query", ddl.Query),
+			zap.String("namespace", d.id.Namespace),
+			zap.String("changefeed", d.id.ID))
+		return nil
+	}
+	index := d.cfg.IndexName(ddl.TableInfo.TableName.Schema, ddl.TableInfo.TableName.Table)
+	return errors.Trace(d.client.PutTableMapping(ctx, index, ddl.TableInfo))
+}
+
+// WriteCheckpointTs does nothing, elasticsearch has no place to store the
+// checkpoints.
+func (d *ddlSink) WriteCheckpointTs(_ context.Context,
+	_ uint64, _ []*model.TableInfo,
+) error {
+	return nil
+}
+
+// Close closes the sink.
+func (d *ddlSink) Close() error {
+	d.client.Close()
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDDLEvent(t *testing.T) {
+	t.Parallel()
+
+	server := elasticsearch.NewMockServer()
+	defer server.Close()
+	sinkURI, err := url.Parse(server.URL + "?index=cdc_{table}")
+	require.NoError(t, err)
+	sinkURI.Scheme = "elasticsearch"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink, err := NewElasticsearchDDLSink(ctx, sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "t"},
+		TableInfo: &timodel.TableInfo{
+			Columns: []*timodel.ColumnInfo{
+				{Name: timodel.NewCIStr("id"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+			},
+		},
+	}
+	require.NoError(t, sink.WriteDDLEvent(ctx, &model.DDLEvent{
+		CommitTs:  100,
+		Type:      timodel.ActionCreateTable,
+		Query:     "create table t(id int primary key)",
+		TableInfo: tableInfo,
+	}))
+	require.Len(t, server.Mapping("cdc_t"), 1)
+
+	tableInfo.Columns = append(tableInfo.Columns, &timodel.ColumnInfo{
+		Name: timodel.NewCIStr("name"), FieldType: *types.NewFieldType(mysql.TypeVarchar),
+	})
+	require.NoError(t, sink.WriteDDLEvent(ctx, &model.DDLEvent{
+		CommitTs:  101,
+		Type:      timodel.ActionAddColumn,
+		Query:     "alter table t add column name varchar(10)",
+		TableInfo: tableInfo,
+	}))
+	require.Len(t, server.Mapping("cdc_t"), 2)
+
+	// the index of the dropped table is kept.
+	require.NoError(t, sink.WriteDDLEvent(ctx, &model.DDLEvent{
+		CommitTs:  102,
+		Type:      timodel.ActionDropTable,
+		Query:     "drop table t",
+		TableInfo: tableInfo,
+	}))
+	require.Len(t, server.Mapping("cdc_t"), 2)
+	require.NoError(t, sink.WriteCheckpointTs(ctx, 103, nil))
+}
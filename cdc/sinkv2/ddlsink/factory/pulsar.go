@@ -0,0 +1,42 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package factory
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink/mq"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink/mq/ddlproducer"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/pulsar"
+)
+
+// newPulsarDDLSink creates a pulsar DDL sink.
+func newPulsarDDLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	cfg *config.ReplicaConfig,
+) (ddlsink.DDLEventSink, error) {
+	s, err := mq.NewPulsarDDLSink(ctx, sinkURI, cfg,
+		pulsar.NewClient, ddlproducer.NewPulsarDDLProducer)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
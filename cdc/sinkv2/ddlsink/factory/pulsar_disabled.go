@@ -0,0 +1,38 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build !pulsar
+// +build !pulsar
+
+package factory
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// newPulsarDDLSink returns an error because the pulsar sink is only built
+// with the `pulsar` build tag.
+func newPulsarDDLSink(
+	_ context.Context,
+	sinkURI *url.URL,
+	_ *config.ReplicaConfig,
+) (ddlsink.DDLEventSink, error) {
+	return nil, cerror.ErrSinkURIInvalid.GenWithStack(
+		"the sink scheme (%s) requires TiCDC to be built with the pulsar tag",
+		sinkURI.Scheme)
+}
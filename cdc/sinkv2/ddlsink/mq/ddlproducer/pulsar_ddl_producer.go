@@ -0,0 +1,164 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package ddlproducer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	ppulsar "github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"go.uber.org/zap"
+)
+
+// PulsarFactory is a function to create a pulsar producer.
+type PulsarFactory func(ctx context.Context, client pulsar.Client,
+	config *ppulsar.Config) (DDLProducer, error)
+
+// Assert DDLProducer implementation
+var _ DDLProducer = (*pulsarDDLProducer)(nil)
+
+// pulsarDDLProducer is used to send messages to pulsar synchronously.
+type pulsarDDLProducer struct {
+	// id indicates this sink belongs to which processor(changefeed).
+	id model.ChangeFeedID
+	// client is used to create producers.
+	client pulsar.Client
+	config *ppulsar.Config
+	// producers caches the producer of each topic,
+	// since a pulsar producer can only send messages to one topic.
+	producers map[string]pulsar.Producer
+	// producersMu is used to protect `producers`.
+	producersMu sync.Mutex
+	// closedMu is used to protect `closed`.
+	// We need to ensure that closed producers are never written to.
+	closedMu sync.RWMutex
+	// closed is used to indicate whether the producer is closed.
+	// We also use it to guard against double closes.
+	closed bool
+}
+
+// NewPulsarDDLProducer creates a new pulsar producer for replicating DDL.
+func NewPulsarDDLProducer(ctx context.Context, client pulsar.Client,
+	config *ppulsar.Config,
+) (DDLProducer, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	return &pulsarDDLProducer{
+		id:        changefeedID,
+		client:    client,
+		config:    config,
+		producers: make(map[string]pulsar.Producer),
+	}, nil
+}
+
+func (p *pulsarDDLProducer) SyncBroadcastMessage(ctx context.Context, topic string,
+	totalPartitionsNum int32, message *common.Message,
+) error {
+	for i := int32(0); i < totalPartitionsNum; i++ {
+		if err := p.SyncSendMessage(ctx, topic, i, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *pulsarDDLProducer) SyncSendMessage(ctx context.Context, topic string,
+	partitionNum int32, message *common.Message,
+) error {
+	p.closedMu.RLock()
+	defer p.closedMu.RUnlock()
+
+	if p.closed {
+		return cerror.ErrPulsarProducerClosed.GenWithStackByArgs()
+	}
+
+	producer, err := p.getProducer(topic)
+	if err != nil {
+		return err
+	}
+
+	msg := &pulsar.ProducerMessage{
+		Key:     string(message.Key),
+		Payload: message.Value,
+	}
+	ppulsar.SetPartition(msg, partitionNum)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	default:
+		_, err := producer.Send(ctx, msg)
+		return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+	}
+}
+
+// getProducer returns the producer of the topic, creates one if it does not exist.
+func (p *pulsarDDLProducer) getProducer(topic string) (pulsar.Producer, error) {
+	p.producersMu.Lock()
+	defer p.producersMu.Unlock()
+	if producer, ok := p.producers[topic]; ok {
+		return producer, nil
+	}
+	producer, err := p.client.CreateProducer(p.config.ProducerOptions(topic))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	log.Info("Pulsar DDL producer created",
+		zap.String("topic", topic),
+		zap.String("namespace", p.id.Namespace),
+		zap.String("changefeed", p.id.ID))
+	p.producers[topic] = producer
+	return producer, nil
+}
+
+func (p *pulsarDDLProducer) Close() {
+	// We have to hold the lock to prevent write to closed producer.
+	p.closedMu.Lock()
+	defer p.closedMu.Unlock()
+	// If the producer was already closed, we should skip the close operation.
+	if p.closed {
+		log.Warn("Pulsar DDL producer already closed",
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID))
+		return
+	}
+	p.closed = true
+	// We need to close it asynchronously. Otherwise, we might get stuck
+	// with an unhealthy pulsar cluster. No data will be lost because
+	// all messages are sent synchronously.
+	p.producersMu.Lock()
+	producers := p.producers
+	p.producers = nil
+	p.producersMu.Unlock()
+	go func() {
+		start := time.Now()
+		for _, producer := range producers {
+			producer.Close()
+		}
+		p.client.Close()
+		log.Info("Pulsar client closed in pulsar DDL producer",
+			zap.Duration("duration", time.Since(start)),
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID))
+	}()
+}
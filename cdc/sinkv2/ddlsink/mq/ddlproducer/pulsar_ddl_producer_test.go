@@ -0,0 +1,79 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package ddlproducer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPulsarSyncBroadcastMessage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := pulsar.NewMockClient()
+	p, err := NewPulsarDDLProducer(ctx, client, pulsar.NewConfig())
+	require.Nil(t, err)
+
+	topic := pulsar.DefaultMockTopicName
+	err = p.SyncBroadcastMessage(ctx, topic,
+		pulsar.DefaultMockPartitionNum, &common.Message{Ts: 417318403368288260})
+	require.Nil(t, err)
+	for i := 0; i < pulsar.DefaultMockPartitionNum; i++ {
+		require.Len(t, client.GetMessages(topic, i), 1)
+	}
+
+	p.Close()
+	require.Eventually(t, client.IsClosed, 5*time.Second, 10*time.Millisecond)
+	err = p.SyncBroadcastMessage(ctx, topic,
+		pulsar.DefaultMockPartitionNum, &common.Message{Ts: 417318403368288260})
+	require.ErrorIs(t, err, cerror.ErrPulsarProducerClosed)
+}
+
+func TestPulsarSyncSendMessage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := pulsar.NewMockClient()
+	p, err := NewPulsarDDLProducer(ctx, client, pulsar.NewConfig())
+	require.Nil(t, err)
+
+	topic := pulsar.DefaultMockTopicName
+	err = p.SyncSendMessage(ctx, topic, 1, &common.Message{Ts: 417318403368288260})
+	require.Nil(t, err)
+	require.Len(t, client.GetMessages(topic, 1), 1)
+	require.Len(t, client.GetAllMessages(topic), 1)
+
+	client.SendError = errors.New("message too large")
+	err = p.SyncSendMessage(ctx, topic, 1, &common.Message{Ts: 417318403368288260})
+	require.ErrorIs(t, err, cerror.ErrPulsarSendMessage)
+
+	p.Close()
+	// Close reentry.
+	p.Close()
+	err = p.SyncSendMessage(ctx, topic, 0, &common.Message{Ts: 417318403368288260})
+	require.ErrorIs(t, err, cerror.ErrPulsarProducerClosed)
+}
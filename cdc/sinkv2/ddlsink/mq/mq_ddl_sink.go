@@ -153,11 +153,7 @@ func (k *ddlSink) WriteCheckpointTs(ctx context.Context,
 		err = k.producer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
 		return errors.Trace(err)
 	}
-	var tableNames []model.TableName
-	for _, table := range tables {
-		tableNames = append(tableNames, table.TableName)
-	}
-	topics := k.eventRouter.GetActiveTopics(tableNames)
+	topics := k.eventRouter.GetActiveTopics(tables)
 	for _, topic := range topics {
 		partitionNum, err := k.topicManager.GetPartitionNum(topic)
 		if err != nil {
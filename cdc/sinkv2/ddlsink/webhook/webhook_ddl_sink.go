@@ -0,0 +1,130 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/webhook"
+	"go.uber.org/zap"
+)
+
+// Assert DDLEventSink implementation
+var _ ddlsink.DDLEventSink = (*ddlSink)(nil)
+
+// ddlSink posts the DDL events and checkpoints to the webhook.
+type ddlSink struct {
+	// id indicates which changefeed this sink belongs to.
+	id     model.ChangeFeedID
+	client *webhook.Client
+
+	mu sync.Mutex
+	// encoder is not thread-safe, so it is protected by mu.
+	encoder        codec.EventBatchEncoder
+	lastCheckpoint uint64
+}
+
+// NewWebhookDDLSink creates a ddl sink for webhook.
+func NewWebhookDDLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*ddlSink, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	cfg := webhook.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	encoderConfig, err := util.GetEncoderConfig(sinkURI, cfg.Protocol,
+		replicaConfig, cfg.MaxMessageBytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	encoderBuilder, err := builder.NewEventBatchEncoderBuilder(ctx, encoderConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+	client, err := webhook.NewClient(cfg, changefeedID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ddlSink{
+		id:      changefeedID,
+		client:  client,
+		encoder: encoderBuilder.Build(),
+	}, nil
+}
+
+// WriteDDLEvent posts the DDL event to the webhook.
+func (d *ddlSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	msg, err := d.encoder.EncodeDDLEvent(ddl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if msg == nil {
+		log.Info("Skip ddl event", zap.Uint64("commitTs", ddl.CommitTs),
+			zap.String("query", ddl.Query),
+			zap.String("namespace", d.id.Namespace),
+			zap.String("changefeed", d.id.ID))
+		return nil
+	}
+	return errors.Trace(d.client.Send(ctx, []*common.Message{msg}))
+}
+
+// WriteCheckpointTs posts the checkpoint to the webhook if it advances.
+func (d *ddlSink) WriteCheckpointTs(ctx context.Context,
+	ts uint64, tables []*model.TableInfo,
+) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if ts <= d.lastCheckpoint {
+		return nil
+	}
+	msg, err := d.encoder.EncodeCheckpointEvent(ts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Some protocols, such as canal-json without the TiDB extension,
+	// do not encode the checkpoints.
+	if msg != nil {
+		if err := d.client.Send(ctx, []*common.Message{msg}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	d.lastCheckpoint = ts
+	return nil
+}
+
+// Close closes the sink.
+func (d *ddlSink) Close() error {
+	d.client.Close()
+	return nil
+}
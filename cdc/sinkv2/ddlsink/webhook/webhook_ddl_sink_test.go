@@ -0,0 +1,111 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, func() []map[string]interface{}) {
+	var (
+		mu       sync.Mutex
+		messages []map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var batch []map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &batch))
+		mu.Lock()
+		messages = append(messages, batch...)
+		mu.Unlock()
+	}))
+	return server, func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return messages
+	}
+}
+
+func TestWriteDDLEvent(t *testing.T) {
+	t.Parallel()
+
+	server, messages := newTestServer(t)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = config.ProtocolCanalJSON.String()
+	sink, err := NewWebhookDDLSink(ctx, sinkURI, replicaConfig)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	ddl := &model.DDLEvent{
+		CommitTs: 100,
+		Query:    "create table test.t(id int primary key)",
+		Type:     timodel.ActionCreateTable,
+		TableInfo: &model.TableInfo{
+			TableName: model.TableName{Schema: "test", Table: "t"},
+		},
+	}
+	require.NoError(t, sink.WriteDDLEvent(ctx, ddl))
+	require.Len(t, messages(), 1)
+	require.Equal(t, ddl.Query, messages()[0]["sql"])
+	require.Equal(t, true, messages()[0]["isDdl"])
+
+	// canal-json does not encode the checkpoints without the TiDB extension.
+	require.NoError(t, sink.WriteCheckpointTs(ctx, 200, nil))
+	require.Len(t, messages(), 1)
+}
+
+func TestWriteCheckpointTs(t *testing.T) {
+	t.Parallel()
+
+	server, messages := newTestServer(t)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(server.URL + "?enable-tidb-extension=true")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = config.ProtocolCanalJSON.String()
+	sink, err := NewWebhookDDLSink(ctx, sinkURI, replicaConfig)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.WriteCheckpointTs(ctx, 100, nil))
+	require.Len(t, messages(), 1)
+	// the checkpoint is sent only when it advances.
+	require.NoError(t, sink.WriteCheckpointTs(ctx, 100, nil))
+	require.Len(t, messages(), 1)
+	require.NoError(t, sink.WriteCheckpointTs(ctx, 200, nil))
+	require.Len(t, messages(), 2)
+	require.Equal(t, "TIDB_WATERMARK", messages()[1]["type"])
+}
@@ -23,12 +23,14 @@ import (
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	rcommon "github.com/pingcap/tiflow/cdc/redo/common"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/metrics"
 	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
 	"github.com/pingcap/tiflow/cdc/sinkv2/util"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
@@ -116,8 +118,10 @@ func NewCloudStorageSink(ctx context.Context,
 		return nil, errors.Trace(err)
 	}
 
-	// get cloud storage file extension according to the specific protocol.
-	ext := util.GetFileExtension(protocol)
+	// get cloud storage file extension according to the specific protocol,
+	// the compression codec is appended to it if the data files are compressed,
+	// e.g. ".csv.gz".
+	ext := util.GetFileExtension(protocol) + compression.FileExtension(cfg.Compression)
 	// the last param maxMsgBytes is mainly to limit the size of a single message for
 	// batch protocols in mq scenario. In cloud storage sink, we just set it to max int.
 	encoderConfig, err := util.GetEncoderConfig(sinkURI, protocol, replicaConfig, math.MaxInt)
@@ -134,11 +138,21 @@ func NewCloudStorageSink(ctx context.Context,
 	s.defragmenter = newDefragmenter(ctx)
 	orderedCh := s.defragmenter.orderedOut()
 	s.statistics = metrics.NewStatistics(ctx, sink.TxnSink)
-	s.writer = newDMLWriter(ctx, changefeedID, storage, cfg, ext, s.statistics, orderedCh, errCh)
+	// a parquet file can't be built by concatenating the encoded messages,
+	// so the events are encoded as a whole by the dmlWorker when flushing.
+	var fileEncoderBuilder codec.EncoderBuilder
+	if protocol == config.ProtocolParquet {
+		fileEncoderBuilder = encoderBuilder
+	}
+	s.writer = newDMLWriter(ctx, changefeedID, storage, cfg, ext,
+		fileEncoderBuilder, s.statistics, orderedCh, errCh)
 
 	// create a group of encoding workers.
 	for i := 0; i < defaultEncodingConcurrency; i++ {
-		encoder := encoderBuilder.Build()
+		var encoder codec.EventBatchEncoder
+		if fileEncoderBuilder == nil {
+			encoder = encoderBuilder.Build()
+		}
 		w := newEncodingWorker(i+1, changefeedID, encoder, s.msgCh, s.defragmenter, errCh)
 		w.run(ctx)
 		s.encodingWorkers = append(s.encodingWorkers, w)
@@ -18,6 +18,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
@@ -112,3 +114,153 @@ func TestCloudStorageWriteEvents(t *testing.T) {
 	err = s.Close()
 	require.Nil(t, err)
 }
+
+func TestCloudStorageWriteEventsParquet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	parentDir := t.TempDir()
+	uri := fmt.Sprintf("file:///%s?flush-interval=2s", parentDir)
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = config.ProtocolParquet.String()
+
+	errCh := make(chan error, 5)
+	s, err := NewCloudStorageSink(ctx, sinkURI, replicaConfig, errCh)
+	require.Nil(t, err)
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "table1"},
+		Version:   33,
+		TableInfo: &timodel.TableInfo{
+			Columns: []*timodel.ColumnInfo{
+				{ID: 1, Name: timodel.NewCIStr("c1"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+				{ID: 2, Name: timodel.NewCIStr("c2"), FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+			},
+		},
+	}
+	txns := make([]*eventsink.TxnCallbackableEvent, 0, 10)
+	var cnt uint64 = 0
+	batch := 100
+	tableStatus := state.TableSinkSinking
+	for i := 0; i < 10; i++ {
+		txn := &eventsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{
+				CommitTs:  100,
+				Table:     &model.TableName{Schema: "test", Table: "table1"},
+				TableInfo: tableInfo,
+			},
+			Callback: func() {
+				atomic.AddUint64(&cnt, uint64(batch))
+			},
+			SinkState: &tableStatus,
+		}
+		for j := 0; j < batch; j++ {
+			row := &model.RowChangedEvent{
+				CommitTs:  100,
+				Table:     &model.TableName{Schema: "test", Table: "table1"},
+				TableInfo: tableInfo,
+				Columns: []*model.Column{
+					{Name: "c1", Type: mysql.TypeLong, Value: int64(i*batch + j)},
+					{Name: "c2", Type: mysql.TypeVarchar, Value: []byte("hello world")},
+				},
+			}
+			txn.Event.Rows = append(txn.Event.Rows, row)
+		}
+		txns = append(txns, txn)
+	}
+	err = s.WriteEvents(txns...)
+	require.Nil(t, err)
+	time.Sleep(4 * time.Second)
+
+	// all the events are written into one parquet file.
+	tableDir := path.Join(parentDir, "test/table1/33")
+	files, err := os.ReadDir(tableDir)
+	require.Nil(t, err)
+	var fileNames []string
+	for _, f := range files {
+		fileNames = append(fileNames, f.Name())
+	}
+	require.ElementsMatch(t, []string{"CDC000001.parquet", "schema.json"}, fileNames)
+	content, err := os.ReadFile(path.Join(tableDir, "CDC000001.parquet"))
+	require.Nil(t, err)
+	require.Equal(t, "PAR1", string(content[:4]))
+
+	require.Equal(t, uint64(1000), atomic.LoadUint64(&cnt))
+	cancel()
+	err = s.Close()
+	require.Nil(t, err)
+}
+
+func TestCloudStorageWriteEventsWithCompression(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	parentDir := t.TempDir()
+	uri := fmt.Sprintf("file:///%s?flush-interval=2s&compression=gzip", parentDir)
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = config.ProtocolOpen.String()
+
+	errCh := make(chan error, 5)
+	s, err := NewCloudStorageSink(ctx, sinkURI, replicaConfig, errCh)
+	require.Nil(t, err)
+
+	tableInfo := &model.TableInfo{
+		TableName: model.TableName{Schema: "test", Table: "table1"},
+		Version:   33,
+		TableInfo: &timodel.TableInfo{
+			Columns: []*timodel.ColumnInfo{
+				{ID: 1, Name: timodel.NewCIStr("c1"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+				{ID: 2, Name: timodel.NewCIStr("c2"), FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+			},
+		},
+	}
+	var cnt uint64 = 0
+	tableStatus := state.TableSinkSinking
+	txn := &eventsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{
+			CommitTs:  100,
+			Table:     &model.TableName{Schema: "test", Table: "table1"},
+			TableInfo: tableInfo,
+		},
+		Callback: func() {
+			atomic.AddUint64(&cnt, 100)
+		},
+		SinkState: &tableStatus,
+	}
+	for i := 0; i < 100; i++ {
+		txn.Event.Rows = append(txn.Event.Rows, &model.RowChangedEvent{
+			CommitTs:  100,
+			Table:     &model.TableName{Schema: "test", Table: "table1"},
+			TableInfo: tableInfo,
+			Columns: []*model.Column{
+				{Name: "c1", Type: mysql.TypeLong, Value: int64(i)},
+				{Name: "c2", Type: mysql.TypeVarchar, Value: []byte("hello world")},
+			},
+		})
+	}
+	err = s.WriteEvents(txn)
+	require.Nil(t, err)
+	time.Sleep(4 * time.Second)
+
+	// the compression codec is recorded in the file extension.
+	tableDir := path.Join(parentDir, "test/table1/33")
+	files, err := os.ReadDir(tableDir)
+	require.Nil(t, err)
+	var fileNames []string
+	for _, f := range files {
+		fileNames = append(fileNames, f.Name())
+	}
+	require.ElementsMatch(t, []string{"CDC000001.json.gz", "schema.json"}, fileNames)
+	content, err := os.ReadFile(path.Join(tableDir, "CDC000001.json.gz"))
+	require.Nil(t, err)
+	content, err = compression.Decode(compression.GZIP, content)
+	require.Nil(t, err)
+	require.Equal(t, 100, strings.Count(string(content), "hello world"))
+
+	require.Equal(t, uint64(100), atomic.LoadUint64(&cnt))
+	cancel()
+	err = s.Close()
+	require.Nil(t, err)
+}
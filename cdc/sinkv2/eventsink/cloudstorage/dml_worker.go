@@ -25,10 +25,13 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sinkv2/metrics"
 	mcloudstorage "github.com/pingcap/tiflow/cdc/sinkv2/metrics/cloudstorage"
 	"github.com/pingcap/tiflow/engine/pkg/clock"
 	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/cloudstorage"
 	"github.com/prometheus/client_golang/prometheus"
@@ -49,11 +52,15 @@ type dmlWorker struct {
 	// fileIndex maintains a mapping of <table, indexWithDate>.
 	fileIndex map[versionedTable]*indexWithDate
 	// fileSize maintains a mapping of <table, file size>.
-	fileSize         map[versionedTable]uint64
-	wg               sync.WaitGroup
-	isClosed         uint64
-	errCh            chan<- error
-	extension        string
+	fileSize  map[versionedTable]uint64
+	wg        sync.WaitGroup
+	isClosed  uint64
+	errCh     chan<- error
+	extension string
+	// fileEncoder encodes all the events of a data file as a whole when the
+	// file is flushed. It's only set for the protocols whose encoded messages
+	// can't be simply concatenated, e.g. parquet.
+	fileEncoder      codec.FileEventBatchEncoder
 	statistics       *metrics.Statistics
 	clock            clock.Clock
 	bufferPool       sync.Pool
@@ -93,6 +100,7 @@ func newDMLWorker(
 	storage storage.ExternalStorage,
 	config *cloudstorage.Config,
 	extension string,
+	fileEncoder codec.FileEventBatchEncoder,
 	statistics *metrics.Statistics,
 	errCh chan<- error,
 ) *dmlWorker {
@@ -106,6 +114,7 @@ func newDMLWorker(
 		fileIndex:     make(map[versionedTable]*indexWithDate),
 		fileSize:      make(map[versionedTable]uint64),
 		extension:     extension,
+		fileEncoder:   fileEncoder,
 		errCh:         errCh,
 		statistics:    statistics,
 		clock:         clock.New(),
@@ -227,18 +236,42 @@ func (d *dmlWorker) writeDataFile(ctx context.Context, path string, events []eve
 	defer d.bufferPool.Put(buf)
 	buf.Reset()
 
+	var msgs []*common.Message
 	for _, frag := range events {
-		msgs := frag.encodedMsgs
 		d.statistics.ObserveRows(frag.event.Event.Rows...)
-		for _, msg := range msgs {
-			d.metricWriteBytes.Add(float64(len(msg.Value)))
-			rowsCnt += msg.GetRowsCount()
-			buf.Write(msg.Value)
-			callbacks = append(callbacks, msg.Callback)
+		if d.fileEncoder == nil {
+			msgs = append(msgs, frag.encodedMsgs...)
+			continue
+		}
+		rows := frag.event.Event.Rows
+		for idx, row := range rows {
+			var callback func()
+			if idx == len(rows)-1 {
+				callback = frag.event.Callback
+			}
+			if err := d.fileEncoder.AppendRowChangedEvent(ctx, "", row, callback); err != nil {
+				return err
+			}
+		}
+	}
+	if d.fileEncoder != nil {
+		var err error
+		if msgs, err = d.fileEncoder.BuildFile(); err != nil {
+			return err
 		}
 	}
+	for _, msg := range msgs {
+		rowsCnt += msg.GetRowsCount()
+		buf.Write(msg.Value)
+		callbacks = append(callbacks, msg.Callback)
+	}
+	data, err := compression.Encode(d.config.Compression, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	d.metricWriteBytes.Add(float64(len(data)))
 	if err := d.statistics.RecordBatchExecution(func() (int, error) {
-		err := d.storage.WriteFile(ctx, path, buf.Bytes())
+		err := d.storage.WriteFile(ctx, path, data)
 		if err != nil {
 			return 0, err
 		}
@@ -329,6 +362,12 @@ func (d *dmlWorker) backgroundDispatchTasks(ctx context.Context, ch *chann.Chann
 						d.fileSize[table] += uint64(len(msg.Value))
 					}
 				}
+				// the events haven't been encoded yet, use the approximate size instead.
+				if d.fileEncoder != nil {
+					for _, row := range frag.event.Event.Rows {
+						d.fileSize[table] += uint64(row.ApproximateBytes())
+					}
+				}
 				// if the file size exceeds the upper limit, emit the flush task containing the table
 				// as soon as possible.
 				if d.fileSize[table] > uint64(d.config.FileSize) {
@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
 	"github.com/pingcap/tiflow/cdc/sinkv2/metrics"
 	"github.com/pingcap/tiflow/pkg/chann"
 	"github.com/pingcap/tiflow/pkg/hash"
@@ -43,6 +44,7 @@ func newDMLWriter(ctx context.Context,
 	storage storage.ExternalStorage,
 	config *cloudstorage.Config,
 	extension string,
+	fileEncoderBuilder codec.EncoderBuilder,
 	statistics *metrics.Statistics,
 	inputCh <-chan eventFragment,
 	errCh chan<- error,
@@ -64,7 +66,12 @@ func newDMLWriter(ctx context.Context,
 	}()
 
 	for i := 0; i < config.WorkerCount; i++ {
-		d := newDMLWorker(i, changefeedID, storage, w.config, extension, statistics, errCh)
+		var fileEncoder codec.FileEventBatchEncoder
+		if fileEncoderBuilder != nil {
+			fileEncoder = fileEncoderBuilder.Build().(codec.FileEventBatchEncoder)
+		}
+		d := newDMLWorker(i, changefeedID, storage, w.config, extension,
+			fileEncoder, statistics, errCh)
 		w.workerChannels[i] = chann.New[eventFragment]()
 		d.run(ctx, w.workerChannels[i])
 		w.workers = append(w.workers, d)
@@ -80,6 +80,13 @@ func (w *encodingWorker) run(ctx context.Context) {
 }
 
 func (w *encodingWorker) encodeEvents(ctx context.Context, frag eventFragment) error {
+	// the events are encoded by the dmlWorker when the data file is flushed
+	// if the encoded messages can't be simply concatenated, e.g. parquet.
+	if w.encoder == nil {
+		w.defragmenter.registerFrag(frag)
+		return nil
+	}
+
 	var err error
 	length := len(frag.event.Event.Rows)
 
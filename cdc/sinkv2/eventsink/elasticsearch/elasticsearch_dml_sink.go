@@ -0,0 +1,211 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"go.uber.org/zap"
+)
+
+// Assert EventSink[E event.TableEvent] implementation
+var _ eventsink.EventSink[*model.RowChangedEvent] = (*dmlSink)(nil)
+
+// dmlSink is the elasticsearch sink.
+// It converts the row changed events to documents and writes them to
+// elasticsearch by the bulk API.
+type dmlSink struct {
+	changefeedID model.ChangeFeedID
+	cfg          *elasticsearch.Config
+	client       *elasticsearch.Client
+	// eventCh caches the events to be sent.
+	// It is an unbounded channel.
+	eventCh *chann.Chann[*eventsink.RowChangeCallbackableEvent]
+	// indices are the indices whose mappings are put by the sink.
+	indices map[string]struct{}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewElasticsearchDMLSink creates an elasticsearch dmlSink.
+func NewElasticsearchDMLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan error,
+) (*dmlSink, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	cfg := elasticsearch.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	client, err := elasticsearch.NewClient(cfg, changefeedID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &dmlSink{
+		changefeedID: changefeedID,
+		cfg:          cfg,
+		client:       client,
+		eventCh:      chann.New[*eventsink.RowChangeCallbackableEvent](),
+		indices:      make(map[string]struct{}),
+		cancel:       cancel,
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.run(ctx); err != nil && errors.Cause(err) != context.Canceled {
+			select {
+			case <-ctx.Done():
+				return
+			case errCh <- err:
+			default:
+				log.Error("Error channel is full in elasticsearch DML sink",
+					zap.String("namespace", changefeedID.Namespace),
+					zap.String("changefeed", changefeedID.ID),
+					zap.Error(err))
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// WriteEvents writes events to the sink.
+// This is an asynchronously and thread-safe method.
+func (s *dmlSink) WriteEvents(rows ...*eventsink.RowChangeCallbackableEvent) error {
+	for _, row := range rows {
+		if row.GetTableSinkState() != state.TableSinkSinking {
+			// The table where the event comes from is in stopping, so it's safe
+			// to drop the event directly.
+			row.Callback()
+			continue
+		}
+		// This never be blocked because this is an unbounded channel.
+		s.eventCh.In() <- row
+	}
+	return nil
+}
+
+// run writes the events to elasticsearch. A bulk request is sent when the
+// batch is full or the flush interval is reached.
+func (s *dmlSink) run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	rows := make([]*eventsink.RowChangeCallbackableEvent, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case row, ok := <-s.eventCh.Out():
+			if !ok {
+				log.Warn("elasticsearch DML sink event channel closed",
+					zap.String("namespace", s.changefeedID.Namespace),
+					zap.String("changefeed", s.changefeedID.ID))
+				return nil
+			}
+			rows = append(rows, row)
+			if len(rows) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(rows) == 0 {
+				continue
+			}
+		}
+		if err := s.flush(ctx, rows); err != nil {
+			return errors.Trace(err)
+		}
+		rows = rows[:0]
+		ticker.Reset(s.cfg.FlushInterval)
+	}
+}
+
+func (s *dmlSink) flush(ctx context.Context, rows []*eventsink.RowChangeCallbackableEvent) error {
+	actions := make([]*elasticsearch.Action, 0, len(rows))
+	for _, row := range rows {
+		index := s.cfg.IndexName(row.Event.Table.Schema, row.Event.Table.Table)
+		if err := s.ensureMapping(ctx, index, row.Event.TableInfo); err != nil {
+			return errors.Trace(err)
+		}
+		rowActions, err := elasticsearch.NewActions(index, row.Event)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		actions = append(actions, rowActions...)
+	}
+	// The handle keys of the rows in a transaction may be swapped, so the
+	// deletions are sent before the other actions of the same transaction.
+	sort.SliceStable(actions, func(i, j int) bool {
+		if actions[i].Version != actions[j].Version {
+			return actions[i].Version < actions[j].Version
+		}
+		return actions[i].Type == elasticsearch.ActionDelete &&
+			actions[j].Type != elasticsearch.ActionDelete
+	})
+	if err := s.client.Bulk(ctx, actions); err != nil {
+		return errors.Trace(err)
+	}
+	for _, row := range rows {
+		row.Callback()
+	}
+	return nil
+}
+
+// ensureMapping puts the mapping of the table when the index is written by
+// the sink for the first time, so the indices of the tables created before
+// the changefeed are mapped by the table schemas too.
+func (s *dmlSink) ensureMapping(
+	ctx context.Context, index string, tableInfo *model.TableInfo,
+) error {
+	if _, ok := s.indices[index]; ok || tableInfo == nil || tableInfo.TableInfo == nil {
+		return nil
+	}
+	if err := s.client.PutTableMapping(ctx, index, tableInfo); err != nil {
+		return errors.Trace(err)
+	}
+	s.indices[index] = struct{}{}
+	return nil
+}
+
+// Close closes the sink.
+func (s *dmlSink) Close() error {
+	s.cancel()
+	s.eventCh.Close()
+	// We need to drain the channel to make sure the channel is closed
+	// properly, otherwise it will cause the goroutine leak.
+	for range s.eventCh.Out() {
+	}
+	s.wg.Wait()
+	s.client.Close()
+	return nil
+}
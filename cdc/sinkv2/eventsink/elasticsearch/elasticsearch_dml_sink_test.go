@@ -0,0 +1,135 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/elasticsearch"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestElasticsearchWriteEvents(t *testing.T) {
+	t.Parallel()
+
+	server := elasticsearch.NewMockServer()
+	defer server.Close()
+	sinkURI, err := url.Parse(server.URL + "?batch-size=3&flush-interval=50ms")
+	require.NoError(t, err)
+	sinkURI.Scheme = "elasticsearch"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	s, err := NewElasticsearchDMLSink(ctx, sinkURI, config.GetDefaultReplicaConfig(), errCh)
+	require.NoError(t, err)
+
+	tableInfo := &model.TableInfo{TableInfo: &timodel.TableInfo{
+		Columns: []*timodel.ColumnInfo{
+			{Name: timodel.NewCIStr("id"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+			{Name: timodel.NewCIStr("name"), FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+		},
+	}}
+	newCols := func(id int64, name string) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: id, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte(name)},
+		}
+	}
+	var flushed atomic.Int32
+	sinking := state.TableSinkSinking
+	newRow := func(commitTs uint64, preCols, cols []*model.Column) *eventsink.RowChangeCallbackableEvent {
+		return &eventsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs:   commitTs,
+				Table:      &model.TableName{Schema: "Test", Table: "T"},
+				TableInfo:  tableInfo,
+				PreColumns: preCols,
+				Columns:    cols,
+			},
+			Callback:  func() { flushed.Inc() },
+			SinkState: &sinking,
+		}
+	}
+	require.NoError(t, s.WriteEvents(
+		newRow(100, nil, newCols(1, "a")),
+		newRow(100, nil, newCols(2, "b")),
+		newRow(100, nil, newCols(3, "c")),
+		// the handle keys of the rows are swapped in a transaction.
+		newRow(101, newCols(1, "a"), newCols(2, "a")),
+		newRow(101, newCols(2, "b"), newCols(1, "b")),
+		newRow(102, newCols(3, "c"), nil),
+	))
+	require.Eventually(t, func() bool {
+		return flushed.Load() == 6
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Close())
+
+	docs := server.Documents("test.t")
+	require.Len(t, docs, 2)
+	require.Equal(t, "b", docs["1"]["name"])
+	require.Equal(t, "a", docs["2"]["name"])
+	require.Len(t, server.Mapping("test.t"), 2)
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+func TestElasticsearchWriteEventsFailed(t *testing.T) {
+	t.Parallel()
+
+	server := elasticsearch.NewMockServer()
+	defer server.Close()
+	sinkURI, err := url.Parse(server.URL + "?batch-size=1")
+	require.NoError(t, err)
+	sinkURI.Scheme = "elasticsearch"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	s, err := NewElasticsearchDMLSink(ctx, sinkURI, config.GetDefaultReplicaConfig(), errCh)
+	require.NoError(t, err)
+
+	// the rows without handle keys can not be written.
+	sinking := state.TableSinkSinking
+	require.NoError(t, s.WriteEvents(&eventsink.RowChangeCallbackableEvent{
+		Event: &model.RowChangedEvent{
+			CommitTs: 100,
+			Table:    &model.TableName{Schema: "test", Table: "t"},
+			Columns:  []*model.Column{{Name: "id", Type: mysql.TypeLong, Value: int64(1)}},
+		},
+		Callback:  func() {},
+		SinkState: &sinking,
+	}))
+	select {
+	case err := <-errCh:
+		require.Regexp(t, ".*document id of the rows in table.*", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the error is not reported")
+	}
+	require.NoError(t, s.Close())
+}
@@ -22,9 +22,11 @@ import (
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/blackhole"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/cloudstorage"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/elasticsearch"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/txn"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/webhook"
 	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -73,6 +75,13 @@ func New(ctx context.Context,
 		}
 		s.rowSink = mqs
 		s.sinkType = sink.RowSink
+	case sink.PulsarScheme, sink.PulsarSSLScheme:
+		mqs, err := newPulsarDMLSink(ctx, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.rowSink = mqs
+		s.sinkType = sink.RowSink
 	case sink.S3Scheme, sink.FileScheme, sink.GCSScheme, sink.GSScheme, sink.AzblobScheme, sink.AzureScheme, sink.CloudStorageNoopScheme:
 		storageSink, err := cloudstorage.NewCloudStorageSink(ctx, sinkURI, cfg, errCh)
 		if err != nil {
@@ -80,6 +89,20 @@ func New(ctx context.Context,
 		}
 		s.txnSink = storageSink
 		s.sinkType = sink.TxnSink
+	case sink.HTTPScheme, sink.HTTPSScheme:
+		ws, err := webhook.NewWebhookDMLSink(ctx, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.rowSink = ws
+		s.sinkType = sink.RowSink
+	case sink.ElasticsearchScheme, sink.ElasticsearchSSLScheme:
+		es, err := elasticsearch.NewElasticsearchDMLSink(ctx, sinkURI, cfg, errCh)
+		if err != nil {
+			return nil, err
+		}
+		s.rowSink = es
+		s.sinkType = sink.RowSink
 	case sink.BlackHoleScheme:
 		bs := blackhole.New()
 		s.rowSink = bs
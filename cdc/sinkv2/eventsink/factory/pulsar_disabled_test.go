@@ -0,0 +1,37 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build !pulsar
+// +build !pulsar
+
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPulsarSinkRequiresBuildTag(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(context.Background(),
+		"pulsar://127.0.0.1:6650/test?protocol=canal-json",
+		config.GetDefaultReplicaConfig(), make(chan error, 1))
+	require.True(t, cerror.ErrSinkURIInvalid.Equal(errors.Cause(err)))
+	require.Regexp(t, ".*built with the pulsar tag.*", err)
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 )
 
@@ -38,5 +39,8 @@ type DMLProducer interface {
 // there is no way to safely close errCh by the sender.
 // So we let the GC close errCh.
 // It's usually a buffered channel.
+// deadLetter records the messages which are rejected by Kafka for their
+// content, it is nil if the dead letter queue is not configured.
 type Factory func(ctx context.Context, client sarama.Client,
-	adminClient kafka.ClusterAdminClient, errCh chan error) (DMLProducer, error)
+	adminClient kafka.ClusterAdminClient, errCh chan error,
+	deadLetter dlq.Writer) (DMLProducer, error)
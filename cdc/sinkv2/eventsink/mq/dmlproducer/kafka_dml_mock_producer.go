@@ -19,6 +19,7 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	mqv1 "github.com/pingcap/tiflow/cdc/sink/mq"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 )
@@ -33,7 +34,7 @@ type MockDMLProducer struct {
 
 // NewDMLMockProducer creates a mock producer.
 func NewDMLMockProducer(_ context.Context, _ sarama.Client,
-	_ kafka.ClusterAdminClient, _ chan error,
+	_ kafka.ClusterAdminClient, _ chan error, _ dlq.Writer,
 ) (DMLProducer, error) {
 	return &MockDMLProducer{
 		events: make(map[mqv1.TopicPartitionKey][]*common.Message),
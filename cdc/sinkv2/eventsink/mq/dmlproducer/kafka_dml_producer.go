@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	collector "github.com/pingcap/tiflow/cdc/sinkv2/metrics/mq/kafka"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -38,6 +39,9 @@ var _ DMLProducer = (*kafkaDMLProducer)(nil)
 // messageMetaData is used to store the callback function for the message.
 type messageMetaData struct {
 	callback eventsink.CallbackFunc
+	// message is the message to be sent, it is recorded in the dead
+	// letter queue if Kafka rejects it.
+	message *common.Message
 }
 
 // kafkaDMLProducer is used to send messages to kafka.
@@ -62,6 +66,9 @@ type kafkaDMLProducer struct {
 	// failpointCh is used to inject failpoints to the run loop.
 	// Only used in test.
 	failpointCh chan error
+	// deadLetter records the messages which are rejected by Kafka for
+	// their content. It is nil if the dead letter queue is not configured.
+	deadLetter dlq.Writer
 }
 
 // NewKafkaDMLProducer creates a new kafka producer.
@@ -70,6 +77,7 @@ func NewKafkaDMLProducer(
 	client sarama.Client,
 	adminClient pkafka.ClusterAdminClient,
 	errCh chan error,
+	deadLetter dlq.Writer,
 ) (DMLProducer, error) {
 	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
 	log.Info("Starting kafka DML producer ...",
@@ -109,6 +117,7 @@ func NewKafkaDMLProducer(
 		closed:        false,
 		closedChan:    make(chan struct{}),
 		failpointCh:   make(chan error, 1),
+		deadLetter:    deadLetter,
 	}
 
 	// Start collecting metrics.
@@ -163,7 +172,7 @@ func (k *kafkaDMLProducer) AsyncSendMessage(
 		Partition: partition,
 		Key:       sarama.StringEncoder(message.Key),
 		Value:     sarama.ByteEncoder(message.Value),
-		Metadata:  messageMetaData{callback: message.Callback},
+		Metadata:  messageMetaData{callback: message.Callback, message: message},
 	}
 
 	select {
@@ -272,7 +281,30 @@ func (k *kafkaDMLProducer) run(ctx context.Context) error {
 			if err == nil {
 				return nil
 			}
-			return cerror.WrapError(cerror.ErrKafkaAsyncSendMessage, err)
+			if k.deadLetter == nil || !isDeadLetterError(err.Err) {
+				return cerror.WrapError(cerror.ErrKafkaAsyncSendMessage, err)
+			}
+			// The message can never be sent, so record it in the dead letter
+			// queue and regard it as sent to keep the changefeed going.
+			meta := err.Msg.Metadata.(messageMetaData)
+			if dlqErr := k.deadLetter.WriteMessages(
+				ctx, err.Err, err.Msg.Topic, meta.message); dlqErr != nil {
+				return errors.Trace(dlqErr)
+			}
+			if meta.callback != nil {
+				meta.callback()
+			}
 		}
 	}
 }
+
+// isDeadLetterError returns true if Kafka rejects the message for its
+// content, so that sending it again can never succeed.
+func isDeadLetterError(err error) bool {
+	switch errors.Cause(err) {
+	case sarama.ErrMessageSizeTooLarge, sarama.ErrInvalidMessageSize,
+		sarama.ErrInvalidRecord:
+		return true
+	}
+	return false
+}
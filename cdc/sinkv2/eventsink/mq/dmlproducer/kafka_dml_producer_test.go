@@ -23,6 +23,7 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/dlq/mock"
 	kafkav1 "github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
@@ -84,7 +85,7 @@ func TestProducerAck(t *testing.T) {
 	require.Nil(t, err)
 	adminClient, err := kafka.NewMockAdminClient(config.BrokerEndpoints, saramaConfig)
 	require.Nil(t, err)
-	producer, err := NewKafkaDMLProducer(ctx, client, adminClient, errCh)
+	producer, err := NewKafkaDMLProducer(ctx, client, adminClient, errCh, nil)
 	require.Nil(t, err)
 	require.NotNil(t, producer)
 
@@ -150,7 +151,7 @@ func TestProducerSendMsgFailed(t *testing.T) {
 	require.Nil(t, err)
 	adminClient, err := kafka.NewMockAdminClient(config.BrokerEndpoints, saramaConfig)
 	require.Nil(t, err)
-	producer, err := NewKafkaDMLProducer(ctx, client, adminClient, errCh)
+	producer, err := NewKafkaDMLProducer(ctx, client, adminClient, errCh, nil)
 	defer func() {
 		producer.Close()
 
@@ -193,6 +194,61 @@ func TestProducerSendMsgFailed(t *testing.T) {
 	wg.Wait()
 }
 
+func TestProducerSendMsgToDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	leader, topic := initBroker(t, false)
+	defer leader.Close()
+
+	config := getConfig(leader.Addr())
+	errCh := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	saramaConfig, err := kafkav1.NewSaramaConfig(context.Background(), config)
+	require.Nil(t, err)
+	saramaConfig.Producer.Flush.MaxMessages = 1
+	saramaConfig.Producer.Retry.Max = 1
+	// This will make all the messages too large to be sent.
+	saramaConfig.Producer.MaxMessageBytes = 8
+
+	client, err := sarama.NewClient(config.BrokerEndpoints, saramaConfig)
+	require.Nil(t, err)
+	adminClient, err := kafka.NewMockAdminClient(config.BrokerEndpoints, saramaConfig)
+	require.Nil(t, err)
+	deadLetter := mock.NewMockWriter()
+	producer, err := NewKafkaDMLProducer(ctx, client, adminClient, errCh, deadLetter)
+	require.Nil(t, err)
+	defer producer.Close()
+
+	count := atomic.NewInt64(0)
+	for i := 0; i < 10; i++ {
+		err = producer.AsyncSendMessage(ctx, topic, int32(0), &common.Message{
+			Key:   []byte("test-key-1"),
+			Value: []byte("test-value"),
+			Callback: func() {
+				count.Add(1)
+			},
+		})
+		require.Nil(t, err)
+	}
+	// The messages are regarded as sent after they are written to
+	// the dead letter queue.
+	require.Eventuallyf(t, func() bool {
+		return count.Load() == 10
+	}, time.Second*5, time.Millisecond*10, "All msgs should be acked")
+
+	msgs, causes := deadLetter.Messages()
+	require.Len(t, msgs, 10)
+	require.Equal(t, []byte("test-value"), msgs[0].Value)
+	require.ErrorIs(t, causes[0], sarama.ErrMessageSizeTooLarge)
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("unexpected err: %s", err)
+	default:
+	}
+}
+
 func TestProducerDoubleClose(t *testing.T) {
 	t.Parallel()
 
@@ -211,7 +267,7 @@ func TestProducerDoubleClose(t *testing.T) {
 	require.Nil(t, err)
 	adminClient, err := kafka.NewMockAdminClient(config.BrokerEndpoints, saramaConfig)
 	require.Nil(t, err)
-	producer, err := NewKafkaDMLProducer(ctx, client, adminClient, errCh)
+	producer, err := NewKafkaDMLProducer(ctx, client, adminClient, errCh, nil)
 	require.Nil(t, err)
 	require.NotNil(t, producer)
 
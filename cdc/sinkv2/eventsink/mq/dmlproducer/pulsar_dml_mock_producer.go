@@ -0,0 +1,36 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package dmlproducer
+
+import (
+	"context"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	mqv1 "github.com/pingcap/tiflow/cdc/sink/mq"
+	ppulsar "github.com/pingcap/tiflow/pkg/sink/pulsar"
+)
+
+// NewPulsarMockDMLProducer creates a mock producer which has the signature
+// of PulsarFactory.
+func NewPulsarMockDMLProducer(_ context.Context, _ pulsar.Client,
+	_ *ppulsar.Config, _ chan error,
+) (DMLProducer, error) {
+	return &MockDMLProducer{
+		events: make(map[mqv1.TopicPartitionKey][]*common.Message),
+	}, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package dmlproducer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	ppulsar "github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"go.uber.org/zap"
+)
+
+// PulsarFactory is a function to create a pulsar producer.
+// errCh has the same semantics as the one of Factory.
+type PulsarFactory func(ctx context.Context, client pulsar.Client,
+	config *ppulsar.Config, errCh chan error) (DMLProducer, error)
+
+var _ DMLProducer = (*pulsarDMLProducer)(nil)
+
+// pulsarDMLProducer is used to send messages to pulsar asynchronously.
+type pulsarDMLProducer struct {
+	// id indicates which processor (changefeed) this sink belongs to.
+	id model.ChangeFeedID
+	// ctx is used to report errors from the send callbacks.
+	ctx context.Context
+	// client is used to create producers.
+	client pulsar.Client
+	config *ppulsar.Config
+	// producers caches the producer of each topic,
+	// since a pulsar producer can only send messages to one topic.
+	producers map[string]pulsar.Producer
+	// producersMu is used to protect `producers`.
+	producersMu sync.Mutex
+	// closedMu is used to protect `closed`.
+	// We need to ensure that closed producers are never written to.
+	closedMu sync.RWMutex
+	// closed is used to indicate whether the producer is closed.
+	// We also use it to guard against double closes.
+	closed bool
+	// errCh is used to report the errors of the send callbacks.
+	errCh chan error
+}
+
+// NewPulsarDMLProducer creates a new pulsar producer for replicating DML.
+func NewPulsarDMLProducer(
+	ctx context.Context,
+	client pulsar.Client,
+	config *ppulsar.Config,
+	errCh chan error,
+) (DMLProducer, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	log.Info("Starting pulsar DML producer ...",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID))
+
+	return &pulsarDMLProducer{
+		id:        changefeedID,
+		ctx:       ctx,
+		client:    client,
+		config:    config,
+		producers: make(map[string]pulsar.Producer),
+		errCh:     errCh,
+	}, nil
+}
+
+func (p *pulsarDMLProducer) AsyncSendMessage(
+	ctx context.Context, topic string,
+	partition int32, message *common.Message,
+) error {
+	// We have to hold the lock to avoid writing to a closed producer.
+	p.closedMu.RLock()
+	defer p.closedMu.RUnlock()
+
+	// If the producer is closed, we should skip the message and return an error.
+	if p.closed {
+		return cerror.ErrPulsarProducerClosed.GenWithStackByArgs()
+	}
+
+	producer, err := p.getProducer(topic)
+	if err != nil {
+		return err
+	}
+
+	msg := &pulsar.ProducerMessage{
+		Key:     string(message.Key),
+		Payload: message.Value,
+	}
+	ppulsar.SetPartition(msg, partition)
+	producer.SendAsync(ctx, msg,
+		func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+			if err != nil {
+				p.reportError(cerror.WrapError(cerror.ErrPulsarAsyncSendMessage, err))
+				return
+			}
+			if message.Callback != nil {
+				message.Callback()
+			}
+		})
+	return nil
+}
+
+// getProducer returns the producer of the topic, creates one if it does not exist.
+func (p *pulsarDMLProducer) getProducer(topic string) (pulsar.Producer, error) {
+	p.producersMu.Lock()
+	defer p.producersMu.Unlock()
+	if producer, ok := p.producers[topic]; ok {
+		return producer, nil
+	}
+	producer, err := p.client.CreateProducer(p.config.ProducerOptions(topic))
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	log.Info("Pulsar DML producer created",
+		zap.String("topic", topic),
+		zap.String("namespace", p.id.Namespace),
+		zap.String("changefeed", p.id.ID))
+	p.producers[topic] = producer
+	return producer, nil
+}
+
+func (p *pulsarDMLProducer) reportError(err error) {
+	select {
+	case <-p.ctx.Done():
+	case p.errCh <- err:
+		log.Error("Pulsar DML producer send error",
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID),
+			zap.Error(err))
+	default:
+		log.Error("Error channel is full in pulsar DML producer",
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID),
+			zap.Error(err))
+	}
+}
+
+func (p *pulsarDMLProducer) Close() {
+	// We have to hold the lock to synchronize closing with writing.
+	p.closedMu.Lock()
+	defer p.closedMu.Unlock()
+	// If the producer has already been closed, we should skip this close operation.
+	if p.closed {
+		log.Warn("Pulsar DML producer already closed",
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID))
+		return
+	}
+	p.closed = true
+	// Closing a pulsar producer waits until all pending messages are persisted,
+	// so we close them asynchronously to avoid getting stuck with an unhealthy
+	// pulsar cluster, which is the same as the kafka DML producer.
+	p.producersMu.Lock()
+	producers := p.producers
+	p.producers = nil
+	p.producersMu.Unlock()
+	go func() {
+		start := time.Now()
+		for _, producer := range producers {
+			producer.Close()
+		}
+		p.client.Close()
+		log.Info("Pulsar client closed in pulsar DML producer",
+			zap.Duration("duration", time.Since(start)),
+			zap.String("namespace", p.id.Namespace),
+			zap.String("changefeed", p.id.ID))
+	}()
+}
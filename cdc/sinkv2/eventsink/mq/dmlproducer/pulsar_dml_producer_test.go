@@ -0,0 +1,105 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package dmlproducer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestPulsarProducerAck(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := pulsar.NewMockClient()
+	errCh := make(chan error, 1)
+	producer, err := NewPulsarDMLProducer(ctx, client, pulsar.NewConfig(), errCh)
+	require.Nil(t, err)
+
+	topic := pulsar.DefaultMockTopicName
+	count := atomic.NewInt64(0)
+	for i := 0; i < 10; i++ {
+		for partition := int32(0); partition < 2; partition++ {
+			err = producer.AsyncSendMessage(ctx, topic, partition, &common.Message{
+				Key:   []byte("test-key-1"),
+				Value: []byte("test-value"),
+				Callback: func() {
+					count.Add(1)
+				},
+			})
+			require.Nil(t, err)
+		}
+	}
+	require.Equal(t, int64(20), count.Load())
+	require.Len(t, client.GetMessages(topic, 0), 10)
+	require.Len(t, client.GetMessages(topic, 1), 10)
+	require.Len(t, client.GetMessages(topic, 2), 0)
+	require.Equal(t, []byte("test-value"), client.GetMessages(topic, 1)[0].Payload)
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("unexpected err: %s", err)
+	default:
+	}
+
+	producer.Close()
+	require.Eventually(t, client.IsClosed, 5*time.Second, 10*time.Millisecond)
+	err = producer.AsyncSendMessage(ctx, topic, 0, &common.Message{
+		Key:   []byte("cancel"),
+		Value: nil,
+	})
+	require.ErrorIs(t, err, cerror.ErrPulsarProducerClosed)
+
+	// Close reentry.
+	producer.Close()
+}
+
+func TestPulsarProducerSendMsgFailed(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := pulsar.NewMockClient()
+	client.SendError = errors.New("message too large")
+	errCh := make(chan error, 1)
+	producer, err := NewPulsarDMLProducer(ctx, client, pulsar.NewConfig(), errCh)
+	require.Nil(t, err)
+	defer producer.Close()
+
+	err = producer.AsyncSendMessage(ctx, pulsar.DefaultMockTopicName, 0, &common.Message{
+		Key:      []byte("test-key-1"),
+		Value:    []byte("test-value"),
+		Callback: func() { t.Fatal("callback should not be called") },
+	})
+	require.Nil(t, err)
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, cerror.ErrPulsarAsyncSendMessage)
+		require.Regexp(t, ".*too large.*", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the error should be reported")
+	}
+}
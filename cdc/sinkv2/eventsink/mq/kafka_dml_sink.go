@@ -20,6 +20,8 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
@@ -82,9 +84,30 @@ func NewKafkaDMLSink(
 		return nil, cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
 	}
 
+	deadLetter, err := dlq.NewWriter(ctx, replicaConfig.Sink.DeadLetterQueue,
+		contextutil.ChangefeedIDFromCtx(ctx))
+	if err != nil {
+		// The client is closed by the producer once it is created,
+		// so we have to close it here.
+		if closeErr := client.Close(); closeErr != nil {
+			log.Error("Close sarama client failed in kafka "+
+				"DML sink", zap.Error(closeErr))
+		}
+		return nil, errors.Trace(err)
+	}
+	// The dead letter queue writer is closed by the sink after it is created.
+	defer func() {
+		if err != nil && deadLetter != nil {
+			if closeErr := deadLetter.Close(); closeErr != nil {
+				log.Error("Close dead letter queue writer failed in kafka "+
+					"DML sink", zap.Error(closeErr))
+			}
+		}
+	}()
+
 	log.Info("Try to create a DML sink producer",
 		zap.Any("baseConfig", baseConfig))
-	p, err := producerCreator(ctx, client, adminClient, errCh)
+	p, err := producerCreator(ctx, client, adminClient, errCh, deadLetter)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
 	}
@@ -106,7 +129,8 @@ func NewKafkaDMLSink(
 		return nil, errors.Trace(err)
 	}
 
-	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic)
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic,
+		contextutil.ChangefeedIDFromCtx(ctx), contextutil.UpstreamIDFromCtx(ctx))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -118,7 +142,7 @@ func NewKafkaDMLSink(
 	}
 
 	s, err := newSink(ctx, p, topicManager, eventRouter, encoderConfig,
-		replicaConfig.Sink.EncoderConcurrency, errCh)
+		replicaConfig.Sink.EncoderConcurrency, deadLetter, errCh)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	mqv1 "github.com/pingcap/tiflow/cdc/sink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sink/mq/manager"
@@ -32,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
@@ -60,6 +62,7 @@ func newSink(ctx context.Context,
 	eventRouter *dispatcher.EventRouter,
 	encoderConfig *common.Config,
 	encoderConcurrency int,
+	deadLetter dlq.Writer,
 	errCh chan error,
 ) (*dmlSink, error) {
 	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
@@ -71,7 +74,7 @@ func newSink(ctx context.Context,
 
 	statistics := metrics.NewStatistics(ctx, sink.RowSink)
 	worker := newWorker(changefeedID, encoderConfig.Protocol,
-		encoderBuilder, encoderConcurrency, producer, statistics)
+		encoderBuilder, encoderConcurrency, producer, deadLetter, statistics)
 	s := &dmlSink{
 		id:           changefeedID,
 		protocol:     encoderConfig.Protocol,
@@ -114,19 +117,53 @@ func (s *dmlSink) WriteEvents(rows ...*eventsink.RowChangeCallbackableEvent) err
 		if err != nil {
 			return errors.Trace(err)
 		}
-		partition := s.eventRouter.GetPartitionForRowChange(row.Event, partitionNum)
-		// This never be blocked because this is an unbounded channel.
-		s.worker.msgChan.In() <- mqEvent{
-			key: mqv1.TopicPartitionKey{
-				Topic: topic, Partition: partition,
-			},
-			rowEvent: row,
+		partitions, err := s.eventRouter.GetPartitionsForRowChange(row.Event, partitionNum)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, e := range splitRowEvent(topic, row, partitions) {
+			// This never be blocked because this is an unbounded channel.
+			s.worker.msgChan.In() <- e
 		}
 	}
 
 	return nil
 }
 
+// splitRowEvent wraps the row event into mq events by the partitions.
+// If the row event is split, its callback is called after all the
+// split events are sent.
+func splitRowEvent(
+	topic string,
+	row *eventsink.RowChangeCallbackableEvent,
+	partitions []dispatcher.RowPartition,
+) []mqEvent {
+	events := make([]mqEvent, 0, len(partitions))
+	if len(partitions) == 1 {
+		return append(events, mqEvent{
+			key:      mqv1.TopicPartitionKey{Topic: topic, Partition: partitions[0].Partition},
+			rowEvent: row,
+		})
+	}
+	remaining := atomic.NewInt32(int32(len(partitions)))
+	callback := func() {
+		if remaining.Dec() == 0 {
+			row.Callback()
+		}
+	}
+	for _, p := range partitions {
+		events = append(events, mqEvent{
+			key: mqv1.TopicPartitionKey{Topic: topic, Partition: p.Partition},
+			rowEvent: &eventsink.RowChangeCallbackableEvent{
+				Event:     p.Row,
+				Callback:  callback,
+				SinkState: row.SinkState,
+			},
+		})
+	}
+	return events
+}
+
 // Close closes the sink.
 func (s *dmlSink) Close() error {
 	s.worker.close()
@@ -22,12 +22,14 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
 	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/sink/kafka"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 )
 
 func initBroker(t *testing.T, partitionNum int) (*sarama.MockBroker, string) {
@@ -124,3 +126,37 @@ func TestWriteEvents(t *testing.T) {
 	err = s.Close()
 	require.Nil(t, err)
 }
+
+func TestSplitRowEvent(t *testing.T) {
+	t.Parallel()
+
+	var called atomic.Int32
+	tableSinkState := state.TableSinkSinking
+	row := &eventsink.RowChangeCallbackableEvent{
+		Event:     &model.RowChangedEvent{},
+		Callback:  func() { called.Inc() },
+		SinkState: &tableSinkState,
+	}
+
+	events := splitRowEvent("test", row, []dispatcher.RowPartition{
+		{Row: row.Event, Partition: 1},
+	})
+	require.Len(t, events, 1)
+	require.Equal(t, row, events[0].rowEvent)
+	require.Equal(t, int32(1), events[0].key.Partition)
+
+	deleteRow, insertRow := &model.RowChangedEvent{}, &model.RowChangedEvent{}
+	events = splitRowEvent("test", row, []dispatcher.RowPartition{
+		{Row: deleteRow, Partition: 1},
+		{Row: insertRow, Partition: 2},
+	})
+	require.Len(t, events, 2)
+	require.Equal(t, deleteRow, events[0].rowEvent.Event)
+	require.Equal(t, "test", events[0].key.Topic)
+	require.Equal(t, insertRow, events[1].rowEvent.Event)
+	require.Equal(t, int32(2), events[1].key.Partition)
+	events[1].rowEvent.Callback()
+	require.Equal(t, int32(0), called.Load())
+	events[0].rowEvent.Callback()
+	require.Equal(t, int32(1), called.Load())
+}
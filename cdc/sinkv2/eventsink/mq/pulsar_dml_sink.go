@@ -0,0 +1,120 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package mq
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
+	"github.com/pingcap/tiflow/cdc/sink/mq/manager"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sinkv2/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"go.uber.org/zap"
+)
+
+// NewPulsarDMLSink will verify the config and create a Pulsar DML Sink.
+func NewPulsarDMLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan error,
+	clientCreator pulsar.ClientCreator,
+	producerCreator dmlproducer.PulsarFactory,
+) (_ *dmlSink, err error) {
+	topic, err := util.GetTopic(sinkURI)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	baseConfig := pulsar.NewConfig()
+	if err := baseConfig.Apply(sinkURI); err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarInvalidConfig, err)
+	}
+
+	protocol, err := util.GetProtocol(replicaConfig.Sink.Protocol)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	client, err := clientCreator(baseConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewClient, err)
+	}
+
+	log.Info("Try to create a DML sink producer",
+		zap.Any("baseConfig", baseConfig))
+	p, err := producerCreator(ctx, client, baseConfig, errCh)
+	if err != nil {
+		client.Close()
+		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
+	}
+	// Preventing leaks when error occurs.
+	// This also closes the client in p.Close().
+	defer func() {
+		if err != nil {
+			p.Close()
+		}
+	}()
+
+	deadLetter, err := dlq.NewWriter(ctx, replicaConfig.Sink.DeadLetterQueue,
+		contextutil.ChangefeedIDFromCtx(ctx))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The dead letter queue writer is closed by the sink after it is created.
+	defer func() {
+		if err != nil && deadLetter != nil {
+			if closeErr := deadLetter.Close(); closeErr != nil {
+				log.Error("Close dead letter queue writer failed in pulsar "+
+					"DML sink", zap.Error(closeErr))
+			}
+		}
+	}()
+
+	topicManager := manager.NewPulsarTopicManager(client)
+	if _, err := topicManager.CreateTopicAndWaitUntilVisible(topic); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	eventRouter, err := dispatcher.NewEventRouter(replicaConfig, topic,
+		contextutil.ChangefeedIDFromCtx(ctx), contextutil.UpstreamIDFromCtx(ctx))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	encoderConfig, err := util.GetEncoderConfig(sinkURI, protocol, replicaConfig,
+		baseConfig.MaxMessageBytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	s, err := newSink(ctx, p, topicManager, eventRouter, encoderConfig,
+		replicaConfig.Sink.EncoderConcurrency, deadLetter, errCh)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s, nil
+}
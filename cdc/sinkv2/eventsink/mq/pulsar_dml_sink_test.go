@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package mq
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/sink/pulsar"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestWriteEventsToPulsar(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uri := "pulsar://127.0.0.1:6650/" + pulsar.DefaultMockTopicName +
+		"?max-message-bytes=1048576&protocol=canal-json"
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"a.*"}, PartitionRule: "table", TopicRule: "{schema}_{table}"},
+	}
+	require.Nil(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	client := pulsar.NewMockClient()
+	client.SetPartitionNum("a_b", 2)
+	s, err := NewPulsarDMLSink(ctx, sinkURI, replicaConfig, errCh,
+		pulsar.NewMockClientCreator(client), dmlproducer.NewPulsarDMLProducer)
+	require.Nil(t, err)
+	require.NotNil(t, s)
+
+	tableStatus := state.TableSinkSinking
+	row := &model.RowChangedEvent{
+		CommitTs: 1,
+		Table:    &model.TableName{Schema: "a", Table: "b"},
+		Columns:  []*model.Column{{Name: "col1", Type: 1, Value: "aa"}},
+	}
+
+	var acked atomic.Int64
+	events := make([]*eventsink.RowChangeCallbackableEvent, 0, 100)
+	for i := 0; i < 100; i++ {
+		events = append(events, &eventsink.RowChangeCallbackableEvent{
+			Event:     row,
+			Callback:  func() { acked.Add(1) },
+			SinkState: &tableStatus,
+		})
+	}
+
+	err = s.WriteEvents(events...)
+	require.Nil(t, err)
+	require.Eventually(t, func() bool {
+		return acked.Load() == 100
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, errCh, 0)
+	// All the events of a table are sent to the same partition.
+	msgs := client.GetAllMessages("a_b")
+	require.NotEmpty(t, msgs)
+	partition := msgs[0].Properties[pulsar.PartitionPropertyKey]
+	for _, msg := range msgs {
+		require.Equal(t, partition, msg.Properties[pulsar.PartitionPropertyKey])
+	}
+	require.Empty(t, client.GetAllMessages(pulsar.DefaultMockTopicName))
+	err = s.Close()
+	require.Nil(t, err)
+}
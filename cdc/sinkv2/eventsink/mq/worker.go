@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	mqv1 "github.com/pingcap/tiflow/cdc/sink/mq"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/dmlproducer"
@@ -65,6 +66,9 @@ type worker struct {
 
 	// producer is used to send the messages to the Kafka broker.
 	producer dmlproducer.DMLProducer
+	// deadLetter receives the events which can not be encoded.
+	// It is nil if the dead letter queue is not configured.
+	deadLetter dlq.Writer
 
 	// metricMQWorkerSendMessageDuration tracks the time duration cost on send messages.
 	metricMQWorkerSendMessageDuration prometheus.Observer
@@ -83,6 +87,7 @@ func newWorker(
 	builder codec.EncoderBuilder,
 	encoderConcurrency int,
 	producer dmlproducer.DMLProducer,
+	deadLetter dlq.Writer,
 	statistics *metrics.Statistics,
 ) *worker {
 	w := &worker{
@@ -90,8 +95,9 @@ func newWorker(
 		protocol:                          protocol,
 		msgChan:                           chann.New[mqEvent](),
 		ticker:                            time.NewTicker(flushInterval),
-		encoderGroup:                      codec.NewEncoderGroup(builder, encoderConcurrency, id),
+		encoderGroup:                      codec.NewEncoderGroup(builder, encoderConcurrency, id, deadLetter),
 		producer:                          producer,
+		deadLetter:                        deadLetter,
 		metricMQWorkerSendMessageDuration: mq.WorkerSendMessageDuration.WithLabelValues(id.Namespace, id.ID),
 		metricMQWorkerBatchSize:           mq.WorkerBatchSize.WithLabelValues(id.Namespace, id.ID),
 		metricMQWorkerBatchDuration:       mq.WorkerBatchDuration.WithLabelValues(id.Namespace, id.ID),
@@ -311,6 +317,14 @@ func (w *worker) close() {
 		// Do nothing. We do not care about the data.
 	}
 	w.producer.Close()
+	if w.deadLetter != nil {
+		if err := w.deadLetter.Close(); err != nil {
+			log.Warn("Close the dead letter queue failed",
+				zap.String("namespace", w.changeFeedID.Namespace),
+				zap.String("changefeed", w.changeFeedID.ID),
+				zap.Error(err))
+		}
+	}
 
 	mq.WorkerSendMessageDuration.DeleteLabelValues(w.changeFeedID.Namespace, w.changeFeedID.ID)
 	mq.WorkerBatchSize.DeleteLabelValues(w.changeFeedID.Namespace, w.changeFeedID.ID)
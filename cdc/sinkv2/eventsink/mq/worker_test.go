@@ -37,12 +37,12 @@ func newBatchEncodeWorker(ctx context.Context, t *testing.T) (*worker, dmlproduc
 	encoderConfig := common.NewConfig(config.ProtocolOpen).WithMaxMessageBytes(200)
 	builder, err := builder.NewEventBatchEncoderBuilder(context.Background(), encoderConfig)
 	require.Nil(t, err)
-	p, err := dmlproducer.NewDMLMockProducer(context.Background(), nil, nil, nil)
+	p, err := dmlproducer.NewDMLMockProducer(context.Background(), nil, nil, nil, nil)
 	require.Nil(t, err)
 	id := model.DefaultChangeFeedID("test")
 	encoderConcurrency := 4
 	statistics := metrics.NewStatistics(ctx, sink.RowSink)
-	return newWorker(id, config.ProtocolOpen, builder, encoderConcurrency, p, statistics), p
+	return newWorker(id, config.ProtocolOpen, builder, encoderConcurrency, p, nil, statistics), p
 }
 
 func newNonBatchEncodeWorker(ctx context.Context, t *testing.T) (*worker, dmlproducer.DMLProducer) {
@@ -50,12 +50,12 @@ func newNonBatchEncodeWorker(ctx context.Context, t *testing.T) (*worker, dmlpro
 	encoderConfig := common.NewConfig(config.ProtocolCanalJSON).WithMaxMessageBytes(200)
 	builder, err := builder.NewEventBatchEncoderBuilder(context.Background(), encoderConfig)
 	require.Nil(t, err)
-	p, err := dmlproducer.NewDMLMockProducer(context.Background(), nil, nil, nil)
+	p, err := dmlproducer.NewDMLMockProducer(context.Background(), nil, nil, nil, nil)
 	require.Nil(t, err)
 	id := model.DefaultChangeFeedID("test")
 	encoderConcurrency := 4
 	statistics := metrics.NewStatistics(ctx, sink.RowSink)
-	return newWorker(id, config.ProtocolCanalJSON, builder, encoderConcurrency, p, statistics), p
+	return newWorker(id, config.ProtocolCanalJSON, builder, encoderConcurrency, p, nil, statistics), p
 }
 
 func TestNonBatchEncode_SendMessages(t *testing.T) {
@@ -19,6 +19,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	dmysql "github.com/go-sql-driver/mysql"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dlq"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/metrics"
 	"github.com/pingcap/tiflow/cdc/sinkv2/metrics/txn"
@@ -62,6 +64,12 @@ type mysqlBackend struct {
 	events []*eventsink.TxnCallbackableEvent
 	rows   int
 
+	// deadLetter receives the rows which are rejected by the downstream,
+	// it is nil if the dead letter queue is not configured. It is shared
+	// by all the backends of a sink, so it is closed only once.
+	deadLetter      dlq.Writer
+	closeDeadLetter func() error
+
 	statistics                    *metrics.Statistics
 	metricTxnSinkDMLBatchCommit   prometheus.Observer
 	metricTxnSinkDMLBatchCallback prometheus.Observer
@@ -102,6 +110,21 @@ func NewMySQLBackends(
 	db.SetMaxIdleConns(cfg.WorkerCount)
 	db.SetMaxOpenConns(cfg.WorkerCount)
 
+	deadLetter, err := dlq.NewWriter(ctx, replicaConfig.Sink.DeadLetterQueue, changefeedID)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	var closeOnce sync.Once
+	closeDeadLetter := func() (err error) {
+		closeOnce.Do(func() {
+			if deadLetter != nil {
+				err = deadLetter.Close()
+			}
+		})
+		return
+	}
+
 	backends := make([]*mysqlBackend, 0, cfg.WorkerCount)
 	for i := 0; i < cfg.WorkerCount; i++ {
 		backends = append(backends, &mysqlBackend{
@@ -112,6 +135,9 @@ func NewMySQLBackends(
 			dmlMaxRetry: defaultDMLMaxRetry,
 			statistics:  statistics,
 
+			deadLetter:      deadLetter,
+			closeDeadLetter: closeDeadLetter,
+
 			metricTxnSinkDMLBatchCommit:   txn.SinkDMLBatchCommit.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 			metricTxnSinkDMLBatchCallback: txn.SinkDMLBatchCallback.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 		})
@@ -121,7 +147,8 @@ func NewMySQLBackends(
 		zap.String("changefeed", changefeed),
 		zap.Int("workerCount", cfg.WorkerCount),
 		zap.Bool("forceReplicate", cfg.ForceReplicate),
-		zap.Bool("enableOldValue", cfg.EnableOldValue))
+		zap.Bool("enableOldValue", cfg.EnableOldValue),
+		zap.Bool("deadLetterQueue", deadLetter != nil))
 	return backends, nil
 }
 
@@ -156,10 +183,17 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 
 	start := time.Now()
 	if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
-		if errors.Cause(err) != context.Canceled {
-			log.Error("execute DMLs failed", zap.Error(err))
+		if s.deadLetter != nil && isDeadLetterError(err) {
+			log.Warn("execute DMLs failed, retry row by row",
+				zap.String("changefeed", s.changefeed), zap.Error(err))
+			err = s.execRowByRow(ctx)
+		}
+		if err != nil {
+			if errors.Cause(err) != context.Canceled {
+				log.Error("execute DMLs failed", zap.Error(err))
+			}
+			return errors.Trace(err)
 		}
-		return errors.Trace(err)
 	}
 	startCallback := time.Now()
 	for _, callback := range dmls.callbacks {
@@ -180,12 +214,47 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 	return
 }
 
+// execRowByRow executes the buffered rows one by one, and writes the rows
+// rejected by the downstream into the dead letter queue. It gives up the
+// atomicity of the transactions to let the changefeed go on.
+func (s *mysqlBackend) execRowByRow(ctx context.Context) error {
+	for _, event := range s.events {
+		for _, row := range event.Event.Rows {
+			single := []*eventsink.TxnCallbackableEvent{{
+				Event: &model.SingleTableTxn{
+					Table:     event.Event.Table,
+					TableInfo: event.Event.TableInfo,
+					StartTs:   event.Event.StartTs,
+					CommitTs:  event.Event.CommitTs,
+					Rows:      []*model.RowChangedEvent{row},
+				},
+			}}
+			err := s.execDMLWithMaxRetries(ctx, s.prepareDMLsForEvents(single, 1))
+			if err == nil {
+				continue
+			}
+			if !isDeadLetterError(err) {
+				return errors.Trace(err)
+			}
+			if err := s.deadLetter.WriteRowChangedEvents(ctx, err, row); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
 // Close implements interface backend.
 func (s *mysqlBackend) Close() (err error) {
 	if s.db != nil {
 		err = s.db.Close()
 		s.db = nil
 	}
+	if s.closeDeadLetter != nil {
+		if dlqErr := s.closeDeadLetter(); err == nil {
+			err = dlqErr
+		}
+	}
 	return
 }
 
@@ -358,11 +427,18 @@ func hasHandleKey(cols []*model.Column) bool {
 
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
 func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
+	return s.prepareDMLsForEvents(s.events, s.rows)
+}
+
+// prepareDMLsForEvents converts the given events to query string list and args list.
+func (s *mysqlBackend) prepareDMLsForEvents(
+	events []*eventsink.TxnCallbackableEvent, rows int,
+) *preparedDMLs {
 	// TODO: use a sync.Pool to reduce allocations.
-	startTs := make([]uint64, 0, s.rows)
-	sqls := make([]string, 0, s.rows)
-	values := make([][]interface{}, 0, s.rows)
-	callbacks := make([]eventsink.CallbackFunc, 0, len(s.events))
+	startTs := make([]uint64, 0, rows)
+	sqls := make([]string, 0, rows)
+	values := make([][]interface{}, 0, rows)
+	callbacks := make([]eventsink.CallbackFunc, 0, len(events))
 	replaces := make(map[string][][]interface{})
 
 	// flushes the cached batch replace or insert DMLs,
@@ -381,7 +457,7 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 	translateToInsert := s.cfg.EnableOldValue && !s.cfg.SafeMode
 
 	rowCount := 0
-	for _, event := range s.events {
+	for _, event := range events {
 		if len(event.Event.Rows) == 0 {
 			continue
 		}
@@ -588,7 +664,7 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 	}, retry.WithBackoffBaseDelay(pmysql.BackoffBaseDelay.Milliseconds()),
 		retry.WithBackoffMaxDelay(pmysql.BackoffMaxDelay.Milliseconds()),
 		retry.WithMaxTries(s.dmlMaxRetry),
-		retry.WithIsRetryableErr(isRetryableDMLError))
+		retry.WithIsRetryableErr(s.isRetryableDMLError))
 }
 
 func logDMLTxnErr(
@@ -627,6 +703,35 @@ func isRetryableDMLError(err error) bool {
 	return true
 }
 
+// isRetryableDMLError does not retry the errors caused by the rows rejected
+// by the downstream if the dead letter queue is configured, because these
+// rows are written to the queue instead.
+func (s *mysqlBackend) isRetryableDMLError(err error) bool {
+	if s.deadLetter != nil && isDeadLetterError(err) {
+		return false
+	}
+	return isRetryableDMLError(err)
+}
+
+// isDeadLetterError returns true if the error is caused by the data of the
+// rows, and retrying the rows can never succeed.
+func isDeadLetterError(err error) bool {
+	errCode, ok := getSQLErrCode(err)
+	if !ok {
+		return false
+	}
+	switch errCode {
+	case mysql.ErrDupEntry, mysql.ErrDupEntryWithKeyName,
+		mysql.ErrNoReferencedRow, mysql.ErrNoReferencedRow2,
+		mysql.ErrRowIsReferenced, mysql.ErrRowIsReferenced2,
+		mysql.ErrDataTooLong, mysql.ErrTruncatedWrongValue,
+		mysql.ErrTruncatedWrongValueForField, mysql.ErrWarnDataOutOfRange,
+		mysql.ErrBadNull:
+		return true
+	}
+	return false
+}
+
 func getSQLErrCode(err error) (errors.ErrCode, bool) {
 	mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError)
 	if !ok {
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
+	mockdlq "github.com/pingcap/tiflow/cdc/sink/dlq/mock"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/metrics"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -628,6 +629,87 @@ func TestMysqlSinkNotRetryErrDupEntry(t *testing.T) {
 	require.Nil(t, sink.Close())
 }
 
+func TestMysqlSinkWriteDeadLetterQueue(t *testing.T) {
+	errDup := &dmysql.MySQLError{Number: mysql.ErrDupEntry, Message: "Duplicate entry '2'"}
+	newRow := func(v int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:       2,
+			CommitTs:      3,
+			ReplicatingTs: 1,
+			Table:         &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: v,
+				},
+			},
+		}
+	}
+	rows := []*model.RowChangedEvent{newRow(1), newRow(2)}
+
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		// the whole transaction fails.
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1`(`a`) VALUES (?),(?)").
+			WithArgs(1, 2).
+			WillReturnError(errDup)
+		mock.ExpectRollback()
+		// then the rows are executed one by one.
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1`(`a`) VALUES (?)").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1`(`a`) VALUES (?)").
+			WithArgs(2).
+			WillReturnError(errDup)
+		mock.ExpectRollback()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&safe-mode=false")
+	require.Nil(t, err)
+	sink, err := newMySQLBackend(ctx, sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn)
+	require.Nil(t, err)
+	deadLetter := mockdlq.NewMockWriter()
+	sink.deadLetter = deadLetter
+	sink.closeDeadLetter = deadLetter.Close
+
+	flushed := false
+	_ = sink.OnTxnEvent(&eventsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{Rows: rows},
+		Callback: func() { flushed = true },
+	})
+	require.Nil(t, sink.Flush(ctx))
+	require.True(t, flushed)
+
+	dlqRows, causes := deadLetter.Rows()
+	require.Equal(t, []*model.RowChangedEvent{rows[1]}, dlqRows)
+	require.Equal(t, errDup, errors.Cause(causes[0]))
+
+	require.Nil(t, sink.Close())
+	require.True(t, deadLetter.IsClosed())
+}
+
 func TestNewMySQLBackendExecDDL(t *testing.T) {
 	// TODO: fill it.
 }
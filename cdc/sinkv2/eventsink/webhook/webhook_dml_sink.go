@@ -0,0 +1,190 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/builder"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
+	"github.com/pingcap/tiflow/cdc/sinkv2/util"
+	"github.com/pingcap/tiflow/pkg/chann"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink/webhook"
+	"go.uber.org/zap"
+)
+
+// Assert EventSink[E event.TableEvent] implementation
+var _ eventsink.EventSink[*model.RowChangedEvent] = (*dmlSink)(nil)
+
+// dmlSink is the webhook sink.
+// It encodes the row changed events and posts them to the webhook in batches.
+type dmlSink struct {
+	changefeedID model.ChangeFeedID
+	cfg          *webhook.Config
+	client       *webhook.Client
+	encoder      codec.EventBatchEncoder
+	// eventCh caches the events to be sent.
+	// It is an unbounded channel.
+	eventCh *chann.Chann[*eventsink.RowChangeCallbackableEvent]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWebhookDMLSink creates a webhook dmlSink.
+func NewWebhookDMLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan error,
+) (*dmlSink, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	cfg := webhook.NewConfig()
+	if err := cfg.Apply(sinkURI, replicaConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	encoderConfig, err := util.GetEncoderConfig(sinkURI, cfg.Protocol,
+		replicaConfig, cfg.MaxMessageBytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	encoderBuilder, err := builder.NewEventBatchEncoderBuilder(ctx, encoderConfig)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+	client, err := webhook.NewClient(cfg, changefeedID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &dmlSink{
+		changefeedID: changefeedID,
+		cfg:          cfg,
+		client:       client,
+		encoder:      encoderBuilder.Build(),
+		eventCh:      chann.New[*eventsink.RowChangeCallbackableEvent](),
+		cancel:       cancel,
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.run(ctx); err != nil && errors.Cause(err) != context.Canceled {
+			select {
+			case <-ctx.Done():
+				return
+			case errCh <- err:
+			default:
+				log.Error("Error channel is full in webhook DML sink",
+					zap.String("namespace", changefeedID.Namespace),
+					zap.String("changefeed", changefeedID.ID),
+					zap.Error(err))
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// WriteEvents writes events to the sink.
+// This is an asynchronously and thread-safe method.
+func (s *dmlSink) WriteEvents(rows ...*eventsink.RowChangeCallbackableEvent) error {
+	for _, row := range rows {
+		if row.GetTableSinkState() != state.TableSinkSinking {
+			// The table where the event comes from is in stopping, so it's safe
+			// to drop the event directly.
+			row.Callback()
+			continue
+		}
+		// This never be blocked because this is an unbounded channel.
+		s.eventCh.In() <- row
+	}
+	return nil
+}
+
+// run encodes the events and posts them to the webhook. A request is sent
+// when the batch is full or the flush interval is reached.
+func (s *dmlSink) run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	rows := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case row, ok := <-s.eventCh.Out():
+			if !ok {
+				log.Warn("webhook DML sink event channel closed",
+					zap.String("namespace", s.changefeedID.Namespace),
+					zap.String("changefeed", s.changefeedID.ID))
+				return nil
+			}
+			if err := s.encoder.AppendRowChangedEvent(ctx, "", row.Event, row.Callback); err != nil {
+				return errors.Trace(err)
+			}
+			rows++
+			if rows < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if rows == 0 {
+				continue
+			}
+		}
+		if err := s.flush(ctx); err != nil {
+			return errors.Trace(err)
+		}
+		rows = 0
+		ticker.Reset(s.cfg.FlushInterval)
+	}
+}
+
+func (s *dmlSink) flush(ctx context.Context) error {
+	msgs := s.encoder.Build()
+	if err := s.client.Send(ctx, msgs); err != nil {
+		return errors.Trace(err)
+	}
+	for _, msg := range msgs {
+		if msg.Callback != nil {
+			msg.Callback()
+		}
+	}
+	return nil
+}
+
+// Close closes the sink.
+func (s *dmlSink) Close() error {
+	s.cancel()
+	s.eventCh.Close()
+	// We need to drain the channel to make sure the channel is closed
+	// properly, otherwise it will cause the goroutine leak.
+	for range s.eventCh.Out() {
+	}
+	s.wg.Wait()
+	s.client.Close()
+	return nil
+}
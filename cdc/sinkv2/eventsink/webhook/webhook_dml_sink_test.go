@@ -0,0 +1,141 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink/state"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestWebhookWriteEvents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		batches [][]map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var batch []map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &batch))
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(server.URL + "?batch-size=2&flush-interval=50ms")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = config.ProtocolCanalJSON.String()
+	errCh := make(chan error, 1)
+	s, err := NewWebhookDMLSink(ctx, sinkURI, replicaConfig, errCh)
+	require.NoError(t, err)
+
+	var flushed atomic.Int32
+	sinking := state.TableSinkSinking
+	stopping := state.TableSinkStopping
+	rows := make([]*eventsink.RowChangeCallbackableEvent, 0, 4)
+	for i := 0; i < 4; i++ {
+		sinkState := &sinking
+		if i == 3 {
+			sinkState = &stopping
+		}
+		rows = append(rows, &eventsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs: uint64(100 + i),
+				Table:    &model.TableName{Schema: "test", Table: "t"},
+				Columns: []*model.Column{{
+					Name: "id", Type: mysql.TypeLong, Value: int64(i),
+					Flag: model.HandleKeyFlag | model.PrimaryKeyFlag,
+				}},
+			},
+			Callback:  func() { flushed.Inc() },
+			SinkState: sinkState,
+		})
+	}
+	require.NoError(t, s.WriteEvents(rows...))
+	require.Eventually(t, func() bool {
+		return flushed.Load() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, s.Close())
+
+	// the event of the stopping table is dropped, and the others are
+	// sent in a full batch and a batch flushed by the ticker.
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], 2)
+	require.Len(t, batches[1], 1)
+	require.Equal(t, "test", batches[0][0]["database"])
+	require.Equal(t, "INSERT", batches[1][0]["type"])
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+func TestWebhookWriteEventsFailed(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(server.URL + "?batch-size=1")
+	require.NoError(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = config.ProtocolOpen.String()
+	errCh := make(chan error, 1)
+	s, err := NewWebhookDMLSink(ctx, sinkURI, replicaConfig, errCh)
+	require.NoError(t, err)
+
+	sinking := state.TableSinkSinking
+	require.NoError(t, s.WriteEvents(&eventsink.RowChangeCallbackableEvent{
+		Event: &model.RowChangedEvent{
+			CommitTs: 100,
+			Table:    &model.TableName{Schema: "test", Table: "t"},
+			Columns:  []*model.Column{{Name: "id", Type: mysql.TypeLong, Value: int64(1)}},
+		},
+		Callback:  func() {},
+		SinkState: &sinking,
+	}))
+	select {
+	case err := <-errCh:
+		require.Regexp(t, ".*webhook request is rejected.*403.*", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the error is not reported")
+	}
+	require.NoError(t, s.Close())
+}
@@ -59,6 +59,8 @@ func GetFileExtension(protocol config.Protocol) string {
 		return ".canal"
 	case config.ProtocolCsv:
 		return ".csv"
+	case config.ProtocolParquet:
+		return ".parquet"
 	default:
 		return ".unknown"
 	}
@@ -37,7 +37,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/cdc/sink/codec"
+	"github.com/pingcap/tiflow/cdc/sink/codec/avro"
 	"github.com/pingcap/tiflow/cdc/sink/codec/canal"
+	"github.com/pingcap/tiflow/cdc/sink/codec/claimcheck"
+	"github.com/pingcap/tiflow/cdc/sink/codec/maxwell"
 	"github.com/pingcap/tiflow/cdc/sink/codec/open"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	cmdUtil "github.com/pingcap/tiflow/pkg/cmd/util"
@@ -65,6 +68,14 @@ var (
 	protocol            config.Protocol
 	enableTiDBExtension bool
 
+	// schemaRegistryURI is only used by the avro protocol
+	schemaRegistryURI string
+
+	// claimCheckStorageURI is the storage of the large messages, it's only
+	// used by the open and canal-json protocols, and set by the changefeed
+	// config file.
+	claimCheckStorageURI string
+
 	// eventRouterReplicaConfig only used to initialize the consumer's eventRouter
 	// which then can be used to check RowChangedEvent dispatched correctness
 	eventRouterReplicaConfig *config.ReplicaConfig
@@ -90,6 +101,7 @@ func init() {
 	flag.StringVar(&ca, "ca", "", "CA certificate path for Kafka SSL connection")
 	flag.StringVar(&cert, "cert", "", "Certificate path for Kafka SSL connection")
 	flag.StringVar(&key, "key", "", "Private key path for Kafka SSL connection")
+	flag.StringVar(&schemaRegistryURI, "schema-registry-uri", "", "Schema registry uri, only for avro protocol")
 	flag.Parse()
 
 	err := logutil.InitLogger(&logutil.Config{
@@ -180,13 +192,42 @@ func init() {
 		if err != nil {
 			log.Panic("invalid enable-tidb-extension of upstream-uri")
 		}
-		if protocol != config.ProtocolCanalJSON && b {
-			log.Panic("enable-tidb-extension only work with canal-json")
+		if protocol != config.ProtocolCanalJSON && protocol != config.ProtocolAvro &&
+			protocol != config.ProtocolCanal && protocol != config.ProtocolMaxwell && b {
+			log.Panic("enable-tidb-extension only work with canal-json / canal / maxwell / avro")
 		}
 
 		enableTiDBExtension = b
 	}
 
+	// Rows are only flushed at the resolved events, which are only emitted by
+	// the maxwell and canal protobuf encoders with the TiDB extension. The
+	// extension also keeps the full commit ts, which is lossy in these protocols.
+	if (protocol == config.ProtocolMaxwell || protocol == config.ProtocolCanal) &&
+		!enableTiDBExtension {
+		log.Panic("protocol requires enable-tidb-extension to be true in upstream-uri",
+			zap.String("protocol", protocol.String()))
+	}
+
+	if protocol == config.ProtocolAvro {
+		if schemaRegistryURI == "" {
+			log.Panic("schema-registry-uri is required for avro protocol")
+		}
+		// Rows are only flushed at the watermarks, which are only emitted by
+		// the avro encoder with both the TiDB extension and the watermark enabled.
+		enableWatermark := false
+		if s = upstreamURI.Query().Get("avro-enable-watermark"); s != "" {
+			enableWatermark, err = strconv.ParseBool(s)
+			if err != nil {
+				log.Panic("invalid avro-enable-watermark of upstream-uri")
+			}
+		}
+		if !enableTiDBExtension || !enableWatermark {
+			log.Panic("avro protocol requires both enable-tidb-extension " +
+				"and avro-enable-watermark to be true in upstream-uri")
+		}
+	}
+
 	if configFile != "" {
 		eventRouterReplicaConfig = config.GetDefaultReplicaConfig()
 		eventRouterReplicaConfig.Sink.Protocol = protocol.String()
@@ -199,6 +240,14 @@ func init() {
 		if _, err := filter.VerifyTableRules(eventRouterReplicaConfig.Filter); err != nil {
 			log.Panic("verify rule failed", zap.Error(err))
 		}
+		largeMessageHandle := eventRouterReplicaConfig.Sink.LargeMessageHandle
+		if largeMessageHandle.EnableClaimCheck() {
+			if protocol == config.ProtocolCanalJSON && !enableTiDBExtension {
+				log.Panic("claim-check of canal-json protocol requires " +
+					"enable-tidb-extension to be true in upstream-uri")
+			}
+			claimCheckStorageURI = largeMessageHandle.ClaimCheckStorageURI
+		}
 	}
 }
 
@@ -376,6 +425,13 @@ type Consumer struct {
 	protocol            config.Protocol
 	enableTiDBExtension bool
 
+	// keySchemaManager and valueSchemaManager are only used by the avro protocol
+	keySchemaManager   *avro.SchemaManager
+	valueSchemaManager *avro.SchemaManager
+
+	// claimCheck is only used by the open and canal-json protocols
+	claimCheck *claimcheck.ClaimCheck
+
 	eventRouter *dispatcher.EventRouter
 }
 
@@ -395,6 +451,24 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 	c.protocol = protocol
 	c.enableTiDBExtension = enableTiDBExtension
 
+	if c.protocol == config.ProtocolAvro {
+		c.keySchemaManager, err = avro.NewAvroSchemaManager(ctx, nil, schemaRegistryURI, "-key")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.valueSchemaManager, err = avro.NewAvroSchemaManager(ctx, nil, schemaRegistryURI, "-value")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	if claimCheckStorageURI != "" {
+		c.claimCheck, err = claimcheck.New(ctx, claimCheckStorageURI, model.ChangeFeedID{})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	// this means user has input config file to enable dispatcher check
 	// some protocol does not provide enough information to check the
 	// dispatched partition match or not. such as `open-protocol`, which
@@ -404,7 +478,8 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 	// rule, make sure decoded `RowChangedEvent` contains information
 	// identical to the CDC side.
 	if eventRouterReplicaConfig != nil {
-		eventRouter, err := dispatcher.NewEventRouter(eventRouterReplicaConfig, kafkaTopic)
+		eventRouter, err := dispatcher.NewEventRouter(eventRouterReplicaConfig, kafkaTopic,
+			model.ChangeFeedID{}, 0)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -474,7 +549,8 @@ func (g *eventsGroup) Append(e *model.RowChangedEvent) {
 }
 
 func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
-	sort.Slice(g.events, func(i, j int) bool {
+	// keep the order of the events with the same commit ts.
+	sort.SliceStable(g.events, func(i, j int) bool {
 		return g.events[i].CommitTs < g.events[j].CommitTs
 	})
 
@@ -499,6 +575,9 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 	}
 
 	eventGroups := make(map[int64]*eventsGroup)
+	// lastCommitTs is the commit ts of the last row or resolved event of the
+	// partition.
+	var lastCommitTs uint64
 	for message := range claim.Messages() {
 		var (
 			decoder codec.EventBatchDecoder
@@ -506,9 +585,17 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 		)
 		switch c.protocol {
 		case config.ProtocolOpen, config.ProtocolDefault:
-			decoder, err = open.NewBatchDecoder(message.Key, message.Value)
+			decoder, err = open.NewBatchDecoderWithClaimCheck(ctx, message.Key, message.Value, c.claimCheck)
 		case config.ProtocolCanalJSON:
-			decoder = canal.NewBatchDecoder(message.Value, c.enableTiDBExtension, "")
+			decoder = canal.NewBatchDecoderWithClaimCheck(ctx,
+				message.Value, c.enableTiDBExtension, "", c.claimCheck)
+		case config.ProtocolAvro:
+			decoder = avro.NewDecoder(ctx, message.Key, message.Value,
+				c.keySchemaManager, c.valueSchemaManager)
+		case config.ProtocolCanal:
+			decoder, err = canal.NewProtobufBatchDecoder(message.Value)
+		case config.ProtocolMaxwell:
+			decoder, err = maxwell.NewBatchDecoder(message.Key, message.Value)
 		default:
 			log.Panic("Protocol not supported", zap.Any("Protocol", c.protocol))
 		}
@@ -555,7 +642,11 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 				}
 
 				if c.eventRouter != nil {
-					target := c.eventRouter.GetPartitionForRowChange(row, kafkaPartitionNum)
+					target, err := c.eventRouter.GetPartitionForRowChange(row, kafkaPartitionNum)
+					if err != nil {
+						log.Panic("dispatch RowChangedEvent failed",
+							zap.Any("row", row), zap.Error(err))
+					}
 					if partition != target {
 						log.Panic("RowChangedEvent dispatched to wrong partition",
 							zap.Int32("obtained", partition),
@@ -566,6 +657,18 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 					}
 				}
 
+				// The tombstone message of the avro protocol does not carry
+				// the commit ts, the delete is ordered after the last event
+				// of the partition. Its real commit ts must be larger than the
+				// resolved ts of the partition, so is the one assigned to it.
+				if row.CommitTs == 0 {
+					row.CommitTs = lastCommitTs
+					if resolvedTs := atomic.LoadUint64(&sink.resolvedTs); row.CommitTs <= resolvedTs {
+						row.CommitTs = resolvedTs + 1
+					}
+				}
+				lastCommitTs = row.CommitTs
+
 				globalResolvedTs := atomic.LoadUint64(&c.globalResolvedTs)
 				if row.CommitTs <= globalResolvedTs || row.CommitTs <= sink.resolvedTs {
 					log.Warn("RowChangedEvent fallback row, ignore it",
@@ -597,6 +700,9 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 				if err != nil {
 					log.Panic("decode message value failed", zap.ByteString("value", message.Value))
 				}
+				if ts > lastCommitTs {
+					lastCommitTs = ts
+				}
 				resolvedTs := atomic.LoadUint64(&sink.resolvedTs)
 				// `resolvedTs` should be monotonically increasing, it's allowed to receive redundant one.
 				if ts < resolvedTs {
@@ -618,8 +724,8 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 								zap.Int32("partition", partition))
 						}
 						commitTs := events[len(events)-1].CommitTs
-						lastCommitTs, ok := sink.tablesMap.Load(tableID)
-						if !ok || lastCommitTs.(uint64) < commitTs {
+						tableCommitTs, ok := sink.tablesMap.Load(tableID)
+						if !ok || tableCommitTs.(uint64) < commitTs {
 							sink.tablesMap.Store(tableID, commitTs)
 						}
 					}
@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
@@ -39,8 +40,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/codec/canal"
 	"github.com/pingcap/tiflow/cdc/sink/codec/common"
 	"github.com/pingcap/tiflow/cdc/sink/codec/csv"
+	"github.com/pingcap/tiflow/cdc/sink/codec/parquet"
 	sinkutil "github.com/pingcap/tiflow/cdc/sinkv2/util"
 	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/logutil"
 	"github.com/pingcap/tiflow/pkg/quotes"
@@ -223,6 +226,9 @@ type consumer struct {
 	codecCfg        *common.Config
 	externalStorage storage.ExternalStorage
 	fileExtension   string
+	// compression is the compression codec of the data files, it's
+	// detected from the file extension of the data files.
+	compression string
 	// tableIdxMap maintains a map of <dmlPathKey, max file index>
 	tableIdxMap map[dmlPathKey]uint64
 	// tableTsMap maintains a map of <TableID, max commit ts>
@@ -247,6 +253,7 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 	switch replicaConfig.Sink.Protocol {
 	case config.ProtocolCsv.String():
 	case config.ProtocolCanalJSON.String():
+	case config.ProtocolParquet.String():
 	default:
 		return nil, fmt.Errorf("data encoded in protocol %s is not supported yet",
 			replicaConfig.Sink.Protocol)
@@ -293,6 +300,7 @@ func newConsumer(ctx context.Context) (*consumer, error) {
 		codecCfg:        codecConfig,
 		externalStorage: storage,
 		fileExtension:   extension,
+		compression:     compression.None,
 		tableIdxMap:     make(map[dmlPathKey]uint64),
 		tableTsMap:      make(map[model.TableID]uint64),
 		tableIDGenerator: &fakeTableIDGenerator{
@@ -358,6 +366,10 @@ func (c *consumer) getNewFiles(ctx context.Context) (map[dmlPathKey]fileIndexRan
 			return nil
 		}
 
+		// the compression codec is appended to the file extension,
+		// e.g. CDC000001.csv.gz.
+		c.compression = compression.FromFileExtension(filepath.Ext(path))
+
 		if _, ok := c.tableIdxMap[dmlkey]; !ok || fileIdx >= c.tableIdxMap[dmlkey] {
 			c.tableIdxMap[dmlkey] = fileIdx
 		}
@@ -415,6 +427,11 @@ func (c *consumer) emitDMLEvents(ctx context.Context, tableID int64, pathKey dml
 		}
 	case config.ProtocolCanalJSON:
 		decoder = canal.NewBatchDecoder(content, false, c.codecCfg.Terminator)
+	case config.ProtocolParquet:
+		decoder, err = parquet.NewBatchDecoder(tableInfo, content)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	cnt := 0
@@ -501,12 +518,17 @@ func (c *consumer) run(ctx context.Context) error {
 		for _, k := range keys {
 			fileRange := fileMap[k]
 			for i := fileRange.start; i <= fileRange.end; i++ {
-				filePath := k.generateDMLFilePath(i, c.fileExtension)
+				filePath := k.generateDMLFilePath(i,
+					c.fileExtension+compression.FileExtension(c.compression))
 				log.Debug("read from dml file path", zap.String("path", filePath))
 				content, err := c.externalStorage.ReadFile(ctx, filePath)
 				if err != nil {
 					return errors.Trace(err)
 				}
+				content, err = compression.Decode(c.compression, content)
+				if err != nil {
+					return errors.Trace(err)
+				}
 				tableID := c.tableIDGenerator.generateFakeTableID(
 					k.schema, k.table, k.partitionNum)
 				err = c.emitDMLEvents(ctx, tableID, k, content)
@@ -66,6 +66,10 @@ type CDCRESTClient struct {
 
 	// Client is a wrapped http client.
 	Client *httputil.Client
+
+	// authorization is the value of the Authorization header, it's empty
+	// if the client doesn't need to be authenticated.
+	authorization string
 }
 
 // NewCDCRESTClient creates a new CDCRESTClient.
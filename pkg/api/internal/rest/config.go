@@ -31,6 +31,8 @@ type Config struct {
 	APIPath string
 	// Credential holds the security Credential used for generating tls config
 	Credential *security.Credential
+	// AuthCredential is used to authenticate the client to the cdc server
+	AuthCredential *security.AuthCredential
 	// API verion
 	Version string
 }
@@ -83,6 +85,7 @@ func CDCRESTClientFromConfig(config *Config) (*CDCRESTClient, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	restClient.authorization = config.AuthCredential.AuthorizationHeader()
 
 	return restClient, nil
 }
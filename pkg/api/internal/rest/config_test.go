@@ -89,3 +89,18 @@ func TestCDCRESTClientUsingTLS(t *testing.T) {
 		require.Equal(t, usingTLS, tc.UsingTLS)
 	}
 }
+
+func TestCDCRESTClientWithAuthCredential(t *testing.T) {
+	client, err := CDCRESTClientFromConfig(&Config{
+		Host: "127.0.0.1:8300", APIPath: "/api", Version: "v2",
+	})
+	require.Nil(t, err)
+	require.Empty(t, client.Get().headers.Get("Authorization"))
+
+	client, err = CDCRESTClientFromConfig(&Config{
+		Host: "127.0.0.1:8300", APIPath: "/api", Version: "v2",
+		AuthCredential: &security.AuthCredential{Token: "secret"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "Bearer secret", client.Get().headers.Get("Authorization"))
+}
@@ -88,6 +88,9 @@ func NewRequest(c *CDCRESTClient) *Request {
 	}
 	r.WithHeader("Accept", "application/json")
 	r.WithHeader(middleware.ClientVersionHeader, version.ReleaseVersion)
+	if c.authorization != "" {
+		r.WithHeader("Authorization", c.authorization)
+	}
 	return r
 }
 
@@ -66,12 +66,15 @@ func (c *APIV1Client) Status() StatusInterface {
 }
 
 // NewAPIClient creates a new APIV1Client.
-func NewAPIClient(ownerAddr string, credential *security.Credential) (*APIV1Client, error) {
+func NewAPIClient(
+	ownerAddr string, credential *security.Credential, auth *security.AuthCredential,
+) (*APIV1Client, error) {
 	c := &rest.Config{}
 	c.APIPath = "/api"
 	c.Version = "v1"
 	c.Host = ownerAddr
 	c.Credential = credential
+	c.AuthCredential = auth
 	client, err := rest.CDCRESTClientFromConfig(c)
 	if err != nil {
 		return nil, err
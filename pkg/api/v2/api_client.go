@@ -24,6 +24,9 @@ import (
 type APIV2Interface interface {
 	RESTClient() rest.CDCRESTInterface
 	ChangefeedsGetter
+	ProcessorsGetter
+	CapturesGetter
+	OwnerGetter
 	TsoGetter
 	UnsafeGetter
 }
@@ -66,13 +69,40 @@ func (c *APIV2Client) Changefeeds() ChangefeedInterface {
 	return newChangefeeds(c)
 }
 
+// Processors returns a ProcessorInterface with cdc api
+func (c *APIV2Client) Processors() ProcessorInterface {
+	if c == nil {
+		return nil
+	}
+	return newProcessors(c)
+}
+
+// Captures returns a CaptureInterface with cdc api
+func (c *APIV2Client) Captures() CaptureInterface {
+	if c == nil {
+		return nil
+	}
+	return newCaptures(c)
+}
+
+// Owner returns an OwnerInterface with cdc api
+func (c *APIV2Client) Owner() OwnerInterface {
+	if c == nil {
+		return nil
+	}
+	return newOwner(c)
+}
+
 // NewAPIClient creates a new APIV1Client.
-func NewAPIClient(serverAddr string, credential *security.Credential) (*APIV2Client, error) {
+func NewAPIClient(
+	serverAddr string, credential *security.Credential, auth *security.AuthCredential,
+) (*APIV2Client, error) {
 	c := &rest.Config{}
 	c.APIPath = "/api"
 	c.Version = "v2"
 	c.Host = serverAddr
 	c.Credential = credential
+	c.AuthCredential = auth
 	client, err := rest.CDCRESTClientFromConfig(c)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -40,6 +40,22 @@ type ChangefeedInterface interface {
 		name string) (*v2.ChangeFeedInfo, error)
 	// Resume resumes a changefeed with given config
 	Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error
+	// Get gets the detail of a changefeed
+	Get(ctx context.Context, name string) (*v2.ChangeFeedInfo, error)
+	// List lists the changefeeds in the given state
+	List(ctx context.Context, state string) ([]v2.ChangefeedCommonInfo, error)
+	// Pause pauses a changefeed
+	Pause(ctx context.Context, name string) error
+	// Delete removes a changefeed
+	Delete(ctx context.Context, name string) error
+	// Status gets the runtime status of a changefeed
+	Status(ctx context.Context, name string) (*v2.ChangefeedStatus, error)
+	// MoveTable moves a table of a changefeed to the target capture
+	MoveTable(ctx context.Context, name string, req *v2.MoveTableReq) error
+	// UpdateTables adds or removes tables of a running changefeed
+	UpdateTables(ctx context.Context, name string, req *v2.UpdateTablesReq) error
+	// ResolveDDL approves, skips or replaces the pending DDL of a changefeed
+	ResolveDDL(ctx context.Context, name string, req *v2.ResolveDDLReq) error
 }
 
 // changefeeds implements ChangefeedInterface
@@ -112,3 +128,94 @@ func (c *changefeeds) Resume(ctx context.Context,
 		WithBody(cfg).
 		Do(ctx).Error()
 }
+
+// Get gets the detail of a changefeed
+func (c *changefeeds) Get(ctx context.Context,
+	name string,
+) (*v2.ChangeFeedInfo, error) {
+	result := &v2.ChangeFeedInfo{}
+	u := fmt.Sprintf("changefeeds/%s", name)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// List lists the changefeeds in the given state
+func (c *changefeeds) List(ctx context.Context,
+	state string,
+) ([]v2.ChangefeedCommonInfo, error) {
+	result := &v2.ListResponse[v2.ChangefeedCommonInfo]{}
+	err := c.client.Get().
+		WithURI("changefeeds").
+		WithParam("state", state).
+		Do(ctx).
+		Into(result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// Pause a changefeed
+func (c *changefeeds) Pause(ctx context.Context, name string) error {
+	u := fmt.Sprintf("changefeeds/%s/pause", name)
+	return c.client.Post().
+		WithURI(u).
+		Do(ctx).Error()
+}
+
+// Delete a changefeed
+func (c *changefeeds) Delete(ctx context.Context, name string) error {
+	u := fmt.Sprintf("changefeeds/%s", name)
+	return c.client.Delete().
+		WithURI(u).
+		Do(ctx).Error()
+}
+
+// Status gets the runtime status of a changefeed
+func (c *changefeeds) Status(ctx context.Context,
+	name string,
+) (*v2.ChangefeedStatus, error) {
+	result := &v2.ChangefeedStatus{}
+	u := fmt.Sprintf("changefeeds/%s/status", name)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// MoveTable moves a table of a changefeed to the target capture
+func (c *changefeeds) MoveTable(ctx context.Context,
+	name string, req *v2.MoveTableReq,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/move_table", name)
+	return c.client.Post().
+		WithURI(u).
+		WithBody(req).
+		Do(ctx).Error()
+}
+
+// UpdateTables adds or removes tables of a running changefeed
+func (c *changefeeds) UpdateTables(ctx context.Context,
+	name string, req *v2.UpdateTablesReq,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/tables", name)
+	return c.client.Post().
+		WithURI(u).
+		WithBody(req).
+		Do(ctx).Error()
+}
+
+// ResolveDDL approves, skips or replaces the pending DDL of a changefeed
+func (c *changefeeds) ResolveDDL(ctx context.Context,
+	name string, req *v2.ResolveDDLReq,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/ddl", name)
+	return c.client.Post().
+		WithURI(u).
+		WithBody(req).
+		Do(ctx).Error()
+}
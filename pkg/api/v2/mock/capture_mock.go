@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: capture.go
+
+// Package mock_v2 is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	v20 "github.com/pingcap/tiflow/pkg/api/v2"
+)
+
+// MockCapturesGetter is a mock of CapturesGetter interface.
+type MockCapturesGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCapturesGetterMockRecorder
+}
+
+// MockCapturesGetterMockRecorder is the mock recorder for MockCapturesGetter.
+type MockCapturesGetterMockRecorder struct {
+	mock *MockCapturesGetter
+}
+
+// NewMockCapturesGetter creates a new mock instance.
+func NewMockCapturesGetter(ctrl *gomock.Controller) *MockCapturesGetter {
+	mock := &MockCapturesGetter{ctrl: ctrl}
+	mock.recorder = &MockCapturesGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCapturesGetter) EXPECT() *MockCapturesGetterMockRecorder {
+	return m.recorder
+}
+
+// Captures mocks base method.
+func (m *MockCapturesGetter) Captures() v20.CaptureInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Captures")
+	ret0, _ := ret[0].(v20.CaptureInterface)
+	return ret0
+}
+
+// Captures indicates an expected call of Captures.
+func (mr *MockCapturesGetterMockRecorder) Captures() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Captures", reflect.TypeOf((*MockCapturesGetter)(nil).Captures))
+}
+
+// MockCaptureInterface is a mock of CaptureInterface interface.
+type MockCaptureInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCaptureInterfaceMockRecorder
+}
+
+// MockCaptureInterfaceMockRecorder is the mock recorder for MockCaptureInterface.
+type MockCaptureInterfaceMockRecorder struct {
+	mock *MockCaptureInterface
+}
+
+// NewMockCaptureInterface creates a new mock instance.
+func NewMockCaptureInterface(ctrl *gomock.Controller) *MockCaptureInterface {
+	mock := &MockCaptureInterface{ctrl: ctrl}
+	mock.recorder = &MockCaptureInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCaptureInterface) EXPECT() *MockCaptureInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Drain mocks base method.
+func (m *MockCaptureInterface) Drain(ctx context.Context, captureID string) (*v2.DrainCaptureResp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx, captureID)
+	ret0, _ := ret[0].(*v2.DrainCaptureResp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockCaptureInterfaceMockRecorder) Drain(ctx, captureID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockCaptureInterface)(nil).Drain), ctx, captureID)
+}
+
+// List mocks base method.
+func (m *MockCaptureInterface) List(ctx context.Context) ([]v2.Capture, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]v2.Capture)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCaptureInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCaptureInterface)(nil).List), ctx)
+}
@@ -88,6 +88,35 @@ func (mr *MockChangefeedInterfaceMockRecorder) Create(ctx, cfg interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockChangefeedInterface)(nil).Create), ctx, cfg)
 }
 
+// Delete mocks base method.
+func (m *MockChangefeedInterface) Delete(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockChangefeedInterfaceMockRecorder) Delete(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockChangefeedInterface)(nil).Delete), ctx, name)
+}
+
+// Get mocks base method.
+func (m *MockChangefeedInterface) Get(ctx context.Context, name string) (*v2.ChangeFeedInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name)
+	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockChangefeedInterfaceMockRecorder) Get(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChangefeedInterface)(nil).Get), ctx, name)
+}
+
 // GetInfo mocks base method.
 func (m *MockChangefeedInterface) GetInfo(ctx context.Context, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -103,6 +132,63 @@ func (mr *MockChangefeedInterfaceMockRecorder) GetInfo(ctx, name interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockChangefeedInterface)(nil).GetInfo), ctx, name)
 }
 
+// List mocks base method.
+func (m *MockChangefeedInterface) List(ctx context.Context, state string) ([]v2.ChangefeedCommonInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, state)
+	ret0, _ := ret[0].([]v2.ChangefeedCommonInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockChangefeedInterfaceMockRecorder) List(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockChangefeedInterface)(nil).List), ctx, state)
+}
+
+// MoveTable mocks base method.
+func (m *MockChangefeedInterface) MoveTable(ctx context.Context, name string, req *v2.MoveTableReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTable", ctx, name, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveTable indicates an expected call of MoveTable.
+func (mr *MockChangefeedInterfaceMockRecorder) MoveTable(ctx, name, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTable", reflect.TypeOf((*MockChangefeedInterface)(nil).MoveTable), ctx, name, req)
+}
+
+// Pause mocks base method.
+func (m *MockChangefeedInterface) Pause(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockChangefeedInterfaceMockRecorder) Pause(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockChangefeedInterface)(nil).Pause), ctx, name)
+}
+
+// ResolveDDL mocks base method.
+func (m *MockChangefeedInterface) ResolveDDL(ctx context.Context, name string, req *v2.ResolveDDLReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveDDL", ctx, name, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveDDL indicates an expected call of ResolveDDL.
+func (mr *MockChangefeedInterfaceMockRecorder) ResolveDDL(ctx, name, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveDDL", reflect.TypeOf((*MockChangefeedInterface)(nil).ResolveDDL), ctx, name, req)
+}
+
 // Resume mocks base method.
 func (m *MockChangefeedInterface) Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error {
 	m.ctrl.T.Helper()
@@ -117,6 +203,21 @@ func (mr *MockChangefeedInterfaceMockRecorder) Resume(ctx, cfg, name interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockChangefeedInterface)(nil).Resume), ctx, cfg, name)
 }
 
+// Status mocks base method.
+func (m *MockChangefeedInterface) Status(ctx context.Context, name string) (*v2.ChangefeedStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx, name)
+	ret0, _ := ret[0].(*v2.ChangefeedStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockChangefeedInterfaceMockRecorder) Status(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockChangefeedInterface)(nil).Status), ctx, name)
+}
+
 // Update mocks base method.
 func (m *MockChangefeedInterface) Update(ctx context.Context, cfg *v2.ChangefeedConfig, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -132,6 +233,20 @@ func (mr *MockChangefeedInterfaceMockRecorder) Update(ctx, cfg, name interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockChangefeedInterface)(nil).Update), ctx, cfg, name)
 }
 
+// UpdateTables mocks base method.
+func (m *MockChangefeedInterface) UpdateTables(ctx context.Context, name string, req *v2.UpdateTablesReq) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTables", ctx, name, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTables indicates an expected call of UpdateTables.
+func (mr *MockChangefeedInterfaceMockRecorder) UpdateTables(ctx, name, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTables", reflect.TypeOf((*MockChangefeedInterface)(nil).UpdateTables), ctx, name, req)
+}
+
 // VerifyTable mocks base method.
 func (m *MockChangefeedInterface) VerifyTable(ctx context.Context, cfg *v2.VerifyTableConfig) (*v2.Tables, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: owner.go
+
+// Package mock_v2 is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/pkg/api/v2"
+)
+
+// MockOwnerGetter is a mock of OwnerGetter interface.
+type MockOwnerGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockOwnerGetterMockRecorder
+}
+
+// MockOwnerGetterMockRecorder is the mock recorder for MockOwnerGetter.
+type MockOwnerGetterMockRecorder struct {
+	mock *MockOwnerGetter
+}
+
+// NewMockOwnerGetter creates a new mock instance.
+func NewMockOwnerGetter(ctrl *gomock.Controller) *MockOwnerGetter {
+	mock := &MockOwnerGetter{ctrl: ctrl}
+	mock.recorder = &MockOwnerGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOwnerGetter) EXPECT() *MockOwnerGetterMockRecorder {
+	return m.recorder
+}
+
+// Owner mocks base method.
+func (m *MockOwnerGetter) Owner() v2.OwnerInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Owner")
+	ret0, _ := ret[0].(v2.OwnerInterface)
+	return ret0
+}
+
+// Owner indicates an expected call of Owner.
+func (mr *MockOwnerGetterMockRecorder) Owner() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Owner", reflect.TypeOf((*MockOwnerGetter)(nil).Owner))
+}
+
+// MockOwnerInterface is a mock of OwnerInterface interface.
+type MockOwnerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOwnerInterfaceMockRecorder
+}
+
+// MockOwnerInterfaceMockRecorder is the mock recorder for MockOwnerInterface.
+type MockOwnerInterfaceMockRecorder struct {
+	mock *MockOwnerInterface
+}
+
+// NewMockOwnerInterface creates a new mock instance.
+func NewMockOwnerInterface(ctrl *gomock.Controller) *MockOwnerInterface {
+	mock := &MockOwnerInterface{ctrl: ctrl}
+	mock.recorder = &MockOwnerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOwnerInterface) EXPECT() *MockOwnerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Resign mocks base method.
+func (m *MockOwnerInterface) Resign(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resign", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resign indicates an expected call of Resign.
+func (mr *MockOwnerInterfaceMockRecorder) Resign(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resign", reflect.TypeOf((*MockOwnerInterface)(nil).Resign), ctx)
+}
@@ -0,0 +1,191 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applier
+
+import (
+	"context"
+
+	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	sinkmetric "github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/factory"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// rowSink is the sink which redo logs are applied to.
+// Both the MySQL sink and eventSink implement it.
+type rowSink interface {
+	EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error
+	FlushRowChangedEvents(
+		ctx context.Context, tableID model.TableID, resolved model.ResolvedTs,
+	) (model.ResolvedTs, error)
+	RemoveTable(ctx context.Context, tableID model.TableID) error
+	Close(ctx context.Context) error
+}
+
+// eventSink applies redo logs to the sinkv2 event sinks, such as MQ,
+// cloud storage and blackhole sinks.
+//
+// The table info isn't persisted in redo logs, so it's rebuilt from the columns
+// of rows, which is enough for encoding the rows.
+// DDL events can't be replayed for the same reason, the same as the MySQL sink.
+type eventSink struct {
+	changefeedID model.ChangeFeedID
+	// startTs is used as the version of the rebuilt table infos.
+	startTs model.Ts
+
+	sinkFactory *factory.SinkFactory
+	// cancel is used to cancel the background goroutines of the sink factory.
+	cancel           context.CancelFunc
+	tableSinks       map[model.TableID]tablesink.TableSink
+	tableInfos       map[model.TableID]*model.TableInfo
+	totalRowsCounter prometheus.Counter
+}
+
+func newEventSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI string,
+	replicaConfig *config.ReplicaConfig,
+	startTs model.Ts,
+	errCh chan error,
+) (*eventSink, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	sinkFactory, err := factory.New(ctx, sinkURI, replicaConfig, errCh)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &eventSink{
+		changefeedID: changefeedID,
+		startTs:      startTs,
+		sinkFactory:  sinkFactory,
+		cancel:       cancel,
+		tableSinks:   make(map[model.TableID]tablesink.TableSink),
+		tableInfos:   make(map[model.TableID]*model.TableInfo),
+		totalRowsCounter: sinkmetric.TableSinkTotalRowsCountCounter.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}, nil
+}
+
+// EmitRowChangedEvents appends rows to the table sinks, the rows must be
+// sorted by commit ts.
+func (s *eventSink) EmitRowChangedEvents(
+	_ context.Context, rows ...*model.RowChangedEvent,
+) error {
+	for _, row := range rows {
+		tableID := row.Table.TableID
+		if row.TableInfo == nil {
+			row.TableInfo = s.getTableInfo(row)
+		}
+		tableSink, ok := s.tableSinks[tableID]
+		if !ok {
+			tableSink = s.sinkFactory.CreateTableSink(s.changefeedID,
+				spanz.TableIDToComparableSpan(tableID), s.totalRowsCounter)
+			s.tableSinks[tableID] = tableSink
+		}
+		tableSink.AppendRowChangedEvents(row)
+	}
+	return nil
+}
+
+// getTableInfo returns the table info rebuilt from the columns of the row.
+// A new version is used if the columns of the table change.
+func (s *eventSink) getTableInfo(row *model.RowChangedEvent) *model.TableInfo {
+	tableID := row.Table.TableID
+	cols := row.Columns
+	if len(cols) == 0 {
+		cols = row.PreColumns
+	}
+	version := s.startTs
+	if info, ok := s.tableInfos[tableID]; ok {
+		if columnsMatch(info.Columns, cols) {
+			return info
+		}
+		version = row.CommitTs
+	}
+
+	tiTableInfo := model.BuildTiDBTableInfo(cols, row.IndexColumns)
+	tiTableInfo.ID = tableID
+	tiTableInfo.Name = timodel.NewCIStr(row.Table.Table)
+	info := &model.TableInfo{
+		TableInfo: tiTableInfo,
+		TableName: *row.Table,
+		Version:   version,
+	}
+	s.tableInfos[tableID] = info
+	log.Info("rebuild table info from redo logs",
+		zap.Stringer("table", row.Table), zap.Uint64("version", version))
+	return info
+}
+
+func columnsMatch(infos []*timodel.ColumnInfo, cols []*model.Column) bool {
+	if len(infos) != len(cols) {
+		return false
+	}
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		if infos[i].Name.O != col.Name || infos[i].GetType() != col.Type {
+			return false
+		}
+	}
+	return true
+}
+
+// FlushRowChangedEvents advances the resolved ts of the table sink,
+// the events are written to downstream asynchronously.
+func (s *eventSink) FlushRowChangedEvents(
+	_ context.Context, tableID model.TableID, resolved model.ResolvedTs,
+) (model.ResolvedTs, error) {
+	tableSink, ok := s.tableSinks[tableID]
+	if !ok {
+		return resolved, nil
+	}
+	if err := tableSink.UpdateResolvedTs(resolved); err != nil {
+		return model.ResolvedTs{}, err
+	}
+	return tableSink.GetCheckpointTs(), nil
+}
+
+// RemoveTable closes the table sink, it blocks until all the events
+// of the table are written to downstream.
+func (s *eventSink) RemoveTable(ctx context.Context, tableID model.TableID) error {
+	tableSink, ok := s.tableSinks[tableID]
+	if !ok {
+		return nil
+	}
+	tableSink.Close(ctx)
+	delete(s.tableSinks, tableID)
+	delete(s.tableInfos, tableID)
+	return ctx.Err()
+}
+
+// Close closes all the table sinks and the sink factory.
+func (s *eventSink) Close(ctx context.Context) error {
+	for tableID, tableSink := range s.tableSinks {
+		tableSink.Close(ctx)
+		delete(s.tableSinks, tableID)
+	}
+	s.cancel()
+	sinkmetric.TableSinkTotalRowsCountCounter.
+		DeleteLabelValues(s.changefeedID.Namespace, s.changefeedID.ID)
+	return s.sinkFactory.Close()
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	psink "github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/util"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -70,12 +71,12 @@ func (rac *RedoApplierConfig) toLogReaderConfig() (string, *reader.LogReaderConf
 		return "", nil, cerror.WrapError(cerror.ErrConsistentStorage, err)
 	}
 	cfg := &reader.LogReaderConfig{
-		Dir:       uri.Path,
-		S3Storage: redo.IsS3StorageEnabled(uri.Scheme),
+		Dir:                uri.Path,
+		UseExternalStorage: redo.IsExternalStorage(uri.Scheme),
 	}
-	if cfg.S3Storage {
-		cfg.S3URI = *uri
-		// If use s3 as backend, applier will download redo logs to local dir.
+	if cfg.UseExternalStorage {
+		cfg.ExtStorageURI = *uri
+		// If use an external storage as backend, applier will download redo logs to local dir.
 		cfg.Dir = rac.Dir
 	}
 	return uri.Scheme, cfg, nil
@@ -115,9 +116,7 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	// - filter: default []string{"*.*"}
 	replicaConfig := config.GetDefaultReplicaConfig()
 	ctx = contextutil.PutRoleInCtx(ctx, util.RoleRedoLogApplier)
-	s, err := sink.New(ctx,
-		model.DefaultChangeFeedID(applierChangefeed),
-		ra.cfg.SinkURI, replicaConfig, ra.errCh)
+	s, err := ra.newSink(ctx, replicaConfig, checkpointTs)
 	if err != nil {
 		return err
 	}
@@ -193,6 +192,22 @@ func (ra *RedoApplier) consumeLogs(ctx context.Context) error {
 	return errApplyFinished
 }
 
+// newSink creates the sink which redo logs are applied to. MySQL compatible
+// sinks are created by the MySQL sink, and others by the sinkv2 event sinks.
+func (ra *RedoApplier) newSink(
+	ctx context.Context, replicaConfig *config.ReplicaConfig, checkpointTs model.Ts,
+) (rowSink, error) {
+	sinkURI, err := url.Parse(ra.cfg.SinkURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	changefeedID := model.DefaultChangeFeedID(applierChangefeed)
+	if psink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		return sink.New(ctx, changefeedID, ra.cfg.SinkURI, replicaConfig, ra.errCh)
+	}
+	return newEventSink(ctx, changefeedID, ra.cfg.SinkURI, replicaConfig, checkpointTs, ra.errCh)
+}
+
 var createRedoReader = createRedoReaderImpl
 
 func createRedoReaderImpl(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
@@ -203,6 +218,11 @@ func createRedoReaderImpl(ctx context.Context, cfg *RedoApplierConfig) (reader.R
 	return redo.NewRedoReader(ctx, storageType, readerCfg)
 }
 
+// NewRedoReader creates a redo log reader with the given applier config.
+func NewRedoReader(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+	return createRedoReader(ctx, cfg)
+}
+
 // ReadMeta creates a new redo applier and read meta from reader
 func (ra *RedoApplier) ReadMeta(ctx context.Context) (checkpointTs uint64, resolvedTs uint64, err error) {
 	rd, err := createRedoReader(ctx, ra.cfg)
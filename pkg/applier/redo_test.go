@@ -17,10 +17,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/phayes/freeport"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/redo/reader"
@@ -255,3 +259,74 @@ func TestApplyMeetSinkError(t *testing.T) {
 	err = ap.Apply(ctx)
 	require.Regexp(t, "CDC:ErrMySQLConnectionError", err)
 }
+
+func TestApplyDMLsToCloudStorage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checkpointTs := uint64(1000)
+	resolvedTs := uint64(2000)
+	redoLogCh := make(chan *model.RedoRowChangedEvent, 1024)
+	ddlEventCh := make(chan *model.RedoDDLEvent, 1024)
+	createMockReader := func(ctx context.Context, cfg *RedoApplierConfig) (reader.RedoLogReader, error) {
+		return NewMockReader(checkpointTs, resolvedTs, redoLogCh, ddlEventCh), nil
+	}
+	createRedoReaderBak := createRedoReader
+	createRedoReader = createMockReader
+	defer func() {
+		createRedoReader = createRedoReaderBak
+	}()
+
+	columns := func(a int, b string) []*model.Column {
+		return []*model.Column{
+			{
+				Name:  "a",
+				Type:  tmysql.TypeLong,
+				Value: a,
+				Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			}, {
+				Name:  "b",
+				Type:  tmysql.TypeVarchar,
+				Value: b,
+			},
+		}
+	}
+	dmls := []*model.RowChangedEvent{
+		{
+			StartTs:      1100,
+			CommitTs:     1200,
+			Table:        &model.TableName{Schema: "test", Table: "t1", TableID: 1},
+			Columns:      columns(1, "2"),
+			IndexColumns: [][]int{{0}},
+		},
+		{
+			StartTs:      1200,
+			CommitTs:     1300,
+			Table:        &model.TableName{Schema: "test", Table: "t1", TableID: 1},
+			PreColumns:   columns(1, "2"),
+			Columns:      columns(2, "3"),
+			IndexColumns: [][]int{{0}},
+		},
+	}
+	for _, dml := range dmls {
+		redoLogCh <- redo.RowToRedo(dml)
+	}
+	close(redoLogCh)
+	close(ddlEventCh)
+
+	dir := t.TempDir()
+	cfg := &RedoApplierConfig{
+		SinkURI: fmt.Sprintf("file://%s?protocol=canal-json&flush-interval=1s", dir),
+	}
+	ap := NewRedoApplier(cfg)
+	err := ap.Apply(ctx)
+	require.Nil(t, err)
+
+	dataFile := filepath.Join(dir, "test", "t1", fmt.Sprintf("%d", checkpointTs), "CDC000001.json")
+	content, err := os.ReadFile(dataFile)
+	require.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"type":"INSERT"`)
+	require.Contains(t, lines[1], `"type":"UPDATE"`)
+}
@@ -46,6 +46,9 @@ type cfMeta struct {
 	ErrorHis       []int64                   `json:"error_history"`
 	CreatorVersion string                    `json:"creator_version"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+
+	InitialSnapshot *v2.InitialSnapshotStatus `json:"initial_snapshot,omitempty"`
+	PendingDDL      *v2.PendingDDL            `json:"pending_ddl,omitempty"`
 }
 
 // queryChangefeedOptions defines flags for the `cli changefeed query` command.
@@ -128,6 +131,16 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 		CreatorVersion: detail.CreatorVersion,
 		TaskStatus:     detail.TaskStatus,
 	}
+	if info.InitialSnapshot != nil {
+		totalTables := 0
+		for _, status := range detail.TaskStatus {
+			totalTables += len(status.Tables)
+		}
+		meta.InitialSnapshot = v2.NewInitialSnapshotStatus(
+			info.InitialSnapshot.SnapshotTs, detail.CheckpointTSO,
+			info.InitialSnapshot.FinishedTables, totalTables)
+	}
+	meta.PendingDDL = info.PendingDDL
 	return util.JSONPrint(cmd, meta)
 }
 
@@ -0,0 +1,112 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// resolveDDLOptions defines flags for the `cli changefeed resolve-ddl` command.
+type resolveDDLOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	changefeedID string
+	jobID        int64
+	action       string
+	query        string
+}
+
+// newResolveDDLOptions creates new options for the `cli changefeed resolve-ddl` command.
+func newResolveDDLOptions() *resolveDDLOptions {
+	return &resolveDDLOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *resolveDDLOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().Int64Var(&o.jobID, "job-id", 0, "ID of the pending DDL job")
+	cmd.PersistentFlags().StringVar(&o.action, "action", "",
+		"Action on the pending DDL, one of approve, skip and replace")
+	cmd.PersistentFlags().StringVar(&o.query, "query", "",
+		"Query replicated instead of the pending DDL, required by the replace action")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("job-id")
+	_ = cmd.MarkPersistentFlagRequired("action")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *resolveDDLOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+
+	o.apiClient = apiClient
+	return nil
+}
+
+// validate checks that the provided flags are valid.
+func (o *resolveDDLOptions) validate() error {
+	switch model.DDLAction(o.action) {
+	case model.DDLActionApprove, model.DDLActionSkip:
+		if o.query != "" {
+			return errors.New("query is only allowed by the replace action")
+		}
+	case model.DDLActionReplace:
+		if o.query == "" {
+			return errors.New("query is required by the replace action")
+		}
+	default:
+		return errors.New("action must be one of approve, skip and replace")
+	}
+	return nil
+}
+
+// run the `cli changefeed resolve-ddl` command.
+func (o *resolveDDLOptions) run() error {
+	ctx := context.GetDefaultContext()
+	return o.apiClient.Changefeeds().ResolveDDL(ctx, o.changefeedID, &v2.ResolveDDLReq{
+		JobID:  o.jobID,
+		Action: o.action,
+		Query:  o.query,
+	})
+}
+
+// newCmdResolveDDL creates the `cli changefeed resolve-ddl` command.
+func newCmdResolveDDL(f factory.Factory) *cobra.Command {
+	o := newResolveDDLOptions()
+
+	command := &cobra.Command{
+		Use:   "resolve-ddl",
+		Short: "Approve, skip or replace the pending DDL of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.complete(f))
+			util.CheckErr(o.validate())
+			util.CheckErr(o.run())
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedResolveDDLCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdResolveDDL(f)
+	f.changefeedsv2.EXPECT().ResolveDDL(gomock.Any(), "abc", &v2.ResolveDDLReq{
+		JobID: 10, Action: "approve",
+	}).Return(nil)
+	os.Args = []string{"resolve-ddl", "--changefeed-id=abc", "--job-id=10", "--action=approve"}
+	require.Nil(t, cmd.Execute())
+
+	f.changefeedsv2.EXPECT().ResolveDDL(gomock.Any(), "abc", gomock.Any()).
+		Return(errors.New("test"))
+	o := newResolveDDLOptions()
+	o.changefeedID = "abc"
+	o.jobID = 10
+	o.action = "replace"
+	o.query = "RENAME TABLE t1 TO t1_bak"
+	require.Nil(t, o.complete(f))
+	require.Nil(t, o.validate())
+	require.NotNil(t, o.run())
+
+	o.action = "skip"
+	require.Regexp(t, ".*only allowed by the replace action.*", o.validate())
+	o.action = "replace"
+	o.query = ""
+	require.Regexp(t, ".*required by the replace action.*", o.validate())
+	o.action = "ignore"
+	require.Regexp(t, ".*must be one of approve, skip and replace.*", o.validate())
+}
@@ -43,6 +43,7 @@ type ClientGetter interface {
 	GetServerAddr() string
 	GetLogLevel() string
 	GetCredential() *security.Credential
+	GetAuthCredential() *security.AuthCredential
 }
 
 // ClientFlags specifies the parameters needed to construct the client.
@@ -53,6 +54,9 @@ type ClientFlags struct {
 	caPath     string
 	certPath   string
 	keyPath    string
+	user       string
+	password   string
+	token      string
 }
 
 var _ ClientGetter = &ClientFlags{}
@@ -112,6 +116,12 @@ func (c *ClientFlags) AddFlags(cmd *cobra.Command) {
 		"Certificate path for TLS connection to CDC server")
 	cmd.PersistentFlags().StringVar(&c.keyPath, "key", "",
 		"Private key path for TLS connection to CDC server")
+	cmd.PersistentFlags().StringVar(&c.user, "user", "",
+		"Upstream TiDB user for authenticating to CDC server")
+	cmd.PersistentFlags().StringVar(&c.password, "password", "",
+		"Upstream TiDB password for authenticating to CDC server")
+	cmd.PersistentFlags().StringVar(&c.token, "token", "",
+		"Token for authenticating to CDC server, it takes precedence over --user")
 	cmd.PersistentFlags().StringVar(&c.logLevel, "log-level", "warn",
 		"log level (etc: debug|info|warn|error)")
 }
@@ -127,3 +137,12 @@ func (c *ClientFlags) GetCredential() *security.Credential {
 		CertAllowedCN: certAllowedCN,
 	}
 }
+
+// GetAuthCredential returns the credential for authenticating to CDC server.
+func (c *ClientFlags) GetAuthCredential() *security.AuthCredential {
+	return &security.AuthCredential{
+		User:     c.user,
+		Password: c.password,
+		Token:    c.token,
+	}
+}
@@ -87,6 +87,11 @@ func (f *factoryImpl) GetCredential() *security.Credential {
 	return f.clientGetter.GetCredential()
 }
 
+// GetAuthCredential returns the credential for authenticating to CDC server.
+func (f *factoryImpl) GetAuthCredential() *security.AuthCredential {
+	return f.clientGetter.GetAuthCredential()
+}
+
 // EtcdClient creates new cdc etcd client.
 func (f *factoryImpl) EtcdClient() (*etcd.CDCEtcdClientImpl, error) {
 	ctx := cmdconetxt.GetDefaultContext()
@@ -207,7 +212,8 @@ func (f *factoryImpl) APIV1Client() (apiv1client.APIV1Interface, error) {
 		return nil, errors.Trace(err)
 	}
 	log.Info(serverAddr)
-	client, err := apiv1client.NewAPIClient(serverAddr, f.clientGetter.GetCredential())
+	client, err := apiv1client.NewAPIClient(serverAddr,
+		f.clientGetter.GetCredential(), f.clientGetter.GetAuthCredential())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -221,14 +227,16 @@ func (f *factoryImpl) APIV2Client() (apiv2client.APIV2Interface, error) {
 		return nil, errors.Trace(err)
 	}
 	log.Info(serverAddr)
-	client, err := apiv1client.NewAPIClient(serverAddr, f.clientGetter.GetCredential())
+	client, err := apiv1client.NewAPIClient(serverAddr,
+		f.clientGetter.GetCredential(), f.clientGetter.GetAuthCredential())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	if err := checkCDCVersion(client); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return apiv2client.NewAPIClient(serverAddr, f.clientGetter.GetCredential())
+	return apiv2client.NewAPIClient(serverAddr,
+		f.clientGetter.GetCredential(), f.clientGetter.GetAuthCredential())
 }
 
 // findServerAddr find the cdc server address by the following logic
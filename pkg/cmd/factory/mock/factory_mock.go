@@ -85,6 +85,20 @@ func (mr *MockFactoryMockRecorder) EtcdClient() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EtcdClient", reflect.TypeOf((*MockFactory)(nil).EtcdClient))
 }
 
+// GetAuthCredential mocks base method.
+func (m *MockFactory) GetAuthCredential() *security.AuthCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthCredential")
+	ret0, _ := ret[0].(*security.AuthCredential)
+	return ret0
+}
+
+// GetAuthCredential indicates an expected call of GetAuthCredential.
+func (mr *MockFactoryMockRecorder) GetAuthCredential() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthCredential", reflect.TypeOf((*MockFactory)(nil).GetAuthCredential))
+}
+
 // GetCredential mocks base method.
 func (m *MockFactory) GetCredential() *security.Credential {
 	m.ctrl.T.Helper()
@@ -209,6 +223,20 @@ func (m *MockClientGetter) EXPECT() *MockClientGetterMockRecorder {
 	return m.recorder
 }
 
+// GetAuthCredential mocks base method.
+func (m *MockClientGetter) GetAuthCredential() *security.AuthCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthCredential")
+	ret0, _ := ret[0].(*security.AuthCredential)
+	return ret0
+}
+
+// GetAuthCredential indicates an expected call of GetAuthCredential.
+func (mr *MockClientGetterMockRecorder) GetAuthCredential() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthCredential", reflect.TypeOf((*MockClientGetter)(nil).GetAuthCredential))
+}
+
 // GetCredential mocks base method.
 func (m *MockClientGetter) GetCredential() *security.Credential {
 	m.ctrl.T.Helper()
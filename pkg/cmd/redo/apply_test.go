@@ -39,4 +39,9 @@ func TestComplete(t *testing.T) {
 	err = o.complete(cmd)
 	require.NoError(t, err)
 	require.Equal(t, "mysql://root@127.0.0.1:3306?time-zone=UTC&safe-mode=true", o.sinkURI)
+
+	o.sinkURI = "kafka://127.0.0.1:9092/test?protocol=canal-json"
+	err = o.complete(cmd)
+	require.NoError(t, err)
+	require.Equal(t, "kafka://127.0.0.1:9092/test?protocol=canal-json", o.sinkURI)
 }
@@ -0,0 +1,405 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	filter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo"
+	"github.com/pingcap/tiflow/cdc/redo/reader"
+	"github.com/pingcap/tiflow/pkg/applier"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	dumpFormatJSON = "json"
+	dumpFormatCSV  = "csv"
+
+	eventTypeInsert = "insert"
+	eventTypeUpdate = "update"
+	eventTypeDelete = "delete"
+	eventTypeDDL    = "ddl"
+
+	dumpReadBatch = 1024
+)
+
+var dumpCSVHeader = []string{
+	"type", "start-ts", "commit-ts", "schema", "table", "pre-columns", "columns", "query",
+}
+
+// dumpRedoOptions defines flags for the `redo dump` command.
+type dumpRedoOptions struct {
+	options
+	tables     []string
+	eventTypes []string
+	startTs    uint64
+	endTs      uint64
+	format     string
+	output     string
+
+	tableFilter filter.Filter
+	typeSet     map[string]struct{}
+}
+
+// newDumpRedoOptions creates new dumpRedoOptions for the `redo dump` command.
+func newDumpRedoOptions() *dumpRedoOptions {
+	return &dumpRedoOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *dumpRedoOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.tables, "table", []string{"*.*"},
+		"table filter rules of the dumped rows, eg, \"test.*\". "+
+			"DDLs are not filtered by table, since redo logs don't record the tables of DDLs")
+	cmd.Flags().StringSliceVar(&o.eventTypes, "type",
+		[]string{eventTypeInsert, eventTypeUpdate, eventTypeDelete, eventTypeDDL},
+		"event types to dump, the possible values are insert, update, delete and ddl")
+	cmd.Flags().Uint64Var(&o.startTs, "start-ts", 0,
+		"dump events whose commit-ts is greater than start-ts, defaults to the checkpoint-ts of redo logs")
+	cmd.Flags().Uint64Var(&o.endTs, "end-ts", 0,
+		"dump events whose commit-ts is less than or equal to end-ts, defaults to the resolved-ts of redo logs")
+	cmd.Flags().StringVar(&o.format, "format", dumpFormatJSON, "output format, json or csv")
+	cmd.Flags().StringVar(&o.output, "output", "", "output file path, defaults to stdout")
+}
+
+func (o *dumpRedoOptions) complete(cmd *cobra.Command) error {
+	o.format = strings.ToLower(strings.TrimSpace(o.format))
+	if o.format != dumpFormatJSON && o.format != dumpFormatCSV {
+		return errors.Errorf("unsupported format %s, only json and csv are supported", o.format)
+	}
+
+	o.typeSet = make(map[string]struct{}, len(o.eventTypes))
+	for _, tp := range o.eventTypes {
+		tp = strings.ToLower(strings.TrimSpace(tp))
+		switch tp {
+		case eventTypeInsert, eventTypeUpdate, eventTypeDelete, eventTypeDDL:
+			o.typeSet[tp] = struct{}{}
+		default:
+			return errors.Errorf("unsupported event type %s", tp)
+		}
+	}
+
+	tableFilter, err := filter.Parse(o.tables)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.tableFilter = filter.CaseInsensitive(tableFilter)
+
+	if o.endTs != 0 && o.startTs >= o.endTs {
+		return errors.Errorf("start-ts %d should be less than end-ts %d", o.startTs, o.endTs)
+	}
+	return nil
+}
+
+// run runs the `redo dump` command.
+func (o *dumpRedoOptions) run(cmd *cobra.Command) error {
+	ctx := cmdcontext.GetDefaultContext()
+
+	rd, err := applier.NewRedoReader(ctx, &applier.RedoApplierConfig{
+		Storage: o.storage,
+		Dir:     o.dir,
+	})
+	if err != nil {
+		return err
+	}
+	defer rd.Close() //nolint:errcheck
+
+	var w io.Writer = cmd.OutOrStdout()
+	if o.output != "" {
+		f, err := os.Create(o.output)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer f.Close() //nolint:errcheck
+		w = f
+	}
+	return o.dump(ctx, rd, w)
+}
+
+// dump writes the events in the redo logs, which match the filters, to w.
+// Rows and DDLs are written in the order of commit-ts, a DDL is written before
+// the rows with the same commit-ts.
+func (o *dumpRedoOptions) dump(
+	ctx context.Context, rd reader.RedoLogReader, w io.Writer,
+) error {
+	checkpointTs, resolvedTs, err := rd.ReadMeta(ctx)
+	if err != nil {
+		return err
+	}
+	startTs, endTs := checkpointTs, resolvedTs
+	if o.startTs > startTs {
+		startTs = o.startTs
+	}
+	if o.endTs != 0 && o.endTs < endTs {
+		endTs = o.endTs
+	}
+
+	bw := bufio.NewWriter(w)
+	ew := newEventWriter(o.format, bw)
+	if err := ew.writeHeader(); err != nil {
+		return err
+	}
+	if startTs < endTs {
+		log.Info("dump redo log starts",
+			zap.Uint64("startTs", startTs), zap.Uint64("endTs", endTs))
+		if err := rd.ResetReader(ctx, startTs, endTs); err != nil {
+			return err
+		}
+		if err := o.dumpEvents(ctx, rd, ew); err != nil {
+			return err
+		}
+	}
+	if err := ew.flush(); err != nil {
+		return err
+	}
+	return errors.Trace(bw.Flush())
+}
+
+func (o *dumpRedoOptions) dumpEvents(
+	ctx context.Context, rd reader.RedoLogReader, ew eventWriter,
+) error {
+	var ddls []*model.DDLEvent
+	if o.matchType(eventTypeDDL) {
+		for {
+			redoDDLs, err := rd.ReadNextDDL(ctx, dumpReadBatch)
+			if err != nil {
+				return err
+			}
+			if len(redoDDLs) == 0 {
+				break
+			}
+			for _, redoDDL := range redoDDLs {
+				ddls = append(ddls, redo.LogToDDL(redoDDL))
+			}
+		}
+	}
+	writeDDLsBefore := func(commitTs uint64) error {
+		for len(ddls) > 0 && ddls[0].CommitTs <= commitTs {
+			if err := ew.write(newDumpDDLEvent(ddls[0])); err != nil {
+				return err
+			}
+			ddls = ddls[1:]
+		}
+		return nil
+	}
+
+	dumpDML := o.matchType(eventTypeInsert) || o.matchType(eventTypeUpdate) ||
+		o.matchType(eventTypeDelete)
+	for dumpDML {
+		redoLogs, err := rd.ReadNextLog(ctx, dumpReadBatch)
+		if err != nil {
+			return err
+		}
+		if len(redoLogs) == 0 {
+			break
+		}
+		for _, redoLog := range redoLogs {
+			row := redo.LogToRow(redoLog)
+			if !o.matchType(rowEventType(row)) ||
+				!o.tableFilter.MatchTable(row.Table.Schema, row.Table.Table) {
+				continue
+			}
+			if err := writeDDLsBefore(row.CommitTs); err != nil {
+				return err
+			}
+			if err := ew.write(newDumpRowEvent(row)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, ddl := range ddls {
+		if err := ew.write(newDumpDDLEvent(ddl)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *dumpRedoOptions) matchType(tp string) bool {
+	_, ok := o.typeSet[tp]
+	return ok
+}
+
+func rowEventType(row *model.RowChangedEvent) string {
+	if row.IsDelete() {
+		return eventTypeDelete
+	}
+	if row.IsUpdate() {
+		return eventTypeUpdate
+	}
+	return eventTypeInsert
+}
+
+// dumpEvent is the output format of an event in redo logs.
+type dumpEvent struct {
+	Type       string                 `json:"type"`
+	StartTs    uint64                 `json:"start-ts"`
+	CommitTs   uint64                 `json:"commit-ts"`
+	Schema     string                 `json:"schema,omitempty"`
+	Table      string                 `json:"table,omitempty"`
+	PreColumns map[string]interface{} `json:"pre-columns,omitempty"`
+	Columns    map[string]interface{} `json:"columns,omitempty"`
+	Query      string                 `json:"query,omitempty"`
+}
+
+func newDumpRowEvent(row *model.RowChangedEvent) *dumpEvent {
+	return &dumpEvent{
+		Type:       rowEventType(row),
+		StartTs:    row.StartTs,
+		CommitTs:   row.CommitTs,
+		Schema:     row.Table.Schema,
+		Table:      row.Table.Table,
+		PreColumns: columnsToMap(row.PreColumns),
+		Columns:    columnsToMap(row.Columns),
+	}
+}
+
+func newDumpDDLEvent(ddl *model.DDLEvent) *dumpEvent {
+	return &dumpEvent{
+		Type:     eventTypeDDL,
+		StartTs:  ddl.StartTs,
+		CommitTs: ddl.CommitTs,
+		Query:    ddl.Query,
+	}
+}
+
+func columnsToMap(cols []*model.Column) map[string]interface{} {
+	if len(cols) == 0 {
+		return nil
+	}
+	res := make(map[string]interface{}, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		// print binary values as strings instead of base64 encoded strings.
+		if v, ok := col.Value.([]byte); ok {
+			res[col.Name] = string(v)
+			continue
+		}
+		res[col.Name] = col.Value
+	}
+	return res
+}
+
+// eventWriter writes dumped events in a specific format.
+type eventWriter interface {
+	writeHeader() error
+	write(event *dumpEvent) error
+	flush() error
+}
+
+func newEventWriter(format string, w io.Writer) eventWriter {
+	if format == dumpFormatCSV {
+		return &csvEventWriter{w: csv.NewWriter(w)}
+	}
+	return &jsonEventWriter{enc: json.NewEncoder(w)}
+}
+
+// jsonEventWriter writes one JSON object per line.
+type jsonEventWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonEventWriter) writeHeader() error {
+	return nil
+}
+
+func (w *jsonEventWriter) write(event *dumpEvent) error {
+	return errors.Trace(w.enc.Encode(event))
+}
+
+func (w *jsonEventWriter) flush() error {
+	return nil
+}
+
+// csvEventWriter writes events as CSV records, the columns of rows
+// are encoded as JSON objects.
+type csvEventWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvEventWriter) writeHeader() error {
+	return errors.Trace(w.w.Write(dumpCSVHeader))
+}
+
+func (w *csvEventWriter) write(event *dumpEvent) error {
+	preColumns, err := marshalColumns(event.PreColumns)
+	if err != nil {
+		return err
+	}
+	columns, err := marshalColumns(event.Columns)
+	if err != nil {
+		return err
+	}
+	return errors.Trace(w.w.Write([]string{
+		event.Type,
+		strconv.FormatUint(event.StartTs, 10),
+		strconv.FormatUint(event.CommitTs, 10),
+		event.Schema,
+		event.Table,
+		preColumns,
+		columns,
+		event.Query,
+	}))
+}
+
+func (w *csvEventWriter) flush() error {
+	w.w.Flush()
+	return errors.Trace(w.w.Error())
+}
+
+func marshalColumns(cols map[string]interface{}) (string, error) {
+	if cols == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(cols)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
+// newCmdDump creates the `redo dump` command.
+func newCmdDump(opt *options) *cobra.Command {
+	o := newDumpRedoOptions()
+	command := &cobra.Command{
+		Use:   "dump",
+		Short: "Dump the events in redo logs in json or csv format",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.options = *opt
+			if err := o.complete(cmd); err != nil {
+				return err
+			}
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,191 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/redo"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReader struct {
+	checkpointTs uint64
+	resolvedTs   uint64
+	startTs      uint64
+	endTs        uint64
+	rows         []*model.RedoRowChangedEvent
+	ddls         []*model.RedoDDLEvent
+}
+
+func (r *mockReader) ResetReader(ctx context.Context, startTs, endTs uint64) error {
+	r.startTs, r.endTs = startTs, endTs
+	return nil
+}
+
+func (r *mockReader) ReadNextLog(
+	ctx context.Context, maxNumberOfEvents uint64,
+) ([]*model.RedoRowChangedEvent, error) {
+	rows := r.rows
+	r.rows = nil
+	return rows, nil
+}
+
+func (r *mockReader) ReadNextDDL(
+	ctx context.Context, maxNumberOfEvents uint64,
+) ([]*model.RedoDDLEvent, error) {
+	ddls := r.ddls
+	r.ddls = nil
+	return ddls, nil
+}
+
+func (r *mockReader) ReadMeta(ctx context.Context) (checkpointTs, resolvedTs uint64, err error) {
+	return r.checkpointTs, r.resolvedTs, nil
+}
+
+func (r *mockReader) Close() error {
+	return nil
+}
+
+func newMockReader() *mockReader {
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:  1100,
+			CommitTs: 1200,
+			Table:    &model.TableName{Schema: "test", Table: "t1"},
+			Columns: []*model.Column{
+				{Name: "a", Value: int64(1), Flag: model.HandleKeyFlag},
+				{Name: "b", Value: []byte("2")},
+			},
+		},
+		{
+			StartTs:  1200,
+			CommitTs: 1300,
+			Table:    &model.TableName{Schema: "test", Table: "t2"},
+			PreColumns: []*model.Column{
+				{Name: "a", Value: int64(1), Flag: model.HandleKeyFlag},
+			},
+		},
+		{
+			StartTs:  1300,
+			CommitTs: 1400,
+			Table:    &model.TableName{Schema: "test", Table: "t1"},
+			PreColumns: []*model.Column{
+				{Name: "a", Value: int64(1), Flag: model.HandleKeyFlag},
+				{Name: "b", Value: "2"},
+			},
+			Columns: []*model.Column{
+				{Name: "a", Value: int64(1), Flag: model.HandleKeyFlag},
+				{Name: "b", Value: "3"},
+			},
+		},
+	}
+	ddls := []*model.DDLEvent{
+		{StartTs: 1250, CommitTs: 1300, Query: "alter table test.t1 add column c int"},
+		{StartTs: 1450, CommitTs: 1500, Query: "drop table test.t2"},
+	}
+	r := &mockReader{checkpointTs: 1000, resolvedTs: 2000}
+	for _, row := range rows {
+		r.rows = append(r.rows, redo.RowToRedo(row))
+	}
+	for _, ddl := range ddls {
+		r.ddls = append(r.ddls, redo.DDLToRedo(ddl))
+	}
+	return r
+}
+
+func TestDumpComplete(t *testing.T) {
+	cmd := &cobra.Command{}
+	o := newDumpRedoOptions()
+	o.addFlags(cmd)
+	require.NoError(t, o.complete(cmd))
+	require.Len(t, o.typeSet, 4)
+	require.True(t, o.tableFilter.MatchTable("test", "t1"))
+
+	o.format = "xml"
+	require.ErrorContains(t, o.complete(cmd), "unsupported format xml")
+
+	o.format = " CSV "
+	o.eventTypes = []string{"truncate"}
+	require.ErrorContains(t, o.complete(cmd), "unsupported event type truncate")
+
+	o.eventTypes = []string{"insert"}
+	o.startTs, o.endTs = 100, 100
+	require.ErrorContains(t, o.complete(cmd), "start-ts 100 should be less than end-ts 100")
+
+	o.startTs = 0
+	o.tables = []string{"test.t1"}
+	require.NoError(t, o.complete(cmd))
+	require.Equal(t, dumpFormatCSV, o.format)
+	require.True(t, o.tableFilter.MatchTable("TEST", "T1"))
+	require.False(t, o.tableFilter.MatchTable("test", "t2"))
+}
+
+func TestDumpJSON(t *testing.T) {
+	ctx := context.Background()
+	cmd := &cobra.Command{}
+	o := newDumpRedoOptions()
+	o.addFlags(cmd)
+	require.NoError(t, o.complete(cmd))
+
+	rd := newMockReader()
+	var buf bytes.Buffer
+	require.NoError(t, o.dump(ctx, rd, &buf))
+	require.Equal(t, uint64(1000), rd.startTs)
+	require.Equal(t, uint64(2000), rd.endTs)
+	require.Equal(t, `{"type":"insert","start-ts":1100,"commit-ts":1200,"schema":"test","table":"t1","columns":{"a":1,"b":"2"}}
+{"type":"ddl","start-ts":1250,"commit-ts":1300,"query":"alter table test.t1 add column c int"}
+{"type":"delete","start-ts":1200,"commit-ts":1300,"schema":"test","table":"t2","pre-columns":{"a":1}}
+{"type":"update","start-ts":1300,"commit-ts":1400,"schema":"test","table":"t1","pre-columns":{"a":1,"b":"2"},"columns":{"a":1,"b":"3"}}
+{"type":"ddl","start-ts":1450,"commit-ts":1500,"query":"drop table test.t2"}
+`, buf.String())
+
+	// filter by table and event type, and limit the commit-ts range.
+	o.tables = []string{"test.t1"}
+	o.eventTypes = []string{"update"}
+	o.startTs, o.endTs = 1100, 1800
+	require.NoError(t, o.complete(cmd))
+	rd = newMockReader()
+	buf.Reset()
+	require.NoError(t, o.dump(ctx, rd, &buf))
+	require.Equal(t, uint64(1100), rd.startTs)
+	require.Equal(t, uint64(1800), rd.endTs)
+	require.Equal(t, `{"type":"update","start-ts":1300,"commit-ts":1400,"schema":"test","table":"t1","pre-columns":{"a":1,"b":"2"},"columns":{"a":1,"b":"3"}}
+`, buf.String())
+}
+
+func TestDumpCSV(t *testing.T) {
+	ctx := context.Background()
+	cmd := &cobra.Command{}
+	o := newDumpRedoOptions()
+	o.addFlags(cmd)
+	o.format = dumpFormatCSV
+	o.eventTypes = []string{"insert", "ddl"}
+	require.NoError(t, o.complete(cmd))
+
+	var buf bytes.Buffer
+	require.NoError(t, o.dump(ctx, newMockReader(), &buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{
+		"type,start-ts,commit-ts,schema,table,pre-columns,columns,query",
+		`insert,1100,1200,test,t1,,"{""a"":1,""b"":""2""}",`,
+		"ddl,1250,1300,,,,,alter table test.t1 add column c int",
+		"ddl,1450,1500,,,,,drop table test.t2",
+	}, lines)
+}
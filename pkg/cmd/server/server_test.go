@@ -212,6 +212,9 @@ func TestParseCfg(t *testing.T) {
 				CheckBalanceInterval: 60000000000,
 				AddTableBatchSize:    50,
 				RegionPerSpan:        0,
+				WriteBytesPerSpan:    0,
+				BalanceStrategy:      config.BalanceStrategyTableCount,
+				BalanceThreshold:     0.2,
 			},
 			EnableNewSink: true,
 		},
@@ -369,6 +372,9 @@ check-balance-interval = "10s"
 				CheckBalanceInterval: config.TomlDuration(10 * time.Second),
 				AddTableBatchSize:    50,
 				RegionPerSpan:        0,
+				WriteBytesPerSpan:    0,
+				BalanceStrategy:      config.BalanceStrategyTableCount,
+				BalanceThreshold:     0.2,
 			},
 			EnableNewSink: true,
 		},
@@ -518,6 +524,9 @@ cert-allowed-cn = ["dd","ee"]
 				CheckBalanceInterval: 60000000000,
 				AddTableBatchSize:    50,
 				RegionPerSpan:        0,
+				WriteBytesPerSpan:    0,
+				BalanceStrategy:      config.BalanceStrategyTableCount,
+				BalanceThreshold:     0.2,
 			},
 			EnableNewSink: true,
 		},
@@ -584,6 +593,9 @@ unknown3 = 3
 			CheckBalanceInterval: 60000000000,
 			AddTableBatchSize:    50,
 			RegionPerSpan:        0,
+			WriteBytesPerSpan:    0,
+			BalanceStrategy:      config.BalanceStrategyTableCount,
+			BalanceThreshold:     0.2,
 		},
 		EnableNewSink: true,
 	}, o.serverConfig.Debug)
@@ -0,0 +1,159 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// None no compression
+	None string = "none"
+	// GZIP compression
+	GZIP string = "gzip"
+	// Snappy compression
+	Snappy string = "snappy"
+	// ZSTD compression
+	ZSTD string = "zstd"
+	// LZ4 compression
+	LZ4 string = "lz4"
+)
+
+// the encoder and decoder are safe for concurrent use when only
+// EncodeAll and DecodeAll are called.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// Supported returns the normalized name of the compression codec,
+// an error is returned if the codec is not supported.
+func Supported(cc string) (string, error) {
+	cc = strings.ToLower(strings.TrimSpace(cc))
+	switch cc {
+	case "":
+		return None, nil
+	case None, GZIP, Snappy, ZSTD, LZ4:
+		return cc, nil
+	default:
+		return "", fmt.Errorf("unsupported compression %s", cc)
+	}
+}
+
+// FileExtension returns the file extension suffix of the compression codec,
+// e.g. ".gz" for gzip. It returns an empty string if there is no compression.
+func FileExtension(cc string) string {
+	switch cc {
+	case GZIP:
+		return ".gz"
+	case Snappy:
+		return ".snappy"
+	case ZSTD:
+		return ".zst"
+	case LZ4:
+		return ".lz4"
+	default:
+		return ""
+	}
+}
+
+// FromFileExtension returns the compression codec of a file extension
+// suffix, it's the reverse of FileExtension.
+func FromFileExtension(ext string) string {
+	switch ext {
+	case ".gz":
+		return GZIP
+	case ".snappy":
+		return Snappy
+	case ".zst":
+		return ZSTD
+	case ".lz4":
+		return LZ4
+	default:
+		return None
+	}
+}
+
+// Encode compresses the data with the compression codec.
+func Encode(cc string, data []byte) ([]byte, error) {
+	switch cc {
+	case None, "":
+		return data, nil
+	case GZIP:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, cerror.ErrCompressionFailed.Wrap(err).GenWithStackByArgs(cc)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, cerror.ErrCompressionFailed.Wrap(err).GenWithStackByArgs(cc)
+		}
+		return buf.Bytes(), nil
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+	case ZSTD:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	case LZ4:
+		var buf bytes.Buffer
+		writer := lz4.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, cerror.ErrCompressionFailed.Wrap(err).GenWithStackByArgs(cc)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, cerror.ErrCompressionFailed.Wrap(err).GenWithStackByArgs(cc)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, cerror.ErrCompressionFailed.GenWithStack("unsupported compression %s", cc)
+	}
+}
+
+// Decode decompresses the data which is compressed by Encode with the same codec.
+func Decode(cc string, data []byte) ([]byte, error) {
+	var (
+		res []byte
+		err error
+	)
+	switch cc {
+	case None, "":
+		return data, nil
+	case GZIP:
+		var reader *gzip.Reader
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+		if err == nil {
+			res, err = io.ReadAll(reader)
+		}
+	case Snappy:
+		res, err = snappy.Decode(nil, data)
+	case ZSTD:
+		res, err = zstdDecoder.DecodeAll(data, nil)
+	case LZ4:
+		res, err = io.ReadAll(lz4.NewReader(bytes.NewReader(data)))
+	default:
+		return nil, cerror.ErrDecompressionFailed.GenWithStack("unsupported compression %s", cc)
+	}
+	if err != nil {
+		return nil, cerror.ErrDecompressionFailed.Wrap(err).GenWithStackByArgs(cc)
+	}
+	return res, nil
+}
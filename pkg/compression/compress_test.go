@@ -0,0 +1,57 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressAndDecompress(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("INSERT,t,1,\"hello world\"\n"), 1024)
+	for _, cc := range []string{None, GZIP, Snappy, ZSTD, LZ4} {
+		compressed, err := Encode(cc, data)
+		require.Nil(t, err, cc)
+		if cc != None {
+			require.Less(t, len(compressed), len(data), cc)
+		}
+		decompressed, err := Decode(cc, compressed)
+		require.Nil(t, err, cc)
+		require.Equal(t, data, decompressed, cc)
+
+		require.Equal(t, cc, FromFileExtension(FileExtension(cc)))
+	}
+
+	_, err := Decode(GZIP, []byte("invalid"))
+	require.ErrorContains(t, err, "decompress data with gzip failed")
+	_, err = Encode("brotli", data)
+	require.ErrorContains(t, err, "unsupported compression")
+}
+
+func TestSupported(t *testing.T) {
+	t.Parallel()
+
+	cc, err := Supported("")
+	require.Nil(t, err)
+	require.Equal(t, None, cc)
+	cc, err = Supported(" ZSTD ")
+	require.Nil(t, err)
+	require.Equal(t, ZSTD, cc)
+	_, err = Supported("brotli")
+	require.ErrorContains(t, err, "unsupported compression brotli")
+}
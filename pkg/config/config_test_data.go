@@ -137,7 +137,10 @@ const (
       "max-task-concurrency": 10,
       "check-balance-interval": 60000000000,
       "add-table-batch-size": 50,
-      "region-per-span": 0
+      "region-per-span": 0,
+      "write-bytes-per-span": 0,
+      "balance-strategy": "table-count",
+      "balance-threshold": 0.2
     },
     "enable-new-sink": true
   },
@@ -189,7 +192,8 @@ const (
     "transaction-atomicity": "",
     "terminator": "",
     "date-separator": "month",
-    "enable-partition-separator": true
+    "enable-partition-separator": true,
+    "ddl-topic": ""
   },
   "consistent": {
     "level": "none",
@@ -241,7 +245,8 @@ const (
     },
     "terminator": "",
     "date-separator": "month",
-    "enable-partition-separator": true
+    "enable-partition-separator": true,
+    "ddl-topic": ""
   },
   "consistent": {
     "level": "none",
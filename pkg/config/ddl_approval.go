@@ -0,0 +1,85 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// DDLApprovalConfig represents the DDLs that must be approved by an operator
+// before they are replicated to the downstream.
+type DDLApprovalConfig struct {
+	// DDLTypes are the types of the DDLs that hold the changefeed until they
+	// are approved, skipped or replaced. A type is the name of a TiDB DDL job
+	// type, such as "drop table", "truncate table" and "drop column".
+	DDLTypes []string `toml:"ddl-types" json:"ddl-types"`
+}
+
+// ddlTypeNames are the names of all the TiDB DDL job types.
+var ddlTypeNames = func() map[string]struct{} {
+	names := make(map[string]struct{})
+	for tp := 1; tp <= 255; tp++ {
+		if name := timodel.ActionType(tp).String(); name != "none" {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}()
+
+// ValidateAndAdjust validates the ddl approval config.
+func (c *DDLApprovalConfig) ValidateAndAdjust() error {
+	for i, tp := range c.DDLTypes {
+		tp = strings.ToLower(strings.TrimSpace(tp))
+		if _, ok := ddlTypeNames[tp]; !ok {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				fmt.Sprintf("invalid ddl-approval ddl type %s", c.DDLTypes[i]))
+		}
+		c.DDLTypes[i] = tp
+	}
+	return nil
+}
+
+// NeedApproval returns true if the DDL job must be approved before it is
+// replicated. A multi-schema change needs approval if any of its sub jobs
+// does. A nil config never needs approval.
+func (c *DDLApprovalConfig) NeedApproval(job *timodel.Job) bool {
+	if c == nil || len(c.DDLTypes) == 0 {
+		return false
+	}
+	if c.matchType(job.Type) {
+		return true
+	}
+	if job.MultiSchemaInfo != nil {
+		for _, sub := range job.MultiSchemaInfo.SubJobs {
+			if c.matchType(sub.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *DDLApprovalConfig) matchType(tp timodel.ActionType) bool {
+	name := tp.String()
+	for _, t := range c.DDLTypes {
+		if strings.EqualFold(t, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLApprovalConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := GetDefaultReplicaConfig()
+	_, err := toml.Decode(`
+[ddl-approval]
+ddl-types = ["drop table", "Truncate Table", "drop column"]
+`, cfg)
+	require.Nil(t, err)
+	require.Nil(t, cfg.DDLApproval.ValidateAndAdjust())
+	require.Equal(t, []string{"drop table", "truncate table", "drop column"},
+		cfg.DDLApproval.DDLTypes)
+
+	// The config is kept after cloning.
+	cloned := cfg.Clone()
+	require.Equal(t, cfg.DDLApproval, cloned.DDLApproval)
+
+	approval := cfg.DDLApproval
+	require.True(t, approval.NeedApproval(&timodel.Job{Type: timodel.ActionDropTable}))
+	require.True(t, approval.NeedApproval(&timodel.Job{Type: timodel.ActionTruncateTable}))
+	require.False(t, approval.NeedApproval(&timodel.Job{Type: timodel.ActionCreateTable}))
+	require.False(t, approval.NeedApproval(&timodel.Job{Type: timodel.ActionAddColumn}))
+	// A multi-schema change needs approval if any of its sub jobs does.
+	require.True(t, approval.NeedApproval(&timodel.Job{
+		Type: timodel.ActionMultiSchemaChange,
+		MultiSchemaInfo: &timodel.MultiSchemaInfo{SubJobs: []*timodel.SubJob{
+			{Type: timodel.ActionAddColumn}, {Type: timodel.ActionDropColumn},
+		}},
+	}))
+	require.False(t, approval.NeedApproval(&timodel.Job{
+		Type: timodel.ActionMultiSchemaChange,
+		MultiSchemaInfo: &timodel.MultiSchemaInfo{SubJobs: []*timodel.SubJob{
+			{Type: timodel.ActionAddColumn}, {Type: timodel.ActionAddIndex},
+		}},
+	}))
+
+	// A nil config never needs approval.
+	var nilApproval *DDLApprovalConfig
+	require.False(t, nilApproval.NeedApproval(&timodel.Job{Type: timodel.ActionDropTable}))
+
+	approval = &DDLApprovalConfig{DDLTypes: []string{"drop everything"}}
+	require.Regexp(t, ".*invalid ddl-approval ddl type.*", approval.ValidateAndAdjust())
+}
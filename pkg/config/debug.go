@@ -62,12 +62,15 @@ func (c *DebugConfig) ValidateAndAdjust() error {
 	if err := c.Scheduler.ValidateAndAdjust(); err != nil {
 		return errors.Trace(err)
 	}
-	if c.Scheduler.RegionPerSpan != 0 {
+	if c.Scheduler.RegionPerSpan != 0 || c.Scheduler.WriteBytesPerSpan != 0 {
+		// The pull-based sink, the source manager and the redo manager
+		// replicate whole tables, so a table must not be split into spans.
 		if c.EnablePullBasedSink || !c.EnableNewSink {
 			// TODO: Removing this check once pull based sink is compatible with
 			//       span replication.
-			return cerror.ErrInvalidServerOption.GenWithStackByArgs(
-				"enabling span replication requires setting " +
+			return cerror.ErrInvalidServerOption.GenWithStack(
+				"enabling span replication by `region-per-span` or " +
+					"`write-bytes-per-span` requires setting " +
 					"`debug.enable-new-sink` to be true and " +
 					"`debug.enable-pull-based-sink` to be false")
 		}
@@ -0,0 +1,53 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/label"
+)
+
+// PlacementConfig represents the placement rule of a changefeed. Tables of
+// the changefeed are only replicated by the captures whose labels match all
+// the selectors.
+type PlacementConfig struct {
+	Selectors []*label.Selector `toml:"selectors" json:"selectors"`
+}
+
+// ValidateAndAdjust validates the placement config.
+func (c *PlacementConfig) ValidateAndAdjust() error {
+	for _, selector := range c.Selectors {
+		if err := selector.Validate(); err != nil {
+			return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+				fmt.Sprintf("invalid placement selector: %s", err.Error()))
+		}
+	}
+	return nil
+}
+
+// Matches returns whether the labels match all the selectors.
+// A nil config matches any labels.
+func (c *PlacementConfig) Matches(labels label.Set) bool {
+	if c == nil {
+		return true
+	}
+	for _, selector := range c.Selectors {
+		if !selector.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
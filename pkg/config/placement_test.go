@@ -0,0 +1,56 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pingcap/tiflow/pkg/label"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlacementConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := GetDefaultReplicaConfig()
+	_, err := toml.Decode(`
+[placement]
+selectors = [
+    { label = "zone", op = "eq", target = "us-west-1a" },
+    { label = "disk", op = "regex", target = "ssd|nvme" },
+]
+`, cfg)
+	require.Nil(t, err)
+	require.Nil(t, cfg.Placement.ValidateAndAdjust())
+	require.Len(t, cfg.Placement.Selectors, 2)
+
+	require.True(t, cfg.Placement.Matches(label.Set{"zone": "us-west-1a", "disk": "nvme"}))
+	require.False(t, cfg.Placement.Matches(label.Set{"zone": "us-west-1a", "disk": "hdd"}))
+	require.False(t, cfg.Placement.Matches(label.Set{"zone": "us-east-1a", "disk": "ssd"}))
+	require.False(t, cfg.Placement.Matches(label.NewSet()))
+
+	// A nil placement matches any captures.
+	var nilPlacement *PlacementConfig
+	require.True(t, nilPlacement.Matches(label.NewSet()))
+
+	// The placement is kept after cloning.
+	cloned := cfg.Clone()
+	require.Len(t, cloned.Placement.Selectors, 2)
+	require.True(t, cloned.Placement.Matches(label.Set{"zone": "us-west-1a", "disk": "ssd"}))
+
+	cfg.Placement.Selectors = append(cfg.Placement.Selectors,
+		&label.Selector{Key: "zone", Op: "in", Target: "a"})
+	require.Regexp(t, ".*invalid placement selector.*", cfg.Placement.ValidateAndAdjust())
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// ChangefeedPriorityHigh is the priority of critical changefeeds, they
+	// can use all of the memory quota of a capture.
+	ChangefeedPriorityHigh = "high"
+	// ChangefeedPriorityNormal is the default priority of changefeeds.
+	ChangefeedPriorityNormal = "normal"
+	// ChangefeedPriorityLow is the priority of changefeeds that give way to
+	// others under memory pressure.
+	ChangefeedPriorityLow = "low"
+)
+
+// QuotaConfig represents the resource quotas of a changefeed on each capture.
+// The worker numbers are only used by the pull-based sink.
+type QuotaConfig struct {
+	// Priority is the priority of the changefeed when the memory quota of
+	// a capture is under pressure, one of "high", "normal" and "low".
+	Priority string `toml:"priority" json:"priority"`
+	// SinkWorkerNum is the number of workers that write events to the sink,
+	// 0 means the default value.
+	SinkWorkerNum int `toml:"sink-worker-num" json:"sink-worker-num"`
+	// RedoWorkerNum is the number of workers that write events to the redo
+	// log, 0 means the default value.
+	RedoWorkerNum int `toml:"redo-worker-num" json:"redo-worker-num"`
+}
+
+// ValidateAndAdjust validates the quota config.
+func (c *QuotaConfig) ValidateAndAdjust() error {
+	switch c.Priority {
+	case "":
+		c.Priority = ChangefeedPriorityNormal
+	case ChangefeedPriorityHigh, ChangefeedPriorityNormal, ChangefeedPriorityLow:
+	default:
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			fmt.Sprintf("invalid quota priority %s, "+
+				"must be one of high, normal and low", c.Priority))
+	}
+	if c.SinkWorkerNum < 0 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"quota sink-worker-num must not be negative")
+	}
+	if c.RedoWorkerNum < 0 {
+		return cerror.ErrInvalidReplicaConfig.GenWithStackByArgs(
+			"quota redo-worker-num must not be negative")
+	}
+	return nil
+}
+
+// GetPriority returns the priority of the changefeed.
+// A nil config returns the normal priority.
+func (c *QuotaConfig) GetPriority() string {
+	if c == nil || c.Priority == "" {
+		return ChangefeedPriorityNormal
+	}
+	return c.Priority
+}
+
+// GetSinkWorkerNum returns the number of sink workers, or defaultNum if it
+// is not set.
+func (c *QuotaConfig) GetSinkWorkerNum(defaultNum int) int {
+	if c == nil || c.SinkWorkerNum == 0 {
+		return defaultNum
+	}
+	return c.SinkWorkerNum
+}
+
+// GetRedoWorkerNum returns the number of redo workers, or defaultNum if it
+// is not set.
+func (c *QuotaConfig) GetRedoWorkerNum(defaultNum int) int {
+	if c == nil || c.RedoWorkerNum == 0 {
+		return defaultNum
+	}
+	return c.RedoWorkerNum
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := GetDefaultReplicaConfig()
+	_, err := toml.Decode(`
+[quota]
+priority = "high"
+sink-worker-num = 2
+`, cfg)
+	require.Nil(t, err)
+	require.Nil(t, cfg.Quota.ValidateAndAdjust())
+	require.Equal(t, ChangefeedPriorityHigh, cfg.Quota.GetPriority())
+	require.Equal(t, 2, cfg.Quota.GetSinkWorkerNum(8))
+	require.Equal(t, 4, cfg.Quota.GetRedoWorkerNum(4))
+
+	// The quota is kept after cloning.
+	cloned := cfg.Clone()
+	require.Equal(t, cfg.Quota, cloned.Quota)
+
+	// A nil quota uses the defaults.
+	var nilQuota *QuotaConfig
+	require.Equal(t, ChangefeedPriorityNormal, nilQuota.GetPriority())
+	require.Equal(t, 8, nilQuota.GetSinkWorkerNum(8))
+	require.Equal(t, 4, nilQuota.GetRedoWorkerNum(4))
+
+	quota := &QuotaConfig{}
+	require.Nil(t, quota.ValidateAndAdjust())
+	require.Equal(t, ChangefeedPriorityNormal, quota.Priority)
+
+	quota = &QuotaConfig{Priority: "urgent"}
+	require.Regexp(t, ".*invalid quota priority.*", quota.ValidateAndAdjust())
+	quota = &QuotaConfig{SinkWorkerNum: -1}
+	require.Regexp(t, ".*sink-worker-num.*", quota.ValidateAndAdjust())
+	quota = &QuotaConfig{RedoWorkerNum: -1}
+	require.Regexp(t, ".*redo-worker-num.*", quota.ValidateAndAdjust())
+}
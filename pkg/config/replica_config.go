@@ -104,6 +104,18 @@ type replicaConfig struct {
 	Mounter            *MounterConfig    `toml:"mounter" json:"mounter"`
 	Sink               *SinkConfig       `toml:"sink" json:"sink"`
 	Consistent         *ConsistentConfig `toml:"consistent" json:"consistent"`
+	Transform          *TransformConfig  `toml:"transform" json:"transform,omitempty"`
+	// InitialSnapshot makes a new changefeed replicate the existing data of
+	// the tables at start-ts as inserts before the incremental changes.
+	InitialSnapshot bool `toml:"initial-snapshot" json:"initial-snapshot,omitempty"`
+	// Placement restricts the captures that replicate the tables of
+	// the changefeed by their labels.
+	Placement *PlacementConfig `toml:"placement" json:"placement,omitempty"`
+	// Quota limits the resources used by the changefeed on each capture.
+	Quota *QuotaConfig `toml:"quota" json:"quota,omitempty"`
+	// DDLApproval holds the changefeed at the DDLs of the given types until
+	// an operator approves, skips or replaces them.
+	DDLApproval *DDLApprovalConfig `toml:"ddl-approval" json:"ddl-approval,omitempty"`
 }
 
 // Marshal returns the json marshal format of a ReplicationConfig
@@ -170,6 +182,26 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error {
 			return err
 		}
 	}
+	if c.Transform != nil {
+		if err := c.Transform.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+	if c.Placement != nil {
+		if err := c.Placement.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+	if c.Quota != nil {
+		if err := c.Quota.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
+	if c.DDLApproval != nil {
+		if err := c.DDLApproval.ValidateAndAdjust(); err != nil {
+			return err
+		}
+	}
 	// check sync point config
 	if c.EnableSyncPoint {
 		if c.SyncPointInterval < minSyncPointInterval {
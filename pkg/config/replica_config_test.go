@@ -130,6 +130,22 @@ func TestReplicaConfigValidate(t *testing.T) {
 	require.Equal(t, "d1", rules[0].PartitionRule)
 	require.Equal(t, "p1", rules[1].PartitionRule)
 	require.Equal(t, "", rules[2].PartitionRule)
+
+	conf = GetDefaultReplicaConfig()
+	conf.Sink.DispatchRules = []*DispatchRule{
+		{Matcher: []string{"a.b"}, PartitionRule: "columns"},
+	}
+	require.Regexp(t, ".*columns must be specified for the columns partition rule.*",
+		conf.ValidateAndAdjust(nil))
+	conf.Sink.DispatchRules = []*DispatchRule{
+		{Matcher: []string{"a.b"}, PartitionRule: "ts", Columns: []string{"a"}},
+	}
+	require.Regexp(t, ".*columns can only be specified for the columns partition rule.*",
+		conf.ValidateAndAdjust(nil))
+	conf.Sink.DispatchRules = []*DispatchRule{
+		{Matcher: []string{"a.b"}, DispatcherRule: "Columns", Columns: []string{"a"}},
+	}
+	require.Nil(t, conf.ValidateAndAdjust(nil))
 }
 
 func TestValidateAndAdjust(t *testing.T) {
@@ -36,10 +36,33 @@ type SchedulerConfig struct {
 	// oom caused by all tables dispatched to only one capture.
 	AddTableBatchSize int `toml:"add-table-batch-size" json:"add-table-batch-size"`
 	// RegionPerSpan the number of regions in a span, must be greater than 1000.
-	// Set 0 to disable span replication.
+	// Set 0 to disable span replication. Span replication requires the
+	// pull-based sink to be disabled.
 	RegionPerSpan int `toml:"region-per-span" json:"region-per-span"`
+	// WriteBytesPerSpan is the write throughput in bytes per second that a
+	// span is expected to handle. Spans written faster are split by region
+	// boundaries, and adjacent spans written much slower are merged.
+	// Spans are checked every CheckBalanceInterval.
+	// It requires span replication, set 0 to disable.
+	WriteBytesPerSpan uint64 `toml:"write-bytes-per-span" json:"write-bytes-per-span"`
+	// BalanceStrategy is the strategy of balancing tables between captures,
+	// either "table-count" or "traffic".
+	BalanceStrategy string `toml:"balance-strategy" json:"balance-strategy"`
+	// BalanceThreshold is the tolerance of the "traffic" strategy. Tables are
+	// moved only when the load of a capture deviates from the average load by
+	// more than the ratio, which prevents moving tables back and forth.
+	BalanceThreshold float64 `toml:"balance-threshold" json:"balance-threshold"`
 }
 
+const (
+	// BalanceStrategyTableCount balances tables by the number of tables.
+	BalanceStrategyTableCount = "table-count"
+	// BalanceStrategyTraffic balances tables by the load of tables, which is
+	// computed from the throughput, the region count and the sink lag
+	// reported by captures.
+	BalanceStrategyTraffic = "traffic"
+)
+
 // NewDefaultSchedulerConfig return the default scheduler configuration.
 func NewDefaultSchedulerConfig() *SchedulerConfig {
 	return &SchedulerConfig{
@@ -49,6 +72,9 @@ func NewDefaultSchedulerConfig() *SchedulerConfig {
 		CheckBalanceInterval: TomlDuration(time.Minute),
 		AddTableBatchSize:    50,
 		RegionPerSpan:        0,
+		WriteBytesPerSpan:    0,
+		BalanceStrategy:      BalanceStrategyTableCount,
+		BalanceThreshold:     0.2,
 	}
 }
 
@@ -74,6 +100,20 @@ func (c *SchedulerConfig) ValidateAndAdjust() error {
 		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
 			"region-per-span must be either 0 or greater than 1000")
 	}
+	if c.WriteBytesPerSpan != 0 && c.RegionPerSpan == 0 {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"write-bytes-per-span requires span replication, " +
+				"region-per-span must be greater than 1000")
+	}
+	if c.BalanceStrategy != BalanceStrategyTableCount &&
+		c.BalanceStrategy != BalanceStrategyTraffic {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"balance-strategy must be either table-count or traffic")
+	}
+	if c.BalanceThreshold <= 0 || c.BalanceThreshold >= 1 {
+		return cerror.ErrInvalidServerOption.GenWithStackByArgs(
+			"balance-threshold must be in the range (0, 1)")
+	}
 
 	return nil
 }
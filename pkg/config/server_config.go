@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/label"
 	"github.com/pingcap/tiflow/pkg/security"
 	"go.uber.org/zap"
 )
@@ -181,6 +182,17 @@ type ServerConfig struct {
 	KVClient            *KVClientConfig `toml:"kv-client" json:"kv-client"`
 	Debug               *DebugConfig    `toml:"debug" json:"debug"`
 	ClusterID           string          `toml:"cluster-id" json:"cluster-id"`
+	// Labels are the labels of the capture, which are used by the placement
+	// rules of changefeeds, e.g. {"zone": "us-west-1a"}.
+	Labels map[string]string `toml:"labels" json:"labels,omitempty"`
+	// MemoryQuota is the memory quota of the capture, 0 means no limit.
+	// The memory of the sorter, decided by `sorter.max-memory-percentage`,
+	// is reserved from it, and the rest is shared by the mounted events of
+	// all changefeeds until they are written to the sinks. Under memory
+	// pressure, changefeeds of "normal" priority can use at most 80% of the
+	// shared quota and changefeeds of "low" priority can use at most 50% of
+	// it, the rest is reserved for changefeeds of higher priorities.
+	MemoryQuota uint64 `toml:"memory-quota" json:"memory-quota,omitempty"`
 }
 
 // Marshal returns the json marshal format of a ServerConfig
@@ -201,9 +213,15 @@ func (c *ServerConfig) Unmarshal(data []byte) error {
 	return nil
 }
 
-// String implements the Stringer interface
+// String implements the Stringer interface. The secrets in the config are
+// redacted, since the config is logged.
 func (c *ServerConfig) String() string {
-	s, _ := c.Marshal()
+	cfg := c
+	if c.Security != nil && c.Security.ClientAuth != nil {
+		cfg = c.Clone()
+		cfg.Security.ClientAuth = c.Security.ClientAuth.Redact()
+	}
+	s, _ := cfg.Marshal()
 	return s
 }
 
@@ -234,6 +252,10 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 	if c.Addr == "" {
 		return cerror.ErrInvalidServerOption.GenWithStack("empty address")
 	}
+	if _, err := label.NewSetFromMap(c.Labels); err != nil {
+		return cerror.ErrInvalidServerOption.GenWithStack(
+			fmt.Sprintf("bad labels: %s", err.Error()))
+	}
 	if c.AdvertiseAddr == "" {
 		c.AdvertiseAddr = c.Addr
 	}
@@ -267,6 +289,11 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 			return errors.Annotate(err, "invalidate TLS config")
 		}
 	}
+	if c.Security != nil && c.Security.ClientAuth != nil {
+		if err := c.Security.ClientAuth.Validate(c.Security); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	defaultCfg := GetDefaultServerConfig()
 	if c.Sorter == nil {
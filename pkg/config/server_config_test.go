@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,6 +49,20 @@ func TestServerConfigClone(t *testing.T) {
 	require.Equal(t, uint64(99), conf.Sorter.ChunkSizeLimit)
 }
 
+func TestServerConfigStringRedactsTokens(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig()
+	conf.Security.ClientAuth = &security.ClientAuthConfig{
+		Enable: true,
+		Tokens: map[string]security.Role{"secret-token": security.RoleAdmin},
+	}
+	s := conf.String()
+	require.NotContains(t, s, "secret-token")
+	require.Contains(t, s, "token-secr****")
+	// the config itself is not modified.
+	require.Contains(t, conf.Security.ClientAuth.Tokens, "secret-token")
+}
+
 func TestServerConfigValidateAndAdjust(t *testing.T) {
 	t.Parallel()
 	conf := new(ServerConfig)
@@ -73,6 +88,10 @@ func TestServerConfigValidateAndAdjust(t *testing.T) {
 	conf.AdvertiseAddr = "advertise"
 	require.Regexp(t, ".*does not contain a port", conf.ValidateAndAdjust())
 	conf.AdvertiseAddr = "advertise:1234"
+	conf.Labels = map[string]string{"zone": "us west"}
+	require.Regexp(t, ".*bad labels.*", conf.ValidateAndAdjust())
+	conf.Labels = map[string]string{"zone": "us-west-1a"}
+	require.Nil(t, conf.ValidateAndAdjust())
 	conf.PerTableMemoryQuota = 1
 	require.Nil(t, conf.ValidateAndAdjust())
 	require.EqualValues(t, 1, conf.PerTableMemoryQuota)
@@ -140,6 +159,35 @@ func TestSchedulerConfigValidateAndAdjust(t *testing.T) {
 	require.Nil(t, conf.ValidateAndAdjust())
 	conf.RegionPerSpan = 999
 	require.Error(t, conf.ValidateAndAdjust())
+
+	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
+	conf.WriteBytesPerSpan = 1024
+	require.Error(t, conf.ValidateAndAdjust())
+	conf.RegionPerSpan = 1000
+	require.Nil(t, conf.ValidateAndAdjust())
+
+	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
+	conf.BalanceStrategy = BalanceStrategyTraffic
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.BalanceStrategy = "unknown"
+	require.Error(t, conf.ValidateAndAdjust())
+
+	conf = GetDefaultServerConfig().Clone().Debug.Scheduler
+	conf.BalanceThreshold = 0
+	require.Error(t, conf.ValidateAndAdjust())
+	conf.BalanceThreshold = 1
+	require.Error(t, conf.ValidateAndAdjust())
+}
+
+func TestDebugConfigSpanReplication(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().Debug
+	conf.Scheduler.RegionPerSpan = 1000
+	require.Regexp(t, ".*enable-pull-based-sink.*", conf.ValidateAndAdjust())
+	conf.Scheduler.WriteBytesPerSpan = 1024
+	require.Regexp(t, ".*enable-pull-based-sink.*", conf.ValidateAndAdjust())
+	conf.EnablePullBasedSink = false
+	require.Nil(t, conf.ValidateAndAdjust())
 }
 
 func TestIsValidClusterID(t *testing.T) {
@@ -66,6 +66,34 @@ const (
 	NULL = "\\N"
 )
 
+// PartitionRuleColumns is the partition rule which dispatches row changed
+// events by the values of the specified columns.
+const PartitionRuleColumns = "columns"
+
+// The message key formats of the MQ sinks.
+const (
+	// KeyFormatDefault keeps the message key of the protocol unchanged.
+	KeyFormatDefault = "default"
+	// KeyFormatPrimaryKey sets the message key to the handle key values
+	// of the row encoded as a JSON array.
+	KeyFormatPrimaryKey = "primary-key"
+	// KeyFormatColumns sets the message key to the values of the
+	// specified columns encoded as a JSON array.
+	KeyFormatColumns = "columns"
+	// KeyFormatTemplate sets the message key by substituting the
+	// placeholders in the template.
+	KeyFormatTemplate = "template"
+	// KeyFormatNone sets the message key to empty.
+	KeyFormatNone = "none"
+)
+
+// The placeholders supported by the key template.
+const (
+	KeyTemplateSchema     = "{schema}"
+	KeyTemplateTable      = "{table}"
+	KeyTemplatePrimaryKey = "{pk}"
+)
+
 // ShouldSplitTxn returns whether the sink should split txn.
 func (l AtomicityLevel) ShouldSplitTxn() bool {
 	return l == noneTxnAtomicity
@@ -90,6 +118,18 @@ type SinkConfig struct {
 	Terminator               string            `toml:"terminator" json:"terminator"`
 	DateSeparator            string            `toml:"date-separator" json:"date-separator"`
 	EnablePartitionSeparator bool              `toml:"enable-partition-separator" json:"enable-partition-separator"`
+	// DDLTopic is the topic expression of the DDL events. If it is set, the
+	// DDL events are sent to the dedicated topic instead of the topics of
+	// the tables. It is only used in the MQ sinks.
+	DDLTopic string `toml:"ddl-topic" json:"ddl-topic"`
+	// DeadLetterQueue is the destination of the row changed events which
+	// can not be written to the downstream. It is only used in the MQ sinks
+	// and the MySQL sink.
+	DeadLetterQueue *DeadLetterQueueConfig `toml:"dead-letter-queue" json:"dead-letter-queue,omitempty"`
+	// LargeMessageHandle is how to handle the row changed events whose
+	// messages are larger than max-message-bytes. It is only used in the
+	// MQ sinks.
+	LargeMessageHandle *LargeMessageHandleConfig `toml:"large-message-handle" json:"large-message-handle,omitempty"`
 	// TiDBSourceID is the source ID of the upstream TiDB,
 	// which is used to set the `tidb_cdc_write_source` session variable.
 	// Note: This field is only used internally and only used in the MySQL sink.
@@ -160,7 +200,29 @@ type DispatchRule struct {
 	// PartitionRule is an alias added for DispatcherRule to mitigate confusions.
 	// In the future release, the DispatcherRule is expected to be removed .
 	PartitionRule string `toml:"partition" json:"partition"`
-	TopicRule     string `toml:"topic" json:"topic"`
+	// Columns are the columns whose values are hashed to dispatch the row
+	// changed events, it's only used by the columns partition rule.
+	Columns []string `toml:"columns" json:"columns"`
+	// TopicRule is the topic expression of the row changed events. Note that
+	// with the {event-type} placeholder, the events of the same key are sent
+	// to different topics, so the consumers can not keep the order of them.
+	TopicRule string `toml:"topic" json:"topic"`
+	// KeyFormat is the format of the message keys of the row changed
+	// events, the non-default formats are only supported by the canal-json,
+	// maxwell and open protocols. Note that the open protocol keeps the
+	// commit ts, the table and the event type in the default message key,
+	// so its messages with the customized keys can't be decoded by TiCDC.
+	// The avro protocol is not supported, since its decoders identify the
+	// rows, especially the deleted ones, by the avro encoded key.
+	KeyFormat string `toml:"key-format" json:"key-format"`
+	// KeyColumns are the columns whose values make up the message key,
+	// it's only used by the columns key format.
+	KeyColumns []string `toml:"key-columns" json:"key-columns"`
+	// KeyTemplate is the template of the message key, it's only used by
+	// the template key format. The placeholders {schema}, {table} and
+	// {pk} are substituted by the schema name, the table name and the
+	// comma separated handle key values.
+	KeyTemplate string `toml:"key-template" json:"key-template"`
 }
 
 // ColumnSelector represents a column selector for a table.
@@ -198,6 +260,18 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) er
 			rule.PartitionRule = rule.DispatcherRule
 			rule.DispatcherRule = ""
 		}
+		isColumnsRule := strings.EqualFold(rule.PartitionRule, PartitionRuleColumns)
+		if isColumnsRule && len(rule.Columns) == 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"columns must be specified for the columns partition rule: %v", rule)
+		}
+		if !isColumnsRule && len(rule.Columns) != 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"columns can only be specified for the columns partition rule: %v", rule)
+		}
+		if err := s.validateKeyFormat(rule); err != nil {
+			return err
+		}
 	}
 
 	if s.EncoderConcurrency < 0 {
@@ -218,6 +292,31 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) er
 		}
 	}
 
+	if s.DeadLetterQueue != nil {
+		if err := s.DeadLetterQueue.validate(sinkURI); err != nil {
+			return err
+		}
+	}
+
+	if s.LargeMessageHandle != nil {
+		if err := s.LargeMessageHandle.validate(sinkURI, s.Protocol); err != nil {
+			return err
+		}
+		// the open protocol consumer locates the large message by the
+		// message key, which would be replaced by the customized key.
+		p, _ := ParseSinkProtocolFromString(s.Protocol)
+		if s.LargeMessageHandle.EnableClaimCheck() && p == ProtocolOpen {
+			for _, rule := range s.DispatchRules {
+				format := strings.ToLower(rule.KeyFormat)
+				if format != "" && format != KeyFormatDefault {
+					return cerror.ErrSinkInvalidConfig.GenWithStack(
+						"claim-check can not be used together with key-format %s: %v",
+						rule.KeyFormat, rule)
+				}
+			}
+		}
+	}
+
 	if s.CSVConfig != nil {
 		return s.validateAndAdjustCSVConfig()
 	}
@@ -225,6 +324,151 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) er
 	return nil
 }
 
+func (s *SinkConfig) validateKeyFormat(rule *DispatchRule) error {
+	format := strings.ToLower(rule.KeyFormat)
+	switch format {
+	case "", KeyFormatDefault:
+	case KeyFormatPrimaryKey, KeyFormatColumns, KeyFormatTemplate, KeyFormatNone:
+		protocol, _ := ParseSinkProtocolFromString(s.Protocol)
+		if protocol != ProtocolOpen && protocol != ProtocolCanalJSON &&
+			protocol != ProtocolMaxwell {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"key-format %s is not supported by the %s protocol", rule.KeyFormat, s.Protocol)
+		}
+		if protocol == ProtocolOpen {
+			log.Warn("the open protocol messages with the customized keys "+
+				"can't be decoded by TiCDC, since the default key carries "+
+				"the commit ts, the table and the event type of the rows",
+				zap.String("keyFormat", rule.KeyFormat), zap.Strings("matcher", rule.Matcher))
+		}
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"unknown key-format %s, it should be one of %s, %s, %s, %s and %s",
+			rule.KeyFormat, KeyFormatDefault, KeyFormatPrimaryKey,
+			KeyFormatColumns, KeyFormatTemplate, KeyFormatNone)
+	}
+
+	isColumnsFormat := format == KeyFormatColumns
+	if isColumnsFormat && len(rule.KeyColumns) == 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"key-columns must be specified for the columns key format: %v", rule)
+	}
+	if !isColumnsFormat && len(rule.KeyColumns) != 0 {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"key-columns can only be specified for the columns key format: %v", rule)
+	}
+
+	isTemplateFormat := format == KeyFormatTemplate
+	if isTemplateFormat && rule.KeyTemplate == "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"key-template must be specified for the template key format: %v", rule)
+	}
+	if !isTemplateFormat && rule.KeyTemplate != "" {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"key-template can only be specified for the template key format: %v", rule)
+	}
+	return nil
+}
+
+// DeadLetterQueueConfig represents the dead letter queue config of a changefeed.
+// The row changed events which can not be written to the downstream, such as
+// the too large messages of the MQ sinks and the rows violating the constraints
+// of the MySQL sink, are recorded in the dead letter queue with the errors,
+// instead of failing the changefeed. The encoded messages which are rejected
+// by Kafka for their content, such as the messages larger than the limit of
+// the broker, are recorded too.
+type DeadLetterQueueConfig struct {
+	// URI is the destination of the dead letter queue, it can be a kafka
+	// topic, e.g. kafka://127.0.0.1:9092/dlq-topic, or an external storage,
+	// e.g. file:///tmp/dlq or s3://bucket/prefix.
+	URI string `toml:"uri" json:"uri"`
+}
+
+func (c *DeadLetterQueueConfig) validate(sinkURI *url.URL) error {
+	if sinkURI != nil && !sink.IsMQScheme(sinkURI.Scheme) &&
+		!sink.IsMySQLCompatibleScheme(sinkURI.Scheme) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"dead letter queue is not supported by the %s sink", sinkURI.Scheme)
+	}
+	uri, err := url.Parse(c.URI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	scheme := strings.ToLower(uri.Scheme)
+	if !sink.IsKafkaScheme(scheme) && !sink.IsStorageScheme(scheme) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"the scheme of dead letter queue uri %s is not supported, "+
+				"it should be kafka or an external storage", c.URI)
+	}
+	return nil
+}
+
+const (
+	// LargeMessageHandleOptionNone fails the changefeed if a message is
+	// larger than max-message-bytes.
+	LargeMessageHandleOptionNone = "none"
+	// LargeMessageHandleOptionClaimCheck writes the large messages into an
+	// external storage, and sends the messages which only carry the
+	// locations of them and the handle key columns of the rows.
+	LargeMessageHandleOptionClaimCheck = "claim-check"
+)
+
+// LargeMessageHandleConfig represents how the MQ sinks handle the row changed
+// events whose messages are larger than max-message-bytes. The claim-check
+// mode is only supported by the open protocol and the canal-json protocol.
+// The open protocol can't use it together with the customized message keys
+// of the dispatchers, and the canal-json protocol requires the
+// enable-tidb-extension parameter to carry the claim-check location.
+type LargeMessageHandleConfig struct {
+	LargeMessageHandleOption string `toml:"large-message-handle-option" json:"large-message-handle-option"`
+	// ClaimCheckStorageURI is the external storage where the large messages
+	// are stored in the claim-check mode, e.g. file:///tmp/claim-check or
+	// s3://bucket/prefix.
+	ClaimCheckStorageURI string `toml:"claim-check-storage-uri" json:"claim-check-storage-uri"`
+}
+
+// EnableClaimCheck returns true if the claim-check mode is enabled.
+func (c *LargeMessageHandleConfig) EnableClaimCheck() bool {
+	if c == nil {
+		return false
+	}
+	return strings.EqualFold(c.LargeMessageHandleOption, LargeMessageHandleOptionClaimCheck)
+}
+
+func (c *LargeMessageHandleConfig) validate(sinkURI *url.URL, protocol string) error {
+	switch strings.ToLower(c.LargeMessageHandleOption) {
+	case "", LargeMessageHandleOptionNone:
+		return nil
+	case LargeMessageHandleOptionClaimCheck:
+	default:
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"unknown large-message-handle-option %s, it should be %s or %s",
+			c.LargeMessageHandleOption, LargeMessageHandleOptionNone,
+			LargeMessageHandleOptionClaimCheck)
+	}
+
+	if sinkURI != nil && !sink.IsMQScheme(sinkURI.Scheme) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"claim-check is not supported by the %s sink", sinkURI.Scheme)
+	}
+	// only the open and canal-json protocols know how to refer to the
+	// rows in the claim-check storage.
+	if p, _ := ParseSinkProtocolFromString(protocol); p != ProtocolOpen && p != ProtocolCanalJSON {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"claim-check is only supported by the open and canal-json protocols, "+
+				"not the %s protocol", protocol)
+	}
+	uri, err := url.Parse(c.ClaimCheckStorageURI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkInvalidConfig, err)
+	}
+	if !sink.IsStorageScheme(strings.ToLower(uri.Scheme)) {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"claim-check-storage-uri %s should be an external storage", c.ClaimCheckStorageURI)
+	}
+	return nil
+}
+
 func (s *SinkConfig) validateAndAdjustCSVConfig() error {
 	// validate quote
 	if len(s.CSVConfig.Quote) > 1 {
@@ -305,7 +549,8 @@ func (s *SinkConfig) applyParameter(sinkURI *url.URL) error {
 	}
 
 	// validate that protocol is compatible with the scheme
-	if sink.IsMQScheme(sinkURI.Scheme) || sink.IsStorageScheme(sinkURI.Scheme) {
+	if sink.IsMQScheme(sinkURI.Scheme) || sink.IsStorageScheme(sinkURI.Scheme) ||
+		sink.IsHTTPScheme(sinkURI.Scheme) {
 		_, err := ParseSinkProtocolFromString(s.Protocol)
 		if err != nil {
 			return err
@@ -263,3 +263,253 @@ func TestValidateAndAdjustCSVConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateKeyFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		protocol string
+		rule     *DispatchRule
+		wantErr  string
+	}{
+		{
+			name:     "default key format",
+			protocol: "avro",
+			rule:     &DispatchRule{KeyFormat: "Default"},
+		},
+		{
+			name:     "primary key format",
+			protocol: "canal-json",
+			rule:     &DispatchRule{KeyFormat: "primary-key"},
+		},
+		{
+			name:     "unsupported protocol",
+			protocol: "canal",
+			rule:     &DispatchRule{KeyFormat: "none"},
+			wantErr:  ".*key-format none is not supported by the canal protocol.*",
+		},
+		{
+			name:     "unknown key format",
+			protocol: "maxwell",
+			rule:     &DispatchRule{KeyFormat: "pk"},
+			wantErr:  ".*unknown key-format pk.*",
+		},
+		{
+			name:     "columns key format",
+			protocol: "open-protocol",
+			rule:     &DispatchRule{KeyFormat: "columns", KeyColumns: []string{"a"}},
+		},
+		{
+			name:     "columns key format without columns",
+			protocol: "open-protocol",
+			rule:     &DispatchRule{KeyFormat: "columns"},
+			wantErr:  ".*key-columns must be specified for the columns key format.*",
+		},
+		{
+			name:     "key columns without columns key format",
+			protocol: "default",
+			rule:     &DispatchRule{KeyColumns: []string{"a"}},
+			wantErr:  ".*key-columns can only be specified for the columns key format.*",
+		},
+		{
+			name:     "avro protocol",
+			protocol: "avro",
+			rule:     &DispatchRule{KeyFormat: "primary-key"},
+			wantErr:  ".*key-format primary-key is not supported by the avro protocol.*",
+		},
+		{
+			name:     "template key format",
+			protocol: "canal-json",
+			rule:     &DispatchRule{KeyFormat: "template", KeyTemplate: "{schema}.{table}:{pk}"},
+		},
+		{
+			name:     "template key format without template",
+			protocol: "maxwell",
+			rule:     &DispatchRule{KeyFormat: "template"},
+			wantErr:  ".*key-template must be specified for the template key format.*",
+		},
+		{
+			name:     "key template without template key format",
+			protocol: "maxwell",
+			rule:     &DispatchRule{KeyFormat: "none", KeyTemplate: "{pk}"},
+			wantErr:  ".*key-template can only be specified for the template key format.*",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &SinkConfig{Protocol: tc.protocol}
+			if tc.wantErr == "" {
+				require.Nil(t, s.validateKeyFormat(tc.rule))
+			} else {
+				require.Regexp(t, tc.wantErr, s.validateKeyFormat(tc.rule))
+			}
+		})
+	}
+}
+
+func TestValidateDeadLetterQueue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sinkURI string
+		dlqURI  string
+		wantErr string
+	}{
+		{
+			name:    "kafka dead letter queue for mysql sink",
+			sinkURI: "mysql://127.0.0.1:3306/",
+			dlqURI:  "kafka://127.0.0.1:9092/dlq",
+		},
+		{
+			name:    "storage dead letter queue for kafka sink",
+			sinkURI: "kafka://127.0.0.1:9092/test?protocol=open-protocol",
+			dlqURI:  "s3://bucket/prefix",
+		},
+		{
+			name:    "unsupported sink",
+			sinkURI: "blackhole://",
+			dlqURI:  "file:///tmp/dlq",
+			wantErr: ".*dead letter queue is not supported by the blackhole sink.*",
+		},
+		{
+			name:    "unsupported dead letter queue",
+			sinkURI: "mysql://127.0.0.1:3306/",
+			dlqURI:  "mysql://127.0.0.1:3306/",
+			wantErr: ".*the scheme of dead letter queue uri .* is not supported.*",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			sinkURI, err := url.Parse(tc.sinkURI)
+			require.Nil(t, err)
+			c := &DeadLetterQueueConfig{URI: tc.dlqURI}
+			if tc.wantErr == "" {
+				require.Nil(t, c.validate(sinkURI))
+			} else {
+				require.Regexp(t, tc.wantErr, c.validate(sinkURI))
+			}
+		})
+	}
+}
+
+func TestValidateLargeMessageHandle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		sinkURI  string
+		protocol string
+		config   *LargeMessageHandleConfig
+		wantErr  string
+	}{
+		{
+			name:     "none",
+			sinkURI:  "kafka://127.0.0.1:9092/test",
+			protocol: "canal-json",
+			config:   &LargeMessageHandleConfig{LargeMessageHandleOption: "none"},
+		},
+		{
+			name:     "claim-check",
+			sinkURI:  "kafka://127.0.0.1:9092/test",
+			protocol: "open-protocol",
+			config: &LargeMessageHandleConfig{
+				LargeMessageHandleOption: "claim-check",
+				ClaimCheckStorageURI:     "s3://bucket/prefix",
+			},
+		},
+		{
+			name:     "unknown option",
+			sinkURI:  "kafka://127.0.0.1:9092/test",
+			protocol: "open-protocol",
+			config:   &LargeMessageHandleConfig{LargeMessageHandleOption: "compress"},
+			wantErr:  ".*unknown large-message-handle-option compress.*",
+		},
+		{
+			name:     "unsupported sink",
+			sinkURI:  "mysql://127.0.0.1:3306/",
+			protocol: "",
+			config: &LargeMessageHandleConfig{
+				LargeMessageHandleOption: "claim-check",
+				ClaimCheckStorageURI:     "file:///tmp/claim-check",
+			},
+			wantErr: ".*claim-check is not supported by the mysql sink.*",
+		},
+		{
+			name:     "claim-check with canal-json",
+			sinkURI:  "kafka://127.0.0.1:9092/test",
+			protocol: "canal-json",
+			config: &LargeMessageHandleConfig{
+				LargeMessageHandleOption: "claim-check",
+				ClaimCheckStorageURI:     "file:///tmp/claim-check",
+			},
+		},
+		{
+			name:     "unsupported protocol",
+			sinkURI:  "kafka://127.0.0.1:9092/test",
+			protocol: "avro",
+			config: &LargeMessageHandleConfig{
+				LargeMessageHandleOption: "claim-check",
+				ClaimCheckStorageURI:     "file:///tmp/claim-check",
+			},
+			wantErr: ".*claim-check is only supported by the open and canal-json protocols, not the avro protocol.*",
+		},
+		{
+			name:     "invalid storage",
+			sinkURI:  "kafka://127.0.0.1:9092/test",
+			protocol: "open-protocol",
+			config: &LargeMessageHandleConfig{
+				LargeMessageHandleOption: "claim-check",
+				ClaimCheckStorageURI:     "kafka://127.0.0.1:9092/claim-check",
+			},
+			wantErr: ".*claim-check-storage-uri .* should be an external storage.*",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			sinkURI, err := url.Parse(tc.sinkURI)
+			require.Nil(t, err)
+			err = tc.config.validate(sinkURI, tc.protocol)
+			if tc.wantErr == "" {
+				require.Nil(t, err)
+			} else {
+				require.Regexp(t, tc.wantErr, err)
+			}
+		})
+	}
+	require.False(t, (*LargeMessageHandleConfig)(nil).EnableClaimCheck())
+}
+
+func TestValidateClaimCheckWithKeyFormat(t *testing.T) {
+	t.Parallel()
+
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/test")
+	require.Nil(t, err)
+	cfg := &SinkConfig{
+		Protocol: "open-protocol",
+		DispatchRules: []*DispatchRule{
+			{Matcher: []string{"test.*"}, KeyFormat: KeyFormatDefault},
+		},
+		LargeMessageHandle: &LargeMessageHandleConfig{
+			LargeMessageHandleOption: LargeMessageHandleOptionClaimCheck,
+			ClaimCheckStorageURI:     "file:///tmp/claim-check",
+		},
+	}
+	require.Nil(t, cfg.validateAndAdjust(sinkURI, true))
+
+	// the customized key would replace the key carrying the claim-check file.
+	cfg.DispatchRules[0].KeyFormat = KeyFormatPrimaryKey
+	require.Regexp(t, ".*claim-check can not be used together with key-format primary-key.*",
+		cfg.validateAndAdjust(sinkURI, true))
+
+	// canal-json carries the claim-check location in the message value.
+	cfg.Protocol = "canal-json"
+	require.Nil(t, cfg.validateAndAdjust(sinkURI, true))
+
+	cfg.Protocol = "open-protocol"
+	cfg.LargeMessageHandle.LargeMessageHandleOption = LargeMessageHandleOptionNone
+	require.Nil(t, cfg.validateAndAdjust(sinkURI, true))
+}
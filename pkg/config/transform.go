@@ -0,0 +1,118 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// TransformConfig represents the row-level transformation config of a
+// changefeed. The transformations are applied to the row changed events
+// before they are sent to the sinks.
+type TransformConfig struct {
+	Rules []*TransformRule `toml:"rules" json:"rules"`
+}
+
+// TransformRule is a set of transformations applied to the row changed events
+// of the matched tables. All the rules matching a table are applied in order,
+// and the transformations of a rule are applied in the order of computed
+// columns, masked and hashed columns, renamed columns and constant columns.
+type TransformRule struct {
+	Matcher []string `toml:"matcher" json:"matcher"`
+	// RenameColumns maps the column names to the new names.
+	RenameColumns map[string]string `toml:"rename-columns" json:"rename-columns,omitempty"`
+	// MaskColumns are the columns whose values are masked. The local part
+	// of an email address is masked except its first character, and the
+	// other values are masked except their last 4 characters.
+	MaskColumns []string `toml:"mask-columns" json:"mask-columns,omitempty"`
+	// HashColumns are the columns whose values are replaced by their
+	// SHA-256 hex digests.
+	HashColumns []string `toml:"hash-columns" json:"hash-columns,omitempty"`
+	// ComputedColumns are the columns appended to the row, whose values are
+	// calculated by TiDB expressions on the original columns of the row.
+	ComputedColumns []*ComputedColumn `toml:"computed-columns" json:"computed-columns,omitempty"`
+	// ConstantColumns maps the names of the columns appended to the row
+	// to their constant string values.
+	ConstantColumns map[string]string `toml:"constant-columns" json:"constant-columns,omitempty"`
+}
+
+// ComputedColumn is a column calculated by a TiDB expression.
+type ComputedColumn struct {
+	Name       string `toml:"name" json:"name"`
+	Expression string `toml:"expression" json:"expression"`
+}
+
+// ValidateAndAdjust validates the transform config.
+func (c *TransformConfig) ValidateAndAdjust() error {
+	for _, rule := range c.Rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TransformRule) validate() error {
+	if _, err := filter.Parse(r.Matcher); err != nil {
+		return cerror.WrapError(cerror.ErrFilterRuleInvalid, err, r.Matcher)
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return cerror.ErrTransformRuleInvalid.GenWithStackByArgs(
+			r.Matcher, fmt.Sprintf(format, args...))
+	}
+
+	for from, to := range r.RenameColumns {
+		if from == "" || to == "" {
+			return invalid("empty column name in rename-columns")
+		}
+	}
+	masked := make(map[string]struct{}, len(r.MaskColumns))
+	for _, name := range r.MaskColumns {
+		if name == "" {
+			return invalid("empty column name in mask-columns")
+		}
+		masked[name] = struct{}{}
+	}
+	for _, name := range r.HashColumns {
+		if name == "" {
+			return invalid("empty column name in hash-columns")
+		}
+		if _, ok := masked[name]; ok {
+			return invalid("column %s can not be both masked and hashed", name)
+		}
+	}
+
+	appended := make(map[string]struct{})
+	for _, col := range r.ComputedColumns {
+		if col == nil || col.Name == "" || col.Expression == "" {
+			return invalid("the name and expression of computed columns must be set")
+		}
+		if _, ok := appended[col.Name]; ok {
+			return invalid("duplicated computed column %s", col.Name)
+		}
+		appended[col.Name] = struct{}{}
+	}
+	for name := range r.ConstantColumns {
+		if name == "" {
+			return invalid("empty column name in constant-columns")
+		}
+		if _, ok := appended[name]; ok {
+			return invalid("column %s is both a computed column and a constant column", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTransformConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    *TransformRule
+		wantErr string
+	}{
+		{
+			name: "valid rule",
+			rule: &TransformRule{
+				Matcher:         []string{"test.*"},
+				RenameColumns:   map[string]string{"a": "b"},
+				MaskColumns:     []string{"email"},
+				HashColumns:     []string{"phone"},
+				ComputedColumns: []*ComputedColumn{{Name: "c", Expression: "a + 1"}},
+				ConstantColumns: map[string]string{"source": "tidb"},
+			},
+		},
+		{
+			name:    "invalid matcher",
+			rule:    &TransformRule{Matcher: []string{"test.*", "!"}},
+			wantErr: ".*ErrFilterRuleInvalid.*",
+		},
+		{
+			name: "empty renamed column",
+			rule: &TransformRule{
+				Matcher:       []string{"test.*"},
+				RenameColumns: map[string]string{"a": ""},
+			},
+			wantErr: ".*empty column name in rename-columns.*",
+		},
+		{
+			name: "masked and hashed column",
+			rule: &TransformRule{
+				Matcher:     []string{"test.*"},
+				MaskColumns: []string{"email"},
+				HashColumns: []string{"email"},
+			},
+			wantErr: ".*column email can not be both masked and hashed.*",
+		},
+		{
+			name: "computed column without expression",
+			rule: &TransformRule{
+				Matcher:         []string{"test.*"},
+				ComputedColumns: []*ComputedColumn{{Name: "c"}},
+			},
+			wantErr: ".*the name and expression of computed columns must be set.*",
+		},
+		{
+			name: "computed column and constant column",
+			rule: &TransformRule{
+				Matcher:         []string{"test.*"},
+				ComputedColumns: []*ComputedColumn{{Name: "c", Expression: "1"}},
+				ConstantColumns: map[string]string{"c": "tidb"},
+			},
+			wantErr: ".*column c is both a computed column and a constant column.*",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &TransformConfig{Rules: []*TransformRule{tc.rule}}
+			if tc.wantErr == "" {
+				require.Nil(t, cfg.ValidateAndAdjust())
+			} else {
+				require.Regexp(t, tc.wantErr, cfg.ValidateAndAdjust())
+			}
+		})
+	}
+}
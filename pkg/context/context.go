@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/factory"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/db/system"
@@ -44,6 +45,10 @@ type GlobalVars struct {
 	SorterSystem      *ssystem.System
 	SortEngineFactory *factory.SortEngineFactory
 
+	// CaptureMemQuota is the memory quota shared by all changefeeds on the
+	// capture, nil means it is not limited.
+	CaptureMemQuota *memquota.CaptureMemQuota
+
 	// OwnerRevision is the Etcd revision when the owner got elected.
 	OwnerRevision int64
 
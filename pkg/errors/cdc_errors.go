@@ -271,6 +271,34 @@ var (
 	ErrKafkaTopicNotExists = errors.Normalize("kafka topic not exists after creation",
 		errors.RFCCodeText("CDC:ErrKafkaTopicNotExists"),
 	)
+	ErrPulsarInvalidConfig = errors.Normalize(
+		"pulsar config invalid",
+		errors.RFCCodeText("CDC:ErrPulsarInvalidConfig"),
+	)
+	ErrPulsarNewClient = errors.Normalize(
+		"new pulsar client",
+		errors.RFCCodeText("CDC:ErrPulsarNewClient"),
+	)
+	ErrPulsarNewProducer = errors.Normalize(
+		"new pulsar producer",
+		errors.RFCCodeText("CDC:ErrPulsarNewProducer"),
+	)
+	ErrPulsarProducerClosed = errors.Normalize(
+		"pulsar producer closed",
+		errors.RFCCodeText("CDC:ErrPulsarProducerClosed"),
+	)
+	ErrPulsarSendMessage = errors.Normalize(
+		"pulsar send message failed",
+		errors.RFCCodeText("CDC:ErrPulsarSendMessage"),
+	)
+	ErrPulsarAsyncSendMessage = errors.Normalize(
+		"pulsar async send message failed",
+		errors.RFCCodeText("CDC:ErrPulsarAsyncSendMessage"),
+	)
+	ErrPulsarTopicPartitions = errors.Normalize(
+		"pulsar get topic partitions failed",
+		errors.RFCCodeText("CDC:ErrPulsarTopicPartitions"),
+	)
 	ErrRedoConfigInvalid = errors.Normalize(
 		"redo log config invalid",
 		errors.RFCCodeText("CDC:ErrRedoConfigInvalid"),
@@ -300,11 +328,11 @@ var (
 		errors.RFCCodeText("CDC:ErrFileSizeExceed"),
 	)
 	ErrS3StorageAPI = errors.Normalize(
-		"s3 storage api",
+		"external storage api",
 		errors.RFCCodeText("CDC:ErrS3StorageAPI"),
 	)
 	ErrS3StorageInitialize = errors.Normalize(
-		"new s3 storage for redo log",
+		"new external storage for redo log",
 		errors.RFCCodeText("CDC:ErrS3StorageInitialize"),
 	)
 	ErrCodecInvalidConfig = errors.Normalize(
@@ -367,6 +395,10 @@ var (
 		"schema manager API error",
 		errors.RFCCodeText("CDC:ErrAvroSchemaAPIError"),
 	)
+	ErrAvroDecodeFailed = errors.Normalize(
+		"decode avro message failed",
+		errors.RFCCodeText("CDC:ErrAvroDecodeFailed"),
+	)
 	ErrMaxwellEncodeFailed = errors.Normalize(
 		"maxwell encode failed",
 		errors.RFCCodeText("CDC:ErrMaxwellEncodeFailed"),
@@ -403,6 +435,10 @@ var (
 		"sink config invalid",
 		errors.RFCCodeText("CDC:ErrSinkInvalidConfig"),
 	)
+	ErrDispatcherFailed = errors.Normalize(
+		"dispatcher error",
+		errors.RFCCodeText("CDC:ErrDispatcherFailed"),
+	)
 	ErrCraftCodecInvalidData = errors.Normalize(
 		"craft codec invalid data",
 		errors.RFCCodeText("CDC:ErrCraftCodecInvalidData"),
@@ -419,6 +455,14 @@ var (
 		"csv decode failed",
 		errors.RFCCodeText("CDC:ErrCSVDecodeFailed"),
 	)
+	ErrParquetEncodeFailed = errors.Normalize(
+		"parquet encode failed",
+		errors.RFCCodeText("CDC:ErrParquetEncodeFailed"),
+	)
+	ErrParquetDecodeFailed = errors.Normalize(
+		"parquet decode failed",
+		errors.RFCCodeText("CDC:ErrParquetDecodeFailed"),
+	)
 	ErrCloudStorageInvalidConfig = errors.Normalize(
 		"cloud storage config invalid",
 		errors.RFCCodeText("CDC:ErrCloudStorageInvalidConfig"),
@@ -427,6 +471,63 @@ var (
 		"cloud storage defragment encoded messages failed",
 		errors.RFCCodeText("CDC:ErrCloudStorageDefragmentFailed"),
 	)
+	ErrCompressionFailed = errors.Normalize(
+		"compress data with %s failed",
+		errors.RFCCodeText("CDC:ErrCompressionFailed"),
+	)
+	ErrDecompressionFailed = errors.Normalize(
+		"decompress data with %s failed",
+		errors.RFCCodeText("CDC:ErrDecompressionFailed"),
+	)
+	ErrDeadLetterQueueInitFailed = errors.Normalize(
+		"failed to initialize the dead letter queue %s",
+		errors.RFCCodeText("CDC:ErrDeadLetterQueueInitFailed"),
+	)
+	ErrDeadLetterQueueWriteFailed = errors.Normalize(
+		"failed to write events to the dead letter queue",
+		errors.RFCCodeText("CDC:ErrDeadLetterQueueWriteFailed"),
+	)
+	ErrClaimCheckInitFailed = errors.Normalize(
+		"failed to initialize the claim-check storage %s",
+		errors.RFCCodeText("CDC:ErrClaimCheckInitFailed"),
+	)
+	ErrClaimCheckWriteFailed = errors.Normalize(
+		"failed to write the large message to the claim-check storage",
+		errors.RFCCodeText("CDC:ErrClaimCheckWriteFailed"),
+	)
+	ErrClaimCheckReadFailed = errors.Normalize(
+		"failed to read the large message %s from the claim-check storage",
+		errors.RFCCodeText("CDC:ErrClaimCheckReadFailed"),
+	)
+	ErrWebhookInvalidConfig = errors.Normalize(
+		"webhook sink config invalid",
+		errors.RFCCodeText("CDC:ErrWebhookInvalidConfig"),
+	)
+	ErrWebhookRequestFailed = errors.Normalize(
+		"webhook request failed: %s",
+		errors.RFCCodeText("CDC:ErrWebhookRequestFailed"),
+	)
+	ErrWebhookRequestRejected = errors.Normalize(
+		"webhook request is rejected: %s",
+		errors.RFCCodeText("CDC:ErrWebhookRequestRejected"),
+	)
+	ErrElasticsearchInvalidConfig = errors.Normalize(
+		"elasticsearch sink config invalid",
+		errors.RFCCodeText("CDC:ErrElasticsearchInvalidConfig"),
+	)
+	ErrElasticsearchRequestFailed = errors.Normalize(
+		"elasticsearch request failed: %s",
+		errors.RFCCodeText("CDC:ErrElasticsearchRequestFailed"),
+	)
+	ErrElasticsearchRequestRejected = errors.Normalize(
+		"elasticsearch request is rejected: %s",
+		errors.RFCCodeText("CDC:ErrElasticsearchRequestRejected"),
+	)
+	ErrElasticsearchDocumentIDNotFound = errors.Normalize(
+		"the document id of the rows in table %s is not found, "+
+			"the table must have a primary key or a not null unique key",
+		errors.RFCCodeText("CDC:ErrElasticsearchDocumentIDNotFound"),
+	)
 
 	// utilities related errors
 	ErrToTLSConfigFailed = errors.Normalize(
@@ -1097,6 +1198,18 @@ var (
 		"cdc server is not ready",
 		errors.RFCCodeText("CDC:ErrServerIsNotReady"),
 	)
+	ErrUnauthenticated = errors.Normalize(
+		"the client is not authenticated: %s",
+		errors.RFCCodeText("CDC:ErrUnauthenticated"),
+	)
+	ErrUnauthorized = errors.Normalize(
+		"the client %s with role %s is not allowed to access %s %s",
+		errors.RFCCodeText("CDC:ErrUnauthorized"),
+	)
+	ErrInvalidClientAuthConfig = errors.Normalize(
+		"invalid client auth config: %s",
+		errors.RFCCodeText("CDC:ErrInvalidClientAuthConfig"),
+	)
 
 	// cli error
 	ErrCliInvalidCheckpointTs = errors.Normalize(
@@ -1144,6 +1257,16 @@ var (
 		errors.RFCCodeText("CDC:ErrSyncRenameTableFailed"),
 	)
 
+	// Transform error
+	ErrTransformRuleInvalid = errors.Normalize(
+		"transform rule %v is invalid: %s",
+		errors.RFCCodeText("CDC:ErrTransformRuleInvalid"),
+	)
+	ErrFailedToTransformDML = errors.Normalize(
+		"failed to transform dml event: %v",
+		errors.RFCCodeText("CDC:ErrFailedToTransformDML"),
+	)
+
 	// changefeed config error
 	ErrInvalidReplicaConfig = errors.Normalize(
 		"invalid replica config, %s",
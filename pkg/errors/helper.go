@@ -75,6 +75,7 @@ var changefeedUnRetryableErrors = []*errors.Error{
 	ErrExpressionParseFailed,
 	ErrSchemaSnapshotNotFound,
 	ErrSyncRenameTableFailed,
+	ErrTransformRuleInvalid,
 	ErrChangefeedUnretryable,
 }
 
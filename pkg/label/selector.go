@@ -39,12 +39,12 @@ const (
 // Selector is one selector on a label Set.
 type Selector struct {
 	// Key is the key of the label that could potentially match the selector.
-	Key Key `json:"label"`
+	Key Key `toml:"label" json:"label"`
 	// Target is the argument to the operator. In the case of the
 	// Eq operator, it is the exact string that needs to match the value.
-	Target string `json:"target"`
+	Target string `toml:"target" json:"target"`
 	// Op is the operator.
-	Op Op `json:"op"`
+	Op Op `toml:"op" json:"op"`
 
 	// regex stores a compiled Regular Expression.
 	// It is not nil only if Op == OpRegex.
@@ -0,0 +1,182 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"encoding/base64"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// Role is the role of an authenticated HTTP API client,
+// it decides which APIs the client can access.
+type Role string
+
+const (
+	// RoleViewer can only access the read-only APIs.
+	RoleViewer Role = "viewer"
+	// RoleOperator can access the read-only and write APIs,
+	// such as creating, pausing and removing changefeeds.
+	RoleOperator Role = "operator"
+	// RoleAdmin can access all the APIs, including the unsafe APIs.
+	RoleAdmin Role = "admin"
+)
+
+// Permission is the permission required to access an API.
+type Permission int
+
+const (
+	// PermissionRead is required by the read-only APIs.
+	PermissionRead Permission = iota + 1
+	// PermissionWrite is required by the APIs which modify changefeeds.
+	PermissionWrite
+	// PermissionAdmin is required by the unsafe and cluster management APIs.
+	PermissionAdmin
+)
+
+// String implements fmt.Stringer interface.
+func (p Permission) String() string {
+	switch p {
+	case PermissionRead:
+		return "read"
+	case PermissionWrite:
+		return "write"
+	case PermissionAdmin:
+		return "admin"
+	}
+	return "unknown"
+}
+
+// IsValid checks whether the role is a known role.
+func (r Role) IsValid() bool {
+	return r == RoleViewer || r == RoleOperator || r == RoleAdmin
+}
+
+// Covers checks whether the role has all the permissions of the other role.
+func (r Role) Covers(other Role) bool {
+	for _, p := range []Permission{PermissionRead, PermissionWrite, PermissionAdmin} {
+		if other.Allows(p) && !r.Allows(p) {
+			return false
+		}
+	}
+	return other.IsValid()
+}
+
+// Allows checks whether the role has the permission.
+func (r Role) Allows(p Permission) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleOperator:
+		return p == PermissionRead || p == PermissionWrite
+	case RoleViewer:
+		return p == PermissionRead
+	}
+	return false
+}
+
+// ClientAuthConfig is the authentication and authorization config of
+// the HTTP API clients of a TiCDC server.
+type ClientAuthConfig struct {
+	// Enable indicates whether the HTTP API clients must be authenticated.
+	Enable bool `toml:"enable" json:"enable"`
+	// Users maps the upstream TiDB users to their roles. The clients
+	// authenticate with HTTP basic auth, and the passwords are verified
+	// against the upstream TiDB.
+	Users map[string]Role `toml:"users" json:"users,omitempty"`
+	// Tokens maps the static tokens to their roles. The clients
+	// authenticate with the `Authorization: Bearer <token>` header.
+	Tokens map[string]Role `toml:"tokens" json:"tokens,omitempty"`
+	// CertCommonNames maps the common names of the client certificates to
+	// their roles, it only works if TLS is enabled. Requests are forwarded
+	// to the owner with the certificate of the TiCDC server together with
+	// the identity of the client, which is trusted only if the role of the
+	// TiCDC server covers the role of the client. So the common name of the
+	// TiCDC servers should be mapped to the admin role.
+	CertCommonNames map[string]Role `toml:"cert-common-names" json:"cert-common-names,omitempty"`
+}
+
+// Validate checks whether the config is valid.
+func (c *ClientAuthConfig) Validate(credential *Credential) error {
+	if !c.Enable {
+		return nil
+	}
+	if len(c.Users) == 0 && len(c.Tokens) == 0 && len(c.CertCommonNames) == 0 {
+		return cerror.ErrInvalidClientAuthConfig.GenWithStackByArgs(
+			"at least one of users, tokens and cert-common-names must be set")
+	}
+	if len(c.CertCommonNames) != 0 && !credential.IsTLSEnabled() {
+		return cerror.ErrInvalidClientAuthConfig.GenWithStackByArgs(
+			"cert-common-names requires TLS to be enabled")
+	}
+	for _, roles := range []map[string]Role{c.Users, c.Tokens, c.CertCommonNames} {
+		for name, role := range roles {
+			if name == "" {
+				return cerror.ErrInvalidClientAuthConfig.GenWithStackByArgs(
+					"empty user, token or common name")
+			}
+			if !role.IsValid() {
+				return cerror.ErrInvalidClientAuthConfig.GenWithStackByArgs(
+					"unknown role " + string(role))
+			}
+		}
+	}
+	return nil
+}
+
+// Redact returns a copy of the config whose tokens are masked,
+// so that it can be logged.
+func (c *ClientAuthConfig) Redact() *ClientAuthConfig {
+	redacted := *c
+	if len(c.Tokens) != 0 {
+		redacted.Tokens = make(map[string]Role, len(c.Tokens))
+		for token, role := range c.Tokens {
+			redacted.Tokens[MaskToken(token)] = role
+		}
+	}
+	return &redacted
+}
+
+// MaskToken masks the token so that it can be logged.
+func MaskToken(token string) string {
+	if len(token) <= 4 {
+		return "token-****"
+	}
+	return "token-" + token[:4] + "****"
+}
+
+// AuthCredential is used by the HTTP API clients to authenticate
+// themselves to the TiCDC server.
+type AuthCredential struct {
+	User     string
+	Password string
+	Token    string
+}
+
+// IsEmpty checks whether the credential is empty or not.
+func (c *AuthCredential) IsEmpty() bool {
+	return c == nil || (c.User == "" && c.Token == "")
+}
+
+// AuthorizationHeader returns the value of the `Authorization` header.
+// The token takes precedence over the user and password.
+func (c *AuthCredential) AuthorizationHeader() string {
+	if c.IsEmpty() {
+		return ""
+	}
+	if c.Token != "" {
+		return "Bearer " + c.Token
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.User+":"+c.Password))
+}
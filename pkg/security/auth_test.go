@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleAllows(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, RoleViewer.Allows(PermissionRead))
+	require.False(t, RoleViewer.Allows(PermissionWrite))
+	require.False(t, RoleViewer.Allows(PermissionAdmin))
+	require.True(t, RoleOperator.Allows(PermissionRead))
+	require.True(t, RoleOperator.Allows(PermissionWrite))
+	require.False(t, RoleOperator.Allows(PermissionAdmin))
+	require.True(t, RoleAdmin.Allows(PermissionAdmin))
+	require.False(t, Role("root").Allows(PermissionRead))
+}
+
+func TestRoleCovers(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, RoleAdmin.Covers(RoleOperator))
+	require.True(t, RoleOperator.Covers(RoleViewer))
+	require.True(t, RoleViewer.Covers(RoleViewer))
+	require.False(t, RoleOperator.Covers(RoleAdmin))
+	require.False(t, RoleViewer.Covers(RoleOperator))
+	require.False(t, RoleAdmin.Covers(Role("root")))
+}
+
+func TestValidateClientAuthConfig(t *testing.T) {
+	t.Parallel()
+
+	credential := &Credential{}
+	cfg := &ClientAuthConfig{}
+	require.Nil(t, cfg.Validate(credential))
+
+	cfg.Enable = true
+	require.ErrorContains(t, cfg.Validate(credential), "at least one of")
+
+	cfg.Tokens = map[string]Role{"secret": "root"}
+	require.ErrorContains(t, cfg.Validate(credential), "unknown role root")
+
+	cfg.Tokens = map[string]Role{"secret": RoleAdmin}
+	cfg.Users = map[string]Role{"": RoleViewer}
+	require.ErrorContains(t, cfg.Validate(credential), "empty user")
+
+	cfg.Users = map[string]Role{"root": RoleViewer}
+	require.Nil(t, cfg.Validate(credential))
+
+	cfg.CertCommonNames = map[string]Role{"client": RoleOperator}
+	require.ErrorContains(t, cfg.Validate(credential), "requires TLS")
+	credential.CAPath, credential.CertPath, credential.KeyPath = "ca", "cert", "key"
+	require.Nil(t, cfg.Validate(credential))
+}
+
+func TestAuthorizationHeader(t *testing.T) {
+	t.Parallel()
+
+	var credential *AuthCredential
+	require.True(t, credential.IsEmpty())
+	require.Equal(t, "", credential.AuthorizationHeader())
+
+	credential = &AuthCredential{User: "root", Password: "123"}
+	require.Equal(t, "Basic cm9vdDoxMjM=", credential.AuthorizationHeader())
+	credential.Token = "secret"
+	require.Equal(t, "Bearer secret", credential.AuthorizationHeader())
+}
@@ -32,6 +32,9 @@ type Credential struct {
 	CertPath      string   `toml:"cert-path" json:"cert-path"`
 	KeyPath       string   `toml:"key-path" json:"key-path"`
 	CertAllowedCN []string `toml:"cert-allowed-cn" json:"cert-allowed-cn"`
+	// ClientAuth is only used by the TiCDC server to authenticate and
+	// authorize the HTTP API clients.
+	ClientAuth *ClientAuthConfig `toml:"client-auth" json:"client-auth,omitempty"`
 }
 
 // IsTLSEnabled checks whether TLS is enabled or not.
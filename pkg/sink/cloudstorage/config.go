@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/pkg/compression"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	psink "github.com/pingcap/tiflow/pkg/sink"
@@ -54,6 +55,8 @@ type Config struct {
 	FileSize                 int
 	DateSeparator            string
 	EnablePartitionSeparator bool
+	// Compression is the codec used to compress the data files.
+	Compression string
 }
 
 // NewConfig returns the default cloud storage sink config.
@@ -62,6 +65,7 @@ func NewConfig() *Config {
 		WorkerCount:   defaultWorkerCount,
 		FlushInterval: defaultFlushInterval,
 		FileSize:      defaultFileSize,
+		Compression:   compression.None,
 	}
 }
 
@@ -91,6 +95,10 @@ func (c *Config) Apply(
 	if err != nil {
 		return err
 	}
+	err = getCompression(query, &c.Compression)
+	if err != nil {
+		return err
+	}
 
 	c.DateSeparator = replicaConfig.Sink.DateSeparator
 	c.EnablePartitionSeparator = replicaConfig.Sink.EnablePartitionSeparator
@@ -171,3 +179,17 @@ func getFileSize(values url.Values, fileSize *int) error {
 	*fileSize = sz
 	return nil
 }
+
+func getCompression(values url.Values, cc *string) error {
+	s := values.Get("compression")
+	if len(s) == 0 {
+		return nil
+	}
+
+	c, err := compression.Supported(s)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrCloudStorageInvalidConfig, err)
+	}
+	*cc = c
+	return nil
+}
@@ -29,7 +29,8 @@ func TestConfigApply(t *testing.T) {
 	expected.FlushInterval = 10 * time.Second
 	expected.FileSize = 16 * 1024 * 1024
 	expected.DateSeparator = config.DateSeparatorNone.String()
-	uri := "s3://bucket/prefix?worker-count=32&flush-interval=10s&file-size=16777216"
+	expected.Compression = "gzip"
+	uri := "s3://bucket/prefix?worker-count=32&flush-interval=10s&file-size=16777216&compression=GZIP"
 	sinkURI, err := url.Parse(uri)
 	require.Nil(t, err)
 	cfg := NewConfig()
@@ -104,6 +105,16 @@ func TestVerifySinkURIParams(t *testing.T) {
 			uri:         "s3://bucket/prefix?file-size=1073741824",
 			expectedErr: "",
 		},
+		{
+			name:        "valid sink uri with zstd compression",
+			uri:         "s3://bucket/prefix?compression=zstd",
+			expectedErr: "",
+		},
+		{
+			name:        "invalid sink uri with unsupported compression",
+			uri:         "s3://bucket/prefix?compression=brotli",
+			expectedErr: "unsupported compression brotli",
+		},
 	}
 
 	for _, tc := range testCases {
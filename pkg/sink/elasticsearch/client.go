@@ -0,0 +1,286 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+)
+
+const (
+	// versionType makes elasticsearch accept the actions whose versions are
+	// greater than or equal to the versions of the documents, so the actions
+	// of the same transaction can be replayed.
+	versionType = "external_gte"
+	// the max length of the response body kept in the errors.
+	maxErrorBodyLength = 1024
+	// resourceAlreadyExists is the error type returned when creating an
+	// existing index.
+	resourceAlreadyExists = "resource_already_exists_exception"
+)
+
+// Client sends the requests to elasticsearch.
+type Client struct {
+	changefeedID model.ChangeFeedID
+	cfg          *Config
+	client       *httputil.Client
+}
+
+// NewClient creates an elasticsearch client.
+func NewClient(cfg *Config, changefeedID model.ChangeFeedID) (*Client, error) {
+	client, err := httputil.NewClient(nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrElasticsearchInvalidConfig, err)
+	}
+	client.SetTimeout(cfg.RequestTimeout)
+	return &Client{
+		changefeedID: changefeedID,
+		cfg:          cfg,
+		client:       client,
+	}, nil
+}
+
+type actionMeta struct {
+	Index       string `json:"_index"`
+	ID          string `json:"_id"`
+	Version     uint64 `json:"version"`
+	VersionType string `json:"version_type"`
+}
+
+type bulkResponse struct {
+	Errors bool                             `json:"errors"`
+	Items  []map[ActionType]*bulkItemResult `json:"items"`
+}
+
+type bulkItemResult struct {
+	ID     string          `json:"_id"`
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// Bulk sends the actions in a bulk request. The request is retried with
+// backoff on network errors and retryable status codes. The actions
+// conflicting with newer versions of the documents are ignored.
+func (c *Client) Bulk(ctx context.Context, actions []*Action) error {
+	if len(actions) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, action := range actions {
+		meta, err := json.Marshal(map[ActionType]*actionMeta{
+			action.Type: {
+				Index:       action.Index,
+				ID:          action.ID,
+				Version:     action.Version,
+				VersionType: versionType,
+			},
+		})
+		if err != nil {
+			return cerror.WrapError(cerror.ErrElasticsearchRequestRejected, err, err.Error())
+		}
+		buf.Write(meta)
+		buf.WriteByte('\n')
+		if action.Type == ActionDelete {
+			continue
+		}
+		doc, err := json.Marshal(action.Document)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrElasticsearchRequestRejected, err, err.Error())
+		}
+		buf.Write(doc)
+		buf.WriteByte('\n')
+	}
+	body := buf.Bytes()
+
+	return c.withRetry(ctx, func() error {
+		status, respBody, err := c.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body)
+		if err != nil {
+			return err
+		}
+		if err := checkStatus(status, respBody); err != nil {
+			return err
+		}
+		var resp bulkResponse
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return cerror.WrapError(cerror.ErrElasticsearchRequestFailed, err, err.Error())
+		}
+		if !resp.Errors {
+			return nil
+		}
+		return c.checkBulkItems(resp.Items)
+	})
+}
+
+// checkBulkItems returns the first error of the bulk items. The version
+// conflicts and the deletions of the missing documents are not errors.
+func (c *Client) checkBulkItems(items []map[ActionType]*bulkItemResult) error {
+	var rejected error
+	for _, item := range items {
+		for tp, result := range item {
+			switch {
+			case result.Status < 300:
+			case result.Status == http.StatusConflict:
+				log.Debug("elasticsearch document is newer than the action, ignore it",
+					zap.String("namespace", c.changefeedID.Namespace),
+					zap.String("changefeed", c.changefeedID.ID),
+					zap.String("id", result.ID))
+			case result.Status == http.StatusNotFound && tp == ActionDelete:
+			case isRetryableStatus(result.Status):
+				// Retry the whole request, it is safe to apply the
+				// successful actions again with the same versions.
+				return cerror.ErrElasticsearchRequestFailed.GenWithStackByArgs(
+					fmt.Sprintf("%s document %s, status code %d, error %s",
+						tp, result.ID, result.Status, string(result.Error)))
+			default:
+				if rejected == nil {
+					rejected = cerror.ErrElasticsearchRequestRejected.GenWithStackByArgs(
+						fmt.Sprintf("%s document %s, status code %d, error %s",
+							tp, result.ID, result.Status, string(result.Error)))
+				}
+			}
+		}
+	}
+	return rejected
+}
+
+// PutMapping creates the index with the mapping properties, or updates the
+// mapping of the index if it exists. The types of the existing fields can
+// not be changed by elasticsearch, so the mapping conflicts are returned as
+// ErrElasticsearchRequestRejected.
+func (c *Client) PutMapping(
+	ctx context.Context, index string, properties map[string]interface{},
+) error {
+	createBody, err := json.Marshal(map[string]interface{}{
+		"mappings": map[string]interface{}{"properties": properties},
+	})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrElasticsearchRequestRejected, err, err.Error())
+	}
+	updateBody, err := json.Marshal(map[string]interface{}{"properties": properties})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrElasticsearchRequestRejected, err, err.Error())
+	}
+	path := "/" + url.PathEscape(index)
+
+	return c.withRetry(ctx, func() error {
+		status, respBody, err := c.do(ctx, http.MethodPut, path, "application/json", createBody)
+		if err != nil {
+			return err
+		}
+		if status == http.StatusBadRequest && strings.Contains(string(respBody), resourceAlreadyExists) {
+			status, respBody, err = c.do(ctx, http.MethodPut, path+"/_mapping", "application/json", updateBody)
+			if err != nil {
+				return err
+			}
+		}
+		return checkStatus(status, respBody)
+	})
+}
+
+// PutTableMapping puts the mapping of the table to the index. The types of
+// the existing fields can not be changed by elasticsearch, so the rejected
+// mapping updates are logged and ignored.
+func (c *Client) PutTableMapping(
+	ctx context.Context, index string, tableInfo *model.TableInfo,
+) error {
+	err := c.PutMapping(ctx, index, NewMapping(tableInfo))
+	if err != nil && cerror.ErrElasticsearchRequestRejected.Equal(errors.Cause(err)) {
+		log.Warn("elasticsearch index mapping is not updated",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.String("index", index),
+			zap.Error(err))
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// Close closes the client.
+func (c *Client) Close() {
+	c.client.CloseIdleConnections()
+}
+
+func (c *Client) withRetry(ctx context.Context, op func() error) error {
+	return retry.Do(ctx, op,
+		retry.WithBackoffBaseDelay(c.cfg.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(c.cfg.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(c.cfg.MaxRetries),
+		retry.WithIsRetryableErr(isRetryableError))
+}
+
+func (c *Client) do(
+	ctx context.Context, method, path, contentType string, body []byte,
+) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.cfg.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, cerror.WrapError(cerror.ErrElasticsearchRequestRejected, err, err.Error())
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Warn("elasticsearch request failed",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.String("path", path),
+			zap.Error(err))
+		return 0, nil, cerror.WrapError(cerror.ErrElasticsearchRequestFailed, err, err.Error())
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, cerror.WrapError(cerror.ErrElasticsearchRequestFailed, err, err.Error())
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+func checkStatus(status int, body []byte) error {
+	if status >= 200 && status < 300 {
+		return nil
+	}
+	if len(body) > maxErrorBodyLength {
+		body = body[:maxErrorBodyLength]
+	}
+	msg := fmt.Sprintf("status code %d, body %s", status, string(body))
+	if isRetryableStatus(status) {
+		return cerror.ErrElasticsearchRequestFailed.GenWithStackByArgs(msg)
+	}
+	return cerror.ErrElasticsearchRequestRejected.GenWithStackByArgs(msg)
+}
+
+func isRetryableStatus(status int) bool {
+	return status >= 500 || status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests
+}
+
+func isRetryableError(err error) bool {
+	return !cerror.ErrElasticsearchRequestRejected.Equal(errors.Cause(err)) &&
+		errors.Cause(err) != context.Canceled
+}
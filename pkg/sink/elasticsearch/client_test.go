@@ -0,0 +1,112 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, endpoint string) *Client {
+	cfg := NewConfig()
+	cfg.Endpoint = endpoint
+	cfg.MaxRetries = 3
+	cfg.BackoffBaseDelay = time.Millisecond
+	cfg.BackoffMaxDelay = 10 * time.Millisecond
+	client, err := NewClient(cfg, model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+	return client
+}
+
+func TestClientBulk(t *testing.T) {
+	t.Parallel()
+
+	server := NewMockServer()
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+	defer client.Close()
+	ctx := context.Background()
+
+	require.NoError(t, client.Bulk(ctx, []*Action{
+		{Type: ActionIndex, Index: "idx", ID: "1", Version: 10, Document: map[string]interface{}{"a": 1}},
+		{Type: ActionIndex, Index: "idx", ID: "2", Version: 10, Document: map[string]interface{}{"a": 2}},
+		// deleting a missing document is not an error.
+		{Type: ActionDelete, Index: "idx", ID: "3", Version: 10},
+	}))
+	require.Len(t, server.Documents("idx"), 2)
+
+	// the stale actions are ignored.
+	require.NoError(t, client.Bulk(ctx, []*Action{
+		{Type: ActionDelete, Index: "idx", ID: "1", Version: 9},
+		{Type: ActionIndex, Index: "idx", ID: "2", Version: 11, Document: map[string]interface{}{"a": 3}},
+	}))
+	docs := server.Documents("idx")
+	require.Len(t, docs, 2)
+	require.Equal(t, float64(3), docs["2"]["a"])
+
+	// the failed requests are retried.
+	server.FailNext(http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	require.NoError(t, client.Bulk(ctx, []*Action{
+		{Type: ActionDelete, Index: "idx", ID: "1", Version: 12},
+	}))
+	require.Len(t, server.Documents("idx"), 1)
+
+	// the rejected requests are not retried.
+	server.FailNext(http.StatusUnauthorized)
+	err := client.Bulk(ctx, []*Action{{Type: ActionDelete, Index: "idx", ID: "2", Version: 13}})
+	require.True(t, cerror.ErrElasticsearchRequestRejected.Equal(err))
+	require.Len(t, server.Documents("idx"), 1)
+}
+
+func TestClientPutTableMapping(t *testing.T) {
+	t.Parallel()
+
+	server := NewMockServer()
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+	defer client.Close()
+	ctx := context.Background()
+
+	tableInfo := &model.TableInfo{TableInfo: &timodel.TableInfo{
+		Columns: []*timodel.ColumnInfo{
+			{Name: timodel.NewCIStr("id"), FieldType: *types.NewFieldType(mysql.TypeLong)},
+		},
+	}}
+	require.NoError(t, client.PutTableMapping(ctx, "idx", tableInfo))
+	require.Len(t, server.Mapping("idx"), 1)
+
+	// the index exists, so the mapping is updated.
+	tableInfo.Columns = append(tableInfo.Columns, &timodel.ColumnInfo{
+		Name: timodel.NewCIStr("name"), FieldType: *types.NewFieldType(mysql.TypeVarchar),
+	})
+	require.NoError(t, client.PutTableMapping(ctx, "idx", tableInfo))
+	require.Len(t, server.Mapping("idx"), 2)
+
+	// the type of the existing field can not be changed, and the rejected
+	// update is ignored.
+	tableInfo.Columns[0] = &timodel.ColumnInfo{
+		Name: timodel.NewCIStr("id"), FieldType: *types.NewFieldType(mysql.TypeDouble),
+	}
+	require.NoError(t, client.PutTableMapping(ctx, "idx", tableInfo))
+	require.Equal(t, "long", fieldType(server.Mapping("idx")["id"]))
+}
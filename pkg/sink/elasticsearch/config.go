@@ -0,0 +1,212 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	psink "github.com/pingcap/tiflow/pkg/sink"
+)
+
+const (
+	// SchemaPlaceholder is replaced by the schema name in the index template.
+	SchemaPlaceholder = "{schema}"
+	// TablePlaceholder is replaced by the table name in the index template.
+	TablePlaceholder = "{table}"
+
+	// defaultIndexTemplate is the default value of index.
+	defaultIndexTemplate = SchemaPlaceholder + "." + TablePlaceholder
+	// defaultBatchSize is the default value of batch-size.
+	defaultBatchSize = 256
+	// the upper limit of batch-size.
+	maxBatchSize = 10000
+	// defaultFlushInterval is the default value of flush-interval.
+	defaultFlushInterval = time.Second
+	// defaultMaxRetries is the default value of max-retries.
+	defaultMaxRetries = 8
+	// defaultBackoffBaseDelay is the default value of backoff-base-delay.
+	defaultBackoffBaseDelay = 500 * time.Millisecond
+	// defaultBackoffMaxDelay is the default value of backoff-max-delay.
+	defaultBackoffMaxDelay = 30 * time.Second
+	// defaultRequestTimeout is the default value of request-timeout.
+	defaultRequestTimeout = 30 * time.Second
+)
+
+const (
+	indexKey            = "index"
+	batchSizeKey        = "batch-size"
+	flushIntervalKey    = "flush-interval"
+	maxRetriesKey       = "max-retries"
+	backoffBaseDelayKey = "backoff-base-delay"
+	backoffMaxDelayKey  = "backoff-max-delay"
+	requestTimeoutKey   = "request-timeout"
+)
+
+// Config is the configuration for the elasticsearch sink.
+type Config struct {
+	// Endpoint is the base URL of the elasticsearch cluster.
+	Endpoint string
+	Username string
+	Password string
+	// IndexTemplate is the template of the index names, which supports
+	// the {schema} and {table} placeholders.
+	IndexTemplate string
+	// BatchSize is the max number of rows in a bulk request.
+	BatchSize     int
+	FlushInterval time.Duration
+	// MaxRetries is the max number of tries of a request.
+	MaxRetries       uint64
+	BackoffBaseDelay time.Duration
+	BackoffMaxDelay  time.Duration
+	RequestTimeout   time.Duration
+}
+
+// NewConfig returns the default elasticsearch sink config.
+func NewConfig() *Config {
+	return &Config{
+		IndexTemplate:    defaultIndexTemplate,
+		BatchSize:        defaultBatchSize,
+		FlushInterval:    defaultFlushInterval,
+		MaxRetries:       defaultMaxRetries,
+		BackoffBaseDelay: defaultBackoffBaseDelay,
+		BackoffMaxDelay:  defaultBackoffMaxDelay,
+		RequestTimeout:   defaultRequestTimeout,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL, _ *config.ReplicaConfig) error {
+	if sinkURI == nil {
+		return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+			"failed to open elasticsearch sink, empty SinkURI")
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !psink.IsElasticsearchScheme(scheme) {
+		return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+			"can't create elasticsearch sink with unsupported scheme: %s", scheme)
+	}
+	if sinkURI.Host == "" {
+		return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+			"the host of the elasticsearch sink is empty")
+	}
+
+	endpoint := url.URL{Scheme: "http", Host: sinkURI.Host, Path: strings.TrimSuffix(sinkURI.Path, "/")}
+	if scheme == psink.ElasticsearchSSLScheme {
+		endpoint.Scheme = "https"
+	}
+	c.Endpoint = endpoint.String()
+	if sinkURI.User != nil {
+		c.Username = sinkURI.User.Username()
+		c.Password, _ = sinkURI.User.Password()
+	}
+
+	query := sinkURI.Query()
+	if s := query.Get(indexKey); s != "" {
+		c.IndexTemplate = s
+	}
+	if err := validateIndexTemplate(c.IndexTemplate); err != nil {
+		return err
+	}
+	if err := getInt(query, batchSizeKey, &c.BatchSize); err != nil {
+		return err
+	}
+	if c.BatchSize > maxBatchSize {
+		c.BatchSize = maxBatchSize
+	}
+	var maxRetries int
+	if err := getInt(query, maxRetriesKey, &maxRetries); err != nil {
+		return err
+	}
+	if maxRetries > 0 {
+		c.MaxRetries = uint64(maxRetries)
+	}
+	for key, d := range map[string]*time.Duration{
+		flushIntervalKey:    &c.FlushInterval,
+		backoffBaseDelayKey: &c.BackoffBaseDelay,
+		backoffMaxDelayKey:  &c.BackoffMaxDelay,
+		requestTimeoutKey:   &c.RequestTimeout,
+	} {
+		if err := getDuration(query, key, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IndexName returns the index name of the table. The index names of
+// elasticsearch must be in lowercase.
+func (c *Config) IndexName(schema, table string) string {
+	index := strings.ReplaceAll(c.IndexTemplate, SchemaPlaceholder, schema)
+	index = strings.ReplaceAll(index, TablePlaceholder, table)
+	return strings.ToLower(index)
+}
+
+// validateIndexTemplate checks that the index template only contains the
+// supported placeholders and the characters allowed in the index names.
+func validateIndexTemplate(template string) error {
+	trimmed := strings.ReplaceAll(template, SchemaPlaceholder, "")
+	trimmed = strings.ReplaceAll(trimmed, TablePlaceholder, "")
+	if strings.ContainsAny(trimmed, "{}\\/*?\"<>| ,#:") {
+		return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+			"invalid index template %s, only the %s and %s placeholders are supported "+
+				"and the index name can not contain \\/*?\"<>| ,#:",
+			template, SchemaPlaceholder, TablePlaceholder)
+	}
+	if strings.HasPrefix(template, "-") || strings.HasPrefix(template, "_") ||
+		strings.HasPrefix(template, "+") {
+		return cerror.ErrElasticsearchInvalidConfig.GenWithStack(
+			"invalid index template %s, the index name can not start with -, _ or +", template)
+	}
+	return nil
+}
+
+func getInt(values url.Values, key string, target *int) error {
+	s := values.Get(key)
+	if len(s) == 0 {
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig, err)
+	}
+	if v <= 0 {
+		return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig,
+			fmt.Errorf("invalid %s %d, it must be greater than 0", key, v))
+	}
+	*target = v
+	return nil
+}
+
+func getDuration(values url.Values, key string, target *time.Duration) error {
+	s := values.Get(key)
+	if len(s) == 0 {
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig, err)
+	}
+	if d <= 0 {
+		return cerror.WrapError(cerror.ErrElasticsearchInvalidConfig,
+			fmt.Errorf("invalid %s %s, it must be greater than 0", key, s))
+	}
+	*target = d
+	return nil
+}
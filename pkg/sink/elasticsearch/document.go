@@ -0,0 +1,213 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/charset"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// ActionType is the type of the bulk API actions.
+type ActionType string
+
+const (
+	// ActionIndex indexes a document, the document is replaced if it exists.
+	ActionIndex ActionType = "index"
+	// ActionDelete deletes a document.
+	ActionDelete ActionType = "delete"
+)
+
+// Action is an action of the bulk API. The commit ts of the row is used as
+// the external version of the document, so the stale actions replayed after
+// a restart of the changefeed are ignored by elasticsearch.
+type Action struct {
+	Type     ActionType
+	Index    string
+	ID       string
+	Version  uint64
+	Document map[string]interface{}
+}
+
+// NewActions converts a row changed event to the bulk API actions. The
+// documents are keyed by the handle key columns of the rows, and an update
+// changing the handle key is converted to a delete and an index action.
+func NewActions(index string, row *model.RowChangedEvent) ([]*Action, error) {
+	var actions []*Action
+	if len(row.PreColumns) != 0 {
+		preID, err := documentID(row, row.PreColumns)
+		if err != nil {
+			return nil, err
+		}
+		if len(row.Columns) == 0 {
+			return []*Action{{Type: ActionDelete, Index: index, ID: preID, Version: row.CommitTs}}, nil
+		}
+		id, err := documentID(row, row.Columns)
+		if err != nil {
+			return nil, err
+		}
+		if preID != id {
+			actions = append(actions,
+				&Action{Type: ActionDelete, Index: index, ID: preID, Version: row.CommitTs})
+		}
+	}
+	id, err := documentID(row, row.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return append(actions, &Action{
+		Type:     ActionIndex,
+		Index:    index,
+		ID:       id,
+		Version:  row.CommitTs,
+		Document: newDocument(row.Columns),
+	}), nil
+}
+
+// documentID returns the document id of the row, which is the value of the
+// handle key column, or a JSON array of the values if the handle key has
+// multiple columns.
+func documentID(row *model.RowChangedEvent, cols []*model.Column) (string, error) {
+	var values []string
+	for _, col := range cols {
+		if col != nil && col.Flag.IsHandleKey() {
+			values = append(values, model.ColumnValueString(col.Value))
+		}
+	}
+	switch len(values) {
+	case 0:
+		return "", cerror.ErrElasticsearchDocumentIDNotFound.GenWithStackByArgs(
+			row.Table.String())
+	case 1:
+		return values[0], nil
+	default:
+		data, err := json.Marshal(values)
+		if err != nil {
+			return "", cerror.WrapError(cerror.ErrElasticsearchRequestRejected, err, err.Error())
+		}
+		return string(data), nil
+	}
+}
+
+// newDocument converts the columns to a document. The values of the binary
+// columns are encoded in base64, and the other byte values are converted to
+// strings.
+func newDocument(cols []*model.Column) map[string]interface{} {
+	doc := make(map[string]interface{}, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			continue
+		}
+		if b, ok := col.Value.([]byte); ok && !col.Flag.IsBinary() {
+			doc[col.Name] = string(b)
+			continue
+		}
+		doc[col.Name] = col.Value
+	}
+	return doc
+}
+
+// ShouldUpdateMapping returns true if the mapping of the index should be
+// updated by the DDL event. The indices of the dropped tables are kept.
+func ShouldUpdateMapping(ddl *model.DDLEvent) bool {
+	if ddl.TableInfo == nil || ddl.TableInfo.TableInfo == nil || ddl.TableInfo.IsView() {
+		return false
+	}
+	switch ddl.Type {
+	case timodel.ActionDropTable, timodel.ActionDropView, timodel.ActionDropSchema:
+		return false
+	}
+	return true
+}
+
+// NewMapping returns the properties of the index mapping of the table.
+// The columns whose types can not be mapped are left to the dynamic mapping.
+func NewMapping(tableInfo *model.TableInfo) map[string]interface{} {
+	properties := make(map[string]interface{}, len(tableInfo.Columns))
+	for _, col := range tableInfo.Columns {
+		if col.Hidden {
+			continue
+		}
+		if m := fieldMapping(&col.FieldType); m != nil {
+			properties[col.Name.O] = m
+		}
+	}
+	return properties
+}
+
+func fieldMapping(ft *types.FieldType) map[string]interface{} {
+	isBinary := ft.GetCharset() == charset.CharsetBin
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong,
+		mysql.TypeYear, mysql.TypeEnum:
+		return map[string]interface{}{"type": "long"}
+	case mysql.TypeLonglong:
+		if mysql.HasUnsignedFlag(ft.GetFlag()) {
+			return map[string]interface{}{"type": "unsigned_long"}
+		}
+		return map[string]interface{}{"type": "long"}
+	case mysql.TypeBit, mysql.TypeSet:
+		return map[string]interface{}{"type": "unsigned_long"}
+	case mysql.TypeFloat:
+		return map[string]interface{}{"type": "float"}
+	case mysql.TypeDouble, mysql.TypeNewDecimal:
+		return map[string]interface{}{"type": "double"}
+	case mysql.TypeDate:
+		return dateMapping("yyyy-MM-dd")
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		format := "yyyy-MM-dd HH:mm:ss"
+		if fsp := ft.GetDecimal(); fsp > 0 {
+			format += "." + strings.Repeat("S", fsp)
+		}
+		return dateMapping(format)
+	case mysql.TypeDuration:
+		return map[string]interface{}{"type": "keyword"}
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString:
+		if isBinary {
+			return map[string]interface{}{"type": "binary"}
+		}
+		// The same as the dynamic mapping of the strings.
+		return map[string]interface{}{
+			"type": "text",
+			"fields": map[string]interface{}{
+				"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 256},
+			},
+		}
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		if isBinary {
+			return map[string]interface{}{"type": "binary"}
+		}
+		return map[string]interface{}{"type": "text"}
+	case mysql.TypeJSON:
+		return map[string]interface{}{"type": "text"}
+	default:
+		return nil
+	}
+}
+
+// dateMapping returns the mapping of the date columns. The zero dates of
+// MySQL can not be parsed by elasticsearch, so they are ignored.
+func dateMapping(format string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":             "date",
+		"format":           format,
+		"ignore_malformed": true,
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewActions(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	newCols := func(id int64, name string) []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLonglong, Value: id, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte(name)},
+			{Name: "data", Type: mysql.TypeBlob, Value: []byte{1, 2}, Flag: model.BinaryFlag},
+			{Name: "note", Type: mysql.TypeVarchar, Value: nil},
+		}
+	}
+
+	// insert
+	actions, err := NewActions("idx", &model.RowChangedEvent{
+		CommitTs: 10, Table: table, Columns: newCols(1, "a"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*Action{{
+		Type: ActionIndex, Index: "idx", ID: "1", Version: 10,
+		Document: map[string]interface{}{"id": int64(1), "name": "a", "data": []byte{1, 2}, "note": nil},
+	}}, actions)
+
+	// update without changing the handle key
+	actions, err = NewActions("idx", &model.RowChangedEvent{
+		CommitTs: 11, Table: table, PreColumns: newCols(1, "a"), Columns: newCols(1, "b"),
+	})
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	require.Equal(t, ActionIndex, actions[0].Type)
+	require.Equal(t, "b", actions[0].Document["name"])
+
+	// update changing the handle key
+	actions, err = NewActions("idx", &model.RowChangedEvent{
+		CommitTs: 12, Table: table, PreColumns: newCols(1, "b"), Columns: newCols(2, "b"),
+	})
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	require.Equal(t, &Action{Type: ActionDelete, Index: "idx", ID: "1", Version: 12}, actions[0])
+	require.Equal(t, ActionIndex, actions[1].Type)
+	require.Equal(t, "2", actions[1].ID)
+
+	// delete
+	actions, err = NewActions("idx", &model.RowChangedEvent{
+		CommitTs: 13, Table: table, PreColumns: newCols(2, "b"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*Action{{Type: ActionDelete, Index: "idx", ID: "2", Version: 13}}, actions)
+
+	// the handle key has multiple columns
+	cols := newCols(3, "c")
+	cols[1].Flag = model.HandleKeyFlag
+	actions, err = NewActions("idx", &model.RowChangedEvent{CommitTs: 14, Table: table, Columns: cols})
+	require.NoError(t, err)
+	require.Equal(t, `["3","c"]`, actions[0].ID)
+
+	// the table has no handle key
+	cols = newCols(4, "d")
+	cols[0].Flag = 0
+	_, err = NewActions("idx", &model.RowChangedEvent{CommitTs: 15, Table: table, Columns: cols})
+	require.Regexp(t, ".*document id of the rows in table.*not found.*", err)
+}
+
+func TestNewMapping(t *testing.T) {
+	t.Parallel()
+
+	newColumn := func(name string, tp byte, flag uint, decimal int, charset string) *timodel.ColumnInfo {
+		ft := types.NewFieldType(tp)
+		ft.SetFlag(flag)
+		ft.SetDecimal(decimal)
+		ft.SetCharset(charset)
+		return &timodel.ColumnInfo{Name: timodel.NewCIStr(name), FieldType: *ft}
+	}
+	tableInfo := &model.TableInfo{TableInfo: &timodel.TableInfo{
+		Columns: []*timodel.ColumnInfo{
+			newColumn("id", mysql.TypeLonglong, mysql.UnsignedFlag, 0, ""),
+			newColumn("age", mysql.TypeLong, 0, 0, ""),
+			newColumn("price", mysql.TypeNewDecimal, 0, 2, ""),
+			newColumn("created", mysql.TypeDatetime, 0, 3, ""),
+			newColumn("name", mysql.TypeVarchar, 0, 0, "utf8mb4"),
+			newColumn("data", mysql.TypeBlob, mysql.BinaryFlag, 0, "binary"),
+			newColumn("geo", mysql.TypeGeometry, 0, 0, ""),
+		},
+	}}
+	properties := NewMapping(tableInfo)
+	require.Len(t, properties, 6)
+	require.Equal(t, map[string]interface{}{"type": "unsigned_long"}, properties["id"])
+	require.Equal(t, map[string]interface{}{"type": "long"}, properties["age"])
+	require.Equal(t, map[string]interface{}{"type": "double"}, properties["price"])
+	require.Equal(t, "yyyy-MM-dd HH:mm:ss.SSS", properties["created"].(map[string]interface{})["format"])
+	require.Equal(t, "text", properties["name"].(map[string]interface{})["type"])
+	require.Equal(t, map[string]interface{}{"type": "binary"}, properties["data"])
+	require.NotContains(t, properties, "geo")
+}
+
+func TestShouldUpdateMapping(t *testing.T) {
+	t.Parallel()
+
+	tableInfo := &model.TableInfo{TableInfo: &timodel.TableInfo{}}
+	require.True(t, ShouldUpdateMapping(&model.DDLEvent{
+		Type: timodel.ActionCreateTable, TableInfo: tableInfo,
+	}))
+	require.True(t, ShouldUpdateMapping(&model.DDLEvent{
+		Type: timodel.ActionAddColumn, TableInfo: tableInfo,
+	}))
+	require.False(t, ShouldUpdateMapping(&model.DDLEvent{
+		Type: timodel.ActionDropTable, TableInfo: tableInfo,
+	}))
+	require.False(t, ShouldUpdateMapping(&model.DDLEvent{
+		Type: timodel.ActionCreateSchema, TableInfo: &model.TableInfo{},
+	}))
+	require.False(t, ShouldUpdateMapping(&model.DDLEvent{
+		Type:      timodel.ActionCreateView,
+		TableInfo: &model.TableInfo{TableInfo: &timodel.TableInfo{View: &timodel.ViewInfo{}}},
+	}))
+}
@@ -0,0 +1,229 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// MockServer is a local stand-in of elasticsearch for tests. It supports the
+// bulk API with external versions, and the index creation and mapping APIs.
+type MockServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	indices  map[string]*mockIndex
+	failures []int
+}
+
+type mockIndex struct {
+	properties map[string]interface{}
+	documents  map[string]*mockDocument
+}
+
+type mockDocument struct {
+	version uint64
+	// source is nil if the document is deleted.
+	source map[string]interface{}
+}
+
+// NewMockServer creates and starts a MockServer.
+func NewMockServer() *MockServer {
+	s := &MockServer{indices: make(map[string]*mockIndex)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// FailNext makes the next requests fail with the status codes.
+func (s *MockServer) FailNext(status ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, status...)
+}
+
+// Documents returns the documents of the index.
+func (s *MockServer) Documents(index string) map[string]map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	docs := make(map[string]map[string]interface{})
+	if idx, ok := s.indices[index]; ok {
+		for id, doc := range idx.documents {
+			if doc.source != nil {
+				docs[id] = doc.source
+			}
+		}
+	}
+	return docs
+}
+
+// Mapping returns the mapping properties of the index.
+func (s *MockServer) Mapping(index string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx, ok := s.indices[index]; ok {
+		return idx.properties
+	}
+	return nil
+}
+
+func (s *MockServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.failures) > 0 {
+		status := s.failures[0]
+		s.failures = s.failures[1:]
+		writeError(w, status, "mock_failure")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	path := strings.Trim(r.URL.Path, "/")
+	switch {
+	case r.Method == http.MethodPost && path == "_bulk":
+		s.handleBulk(w, body)
+	case r.Method == http.MethodPut && strings.HasSuffix(path, "/_mapping"):
+		var req struct {
+			Properties map[string]interface{} `json:"properties"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		idx, ok := s.indices[strings.TrimSuffix(path, "/_mapping")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "index_not_found_exception")
+			return
+		}
+		for name, field := range req.Properties {
+			if old, ok := idx.properties[name]; ok && fieldType(old) != fieldType(field) {
+				writeError(w, http.StatusBadRequest, "illegal_argument_exception")
+				return
+			}
+		}
+		for name, field := range req.Properties {
+			idx.properties[name] = field
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"acknowledged": true})
+	case r.Method == http.MethodPut && !strings.Contains(path, "/"):
+		var req struct {
+			Mappings struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"mappings"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := s.indices[path]; ok {
+			writeError(w, http.StatusBadRequest, resourceAlreadyExists)
+			return
+		}
+		idx := s.getOrCreateIndex(path)
+		for name, field := range req.Mappings.Properties {
+			idx.properties[name] = field
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"acknowledged": true})
+	default:
+		writeError(w, http.StatusNotFound, "unsupported request "+r.Method+" "+path)
+	}
+}
+
+func (s *MockServer) handleBulk(w http.ResponseWriter, body []byte) {
+	var (
+		items     []map[ActionType]*bulkItemResult
+		hasErrors bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, len(body)+1)
+	for scanner.Scan() {
+		var meta map[ActionType]*actionMeta
+		if err := json.Unmarshal(scanner.Bytes(), &meta); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for tp, m := range meta {
+			var source map[string]interface{}
+			if tp != ActionDelete {
+				if !scanner.Scan() {
+					writeError(w, http.StatusBadRequest, "missing document")
+					return
+				}
+				if err := json.Unmarshal(scanner.Bytes(), &source); err != nil {
+					writeError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+			}
+			result := &bulkItemResult{ID: m.ID, Status: http.StatusOK}
+			idx := s.getOrCreateIndex(m.Index)
+			doc, ok := idx.documents[m.ID]
+			switch {
+			case ok && doc.version > m.Version:
+				result.Status = http.StatusConflict
+				result.Error = json.RawMessage(`{"type":"version_conflict_engine_exception"}`)
+				hasErrors = true
+			case tp == ActionDelete && (!ok || doc.source == nil):
+				result.Status = http.StatusNotFound
+				idx.documents[m.ID] = &mockDocument{version: m.Version}
+			default:
+				idx.documents[m.ID] = &mockDocument{version: m.Version, source: source}
+			}
+			items = append(items, map[ActionType]*bulkItemResult{tp: result})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"errors": hasErrors, "items": items})
+}
+
+func (s *MockServer) getOrCreateIndex(name string) *mockIndex {
+	idx, ok := s.indices[name]
+	if !ok {
+		idx = &mockIndex{
+			properties: make(map[string]interface{}),
+			documents:  make(map[string]*mockDocument),
+		}
+		s.indices[name] = idx
+	}
+	return idx
+}
+
+func fieldType(field interface{}) string {
+	if m, ok := field.(map[string]interface{}); ok {
+		return fmt.Sprint(m["type"])
+	}
+	return ""
+}
+
+func writeError(w http.ResponseWriter, status int, errType string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error":  map[string]interface{}{"type": errType},
+		"status": status,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
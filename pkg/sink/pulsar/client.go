@@ -0,0 +1,58 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package pulsar
+
+import (
+	"strconv"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// PartitionPropertyKey is the key of the message property which carries
+// the partition chosen by the event router.
+const PartitionPropertyKey = "ticdc-partition"
+
+// ClientCreator defines the type of client crater.
+type ClientCreator func(config *Config) (pulsar.Client, error)
+
+// NewClient creates a new pulsar client.
+func NewClient(config *Config) (pulsar.Client, error) {
+	client, err := pulsar.NewClient(config.ClientOptions())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPulsarNewClient, err)
+	}
+	return client, nil
+}
+
+// SetPartition sets the partition the message should be sent to.
+func SetPartition(msg *pulsar.ProducerMessage, partition int32) {
+	if msg.Properties == nil {
+		msg.Properties = make(map[string]string, 1)
+	}
+	msg.Properties[PartitionPropertyKey] = strconv.Itoa(int(partition))
+}
+
+// routeByPartitionProperty routes the message to the partition set by
+// SetPartition. The messages without the property go to the first partition.
+func routeByPartitionProperty(msg *pulsar.ProducerMessage, metadata pulsar.TopicMetadata) int {
+	partition, err := strconv.Atoi(msg.Properties[PartitionPropertyKey])
+	if err != nil || partition < 0 {
+		return 0
+	}
+	return partition % int(metadata.NumPartitions())
+}
@@ -0,0 +1,208 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+)
+
+const (
+	// DefaultMockTopicName specifies the default mock topic name.
+	DefaultMockTopicName = "mock_topic"
+	// DefaultMockPartitionNum is the default partition number of mock topics.
+	DefaultMockPartitionNum = 3
+)
+
+var _ pulsar.Client = (*MockClient)(nil)
+
+// MockClient is a mock pulsar client. The messages sent by the producers
+// created by it are kept in memory.
+type MockClient struct {
+	mu         sync.Mutex
+	partitions map[string]int
+	messages   map[string]map[int][]*pulsar.ProducerMessage
+	// SendError is returned by all sends of the producers if it is not nil.
+	SendError error
+	closed    bool
+}
+
+// NewMockClient creates a new mock pulsar client.
+func NewMockClient() *MockClient {
+	return &MockClient{
+		partitions: map[string]int{DefaultMockTopicName: DefaultMockPartitionNum},
+		messages:   make(map[string]map[int][]*pulsar.ProducerMessage),
+	}
+}
+
+// NewMockClientCreator returns a ClientCreator which always returns the given client.
+func NewMockClientCreator(client *MockClient) ClientCreator {
+	return func(_ *Config) (pulsar.Client, error) {
+		return client, nil
+	}
+}
+
+// SetPartitionNum sets the partition number of the topic.
+func (m *MockClient) SetPartitionNum(topic string, partitionNum int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.partitions[topic] = partitionNum
+}
+
+// CreateProducer creates a mock producer.
+func (m *MockClient) CreateProducer(options pulsar.ProducerOptions) (pulsar.Producer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, errors.New("pulsar client is closed")
+	}
+	return &mockProducer{client: m, options: options}, nil
+}
+
+// Subscribe is not supported by the mock client.
+func (m *MockClient) Subscribe(pulsar.ConsumerOptions) (pulsar.Consumer, error) {
+	return nil, errors.New("not supported")
+}
+
+// CreateReader is not supported by the mock client.
+func (m *MockClient) CreateReader(pulsar.ReaderOptions) (pulsar.Reader, error) {
+	return nil, errors.New("not supported")
+}
+
+// CreateTableView is not supported by the mock client.
+func (m *MockClient) CreateTableView(pulsar.TableViewOptions) (pulsar.TableView, error) {
+	return nil, errors.New("not supported")
+}
+
+// TopicPartitions returns the partition names of the topic. The topic which is
+// not set by SetPartitionNum has DefaultMockPartitionNum partitions.
+func (m *MockClient) TopicPartitions(topic string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	num, ok := m.partitions[topic]
+	if !ok {
+		num = DefaultMockPartitionNum
+		m.partitions[topic] = num
+	}
+	names := make([]string, 0, num)
+	for i := 0; i < num; i++ {
+		names = append(names, fmt.Sprintf("%s-partition-%d", topic, i))
+	}
+	return names, nil
+}
+
+// Close closes the mock client.
+func (m *MockClient) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+}
+
+// IsClosed returns whether the client is closed.
+func (m *MockClient) IsClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// GetMessages returns the messages sent to the partition of the topic.
+func (m *MockClient) GetMessages(topic string, partition int) []*pulsar.ProducerMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.messages[topic][partition]
+}
+
+// GetAllMessages returns all the messages sent to the topic.
+func (m *MockClient) GetAllMessages(topic string) []*pulsar.ProducerMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var msgs []*pulsar.ProducerMessage
+	for _, partitionMsgs := range m.messages[topic] {
+		msgs = append(msgs, partitionMsgs...)
+	}
+	return msgs
+}
+
+func (m *MockClient) send(topic string, msg *pulsar.ProducerMessage,
+	router func(*pulsar.ProducerMessage, pulsar.TopicMetadata) int,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SendError != nil {
+		return m.SendError
+	}
+	num, ok := m.partitions[topic]
+	if !ok {
+		num = DefaultMockPartitionNum
+	}
+	partition := 0
+	if router != nil {
+		partition = router(msg, mockTopicMetadata(num))
+	}
+	if _, ok := m.messages[topic]; !ok {
+		m.messages[topic] = make(map[int][]*pulsar.ProducerMessage)
+	}
+	m.messages[topic][partition] = append(m.messages[topic][partition], msg)
+	return nil
+}
+
+type mockTopicMetadata uint32
+
+func (m mockTopicMetadata) NumPartitions() uint32 {
+	return uint32(m)
+}
+
+type mockProducer struct {
+	client  *MockClient
+	options pulsar.ProducerOptions
+}
+
+func (p *mockProducer) Topic() string {
+	return p.options.Topic
+}
+
+func (p *mockProducer) Name() string {
+	return "mock-producer-" + p.options.Topic
+}
+
+func (p *mockProducer) Send(
+	_ context.Context, msg *pulsar.ProducerMessage,
+) (pulsar.MessageID, error) {
+	return nil, p.client.send(p.options.Topic, msg, p.options.MessageRouter)
+}
+
+func (p *mockProducer) SendAsync(
+	_ context.Context, msg *pulsar.ProducerMessage,
+	callback func(pulsar.MessageID, *pulsar.ProducerMessage, error),
+) {
+	err := p.client.send(p.options.Topic, msg, p.options.MessageRouter)
+	callback(nil, msg, err)
+}
+
+func (p *mockProducer) LastSequenceID() int64 {
+	return -1
+}
+
+func (p *mockProducer) Flush() error {
+	return nil
+}
+
+func (p *mockProducer) Close() {}
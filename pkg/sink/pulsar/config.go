@@ -0,0 +1,211 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package pulsar
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/sink"
+)
+
+const (
+	// defaultMaxMessageBytes is identical to the default `maxMessageSize`
+	// of the pulsar broker, which is 5MB.
+	defaultMaxMessageBytes = 5 * 1024 * 1024
+	// defaultBatchingMaxMessages is the default max number of messages
+	// permitted in a batch of the pulsar producer.
+	defaultBatchingMaxMessages = 1000
+	// defaultBatchingMaxPublishDelay is the default time period within which
+	// the messages sent will be batched by the pulsar producer.
+	defaultBatchingMaxPublishDelay = 10 * time.Millisecond
+)
+
+// Config is the configuration of the pulsar sink.
+type Config struct {
+	// URL is the service URL of the pulsar cluster,
+	// e.g. pulsar://127.0.0.1:6650,127.0.0.2:6650.
+	URL string
+
+	// MaxMessageBytes will be used to initialize the encoder.
+	MaxMessageBytes int
+	// Compression is the compression type of the producer,
+	// it can be one of none, lz4, zlib and zstd.
+	Compression string
+
+	// AuthenticationToken is the token used to authenticate with the broker.
+	AuthenticationToken string
+	// TLSTrustCertsFilePath is the path of the trusted TLS certificate file.
+	TLSTrustCertsFilePath string
+	// TLSCertificatePath and TLSPrivateKeyPath are used for TLS authentication.
+	TLSCertificatePath string
+	TLSPrivateKeyPath  string
+
+	ConnectionTimeout time.Duration
+	OperationTimeout  time.Duration
+	SendTimeout       time.Duration
+
+	BatchingMaxMessages     uint
+	BatchingMaxPublishDelay time.Duration
+}
+
+// NewConfig returns a default pulsar config.
+func NewConfig() *Config {
+	return &Config{
+		MaxMessageBytes:         defaultMaxMessageBytes,
+		Compression:             "none",
+		ConnectionTimeout:       5 * time.Second,
+		OperationTimeout:        30 * time.Second,
+		SendTimeout:             30 * time.Second,
+		BatchingMaxMessages:     defaultBatchingMaxMessages,
+		BatchingMaxPublishDelay: defaultBatchingMaxPublishDelay,
+	}
+}
+
+// Apply the sinkURI to update Config.
+func (c *Config) Apply(sinkURI *url.URL) error {
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !sink.IsPulsarScheme(scheme) {
+		return cerror.ErrPulsarInvalidConfig.GenWithStack(
+			"the scheme (%s) of pulsar sink-uri is invalid", scheme)
+	}
+	if sinkURI.Host == "" {
+		return cerror.ErrPulsarInvalidConfig.GenWithStack(
+			"no broker address is specified in sink-uri")
+	}
+	c.URL = scheme + "://" + sinkURI.Host
+
+	params := sinkURI.Query()
+	s := params.Get("max-message-bytes")
+	if s != "" {
+		a, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		if a <= 0 {
+			return cerror.ErrPulsarInvalidConfig.GenWithStack(
+				"invalid max-message-bytes %d", a)
+		}
+		c.MaxMessageBytes = a
+	}
+
+	s = params.Get("compression")
+	if s != "" {
+		if _, err := compressionType(s); err != nil {
+			return err
+		}
+		c.Compression = s
+	}
+
+	c.AuthenticationToken = params.Get("authentication-token")
+
+	c.TLSTrustCertsFilePath = params.Get("ca")
+	c.TLSCertificatePath = params.Get("cert")
+	c.TLSPrivateKeyPath = params.Get("key")
+	if (c.TLSCertificatePath == "") != (c.TLSPrivateKeyPath == "") {
+		return cerror.ErrPulsarInvalidConfig.GenWithStack(
+			"cert and key files should be supplied together")
+	}
+	if c.AuthenticationToken != "" && c.TLSCertificatePath != "" {
+		return cerror.ErrPulsarInvalidConfig.GenWithStack(
+			"authentication-token and TLS authentication can not be used together")
+	}
+
+	durations := map[string]*time.Duration{
+		"connection-timeout":         &c.ConnectionTimeout,
+		"operation-timeout":          &c.OperationTimeout,
+		"send-timeout":               &c.SendTimeout,
+		"batching-max-publish-delay": &c.BatchingMaxPublishDelay,
+	}
+	for key, d := range durations {
+		s = params.Get(key)
+		if s == "" {
+			continue
+		}
+		a, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = a
+	}
+
+	s = params.Get("batching-max-messages")
+	if s != "" {
+		a, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return err
+		}
+		c.BatchingMaxMessages = uint(a)
+	}
+
+	return nil
+}
+
+// ClientOptions returns the options used to create a pulsar client.
+func (c *Config) ClientOptions() pulsar.ClientOptions {
+	options := pulsar.ClientOptions{
+		URL:                   c.URL,
+		ConnectionTimeout:     c.ConnectionTimeout,
+		OperationTimeout:      c.OperationTimeout,
+		TLSTrustCertsFilePath: c.TLSTrustCertsFilePath,
+	}
+	if c.AuthenticationToken != "" {
+		options.Authentication = pulsar.NewAuthenticationToken(c.AuthenticationToken)
+	}
+	if c.TLSCertificatePath != "" {
+		options.Authentication = pulsar.NewAuthenticationTLS(
+			c.TLSCertificatePath, c.TLSPrivateKeyPath)
+	}
+	return options
+}
+
+// ProducerOptions returns the options used to create a producer of the topic.
+// The messages are routed to the partition which is chosen by the event router,
+// see SetPartition for more details.
+func (c *Config) ProducerOptions(topic string) pulsar.ProducerOptions {
+	// The compression has already been validated in Apply.
+	compression, _ := compressionType(c.Compression)
+	return pulsar.ProducerOptions{
+		Topic:                   topic,
+		SendTimeout:             c.SendTimeout,
+		CompressionType:         compression,
+		BatchingMaxMessages:     c.BatchingMaxMessages,
+		BatchingMaxPublishDelay: c.BatchingMaxPublishDelay,
+		MessageRouter:           routeByPartitionProperty,
+	}
+}
+
+func compressionType(s string) (pulsar.CompressionType, error) {
+	switch strings.ToLower(s) {
+	case "none", "":
+		return pulsar.NoCompression, nil
+	case "lz4":
+		return pulsar.LZ4, nil
+	case "zlib":
+		return pulsar.ZLib, nil
+	case "zstd":
+		return pulsar.ZSTD, nil
+	default:
+		return pulsar.NoCompression, cerror.WrapError(cerror.ErrPulsarInvalidConfig,
+			errors.Errorf("unsupported compression %s", s))
+	}
+}
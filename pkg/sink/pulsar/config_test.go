@@ -0,0 +1,91 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+//go:build pulsar
+// +build pulsar
+
+package pulsar
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigApply(t *testing.T) {
+	t.Parallel()
+
+	uri := "pulsar+ssl://127.0.0.1:6651,127.0.0.2:6651/test?max-message-bytes=1024" +
+		"&compression=zstd&authentication-token=token&connection-timeout=3s" +
+		"&send-timeout=10s&batching-max-messages=100&batching-max-publish-delay=5ms"
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+	cfg := NewConfig()
+	require.Nil(t, cfg.Apply(sinkURI))
+	require.Equal(t, "pulsar+ssl://127.0.0.1:6651,127.0.0.2:6651", cfg.URL)
+	require.Equal(t, 1024, cfg.MaxMessageBytes)
+	require.Equal(t, "zstd", cfg.Compression)
+	require.Equal(t, "token", cfg.AuthenticationToken)
+	require.Equal(t, 3*time.Second, cfg.ConnectionTimeout)
+	require.Equal(t, 30*time.Second, cfg.OperationTimeout)
+	require.Equal(t, 10*time.Second, cfg.SendTimeout)
+	require.Equal(t, uint(100), cfg.BatchingMaxMessages)
+	require.Equal(t, 5*time.Millisecond, cfg.BatchingMaxPublishDelay)
+
+	options := cfg.ClientOptions()
+	require.Equal(t, cfg.URL, options.URL)
+	require.NotNil(t, options.Authentication)
+
+	producerOptions := cfg.ProducerOptions("test")
+	require.Equal(t, "test", producerOptions.Topic)
+	require.Equal(t, pulsar.ZSTD, producerOptions.CompressionType)
+	require.NotNil(t, producerOptions.MessageRouter)
+}
+
+func TestConfigApplyInvalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []string{
+		"kafka://127.0.0.1:9092/test",
+		"pulsar:///test",
+		"pulsar://127.0.0.1:6650/test?compression=snappy",
+		"pulsar://127.0.0.1:6650/test?max-message-bytes=0",
+		"pulsar://127.0.0.1:6650/test?cert=/tmp/cert",
+		"pulsar://127.0.0.1:6650/test?cert=/tmp/cert&key=/tmp/key&authentication-token=a",
+	}
+	for _, uri := range testCases {
+		sinkURI, err := url.Parse(uri)
+		require.Nil(t, err)
+		err = NewConfig().Apply(sinkURI)
+		require.ErrorIs(t, err, cerror.ErrPulsarInvalidConfig, uri)
+	}
+
+	sinkURI, err := url.Parse("pulsar://127.0.0.1:6650/test?send-timeout=abc")
+	require.Nil(t, err)
+	require.Error(t, NewConfig().Apply(sinkURI))
+}
+
+func TestRouteByPartitionProperty(t *testing.T) {
+	t.Parallel()
+
+	msg := &pulsar.ProducerMessage{}
+	require.Equal(t, 0, routeByPartitionProperty(msg, mockTopicMetadata(3)))
+	SetPartition(msg, 2)
+	require.Equal(t, 2, routeByPartitionProperty(msg, mockTopicMetadata(3)))
+	// The partition is out of the range of the topic.
+	require.Equal(t, 0, routeByPartitionProperty(msg, mockTopicMetadata(2)))
+}
@@ -39,6 +39,10 @@ const (
 	KafkaScheme = "kafka"
 	// KafkaSSLScheme indicates the scheme is kafka+ssl.
 	KafkaSSLScheme = "kafka+ssl"
+	// PulsarScheme indicates the scheme is pulsar.
+	PulsarScheme = "pulsar"
+	// PulsarSSLScheme indicates the scheme is pulsar+ssl.
+	PulsarSSLScheme = "pulsar+ssl"
 	// BlackHoleScheme indicates the scheme is blackhole.
 	BlackHoleScheme = "blackhole"
 	// MySQLScheme indicates the scheme is MySQL.
@@ -63,19 +67,47 @@ const (
 	AzureScheme = "azure"
 	// CloudStorageNoopScheme indicates the scheme is noop.
 	CloudStorageNoopScheme = "noop"
+	// HTTPScheme indicates the scheme is http.
+	HTTPScheme = "http"
+	// HTTPSScheme indicates the scheme is https.
+	HTTPSScheme = "https"
+	// ElasticsearchScheme indicates the scheme is elasticsearch.
+	ElasticsearchScheme = "elasticsearch"
+	// ElasticsearchSSLScheme indicates the scheme is elasticsearch+ssl.
+	ElasticsearchSSLScheme = "elasticsearch+ssl"
 )
 
 // IsMQScheme returns true if the scheme belong to mq scheme.
 func IsMQScheme(scheme string) bool {
+	return IsKafkaScheme(scheme) || IsPulsarScheme(scheme)
+}
+
+// IsKafkaScheme returns true if the scheme belong to kafka scheme.
+func IsKafkaScheme(scheme string) bool {
 	return scheme == KafkaScheme || scheme == KafkaSSLScheme
 }
 
+// IsPulsarScheme returns true if the scheme belong to pulsar scheme.
+func IsPulsarScheme(scheme string) bool {
+	return scheme == PulsarScheme || scheme == PulsarSSLScheme
+}
+
 // IsMySQLCompatibleScheme returns true if the scheme is compatible with MySQL.
 func IsMySQLCompatibleScheme(scheme string) bool {
 	return scheme == MySQLScheme || scheme == MySQLSSLScheme ||
 		scheme == TiDBScheme || scheme == TiDBSSLScheme
 }
 
+// IsHTTPScheme returns true if the scheme belong to http scheme.
+func IsHTTPScheme(scheme string) bool {
+	return scheme == HTTPScheme || scheme == HTTPSScheme
+}
+
+// IsElasticsearchScheme returns true if the scheme belong to elasticsearch scheme.
+func IsElasticsearchScheme(scheme string) bool {
+	return scheme == ElasticsearchScheme || scheme == ElasticsearchSSLScheme
+}
+
 // IsStorageScheme returns true if the scheme belong to storage scheme.
 func IsStorageScheme(scheme string) bool {
 	return scheme == FileScheme || scheme == S3Scheme || scheme == GCSScheme ||
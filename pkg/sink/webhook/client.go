@@ -0,0 +1,196 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader is the header carrying the idempotency key of a
+	// request. The key is derived from the commit ts of the messages and the
+	// request body, so the retries of a request always carry the same key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// the max length of the response body kept in the errors.
+	maxErrorBodyLength = 1024
+)
+
+// openMessage is the element of the request body of the open protocol.
+// The key and value are binary, so they are encoded in base64.
+type openMessage struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Client posts the encoded messages to the webhook.
+type Client struct {
+	changefeedID model.ChangeFeedID
+	cfg          *Config
+	client       *httputil.Client
+}
+
+// NewClient creates a webhook client.
+func NewClient(cfg *Config, changefeedID model.ChangeFeedID) (*Client, error) {
+	client, err := httputil.NewClient(nil)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+	client.SetTimeout(cfg.RequestTimeout)
+	return &Client{
+		changefeedID: changefeedID,
+		cfg:          cfg,
+		client:       client,
+	}, nil
+}
+
+// Send posts the messages to the webhook in a request. The request is retried
+// with backoff on network errors and retryable status codes.
+func (c *Client) Send(ctx context.Context, msgs []*common.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	body, err := c.encodeBody(msgs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	key := c.idempotencyKey(msgs, body)
+
+	return retry.Do(ctx, func() error {
+		return c.post(ctx, body, key)
+	}, retry.WithBackoffBaseDelay(c.cfg.BackoffBaseDelay.Milliseconds()),
+		retry.WithBackoffMaxDelay(c.cfg.BackoffMaxDelay.Milliseconds()),
+		retry.WithMaxTries(c.cfg.MaxRetries),
+		retry.WithIsRetryableErr(isRetryableError))
+}
+
+// Close closes the client.
+func (c *Client) Close() {
+	c.client.CloseIdleConnections()
+}
+
+func (c *Client) post(ctx context.Context, body []byte, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return cerror.WrapError(cerror.ErrWebhookRequestRejected, err, err.Error())
+	}
+	for name, values := range c.cfg.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		log.Warn("webhook request failed",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.Error(err))
+		return cerror.WrapError(cerror.ErrWebhookRequestFailed, err, err.Error())
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg := fmt.Sprintf("status code %d, body %s", resp.StatusCode, string(respBody))
+	log.Warn("webhook request is not accepted",
+		zap.String("namespace", c.changefeedID.Namespace),
+		zap.String("changefeed", c.changefeedID.ID),
+		zap.Int("statusCode", resp.StatusCode),
+		zap.String("idempotencyKey", key))
+	if resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests {
+		return cerror.ErrWebhookRequestFailed.GenWithStackByArgs(msg)
+	}
+	return cerror.ErrWebhookRequestRejected.GenWithStackByArgs(msg)
+}
+
+// encodeBody encodes the messages into a JSON array, whose elements are the
+// values of the canal-json messages, or the key-value pairs of the open
+// protocol messages.
+func (c *Client) encodeBody(msgs []*common.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, msg := range msgs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if c.cfg.Protocol == config.ProtocolOpen {
+			data, err := json.Marshal(&openMessage{
+				Key: msg.Key, Value: msg.Value,
+			})
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrWebhookRequestRejected, err, err.Error())
+			}
+			buf.Write(data)
+			continue
+		}
+		buf.Write(toJSON(msg.Value))
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// idempotencyKey returns the idempotency key of the messages.
+func (c *Client) idempotencyKey(msgs []*common.Message, body []byte) string {
+	minTs, maxTs := msgs[0].Ts, msgs[0].Ts
+	for _, msg := range msgs[1:] {
+		if msg.Ts < minTs {
+			minTs = msg.Ts
+		}
+		if msg.Ts > maxTs {
+			maxTs = msg.Ts
+		}
+	}
+	return fmt.Sprintf("%s.%s-%d-%d-%08x", c.changefeedID.Namespace, c.changefeedID.ID,
+		minTs, maxTs, crc32.ChecksumIEEE(body))
+}
+
+// toJSON returns the data if it is valid JSON, otherwise the data is
+// encoded as a JSON string.
+func toJSON(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return json.RawMessage("null")
+	}
+	if json.Valid(data) {
+		return data
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
+
+func isRetryableError(err error) bool {
+	return !cerror.ErrWebhookRequestRejected.Equal(errors.Cause(err)) &&
+		errors.Cause(err) != context.Canceled
+}
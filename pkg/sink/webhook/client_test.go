@@ -0,0 +1,149 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/codec/common"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(url string, protocol config.Protocol) *Config {
+	cfg := NewConfig()
+	cfg.URL = url
+	cfg.Protocol = protocol
+	cfg.MaxRetries = 3
+	cfg.BackoffBaseDelay = time.Millisecond
+	cfg.BackoffMaxDelay = 10 * time.Millisecond
+	cfg.Headers.Set("X-Source", "tidb")
+	return cfg
+}
+
+func TestClientSend(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		bodies [][]byte
+		keys   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "tidb", r.Header.Get("X-Source"))
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, body)
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		// the first request fails, and it is retried.
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	client, err := NewClient(newTestConfig(server.URL, config.ProtocolCanalJSON), changefeedID)
+	require.NoError(t, err)
+	defer client.Close()
+
+	msgs := []*common.Message{
+		{Value: []byte(`{"id":1}`), Ts: 10},
+		{Value: []byte(`{"id":2}`), Ts: 20},
+	}
+	require.NoError(t, client.Send(context.Background(), msgs))
+	require.Len(t, bodies, 2)
+	require.Equal(t, bodies[0], bodies[1])
+	require.JSONEq(t, `[{"id":1},{"id":2}]`, string(bodies[1]))
+	// the retries carry the same idempotency key.
+	require.Equal(t, keys[0], keys[1])
+	require.Regexp(t, "^default.test-10-20-[0-9a-f]{8}$", keys[0])
+}
+
+func TestClientSendOpenProtocol(t *testing.T) {
+	t.Parallel()
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(newTestConfig(server.URL, config.ProtocolOpen),
+		model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send(context.Background(), []*common.Message{
+		{Key: []byte("key"), Value: []byte("value"), Ts: 10},
+	}))
+	var msgs []openMessage
+	require.NoError(t, json.Unmarshal(body, &msgs))
+	require.Equal(t, []openMessage{{Key: []byte("key"), Value: []byte("value")}}, msgs)
+}
+
+func TestClientSendFailed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests int
+		status   = http.StatusBadRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(newTestConfig(server.URL, config.ProtocolCanalJSON),
+		model.DefaultChangeFeedID("test"))
+	require.NoError(t, err)
+	defer client.Close()
+	msgs := []*common.Message{{Value: []byte(`{}`), Ts: 10}}
+
+	// the rejected requests are not retried.
+	err = client.Send(context.Background(), msgs)
+	require.True(t, cerror.ErrWebhookRequestRejected.Equal(err))
+	require.Equal(t, 1, requests)
+
+	// the failed requests are retried until the max retries is reached.
+	mu.Lock()
+	requests = 0
+	status = http.StatusTooManyRequests
+	mu.Unlock()
+	err = client.Send(context.Background(), msgs)
+	require.Regexp(t, ".*webhook request failed.*", err)
+	require.Equal(t, 3, requests)
+}
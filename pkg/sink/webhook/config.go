@@ -0,0 +1,198 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	psink "github.com/pingcap/tiflow/pkg/sink"
+)
+
+const (
+	// defaultBatchSize is the default value of batch-size.
+	defaultBatchSize = 64
+	// the upper limit of batch-size.
+	maxBatchSize = 10000
+	// defaultFlushInterval is the default value of flush-interval.
+	defaultFlushInterval = 100 * time.Millisecond
+	// defaultMaxRetries is the default value of max-retries.
+	defaultMaxRetries = 8
+	// defaultBackoffBaseDelay is the default value of backoff-base-delay.
+	defaultBackoffBaseDelay = 500 * time.Millisecond
+	// defaultBackoffMaxDelay is the default value of backoff-max-delay.
+	defaultBackoffMaxDelay = 30 * time.Second
+	// defaultRequestTimeout is the default value of request-timeout.
+	defaultRequestTimeout = 10 * time.Second
+)
+
+const (
+	batchSizeKey        = "batch-size"
+	flushIntervalKey    = "flush-interval"
+	maxRetriesKey       = "max-retries"
+	backoffBaseDelayKey = "backoff-base-delay"
+	backoffMaxDelayKey  = "backoff-max-delay"
+	requestTimeoutKey   = "request-timeout"
+	headerKey           = "header"
+	maxMessageBytesKey  = "max-message-bytes"
+)
+
+// sinkParameters are the parameters of the sink URI consumed by TiCDC, which
+// are removed from the URL of the webhook.
+var sinkParameters = []string{
+	batchSizeKey, flushIntervalKey, maxRetriesKey, backoffBaseDelayKey,
+	backoffMaxDelayKey, requestTimeoutKey, headerKey, maxMessageBytesKey,
+	config.ProtocolKey, "max-batch-size", "enable-tidb-extension", "transaction-atomicity",
+}
+
+// Config is the configuration for the webhook sink.
+type Config struct {
+	// URL is the URL which the events are posted to.
+	URL      string
+	Protocol config.Protocol
+	// BatchSize is the max number of rows in a request.
+	BatchSize     int
+	FlushInterval time.Duration
+	// MaxRetries is the max number of tries of a request.
+	MaxRetries       uint64
+	BackoffBaseDelay time.Duration
+	BackoffMaxDelay  time.Duration
+	RequestTimeout   time.Duration
+	// Headers are the extra headers of the requests.
+	Headers         http.Header
+	MaxMessageBytes int
+}
+
+// NewConfig returns the default webhook sink config.
+func NewConfig() *Config {
+	return &Config{
+		BatchSize:        defaultBatchSize,
+		FlushInterval:    defaultFlushInterval,
+		MaxRetries:       defaultMaxRetries,
+		BackoffBaseDelay: defaultBackoffBaseDelay,
+		BackoffMaxDelay:  defaultBackoffMaxDelay,
+		RequestTimeout:   defaultRequestTimeout,
+		Headers:          make(http.Header),
+		MaxMessageBytes:  config.DefaultMaxMessageBytes,
+	}
+}
+
+// Apply applies the sink URI parameters to the config.
+func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) error {
+	if sinkURI == nil {
+		return cerror.ErrWebhookInvalidConfig.GenWithStack(
+			"failed to open webhook sink, empty SinkURI")
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !psink.IsHTTPScheme(scheme) {
+		return cerror.ErrWebhookInvalidConfig.GenWithStack(
+			"can't create webhook sink with unsupported scheme: %s", scheme)
+	}
+
+	protocol, err := config.ParseSinkProtocolFromString(replicaConfig.Sink.Protocol)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+	if protocol != config.ProtocolCanalJSON && protocol != config.ProtocolOpen {
+		return cerror.ErrWebhookInvalidConfig.GenWithStack(
+			"webhook sink only supports canal-json and open-protocol, but got %s",
+			replicaConfig.Sink.Protocol)
+	}
+	c.Protocol = protocol
+
+	query := sinkURI.Query()
+	if err := getInt(query, batchSizeKey, &c.BatchSize); err != nil {
+		return err
+	}
+	if c.BatchSize > maxBatchSize {
+		c.BatchSize = maxBatchSize
+	}
+	if err := getInt(query, maxMessageBytesKey, &c.MaxMessageBytes); err != nil {
+		return err
+	}
+	var maxRetries int
+	if err := getInt(query, maxRetriesKey, &maxRetries); err != nil {
+		return err
+	}
+	if maxRetries > 0 {
+		c.MaxRetries = uint64(maxRetries)
+	}
+	for key, d := range map[string]*time.Duration{
+		flushIntervalKey:    &c.FlushInterval,
+		backoffBaseDelayKey: &c.BackoffBaseDelay,
+		backoffMaxDelayKey:  &c.BackoffMaxDelay,
+		requestTimeoutKey:   &c.RequestTimeout,
+	} {
+		if err := getDuration(query, key, d); err != nil {
+			return err
+		}
+	}
+	for _, header := range query[headerKey] {
+		name, value, ok := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return cerror.ErrWebhookInvalidConfig.GenWithStack(
+				"invalid header %s, it should be in the format of name:value", header)
+		}
+		c.Headers.Add(name, strings.TrimSpace(value))
+	}
+
+	target := *sinkURI
+	for _, key := range sinkParameters {
+		query.Del(key)
+	}
+	target.RawQuery = query.Encode()
+	c.URL = target.String()
+	return nil
+}
+
+func getInt(values url.Values, key string, target *int) error {
+	s := values.Get(key)
+	if len(s) == 0 {
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+	if v <= 0 {
+		return cerror.WrapError(cerror.ErrWebhookInvalidConfig,
+			fmt.Errorf("invalid %s %d, it must be greater than 0", key, v))
+	}
+	*target = v
+	return nil
+}
+
+func getDuration(values url.Values, key string, target *time.Duration) error {
+	s := values.Get(key)
+	if len(s) == 0 {
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrWebhookInvalidConfig, err)
+	}
+	if d <= 0 {
+		return cerror.WrapError(cerror.ErrWebhookInvalidConfig,
+			fmt.Errorf("invalid %s %s, it must be greater than 0", key, s))
+	}
+	*target = d
+	return nil
+}
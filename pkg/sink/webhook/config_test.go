@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigApply(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.Protocol = config.ProtocolCanalJSON.String()
+
+	uri := "https://127.0.0.1:8080/events?token=abc&protocol=canal-json&batch-size=10" +
+		"&flush-interval=1s&max-retries=3&backoff-base-delay=10ms&backoff-max-delay=1s" +
+		"&request-timeout=5s&header=X-Source:%20tidb&header=Authorization:Bearer%20x"
+	sinkURI, err := url.Parse(uri)
+	require.NoError(t, err)
+	cfg := NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI, replicaConfig))
+	require.Equal(t, "https://127.0.0.1:8080/events?token=abc", cfg.URL)
+	require.Equal(t, config.ProtocolCanalJSON, cfg.Protocol)
+	require.Equal(t, 10, cfg.BatchSize)
+	require.Equal(t, time.Second, cfg.FlushInterval)
+	require.Equal(t, uint64(3), cfg.MaxRetries)
+	require.Equal(t, 10*time.Millisecond, cfg.BackoffBaseDelay)
+	require.Equal(t, time.Second, cfg.BackoffMaxDelay)
+	require.Equal(t, 5*time.Second, cfg.RequestTimeout)
+	require.Equal(t, "tidb", cfg.Headers.Get("X-Source"))
+	require.Equal(t, "Bearer x", cfg.Headers.Get("Authorization"))
+
+	// the default values are used if the parameters are not set.
+	sinkURI, err = url.Parse("http://127.0.0.1:8080")
+	require.NoError(t, err)
+	cfg = NewConfig()
+	require.NoError(t, cfg.Apply(sinkURI, replicaConfig))
+	require.Equal(t, "http://127.0.0.1:8080", cfg.URL)
+	require.Equal(t, defaultBatchSize, cfg.BatchSize)
+	require.Equal(t, defaultFlushInterval, cfg.FlushInterval)
+	require.Equal(t, uint64(defaultMaxRetries), cfg.MaxRetries)
+}
+
+func TestConfigApplyInvalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		uri      string
+		protocol string
+		err      string
+	}{
+		{uri: "kafka://127.0.0.1:9092", protocol: "canal-json", err: ".*unsupported scheme.*"},
+		{uri: "http://127.0.0.1", protocol: "avro", err: ".*only supports canal-json and open-protocol.*"},
+		{uri: "http://127.0.0.1?batch-size=0", protocol: "canal-json", err: ".*invalid batch-size.*"},
+		{uri: "http://127.0.0.1?batch-size=a", protocol: "canal-json", err: ".*invalid syntax.*"},
+		{uri: "http://127.0.0.1?flush-interval=1", protocol: "canal-json", err: ".*missing unit.*"},
+		{uri: "http://127.0.0.1?header=abc", protocol: "canal-json", err: ".*invalid header abc.*"},
+	}
+	for _, tc := range testCases {
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Protocol = tc.protocol
+		sinkURI, err := url.Parse(tc.uri)
+		require.NoError(t, err)
+		err = NewConfig().Apply(sinkURI, replicaConfig)
+		require.Regexp(t, tc.err, err)
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/rowcodec"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/dm/pkg/utils"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Transformer transforms the row changed events by the transform rules
+// before they are sent to the sinks. It is safe for concurrent use.
+type Transformer interface {
+	// TransformDMLEvent transforms the columns of the DML event in place.
+	TransformDMLEvent(
+		row *model.RowChangedEvent, rawRow model.RowChangedDatums, tableInfo *model.TableInfo,
+	) error
+	// Verify should only be called by create changefeed OpenAPI.
+	// Its purpose is to verify the expressions of the computed columns.
+	Verify(tableInfos []*model.TableInfo) error
+}
+
+// transformer implements Transformer.
+type transformer struct {
+	rules []*transformRule
+}
+
+// NewTransformer creates a transformer.
+func NewTransformer(cfg *config.ReplicaConfig, tz string) (Transformer, error) {
+	res := &transformer{}
+	if cfg.Transform == nil {
+		return res, nil
+	}
+	sessCtx := utils.NewSessionCtx(map[string]string{
+		"time_zone": tz,
+	})
+	for _, ruleCfg := range cfg.Transform.Rules {
+		rule, err := newTransformRule(sessCtx, ruleCfg, cfg.CaseSensitive)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		res.rules = append(res.rules, rule)
+	}
+	return res, nil
+}
+
+// TransformDMLEvent applies all the rules matching the table to the event.
+func (t *transformer) TransformDMLEvent(
+	row *model.RowChangedEvent,
+	rawRow model.RowChangedDatums,
+	ti *model.TableInfo,
+) error {
+	// for defense purpose, normally the row and ti should not be nil.
+	if len(t.rules) == 0 || row == nil || ti == nil {
+		return nil
+	}
+	for _, rule := range t.rules {
+		if !rule.tableMatcher.MatchTable(row.Table.Schema, row.Table.Table) {
+			continue
+		}
+		if err := rule.apply(row, rawRow, ti); err != nil {
+			if cerror.IsChangefeedUnRetryableError(err) {
+				return err
+			}
+			return cerror.WrapError(cerror.ErrFailedToTransformDML, err, row)
+		}
+	}
+	return nil
+}
+
+// Verify checks whether the expressions of the computed columns are valid
+// for the matched tables.
+func (t *transformer) Verify(tableInfos []*model.TableInfo) error {
+	for _, rule := range t.rules {
+		for _, ti := range tableInfos {
+			if !rule.tableMatcher.MatchTable(ti.TableName.Schema, ti.TableName.Table) {
+				continue
+			}
+			if _, err := rule.getComputedExprs(ti); err != nil {
+				log.Error("failed to verify transform rule", zap.Error(err))
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+type transformRule struct {
+	mu sync.Mutex
+	// Cache tableInfos to check if the table was changed.
+	tables map[string]*model.TableInfo
+	// computedExprs caches the expressions of the computed columns.
+	computedExprs map[string][]expression.Expression // tableName -> exprs
+
+	tableMatcher tfilter.Filter
+	config       *config.TransformRule
+	// the column names are matched case-insensitively.
+	renames   map[string]string
+	masked    map[string]struct{}
+	hashed    map[string]struct{}
+	constants []string
+
+	sessCtx sessionctx.Context
+}
+
+func newTransformRule(
+	sessCtx sessionctx.Context,
+	cfg *config.TransformRule,
+	caseSensitive bool,
+) (*transformRule, error) {
+	tf, err := tfilter.Parse(cfg.Matcher)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, cfg.Matcher)
+	}
+	if !caseSensitive {
+		tf = tfilter.CaseInsensitive(tf)
+	}
+
+	rule := &transformRule{
+		tables:        make(map[string]*model.TableInfo),
+		computedExprs: make(map[string][]expression.Expression),
+		tableMatcher:  tf,
+		config:        cfg,
+		renames:       make(map[string]string, len(cfg.RenameColumns)),
+		masked:        make(map[string]struct{}, len(cfg.MaskColumns)),
+		hashed:        make(map[string]struct{}, len(cfg.HashColumns)),
+		sessCtx:       sessCtx,
+	}
+	for from, to := range cfg.RenameColumns {
+		rule.renames[strings.ToLower(from)] = to
+	}
+	for _, name := range cfg.MaskColumns {
+		rule.masked[strings.ToLower(name)] = struct{}{}
+	}
+	for _, name := range cfg.HashColumns {
+		rule.hashed[strings.ToLower(name)] = struct{}{}
+	}
+	// sort the constant columns to keep the order of the appended columns stable.
+	for name := range cfg.ConstantColumns {
+		rule.constants = append(rule.constants, name)
+	}
+	sort.Strings(rule.constants)
+	return rule, nil
+}
+
+// getComputedExprs returns the expressions of the computed columns of the table.
+// This function will lazy calculate expressions if not initialized.
+func (r *transformRule) getComputedExprs(ti *model.TableInfo) ([]expression.Expression, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tableName := ti.TableName.String()
+	if oldTi, ok := r.tables[tableName]; ok {
+		// If one table's tableInfo was updated, we need to reset
+		// the expressions and update the tableInfo in the cache.
+		if ti.Version != oldTi.Version {
+			r.tables[tableName] = ti.Clone()
+			delete(r.computedExprs, tableName)
+		}
+	} else {
+		r.tables[tableName] = ti.Clone()
+	}
+	if exprs, ok := r.computedExprs[tableName]; ok {
+		return exprs, nil
+	}
+
+	exprs := make([]expression.Expression, 0, len(r.config.ComputedColumns))
+	for _, col := range r.config.ComputedColumns {
+		e, err := expression.ParseSimpleExprWithTableInfo(r.sessCtx, col.Expression, ti.TableInfo)
+		if err != nil {
+			log.Error("failed to parse the expression of computed column",
+				zap.String("table", tableName), zap.String("column", col.Name),
+				zap.String("expression", col.Expression), zap.Error(err))
+			if core.ErrUnknownColumn.Equal(err) {
+				return nil, cerror.ErrTransformRuleInvalid.GenWithStackByArgs(r.config.Matcher,
+					"unknown column in the expression "+col.Expression+" of table "+tableName)
+			}
+			return nil, cerror.ErrTransformRuleInvalid.GenWithStackByArgs(r.config.Matcher,
+				"invalid expression "+col.Expression+": "+err.Error())
+		}
+		exprs = append(exprs, e)
+	}
+	r.computedExprs[tableName] = exprs
+	return exprs, nil
+}
+
+func (r *transformRule) apply(
+	row *model.RowChangedEvent,
+	rawRow model.RowChangedDatums,
+	ti *model.TableInfo,
+) error {
+	exprs, err := r.getComputedExprs(ti)
+	if err != nil {
+		return err
+	}
+	// The ColInfos are shared by all the events of the table, so they are
+	// copied before being modified.
+	colInfos := make([]rowcodec.ColInfo, len(row.ColInfos), len(row.ColInfos)+len(exprs)+len(r.constants))
+	copy(colInfos, row.ColInfos)
+
+	// 1. computed columns.
+	for i, expr := range exprs {
+		ft := expr.GetType()
+		name := r.config.ComputedColumns[i].Name
+		if row.Columns != nil {
+			col, err := evalComputedColumn(name, expr, rawRow.RowDatums)
+			if err != nil {
+				return err
+			}
+			row.Columns = append(row.Columns, col)
+		}
+		if row.PreColumns != nil {
+			col, err := evalComputedColumn(name, expr, rawRow.PreRowDatums)
+			if err != nil {
+				return err
+			}
+			row.PreColumns = append(row.PreColumns, col)
+		}
+		colInfos = append(colInfos, rowcodec.ColInfo{Ft: ft})
+	}
+
+	// 2. masked and hashed columns, 3. renamed columns.
+	for _, cols := range [][]*model.Column{row.Columns, row.PreColumns} {
+		for i, col := range cols {
+			if col == nil {
+				continue
+			}
+			name := strings.ToLower(col.Name)
+			_, mask := r.masked[name]
+			_, hash := r.hashed[name]
+			if mask || hash {
+				if col.Value != nil {
+					value := model.ColumnValueString(col.Value)
+					if mask {
+						value = maskValue(value)
+					} else {
+						value = hashValue(value)
+					}
+					col.Value = []byte(value)
+				}
+				col.Type = mysql.TypeVarchar
+				col.Charset = mysql.DefaultCharset
+				col.Flag.UnsetIsBinary()
+				col.Flag.UnsetIsUnsigned()
+				if i < len(colInfos) {
+					colInfos[i].Ft = newStringFieldType()
+				}
+			}
+			if newName, ok := r.renames[name]; ok {
+				col.Name = newName
+			}
+		}
+	}
+
+	// 4. constant columns.
+	for _, name := range r.constants {
+		value := r.config.ConstantColumns[name]
+		if row.Columns != nil {
+			row.Columns = append(row.Columns, newConstantColumn(name, value))
+		}
+		if row.PreColumns != nil {
+			row.PreColumns = append(row.PreColumns, newConstantColumn(name, value))
+		}
+		colInfos = append(colInfos, rowcodec.ColInfo{Ft: newStringFieldType()})
+	}
+	row.ColInfos = colInfos
+	return nil
+}
+
+func evalComputedColumn(
+	name string, expr expression.Expression, datums []types.Datum,
+) (*model.Column, error) {
+	ft := expr.GetType()
+	col := &model.Column{
+		Name:    name,
+		Type:    ft.GetType(),
+		Charset: ft.GetCharset(),
+	}
+	if !mysql.HasNotNullFlag(ft.GetFlag()) {
+		col.Flag.SetIsNullable()
+	}
+	if mysql.HasUnsignedFlag(ft.GetFlag()) {
+		col.Flag.SetIsUnsigned()
+	}
+	if ft.GetCharset() == charset.CharsetBin {
+		col.Flag.SetIsBinary()
+	}
+	if len(datums) == 0 {
+		return col, nil
+	}
+	d, err := expr.Eval(chunk.MutRowFromDatums(datums).ToRow())
+	if err != nil {
+		log.Error("failed to eval the expression of computed column",
+			zap.String("column", name), zap.Error(err))
+		return nil, errors.Trace(err)
+	}
+	col.Value, err = datumToColumnValue(d)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return col, nil
+}
+
+// datumToColumnValue converts the datum to the column value in the same
+// way as the mounter does.
+func datumToColumnValue(d types.Datum) (interface{}, error) {
+	switch d.Kind() {
+	case types.KindNull:
+		return nil, nil
+	case types.KindMysqlTime:
+		return d.GetMysqlTime().String(), nil
+	case types.KindMysqlDuration:
+		return d.GetMysqlDuration().String(), nil
+	case types.KindMysqlJSON:
+		return d.GetMysqlJSON().String(), nil
+	case types.KindMysqlDecimal:
+		return d.GetMysqlDecimal().String(), nil
+	case types.KindMysqlEnum:
+		return d.GetMysqlEnum().Value, nil
+	case types.KindMysqlSet:
+		return d.GetMysqlSet().Value, nil
+	case types.KindBinaryLiteral, types.KindMysqlBit:
+		return d.GetBinaryLiteral().ToInt(nil)
+	case types.KindString, types.KindBytes:
+		return d.GetBytes(), nil
+	case types.KindFloat32:
+		return float64(d.GetFloat32()), nil
+	default:
+		return d.GetValue(), nil
+	}
+}
+
+func newConstantColumn(name, value string) *model.Column {
+	return &model.Column{
+		Name:    name,
+		Type:    mysql.TypeVarchar,
+		Charset: mysql.DefaultCharset,
+		Value:   []byte(value),
+	}
+}
+
+func newStringFieldType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeVarchar)
+	ft.SetCharset(mysql.DefaultCharset)
+	ft.SetCollate(mysql.DefaultCollationName)
+	return ft
+}
+
+// maskValue masks the local part of an email address except its first
+// character, and masks the other values except their last 4 characters.
+func maskValue(value string) string {
+	if at := strings.LastIndexByte(value, '@'); at > 0 {
+		local := []rune(value[:at])
+		return string(local[0]) + strings.Repeat("*", len(local)-1) + value[at:]
+	}
+	const keep = 4
+	runes := []rune(value)
+	if len(runes) <= keep {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-keep) + string(runes[len(runes)-keep:])
+}
+
+// hashValue returns the SHA-256 hex digest of the value.
+func hashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyTables checks whether the transform rules of the replica config
+// are valid for the tables.
+func VerifyTables(cfg *config.ReplicaConfig, tableInfos []*model.TableInfo) error {
+	if cfg.Transform == nil || len(cfg.Transform.Rules) == 0 {
+		return nil
+	}
+	t, err := NewTransformer(cfg, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return t.Verify(tableInfos)
+}
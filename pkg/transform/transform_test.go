@@ -0,0 +1,107 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskAndHashValue(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "a***@pingcap.com", maskValue("abcd@pingcap.com"))
+	require.Equal(t, "*******5678", maskValue("12345-45678"))
+	require.Equal(t, "****", maskValue("1234"))
+	require.Equal(t, "", maskValue(""))
+	require.Equal(t,
+		"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", hashValue("test"))
+}
+
+func TestTransformDMLEvent(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Transform = &config.TransformConfig{
+		Rules: []*config.TransformRule{
+			{
+				Matcher:         []string{"test.user"},
+				RenameColumns:   map[string]string{"Name": "nickname"},
+				MaskColumns:     []string{"email"},
+				HashColumns:     []string{"phone"},
+				ConstantColumns: map[string]string{"source": "tidb", "region": "us"},
+			},
+			{
+				Matcher:       []string{"test.*"},
+				RenameColumns: map[string]string{"nickname": "alias"},
+			},
+		},
+	}
+	tr, err := NewTransformer(cfg, "UTC")
+	require.NoError(t, err)
+
+	newCols := func() []*model.Column {
+		return []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Value: int64(1)},
+			{Name: "name", Type: mysql.TypeVarchar, Value: []byte("tom")},
+			{Name: "email", Type: mysql.TypeVarchar, Value: []byte("tom@pingcap.com")},
+			{Name: "phone", Type: mysql.TypeLonglong, Value: int64(12345678)},
+		}
+	}
+	ti := &model.TableInfo{
+		TableInfo: &timodel.TableInfo{Name: timodel.NewCIStr("user")},
+		TableName: model.TableName{Schema: "test", Table: "user"},
+	}
+	row := &model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "test", Table: "user"},
+		Columns:    newCols(),
+		PreColumns: newCols(),
+	}
+	require.NoError(t, tr.TransformDMLEvent(row, model.RowChangedDatums{}, ti))
+
+	for _, cols := range [][]*model.Column{row.Columns, row.PreColumns} {
+		require.Len(t, cols, 6)
+		require.Equal(t, "alias", cols[1].Name)
+		require.Equal(t, []byte("t**@pingcap.com"), cols[2].Value)
+		require.Equal(t, []byte(hashValue("12345678")), cols[3].Value)
+		require.Equal(t, mysql.TypeVarchar, cols[3].Type)
+		require.Equal(t, "region", cols[4].Name)
+		require.Equal(t, []byte("us"), cols[4].Value)
+		require.Equal(t, "source", cols[5].Name)
+		require.Equal(t, []byte("tidb"), cols[5].Value)
+	}
+	require.Len(t, row.ColInfos, 2)
+
+	// the delete events only have the old values.
+	row = &model.RowChangedEvent{
+		Table:      &model.TableName{Schema: "test", Table: "user"},
+		PreColumns: newCols(),
+	}
+	require.NoError(t, tr.TransformDMLEvent(row, model.RowChangedDatums{}, ti))
+	require.Nil(t, row.Columns)
+	require.Len(t, row.PreColumns, 6)
+
+	// the unmatched tables are not transformed.
+	row = &model.RowChangedEvent{
+		Table:   &model.TableName{Schema: "other", Table: "user"},
+		Columns: newCols(),
+	}
+	require.NoError(t, tr.TransformDMLEvent(row, model.RowChangedDatums{}, ti))
+	require.Equal(t, newCols(), row.Columns)
+}